@@ -40,10 +40,10 @@ type Application struct {
 }
 
 // Init initializes Application ...
-func (a *Application) Init(conDB *consensusdb.Database, memDB *badger.DB, dph *deposit.Handler, storageInterface dynamics.StorageGetter) error {
+func (a *Application) Init(conDB *consensusdb.Database, memDB *badger.DB, dph *deposit.Handler, storageInterface dynamics.StorageGetter, forkEpoch uint32) error {
 	a.logger = logging.GetLogger(constants.LoggerApp)
-	storage := wrapper.NewStorage(storageInterface)
-	uHdlr := utxohandler.NewUTXOHandler(conDB.DB())
+	storage := wrapper.NewStorage(storageInterface, forkEpoch)
+	uHdlr := utxohandler.NewUTXOHandler(conDB.DB(), storage)
 	pHdlr := pendingtx.NewPendingTxHandler(memDB)
 	pHdlr.UTXOHandler = uHdlr
 	pHdlr.DepositHandler = dph
@@ -209,6 +209,12 @@ func (a *Application) PendingTxAdd(txn *badger.Txn, chainID, height uint32, txs
 	return a.txHandler.PendingTxAdd(txn, chainID, height, tx)
 }
 
+// ValidateTx runs the checks of PendingTxAdd for a transaction against the
+// current state without adding it to the txPool.
+func (a *Application) ValidateTx(txn *badger.Txn, chainID, height uint32, tx *objs.Tx) (*TxValidation, error) {
+	return a.txHandler.ValidateTx(txn, chainID, height, tx)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 //Data Getters/Setters/RPC methods//////////////////////////////////////////////
@@ -267,6 +273,18 @@ func (a *Application) MinedTxGet(txn *badger.Txn, txHash [][]byte) ([]interfaces
 	return a.convertTxToIface(r), m, nil
 }
 
+// MinedTxGetByMemo returns at most maxCount hashes of the mined transactions
+// carrying the memo with memoHash, starting after startTxHash, along with
+// the txHash to continue from if more transactions remain.
+func (a *Application) MinedTxGetByMemo(txn *badger.Txn, memoHash []byte, maxCount int, startTxHash []byte) ([][]byte, []byte, error) {
+	txHashes, next, err := a.txHandler.MinedTxGetByMemo(txn, memoHash, maxCount, startTxHash)
+	if err != nil {
+		utils.DebugTrace(a.logger, err)
+		return nil, nil, err
+	}
+	return txHashes, next, nil
+}
+
 // PendingTxGet returns a list of transactions and a list of missing
 // transaction hashes from the pending transaction pool.
 func (a *Application) PendingTxGet(txn *badger.Txn, height uint32, txHashes [][]byte) ([]interfaces.Transaction, [][]byte, error) {
@@ -301,11 +319,24 @@ func (a *Application) UTXOGetData(txn *badger.Txn, curveSpec constants.CurveSpec
 	return a.txHandler.UTXOGetData(txn, owner, dataIdx)
 }
 
+// UTXOGetDataStore returns the DataStore UTXO stored by the account at the
+// data index.
+func (a *Application) UTXOGetDataStore(txn *badger.Txn, curveSpec constants.CurveSpec, account, dataIdx []byte) (*objs.TXOut, error) {
+	owner := &objs.Owner{}
+	err := owner.New(account, curveSpec)
+	if err != nil {
+		utils.DebugTrace(a.logger, err)
+		return nil, err
+	}
+	return a.txHandler.UTXOGetDataStore(txn, owner, dataIdx)
+}
+
 // GetValueForOwner returns a list utxoIDs and value for the specified account.
 // The purpose of this function is to allow for a user to request
 // a specific value and then to receive a list of utxoIDs whose sum
-// is greater than or equal to the requested value.
-func (a *Application) GetValueForOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, minValue *uint256.Uint256, ptBytes []byte) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
+// is greater than or equal to the requested value. Only value which is
+// spendable at currentHeight is returned.
+func (a *Application) GetValueForOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, currentHeight uint32, minValue *uint256.Uint256, ptBytes []byte) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
 	owner := &objs.Owner{}
 	err := owner.New(account, curveSpec)
 	if err != nil {
@@ -323,7 +354,44 @@ func (a *Application) GetValueForOwner(txn *badger.Txn, curveSpec constants.Curv
 		}
 	}
 
-	return a.txHandler.GetValueForOwner(txn, owner, minValue, pt)
+	return a.txHandler.GetValueForOwner(txn, owner, currentHeight, minValue, pt)
+}
+
+// GetAssetValueForOwner returns a list of utxoIDs and the amount of the asset
+// assetID held by the TokenStores of the specified account. As with
+// GetValueForOwner, the utxoIDs sum to at least minAmount when possible.
+func (a *Application) GetAssetValueForOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, assetID []byte, minAmount *uint256.Uint256, ptBytes []byte) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
+	owner := &objs.Owner{}
+	err := owner.New(account, curveSpec)
+	if err != nil {
+		utils.DebugTrace(a.logger, err)
+		return nil, nil, nil, err
+	}
+
+	var pt *objs.PaginationToken
+	if ptBytes != nil {
+		pt = &objs.PaginationToken{}
+		err := pt.UnmarshalBinary(ptBytes)
+		if err != nil {
+			utils.DebugTrace(a.logger, err)
+			return nil, nil, nil, err
+		}
+	}
+
+	return a.txHandler.GetAssetValueForOwner(txn, owner, assetID, minAmount, pt)
+}
+
+// GetLockedValueForOwner returns the value of the time locked ValueStores of
+// the specified account which is still locked at currentHeight, along with
+// the value which has already vested.
+func (a *Application) GetLockedValueForOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, currentHeight uint32) (*uint256.Uint256, *uint256.Uint256, error) {
+	owner := &objs.Owner{}
+	err := owner.New(account, curveSpec)
+	if err != nil {
+		utils.DebugTrace(a.logger, err)
+		return nil, nil, err
+	}
+	return a.txHandler.GetLockedValueForOwner(txn, owner, currentHeight)
 }
 
 // UTXOGet returns a list of UTXO objects as specified by the utxoIDs.
@@ -331,6 +399,23 @@ func (a *Application) UTXOGet(txn *badger.Txn, utxoIDs [][]byte) ([]*objs.TXOut,
 	return a.txHandler.UTXOGet(txn, utxoIDs)
 }
 
+// UTXOGetAtHeight returns the UTXOs which were in the state at height, as
+// well as the utxoIDs which were not. Deposits are not returned.
+func (a *Application) UTXOGetAtHeight(txn *badger.Txn, height uint32, utxoIDs [][]byte) ([]*objs.TXOut, [][]byte, error) {
+	return a.txHandler.UTXOGetAtHeight(txn, height, utxoIDs)
+}
+
+// GetStateRootForHeight returns the StateRoot at height; badger.ErrKeyNotFound
+// is returned if the state at height is not retained.
+func (a *Application) GetStateRootForHeight(txn *badger.Txn, height uint32) ([]byte, error) {
+	return a.txHandler.GetStateRootForHeight(txn, height)
+}
+
+// DepositGet returns a deposit by utxoID and whether it has been spent.
+func (a *Application) DepositGet(txn *badger.Txn, utxoID []byte) (*objs.TXOut, bool, error) {
+	return a.txHandler.DepositGet(txn, utxoID)
+}
+
 // PaginateDataByOwner returns a list of UTXOIDs and indexes from an account
 // namespace.
 func (a *Application) PaginateDataByOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, height uint32, numItems int, startIndex []byte) ([]*objs.PaginationResponse, error) {
@@ -343,6 +428,19 @@ func (a *Application) PaginateDataByOwner(txn *badger.Txn, curveSpec constants.C
 	return a.txHandler.PaginateDataByOwner(txn, owner, height, numItems, startIndex)
 }
 
+// QueryDataByOwner returns the UTXOIDs and indexes of the datastores of an
+// account namespace selected by the query, along with the index to continue
+// the query from if more datastores match.
+func (a *Application) QueryDataByOwner(txn *badger.Txn, curveSpec constants.CurveSpec, account []byte, height uint32, q *objs.DataStoreQuery, withMetadata bool) ([]*objs.PaginationResponse, []byte, error) {
+	owner := &objs.Owner{}
+	err := owner.New(account, curveSpec)
+	if err != nil {
+		utils.DebugTrace(a.logger, err)
+		return nil, nil, err
+	}
+	return a.txHandler.QueryDataByOwner(txn, owner, height, q, withMetadata)
+}
+
 // GetHeightForTx returns the height at which a tx was mined.
 func (a *Application) GetHeightForTx(txn *badger.Txn, txHash []byte) (uint32, error) {
 	return a.txHandler.GetHeightForTx(txn, txHash)
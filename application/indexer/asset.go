@@ -0,0 +1,102 @@
+package indexer
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/alicenet/alicenet/application/objs"
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+/*
+
+== BADGER KEYS ==
+
+lookup:
+key: <prefix>|<assetID>|<owner>|<amount>|<utxoID>
+  value: <utxoID>
+
+reverse lookup:
+key: <prefix>|<utxoID>
+  value: <assetID>|<owner>|<amount>|<utxoID>
+
+*/
+
+// NewAssetIndex returns a new AssetIndex
+func NewAssetIndex(p, pp prefixFunc) *AssetIndex {
+	return &AssetIndex{p, pp}
+}
+
+// AssetIndex creates an index that tracks the amounts of fungible assets
+type AssetIndex struct {
+	prefix    prefixFunc
+	refPrefix prefixFunc
+}
+
+// Add adds a utxoID to the index
+func (ai *AssetIndex) Add(txn *badger.Txn, utxoID []byte, assetID []byte, owner *objs.Owner, amount *uint256.Uint256) error {
+	if len(assetID) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("assetIndex.add; invalid assetID")
+	}
+	amountBytes, err := amount.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	ownerBytes, err := owner.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	assetIndex := []byte{}
+	assetIndex = append(assetIndex, utils.CopySlice(assetID)...)
+	assetIndex = append(assetIndex, ownerBytes...)
+	assetIndex = append(assetIndex, amountBytes...)
+	assetIndex = append(assetIndex, utils.CopySlice(utxoID)...)
+	key := ai.prefix()
+	key = append(key, assetIndex...)
+	refKey := ai.makeRefKey(utxoID)
+	err = utils.SetValue(txn, refKey, assetIndex)
+	if err != nil {
+		return err
+	}
+	return utils.SetValue(txn, key, utils.CopySlice(utxoID))
+}
+
+// Drop removes a utxoID from the index
+func (ai *AssetIndex) Drop(txn *badger.Txn, utxoID []byte) error {
+	refKey := ai.makeRefKey(utxoID)
+	assetIndex, err := utils.GetValue(txn, refKey)
+	if err != nil {
+		return err
+	}
+	key := ai.prefix()
+	key = append(key, assetIndex...)
+	err = utils.DeleteValue(txn, refKey)
+	if err != nil {
+		return err
+	}
+	return utils.DeleteValue(txn, key)
+}
+
+// GetAmountForOwner returns a list of utxoIDs holding the asset assetID
+// which sum to the specified amount
+func (ai *AssetIndex) GetAmountForOwner(txn *badger.Txn, assetID []byte, owner *objs.Owner, minAmount *uint256.Uint256, excludeFn func([]byte) (bool, error), maxCount int, lastKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+	if len(assetID) != constants.HashLen {
+		return nil, nil, nil, errorz.ErrInvalid{}.New("assetIndex.getAmountForOwner; invalid assetID")
+	}
+	ownerBytes, err := owner.MarshalBinary()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	prefix := ai.prefix()
+	prefix = append(prefix, utils.CopySlice(assetID)...)
+	prefix = append(prefix, ownerBytes...)
+	return getValueForPrefix(txn, prefix, minAmount, excludeFn, maxCount, lastKey)
+}
+
+func (ai *AssetIndex) makeRefKey(utxoID []byte) []byte {
+	refKey := ai.refPrefix()
+	refKey = append(refKey, utils.CopySlice(utxoID)...)
+	return refKey
+}
@@ -0,0 +1,88 @@
+package indexer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/internal/testing/environment"
+)
+
+func makeAssetIndex() *AssetIndex {
+	prefix1 := func() []byte {
+		return []byte("ya")
+	}
+	prefix2 := func() []byte {
+		return []byte("yb")
+	}
+	return NewAssetIndex(prefix1, prefix2)
+}
+
+func TestAssetIndexGetAmountForOwner(t *testing.T) {
+	t.Parallel()
+	db := environment.SetupBadgerDatabase(t)
+
+	index := makeAssetIndex()
+	owner := makeOwner()
+	assetA := crypto.Hasher([]byte("assetA"))
+	assetB := crypto.Hasher([]byte("assetB"))
+	utxoIDA1 := crypto.Hasher([]byte("utxoIDA1"))
+	utxoIDA2 := crypto.Hasher([]byte("utxoIDA2"))
+	utxoIDB := crypto.Hasher([]byte("utxoIDB"))
+	amount := uint256.One()
+
+	err := db.Update(func(txn *badger.Txn) error {
+		if err := index.Add(txn, utxoIDA1, crypto.Hasher([]byte("short"))[:4], owner, amount); err == nil {
+			t.Fatal("Should have raised error for an invalid assetID")
+		}
+		for _, add := range []struct {
+			utxoID  []byte
+			assetID []byte
+		}{{utxoIDA1, assetA}, {utxoIDA2, assetA}, {utxoIDB, assetB}} {
+			if err := index.Add(txn, add.utxoID, add.assetID, owner, amount); err != nil {
+				t.Fatal(err)
+			}
+		}
+		minAmount, err := new(uint256.Uint256).FromUint64(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utxoIDs, total, _, err := index.GetAmountForOwner(txn, assetA, owner, minAmount, nil, 256, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 2 {
+			t.Fatalf("expected 2 utxos of asset A; got %v", len(utxoIDs))
+		}
+		for _, utxoID := range utxoIDs {
+			if bytes.Equal(utxoID, utxoIDB) {
+				t.Fatal("returned a utxo of asset B")
+			}
+		}
+		two, err := new(uint256.Uint256).FromUint64(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !total.Eq(two) {
+			t.Fatalf("expected a total amount of 2; got %v", total)
+		}
+
+		if err := index.Drop(txn, utxoIDA1); err != nil {
+			t.Fatal(err)
+		}
+		utxoIDs, _, _, err = index.GetAmountForOwner(txn, assetA, owner, minAmount, nil, 256, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || !bytes.Equal(utxoIDs[0], utxoIDA2) {
+			t.Fatalf("expected only utxo A2 after drop; got %x", utxoIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
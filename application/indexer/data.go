@@ -227,6 +227,56 @@ func (di *DataIndex) PaginateDataStores(txn *badger.Txn, owner *objs.Owner, num
 	return result, nil
 }
 
+// QueryDataStores calls fn with the datastores of owner selected by q, in
+// the order of q, until fn returns false.
+func (di *DataIndex) QueryDataStores(txn *badger.Txn, owner *objs.Owner, q *objs.DataStoreQuery, fn func(*objs.PaginationResponse) (bool, error)) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	iterKey, err := di.makeIterKey(owner)
+	if err != nil {
+		return err
+	}
+	diSeekKey, err := di.makeKey(owner, q.SeekIndex())
+	if err != nil {
+		return err
+	}
+	prefix := append(iterKey, q.Prefix...)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	opts.Reverse = q.Reverse
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+	for iter.Seek(diSeekKey.MarshalBinary()); iter.ValidForPrefix(prefix); iter.Next() {
+		itm := iter.Item()
+		dk := &DataIndexKey{}
+		dk.UnmarshalBinary(itm.KeyCopy(nil))
+		if !q.Matches(dk.index) {
+			// only the exclusive bound the iteration seeks to may precede
+			// the selected indexes; any other index is past the bounds
+			if bytes.Equal(dk.index, q.After) || bytes.Equal(dk.index, q.End) {
+				continue
+			}
+			break
+		}
+		utxoID, err := itm.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		ok, err := fn(&objs.PaginationResponse{
+			UTXOID: utils.CopySlice(utxoID),
+			Index:  utils.CopySlice(dk.index),
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
 func (di *DataIndex) makeIterKey(owner *objs.Owner) ([]byte, error) {
 	ownerBytes, err := owner.MarshalBinary()
 	if err != nil {
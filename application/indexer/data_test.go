@@ -411,3 +411,96 @@ func TestDataIndexPaginateDataStores(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDataIndexQueryDataStores(t *testing.T) {
+	t.Parallel()
+	db := environment.SetupBadgerDatabase(t)
+
+	index := makeDataIndex()
+	owner := makeOwner()
+	other := &objs.Owner{}
+	if err := other.New(crypto.Hasher([]byte("other"))[:constants.OwnerLen], constants.CurveSecp256k1); err != nil {
+		t.Fatal(err)
+	}
+	makeIndex := func(p, k byte) []byte {
+		idx := make([]byte, constants.HashLen)
+		idx[0] = p
+		idx[1] = k
+		return idx
+	}
+	err := db.Update(func(txn *badger.Txn) error {
+		for p := byte(1); p <= 3; p++ {
+			for k := byte(0); k < 5; k++ {
+				utxoID := crypto.Hasher(makeIndex(p, k))
+				if err := index.Add(txn, utxoID, owner, makeIndex(p, k)); err != nil {
+					return err
+				}
+			}
+		}
+		return index.Add(txn, crypto.Hasher([]byte("other")), other, makeIndex(2, 9))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		q        *objs.DataStoreQuery
+		expected [][]byte
+	}{
+		{"prefix", &objs.DataStoreQuery{Prefix: []byte{2}, Limit: 10},
+			[][]byte{makeIndex(2, 0), makeIndex(2, 1), makeIndex(2, 2), makeIndex(2, 3), makeIndex(2, 4)}},
+		{"prefix reverse", &objs.DataStoreQuery{Prefix: []byte{2}, Reverse: true, Limit: 10},
+			[][]byte{makeIndex(2, 4), makeIndex(2, 3), makeIndex(2, 2), makeIndex(2, 1), makeIndex(2, 0)}},
+		{"range", &objs.DataStoreQuery{Start: []byte{1, 3}, End: []byte{2, 2}, Limit: 10},
+			[][]byte{makeIndex(1, 3), makeIndex(1, 4), makeIndex(2, 0), makeIndex(2, 1)}},
+		{"range reverse", &objs.DataStoreQuery{Start: []byte{1, 3}, End: []byte{2, 2}, Reverse: true, Limit: 10},
+			[][]byte{makeIndex(2, 1), makeIndex(2, 0), makeIndex(1, 4), makeIndex(1, 3)}},
+		{"exclusive end reverse", &objs.DataStoreQuery{Prefix: []byte{2}, End: makeIndex(2, 3), Reverse: true, Limit: 10},
+			[][]byte{makeIndex(2, 2), makeIndex(2, 1), makeIndex(2, 0)}},
+		{"after", &objs.DataStoreQuery{Prefix: []byte{2}, After: makeIndex(2, 1), Limit: 10},
+			[][]byte{makeIndex(2, 2), makeIndex(2, 3), makeIndex(2, 4)}},
+		{"after reverse", &objs.DataStoreQuery{Prefix: []byte{2}, After: makeIndex(2, 1), Reverse: true, Limit: 10},
+			[][]byte{makeIndex(2, 0)}},
+		{"start in prefix", &objs.DataStoreQuery{Prefix: []byte{3}, Start: []byte{3, 3}, Limit: 10},
+			[][]byte{makeIndex(3, 3), makeIndex(3, 4)}},
+		{"reverse all", &objs.DataStoreQuery{Reverse: true, Limit: 2},
+			[][]byte{makeIndex(3, 4), makeIndex(3, 3)}},
+		{"missing prefix", &objs.DataStoreQuery{Prefix: []byte{9}, Limit: 10}, nil},
+		{"missing prefix reverse", &objs.DataStoreQuery{Prefix: []byte{0}, Reverse: true, Limit: 10}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := [][]byte{}
+			err := db.View(func(txn *badger.Txn) error {
+				return index.QueryDataStores(txn, owner, tc.q, func(item *objs.PaginationResponse) (bool, error) {
+					if !bytes.Equal(item.UTXOID, crypto.Hasher(item.Index)) {
+						t.Fatal("wrong utxoID")
+					}
+					got = append(got, item.Index)
+					return len(got) < tc.q.Limit, nil
+				})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v datastores; got %v", len(tc.expected), len(got))
+			}
+			for i := range got {
+				if !bytes.Equal(got[i], tc.expected[i]) {
+					t.Fatalf("wrong index %v: %x", i, got[i])
+				}
+			}
+		})
+	}
+
+	err = db.View(func(txn *badger.Txn) error {
+		return index.QueryDataStores(txn, owner, &objs.DataStoreQuery{}, func(*objs.PaginationResponse) (bool, error) {
+			return true, nil
+		})
+	})
+	if err == nil {
+		t.Fatal("Should have raised error (invalid query)")
+	}
+}
@@ -402,6 +402,81 @@ func TestRefLinkerEvict(t *testing.T) {
 	}
 }
 
+func TestRefLinkerGetTxHashes(t *testing.T) {
+	t.Parallel()
+	db := environment.SetupBadgerDatabase(t)
+
+	prefix1 := func() []byte {
+		return []byte("za")
+	}
+
+	prefix2 := func() []byte {
+		return []byte("zb")
+	}
+
+	prefix3 := func() []byte {
+		return []byte("zk")
+	}
+
+	txHash1 := trie.Hasher([]byte("foo"))
+	utxoIDs1 := [][]byte{}
+	utxoIDs1 = append(utxoIDs1, trie.Hasher([]byte("a")))
+	utxoIDs1 = append(utxoIDs1, trie.Hasher([]byte("b")))
+
+	txHash2 := trie.Hasher([]byte("bar"))
+	utxoIDs2 := [][]byte{}
+	utxoIDs2 = append(utxoIDs2, trie.Hasher([]byte("a")))
+	utxoIDs2 = append(utxoIDs2, trie.Hasher([]byte("c")))
+
+	rl := NewRefLinkerIndex(prefix1, prefix2, prefix3)
+	err := db.Update(func(txn *badger.Txn) error {
+		_, _, err := rl.Add(txn, txHash1, utxoIDs1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = rl.Add(txn, txHash2, utxoIDs2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txHashes, err := rl.GetTxHashes(txn, trie.Hasher([]byte("a")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 2 {
+			t.Fatal("wrong txHashes length", len(txHashes))
+		}
+		txHashes, err = rl.GetTxHashes(txn, trie.Hasher([]byte("c")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(txHashes[0], txHash2) {
+			t.Fatal("txHash2 should be the only reference to c")
+		}
+		err = rl.Delete(txn, txHash2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txHashes, err = rl.GetTxHashes(txn, trie.Hasher([]byte("a")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(txHashes[0], txHash1) {
+			t.Fatal("txHash1 should be the only reference to a")
+		}
+		txHashes, err = rl.GetTxHashes(txn, trie.Hasher([]byte("d")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 0 {
+			t.Fatal("d should not be referenced")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestHeightIdx(t *testing.T) {
 	t.Parallel()
 	db := environment.SetupBadgerDatabase(t)
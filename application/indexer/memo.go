@@ -0,0 +1,147 @@
+package indexer
+
+/*
+Given memoHash get the txHashes of the txs carrying the memo
+  <prefix>|<memoHash>|<txHash>
+      <>
+
+given txHash get memoHash
+  <refPrefix>|<txHash>
+      <memoHash>
+*/
+
+import (
+	"bytes"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// NewMemoIndex makes a new MemoIndex
+func NewMemoIndex(p, pp prefixFunc) *MemoIndex {
+	return &MemoIndex{p, pp}
+}
+
+// MemoIndex is an indexer which stores the txs by the hash of their memo;
+// this is used in the mined tx handler.
+type MemoIndex struct {
+	prefix    prefixFunc
+	prefixRef prefixFunc
+}
+
+type MemoIndexKey struct {
+	key []byte
+}
+
+// MarshalBinary returns the byte slice for the key object.
+func (mik *MemoIndexKey) MarshalBinary() []byte {
+	return utils.CopySlice(mik.key)
+}
+
+// UnmarshalBinary takes in a byte slice to set the key object.
+func (mik *MemoIndexKey) UnmarshalBinary(data []byte) {
+	mik.key = utils.CopySlice(data)
+}
+
+type MemoIndexRefKey struct {
+	refkey []byte
+}
+
+// MarshalBinary returns the byte slice for the key object.
+func (mirk *MemoIndexRefKey) MarshalBinary() []byte {
+	return utils.CopySlice(mirk.refkey)
+}
+
+// UnmarshalBinary takes in a byte slice to set the key object.
+func (mirk *MemoIndexRefKey) UnmarshalBinary(data []byte) {
+	mirk.refkey = utils.CopySlice(data)
+}
+
+// Add adds a tx to the indexer by storing the txHash under the memoHash
+func (mi *MemoIndex) Add(txn *badger.Txn, txHash, memoHash []byte) error {
+	if len(memoHash) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("memoIndex.add: invalid memoHash length")
+	}
+	miKey := mi.makeKey(memoHash, txHash)
+	key := miKey.MarshalBinary()
+	miRefKey := mi.makeRefKey(txHash)
+	refKey := miRefKey.MarshalBinary()
+	err := utils.SetValue(txn, refKey, utils.CopySlice(memoHash))
+	if err != nil {
+		return err
+	}
+	return utils.SetValue(txn, key, []byte{})
+}
+
+// Delete removes a tx from the indexer; txs without a memo are ignored
+func (mi *MemoIndex) Delete(txn *badger.Txn, txHash []byte) error {
+	miRefKey := mi.makeRefKey(txHash)
+	refKey := miRefKey.MarshalBinary()
+	memoHash, err := utils.GetValue(txn, refKey)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+	miKey := mi.makeKey(memoHash, txHash)
+	key := miKey.MarshalBinary()
+	err = utils.DeleteValue(txn, key)
+	if err != nil {
+		return err
+	}
+	return utils.DeleteValue(txn, refKey)
+}
+
+// GetTxHashes returns at most maxCount txHashes of the txs carrying the
+// memo with memoHash, starting after startTxHash when it is not empty; the
+// last txHash is returned to continue the iteration when more txs remain.
+func (mi *MemoIndex) GetTxHashes(txn *badger.Txn, memoHash []byte, maxCount int, startTxHash []byte) ([][]byte, []byte, error) {
+	if maxCount < 1 {
+		return nil, nil, errorz.ErrInvalid{}.New("memoIndex.getTxHashes: maxCount must be positive")
+	}
+	prefix := mi.makeKey(memoHash, nil).MarshalBinary()
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = prefix
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+	seek := prefix
+	if len(startTxHash) > 0 {
+		seek = mi.makeKey(memoHash, startTxHash).MarshalBinary()
+	}
+	var result [][]byte
+	for iter.Seek(seek); iter.ValidForPrefix(prefix); iter.Next() {
+		txHash := iter.Item().KeyCopy(nil)[len(prefix):]
+		if bytes.Equal(txHash, startTxHash) {
+			continue
+		}
+		if len(result) >= maxCount {
+			return result, utils.CopySlice(result[len(result)-1]), nil
+		}
+		result = append(result, txHash)
+	}
+	return result, nil, nil
+}
+
+func (mi *MemoIndex) makeKey(memoHash, txHash []byte) *MemoIndexKey {
+	key := []byte{}
+	key = append(key, mi.prefix()...)
+	key = append(key, utils.CopySlice(memoHash)...)
+	key = append(key, utils.CopySlice(txHash)...)
+	miKey := &MemoIndexKey{}
+	miKey.UnmarshalBinary(key)
+	return miKey
+}
+
+func (mi *MemoIndex) makeRefKey(txHash []byte) *MemoIndexRefKey {
+	refKey := []byte{}
+	refKey = append(refKey, mi.prefixRef()...)
+	refKey = append(refKey, utils.CopySlice(txHash)...)
+	miRefKey := &MemoIndexRefKey{}
+	miRefKey.UnmarshalBinary(refKey)
+	return miRefKey
+}
@@ -0,0 +1,101 @@
+package indexer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/internal/testing/environment"
+)
+
+func makeMemoIndex() *MemoIndex {
+	prefix1 := func() []byte {
+		return []byte("ze")
+	}
+	prefix2 := func() []byte {
+		return []byte("zf")
+	}
+	index := NewMemoIndex(prefix1, prefix2)
+	return index
+}
+
+func TestMemoIndexGetTxHashes(t *testing.T) {
+	t.Parallel()
+	db := environment.SetupBadgerDatabase(t)
+
+	index := makeMemoIndex()
+	memoHash := crypto.Hasher([]byte("memo"))
+	otherMemoHash := crypto.Hasher([]byte("other memo"))
+	txHash1 := crypto.Hasher([]byte("txHash1"))
+	txHash2 := crypto.Hasher([]byte("txHash2"))
+	txHash3 := crypto.Hasher([]byte("txHash3"))
+
+	err := db.Update(func(txn *badger.Txn) error {
+		err := index.Add(txn, txHash1, []byte("memo"))
+		if err == nil {
+			t.Fatal("Should raise an error")
+		}
+		if err := index.Add(txn, txHash1, memoHash); err != nil {
+			t.Fatal(err)
+		}
+		if err := index.Add(txn, txHash2, memoHash); err != nil {
+			t.Fatal(err)
+		}
+		if err := index.Add(txn, txHash3, otherMemoHash); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = index.GetTxHashes(txn, memoHash, 0, nil)
+		if err == nil {
+			t.Fatal("Should raise an error")
+		}
+		txHashes, next, err := index.GetTxHashes(txn, memoHash, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 2 || next != nil {
+			t.Fatalf("invalid txHashes: %x next: %x", txHashes, next)
+		}
+		txHashes, next, err = index.GetTxHashes(txn, memoHash, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(next, txHashes[0]) {
+			t.Fatalf("invalid first page: %x next: %x", txHashes, next)
+		}
+		txHashes2, next, err := index.GetTxHashes(txn, memoHash, 1, next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes2) != 1 || next != nil || bytes.Equal(txHashes[0], txHashes2[0]) {
+			t.Fatalf("invalid second page: %x next: %x", txHashes2, next)
+		}
+
+		if err := index.Delete(txn, txHash1); err != nil {
+			t.Fatal(err)
+		}
+		// deleting a tx which is not indexed is a no-op
+		if err := index.Delete(txn, txHash1); err != nil {
+			t.Fatal(err)
+		}
+		txHashes, _, err = index.GetTxHashes(txn, memoHash, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(txHashes[0], txHash2) {
+			t.Fatalf("invalid txHashes after delete: %x", txHashes)
+		}
+		txHashes, _, err = index.GetTxHashes(txn, otherMemoHash, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(txHashes[0], txHash3) {
+			t.Fatalf("invalid txHashes for other memo: %x", txHashes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
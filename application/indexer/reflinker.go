@@ -142,6 +142,27 @@ func (rl *RefLinker) Add(txn *badger.Txn, txHash []byte, utxoIDs [][]byte) (bool
 	return true, evictions, nil
 }
 
+// GetTxHashes returns the txhashes which currently reference the utxoID
+func (rl *RefLinker) GetTxHashes(txn *badger.Txn, utxoID []byte) ([][]byte, error) {
+	utxoIDCopy := utils.CopySlice(utxoID)
+	txHashes := [][]byte{}
+	opts := badger.DefaultIteratorOptions
+	prefix := append(rl.prefixRevRef(), utxoIDCopy...)
+	opts.Prefix = prefix
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		itm := iter.Item()
+		refKey, err := itm.ValueCopy(nil)
+		if err != nil {
+			return nil, err
+		}
+		txHash := refKey[len(refKey)-64 : len(refKey)-32]
+		txHashes = append(txHashes, utils.CopySlice(txHash))
+	}
+	return txHashes, nil
+}
+
 // DeleteMined removes the txhash from the RefLinker as well as all other txs
 // which reference the same utxoIDs
 func (rl *RefLinker) DeleteMined(txn *badger.Txn, txHash []byte) ([][]byte, [][]byte, error) {
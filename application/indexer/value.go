@@ -111,6 +111,12 @@ func (vi *ValueIndex) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minVa
 
 	prefix := vi.prefix()
 	prefix = append(prefix, ownerBytes...)
+	return getValueForPrefix(txn, prefix, minValue, excludeFn, maxCount, lastKey)
+}
+
+// getValueForPrefix returns a list of utxoIDs which sum to the specified
+// value from the keys <prefix>|<value>|<utxoID>
+func getValueForPrefix(txn *badger.Txn, prefix []byte, minValue *uint256.Uint256, excludeFn func([]byte) (bool, error), maxCount int, lastKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
 	opts := badger.DefaultIteratorOptions
 	opts.Prefix = prefix
 	iter := txn.NewIterator(opts)
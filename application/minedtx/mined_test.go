@@ -25,7 +25,7 @@ func makeWrapperStorageMock() *wrapper.Storage {
 	mocks.GetMaxBlockSizeFunc.SetDefaultReturn(0)
 	mocks.GetMaxProposalSizeFunc.SetDefaultReturn(0)
 	mocks.GetMinScaledTransactionFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(0))
-	return wrapper.NewStorage(mocks)
+	return wrapper.NewStorage(mocks, 1)
 }
 
 func testingOwner(t *testing.T) objs.Signer {
@@ -576,3 +576,94 @@ func TestMinedMakeMinedTxKey(t *testing.T) {
 		t.Fatal("keys do not agree")
 	}
 }
+
+func makeMemoTx(t *testing.T, ownerSigner objs.Signer, memo []byte) *objs.Tx {
+	t.Helper()
+	consumedUTXOs, tx := makeTxInitial(t, ownerSigner, 1)
+	memoTx := &objs.Tx{Vin: tx.Vin, Vout: tx.Vout, Fee: tx.Fee, Memo: memo}
+	err := memoTx.SetTxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := consumedUTXOs[0].ValueStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = vs.Sign(memoTx.Vin[0], ownerSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return memoTx
+}
+
+func TestMinedGetTxHashesForMemo(t *testing.T) {
+	t.Parallel()
+	db := environment.SetupBadgerDatabase(t)
+	hndlr := NewMinedTxHandler()
+
+	ownerSigner := testingOwner(t)
+	memo := []byte("invoice-1")
+	tx1 := makeMemoTx(t, ownerSigner, memo)
+	tx2 := makeMemoTx(t, ownerSigner, memo)
+	tx3 := makeMemoTx(t, ownerSigner, []byte("invoice-2"))
+	_, tx4 := makeTxInitial(t, ownerSigner, 1)
+
+	err := db.Update(func(txn *badger.Txn) error {
+		err := hndlr.Add(txn, 1, []*objs.Tx{tx1, tx2, tx3, tx4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		memoHash := crypto.Hasher(memo)
+		first, next, err := hndlr.GetTxHashesForMemo(txn, memoHash, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(first) != 1 || len(next) == 0 {
+			t.Fatalf("invalid first page: %x next: %x", first, next)
+		}
+		second, next, err := hndlr.GetTxHashesForMemo(txn, memoHash, 1, next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(second) != 1 || next != nil {
+			t.Fatalf("invalid second page: %x next: %x", second, next)
+		}
+		tx1Hash, err := tx1.TxHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx2Hash, err := tx2.TxHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := [][]byte{first[0], second[0]}
+		if !(bytes.Equal(got[0], tx1Hash) && bytes.Equal(got[1], tx2Hash)) && !(bytes.Equal(got[0], tx2Hash) && bytes.Equal(got[1], tx1Hash)) {
+			t.Fatalf("invalid txhashes: %x", got)
+		}
+
+		err = hndlr.Delete(txn, [][]byte{tx1Hash})
+		if err != nil {
+			t.Fatal(err)
+		}
+		txHashes, _, err := hndlr.GetTxHashesForMemo(txn, memoHash, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txHashes) != 1 || !bytes.Equal(txHashes[0], tx2Hash) {
+			t.Fatalf("invalid txhashes after delete: %x", txHashes)
+		}
+		tx4Hash, err := tx4.TxHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// txs without a memo are not indexed
+		err = hndlr.Delete(txn, [][]byte{tx4Hash})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
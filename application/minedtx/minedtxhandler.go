@@ -16,12 +16,14 @@ import (
 func NewMinedTxHandler() *MinedTxHandler {
 	return &MinedTxHandler{
 		heightIdxIndex: indexer.NewHeightIdxIndex(dbprefix.PrefixMinedTxIndexKey, dbprefix.PrefixMinedTxIndexRefKey),
+		memoIndex:      indexer.NewMemoIndex(dbprefix.PrefixMinedTxMemoKey, dbprefix.PrefixMinedTxMemoRefKey),
 	}
 }
 
 // MinedTxHandler manages the storage of mined trasactions with indexing.
 type MinedTxHandler struct {
 	heightIdxIndex *indexer.HeightIdxIndex
+	memoIndex      *indexer.MemoIndex
 }
 
 // Add adds txs at height to MinedTxHandler.
@@ -48,6 +50,10 @@ func (mt *MinedTxHandler) Delete(txn *badger.Txn, txHashes [][]byte) error {
 		if err != nil {
 			return err
 		}
+		err = mt.memoIndex.Delete(txn, utils.CopySlice(txHash))
+		if err != nil {
+			return err
+		}
 		key := mt.makeMinedTxKey(utils.CopySlice(txHash))
 		if err := utils.DeleteValue(txn, key); err != nil {
 			return err
@@ -84,6 +90,12 @@ func (mt *MinedTxHandler) GetHeightForTx(txn *badger.Txn, txHash []byte) (uint32
 	return height, nil
 }
 
+// GetTxHashesForMemo returns at most maxCount txHashes of the mined txs
+// carrying the memo with memoHash, starting after startTxHash.
+func (mt *MinedTxHandler) GetTxHashesForMemo(txn *badger.Txn, memoHash []byte, maxCount int, startTxHash []byte) ([][]byte, []byte, error) {
+	return mt.memoIndex.GetTxHashes(txn, memoHash, maxCount, startTxHash)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 /////////PRIVATE METHODS////////////////////////////////////////////////////////
@@ -104,6 +116,12 @@ func (mt *MinedTxHandler) addOneInternal(txn *badger.Txn, tx *objs.Tx, txHash []
 	if err != nil {
 		return err
 	}
+	if memoHash := tx.MemoHash(); memoHash != nil {
+		err = mt.memoIndex.Add(txn, txHash, memoHash)
+		if err != nil {
+			return err
+		}
+	}
 	return db.SetTx(txn, key, tx)
 }
 
@@ -0,0 +1,212 @@
+package objs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+func makeAtomicSwap(t *testing.T) (*AtomicSwap, *crypto.Secp256k1Signer, *crypto.BNSigner, []byte) {
+	t.Helper()
+	owner, primarySigner, alternateSigner, hashKey := makeAtomicSwapOwner(t)
+	as := &AtomicSwap{}
+	err := as.New(1, uint256.Two(), uint256.One(), owner, 2, 5, crypto.Hasher([]byte("txHash")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return as, primarySigner, alternateSigner, hashKey
+}
+
+func TestAtomicSwapNew(t *testing.T) {
+	owner, _, _, _ := makeAtomicSwapOwner(t)
+	txHash := crypto.Hasher([]byte("txHash"))
+	as := &AtomicSwap{}
+	if err := as.New(1, uint256.Zero(), uint256.One(), owner, 2, 5, txHash); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	if err := as.New(0, uint256.Two(), uint256.One(), owner, 2, 5, txHash); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	if err := as.New(1, uint256.Two(), uint256.One(), owner, 0, 5, txHash); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+	if err := as.New(1, uint256.Two(), uint256.One(), owner, 5, 5, txHash); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+	if err := as.New(1, uint256.Two(), uint256.One(), &AtomicSwapOwner{}, 2, 5, txHash); err == nil {
+		t.Fatal("Should raise an error (4)")
+	}
+	if err := as.New(1, uint256.Two(), uint256.One(), owner, 2, 5, txHash[1:]); err == nil {
+		t.Fatal("Should raise an error (5)")
+	}
+	if err := as.New(1, uint256.Two(), uint256.One(), owner, 2, 5, txHash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAtomicSwapMarshalBinary(t *testing.T) {
+	as, _, _, _ := makeAtomicSwap(t)
+	if err := as.SetTxOutIdx(1); err != nil {
+		t.Fatal(err)
+	}
+	utxo := &TXOut{}
+	if err := utxo.NewAtomicSwap(as); err != nil {
+		t.Fatal(err)
+	}
+	data, err := utxo.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo2 := &TXOut{}
+	if err := utxo2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !utxo2.HasAtomicSwap() || utxo2.HasValueStore() || utxo2.HasDataStore() {
+		t.Fatal("Should have an AtomicSwap")
+	}
+	as2, err := utxo2.AtomicSwap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as2.ASPreImage.ChainID != 1 || as2.ASPreImage.TXOutIdx != 1 || as2.ASPreImage.IssuedAt != 2 || as2.ASPreImage.Exp != 5 {
+		t.Fatal("preimages do not match")
+	}
+	if as2.ASPreImage.Value.Cmp(uint256.Two()) != 0 || as2.ASPreImage.Fee.Cmp(uint256.One()) != 0 {
+		t.Fatal("values do not match")
+	}
+	if !bytes.Equal(as.TxHash, as2.TxHash) {
+		t.Fatal("tx hashes do not match")
+	}
+	utxoID, err := utxo.UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoID2, err := utxo2.UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(utxoID, utxoID2) {
+		t.Fatal("utxoIDs do not match")
+	}
+
+	as2.ASPreImage.Exp = as2.ASPreImage.IssuedAt
+	data, err = as2.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := as2.UnmarshalBinary(data); err == nil {
+		t.Fatal("Should raise an error")
+	}
+}
+
+func TestAtomicSwapOwners(t *testing.T) {
+	as, primarySigner, alternateSigner, _ := makeAtomicSwap(t)
+	utxo := &TXOut{}
+	if err := utxo.NewAtomicSwap(as); err != nil {
+		t.Fatal(err)
+	}
+	primaryPubk, err := primarySigner.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	onr, err := utxo.GenericOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(onr.Account, crypto.GetAccount(primaryPubk)) || onr.CurveSpec != constants.CurveSecp256k1 {
+		t.Fatal("invalid generic owner")
+	}
+	alternatePubk, err := alternateSigner.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	altOnr, err := as.AlternateGenericOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(altOnr.Account, crypto.GetAccount(alternatePubk)) || altOnr.CurveSpec != constants.CurveBN256Eth {
+		t.Fatal("invalid alternate generic owner")
+	}
+}
+
+func TestAtomicSwapValidateSignature(t *testing.T) {
+	as, primarySigner, alternateSigner, hashKey := makeAtomicSwap(t)
+	utxo := &TXOut{}
+	if err := utxo.NewAtomicSwap(as); err != nil {
+		t.Fatal(err)
+	}
+	beforeExp := 4 * constants.EpochLength
+	afterExp := beforeExp + 1
+
+	claim, err := utxo.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := as.SignAsPrimary(claim, primarySigner, hashKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(beforeExp, claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(afterExp, claim); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	refund, err := utxo.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := as.SignAsAlternate(refund, alternateSigner); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(afterExp, refund); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(beforeExp, refund); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+
+	vsSig := &TXIn{TXInLinker: refund.TXInLinker}
+	vs := &ValueStore{}
+	if err := vs.New(1, uint256.Two(), uint256.One(), make([]byte, constants.OwnerLen), constants.CurveSecp256k1, as.TxHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Sign(vsSig, primarySigner); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(beforeExp, vsSig); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+}
+
+func TestAtomicSwapMinedHeights(t *testing.T) {
+	as, _, _, _ := makeAtomicSwap(t)
+	utxo := &TXOut{}
+	if err := utxo.NewAtomicSwap(as); err != nil {
+		t.Fatal(err)
+	}
+	mustBefore, err := utxo.MustBeMinedBeforeHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mustBefore != 2*constants.EpochLength-1 {
+		t.Fatalf("invalid MustBeMinedBeforeHeight: %v", mustBefore)
+	}
+	cannotBefore, err := utxo.CannotBeMinedBeforeHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cannotBefore != constants.EpochLength+1 {
+		t.Fatalf("invalid CannotBeMinedBeforeHeight: %v", cannotBefore)
+	}
+	expired, err := utxo.IsExpired(10 * constants.EpochLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired {
+		t.Fatal("AtomicSwaps are never collected as expired")
+	}
+}
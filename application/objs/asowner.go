@@ -0,0 +1,377 @@
+package objs
+
+import (
+	"bytes"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// AtomicSwapSubOwner contains information related to one of the two owners
+// of an AtomicSwap.
+type AtomicSwapSubOwner struct {
+	CurveSpec constants.CurveSpec
+	Account   []byte
+}
+
+// New makes a new AtomicSwapSubOwner.
+func (asso *AtomicSwapSubOwner) New(acct []byte, curveSpec constants.CurveSpec) {
+	asso.CurveSpec = curveSpec
+	asso.Account = utils.CopySlice(acct)
+}
+
+// MarshalBinary takes the AtomicSwapSubOwner object and returns the canonical
+// byte slice.
+func (asso *AtomicSwapSubOwner) MarshalBinary() ([]byte, error) {
+	if err := asso.Validate(); err != nil {
+		return nil, err
+	}
+	owner := []byte{}
+	owner = append(owner, []byte{uint8(asso.CurveSpec)}...)
+	owner = append(owner, utils.CopySlice(asso.Account)...)
+	return owner, nil
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// AtomicSwapSubOwner object; the remaining bytes are returned.
+func (asso *AtomicSwapSubOwner) UnmarshalBinary(o []byte) ([]byte, error) {
+	if asso == nil {
+		return nil, errorz.ErrInvalid{}.New("asso.unmarshalBinary; asso not initialized")
+	}
+	owner := utils.CopySlice(o)
+	curveSpec, owner, err := extractCurveSpec(owner)
+	if err != nil {
+		return nil, err
+	}
+	account, owner, err := extractAccount(owner)
+	if err != nil {
+		return nil, err
+	}
+	asso.CurveSpec = curveSpec
+	asso.Account = account
+	if err := asso.Validate(); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// Validate validates the AtomicSwapSubOwner object.
+func (asso *AtomicSwapSubOwner) Validate() error {
+	if asso == nil {
+		return errorz.ErrInvalid{}.New("asso.validate; asso not initialized")
+	}
+	if !(asso.CurveSpec == constants.CurveSecp256k1) && !(asso.CurveSpec == constants.CurveBN256Eth) {
+		return errorz.ErrInvalid{}.New("asso.validate; invalid curve spec")
+	}
+	if len(asso.Account) != constants.OwnerLen {
+		return errorz.ErrInvalid{}.New("asso.validate; asso.account has incorrect length")
+	}
+	return nil
+}
+
+// ValidateSignature validates the signature for message msg.
+func (asso *AtomicSwapSubOwner) ValidateSignature(msg []byte, curveSpec constants.CurveSpec, signature []byte) error {
+	if err := asso.Validate(); err != nil {
+		return errorz.ErrInvalid{}.New("asso.validateSignature; invalid AtomicSwapSubOwner")
+	}
+	if asso.CurveSpec != curveSpec {
+		return errorz.ErrInvalid{}.New("asso.validateSignature; mismatched curve spec")
+	}
+	switch asso.CurveSpec {
+	case constants.CurveSecp256k1:
+		val := crypto.Secp256k1Validator{}
+		pk, err := val.Validate(msg, signature)
+		if err != nil {
+			return err
+		}
+		account := crypto.GetAccount(pk)
+		if !bytes.Equal(account, asso.Account) {
+			return errorz.ErrInvalid{}.New("asso.validateSignature; invalid sig for secp256k1 account")
+		}
+		return nil
+	case constants.CurveBN256Eth:
+		val := crypto.BNValidator{}
+		pk, err := val.Validate(msg, signature)
+		if err != nil {
+			return err
+		}
+		account := crypto.GetAccount(pk)
+		if !bytes.Equal(account, asso.Account) {
+			return errorz.ErrInvalid{}.New("asso.validateSignature; invalid sig for bn256 account")
+		}
+		return nil
+	default:
+		return errorz.ErrInvalid{}.New("asso.validateSignature; invalid curve spec")
+	}
+}
+
+// AtomicSwapOwner contains information related to the owners of the
+// AtomicSwap. The PrimaryOwner (recipient) may consume the AtomicSwap before
+// its expiration by revealing the preimage of the HashLock; the
+// AlternateOwner (refunder) may consume it once it has expired.
+type AtomicSwapOwner struct {
+	SVA            SVA
+	HashLock       []byte
+	PrimaryOwner   *AtomicSwapSubOwner
+	AlternateOwner *AtomicSwapSubOwner
+}
+
+// New makes a new AtomicSwapOwner.
+func (aso *AtomicSwapOwner) New(hashLock []byte, primaryAcct []byte, primaryCurveSpec constants.CurveSpec, alternateAcct []byte, alternateCurveSpec constants.CurveSpec) error {
+	if aso == nil {
+		return errorz.ErrInvalid{}.New("aso.new; aso not initialized")
+	}
+	primary := &AtomicSwapSubOwner{}
+	primary.New(primaryAcct, primaryCurveSpec)
+	alternate := &AtomicSwapSubOwner{}
+	alternate.New(alternateAcct, alternateCurveSpec)
+	aso.SVA = HashedTimelockSVA
+	aso.HashLock = utils.CopySlice(hashLock)
+	aso.PrimaryOwner = primary
+	aso.AlternateOwner = alternate
+	if err := aso.Validate(); err != nil {
+		aso.SVA = 0
+		aso.HashLock = nil
+		aso.PrimaryOwner = nil
+		aso.AlternateOwner = nil
+		return err
+	}
+	return nil
+}
+
+// MarshalBinary takes the AtomicSwapOwner object and returns the canonical
+// byte slice.
+func (aso *AtomicSwapOwner) MarshalBinary() ([]byte, error) {
+	if err := aso.Validate(); err != nil {
+		return nil, err
+	}
+	primary, err := aso.PrimaryOwner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	alternate, err := aso.AlternateOwner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	owner := []byte{}
+	owner = append(owner, []byte{uint8(aso.SVA)}...)
+	owner = append(owner, utils.CopySlice(aso.HashLock)...)
+	owner = append(owner, primary...)
+	owner = append(owner, alternate...)
+	return owner, nil
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// AtomicSwapOwner object.
+func (aso *AtomicSwapOwner) UnmarshalBinary(o []byte) error {
+	if aso == nil {
+		return errorz.ErrInvalid{}.New("aso.unmarshalBinary; aso not initialized")
+	}
+	owner := utils.CopySlice(o)
+	sva, owner, err := extractSVA(owner)
+	if err != nil {
+		return err
+	}
+	hashLock, owner, err := extractHash(owner)
+	if err != nil {
+		return err
+	}
+	primary := &AtomicSwapSubOwner{}
+	owner, err = primary.UnmarshalBinary(owner)
+	if err != nil {
+		return err
+	}
+	alternate := &AtomicSwapSubOwner{}
+	owner, err = alternate.UnmarshalBinary(owner)
+	if err != nil {
+		return err
+	}
+	if err := extractZero(owner); err != nil {
+		return err
+	}
+	aso.SVA = sva
+	aso.HashLock = hashLock
+	aso.PrimaryOwner = primary
+	aso.AlternateOwner = alternate
+	return aso.Validate()
+}
+
+// Validate validates the AtomicSwapOwner object.
+func (aso *AtomicSwapOwner) Validate() error {
+	if aso == nil {
+		return errorz.ErrInvalid{}.New("aso.validate; aso not initialized")
+	}
+	if aso.SVA != HashedTimelockSVA {
+		return errorz.ErrInvalid{}.New("aso.validate; invalid signature verification algorithm")
+	}
+	if len(aso.HashLock) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("aso.validate; aso.hashLock has incorrect length")
+	}
+	if err := aso.PrimaryOwner.Validate(); err != nil {
+		return err
+	}
+	return aso.AlternateOwner.Validate()
+}
+
+// ValidateSignature validates AtomicSwapSignature sig for message msg.
+// Before expiration only the PrimaryOwner may sign and the signature must
+// reveal the preimage of the HashLock; after expiration only the
+// AlternateOwner may sign.
+func (aso *AtomicSwapOwner) ValidateSignature(msg []byte, sig *AtomicSwapSignature, isExpired bool) error {
+	if err := aso.Validate(); err != nil {
+		return errorz.ErrInvalid{}.New("aso.validateSignature; invalid AtomicSwapOwner")
+	}
+	if err := sig.Validate(); err != nil {
+		return errorz.ErrInvalid{}.New("aso.validateSignature; invalid AtomicSwapSignature")
+	}
+	switch sig.SignerRole {
+	case PrimarySignerRole:
+		if isExpired {
+			return errorz.ErrInvalid{}.New("aso.validateSignature; atomic swap is expired")
+		}
+		if !bytes.Equal(crypto.Hasher(sig.HashKey), aso.HashLock) {
+			return errorz.ErrInvalid{}.New("aso.validateSignature; hash key does not match hash lock")
+		}
+		return aso.PrimaryOwner.ValidateSignature(msg, sig.CurveSpec, sig.Signature)
+	case AlternateSignerRole:
+		if !isExpired {
+			return errorz.ErrInvalid{}.New("aso.validateSignature; atomic swap is not expired")
+		}
+		return aso.AlternateOwner.ValidateSignature(msg, sig.CurveSpec, sig.Signature)
+	default:
+		return errorz.ErrInvalid{}.New("aso.validateSignature; invalid signer role")
+	}
+}
+
+// SignAsPrimary signs message msg with signer s as the PrimaryOwner,
+// revealing hashKey.
+func (aso *AtomicSwapOwner) SignAsPrimary(msg []byte, s Signer, hashKey []byte) (*AtomicSwapSignature, error) {
+	if err := aso.Validate(); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(crypto.Hasher(hashKey), aso.HashLock) {
+		return nil, errorz.ErrInvalid{}.New("aso.signAsPrimary; hash key does not match hash lock")
+	}
+	return aso.sign(msg, s, PrimarySignerRole, hashKey)
+}
+
+// SignAsAlternate signs message msg with signer s as the AlternateOwner.
+func (aso *AtomicSwapOwner) SignAsAlternate(msg []byte, s Signer) (*AtomicSwapSignature, error) {
+	if err := aso.Validate(); err != nil {
+		return nil, err
+	}
+	return aso.sign(msg, s, AlternateSignerRole, make([]byte, constants.HashLen))
+}
+
+func (aso *AtomicSwapOwner) sign(msg []byte, s Signer, role SignerRole, hashKey []byte) (*AtomicSwapSignature, error) {
+	sig := &AtomicSwapSignature{
+		SVA:        HashedTimelockSVA,
+		SignerRole: role,
+		HashKey:    utils.CopySlice(hashKey),
+	}
+	switch s.(type) {
+	case *crypto.Secp256k1Signer:
+		sig.CurveSpec = constants.CurveSecp256k1
+	case *crypto.BNSigner:
+		sig.CurveSpec = constants.CurveBN256Eth
+	default:
+		return nil, errorz.ErrInvalid{}.New("aso.sign; invalid signer type")
+	}
+	signature, err := s.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig.Signature = signature
+	return sig, nil
+}
+
+// AtomicSwapSignature is a struct which the necessary information
+// for signing an AtomicSwap. The HashKey is the preimage of the HashLock
+// when signed by the PrimaryOwner and all zeros otherwise.
+type AtomicSwapSignature struct {
+	SVA        SVA
+	CurveSpec  constants.CurveSpec
+	SignerRole SignerRole
+	HashKey    []byte
+	Signature  []byte
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// AtomicSwapSignature object.
+func (ass *AtomicSwapSignature) UnmarshalBinary(signature []byte) error {
+	if ass == nil {
+		return errorz.ErrInvalid{}.New("ass.unmarshalBinary; ass not initialized")
+	}
+	sva, signature, err := extractSVA(signature)
+	if err != nil {
+		return err
+	}
+	curveSpec, signature, err := extractCurveSpec(signature)
+	if err != nil {
+		return err
+	}
+	role, signature, err := extractSignerRole(signature)
+	if err != nil {
+		return err
+	}
+	hashKey, signature, err := extractHash(signature)
+	if err != nil {
+		return err
+	}
+	signature, null, err := extractSignature(signature, curveSpec)
+	if err != nil {
+		return err
+	}
+	if err := extractZero(null); err != nil {
+		return err
+	}
+	ass.SVA = sva
+	ass.CurveSpec = curveSpec
+	ass.SignerRole = role
+	ass.HashKey = hashKey
+	ass.Signature = signature
+	return ass.Validate()
+}
+
+// MarshalBinary takes the AtomicSwapSignature object and returns the canonical
+// byte slice.
+func (ass *AtomicSwapSignature) MarshalBinary() ([]byte, error) {
+	if err := ass.Validate(); err != nil {
+		return nil, err
+	}
+	signature := []byte{}
+	signature = append(signature, []byte{uint8(ass.SVA)}...)
+	signature = append(signature, []byte{uint8(ass.CurveSpec)}...)
+	signature = append(signature, []byte{uint8(ass.SignerRole)}...)
+	signature = append(signature, utils.CopySlice(ass.HashKey)...)
+	signature = append(signature, utils.CopySlice(ass.Signature)...)
+	return signature, nil
+}
+
+// Validate validates the AtomicSwapSignature object.
+func (ass *AtomicSwapSignature) Validate() error {
+	if ass == nil {
+		return errorz.ErrInvalid{}.New("ass.validate; ass not initialized")
+	}
+	if ass.SVA != HashedTimelockSVA {
+		return errorz.ErrInvalid{}.New("ass.validate; invalid signature verification algorithm")
+	}
+	if !(ass.CurveSpec == constants.CurveSecp256k1) && !(ass.CurveSpec == constants.CurveBN256Eth) {
+		return errorz.ErrInvalid{}.New("ass.validate; invalid curve spec")
+	}
+	if len(ass.HashKey) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("ass.validate; ass.hashKey has incorrect length")
+	}
+	switch ass.SignerRole {
+	case PrimarySignerRole:
+	case AlternateSignerRole:
+		if !bytes.Equal(ass.HashKey, make([]byte, constants.HashLen)) {
+			return errorz.ErrInvalid{}.New("ass.validate; hash key must be zero for the alternate signer")
+		}
+	default:
+		return errorz.ErrInvalid{}.New("ass.validate; invalid signer role")
+	}
+	return validateSignatureLen(ass.Signature, ass.CurveSpec)
+}
@@ -0,0 +1,199 @@
+package objs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+func makeAtomicSwapOwner(t *testing.T) (*AtomicSwapOwner, *crypto.Secp256k1Signer, *crypto.BNSigner, []byte) {
+	t.Helper()
+	primarySigner := &crypto.Secp256k1Signer{}
+	if err := primarySigner.SetPrivk(crypto.Hasher([]byte("recipient"))); err != nil {
+		t.Fatal(err)
+	}
+	primaryPubk, err := primarySigner.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	alternateSigner := &crypto.BNSigner{}
+	if err := alternateSigner.SetPrivk(crypto.Hasher([]byte("refunder"))); err != nil {
+		t.Fatal(err)
+	}
+	alternatePubk, err := alternateSigner.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashKey := crypto.Hasher([]byte("secret"))
+	aso := &AtomicSwapOwner{}
+	err = aso.New(crypto.Hasher(hashKey), crypto.GetAccount(primaryPubk), constants.CurveSecp256k1, crypto.GetAccount(alternatePubk), constants.CurveBN256Eth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aso, primarySigner, alternateSigner, hashKey
+}
+
+func TestASOwnerMarshalBinary(t *testing.T) {
+	aso := &AtomicSwapOwner{}
+	_, err := aso.MarshalBinary()
+	if err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	aso, _, _, _ = makeAtomicSwapOwner(t)
+	data, err := aso.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aso2 := &AtomicSwapOwner{}
+	if err := aso2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if aso2.SVA != HashedTimelockSVA {
+		t.Fatal("invalid SVA")
+	}
+	if !bytes.Equal(aso.HashLock, aso2.HashLock) {
+		t.Fatal("hash locks do not match")
+	}
+	if !bytes.Equal(aso.PrimaryOwner.Account, aso2.PrimaryOwner.Account) || aso2.PrimaryOwner.CurveSpec != constants.CurveSecp256k1 {
+		t.Fatal("primary owners do not match")
+	}
+	if !bytes.Equal(aso.AlternateOwner.Account, aso2.AlternateOwner.Account) || aso2.AlternateOwner.CurveSpec != constants.CurveBN256Eth {
+		t.Fatal("alternate owners do not match")
+	}
+
+	if err := aso2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	if err := aso2.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+	data[0] = uint8(ValueStoreSVA)
+	if err := aso2.UnmarshalBinary(data); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+}
+
+func TestASOwnerValidate(t *testing.T) {
+	aso, _, _, _ := makeAtomicSwapOwner(t)
+	if err := aso.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	aso.HashLock = aso.HashLock[1:]
+	if err := aso.Validate(); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	aso, _, _, _ = makeAtomicSwapOwner(t)
+	aso.AlternateOwner = nil
+	if err := aso.Validate(); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+
+	aso, _, _, _ = makeAtomicSwapOwner(t)
+	aso.PrimaryOwner.CurveSpec = 0
+	if err := aso.Validate(); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+}
+
+func TestASOwnerValidateSignaturePrimary(t *testing.T) {
+	msg := crypto.Hasher([]byte("msg"))
+	aso, primarySigner, alternateSigner, hashKey := makeAtomicSwapOwner(t)
+
+	if _, err := aso.SignAsPrimary(msg, primarySigner, crypto.Hasher([]byte("wrong"))); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	sig, err := aso.SignAsPrimary(msg, primarySigner, hashKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig, true); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+
+	sigBytes, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2 := &AtomicSwapSignature{}
+	if err := sig2.UnmarshalBinary(sigBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// the hash key must be the preimage of the hash lock
+	sig2.HashKey = crypto.Hasher([]byte("wrong"))
+	if err := aso.ValidateSignature(msg, sig2, false); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+
+	// the refunder cannot sign as the recipient
+	sig3, err := aso.sign(msg, alternateSigner, PrimarySignerRole, hashKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig3, false); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+}
+
+func TestASOwnerValidateSignatureAlternate(t *testing.T) {
+	msg := crypto.Hasher([]byte("msg"))
+	aso, primarySigner, alternateSigner, _ := makeAtomicSwapOwner(t)
+
+	sig, err := aso.SignAsAlternate(msg, alternateSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig, false); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	// the recipient cannot sign as the refunder
+	sig2, err := aso.SignAsAlternate(msg, primarySigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := aso.ValidateSignature(msg, sig2, true); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+
+	// the refunder must not reveal a hash key
+	sig.HashKey = crypto.Hasher([]byte("secret"))
+	if err := aso.ValidateSignature(msg, sig, true); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+}
+
+func TestASSignatureUnmarshalBinary(t *testing.T) {
+	sig := &AtomicSwapSignature{}
+	if err := sig.UnmarshalBinary(nil); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	data := make([]byte, 3+constants.HashLen+constants.CurveSecp256k1SigLen)
+	data[0] = uint8(HashedTimelockSVA)
+	data[1] = uint8(constants.CurveSecp256k1)
+	data[2] = uint8(AlternateSignerRole)
+	if err := sig.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	data[2] = 0
+	if err := sig.UnmarshalBinary(data); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	data[2] = uint8(AlternateSignerRole)
+	if err := sig.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+}
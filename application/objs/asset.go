@@ -0,0 +1,104 @@
+package objs
+
+import (
+	"fmt"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+)
+
+// MakeAssetID returns the identifier of the asset issued by a transaction
+// whose first input consumes the UTXO utxoID. A UTXO is consumed only once,
+// so every asset is issued by exactly one transaction.
+func MakeAssetID(utxoID []byte) []byte {
+	return crypto.Hasher(utxoID)
+}
+
+// IssuedAssetID returns the identifier of the asset which the transaction
+// may issue. The TokenStores of this asset created by the transaction define
+// the total supply of the asset.
+func (b *Tx) IssuedAssetID() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("tx.issuedAssetID: tx not initialized")
+	}
+	if len(b.Vin) == 0 {
+		return nil, errorz.ErrInvalid{}.New("tx.issuedAssetID: tx.vin not initialized")
+	}
+	utxoID, err := b.Vin[0].UTXOID()
+	if err != nil {
+		return nil, err
+	}
+	return MakeAssetID(utxoID), nil
+}
+
+// AssetAmounts returns the amount of each asset held by the TokenStores of
+// the vector, keyed by asset identifier.
+func (vout Vout) AssetAmounts() (map[string]*uint256.Uint256, error) {
+	amounts := make(map[string]*uint256.Uint256)
+	for i := 0; i < len(vout); i++ {
+		if !vout[i].HasTokenStore() {
+			continue
+		}
+		ts, err := vout[i].TokenStore()
+		if err != nil {
+			return nil, err
+		}
+		assetID, err := ts.AssetID()
+		if err != nil {
+			return nil, err
+		}
+		amount, err := ts.Amount()
+		if err != nil {
+			return nil, err
+		}
+		sum, ok := amounts[string(assetID)]
+		if !ok {
+			sum = uint256.Zero()
+		}
+		sum, err = new(uint256.Uint256).Add(sum, amount)
+		if err != nil {
+			return nil, err
+		}
+		amounts[string(assetID)] = sum
+	}
+	return amounts, nil
+}
+
+// validateAssets validates that the transaction conserves the amount of
+// each asset; only the asset issued by the transaction may be created.
+func (b *Tx) validateAssets(refUTXOs Vout) error {
+	amountsOut, err := b.Vout.AssetAmounts()
+	if err != nil {
+		return err
+	}
+	amountsIn, err := refUTXOs.AssetAmounts()
+	if err != nil {
+		return err
+	}
+	if len(amountsIn) == 0 && len(amountsOut) == 0 {
+		return nil
+	}
+	issued, err := b.IssuedAssetID()
+	if err != nil {
+		return err
+	}
+	for assetID, amountOut := range amountsOut {
+		if assetID == string(issued) {
+			continue
+		}
+		amountIn, ok := amountsIn[assetID]
+		if !ok {
+			amountIn = uint256.Zero()
+		}
+		if amountIn.Cmp(amountOut) != 0 {
+			return errorz.ErrInvalid{}.New(fmt.Sprintf("tx.validateAssets: input amount does not match output amount of asset %x: IN:%v  vs  OUT:%v", assetID, amountIn, amountOut))
+		}
+	}
+	for assetID, amountIn := range amountsIn {
+		if _, ok := amountsOut[assetID]; !ok {
+			return errorz.ErrInvalid{}.New(fmt.Sprintf("tx.validateAssets: input amount does not match output amount of asset %x: IN:%v  vs  OUT:0", assetID, amountIn))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package objs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+func TestTxIssuedAssetID(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	consumed := makeVSWithValueFee(t, signer, 1, uint256.Two(), uint256.Zero())
+	txIn, err := consumed.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &Tx{Vin: Vin{txIn}}
+	assetID, err := tx.IssuedAssetID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoID, err := consumed.UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(assetID, MakeAssetID(utxoID)) {
+		t.Fatal("the asset is not derived from the first consumed utxo")
+	}
+	if _, err := (&Tx{}).IssuedAssetID(); err == nil {
+		t.Fatal("Should raise an error without inputs")
+	}
+}
+
+func TestTxValidateEqualVinVoutAssets(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	vsIn := makeVSWithValueFee(t, signer, 1, uint256.Two(), uint256.Zero())
+	vsOut := makeVSWithValueFee(t, signer, 0, uint256.Two(), uint256.Zero())
+	txIn, err := vsIn.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedAssetID, err := (&Tx{Vin: Vin{txIn}}).IssuedAssetID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetA := crypto.Hasher([]byte("assetA"))
+	assetB := crypto.Hasher([]byte("assetB"))
+	tsIn := makeTSWithAmount(t, signer, 2, assetA, 100)
+
+	testCases := []struct {
+		name  string
+		vin   Vout
+		vout  Vout
+		valid bool
+	}{
+		{"issuance", Vout{vsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, issuedAssetID, 1000)}, true},
+		{"transfer", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetA, 100)}, true},
+		{"split", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetA, 60), makeTSWithAmount(t, signer, 0, assetA, 40)}, true},
+		{"issuance alongside transfer", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetA, 100), makeTSWithAmount(t, signer, 0, issuedAssetID, 1)}, true},
+		{"mint", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetA, 101)}, false},
+		{"burn", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetA, 99)}, false},
+		{"dropped", Vout{vsIn, tsIn}, Vout{vsOut}, false},
+		{"other asset", Vout{vsIn, tsIn}, Vout{vsOut, makeTSWithAmount(t, signer, 0, assetB, 100)}, false},
+		{"no native value", Vout{vsIn}, Vout{makeTSWithAmount(t, signer, 0, issuedAssetID, 2)}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vin := Vin{}
+			for _, utxo := range tc.vin {
+				txIn, err := utxo.MakeTxIn()
+				if err != nil {
+					t.Fatal(err)
+				}
+				vin = append(vin, txIn)
+			}
+			tx := &Tx{Vin: vin, Vout: tc.vout, Fee: uint256.Zero()}
+			err := tx.ValidateEqualVinVout(1, tc.vin)
+			if tc.valid && err != nil {
+				t.Fatalf("expected a valid tx: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an invalid tx")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package atomicswap
+
+import (
+	capnp "github.com/MadBase/go-capnproto2/v2"
+
+	mdefs "github.com/alicenet/alicenet/application/objs/capn"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// Marshal will marshal the AtomicSwap object.
+func Marshal(v mdefs.AtomicSwap) ([]byte, error) {
+	raw, err := capnp.Canonicalize(v.Struct)
+	if err != nil {
+		return nil, err
+	}
+	out := utils.CopySlice(raw)
+	return out, nil
+}
+
+// Unmarshal will unmarshal the AtomicSwap object.
+func Unmarshal(data []byte) (mdefs.AtomicSwap, error) {
+	var err error
+	fn := func() (mdefs.AtomicSwap, error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errorz.ErrInvalid{}.New("bad serialization")
+			}
+		}()
+		dataCopy := utils.CopySlice(data)
+		msg := &capnp.Message{Arena: capnp.SingleSegment(dataCopy)}
+		obj, tmp := mdefs.ReadRootAtomicSwap(msg)
+		err = tmp
+		return obj, err
+	}
+	obj, err := fn()
+	if err != nil {
+		return mdefs.AtomicSwap{}, err
+	}
+	return obj, nil
+}
+
+// Validate will validate the AtomicSwap object.
+func Validate(v mdefs.AtomicSwap) error {
+	if !v.HasASPreImage() {
+		return errorz.ErrInvalid{}.New("atomicswap capn obj does not have ASPreImage")
+	}
+	if !v.HasTxHash() {
+		return errorz.ErrInvalid{}.New("atomicswap capn obj does not have TxHash")
+	}
+	if len(v.TxHash()) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("atomicswap capn obj is not valid: invalid TxHash; incorrect byte length")
+	}
+	return nil
+}
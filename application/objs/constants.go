@@ -9,7 +9,35 @@ const (
 	// Signature Verification Algorithm used for ValueStore objects.
 	ValueStoreSVA SVA = 1
 
+	// HashedTimelockSVA is the constant which specifies the
+	// Signature Verification Algorithm used for AtomicSwap objects.
+	HashedTimelockSVA SVA = 2
+
 	// DataStoreSVA is the constant which specifies the
 	// Signature Verification Algorithm used for DataStore objects.
 	DataStoreSVA = 3
+
+	// MultiSigSVA is the constant which specifies the
+	// Signature Verification Algorithm used for ValueStore and DataStore
+	// objects owned by a MultiSigOwner.
+	MultiSigSVA SVA = 4
+
+	// TimeLockSVA is the constant which specifies the
+	// Signature Verification Algorithm used for ValueStore objects whose
+	// value is locked until an epoch or vests linearly.
+	TimeLockSVA SVA = 5
+)
+
+// SignerRole is the defined type utilized for designation of the owner
+// signing for an AtomicSwap object.
+type SignerRole uint8
+
+const (
+	// PrimarySignerRole designates the recipient of an AtomicSwap, who may
+	// consume the object before expiration by revealing the hash key.
+	PrimarySignerRole SignerRole = 1
+
+	// AlternateSignerRole designates the refunder of an AtomicSwap, who may
+	// consume the object once it has expired.
+	AlternateSignerRole SignerRole = 2
 )
@@ -1,6 +1,8 @@
 package objs
 
 import (
+	"bytes"
+
 	capnp "github.com/MadBase/go-capnproto2/v2"
 
 	mdefs "github.com/alicenet/alicenet/application/objs/capn"
@@ -233,6 +235,13 @@ func (b *DataStore) EpochOfExpiration() (uint32, error) {
 }
 
 // RemainingValue returns remaining value at the time of consumption.
+//
+// Before expiration this is the deposit of the epochs which are left,
+// including the 2 additional epochs of the deposit, so it is exactly the
+// deposit of a DataStore of the same data which is issued at currentHeight
+// and expires at the same epoch. A renewal or a transfer is funded by this
+// refund: the successor built by Renew or Transfer only costs the deposit of
+// the added epochs and its fee, see UpdateCost.
 func (b *DataStore) RemainingValue(currentHeight uint32) (*uint256.Uint256, error) {
 	if b == nil {
 		return nil, errorz.ErrInvalid{}.New("ds.remainingValue: ds not initialized")
@@ -450,3 +459,144 @@ func (b *DataStore) MakeTxIn() (*TXIn, error) {
 		},
 	}, nil
 }
+
+// Renew returns the DataStore which renews the datastore for numEpochs more
+// epochs when mined at currentHeight. The renewal is issued in the epoch of
+// currentHeight with the owner, index and data of the datastore; perEpochFee
+// is the current DataStore fee. TXOutIdx, TxHash and the presignature are set
+// once the transaction is built.
+func (b *DataStore) Renew(currentHeight, numEpochs uint32, perEpochFee *uint256.Uint256) (*DataStore, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ds.renew: ds not initialized")
+	}
+	if numEpochs == 0 {
+		return nil, errorz.ErrInvalid{}.New("ds.renew: numEpochs is zero")
+	}
+	owner, err := b.Owner()
+	if err != nil {
+		return nil, err
+	}
+	return b.successor(owner, currentHeight, numEpochs, perEpochFee)
+}
+
+// Transfer returns the DataStore which transfers the datastore to owner when
+// mined at currentHeight. The transfer expires numEpochs after the datastore;
+// numEpochs may be zero. The old owner signs the input consuming the
+// datastore and the new owner presigns the transfer.
+func (b *DataStore) Transfer(owner *DataStoreOwner, currentHeight, numEpochs uint32, perEpochFee *uint256.Uint256) (*DataStore, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ds.transfer: ds not initialized")
+	}
+	if err := owner.Validate(); err != nil {
+		return nil, err
+	}
+	oldOwner, err := b.Owner()
+	if err != nil {
+		return nil, err
+	}
+	oldOwnerBytes, err := oldOwner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ownerBytes, err := owner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(oldOwnerBytes, ownerBytes) {
+		return nil, errorz.ErrInvalid{}.New("ds.transfer: owner is unchanged")
+	}
+	return b.successor(owner, currentHeight, numEpochs, perEpochFee)
+}
+
+// UpdateCost returns the value a transaction mined at currentHeight must add
+// to the refund of the datastore to pay for successor, which is the deposit
+// of the added epochs and the fee of successor.
+func (b *DataStore) UpdateCost(successor *DataStore, currentHeight uint32) (*uint256.Uint256, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ds.updateCost: ds not initialized")
+	}
+	refund, err := b.RemainingValue(currentHeight)
+	if err != nil {
+		return nil, err
+	}
+	total, err := successor.ValuePlusFee()
+	if err != nil {
+		return nil, err
+	}
+	if total.Lt(refund) {
+		return nil, errorz.ErrInvalid{}.New("ds.updateCost: successor is worth less than the refund")
+	}
+	return new(uint256.Uint256).Sub(total, refund)
+}
+
+// successor returns the DataStore issued at currentHeight for owner which
+// keeps the index and data of the datastore and expires numEpochs after it.
+func (b *DataStore) successor(owner *DataStoreOwner, currentHeight, numEpochs uint32, perEpochFee *uint256.Uint256) (*DataStore, error) {
+	if perEpochFee == nil {
+		return nil, errorz.ErrInvalid{}.New("ds.successor: perEpochFee not initialized")
+	}
+	expired, err := b.IsExpired(currentHeight)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, errorz.ErrInvalid{}.New("ds.successor: ds is expired")
+	}
+	eoe, err := b.EpochOfExpiration()
+	if err != nil {
+		return nil, err
+	}
+	if eoe > constants.MaxUint32-numEpochs {
+		return nil, errorz.ErrInvalid{}.New("ds.successor: numEpochs is too large")
+	}
+	// EpochOfExpiration == IssuedAt + lifetime + 1; the datastore is not
+	// expired so eoe > epoch
+	epoch := utils.Epoch(currentHeight)
+	lifetime := eoe + numEpochs - epoch - 1
+	if lifetime == 0 {
+		return nil, errorz.ErrInvalid{}.New("ds.successor: ds expires at the next epoch; numEpochs must not be zero")
+	}
+	chainID, err := b.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	index, err := b.Index()
+	if err != nil {
+		return nil, err
+	}
+	rawData, err := b.RawData()
+	if err != nil {
+		return nil, err
+	}
+	deposit, err := BaseDepositEquation(uint32(len(rawData)), lifetime)
+	if err != nil {
+		return nil, err
+	}
+	totalEpochs, _ := new(uint256.Uint256).FromUint64(uint64(lifetime) + 2)
+	fee, err := new(uint256.Uint256).Mul(perEpochFee, totalEpochs)
+	if err != nil {
+		return nil, err
+	}
+	ownerBytes, err := owner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	newOwner := &DataStoreOwner{}
+	if err := newOwner.UnmarshalBinary(ownerBytes); err != nil {
+		return nil, err
+	}
+	ds := &DataStore{
+		DSLinker: &DSLinker{
+			DSPreImage: &DSPreImage{
+				ChainID:  chainID,
+				Index:    index,
+				IssuedAt: epoch,
+				Deposit:  deposit,
+				RawData:  rawData,
+				Owner:    newOwner,
+				Fee:      fee,
+			},
+		},
+	}
+	return ds, nil
+}
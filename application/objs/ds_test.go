@@ -10,6 +10,7 @@ import (
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
 	"github.com/alicenet/alicenet/dynamics/mocks"
+	"github.com/alicenet/alicenet/utils"
 )
 
 func MakeWrapperStorageMock() *wrapper.Storage {
@@ -19,7 +20,7 @@ func MakeWrapperStorageMock() *wrapper.Storage {
 	mocks.GetMaxBlockSizeFunc.SetDefaultReturn(0)
 	mocks.GetMaxProposalSizeFunc.SetDefaultReturn(0)
 	mocks.GetMinScaledTransactionFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(0))
-	return wrapper.NewStorage(mocks)
+	return wrapper.NewStorage(mocks, 1)
 }
 
 func MakeWrapperStorageMockWithValues(dataStoreFee int64, valueStoreFee int64, minTxFee int64, maxBlockSize uint32) *wrapper.Storage {
@@ -29,7 +30,7 @@ func MakeWrapperStorageMockWithValues(dataStoreFee int64, valueStoreFee int64, m
 	mocks.GetMinScaledTransactionFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(minTxFee))
 	mocks.GetMaxBlockSizeFunc.SetDefaultReturn(maxBlockSize)
 	mocks.GetMaxProposalSizeFunc.SetDefaultReturn(maxBlockSize)
-	return wrapper.NewStorage(mocks)
+	return wrapper.NewStorage(mocks, 1)
 }
 
 func makeSecpSigner(privk []byte) *crypto.Secp256k1Signer {
@@ -1200,3 +1201,176 @@ func TestDSValidateFee(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDSRenew(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	// issued at epoch e for 10 epochs; expires at epoch e+11
+	e := uint32(2048)
+	consumed := makeDSWithValueFee(t, signer, 1, rawData, index, e, 10, uint256.Zero())
+	ds, err := consumed.DataStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	perEpochFee, _ := new(uint256.Uint256).FromUint64(3)
+	height := (e+1)*constants.EpochLength + 1
+
+	if _, err := ds.Renew(height, 0, perEpochFee); err == nil {
+		t.Fatal("Should have raised error (zero epochs)")
+	}
+	if _, err := ds.Renew((e+10)*constants.EpochLength+1, 1, perEpochFee); err == nil {
+		t.Fatal("Should have raised error (expired)")
+	}
+
+	renewal, err := ds.Renew(height, 4, perEpochFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renewal.DSLinker.DSPreImage.ValidateDeposit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := renewal.ValidateFee(MakeWrapperStorageMockWithValues(3, 0, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	issuedAt, err := renewal.IssuedAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eoe, err := renewal.EpochOfExpiration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issuedAt != e+2 || eoe != e+15 {
+		t.Fatalf("bad renewal: issuedAt %v, epochOfExpiration %v", issuedAt, eoe)
+	}
+
+	// the renewal costs the deposit of the 4 added epochs and its fee
+	cost, err := ds.UpdateCost(renewal, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee, err := renewal.Fee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	epochCost := uint64(len(rawData) + constants.BaseDatasizeConst)
+	addedDeposit, _ := new(uint256.Uint256).FromUint64(4 * epochCost)
+	expected, _ := new(uint256.Uint256).Add(addedDeposit, fee)
+	if !cost.Eq(expected) {
+		t.Fatalf("bad cost: %v vs %v", cost, expected)
+	}
+
+	// a transaction funding the cost with a ValueStore balances
+	vs := &ValueStore{}
+	if err := vs.New(2, cost, uint256.Zero(), crypto.Hasher([]byte("acct"))[:constants.OwnerLen], constants.CurveSecp256k1, crypto.Hasher([]byte("vs"))); err != nil {
+		t.Fatal(err)
+	}
+	funding := &TXOut{}
+	if err := funding.NewValueStore(vs); err != nil {
+		t.Fatal(err)
+	}
+	dsTxIn, err := ds.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vsTxIn, err := vs.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo := &TXOut{}
+	if err := utxo.NewDataStore(renewal); err != nil {
+		t.Fatal(err)
+	}
+	tx := &Tx{Vin: Vin{dsTxIn, vsTxIn}, Vout: Vout{utxo}, Fee: uint256.Zero()}
+	refUTXOs := Vout{consumed, funding}
+	if err := tx.ValidateEqualVinVout(height, refUTXOs); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.ValidateDataStoreUpdates(height, refUTXOs, MakeWrapperStorageMock()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the deposit of the epochs which are left is credited to the renewal,
+	// so renewing later in the lifetime of the datastore costs the same
+	for _, later := range []uint32{(e+5)*constants.EpochLength + 1, (e+9)*constants.EpochLength + 1} {
+		refund, err := ds.RemainingValue(later)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leftEpochs := e + 11 - utils.Epoch(later)
+		leftDeposit, _ := new(uint256.Uint256).FromUint64(uint64(leftEpochs+1) * epochCost)
+		if !refund.Eq(leftDeposit) {
+			t.Fatalf("bad refund at height %v: %v vs %v", later, refund, leftDeposit)
+		}
+		renewal, err := ds.Renew(later, 4, perEpochFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cost, err := ds.UpdateCost(renewal, later)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fee, err := renewal.Fee()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := new(uint256.Uint256).Add(addedDeposit, fee)
+		if !cost.Eq(expected) {
+			t.Fatalf("bad cost at height %v: %v vs %v", later, cost, expected)
+		}
+	}
+}
+
+func TestDSTransfer(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	consumed := makeDSWithValueFee(t, signer, 1, rawData, index, 1, 10, uint256.Zero())
+	ds, err := consumed.DataStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldOwner, err := ds.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newOwner := &DataStoreOwner{}
+	newOwner.New(crypto.Hasher([]byte("b"))[:constants.OwnerLen], constants.CurveSecp256k1)
+	perEpochFee := uint256.Zero()
+	height := 2*constants.EpochLength + 1
+
+	if _, err := ds.Transfer(oldOwner, height, 0, perEpochFee); err == nil {
+		t.Fatal("Should have raised error (same owner)")
+	}
+	if _, err := ds.Transfer(newOwner, 10*constants.EpochLength+1, 0, perEpochFee); err == nil {
+		t.Fatal("Should have raised error (expires at the next epoch)")
+	}
+
+	transfer, err := ds.Transfer(newOwner, height, 0, perEpochFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eoe, err := transfer.EpochOfExpiration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eoe != 12 {
+		t.Fatalf("bad epochOfExpiration: %v", eoe)
+	}
+	owner, err := transfer.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(owner.Account, newOwner.Account) {
+		t.Fatal("bad owner")
+	}
+	// without a fee, a transfer keeping the expiration is paid by the refund
+	cost, err := ds.UpdateCost(transfer, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cost.IsZero() {
+		t.Fatalf("bad cost: %v", cost)
+	}
+}
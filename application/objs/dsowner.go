@@ -114,6 +114,16 @@ func (dso *DataStoreOwner) ValidateSignature(msg []byte, sig *DataStoreSignature
 	if !isExpired && sig.CurveSpec != dso.CurveSpec {
 		return errorz.ErrInvalid{}.New("dso.validateSignature; unmatched curve spec")
 	}
+	if !isExpired && (dso.SVA == MultiSigSVA || sig.SVA == MultiSigSVA) && dso.SVA != sig.SVA {
+		return errorz.ErrInvalid{}.New("dso.validateSignature; mismatched signature verification algorithm")
+	}
+	if sig.SVA == MultiSigSVA {
+		// once expired, any valid signature may collect the DataStore
+		if isExpired {
+			return validateMultiSig(msg, nil, sig.CurveSpec, sig.Signature)
+		}
+		return validateMultiSig(msg, dso.Account, dso.CurveSpec, sig.Signature)
+	}
 	switch sig.CurveSpec {
 	case constants.CurveSecp256k1:
 		val := crypto.Secp256k1Validator{}
@@ -160,7 +170,7 @@ func (dso *DataStoreOwner) validateSVA() error {
 	if dso == nil {
 		return errorz.ErrInvalid{}.New("dso.validateSVA; dso not initialized")
 	}
-	if dso.SVA != DataStoreSVA {
+	if dso.SVA != DataStoreSVA && dso.SVA != MultiSigSVA {
 		return errorz.ErrInvalid{}.New("dso.validateSVA; invalid signature verification algorithm")
 	}
 	return nil
@@ -226,6 +236,10 @@ func (dss *DataStoreSignature) UnmarshalBinary(signature []byte) error {
 		return err
 	}
 	dss.CurveSpec = curveSpec
+	if sva == MultiSigSVA {
+		dss.Signature = utils.CopySlice(signature)
+		return dss.Validate()
+	}
 	signature, null, err := extractSignature(signature, curveSpec)
 	if err != nil {
 		return err
@@ -261,6 +275,9 @@ func (dss *DataStoreSignature) Validate() error {
 	if err := dss.validateCurveSpec(); err != nil {
 		return err
 	}
+	if dss.SVA == MultiSigSVA {
+		return validateMultiSigLen(dss.Signature, dss.CurveSpec)
+	}
 	return validateSignatureLen(dss.Signature, dss.CurveSpec)
 }
 
@@ -268,7 +285,7 @@ func (dss *DataStoreSignature) validateSVA() error {
 	if dss == nil {
 		return errorz.ErrInvalid{}.New("dss.validateSVA; dss not initialized")
 	}
-	if dss.SVA != DataStoreSVA {
+	if dss.SVA != DataStoreSVA && dss.SVA != MultiSigSVA {
 		return errorz.ErrInvalid{}.New("dss.validateSVA; invalid signature verification algorithm")
 	}
 	return nil
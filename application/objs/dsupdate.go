@@ -0,0 +1,204 @@
+package objs
+
+import (
+	"bytes"
+
+	"github.com/alicenet/alicenet/application/wrapper"
+	"github.com/alicenet/alicenet/errorz"
+)
+
+// DataStoreUpdateKind is the kind of a DataStoreUpdate.
+type DataStoreUpdateKind uint8
+
+const (
+	// DataStoreRenewal extends the lifetime of a DataStore; the owner, the
+	// index and the data are kept.
+	DataStoreRenewal DataStoreUpdateKind = iota + 1
+	// DataStoreTransfer moves a DataStore to a new owner; the index and the
+	// data are kept.
+	DataStoreTransfer
+)
+
+// DataStoreUpdate pairs a DataStore consumed by a transaction with the
+// DataStore of the same transaction which continues it.
+//
+// A generated DataStore continues a consumed DataStore which is not expired
+// and which has the same index and data. A DataStore of the same owner is a
+// renewal; otherwise it is a transfer. Each consumed DataStore is continued
+// at most once.
+type DataStoreUpdate struct {
+	Kind      DataStoreUpdateKind
+	Consumed  *DataStore
+	Generated *DataStore
+}
+
+// DataStoreUpdates returns the renewals and transfers of DataStores performed
+// by the transaction when mined at currentHeight.
+func (b *Tx) DataStoreUpdates(currentHeight uint32, refUTXOs Vout) ([]*DataStoreUpdate, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("tx.dataStoreUpdates: tx not initialized")
+	}
+	consumed := []*DataStore{}
+	for _, utxo := range refUTXOs {
+		if !utxo.HasDataStore() {
+			continue
+		}
+		ds, err := utxo.DataStore()
+		if err != nil {
+			return nil, err
+		}
+		expired, err := ds.IsExpired(currentHeight)
+		if err != nil {
+			return nil, err
+		}
+		if !expired {
+			consumed = append(consumed, ds)
+		}
+	}
+	used := make([]bool, len(consumed))
+	updates := []*DataStoreUpdate{}
+	for _, utxo := range b.Vout {
+		if !utxo.HasDataStore() {
+			continue
+		}
+		ds, err := utxo.DataStore()
+		if err != nil {
+			return nil, err
+		}
+		match := -1
+		kind := DataStoreTransfer
+		for i, c := range consumed {
+			if used[i] {
+				continue
+			}
+			continues, sameOwner, err := continuesDataStore(c, ds)
+			if err != nil {
+				return nil, err
+			}
+			if !continues {
+				continue
+			}
+			if sameOwner {
+				match = i
+				kind = DataStoreRenewal
+				break
+			}
+			if match < 0 {
+				match = i
+			}
+		}
+		if match < 0 {
+			continue
+		}
+		used[match] = true
+		updates = append(updates, &DataStoreUpdate{
+			Kind:      kind,
+			Consumed:  consumed[match],
+			Generated: ds,
+		})
+	}
+	return updates, nil
+}
+
+// ValidateDataStoreUpdates validates the renewals and transfers of
+// DataStores performed by the transaction:
+//
+//	a renewal must expire after the renewed DataStore
+//	a transfer must not expire before the transferred DataStore
+//
+// Both the old and the new owner sign a transfer: the consumed DataStore is
+// not expired, so the input must be signed by its owner, and the generated
+// DataStore is presigned by the new owner.
+//
+// These rules only apply once the application fork of storage is active;
+// both the pending transaction pool and the validation of blocks go through
+// this check, so the pool never accepts a transaction which a block would
+// reject.
+func (b *Tx) ValidateDataStoreUpdates(currentHeight uint32, refUTXOs Vout, storage *wrapper.Storage) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("tx.validateDataStoreUpdates: tx not initialized")
+	}
+	minBH, err := b.CannotBeMinedUntil()
+	if err != nil {
+		return err
+	}
+	if minBH > currentHeight {
+		currentHeight = minBH
+	}
+	forked, err := storage.IsForked(currentHeight)
+	if err != nil {
+		return err
+	}
+	if !forked {
+		return nil
+	}
+	updates, err := b.DataStoreUpdates(currentHeight, refUTXOs)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		consumedEOE, err := update.Consumed.EpochOfExpiration()
+		if err != nil {
+			return err
+		}
+		generatedEOE, err := update.Generated.EpochOfExpiration()
+		if err != nil {
+			return err
+		}
+		switch update.Kind {
+		case DataStoreRenewal:
+			if generatedEOE <= consumedEOE {
+				return errorz.ErrInvalid{}.New("tx.validateDataStoreUpdates: renewal does not extend the datastore")
+			}
+		case DataStoreTransfer:
+			if generatedEOE < consumedEOE {
+				return errorz.ErrInvalid{}.New("tx.validateDataStoreUpdates: transfer shortens the datastore")
+			}
+		}
+	}
+	return nil
+}
+
+// continuesDataStore returns true if generated has the index and the data of
+// consumed, along with whether both have the same owner.
+func continuesDataStore(consumed, generated *DataStore) (bool, bool, error) {
+	consumedIndex, err := consumed.Index()
+	if err != nil {
+		return false, false, err
+	}
+	generatedIndex, err := generated.Index()
+	if err != nil {
+		return false, false, err
+	}
+	if !bytes.Equal(consumedIndex, generatedIndex) {
+		return false, false, nil
+	}
+	consumedData, err := consumed.RawData()
+	if err != nil {
+		return false, false, err
+	}
+	generatedData, err := generated.RawData()
+	if err != nil {
+		return false, false, err
+	}
+	if !bytes.Equal(consumedData, generatedData) {
+		return false, false, nil
+	}
+	consumedOwner, err := consumed.GenericOwner()
+	if err != nil {
+		return false, false, err
+	}
+	generatedOwner, err := generated.GenericOwner()
+	if err != nil {
+		return false, false, err
+	}
+	consumedOwnerBytes, err := consumedOwner.MarshalBinary()
+	if err != nil {
+		return false, false, err
+	}
+	generatedOwnerBytes, err := generatedOwner.MarshalBinary()
+	if err != nil {
+		return false, false, err
+	}
+	return true, bytes.Equal(consumedOwnerBytes, generatedOwnerBytes), nil
+}
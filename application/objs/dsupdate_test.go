@@ -0,0 +1,121 @@
+package objs
+
+import (
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/application/wrapper"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/dynamics/mocks"
+)
+
+func TestTxValidateDataStoreUpdates(t *testing.T) {
+	signerA := makeSecpSigner(crypto.Hasher([]byte("a")))
+	signerB := makeSecpSigner(crypto.Hasher([]byte("b")))
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	otherData := crypto.Hasher([]byte("otherData"))
+	fee := uint256.Zero()
+	// issued at the fork epoch e for 10 epochs; expires at epoch e+11
+	e := uint32(2048)
+	storage := wrapper.NewStorage(mocks.NewMockStorageGetter(), e)
+	consumed := makeDSWithValueFee(t, signerA, 1, rawData, index, e, 10, fee)
+	epoch3 := (e+1)*constants.EpochLength + 1
+	epoch12 := (e+10)*constants.EpochLength + 1
+
+	testCases := []struct {
+		name   string
+		height uint32
+		vout   *TXOut
+		kind   DataStoreUpdateKind
+		valid  bool
+	}{
+		{"renewal", epoch3, makeDSWithValueFee(t, signerA, 0, rawData, index, e+2, 12, fee), DataStoreRenewal, true},
+		{"renewal not extending", epoch3, makeDSWithValueFee(t, signerA, 0, rawData, index, e+2, 8, fee), DataStoreRenewal, false},
+		{"transfer", epoch3, makeDSWithValueFee(t, signerB, 0, rawData, index, e+2, 8, fee), DataStoreTransfer, true},
+		{"transfer extending", epoch3, makeDSWithValueFee(t, signerB, 0, rawData, index, e+2, 9, fee), DataStoreTransfer, true},
+		{"transfer shortening", epoch3, makeDSWithValueFee(t, signerB, 0, rawData, index, e+2, 7, fee), DataStoreTransfer, false},
+		{"new data", epoch3, makeDSWithValueFee(t, signerA, 0, otherData, index, e+2, 2, fee), 0, true},
+		{"expired", epoch12, makeDSWithValueFee(t, signerA, 0, rawData, index, e+11, 2, fee), 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := &Tx{Vout: Vout{tc.vout}, Fee: uint256.Zero()}
+			updates, err := tx.DataStoreUpdates(tc.height, Vout{consumed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.kind == 0 {
+				if len(updates) != 0 {
+					t.Fatalf("expected no update; got %v", updates)
+				}
+			} else if len(updates) != 1 || updates[0].Kind != tc.kind {
+				t.Fatalf("expected an update of kind %v; got %v", tc.kind, updates)
+			}
+			err = tx.ValidateDataStoreUpdates(tc.height, Vout{consumed}, storage)
+			if tc.valid && err != nil {
+				t.Fatalf("expected a valid update: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an invalid update")
+			}
+		})
+	}
+}
+
+func TestTxDataStoreUpdatesPrefersRenewal(t *testing.T) {
+	signerA := makeSecpSigner(crypto.Hasher([]byte("a")))
+	signerB := makeSecpSigner(crypto.Hasher([]byte("b")))
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	fee := uint256.Zero()
+	consumedB := makeDSWithValueFee(t, signerB, 1, rawData, index, 1, 10, fee)
+	consumedA := makeDSWithValueFee(t, signerA, 2, rawData, index, 1, 10, fee)
+	generated := makeDSWithValueFee(t, signerA, 0, rawData, index, 3, 12, fee)
+
+	tx := &Tx{Vout: Vout{generated}, Fee: uint256.Zero()}
+	updates, err := tx.DataStoreUpdates(2*constants.EpochLength+1, Vout{consumedB, consumedA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].Kind != DataStoreRenewal {
+		t.Fatalf("expected a renewal; got %v", updates)
+	}
+	dsA, err := consumedA.DataStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates[0].Consumed != dsA {
+		t.Fatal("the renewal does not consume the datastore of the owner")
+	}
+}
+
+func TestTxValidateDataStoreUpdatesActivation(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	fee := uint256.Zero()
+	e := uint32(2048)
+	storage := wrapper.NewStorage(mocks.NewMockStorageGetter(), e)
+	// issued 10 epochs before the fork for 20 epochs
+	consumed := makeDSWithValueFee(t, signer, 1, rawData, index, e-10, 20, fee)
+	// shortens the datastore by a single epoch
+	generated := makeDSWithValueFee(t, signer, 0, rawData, index, e-1, 10, fee)
+	tx := &Tx{Vout: Vout{generated}, Fee: uint256.Zero()}
+
+	before := (e-2)*constants.EpochLength + 1
+	if err := tx.ValidateDataStoreUpdates(before, Vout{consumed}, storage); err != nil {
+		t.Fatalf("updates should not be validated before the fork: %v", err)
+	}
+	after := (e-1)*constants.EpochLength + 1
+	if err := tx.ValidateDataStoreUpdates(after, Vout{consumed}, storage); err == nil {
+		t.Fatal("updates should be validated from the fork on")
+	}
+
+	// without a fork epoch the updates are never validated
+	disabled := wrapper.NewStorage(mocks.NewMockStorageGetter(), 0)
+	if err := tx.ValidateDataStoreUpdates(after, Vout{consumed}, disabled); err != nil {
+		t.Fatalf("updates should not be validated without a fork epoch: %v", err)
+	}
+}
@@ -0,0 +1,478 @@
+package objs
+
+import (
+	"bytes"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// MultiSigOwner specifies a set of public keys of which at least Threshold
+// must sign to consume an object. The object itself only stores the Account
+// of the MultiSigOwner; the MultiSigOwner is revealed in the signature.
+//
+// A Threshold of zero denotes a single bn256 group public key which is signed
+// by an aggregated group signature (see crypto.BNGroupSigner.Aggregate); the
+// threshold of the group is then enforced by the group signature itself.
+type MultiSigOwner struct {
+	CurveSpec  constants.CurveSpec
+	Threshold  uint8
+	PublicKeys [][]byte
+}
+
+// New makes a new M-of-N MultiSigOwner.
+func (mso *MultiSigOwner) New(curveSpec constants.CurveSpec, threshold uint8, pubks [][]byte) error {
+	if mso == nil {
+		return errorz.ErrInvalid{}.New("mso.new; mso not initialized")
+	}
+	if threshold == 0 {
+		return errorz.ErrInvalid{}.New("mso.new; threshold is zero")
+	}
+	mso.CurveSpec = curveSpec
+	mso.Threshold = threshold
+	mso.PublicKeys = make([][]byte, len(pubks))
+	for i := 0; i < len(pubks); i++ {
+		mso.PublicKeys[i] = utils.CopySlice(pubks[i])
+	}
+	return mso.Validate()
+}
+
+// NewGroup makes a new MultiSigOwner for the bn256 group public key
+// groupPubk.
+func (mso *MultiSigOwner) NewGroup(groupPubk []byte) error {
+	if mso == nil {
+		return errorz.ErrInvalid{}.New("mso.newGroup; mso not initialized")
+	}
+	mso.CurveSpec = constants.CurveBN256Eth
+	mso.Threshold = 0
+	mso.PublicKeys = [][]byte{utils.CopySlice(groupPubk)}
+	return mso.Validate()
+}
+
+// IsGroup returns true if the MultiSigOwner is a bn256 group public key.
+func (mso *MultiSigOwner) IsGroup() bool {
+	if mso == nil {
+		return false
+	}
+	return mso.Threshold == 0
+}
+
+// MarshalBinary takes the MultiSigOwner object and returns the canonical
+// byte slice.
+func (mso *MultiSigOwner) MarshalBinary() ([]byte, error) {
+	if err := mso.Validate(); err != nil {
+		return nil, err
+	}
+	owner := []byte{}
+	owner = append(owner, []byte{uint8(mso.CurveSpec)}...)
+	owner = append(owner, []byte{mso.Threshold}...)
+	owner = append(owner, []byte{uint8(len(mso.PublicKeys))}...)
+	for i := 0; i < len(mso.PublicKeys); i++ {
+		owner = append(owner, utils.CopySlice(mso.PublicKeys[i])...)
+	}
+	return owner, nil
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// MultiSigOwner object along with the remaining bytes.
+func (mso *MultiSigOwner) UnmarshalBinary(o []byte) ([]byte, error) {
+	if mso == nil {
+		return nil, errorz.ErrInvalid{}.New("mso.unmarshalBinary; mso not initialized")
+	}
+	owner := utils.CopySlice(o)
+	curveSpec, owner, err := extractCurveSpec(owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(owner) < 2 {
+		return nil, errorz.ErrInvalid{}.New("mso.unmarshalBinary; extraction failed")
+	}
+	threshold := owner[0]
+	numKeys := int(owner[1])
+	owner = owner[2:]
+	pubkLen, err := multiSigPubkeyLen(curveSpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(owner) < numKeys*pubkLen {
+		return nil, errorz.ErrInvalid{}.New("mso.unmarshalBinary; extraction failed")
+	}
+	pubks := make([][]byte, numKeys)
+	for i := 0; i < numKeys; i++ {
+		pubks[i] = utils.CopySlice(owner[i*pubkLen : (i+1)*pubkLen])
+	}
+	mso.CurveSpec = curveSpec
+	mso.Threshold = threshold
+	mso.PublicKeys = pubks
+	if err := mso.Validate(); err != nil {
+		return nil, err
+	}
+	return utils.CopySlice(owner[numKeys*pubkLen:]), nil
+}
+
+// Validate validates the MultiSigOwner object.
+func (mso *MultiSigOwner) Validate() error {
+	if mso == nil {
+		return errorz.ErrInvalid{}.New("mso.validate; mso not initialized")
+	}
+	pubkLen, err := multiSigPubkeyLen(mso.CurveSpec)
+	if err != nil {
+		return err
+	}
+	if mso.IsGroup() {
+		if mso.CurveSpec != constants.CurveBN256Eth {
+			return errorz.ErrInvalid{}.New("mso.validate; group public keys must use the bn256 curve")
+		}
+		if len(mso.PublicKeys) != 1 {
+			return errorz.ErrInvalid{}.New("mso.validate; group owner must have exactly one public key")
+		}
+	}
+	if len(mso.PublicKeys) == 0 || len(mso.PublicKeys) > constants.MaxMultiSigPublicKeys {
+		return errorz.ErrInvalid{}.New("mso.validate; invalid number of public keys")
+	}
+	if int(mso.Threshold) > len(mso.PublicKeys) {
+		return errorz.ErrInvalid{}.New("mso.validate; threshold is larger than the number of public keys")
+	}
+	keys := make(map[string]bool)
+	for i := 0; i < len(mso.PublicKeys); i++ {
+		if len(mso.PublicKeys[i]) != pubkLen {
+			return errorz.ErrInvalid{}.New("mso.validate; public key has incorrect length")
+		}
+		if keys[string(mso.PublicKeys[i])] {
+			return errorz.ErrInvalid{}.New("mso.validate; duplicate public key")
+		}
+		keys[string(mso.PublicKeys[i])] = true
+	}
+	return nil
+}
+
+// Account returns the account which commits to the MultiSigOwner.
+func (mso *MultiSigOwner) Account() ([]byte, error) {
+	owner, err := mso.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Hasher(owner)[constants.HashLen-constants.OwnerLen:], nil
+}
+
+// ValueStoreOwner returns the ValueStoreOwner for the MultiSigOwner.
+func (mso *MultiSigOwner) ValueStoreOwner() (*ValueStoreOwner, error) {
+	account, err := mso.Account()
+	if err != nil {
+		return nil, err
+	}
+	return &ValueStoreOwner{SVA: MultiSigSVA, CurveSpec: mso.CurveSpec, Account: account}, nil
+}
+
+// DataStoreOwner returns the DataStoreOwner for the MultiSigOwner.
+func (mso *MultiSigOwner) DataStoreOwner() (*DataStoreOwner, error) {
+	account, err := mso.Account()
+	if err != nil {
+		return nil, err
+	}
+	return &DataStoreOwner{SVA: MultiSigSVA, CurveSpec: mso.CurveSpec, Account: account}, nil
+}
+
+// Sign generates the partial signature of message msg with signer s; the
+// partial signatures of the owners are combined with Combine.
+func (mso *MultiSigOwner) Sign(msg []byte, s Signer) ([]byte, error) {
+	if err := mso.Validate(); err != nil {
+		return nil, err
+	}
+	switch s.(type) {
+	case *crypto.Secp256k1Signer:
+		if mso.CurveSpec != constants.CurveSecp256k1 {
+			return nil, errorz.ErrInvalid{}.New("mso.sign; mismatched curve spec")
+		}
+	case *crypto.BNSigner:
+		if mso.CurveSpec != constants.CurveBN256Eth || mso.IsGroup() {
+			return nil, errorz.ErrInvalid{}.New("mso.sign; mismatched curve spec")
+		}
+	default:
+		return nil, errorz.ErrInvalid{}.New("mso.sign; invalid signer type")
+	}
+	return s.Sign(msg)
+}
+
+// SignGroupShare generates the signature share of message msg with group
+// signer s; the shares are aggregated with crypto.BNGroupSigner.Aggregate
+// before being passed to Combine.
+func (mso *MultiSigOwner) SignGroupShare(msg []byte, s *crypto.BNGroupSigner) ([]byte, error) {
+	if err := mso.Validate(); err != nil {
+		return nil, err
+	}
+	if !mso.IsGroup() {
+		return nil, errorz.ErrInvalid{}.New("mso.signGroupShare; owner is not a group public key")
+	}
+	return s.Sign(msg)
+}
+
+// Combine combines the partial signatures of message msg into a
+// MultiSigSignature. For a group public key, sigs must be the single
+// aggregated group signature.
+func (mso *MultiSigOwner) Combine(msg []byte, sigs [][]byte) (*MultiSigSignature, error) {
+	if err := mso.Validate(); err != nil {
+		return nil, err
+	}
+	if mso.IsGroup() {
+		if len(sigs) != 1 {
+			return nil, errorz.ErrInvalid{}.New("mso.combine; group owner requires one aggregated signature")
+		}
+		mss := &MultiSigSignature{Owner: mso, Indices: []uint8{0}, Signatures: [][]byte{utils.CopySlice(sigs[0])}}
+		if err := mso.ValidateSignature(msg, mss); err != nil {
+			return nil, err
+		}
+		return mss, nil
+	}
+	signed := make([][]byte, len(mso.PublicKeys))
+	for i := 0; i < len(sigs); i++ {
+		pubk, err := mso.signer(msg, sigs[i])
+		if err != nil {
+			return nil, err
+		}
+		idx, err := mso.index(pubk)
+		if err != nil {
+			return nil, err
+		}
+		signed[idx] = utils.CopySlice(sigs[i])
+	}
+	mss := &MultiSigSignature{Owner: mso}
+	for i := 0; i < len(signed) && len(mss.Indices) < int(mso.Threshold); i++ {
+		if signed[i] == nil {
+			continue
+		}
+		mss.Indices = append(mss.Indices, uint8(i))
+		mss.Signatures = append(mss.Signatures, signed[i])
+	}
+	if len(mss.Indices) < int(mso.Threshold) {
+		return nil, errorz.ErrInvalid{}.New("mso.combine; not enough signatures")
+	}
+	return mss, nil
+}
+
+// ValidateSignature validates that MultiSigSignature sig for message msg
+// was generated by the MultiSigOwner; sig must be signed by at least
+// Threshold distinct public keys.
+func (mso *MultiSigOwner) ValidateSignature(msg []byte, sig *MultiSigSignature) error {
+	if err := mso.Validate(); err != nil {
+		return errorz.ErrInvalid{}.New("mso.validateSignature; invalid MultiSigOwner")
+	}
+	if err := sig.Validate(); err != nil {
+		return errorz.ErrInvalid{}.New("mso.validateSignature; invalid MultiSigSignature")
+	}
+	if mso.IsGroup() {
+		if len(sig.Indices) != 1 {
+			return errorz.ErrInvalid{}.New("mso.validateSignature; group owner requires one aggregated signature")
+		}
+		val := crypto.BNGroupValidator{}
+		pk, err := val.Validate(msg, sig.Signatures[0])
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pk, mso.PublicKeys[0]) {
+			return errorz.ErrInvalid{}.New("mso.validateSignature; invalid sig for group public key")
+		}
+		return nil
+	}
+	if len(sig.Indices) < int(mso.Threshold) {
+		return errorz.ErrInvalid{}.New("mso.validateSignature; not enough signatures")
+	}
+	for i := 0; i < len(sig.Indices); i++ {
+		idx := int(sig.Indices[i])
+		if idx >= len(mso.PublicKeys) {
+			return errorz.ErrInvalid{}.New("mso.validateSignature; invalid public key index")
+		}
+		pk, err := mso.signer(msg, sig.Signatures[i])
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pk, mso.PublicKeys[idx]) {
+			return errorz.ErrInvalid{}.New("mso.validateSignature; invalid sig for public key")
+		}
+	}
+	return nil
+}
+
+// signer returns the public key which generated signature sig.
+func (mso *MultiSigOwner) signer(msg []byte, sig []byte) ([]byte, error) {
+	switch mso.CurveSpec {
+	case constants.CurveSecp256k1:
+		val := crypto.Secp256k1Validator{}
+		return val.Validate(msg, sig)
+	case constants.CurveBN256Eth:
+		val := crypto.BNValidator{}
+		return val.Validate(msg, sig)
+	default:
+		return nil, errorz.ErrInvalid{}.New("mso.signer; invalid curve spec")
+	}
+}
+
+func (mso *MultiSigOwner) index(pubk []byte) (int, error) {
+	for i := 0; i < len(mso.PublicKeys); i++ {
+		if bytes.Equal(pubk, mso.PublicKeys[i]) {
+			return i, nil
+		}
+	}
+	return 0, errorz.ErrInvalid{}.New("mso.index; signer is not an owner")
+}
+
+// MultiSigSignature is the signature of a MultiSigOwner. It reveals the
+// MultiSigOwner and holds the signatures along with the index of the public
+// key of each signer; the indices are strictly increasing.
+type MultiSigSignature struct {
+	Owner      *MultiSigOwner
+	Indices    []uint8
+	Signatures [][]byte
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// MultiSigSignature object.
+func (mss *MultiSigSignature) UnmarshalBinary(signature []byte) error {
+	if mss == nil {
+		return errorz.ErrInvalid{}.New("mss.unmarshalBinary; mss not initialized")
+	}
+	owner := &MultiSigOwner{}
+	signature, err := owner.UnmarshalBinary(signature)
+	if err != nil {
+		return err
+	}
+	if len(signature) < 1 {
+		return errorz.ErrInvalid{}.New("mss.unmarshalBinary; extraction failed")
+	}
+	numSigs := int(signature[0])
+	signature = signature[1:]
+	indices := []uint8{}
+	sigs := [][]byte{}
+	for i := 0; i < numSigs; i++ {
+		if len(signature) < 1 {
+			return errorz.ErrInvalid{}.New("mss.unmarshalBinary; extraction failed")
+		}
+		indices = append(indices, signature[0])
+		sig, rest, err := extractSignature(signature[1:], owner.CurveSpec)
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, utils.CopySlice(sig))
+		signature = rest
+	}
+	if err := extractZero(signature); err != nil {
+		return err
+	}
+	mss.Owner = owner
+	mss.Indices = indices
+	mss.Signatures = sigs
+	return mss.Validate()
+}
+
+// MarshalBinary takes the MultiSigSignature object and returns the canonical
+// byte slice.
+func (mss *MultiSigSignature) MarshalBinary() ([]byte, error) {
+	if err := mss.Validate(); err != nil {
+		return nil, err
+	}
+	signature, err := mss.Owner.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signature = append(signature, []byte{uint8(len(mss.Indices))}...)
+	for i := 0; i < len(mss.Indices); i++ {
+		signature = append(signature, []byte{mss.Indices[i]}...)
+		signature = append(signature, utils.CopySlice(mss.Signatures[i])...)
+	}
+	return signature, nil
+}
+
+// Validate validates the MultiSigSignature object.
+func (mss *MultiSigSignature) Validate() error {
+	if mss == nil {
+		return errorz.ErrInvalid{}.New("mss.validate; mss not initialized")
+	}
+	if err := mss.Owner.Validate(); err != nil {
+		return err
+	}
+	if len(mss.Indices) == 0 || len(mss.Indices) != len(mss.Signatures) {
+		return errorz.ErrInvalid{}.New("mss.validate; invalid number of signatures")
+	}
+	for i := 0; i < len(mss.Indices); i++ {
+		if int(mss.Indices[i]) >= len(mss.Owner.PublicKeys) {
+			return errorz.ErrInvalid{}.New("mss.validate; invalid public key index")
+		}
+		if i > 0 && mss.Indices[i] <= mss.Indices[i-1] {
+			return errorz.ErrInvalid{}.New("mss.validate; public key indices must be strictly increasing")
+		}
+		if err := validateSignatureLen(mss.Signatures[i], mss.Owner.CurveSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValueStoreSignature returns the ValueStoreSignature for the
+// MultiSigSignature. The encoding of the ValueStoreSignature and the
+// DataStoreSignature of a MultiSigSignature is the same.
+func (mss *MultiSigSignature) ValueStoreSignature() (*ValueStoreSignature, error) {
+	signature, err := mss.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &ValueStoreSignature{SVA: MultiSigSVA, CurveSpec: mss.Owner.CurveSpec, Signature: signature}, nil
+}
+
+// DataStoreSignature returns the DataStoreSignature for the
+// MultiSigSignature.
+func (mss *MultiSigSignature) DataStoreSignature() (*DataStoreSignature, error) {
+	signature, err := mss.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &DataStoreSignature{SVA: MultiSigSVA, CurveSpec: mss.Owner.CurveSpec, Signature: signature}, nil
+}
+
+// validateMultiSig validates the encoded MultiSigSignature signature for
+// message msg. If account is not nil, the MultiSigOwner revealed in the
+// signature must commit to account.
+func validateMultiSig(msg []byte, account []byte, curveSpec constants.CurveSpec, signature []byte) error {
+	mss := &MultiSigSignature{}
+	if err := mss.UnmarshalBinary(signature); err != nil {
+		return err
+	}
+	if mss.Owner.CurveSpec != curveSpec {
+		return errorz.ErrInvalid{}.New("validateMultiSig; mismatched curve spec")
+	}
+	if account != nil {
+		msAccount, err := mss.Owner.Account()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(msAccount, account) {
+			return errorz.ErrInvalid{}.New("validateMultiSig; multisig owner does not match account")
+		}
+	}
+	return mss.Owner.ValidateSignature(msg, mss)
+}
+
+// validateMultiSigLen validates the encoding of the MultiSigSignature
+// signature.
+func validateMultiSigLen(signature []byte, curveSpec constants.CurveSpec) error {
+	mss := &MultiSigSignature{}
+	if err := mss.UnmarshalBinary(signature); err != nil {
+		return err
+	}
+	if mss.Owner.CurveSpec != curveSpec {
+		return errorz.ErrInvalid{}.New("validateMultiSigLen; mismatched curve spec")
+	}
+	return nil
+}
+
+func multiSigPubkeyLen(curveSpec constants.CurveSpec) (int, error) {
+	switch curveSpec {
+	case constants.CurveSecp256k1:
+		return constants.CurveSecp256k1PubkeyLen, nil
+	case constants.CurveBN256Eth:
+		return constants.CurveBN256EthPubkeyLen, nil
+	default:
+		return 0, errorz.ErrInvalid{}.New("multiSigPubkeyLen; invalid curveSpec")
+	}
+}
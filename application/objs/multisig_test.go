@@ -0,0 +1,437 @@
+package objs
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	bn256 "github.com/alicenet/alicenet/crypto/bn256/cloudflare"
+)
+
+func makeMultiSigSecpSigners(t *testing.T, n int) ([]*crypto.Secp256k1Signer, [][]byte) {
+	t.Helper()
+	signers := make([]*crypto.Secp256k1Signer, n)
+	pubks := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		signers[i] = makeSecpSigner(crypto.Hasher([]byte{byte(i), 's'}))
+		pubk, err := signers[i].Pubkey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubks[i] = pubk
+	}
+	return signers, pubks
+}
+
+func makeMultiSigBNSigners(t *testing.T, n int) ([]*crypto.BNSigner, [][]byte) {
+	t.Helper()
+	signers := make([]*crypto.BNSigner, n)
+	pubks := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		signers[i] = &crypto.BNSigner{}
+		if err := signers[i].SetPrivk(crypto.Hasher([]byte{byte(i), 'b'})); err != nil {
+			t.Fatal(err)
+		}
+		pubk, err := signers[i].Pubkey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubks[i] = pubk
+	}
+	return signers, pubks
+}
+
+func makeMultiSigOwner(t *testing.T) (*MultiSigOwner, []*crypto.Secp256k1Signer) {
+	t.Helper()
+	signers, pubks := makeMultiSigSecpSigners(t, 3)
+	mso := &MultiSigOwner{}
+	if err := mso.New(constants.CurveSecp256k1, 2, pubks); err != nil {
+		t.Fatal(err)
+	}
+	return mso, signers
+}
+
+func TestMultiSigOwnerMarshalBinary(t *testing.T) {
+	mso := &MultiSigOwner{}
+	if _, err := mso.MarshalBinary(); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	mso, _ = makeMultiSigOwner(t)
+	data, err := mso.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mso2 := &MultiSigOwner{}
+	rest, err := mso2.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Fatal("Should not have remaining bytes")
+	}
+	if mso2.CurveSpec != constants.CurveSecp256k1 || mso2.Threshold != 2 || len(mso2.PublicKeys) != 3 {
+		t.Fatal("owners do not match")
+	}
+	for i := 0; i < len(mso.PublicKeys); i++ {
+		if !bytes.Equal(mso.PublicKeys[i], mso2.PublicKeys[i]) {
+			t.Fatal("public keys do not match")
+		}
+	}
+	if _, err := mso2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+}
+
+func TestMultiSigOwnerValidate(t *testing.T) {
+	_, pubks := makeMultiSigSecpSigners(t, 3)
+	mso := &MultiSigOwner{}
+	if err := mso.New(constants.CurveSecp256k1, 0, pubks); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	if err := mso.New(constants.CurveSecp256k1, 4, pubks); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	if err := mso.New(constants.CurveBN256Eth, 2, pubks); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+	if err := mso.New(constants.CurveSecp256k1, 2, [][]byte{pubks[0], pubks[1], pubks[0]}); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+	tooMany := make([][]byte, constants.MaxMultiSigPublicKeys+1)
+	for i := 0; i < len(tooMany); i++ {
+		pubk, err := makeSecpSigner(crypto.Hasher([]byte{byte(i)})).Pubkey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tooMany[i] = pubk
+	}
+	if err := mso.New(constants.CurveSecp256k1, 2, tooMany); err == nil {
+		t.Fatal("Should raise an error (4)")
+	}
+	if err := mso.NewGroup(pubks[0]); err == nil {
+		t.Fatal("Should raise an error (5)")
+	}
+	if err := mso.New(constants.CurveSecp256k1, 2, pubks); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiSigSecp(t *testing.T) {
+	msg := crypto.Hasher([]byte("msg"))
+	mso, signers := makeMultiSigOwner(t)
+
+	sig0, err := mso.Sign(msg, signers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := mso.Sign(msg, signers[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mso.Combine(msg, [][]byte{sig2}); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	if _, err := mso.Combine(msg, [][]byte{sig2, sig2}); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	mss, err := mso.Combine(msg, [][]byte{sig2, sig0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mss.Indices[0] != 0 || mss.Indices[1] != 2 {
+		t.Fatal("indices are not sorted")
+	}
+	if err := mso.ValidateSignature(msg, mss); err != nil {
+		t.Fatal(err)
+	}
+	if err := mso.ValidateSignature(crypto.Hasher([]byte("wrong")), mss); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+
+	// a signature may not be counted twice
+	mss.Indices = []uint8{0, 0}
+	mss.Signatures = [][]byte{sig0, sig0}
+	if err := mso.ValidateSignature(msg, mss); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+
+	// signatures must match the public key of their index
+	mss.Indices = []uint8{0, 1}
+	mss.Signatures = [][]byte{sig0, sig2}
+	if err := mso.ValidateSignature(msg, mss); err == nil {
+		t.Fatal("Should raise an error (4)")
+	}
+
+	outsider := makeSecpSigner(crypto.Hasher([]byte("outsider")))
+	sig3, err := mso.Sign(msg, outsider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mso.Combine(msg, [][]byte{sig0, sig3}); err == nil {
+		t.Fatal("Should raise an error (5)")
+	}
+}
+
+func TestMultiSigBN(t *testing.T) {
+	msg := crypto.Hasher([]byte("msg"))
+	signers, pubks := makeMultiSigBNSigners(t, 3)
+	mso := &MultiSigOwner{}
+	if err := mso.New(constants.CurveBN256Eth, 2, pubks); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mso.Sign(msg, makeSecpSigner(crypto.Hasher([]byte("secp")))); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	sig1, err := mso.Sign(msg, signers[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := mso.Sign(msg, signers[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mss, err := mso.Combine(msg, [][]byte{sig1, sig2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := mss.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mss2 := &MultiSigSignature{}
+	if err := mss2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := mso.ValidateSignature(msg, mss2); err != nil {
+		t.Fatal(err)
+	}
+	if err := mss2.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+}
+
+func TestMultiSigGroup(t *testing.T) {
+	msg := crypto.Hasher([]byte("msg"))
+	msk := big.NewInt(1234)
+	coefs := []*big.Int{msk, big.NewInt(1), big.NewInt(2)}
+	groupPubk := new(bn256.G2).ScalarBaseMult(msk).Marshal()
+
+	mso := &MultiSigOwner{}
+	if err := mso.NewGroup(groupPubk); err != nil {
+		t.Fatal(err)
+	}
+	if !mso.IsGroup() {
+		t.Fatal("Should be a group owner")
+	}
+	groupShares := make([][]byte, 4)
+	shares := make([][]byte, 0, 3)
+	for i := 0; i < len(groupShares); i++ {
+		gsk := bn256.PrivatePolyEval(coefs, i+1)
+		groupShares[i] = new(bn256.G2).ScalarBaseMult(gsk).Marshal()
+		if i == 0 {
+			continue
+		}
+		s := &crypto.BNGroupSigner{}
+		if err := s.SetPrivk(gsk.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		share, err := mso.SignGroupShare(msg, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares = append(shares, share)
+	}
+	aggregator := &crypto.BNGroupSigner{}
+	if err := aggregator.SetGroupPubk(groupPubk); err != nil {
+		t.Fatal(err)
+	}
+	groupSig, err := aggregator.Aggregate(shares, groupShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mso.Combine(msg, [][]byte{shares[0]}); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	mss, err := mso.Combine(msg, [][]byte{groupSig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mso.ValidateSignature(msg, mss); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiSigValueStore(t *testing.T) {
+	mso, signers := makeMultiSigOwner(t)
+	vso, err := mso.ValueStoreOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := &ValueStore{}
+	if err := vs.New(1, uint256.One(), uint256.Zero(), vso.Account, constants.CurveSecp256k1, crypto.Hasher([]byte("txHash"))); err != nil {
+		t.Fatal(err)
+	}
+	vs.VSPreImage.Owner = vso
+	utxo := &TXOut{}
+	if err := utxo.NewValueStore(vs); err != nil {
+		t.Fatal(err)
+	}
+	data, err := utxo.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	onr, err := utxo.GenericOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msOnr := &Owner{}
+	if err := msOnr.NewFromMultiSigOwner(mso); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(onr.Account, msOnr.Account) {
+		t.Fatal("generic owner does not match")
+	}
+
+	txIn, err := utxo.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a single key of the owner may not spend the ValueStore
+	if err := vs.Sign(txIn, signers[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	msg, err := txIn.TXInLinker.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig0, err := mso.Sign(msg, signers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig1, err := mso.Sign(msg, signers[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mss, err := mso.Combine(msg, [][]byte{sig0, sig1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vss, err := mss.ValueStoreSignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txIn.Signature, err = vss.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err != nil {
+		t.Fatal(err)
+	}
+
+	// the signature must be made by the owner committed to by the account
+	other, otherSigners := makeMultiSigOwner(t)
+	other.Threshold = 1
+	sig, err := other.Sign(msg, otherSigners[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mss, err = other.Combine(msg, [][]byte{sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vss, err = mss.ValueStoreSignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txIn.Signature, err = vss.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+}
+
+func TestMultiSigDataStore(t *testing.T) {
+	mso, signers := makeMultiSigOwner(t)
+	dso, err := mso.DataStoreOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := makeDataStoreGood(crypto.Hasher([]byte("unused")))
+	ds.DSLinker.DSPreImage.Owner = dso
+	msg, err := ds.DSLinker.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a single key of the owner may not create the DataStore
+	if err := ds.PreSign(signers[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.ValidatePreSignature(); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+
+	sig1, err := mso.Sign(msg, signers[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := mso.Sign(msg, signers[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mss, err := mso.Combine(msg, [][]byte{sig1, sig2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Signature, err = mss.DataStoreSignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.ValidatePreSignature(); err != nil {
+		t.Fatal(err)
+	}
+	utxo := &TXOut{}
+	if err := utxo.NewDataStore(ds); err != nil {
+		t.Fatal(err)
+	}
+	data, err := utxo.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo2 := &TXOut{}
+	if err := utxo2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo2.ValidatePreSignature(); err != nil {
+		t.Fatal(err)
+	}
+
+	// once expired, any signer may collect the DataStore
+	txIn, err := utxo.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Sign(txIn, makeSecpSigner(crypto.Hasher([]byte("collector")))); err != nil {
+		t.Fatal(err)
+	}
+	expired := 100 * constants.EpochLength
+	if err := utxo.ValidateSignature(expired, txIn); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+}
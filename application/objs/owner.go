@@ -49,6 +49,30 @@ func (onr *Owner) NewFromValueStoreOwner(vso *ValueStoreOwner) error {
 	return onr.New(vso.Account, vso.CurveSpec)
 }
 
+// NewFromAtomicSwapSubOwner makes a new Owner from an AtomicSwapSubOwner.
+func (onr *Owner) NewFromAtomicSwapSubOwner(asso *AtomicSwapSubOwner) error {
+	if onr == nil {
+		return errorz.ErrInvalid{}.New("owner.newFromAtomicSwapSubOwner; owner not initialized")
+	}
+	if err := asso.Validate(); err != nil {
+		return err
+	}
+	return onr.New(asso.Account, asso.CurveSpec)
+}
+
+// NewFromMultiSigOwner makes a new Owner from a MultiSigOwner; the Account of
+// the Owner is the commitment to the MultiSigOwner.
+func (onr *Owner) NewFromMultiSigOwner(mso *MultiSigOwner) error {
+	if onr == nil {
+		return errorz.ErrInvalid{}.New("owner.newFromMultiSigOwner; owner not initialized")
+	}
+	account, err := mso.Account()
+	if err != nil {
+		return err
+	}
+	return onr.New(account, mso.CurveSpec)
+}
+
 // MarshalBinary takes the Owner object and returns the canonical
 // byte slice.
 func (onr *Owner) MarshalBinary() ([]byte, error) {
@@ -1,6 +1,111 @@
 package objs
 
+import (
+	"bytes"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+)
+
+// MaxDataStoreQueryLimit is the largest number of datastores returned by a
+// DataStoreQuery.
+const MaxDataStoreQueryLimit = 256
+
 type PaginationResponse struct {
 	UTXOID []byte
 	Index  []byte
+	// IssuedAt and EpochOfExpiration are only set by queries which include
+	// the metadata of the datastores.
+	IssuedAt          uint32
+	EpochOfExpiration uint32
+}
+
+// DataStoreQuery selects the datastores of an owner by index. Indexes are
+// compared lexicographically and an empty field does not restrict the query.
+type DataStoreQuery struct {
+	// Prefix selects the indexes which start with Prefix.
+	Prefix []byte
+	// Start is the inclusive lower bound of the indexes.
+	Start []byte
+	// End is the exclusive upper bound of the indexes.
+	End []byte
+	// Reverse returns the indexes in descending order.
+	Reverse bool
+	// After continues a query after the last index it returned; in reverse
+	// order the query continues below After.
+	After []byte
+	// Limit is the largest number of datastores returned.
+	Limit int
+}
+
+// Validate validates the DataStoreQuery object.
+func (q *DataStoreQuery) Validate() error {
+	if q == nil {
+		return errorz.ErrInvalid{}.New("dsq.validate; dsq not initialized")
+	}
+	if len(q.Prefix) > constants.HashLen {
+		return errorz.ErrInvalid{}.New("dsq.validate; prefix is too long")
+	}
+	if len(q.Start) > constants.HashLen || len(q.End) > constants.HashLen {
+		return errorz.ErrInvalid{}.New("dsq.validate; bound is too long")
+	}
+	if len(q.Start) > 0 && len(q.End) > 0 && bytes.Compare(q.Start, q.End) >= 0 {
+		return errorz.ErrInvalid{}.New("dsq.validate; start is not below end")
+	}
+	if len(q.After) != 0 && len(q.After) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("dsq.validate; after has invalid length")
+	}
+	if q.Limit < 1 || q.Limit > MaxDataStoreQueryLimit {
+		return errorz.ErrInvalid{}.New("dsq.validate; invalid limit")
+	}
+	return nil
+}
+
+// Matches returns true if the query selects index, including the
+// continuation of After.
+func (q *DataStoreQuery) Matches(index []byte) bool {
+	if !bytes.HasPrefix(index, q.Prefix) {
+		return false
+	}
+	if len(q.Start) > 0 && bytes.Compare(index, q.Start) < 0 {
+		return false
+	}
+	if len(q.End) > 0 && bytes.Compare(index, q.End) >= 0 {
+		return false
+	}
+	if len(q.After) > 0 {
+		cmp := bytes.Compare(index, q.After)
+		if q.Reverse {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+	return true
+}
+
+// SeekIndex returns the index the iteration of the query starts from: the
+// smallest index which may match or, in reverse order, an upper bound of the
+// largest one.
+func (q *DataStoreQuery) SeekIndex() []byte {
+	if !q.Reverse {
+		seek := q.Prefix
+		for _, bound := range [][]byte{q.Start, q.After} {
+			if bytes.Compare(bound, seek) > 0 {
+				seek = bound
+			}
+		}
+		return append([]byte{}, seek...)
+	}
+	// every index with the prefix sorts before the prefix padded with 0xff
+	// beyond the length of an index
+	seek := append([]byte{}, q.Prefix...)
+	for len(seek) <= constants.HashLen {
+		seek = append(seek, 0xff)
+	}
+	for _, bound := range [][]byte{q.End, q.After} {
+		if len(bound) > 0 && bytes.Compare(bound, seek) < 0 {
+			seek = bound
+		}
+	}
+	return append([]byte{}, seek...)
 }
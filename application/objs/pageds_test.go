@@ -0,0 +1,60 @@
+package objs
+
+import (
+	"testing"
+
+	"github.com/alicenet/alicenet/constants"
+)
+
+func TestDataStoreQueryValidate(t *testing.T) {
+	long := make([]byte, constants.HashLen+1)
+	testCases := []struct {
+		name  string
+		q     *DataStoreQuery
+		valid bool
+	}{
+		{"nil", nil, false},
+		{"empty", &DataStoreQuery{Limit: 1}, true},
+		{"zero limit", &DataStoreQuery{}, false},
+		{"large limit", &DataStoreQuery{Limit: MaxDataStoreQueryLimit + 1}, false},
+		{"long prefix", &DataStoreQuery{Prefix: long, Limit: 1}, false},
+		{"long bound", &DataStoreQuery{End: long, Limit: 1}, false},
+		{"empty range", &DataStoreQuery{Start: []byte{2}, End: []byte{2}, Limit: 1}, false},
+		{"range", &DataStoreQuery{Start: []byte{1}, End: []byte{2}, Limit: 1}, true},
+		{"short after", &DataStoreQuery{After: []byte{2}, Limit: 1}, false},
+		{"after", &DataStoreQuery{After: make([]byte, constants.HashLen), Limit: 1}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.q.Validate()
+			if tc.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("Should have raised error")
+			}
+		})
+	}
+}
+
+func TestDataStoreQuerySeekIndex(t *testing.T) {
+	after := make([]byte, constants.HashLen)
+	after[0] = 3
+	q := &DataStoreQuery{Prefix: []byte{3}, Start: []byte{2, 5}, After: after, Limit: 1}
+	if seek := q.SeekIndex(); string(seek) != string(after) {
+		t.Fatalf("bad seek index: %x", seek)
+	}
+	q = &DataStoreQuery{Prefix: []byte{3}, Start: []byte{3, 5}, Limit: 1}
+	if seek := q.SeekIndex(); string(seek) != string([]byte{3, 5}) {
+		t.Fatalf("bad seek index: %x", seek)
+	}
+	q = &DataStoreQuery{Prefix: []byte{3}, Reverse: true, Limit: 1}
+	seek := q.SeekIndex()
+	if len(seek) != constants.HashLen+1 || seek[0] != 3 || seek[1] != 0xff {
+		t.Fatalf("bad seek index: %x", seek)
+	}
+	q = &DataStoreQuery{Prefix: []byte{3}, End: []byte{3, 1}, Reverse: true, Limit: 1}
+	if seek := q.SeekIndex(); string(seek) != string([]byte{3, 1}) {
+		t.Fatalf("bad seek index: %x", seek)
+	}
+}
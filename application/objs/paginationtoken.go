@@ -18,6 +18,9 @@ type LastPaginatedType byte
 const (
 	LastPaginatedUtxo LastPaginatedType = iota
 	LastPaginatedDeposit
+	LastPaginatedAtomicSwapRefund
+	LastPaginatedUnlockedValue
+	LastPaginatedAsset
 )
 
 // UnmarshalBinary takes a byte slice and returns the corresponding
@@ -27,7 +30,7 @@ func (pt *PaginationToken) UnmarshalBinary(data []byte) error {
 		return errorz.ErrInvalid{}.New("pt.unmarshalBinary; pt not initialized")
 	}
 
-	if data == nil || len(data) < 65 || data[0] > 1 {
+	if data == nil || len(data) < 65 || data[0] > byte(LastPaginatedAsset) {
 		return errorz.ErrInvalid{}.New("pt.unmarshalBinary; bytes invalid")
 	}
 
@@ -27,7 +27,7 @@ func TestUnMarshalInvalid(t *testing.T) {
 	}
 
 	b := make([]byte, 65)
-	b[0] = 2
+	b[0] = byte(LastPaginatedAsset) + 1
 
 	if err := p.UnmarshalBinary(b); err == nil {
 		t.Fatal("Should raise an error when called with invalid LastPaginatedType")
@@ -0,0 +1,56 @@
+package tokenstore
+
+import (
+	capnp "github.com/MadBase/go-capnproto2/v2"
+
+	mdefs "github.com/alicenet/alicenet/application/objs/capn"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// Marshal will marshal the TokenStore object.
+func Marshal(v mdefs.TokenStore) ([]byte, error) {
+	raw, err := capnp.Canonicalize(v.Struct)
+	if err != nil {
+		return nil, err
+	}
+	out := utils.CopySlice(raw)
+	return out, nil
+}
+
+// Unmarshal will unmarshal the TokenStore object.
+func Unmarshal(data []byte) (mdefs.TokenStore, error) {
+	var err error
+	fn := func() (mdefs.TokenStore, error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errorz.ErrInvalid{}.New("bad serialization")
+			}
+		}()
+		dataCopy := utils.CopySlice(data)
+		msg := &capnp.Message{Arena: capnp.SingleSegment(dataCopy)}
+		obj, tmp := mdefs.ReadRootTokenStore(msg)
+		err = tmp
+		return obj, err
+	}
+	obj, err := fn()
+	if err != nil {
+		return mdefs.TokenStore{}, err
+	}
+	return obj, nil
+}
+
+// Validate will validate the TokenStore object.
+func Validate(v mdefs.TokenStore) error {
+	if !v.HasTSPreImage() {
+		return errorz.ErrInvalid{}.New("tokenstore capn obj does not have TSPreImage")
+	}
+	if !v.HasTxHash() {
+		return errorz.ErrInvalid{}.New("tokenstore capn obj does not have TxHash")
+	}
+	if len(v.TxHash()) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("tokenstore capn obj is not valid: invalid TxHash; incorrect byte length")
+	}
+	return nil
+}
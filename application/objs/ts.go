@@ -0,0 +1,389 @@
+package objs
+
+import (
+	capnp "github.com/MadBase/go-capnproto2/v2"
+
+	mdefs "github.com/alicenet/alicenet/application/objs/capn"
+	"github.com/alicenet/alicenet/application/objs/tokenstore"
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/application/wrapper"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// TokenStore stores an amount of a fungible asset in a UTXO. The asset is
+// distinct from the native value; a TokenStore carries no native value and
+// only pays the ValueStore fee at the time of creation.
+type TokenStore struct {
+	TSPreImage *TSPreImage
+	TxHash     []byte
+	//
+	utxoID []byte
+}
+
+// New creates a new TokenStore.
+func (b *TokenStore) New(chainID uint32, assetID []byte, amount, fee *uint256.Uint256, acct []byte, curveSpec constants.CurveSpec, txHash []byte) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("ts.new: ts not initialized")
+	}
+	if len(assetID) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("ts.new: invalid assetID; incorrect assetID length")
+	}
+	if amount == nil {
+		return errorz.ErrInvalid{}.New("ts.new: amount is nil")
+	}
+	if amount.IsZero() {
+		return errorz.ErrInvalid{}.New("ts.new: amount is zero")
+	}
+	if fee == nil {
+		return errorz.ErrInvalid{}.New("ts.new: fee is nil")
+	}
+	vsowner := &ValueStoreOwner{}
+	vsowner.New(acct, curveSpec)
+	if err := vsowner.Validate(); err != nil {
+		return err
+	}
+	if chainID == 0 {
+		return errorz.ErrInvalid{}.New("ts.new: chainID is zero")
+	}
+	if len(txHash) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("ts.new: invalid txHash; incorrect txhash length")
+	}
+	tsp := &TSPreImage{
+		ChainID: chainID,
+		AssetID: utils.CopySlice(assetID),
+		Amount:  amount.Clone(),
+		Owner:   vsowner,
+		Fee:     fee.Clone(),
+	}
+	b.TSPreImage = tsp
+	b.TxHash = utils.CopySlice(txHash)
+	return nil
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// TokenStore object.
+func (b *TokenStore) UnmarshalBinary(data []byte) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("ts.unmarshalBinary: ts not initialized")
+	}
+	bc, err := tokenstore.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return b.UnmarshalCapn(bc)
+}
+
+// MarshalBinary takes the TokenStore object and returns the canonical
+// byte slice.
+func (b *TokenStore) MarshalBinary() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.marshalBinary: ts not initialized")
+	}
+	bc, err := b.MarshalCapn(nil)
+	if err != nil {
+		return nil, err
+	}
+	return tokenstore.Marshal(bc)
+}
+
+// UnmarshalCapn unmarshals the capnproto definition of the object.
+func (b *TokenStore) UnmarshalCapn(bc mdefs.TokenStore) error {
+	if err := tokenstore.Validate(bc); err != nil {
+		return err
+	}
+	b.TSPreImage = &TSPreImage{}
+	if err := b.TSPreImage.UnmarshalCapn(bc.TSPreImage()); err != nil {
+		return err
+	}
+	b.TxHash = utils.CopySlice(bc.TxHash())
+	return nil
+}
+
+// MarshalCapn marshals the object into its capnproto definition.
+func (b *TokenStore) MarshalCapn(seg *capnp.Segment) (mdefs.TokenStore, error) {
+	if b == nil {
+		return mdefs.TokenStore{}, errorz.ErrInvalid{}.New("ts.marshalCapn: ts not initialized")
+	}
+	var bc mdefs.TokenStore
+	if seg == nil {
+		_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+		if err != nil {
+			return bc, err
+		}
+		tmp, err := mdefs.NewRootTokenStore(seg)
+		if err != nil {
+			return bc, err
+		}
+		bc = tmp
+	} else {
+		tmp, err := mdefs.NewTokenStore(seg)
+		if err != nil {
+			return bc, err
+		}
+		bc = tmp
+	}
+	seg = bc.Struct.Segment()
+	bt, err := b.TSPreImage.MarshalCapn(seg)
+	if err != nil {
+		return bc, err
+	}
+	if err := bc.SetTSPreImage(bt); err != nil {
+		return bc, err
+	}
+	if err := bc.SetTxHash(utils.CopySlice(b.TxHash)); err != nil {
+		return bc, err
+	}
+	return bc, nil
+}
+
+// PreHash calculates the PreHash of the object.
+func (b *TokenStore) PreHash() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.preHash: ts not initialized")
+	}
+	return b.TSPreImage.PreHash()
+}
+
+// UTXOID calculates the UTXOID of the object.
+func (b *TokenStore) UTXOID() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.utxoID: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.utxoID: tspi not initialized")
+	}
+	if len(b.TxHash) != constants.HashLen {
+		return nil, errorz.ErrInvalid{}.New("ts.utxoID: ts.txhash has incorrect length")
+	}
+	if b.utxoID != nil {
+		return utils.CopySlice(b.utxoID), nil
+	}
+	b.utxoID = MakeUTXOID(b.TxHash, b.TSPreImage.TXOutIdx)
+	return utils.CopySlice(b.utxoID), nil
+}
+
+// TxOutIdx returns the TxOutIdx of the object.
+func (b *TokenStore) TxOutIdx() (uint32, error) {
+	if b == nil {
+		return 0, errorz.ErrInvalid{}.New("ts.txOutIdx: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return 0, errorz.ErrInvalid{}.New("ts.txOutIdx: tspi not initialized")
+	}
+	return b.TSPreImage.TXOutIdx, nil
+}
+
+// SetTxOutIdx sets the TxOutIdx of the object.
+func (b *TokenStore) SetTxOutIdx(idx uint32) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("ts.setTxOutIdx: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return errorz.ErrInvalid{}.New("ts.setTxOutIdx: tspi not initialized")
+	}
+	b.TSPreImage.TXOutIdx = idx
+	return nil
+}
+
+// SetTxHash sets the TxHash of the object.
+func (b *TokenStore) SetTxHash(txHash []byte) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("ts.setTxHash: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return errorz.ErrInvalid{}.New("ts.setTxHash: tspi not initialized")
+	}
+	if len(txHash) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("ts.setTxHash: invalid hash length")
+	}
+	b.TxHash = utils.CopySlice(txHash)
+	return nil
+}
+
+// ChainID returns the ChainID of the object.
+func (b *TokenStore) ChainID() (uint32, error) {
+	if b == nil {
+		return 0, errorz.ErrInvalid{}.New("ts.chainID: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return 0, errorz.ErrInvalid{}.New("ts.chainID: tspi not initialized")
+	}
+	if b.TSPreImage.ChainID == 0 {
+		return 0, errorz.ErrInvalid{}.New("ts.chainID: chainID is zero")
+	}
+	return b.TSPreImage.ChainID, nil
+}
+
+// AssetID returns the AssetID of the object.
+func (b *TokenStore) AssetID() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.assetID: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.assetID: tspi not initialized")
+	}
+	if len(b.TSPreImage.AssetID) != constants.HashLen {
+		return nil, errorz.ErrInvalid{}.New("ts.assetID: tspi.assetID has incorrect length")
+	}
+	return utils.CopySlice(b.TSPreImage.AssetID), nil
+}
+
+// Amount returns the amount of the asset stored in the object.
+func (b *TokenStore) Amount() (*uint256.Uint256, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.amount: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.amount: tspi not initialized")
+	}
+	if b.TSPreImage.Amount == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.amount: tspi.amount not initialized")
+	}
+	if b.TSPreImage.Amount.IsZero() {
+		return nil, errorz.ErrInvalid{}.New("ts.amount: tspi.amount is zero")
+	}
+	return b.TSPreImage.Amount.Clone(), nil
+}
+
+// Value returns the native value of the object, which is always zero.
+func (b *TokenStore) Value() (*uint256.Uint256, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.value: ts not initialized")
+	}
+	return uint256.Zero(), nil
+}
+
+// Fee returns the Fee of the object.
+func (b *TokenStore) Fee() (*uint256.Uint256, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.fee: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.fee: tspi not initialized")
+	}
+	if b.TSPreImage.Fee == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.fee: tspi.fee not initialized")
+	}
+	return b.TSPreImage.Fee.Clone(), nil
+}
+
+// ValuePlusFee returns the native value of the object with the associated
+// fee; as a TokenStore carries no native value, this is the fee.
+func (b *TokenStore) ValuePlusFee() (*uint256.Uint256, error) {
+	return b.Fee()
+}
+
+// Owner returns the ValueStoreOwner of the TokenStore.
+func (b *TokenStore) Owner() (*ValueStoreOwner, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.owner: ts not initialized")
+	}
+	if b.TSPreImage == nil {
+		return nil, errorz.ErrInvalid{}.New("ts.owner: tspi not initialized")
+	}
+	if err := b.TSPreImage.Owner.Validate(); err != nil {
+		return nil, errorz.ErrInvalid{}.New("ts.owner: ValueStoreOwner invalid")
+	}
+	return b.TSPreImage.Owner, nil
+}
+
+// GenericOwner returns the Owner of the TokenStore.
+func (b *TokenStore) GenericOwner() (*Owner, error) {
+	vso, err := b.Owner()
+	if err != nil {
+		return nil, err
+	}
+	onr := &Owner{}
+	if err := onr.NewFromValueStoreOwner(vso); err != nil {
+		return nil, err
+	}
+	return onr, nil
+}
+
+// Sign generates the signature for a TokenStore at the time of consumption.
+func (b *TokenStore) Sign(txIn *TXIn, s Signer) error {
+	if txIn == nil {
+		return errorz.ErrInvalid{}.New("ts.sign: txin not initialized")
+	}
+	msg, err := txIn.TXInLinker.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	owner, err := b.Owner()
+	if err != nil {
+		return err
+	}
+	sig, err := owner.Sign(msg, s)
+	if err != nil {
+		return err
+	}
+	sigb, err := sig.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	txIn.Signature = sigb
+	return nil
+}
+
+// ValidateFee validates the fee of the object at the time of creation; a
+// TokenStore pays the same fee as a ValueStore.
+func (b *TokenStore) ValidateFee(storage *wrapper.Storage) error {
+	fee, err := b.Fee()
+	if err != nil {
+		return err
+	}
+	feeTrue, err := storage.GetValueStoreFee()
+	if err != nil {
+		return err
+	}
+	if fee.Cmp(feeTrue) != 0 {
+		return errorz.ErrInvalid{}.New("ts.validateFee: invalid fee")
+	}
+	return nil
+}
+
+// ValidateSignature validates the signature of the TokenStore at the time of
+// consumption.
+func (b *TokenStore) ValidateSignature(txIn *TXIn) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("ts.validateSignature: ts not initialized")
+	}
+	if txIn == nil {
+		return errorz.ErrInvalid{}.New("ts.validateSignature: txin not initialized")
+	}
+	msg, err := txIn.TXInLinker.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	sig := &ValueStoreSignature{}
+	if err := sig.UnmarshalBinary(txIn.Signature); err != nil {
+		return err
+	}
+	return b.TSPreImage.ValidateSignature(msg, sig)
+}
+
+// MakeTxIn constructs a TXIn object for the current object.
+func (b *TokenStore) MakeTxIn() (*TXIn, error) {
+	txOutIdx, err := b.TxOutIdx()
+	if err != nil {
+		return nil, err
+	}
+	cid, err := b.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	if len(b.TxHash) != constants.HashLen {
+		return nil, errorz.ErrInvalid{}.New("ts.makeTxIn: invalid TxHash")
+	}
+	return &TXIn{
+		TXInLinker: &TXInLinker{
+			TXInPreImage: &TXInPreImage{
+				ConsumedTxIdx:  txOutIdx,
+				ConsumedTxHash: utils.CopySlice(b.TxHash),
+				ChainID:        cid,
+			},
+		},
+	}, nil
+}
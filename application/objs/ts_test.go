@@ -0,0 +1,148 @@
+package objs
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+func makeTSWithAmount(t *testing.T, ownerSigner Signer, i int, assetID []byte, amount uint64) *TXOut {
+	t.Helper()
+	ownerPubk, err := ownerSigner.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := new(uint256.Uint256).FromUint64(amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash := crypto.Hasher([]byte(strconv.Itoa(i)))
+	utxo := &TXOut{}
+	err = utxo.CreateTokenStore(2, assetID, a, uint256.Zero(), crypto.GetAccount(ownerPubk), constants.CurveSecp256k1, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return utxo
+}
+
+func TestTokenStoreNew(t *testing.T) {
+	acct := crypto.GetAccount(crypto.Hasher([]byte("owner")))
+	assetID := crypto.Hasher([]byte("asset"))
+	txHash := crypto.Hasher([]byte("txHash"))
+	ts := &TokenStore{}
+	if err := ts.New(2, assetID[1:], uint256.Two(), uint256.One(), acct, constants.CurveSecp256k1, txHash); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	if err := ts.New(2, assetID, uint256.Zero(), uint256.One(), acct, constants.CurveSecp256k1, txHash); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	if err := ts.New(2, assetID, uint256.Two(), nil, acct, constants.CurveSecp256k1, txHash); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+	if err := ts.New(0, assetID, uint256.Two(), uint256.One(), acct, constants.CurveSecp256k1, txHash); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+	if err := ts.New(2, assetID, uint256.Two(), uint256.One(), acct, constants.CurveSecp256k1, txHash[1:]); err == nil {
+		t.Fatal("Should raise an error (4)")
+	}
+	if err := ts.New(2, assetID, uint256.Two(), uint256.One(), acct, constants.CurveSecp256k1, txHash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenStoreMarshalBinary(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	assetID := crypto.Hasher([]byte("asset"))
+	utxo := makeTSWithAmount(t, signer, 1, assetID, 25519)
+	if err := utxo.SetTxOutIdx(1); err != nil {
+		t.Fatal(err)
+	}
+	data, err := utxo.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo2 := &TXOut{}
+	if err := utxo2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !utxo2.HasTokenStore() || utxo2.HasValueStore() || utxo2.HasDataStore() || utxo2.HasAtomicSwap() {
+		t.Fatal("Should have a TokenStore")
+	}
+	ts, err := utxo.TokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts2, err := utxo2.TokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts2.TSPreImage.ChainID != 2 || ts2.TSPreImage.TXOutIdx != 1 {
+		t.Fatal("preimages do not match")
+	}
+	if !bytes.Equal(ts2.TSPreImage.AssetID, assetID) || ts2.TSPreImage.Amount.Cmp(ts.TSPreImage.Amount) != 0 {
+		t.Fatal("assets do not match")
+	}
+	if !bytes.Equal(ts.TxHash, ts2.TxHash) {
+		t.Fatal("txHashes do not match")
+	}
+	// a TokenStore carries no native value
+	value, err := utxo2.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !value.IsZero() {
+		t.Fatal("Should have no value")
+	}
+}
+
+func TestTokenStoreLockedOwner(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	utxo := makeTSWithAmount(t, signer, 1, crypto.Hasher([]byte("asset")), 1)
+	ts, err := utxo.TokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &ValueStoreOwner{}
+	if err := owner.NewLocked(ts.TSPreImage.Owner.Account, constants.CurveSecp256k1, &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 2}); err != nil {
+		t.Fatal(err)
+	}
+	ts.TSPreImage.Owner = owner
+	data, err := ts.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(TokenStore).UnmarshalBinary(data); err == nil {
+		t.Fatal("Should raise an error for a time locked owner")
+	}
+}
+
+func TestTokenStoreValidateSignature(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	other := makeSecpSigner(crypto.Hasher([]byte("b")))
+	utxo := makeTSWithAmount(t, signer, 1, crypto.Hasher([]byte("asset")), 1)
+	ts, err := utxo.TokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txIn, err := utxo.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txIn.TXInLinker.TxHash = crypto.Hasher([]byte("spender"))
+	if err := ts.Sign(txIn, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err == nil {
+		t.Fatal("Should raise an error for a signature of another account")
+	}
+	if err := ts.Sign(txIn, signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := utxo.ValidateSignature(1, txIn); err != nil {
+		t.Fatal(err)
+	}
+}
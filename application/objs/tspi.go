@@ -0,0 +1,186 @@
+package objs
+
+import (
+	capnp "github.com/MadBase/go-capnproto2/v2"
+
+	mdefs "github.com/alicenet/alicenet/application/objs/capn"
+	"github.com/alicenet/alicenet/application/objs/tspreimage"
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// TSPreImage is a token store preimage.
+type TSPreImage struct {
+	ChainID  uint32
+	AssetID  []byte
+	Amount   *uint256.Uint256
+	TXOutIdx uint32
+	Owner    *ValueStoreOwner
+	Fee      *uint256.Uint256
+	//
+	preHash []byte
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// TSPreImage object.
+func (b *TSPreImage) UnmarshalBinary(data []byte) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("tspi.unmarshalBinary: tspi not initialized")
+	}
+	bc, err := tspreimage.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return b.UnmarshalCapn(bc)
+}
+
+// MarshalBinary takes the TSPreImage object and returns the canonical
+// byte slice.
+func (b *TSPreImage) MarshalBinary() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("tspi.marshalBinary: tspi not initialized")
+	}
+	bc, err := b.MarshalCapn(nil)
+	if err != nil {
+		return nil, err
+	}
+	return tspreimage.Marshal(bc)
+}
+
+// UnmarshalCapn unmarshals the capnproto definition of the object.
+func (b *TSPreImage) UnmarshalCapn(bc mdefs.TSPreImage) error {
+	if err := tspreimage.Validate(bc); err != nil {
+		return err
+	}
+	b.ChainID = bc.ChainID()
+	b.AssetID = utils.CopySlice(bc.AssetID())
+	u32array := [8]uint32{}
+	u32array[0] = bc.Amount()
+	u32array[1] = bc.Amount1()
+	u32array[2] = bc.Amount2()
+	u32array[3] = bc.Amount3()
+	u32array[4] = bc.Amount4()
+	u32array[5] = bc.Amount5()
+	u32array[6] = bc.Amount6()
+	u32array[7] = bc.Amount7()
+	aObj := &uint256.Uint256{}
+	err := aObj.FromUint32Array(u32array)
+	if err != nil {
+		return err
+	}
+	b.Amount = aObj
+	b.TXOutIdx = bc.TXOutIdx()
+
+	owner := &ValueStoreOwner{}
+	if err := owner.UnmarshalBinary(bc.Owner()); err != nil {
+		return err
+	}
+	if owner.Lock != nil {
+		return errorz.ErrInvalid{}.New("tspi.unmarshalCapn: owner may not be time locked")
+	}
+	b.Owner = owner
+	fObj := &uint256.Uint256{}
+	u32array[0] = bc.Fee0()
+	u32array[1] = bc.Fee1()
+	u32array[2] = bc.Fee2()
+	u32array[3] = bc.Fee3()
+	u32array[4] = bc.Fee4()
+	u32array[5] = bc.Fee5()
+	u32array[6] = bc.Fee6()
+	u32array[7] = bc.Fee7()
+	err = fObj.FromUint32Array(u32array)
+	if err != nil {
+		return err
+	}
+	b.Fee = fObj
+	return nil
+}
+
+// MarshalCapn marshals the object into its capnproto definition.
+func (b *TSPreImage) MarshalCapn(seg *capnp.Segment) (mdefs.TSPreImage, error) {
+	if b == nil {
+		return mdefs.TSPreImage{}, errorz.ErrInvalid{}.New("tspi.marshalCapn: tspi not initialized")
+	}
+	var bc mdefs.TSPreImage
+	if seg == nil {
+		_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+		if err != nil {
+			return bc, err
+		}
+		tmp, err := mdefs.NewRootTSPreImage(seg)
+		if err != nil {
+			return bc, err
+		}
+		bc = tmp
+	} else {
+		tmp, err := mdefs.NewTSPreImage(seg)
+		if err != nil {
+			return bc, err
+		}
+		bc = tmp
+	}
+	owner, err := b.Owner.MarshalBinary()
+	if err != nil {
+		return bc, err
+	}
+	if err := bc.SetOwner(owner); err != nil {
+		return bc, err
+	}
+	if err := bc.SetAssetID(utils.CopySlice(b.AssetID)); err != nil {
+		return bc, err
+	}
+	bc.SetChainID(b.ChainID)
+	u32array, err := b.Amount.ToUint32Array()
+	if err != nil {
+		return bc, err
+	}
+	bc.SetAmount(u32array[0])
+	bc.SetAmount1(u32array[1])
+	bc.SetAmount2(u32array[2])
+	bc.SetAmount3(u32array[3])
+	bc.SetAmount4(u32array[4])
+	bc.SetAmount5(u32array[5])
+	bc.SetAmount6(u32array[6])
+	bc.SetAmount7(u32array[7])
+	u32array, err = b.Fee.ToUint32Array()
+	if err != nil {
+		return bc, err
+	}
+	bc.SetFee0(u32array[0])
+	bc.SetFee1(u32array[1])
+	bc.SetFee2(u32array[2])
+	bc.SetFee3(u32array[3])
+	bc.SetFee4(u32array[4])
+	bc.SetFee5(u32array[5])
+	bc.SetFee6(u32array[6])
+	bc.SetFee7(u32array[7])
+	bc.SetTXOutIdx(b.TXOutIdx)
+	return bc, nil
+}
+
+// PreHash calculates the PreHash of the object.
+func (b *TSPreImage) PreHash() ([]byte, error) {
+	if b == nil {
+		return nil, errorz.ErrInvalid{}.New("tspi.preHash: tspi not initialized")
+	}
+	if b.preHash != nil {
+		return utils.CopySlice(b.preHash), nil
+	}
+	msg, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	hsh := crypto.Hasher(msg)
+	b.preHash = hsh
+	return utils.CopySlice(b.preHash), nil
+}
+
+// ValidateSignature validates the signature for TSPreImage.
+func (b *TSPreImage) ValidateSignature(msg []byte, sig *ValueStoreSignature) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("tspi.validateSignature: tspi not initialized")
+	}
+	return b.Owner.ValidateSignature(msg, sig)
+}
@@ -0,0 +1,68 @@
+package tspreimage
+
+import (
+	capnp "github.com/MadBase/go-capnproto2/v2"
+
+	mdefs "github.com/alicenet/alicenet/application/objs/capn"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// Marshal will marshal the TSPreImage object.
+func Marshal(v mdefs.TSPreImage) ([]byte, error) {
+	raw, err := capnp.Canonicalize(v.Struct)
+	if err != nil {
+		return nil, err
+	}
+	out := utils.CopySlice(raw)
+	return out, nil
+}
+
+// Unmarshal will unmarshal the TSPreImage object.
+func Unmarshal(data []byte) (mdefs.TSPreImage, error) {
+	var err error
+	fn := func() (mdefs.TSPreImage, error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errorz.ErrInvalid{}.New("bad serialization")
+			}
+		}()
+		dataCopy := utils.CopySlice(data)
+		msg := &capnp.Message{Arena: capnp.SingleSegment(dataCopy)}
+		obj, tmp := mdefs.ReadRootTSPreImage(msg)
+		err = tmp
+		return obj, err
+	}
+	obj, err := fn()
+	if err != nil {
+		return mdefs.TSPreImage{}, err
+	}
+	return obj, nil
+}
+
+// Validate will validate the TSPreImage object.
+func Validate(v mdefs.TSPreImage) error {
+	if v.ChainID() < 1 {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj is not valid; invalid ChainID")
+	}
+	if !v.HasAssetID() {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj does not have AssetID")
+	}
+	if len(v.AssetID()) != constants.HashLen {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj is not valid: invalid AssetID; incorrect byte length")
+	}
+	if !v.HasOwner() {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj does not have Owner")
+	}
+	if len(v.Owner()) == 0 {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj is not valid: invalid Owner; zero byte length")
+	}
+	if (v.Amount() + v.Amount1() + v.Amount2() + v.Amount3() + v.Amount4() + v.Amount5() + v.Amount6() + v.Amount7()) == 0 {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj is not valid; no amount")
+	}
+	if int(v.TXOutIdx()) >= constants.MaxTxVectorLength {
+		return errorz.ErrInvalid{}.New("tspreimage capn obj is not valid: output index is too large")
+	}
+	return nil
+}
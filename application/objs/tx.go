@@ -25,6 +25,8 @@ type Tx struct {
 	Vin  Vin
 	Vout Vout
 	Fee  *uint256.Uint256
+	// Memo is an optional reference attached to the transaction
+	Memo []byte
 	// not part of serialized object below this line
 	txHash []byte
 }
@@ -57,6 +59,9 @@ func (b *Tx) MarshalBinary() ([]byte, error) {
 	if len(b.Vout) > constants.MaxTxVectorLength {
 		return nil, errorz.ErrInvalid{}.New("tx.marshalBinary: len(tx.vout) > MaxTxVectorLength")
 	}
+	if len(b.Memo) > constants.MaxTxMemoSize {
+		return nil, errorz.ErrInvalid{}.New("tx.marshalBinary: len(tx.memo) > MaxTxMemoSize")
+	}
 	bc, err := b.MarshalCapn(nil)
 	if err != nil {
 		return nil, err
@@ -116,6 +121,16 @@ func (b *Tx) UnmarshalCapn(bc mdefs.Tx) error {
 		return err
 	}
 	b.Fee = fObj
+	b.Memo = nil
+	if bc.HasMemo() {
+		memo, err := bc.Memo()
+		if err != nil {
+			return err
+		}
+		if len(memo) > 0 {
+			b.Memo = utils.CopySlice(memo)
+		}
+	}
 	return nil
 }
 
@@ -189,6 +204,11 @@ func (b *Tx) MarshalCapn(seg *capnp.Segment) (mdefs.Tx, error) {
 	bc.SetFee5(u32array[5])
 	bc.SetFee6(u32array[6])
 	bc.SetFee7(u32array[7])
+	if len(b.Memo) > 0 {
+		if err := bc.SetMemo(b.Memo); err != nil {
+			return bc, err
+		}
+	}
 	return bc, nil
 }
 
@@ -226,7 +246,10 @@ func (b *Tx) ValidateUnique(opset map[string]bool) (map[string]bool, error) {
 }
 
 // ValidateDataStoreIndexes ensures there are no duplicate output indices
-// for DataStore objects.
+// for DataStore objects. The same owner and index may still be generated by
+// a renewal, which consumes the DataStore in the same transaction, and an
+// index may move to another owner by a transfer; both are validated by
+// ValidateDataStoreUpdates.
 func (b *Tx) ValidateDataStoreIndexes(opset map[string]bool) (map[string]bool, error) {
 	if b == nil {
 		return nil, errorz.ErrInvalid{}.New("tx.validateDataStoreIndexes: tx not initialized")
@@ -300,6 +323,8 @@ func (b *Tx) ValidateTxHash() error {
 }
 
 // TxHash calculates the TxHash of the transaction.
+// When the transaction carries a memo, the TxHash is the hash of the root of
+// the inputs and outputs together with the MemoHash.
 func (b *Tx) TxHash() ([]byte, error) {
 	if b == nil {
 		return nil, errorz.ErrInvalid{}.New("tx.txHash: tx not initialized")
@@ -343,19 +368,31 @@ func (b *Tx) TxHash() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	var rootHash []byte
 	if len(keysSorted) == 0 && len(valuesSorted) == 0 {
-		rootHash := crypto.Hasher([][]byte{}...)
-		b.txHash = rootHash
-		return utils.CopySlice(b.txHash), nil
+		rootHash = crypto.Hasher([][]byte{}...)
+	} else {
+		rootHash, err = smt.Update(keysSorted, valuesSorted)
+		if err != nil {
+			return nil, err
+		}
 	}
-	rootHash, err := smt.Update(keysSorted, valuesSorted)
-	if err != nil {
-		return nil, err
+	if len(b.Memo) > 0 {
+		rootHash = crypto.Hasher(rootHash, b.MemoHash())
 	}
 	b.txHash = rootHash
 	return utils.CopySlice(b.txHash), nil
 }
 
+// MemoHash returns the hash of the memo of the transaction;
+// it is nil if the transaction does not carry a memo.
+func (b *Tx) MemoHash() []byte {
+	if b == nil || len(b.Memo) == 0 {
+		return nil
+	}
+	return crypto.Hasher(b.Memo)
+}
+
 // SetTxHash calculates the TxHash and sets it on all UTXOs and TXIns.
 func (b *Tx) SetTxHash() error {
 	if b == nil {
@@ -482,12 +519,39 @@ func (b *Tx) ValidateFees(currentHeight uint32, refUTXOs Vout, storage *wrapper.
 	if b.Fee.Lt(minTxFee) {
 		return errorz.ErrInvalid{}.New("tx.validateFees; tx.fee below minTxFee")
 	}
+	if len(b.Memo) > constants.MaxTxMemoSize {
+		return errorz.ErrInvalid{}.New("tx.validateFees; len(tx.memo) > MaxTxMemoSize")
+	}
+	if len(b.Memo) > 0 {
+		// The memo is charged per byte on top of minTxFee
+		memoByteFee, err := storage.GetMemoByteFee()
+		if err != nil {
+			return err
+		}
+		memoSize, err := new(uint256.Uint256).FromUint64(uint64(len(b.Memo)))
+		if err != nil {
+			return err
+		}
+		memoFee, err := new(uint256.Uint256).Mul(memoByteFee, memoSize)
+		if err != nil {
+			return err
+		}
+		minFee, err := new(uint256.Uint256).Add(minTxFee, memoFee)
+		if err != nil {
+			return err
+		}
+		if b.Fee.Lt(minFee) {
+			return errorz.ErrInvalid{}.New("tx.validateFees; tx.fee below minTxFee plus memo fee")
+		}
+	}
 	return nil
 }
 
 // ValidateEqualVinVout checks the following
 // calc sum on inputs from utxos and currentHeight
 // sum inputs must equal sum outputs plus fee.
+// The amount of each asset held by TokenStores must be conserved as well,
+// apart from the asset issued by the transaction.
 func (b *Tx) ValidateEqualVinVout(currentHeight uint32, refUTXOs Vout) error {
 	if b == nil {
 		return errorz.ErrInvalid{}.New("tx.validateEqualVinVout: tx not initialized")
@@ -524,11 +588,129 @@ func (b *Tx) ValidateEqualVinVout(currentHeight uint32, refUTXOs Vout) error {
 		return err
 	}
 	if valueOutPlusFee.Cmp(valueIn) == 0 {
-		return nil
+		if err := b.validateLocks(currentHeight, refUTXOs); err != nil {
+			return err
+		}
+		return b.validateAssets(refUTXOs)
 	}
 	return errorz.ErrInvalid{}.New(fmt.Sprintf("tx.validateEqualVinVout: input value does not match output value: IN:%v  vs  OUT+FEE:%v", valueIn, valueOutPlusFee))
 }
 
+// lockedValueStore is the value and lock of a time locked ValueStore.
+type lockedValueStore struct {
+	value *uint256.Uint256
+	lock  *ValueStoreLock
+}
+
+// validateLocks validates that the value of the consumed ValueStores which is
+// still locked at currentHeight stays locked for the same owner. At every
+// epoch from now on, the time locked outputs of an owner must lock at least
+// the value that the consumed ValueStores of the owner would have locked;
+// this allows the vested value to be spent while the rest is locked again.
+func (b *Tx) validateLocks(currentHeight uint32, refUTXOs Vout) error {
+	epoch := utils.Epoch(currentHeight)
+	inputs := make(map[string][]*lockedValueStore)
+	for i := 0; i < len(refUTXOs); i++ {
+		lvs, key, err := lockedValueStoreOf(refUTXOs[i])
+		if err != nil {
+			return err
+		}
+		if lvs == nil || lvs.lock.IsUnlocked(epoch) {
+			continue
+		}
+		inputs[key] = append(inputs[key], lvs)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	outputs := make(map[string][]*lockedValueStore)
+	for i := 0; i < len(b.Vout); i++ {
+		lvs, key, err := lockedValueStoreOf(b.Vout[i])
+		if err != nil {
+			return err
+		}
+		if lvs == nil {
+			continue
+		}
+		outputs[key] = append(outputs[key], lvs)
+	}
+	for key, ins := range inputs {
+		outs := outputs[key]
+		// the locked values are linear between the unlock and vesting end
+		// epochs, so comparing them at those epochs covers every epoch
+		epochs := []uint32{epoch}
+		for _, lvs := range append(append([]*lockedValueStore{}, ins...), outs...) {
+			if lvs.lock.UnlockEpoch > epoch {
+				epochs = append(epochs, lvs.lock.UnlockEpoch)
+			}
+			if lvs.lock.VestingEndEpoch > epoch {
+				epochs = append(epochs, lvs.lock.VestingEndEpoch)
+			}
+		}
+		for _, e := range epochs {
+			lockedIn, err := sumLockedValue(ins, e)
+			if err != nil {
+				return err
+			}
+			lockedOut, err := sumLockedValue(outs, e)
+			if err != nil {
+				return err
+			}
+			if lockedOut.Lt(lockedIn) {
+				return errorz.ErrInvalid{}.New(fmt.Sprintf("tx.validateLocks: locked value is released early at epoch %v: IN:%v  vs  OUT:%v", e, lockedIn, lockedOut))
+			}
+		}
+	}
+	return nil
+}
+
+// lockedValueStoreOf returns the lockedValueStore of utxo along with the
+// owner it is locked for; it is nil if utxo is not a time locked ValueStore.
+func lockedValueStoreOf(utxo *TXOut) (*lockedValueStore, string, error) {
+	if !utxo.HasValueStore() {
+		return nil, "", nil
+	}
+	vs, err := utxo.ValueStore()
+	if err != nil {
+		return nil, "", err
+	}
+	lock, err := vs.Lock()
+	if err != nil {
+		return nil, "", err
+	}
+	if lock == nil {
+		return nil, "", nil
+	}
+	value, err := vs.Value()
+	if err != nil {
+		return nil, "", err
+	}
+	owner, err := vs.GenericOwner()
+	if err != nil {
+		return nil, "", err
+	}
+	ownerBytes, err := owner.MarshalBinary()
+	if err != nil {
+		return nil, "", err
+	}
+	return &lockedValueStore{value: value, lock: lock}, string(ownerBytes), nil
+}
+
+func sumLockedValue(lvss []*lockedValueStore, epoch uint32) (*uint256.Uint256, error) {
+	sum := uint256.Zero()
+	for _, lvs := range lvss {
+		locked, err := lvs.lock.LockedValue(lvs.value, epoch)
+		if err != nil {
+			return nil, err
+		}
+		sum, err = sum.Add(sum, locked)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
 // ValidateChainID validates that all elements have the correct ChainID.
 func (b *Tx) ValidateChainID(chainID uint32) error {
 	if b == nil {
@@ -654,6 +836,62 @@ func (b *Tx) EpochOfExpirationForMining() (uint32, error) {
 	return utils.Epoch(mbh), nil
 }
 
+// ValidateFork rejects the features of the application fork before the fork
+// is active: memos, AtomicSwaps, TokenStores, time-locked ValueStores and
+// owners or signatures of a MultiSigOwner. The rules which changed with the
+// fork, such as those of ValidateDataStoreUpdates, check the fork themselves.
+func (b *Tx) ValidateFork(currentHeight uint32, storage *wrapper.Storage) error {
+	if b == nil {
+		return errorz.ErrInvalid{}.New("tx.validateFork: tx not initialized")
+	}
+	forked, err := storage.IsForked(currentHeight)
+	if err != nil {
+		return err
+	}
+	if forked {
+		return nil
+	}
+	if len(b.Memo) > 0 {
+		return errorz.ErrInvalid{}.New("tx.validateFork: memo before the fork")
+	}
+	for _, utxo := range b.Vout {
+		switch {
+		case utxo.HasValueStore():
+			vs, err := utxo.ValueStore()
+			if err != nil {
+				return err
+			}
+			owner, err := vs.Owner()
+			if err != nil {
+				return err
+			}
+			if owner.SVA != ValueStoreSVA || owner.Lock != nil {
+				return errorz.ErrInvalid{}.New("tx.validateFork: valuestore owner before the fork")
+			}
+		case utxo.HasDataStore():
+			ds, err := utxo.DataStore()
+			if err != nil {
+				return err
+			}
+			owner, err := ds.Owner()
+			if err != nil {
+				return err
+			}
+			if owner.SVA != DataStoreSVA {
+				return errorz.ErrInvalid{}.New("tx.validateFork: datastore owner before the fork")
+			}
+		default:
+			return errorz.ErrInvalid{}.New("tx.validateFork: utxo type before the fork")
+		}
+	}
+	for _, txIn := range b.Vin {
+		if len(txIn.Signature) > 0 && SVA(txIn.Signature[0]) == MultiSigSVA {
+			return errorz.ErrInvalid{}.New("tx.validateFork: multisig signature before the fork")
+		}
+	}
+	return nil
+}
+
 // Validate validates the tx object
 func (b *Tx) Validate(set map[string]bool, currentHeight uint32, consumedUTXOs Vout, storage *wrapper.Storage) (map[string]bool, error) {
 	if b == nil {
@@ -668,6 +906,9 @@ func (b *Tx) Validate(set map[string]bool, currentHeight uint32, consumedUTXOs V
 	if b.Fee == nil {
 		return nil, errorz.ErrInvalid{}.New("tx.validate: tx.fee not initialized")
 	}
+	if err := b.ValidateFork(currentHeight, storage); err != nil {
+		return nil, err
+	}
 	if err := b.Vout.ValidateTxOutIdx(); err != nil {
 		return nil, err
 	}
@@ -683,6 +924,10 @@ func (b *Tx) Validate(set map[string]bool, currentHeight uint32, consumedUTXOs V
 	if err != nil {
 		return nil, err
 	}
+	err = b.ValidateDataStoreUpdates(currentHeight, consumedUTXOs, storage)
+	if err != nil {
+		return nil, err
+	}
 	err = b.ValidateFees(currentHeight, consumedUTXOs, storage)
 	if err != nil {
 		return nil, err
@@ -694,71 +939,89 @@ func (b *Tx) Validate(set map[string]bool, currentHeight uint32, consumedUTXOs V
 	return set, nil
 }
 
-// PreValidatePending performs the initial check of a transaction
-// before it is added to the pending transaction pool.
-// This includes all of the basic validation logic that *all* transactions
-// must minimally pass to be valid.
-// Other important validation logic is included in PostValidatePending.
-func (b *Tx) PreValidatePending(chainID uint32) error {
+// PendingStep is a rule of the pending transaction pool; Code is the
+// errorz code reported when Validate fails.
+type PendingStep struct {
+	Code     errorz.Code
+	Validate func() error
+}
+
+// RunPendingSteps runs steps in order and returns the error of the first
+// broken rule with its code attached.
+func RunPendingSteps(steps []PendingStep) error {
+	for _, step := range steps {
+		if err := step.Validate(); err != nil {
+			return errorz.WithCode(step.Code, err)
+		}
+	}
+	return nil
+}
+
+// ValidatePendingFields ensures the fields every rule of the pending
+// transaction pool relies on are set.
+func (b *Tx) ValidatePendingFields() error {
 	if b == nil {
-		return errorz.ErrInvalid{}.New("tx.preValidatePending: tx not initialized")
+		return errorz.WithCode(errorz.CodeTxMalformed, errorz.ErrInvalid{}.New("tx.validatePendingFields: tx not initialized"))
 	}
 	if len(b.Vin) == 0 {
-		return errorz.ErrInvalid{}.New("tx.preValidatePending: tx.vin not initialized")
+		return errorz.WithCode(errorz.CodeTxMalformed, errorz.ErrInvalid{}.New("tx.validatePendingFields: tx.vin not initialized"))
 	}
 	if len(b.Vout) == 0 {
-		return errorz.ErrInvalid{}.New("tx.preValidatePending: tx.vout not initialized")
+		return errorz.WithCode(errorz.CodeTxMalformed, errorz.ErrInvalid{}.New("tx.validatePendingFields: tx.vout not initialized"))
 	}
 	if b.Fee == nil {
-		return errorz.ErrInvalid{}.New("tx.preValidatePending: tx.fee not initialized")
-	}
-	err := b.ValidateChainID(chainID)
-	if err != nil {
-		return err
+		return errorz.WithCode(errorz.CodeTxMalformed, errorz.ErrInvalid{}.New("tx.validatePendingFields: tx.fee not initialized"))
 	}
-	_, err = b.ValidateUnique(nil)
-	if err != nil {
-		return err
+	return nil
+}
+
+// PreValidatePendingSteps returns the rules checked by PreValidatePending,
+// in order. The fields of the transaction must have been validated with
+// ValidatePendingFields.
+func (b *Tx) PreValidatePendingSteps(chainID uint32) []PendingStep {
+	return []PendingStep{
+		{errorz.CodeTxChainID, func() error { return b.ValidateChainID(chainID) }},
+		{errorz.CodeTxMalformed, func() error {
+			_, err := b.ValidateUnique(nil)
+			return err
+		}},
+		{errorz.CodeTxHash, b.ValidateTxHash},
+		{errorz.CodeTxOutputPreSignature, b.ValidatePreSignature},
 	}
-	err = b.ValidateTxHash()
-	if err != nil {
-		return err
+}
+
+// PostValidatePendingSteps returns the rules checked by PostValidatePending,
+// in order. The fields of the transaction must have been validated with
+// ValidatePendingFields.
+func (b *Tx) PostValidatePendingSteps(currentHeight uint32, consumedUTXOs Vout, storage *wrapper.Storage) []PendingStep {
+	return []PendingStep{
+		{errorz.CodeTxFork, func() error { return b.ValidateFork(currentHeight, storage) }},
+		{errorz.CodeTxValueMismatch, func() error { return b.ValidateEqualVinVout(currentHeight, consumedUTXOs) }},
+		{errorz.CodeTxDataStoreUpdate, func() error { return b.ValidateDataStoreUpdates(currentHeight, consumedUTXOs, storage) }},
+		{errorz.CodeTxFee, func() error { return b.ValidateFees(currentHeight, consumedUTXOs, storage) }},
+		{errorz.CodeTxSignature, func() error { return b.ValidateSignature(currentHeight, consumedUTXOs) }},
 	}
-	err = b.ValidatePreSignature()
-	if err != nil {
+}
+
+// PreValidatePending performs the initial check of a transaction
+// before it is added to the pending transaction pool.
+// This includes all of the basic validation logic that *all* transactions
+// must minimally pass to be valid.
+// Other important validation logic is included in PostValidatePending.
+func (b *Tx) PreValidatePending(chainID uint32) error {
+	if err := b.ValidatePendingFields(); err != nil {
 		return err
 	}
-	return nil
+	return RunPendingSteps(b.PreValidatePendingSteps(chainID))
 }
 
 // PostValidatePending performs the validation logic which occurs before
 // adding the transaction to the pending transaction pool.
 func (b *Tx) PostValidatePending(currentHeight uint32, consumedUTXOs Vout, storage *wrapper.Storage) error {
-	if b == nil {
-		return errorz.ErrInvalid{}.New("tx.postValidatePending: tx not initialized")
-	}
-	if len(b.Vin) == 0 {
-		return errorz.ErrInvalid{}.New("tx.postValidatePending: tx.vin not initialized")
-	}
-	if len(b.Vout) == 0 {
-		return errorz.ErrInvalid{}.New("tx.postValidatePending: tx.vout not initialized")
-	}
-	if b.Fee == nil {
-		return errorz.ErrInvalid{}.New("tx.postValidatePending: tx.fee not initialized")
-	}
-	err := b.ValidateEqualVinVout(currentHeight, consumedUTXOs)
-	if err != nil {
-		return err
-	}
-	err = b.ValidateFees(currentHeight, consumedUTXOs, storage)
-	if err != nil {
+	if err := b.ValidatePendingFields(); err != nil {
 		return err
 	}
-	err = b.ValidateSignature(currentHeight, consumedUTXOs)
-	if err != nil {
-		return err
-	}
-	return nil
+	return RunPendingSteps(b.PostValidatePendingSteps(currentHeight, consumedUTXOs, storage))
 }
 
 // IsCleanupTx checks if Tx is a cleanup transaction.
@@ -783,6 +1046,10 @@ func (b *Tx) IsCleanupTx(currentHeight uint32, refUTXOs Vout) bool {
 	if !b.Fee.IsZero() {
 		return false
 	}
+	// Memos are paid for, so they cannot be attached to cleanup txs
+	if len(b.Memo) > 0 {
+		return false
+	}
 	// Confirm inputs equal outputs
 	if err := b.ValidateEqualVinVout(currentHeight, refUTXOs); err != nil {
 		return false
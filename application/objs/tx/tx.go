@@ -69,5 +69,14 @@ func Validate(v mdefs.Tx) error {
 	if vout.Len() > constants.MaxTxVectorLength {
 		return errorz.ErrInvalid{}.New("tx capn obj is not valid; invalid Vout; length object too large")
 	}
+	if v.HasMemo() {
+		memo, err := v.Memo()
+		if err != nil {
+			return err
+		}
+		if len(memo) > constants.MaxTxMemoSize {
+			return errorz.ErrInvalid{}.New("tx capn obj is not valid; invalid Memo; length object too large")
+		}
+	}
 	return nil
 }
@@ -10,8 +10,11 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/application/wrapper"
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/dynamics/mocks"
+	"github.com/alicenet/alicenet/errorz"
 )
 
 func makeVS(t *testing.T, ownerSigner Signer, i int) *TXOut {
@@ -1808,3 +1811,262 @@ func TestTxValidateEqualVinVoutBad3(t *testing.T) {
 		t.Fatal("Should have raised error")
 	}
 }
+
+func makeLockedValueStoreUTXO(t *testing.T, value uint64, acct []byte, lock *ValueStoreLock) *TXOut {
+	t.Helper()
+	v, err := new(uint256.Uint256).FromUint64(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := &ValueStore{}
+	if err := vs.New(1, v, uint256.Zero(), acct, constants.CurveSecp256k1, make([]byte, constants.HashLen)); err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		vso := &ValueStoreOwner{}
+		if err := vso.NewLocked(acct, constants.CurveSecp256k1, lock); err != nil {
+			t.Fatal(err)
+		}
+		vs.VSPreImage.Owner = vso
+	}
+	utxo := &TXOut{}
+	if err := utxo.NewValueStore(vs); err != nil {
+		t.Fatal(err)
+	}
+	return utxo
+}
+
+func TestTxValidateEqualVinVoutLocked(t *testing.T) {
+	acct := crypto.Hasher([]byte("owner"))[:constants.OwnerLen]
+	other := crypto.Hasher([]byte("other"))[:constants.OwnerLen]
+	vesting := &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 4}
+	refUTXOs := Vout{makeLockedValueStoreUTXO(t, 10, acct, vesting)}
+	epoch1 := uint32(1)
+	epoch3 := 2*constants.EpochLength + 1
+	epoch4 := 3*constants.EpochLength + 1
+
+	testCases := []struct {
+		height uint32
+		vout   Vout
+		valid  bool
+	}{
+		// nothing has vested yet
+		{epoch1, Vout{makeLockedValueStoreUTXO(t, 10, acct, nil)}, false},
+		{epoch1, Vout{makeLockedValueStoreUTXO(t, 10, acct, vesting)}, true},
+		// half has vested; the rest must vest from now until the end
+		{epoch3, Vout{makeLockedValueStoreUTXO(t, 5, acct, nil), makeLockedValueStoreUTXO(t, 5, acct, &ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 4})}, true},
+		{epoch3, Vout{makeLockedValueStoreUTXO(t, 6, acct, nil), makeLockedValueStoreUTXO(t, 4, acct, &ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 4})}, false},
+		{epoch3, Vout{makeLockedValueStoreUTXO(t, 5, acct, nil), makeLockedValueStoreUTXO(t, 5, acct, &ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 3})}, false},
+		{epoch3, Vout{makeLockedValueStoreUTXO(t, 5, acct, nil), makeLockedValueStoreUTXO(t, 5, other, &ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 4})}, false},
+		// everything has vested
+		{epoch4, Vout{makeLockedValueStoreUTXO(t, 10, other, nil)}, true},
+	}
+	for i, tc := range testCases {
+		tx := &Tx{Vin: Vin{&TXIn{}}, Vout: tc.vout, Fee: uint256.Zero()}
+		err := tx.ValidateEqualVinVout(tc.height, refUTXOs)
+		if tc.valid && err != nil {
+			t.Fatalf("Should be valid (%v): %v", i, err)
+		}
+		if !tc.valid && err == nil {
+			t.Fatalf("Should raise an error (%v)", i)
+		}
+	}
+}
+
+func makeMemoTx(t *testing.T, memo []byte, fee uint64) *Tx {
+	t.Helper()
+	ownerSigner := &crypto.Secp256k1Signer{}
+	if err := ownerSigner.SetPrivk(crypto.Hasher([]byte("a"))); err != nil {
+		t.Fatal(err)
+	}
+	value, err := new(uint256.Uint256).FromUint64(10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txFee, err := new(uint256.Uint256).FromUint64(fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo1 := makeVSWithValueFee(t, ownerSigner, 1, value, uint256.Zero())
+	txin1, err := utxo1.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo2 := makeVSWithValueFee(t, ownerSigner, 2, value, uint256.Zero())
+	tx := &Tx{Vin: []*TXIn{txin1}, Vout: []*TXOut{utxo2}, Fee: txFee, Memo: memo}
+	err = tx.SetTxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = utxo1.valueStore.Sign(tx.Vin[0], ownerSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestTxMemo(t *testing.T) {
+	memo := []byte("invoice-42")
+	tx := makeMemoTx(t, memo, 0)
+	txHash, err := tx.TxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txNoMemo := makeMemoTx(t, nil, 0)
+	txHashNoMemo, err := txNoMemo.TxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(txHash, txHashNoMemo) {
+		t.Fatal("memo should be included in the txhash")
+	}
+	if txNoMemo.MemoHash() != nil {
+		t.Fatal("memo hash should be nil without a memo")
+	}
+	if !bytes.Equal(tx.MemoHash(), crypto.Hasher(memo)) {
+		t.Fatal("invalid memo hash")
+	}
+
+	txb, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2 := &Tx{}
+	err = tx2.UnmarshalBinary(txb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tx2.Memo, memo) {
+		t.Fatalf("memo mismatch: %x", tx2.Memo)
+	}
+	txHash2, err := tx2.TxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(txHash2, txHash) {
+		t.Fatal("txhashes do not match")
+	}
+
+	txb, err = txNoMemo.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx3 := &Tx{}
+	err = tx3.UnmarshalBinary(txb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx3.Memo != nil {
+		t.Fatal("memo should be nil")
+	}
+
+	txBig := makeMemoTx(t, make([]byte, constants.MaxTxMemoSize+1), 0)
+	_, err = txBig.MarshalBinary()
+	if err == nil {
+		t.Fatal("Should have raised error")
+	}
+}
+
+func TestTxValidateFeesMemo(t *testing.T) {
+	mock := mocks.NewMockStorageGetter()
+	mock.GetDataStoreFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(0))
+	mock.GetValueStoreFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(0))
+	mock.GetMinScaledTransactionFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(1))
+	mock.GetMemoByteFeeFunc.SetDefaultReturn(new(big.Int).SetInt64(2))
+	storage := wrapper.NewStorage(mock, 1)
+	memo := []byte("memo")
+
+	tx := makeMemoTx(t, memo, 8)
+	err := tx.ValidateFees(0, nil, storage)
+	if err == nil {
+		t.Fatal("Should have raised error")
+	}
+	tx = makeMemoTx(t, memo, 9)
+	err = tx.ValidateFees(0, nil, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = makeMemoTx(t, nil, 1)
+	err = tx.ValidateFees(0, nil, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTxPreValidatePendingCodes(t *testing.T) {
+	tx := &Tx{}
+	err := tx.PreValidatePending(2)
+	if errorz.CodeOf(err) != errorz.CodeTxMalformed {
+		t.Fatalf("wrong code for a malformed tx: %v", err)
+	}
+
+	tx = makeMemoTx(t, nil, 0)
+	err = tx.PreValidatePending(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.PreValidatePending(3)
+	if errorz.CodeOf(err) != errorz.CodeTxChainID {
+		t.Fatalf("wrong code for a chainID mismatch: %v", err)
+	}
+}
+
+func TestTxValidateFork(t *testing.T) {
+	signer := makeSecpSigner(crypto.Hasher([]byte("a")))
+	acct := crypto.Hasher([]byte("owner"))[:constants.OwnerLen]
+	index := crypto.Hasher([]byte("index"))
+	rawData := crypto.Hasher([]byte("rawData"))
+	as, _, _, _ := makeAtomicSwap(t)
+	asUTXO := &TXOut{}
+	if err := asUTXO.NewAtomicSwap(as); err != nil {
+		t.Fatal(err)
+	}
+	multiSigUTXO := makeLockedValueStoreUTXO(t, 1, acct, nil)
+	multiSigUTXO.valueStore.VSPreImage.Owner.SVA = MultiSigSVA
+	multiSigTxIn := &TXIn{Signature: []byte{uint8(MultiSigSVA)}}
+
+	// the fork starts at the third epoch
+	storage := wrapper.NewStorage(mocks.NewMockStorageGetter(), 3)
+	before := 2 * constants.EpochLength
+	after := 2*constants.EpochLength + 1
+
+	testCases := []struct {
+		name    string
+		tx      *Tx
+		oldRule bool
+	}{
+		{"valuestore", makeMemoTx(t, nil, 0), true},
+		{"datastore", &Tx{Vout: Vout{makeDSWithValueFee(t, signer, 0, rawData, index, 1, 10, uint256.Zero())}, Fee: uint256.Zero()}, true},
+		{"memo", makeMemoTx(t, []byte("memo"), 0), false},
+		{"atomicswap", &Tx{Vout: Vout{asUTXO}, Fee: uint256.Zero()}, false},
+		{"tokenstore", &Tx{Vout: Vout{makeTSWithAmount(t, signer, 0, crypto.Hasher([]byte("asset")), 1)}, Fee: uint256.Zero()}, false},
+		{"locked valuestore", &Tx{Vout: Vout{makeLockedValueStoreUTXO(t, 1, acct, &ValueStoreLock{UnlockEpoch: 5, VestingEndEpoch: 5})}, Fee: uint256.Zero()}, false},
+		{"multisig owner", &Tx{Vout: Vout{multiSigUTXO}, Fee: uint256.Zero()}, false},
+		{"multisig signature", &Tx{Vin: Vin{multiSigTxIn}, Fee: uint256.Zero()}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.tx.ValidateFork(before, storage)
+			if tc.oldRule && err != nil {
+				t.Fatalf("Should be valid before the fork: %v", err)
+			}
+			if !tc.oldRule && err == nil {
+				t.Fatal("Should raise an error before the fork")
+			}
+			if err := tc.tx.ValidateFork(after, storage); err != nil {
+				t.Fatalf("Should be valid after the fork: %v", err)
+			}
+		})
+	}
+
+	// a fork epoch of zero never activates the fork
+	disabled := wrapper.NewStorage(mocks.NewMockStorageGetter(), 0)
+	memoTx := makeMemoTx(t, []byte("memo"), 0)
+	if err := memoTx.ValidateFork(constants.MaxUint32, disabled); err == nil {
+		t.Fatal("Should raise an error without a fork epoch")
+	}
+	err := RunPendingSteps(memoTx.PostValidatePendingSteps(constants.MaxUint32, nil, disabled))
+	if errorz.CodeOf(err) != errorz.CodeTxFork {
+		t.Fatalf("wrong code for a tx before the fork: %v", err)
+	}
+}
@@ -76,6 +76,13 @@ func extractSVA(owner []byte) (SVA, []byte, error) {
 	return SVA(owner[0]), utils.CopySlice(owner[1:]), nil
 }
 
+func extractSignerRole(owner []byte) (SignerRole, []byte, error) {
+	if len(owner) < 1 {
+		return 0, nil, errorz.ErrInvalid{}.New("extractSignerRole: extraction failed")
+	}
+	return SignerRole(owner[0]), utils.CopySlice(owner[1:]), nil
+}
+
 func extractHash(owner []byte) ([]byte, []byte, error) {
 	if len(owner) < constants.HashLen {
 		return nil, nil, errorz.ErrInvalid{}.New("extractHash: extraction failed")
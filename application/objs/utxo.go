@@ -16,9 +16,13 @@ import (
 type TXOut struct {
 	dataStore  *DataStore
 	valueStore *ValueStore
+	atomicSwap *AtomicSwap
+	tokenStore *TokenStore
 	// not part of serialized object below this line
 	hasDataStore  bool
 	hasValueStore bool
+	hasAtomicSwap bool
+	hasTokenStore bool
 }
 
 // CreateValueStore makes a new ValueStore.
@@ -41,12 +45,36 @@ func (b *TXOut) CreateValueStoreFromDeposit(chainID uint32, value *uint256.Uint2
 	return b.NewValueStore(vs)
 }
 
+// CreateAtomicSwap makes a new AtomicSwap.
+func (b *TXOut) CreateAtomicSwap(chainID uint32, value, fee *uint256.Uint256, owner *AtomicSwapOwner, issuedAt, exp uint32, txHash []byte) error {
+	as := &AtomicSwap{}
+	err := as.New(chainID, value, fee, owner, issuedAt, exp, txHash)
+	if err != nil {
+		return err
+	}
+	return b.NewAtomicSwap(as)
+}
+
+// CreateTokenStore makes a new TokenStore.
+func (b *TXOut) CreateTokenStore(chainID uint32, assetID []byte, amount, fee *uint256.Uint256, acct []byte, curveSpec constants.CurveSpec, txHash []byte) error {
+	ts := &TokenStore{}
+	err := ts.New(chainID, assetID, amount, fee, acct, curveSpec, txHash)
+	if err != nil {
+		return err
+	}
+	return b.NewTokenStore(ts)
+}
+
 // NewDataStore makes a TXOut object which with the specified DataStore.
 func (b *TXOut) NewDataStore(v *DataStore) error {
 	b.hasDataStore = true
 	b.hasValueStore = false
+	b.hasAtomicSwap = false
+	b.hasTokenStore = false
 	b.dataStore = v
 	b.valueStore = nil
+	b.atomicSwap = nil
+	b.tokenStore = nil
 	return nil
 }
 
@@ -54,8 +82,38 @@ func (b *TXOut) NewDataStore(v *DataStore) error {
 func (b *TXOut) NewValueStore(v *ValueStore) error {
 	b.hasDataStore = false
 	b.hasValueStore = true
+	b.hasAtomicSwap = false
+	b.hasTokenStore = false
 	b.dataStore = nil
 	b.valueStore = v
+	b.atomicSwap = nil
+	b.tokenStore = nil
+	return nil
+}
+
+// NewAtomicSwap makes a TXOut object which with the specified AtomicSwap.
+func (b *TXOut) NewAtomicSwap(v *AtomicSwap) error {
+	b.hasDataStore = false
+	b.hasValueStore = false
+	b.hasAtomicSwap = true
+	b.hasTokenStore = false
+	b.dataStore = nil
+	b.valueStore = nil
+	b.atomicSwap = v
+	b.tokenStore = nil
+	return nil
+}
+
+// NewTokenStore makes a TXOut object which with the specified TokenStore.
+func (b *TXOut) NewTokenStore(v *TokenStore) error {
+	b.hasDataStore = false
+	b.hasValueStore = false
+	b.hasAtomicSwap = false
+	b.hasTokenStore = true
+	b.dataStore = nil
+	b.valueStore = nil
+	b.atomicSwap = nil
+	b.tokenStore = v
 	return nil
 }
 
@@ -75,6 +133,22 @@ func (b *TXOut) HasValueStore() bool {
 	return b.hasValueStore
 }
 
+// HasAtomicSwap specifies if the TXOut object has an AtomicSwap.
+func (b *TXOut) HasAtomicSwap() bool {
+	if b == nil {
+		return false
+	}
+	return b.hasAtomicSwap
+}
+
+// HasTokenStore specifies if the TXOut object has a TokenStore.
+func (b *TXOut) HasTokenStore() bool {
+	if b == nil {
+		return false
+	}
+	return b.hasTokenStore
+}
+
 // DataStore returns the DataStore of the TXOut object if it exists.
 func (b *TXOut) DataStore() (*DataStore, error) {
 	if b.HasDataStore() {
@@ -91,6 +165,22 @@ func (b *TXOut) ValueStore() (*ValueStore, error) {
 	return nil, errorz.ErrInvalid{}.New("txout.valuestore; object does not have a ValueStore")
 }
 
+// AtomicSwap returns the AtomicSwap of the TXOut object if it exists.
+func (b *TXOut) AtomicSwap() (*AtomicSwap, error) {
+	if b.HasAtomicSwap() {
+		return b.atomicSwap, nil
+	}
+	return nil, errorz.ErrInvalid{}.New("txout.atomicswap; object does not have an AtomicSwap")
+}
+
+// TokenStore returns the TokenStore of the TXOut object if it exists.
+func (b *TXOut) TokenStore() (*TokenStore, error) {
+	if b.HasTokenStore() {
+		return b.tokenStore, nil
+	}
+	return nil, errorz.ErrInvalid{}.New("txout.tokenstore; object does not have a TokenStore")
+}
+
 // UnmarshalBinary takes a byte slice and returns the corresponding
 // TXOut object.
 func (b *TXOut) UnmarshalBinary(data []byte) error {
@@ -131,6 +221,10 @@ func (b *TXOut) UnmarshalCapn(bc mdefs.TXOut) error {
 		b.hasDataStore = true
 		b.valueStore = nil
 		b.hasValueStore = false
+		b.atomicSwap = nil
+		b.hasAtomicSwap = false
+		b.tokenStore = nil
+		b.hasTokenStore = false
 	case bc.HasValueStore():
 		cObj, err := bc.ValueStore()
 		if err != nil {
@@ -145,6 +239,46 @@ func (b *TXOut) UnmarshalCapn(bc mdefs.TXOut) error {
 		b.hasDataStore = false
 		b.valueStore = obj
 		b.hasValueStore = true
+		b.atomicSwap = nil
+		b.hasAtomicSwap = false
+		b.tokenStore = nil
+		b.hasTokenStore = false
+	case bc.HasAtomicSwap():
+		cObj, err := bc.AtomicSwap()
+		if err != nil {
+			return err
+		}
+		obj := &AtomicSwap{}
+		err = obj.UnmarshalCapn(cObj)
+		if err != nil {
+			return err
+		}
+		b.dataStore = nil
+		b.hasDataStore = false
+		b.valueStore = nil
+		b.hasValueStore = false
+		b.atomicSwap = obj
+		b.hasAtomicSwap = true
+		b.tokenStore = nil
+		b.hasTokenStore = false
+	case bc.HasTokenStore():
+		cObj, err := bc.TokenStore()
+		if err != nil {
+			return err
+		}
+		obj := &TokenStore{}
+		err = obj.UnmarshalCapn(cObj)
+		if err != nil {
+			return err
+		}
+		b.dataStore = nil
+		b.hasDataStore = false
+		b.valueStore = nil
+		b.hasValueStore = false
+		b.atomicSwap = nil
+		b.hasAtomicSwap = false
+		b.tokenStore = obj
+		b.hasTokenStore = true
 	default:
 		return errorz.ErrInvalid{}.New("txout.unmarshalCapn; type not defined")
 	}
@@ -192,6 +326,22 @@ func (b *TXOut) MarshalCapn(seg *capnp.Segment) (mdefs.TXOut, error) {
 		if err := bc.SetValueStore(vs); err != nil {
 			return bc, err
 		}
+	case b.hasAtomicSwap:
+		as, err := b.atomicSwap.MarshalCapn(seg)
+		if err != nil {
+			return bc, err
+		}
+		if err := bc.SetAtomicSwap(as); err != nil {
+			return bc, err
+		}
+	case b.hasTokenStore:
+		ts, err := b.tokenStore.MarshalCapn(seg)
+		if err != nil {
+			return bc, err
+		}
+		if err := bc.SetTokenStore(ts); err != nil {
+			return bc, err
+		}
 	default:
 		return mdefs.TXOut{}, errorz.ErrInvalid{}.New("txout.marshalCapn; type not defined")
 	}
@@ -207,6 +357,12 @@ func (b *TXOut) PreHash() ([]byte, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.PreHash()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.PreHash()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.PreHash()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.preHash; type not defined")
 	}
@@ -221,6 +377,12 @@ func (b *TXOut) UTXOID() ([]byte, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.UTXOID()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.UTXOID()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.UTXOID()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.utxoID; type not defined")
 	}
@@ -235,6 +397,12 @@ func (b *TXOut) ChainID() (uint32, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.ChainID()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.ChainID()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.ChainID()
 	default:
 		return 0, errorz.ErrInvalid{}.New("txout.chainID; type not defined")
 	}
@@ -249,6 +417,12 @@ func (b *TXOut) TxOutIdx() (uint32, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.TxOutIdx()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.TxOutIdx()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.TxOutIdx()
 	default:
 		return 0, errorz.ErrInvalid{}.New("txout.txOutIdx; type not defined")
 	}
@@ -263,6 +437,12 @@ func (b *TXOut) SetTxOutIdx(idx uint32) error {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.SetTxOutIdx(idx)
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.SetTxOutIdx(idx)
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.SetTxOutIdx(idx)
 	default:
 		return errorz.ErrInvalid{}.New("txout.setTxOutIdx; type not defined")
 	}
@@ -287,6 +467,24 @@ func (b *TXOut) TxHash() ([]byte, error) {
 			return nil, errorz.ErrInvalid{}.New("txout.txhash: vs.txhash has incorrect length")
 		}
 		return utils.CopySlice(obj.TxHash), nil
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		if obj == nil {
+			return nil, errorz.ErrInvalid{}.New("txout.txhash: as not initialized")
+		}
+		if len(obj.TxHash) != constants.HashLen {
+			return nil, errorz.ErrInvalid{}.New("txout.txhash: as.txhash has incorrect length")
+		}
+		return utils.CopySlice(obj.TxHash), nil
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		if obj == nil {
+			return nil, errorz.ErrInvalid{}.New("txout.txhash: ts not initialized")
+		}
+		if len(obj.TxHash) != constants.HashLen {
+			return nil, errorz.ErrInvalid{}.New("txout.txhash: ts.txhash has incorrect length")
+		}
+		return utils.CopySlice(obj.TxHash), nil
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.txhash; type not defined")
 	}
@@ -301,6 +499,12 @@ func (b *TXOut) SetTxHash(txHash []byte) error {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.SetTxHash(utils.CopySlice(txHash))
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.SetTxHash(utils.CopySlice(txHash))
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.SetTxHash(utils.CopySlice(txHash))
 	default:
 		return errorz.ErrInvalid{}.New("txout.setTxHash; type not defined")
 	}
@@ -314,6 +518,10 @@ func (b *TXOut) IsExpired(currentHeight uint32) (bool, error) {
 		return obj.IsExpired(currentHeight)
 	case b.HasValueStore():
 		return false, nil
+	case b.HasAtomicSwap():
+		return false, nil
+	case b.HasTokenStore():
+		return false, nil
 	default:
 		return false, errorz.ErrInvalid{}.New("txout.isExpired; type not defined")
 	}
@@ -328,6 +536,12 @@ func (b *TXOut) RemainingValue(currentHeight uint32) (*uint256.Uint256, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.Value()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.Value()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.Value()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.remainingValue; type not defined")
 	}
@@ -342,6 +556,12 @@ func (b *TXOut) MakeTxIn() (*TXIn, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.MakeTxIn()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.MakeTxIn()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.MakeTxIn()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.makeTxIn; type not defined")
 	}
@@ -356,6 +576,12 @@ func (b *TXOut) Value() (*uint256.Uint256, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.Value()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.Value()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.Value()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.value; type not defined")
 	}
@@ -370,6 +596,12 @@ func (b *TXOut) ValuePlusFee() (*uint256.Uint256, error) {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.ValuePlusFee()
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.ValuePlusFee()
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.ValuePlusFee()
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.valuePlusFee; type not defined")
 	}
@@ -384,6 +616,12 @@ func (b *TXOut) ValidateFee(storage *wrapper.Storage) error {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.ValidateFee(storage)
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.ValidateFee(storage)
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.ValidateFee(storage)
 	default:
 		return errorz.ErrInvalid{}.New("txout.validateFee; type not defined")
 	}
@@ -397,6 +635,10 @@ func (b *TXOut) ValidatePreSignature() error {
 		return obj.ValidatePreSignature()
 	case b.HasValueStore():
 		return nil
+	case b.HasAtomicSwap():
+		return nil
+	case b.HasTokenStore():
+		return nil
 	default:
 		return errorz.ErrInvalid{}.New("txout.validatePreSignature; type not defined")
 	}
@@ -411,6 +653,12 @@ func (b *TXOut) ValidateSignature(currentHeight uint32, txIn *TXIn) error {
 	case b.HasValueStore():
 		obj, _ := b.ValueStore()
 		return obj.ValidateSignature(txIn)
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		return obj.ValidateSignature(currentHeight, txIn)
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		return obj.ValidateSignature(txIn)
 	default:
 		return errorz.ErrInvalid{}.New("txout.validateSignature; type not defined")
 	}
@@ -426,8 +674,17 @@ func (b *TXOut) MustBeMinedBeforeHeight() (uint32, error) {
 			return 0, err
 		}
 		return (iat * constants.EpochLength) - 1, nil
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		iat, err := obj.IssuedAt()
+		if err != nil {
+			return 0, err
+		}
+		return (iat * constants.EpochLength) - 1, nil
 	case b.HasValueStore():
 		return constants.MaxUint32, nil
+	case b.HasTokenStore():
+		return constants.MaxUint32, nil
 	default:
 		return 0, errorz.ErrInvalid{}.New("txout.mustBeMinedBeforeHeight; type not defined")
 	}
@@ -443,8 +700,17 @@ func (b *TXOut) CannotBeMinedBeforeHeight() (uint32, error) {
 			return 0, err
 		}
 		return (iat-1)*constants.EpochLength + 1, nil
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		iat, err := obj.IssuedAt()
+		if err != nil {
+			return 0, err
+		}
+		return (iat-1)*constants.EpochLength + 1, nil
 	case b.HasValueStore():
 		return 1, nil
+	case b.HasTokenStore():
+		return 1, nil
 	default:
 		return 0, errorz.ErrInvalid{}.New("txout.cannotBeMinedBeforeHeight; type not defined")
 	}
@@ -467,6 +733,20 @@ func (b *TXOut) Account() ([]byte, error) {
 			return nil, err
 		}
 		return utils.CopySlice(vso.Account), nil
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		aso, err := obj.Owner()
+		if err != nil {
+			return nil, err
+		}
+		return utils.CopySlice(aso.PrimaryOwner.Account), nil
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		tso, err := obj.Owner()
+		if err != nil {
+			return nil, err
+		}
+		return utils.CopySlice(tso.Account), nil
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.account; type not defined")
 	}
@@ -489,6 +769,20 @@ func (b *TXOut) GenericOwner() (*Owner, error) {
 			return nil, err
 		}
 		return onr, nil
+	case b.HasAtomicSwap():
+		obj, _ := b.AtomicSwap()
+		onr, err := obj.GenericOwner()
+		if err != nil {
+			return nil, err
+		}
+		return onr, nil
+	case b.HasTokenStore():
+		obj, _ := b.TokenStore()
+		onr, err := obj.GenericOwner()
+		if err != nil {
+			return nil, err
+		}
+		return onr, nil
 	default:
 		return nil, errorz.ErrInvalid{}.New("txout.genericOwner; type not defined")
 	}
@@ -74,6 +74,20 @@ func (vout Vout) ValidateTxOutIdx() error {
 				return err
 			}
 			txOutIdx = vsTxOutIdx
+		case utxo.HasAtomicSwap():
+			as, _ := utxo.AtomicSwap()
+			asTxOutIdx, err := as.TxOutIdx()
+			if err != nil {
+				return err
+			}
+			txOutIdx = asTxOutIdx
+		case utxo.HasTokenStore():
+			ts, _ := utxo.TokenStore()
+			tsTxOutIdx, err := ts.TxOutIdx()
+			if err != nil {
+				return err
+			}
+			txOutIdx = tsTxOutIdx
 		default:
 			return errorz.ErrInvalid{}.New("vout.validateTxOutIdx; bad txOutIdx: Invalid Type")
 		}
@@ -166,7 +180,7 @@ func (vout Vout) MakeTxIn() (Vin, error) {
 }
 
 // IsCleanupVout ensures we have a valid Vout object in Cleanup Tx.
-// In this case, Vout must be only one ValueStore with no fee.
+// In this case, Vout must be only one ValueStore with no fee and no lock.
 func (vout Vout) IsCleanupVout() bool {
 	if len(vout) != 1 {
 		return false
@@ -187,5 +201,9 @@ func (vout Vout) IsCleanupVout() bool {
 	if !vsFee.IsZero() {
 		return false
 	}
+	lock, err := vs.Lock()
+	if err != nil || lock != nil {
+		return false
+	}
 	return true
 }
@@ -312,6 +312,33 @@ func (b *ValueStore) Owner() (*ValueStoreOwner, error) {
 	return b.VSPreImage.Owner, nil
 }
 
+// Lock returns the ValueStoreLock of the ValueStore; it is nil if the value
+// is not locked.
+func (b *ValueStore) Lock() (*ValueStoreLock, error) {
+	vso, err := b.Owner()
+	if err != nil {
+		return nil, err
+	}
+	return vso.Lock, nil
+}
+
+// LockedValue returns the part of the value of the ValueStore which is still
+// locked at currentHeight.
+func (b *ValueStore) LockedValue(currentHeight uint32) (*uint256.Uint256, error) {
+	lock, err := b.Lock()
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return uint256.Zero(), nil
+	}
+	value, err := b.Value()
+	if err != nil {
+		return nil, err
+	}
+	return lock.LockedValue(value, utils.Epoch(currentHeight))
+}
+
 // GenericOwner returns the Owner of the ValueStore.
 func (b *ValueStore) GenericOwner() (*Owner, error) {
 	vso, err := b.Owner()
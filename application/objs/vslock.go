@@ -0,0 +1,99 @@
+package objs
+
+import (
+	"math/big"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// ValueStoreLock locks the value of a ValueStore. The value is locked before
+// UnlockEpoch and vests linearly from UnlockEpoch until VestingEndEpoch; when
+// both are equal, the whole value is unlocked at UnlockEpoch.
+type ValueStoreLock struct {
+	UnlockEpoch     uint32
+	VestingEndEpoch uint32
+}
+
+// MarshalBinary takes the ValueStoreLock object and returns the canonical
+// byte slice.
+func (vsl *ValueStoreLock) MarshalBinary() ([]byte, error) {
+	if err := vsl.Validate(); err != nil {
+		return nil, err
+	}
+	lock := []byte{}
+	lock = append(lock, utils.MarshalUint32(vsl.UnlockEpoch)...)
+	lock = append(lock, utils.MarshalUint32(vsl.VestingEndEpoch)...)
+	return lock, nil
+}
+
+// UnmarshalBinary takes a byte slice and returns the corresponding
+// ValueStoreLock object along with the remaining bytes.
+func (vsl *ValueStoreLock) UnmarshalBinary(data []byte) ([]byte, error) {
+	if vsl == nil {
+		return nil, errorz.ErrInvalid{}.New("vsl.unmarshalBinary; vsl not initialized")
+	}
+	if len(data) < 8 {
+		return nil, errorz.ErrInvalid{}.New("vsl.unmarshalBinary; extraction failed")
+	}
+	unlockEpoch, err := utils.UnmarshalUint32(data[0:4])
+	if err != nil {
+		return nil, err
+	}
+	vestingEndEpoch, err := utils.UnmarshalUint32(data[4:8])
+	if err != nil {
+		return nil, err
+	}
+	vsl.UnlockEpoch = unlockEpoch
+	vsl.VestingEndEpoch = vestingEndEpoch
+	if err := vsl.Validate(); err != nil {
+		return nil, err
+	}
+	return utils.CopySlice(data[8:]), nil
+}
+
+// Validate validates the ValueStoreLock object.
+func (vsl *ValueStoreLock) Validate() error {
+	if vsl == nil {
+		return errorz.ErrInvalid{}.New("vsl.validate; vsl not initialized")
+	}
+	if vsl.UnlockEpoch == 0 {
+		return errorz.ErrInvalid{}.New("vsl.validate; unlock epoch is zero")
+	}
+	if vsl.VestingEndEpoch < vsl.UnlockEpoch {
+		return errorz.ErrInvalid{}.New("vsl.validate; vesting ends before the unlock epoch")
+	}
+	return nil
+}
+
+// IsUnlocked returns true if the whole value is spendable at epoch.
+func (vsl *ValueStoreLock) IsUnlocked(epoch uint32) bool {
+	return epoch >= vsl.VestingEndEpoch
+}
+
+// LockedValue returns the part of value which is still locked at epoch.
+// The locked value is rounded up so that vesting never releases more than
+// the linear schedule.
+func (vsl *ValueStoreLock) LockedValue(value *uint256.Uint256, epoch uint32) (*uint256.Uint256, error) {
+	if err := vsl.Validate(); err != nil {
+		return nil, err
+	}
+	if vsl.IsUnlocked(epoch) {
+		return uint256.Zero(), nil
+	}
+	if epoch < vsl.UnlockEpoch {
+		return value.Clone(), nil
+	}
+	v, err := value.ToBigInt()
+	if err != nil {
+		return nil, err
+	}
+	remaining := big.NewInt(int64(vsl.VestingEndEpoch - epoch))
+	period := big.NewInt(int64(vsl.VestingEndEpoch - vsl.UnlockEpoch))
+	locked := new(big.Int).Mul(v, remaining)
+	locked.Add(locked, period)
+	locked.Sub(locked, big.NewInt(1))
+	locked.Div(locked, period)
+	return new(uint256.Uint256).FromBigInt(locked)
+}
@@ -0,0 +1,74 @@
+package objs
+
+import (
+	"testing"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+)
+
+func TestValueStoreLockMarshalBinary(t *testing.T) {
+	vsl := &ValueStoreLock{}
+	if _, err := vsl.MarshalBinary(); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	vsl = &ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 2}
+	if _, err := vsl.MarshalBinary(); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+	vsl = &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 7}
+	data, err := vsl.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vsl2 := &ValueStoreLock{}
+	rest, err := vsl2.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 || vsl2.UnlockEpoch != 2 || vsl2.VestingEndEpoch != 7 {
+		t.Fatal("locks do not match")
+	}
+	if _, err := vsl2.UnmarshalBinary(data[:7]); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+}
+
+func TestValueStoreLockLockedValue(t *testing.T) {
+	value, err := new(uint256.Uint256).FromUint64(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vesting := &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 5}
+	timeLock := &ValueStoreLock{UnlockEpoch: 4, VestingEndEpoch: 4}
+	testCases := []struct {
+		lock     *ValueStoreLock
+		epoch    uint32
+		expected uint64
+	}{
+		{vesting, 1, 10},
+		{vesting, 2, 10},
+		// the locked value is rounded up
+		{vesting, 3, 7},
+		{vesting, 4, 4},
+		{vesting, 5, 0},
+		{vesting, 9, 0},
+		{timeLock, 3, 10},
+		{timeLock, 4, 0},
+	}
+	for i, tc := range testCases {
+		locked, err := tc.lock.LockedValue(value, tc.epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := new(uint256.Uint256).FromUint64(tc.expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !locked.Eq(expected) {
+			t.Fatalf("invalid locked value (%v): %v vs %v", i, locked, expected)
+		}
+		if tc.lock.IsUnlocked(tc.epoch) != (tc.expected == 0) {
+			t.Fatalf("invalid unlocked state (%v)", i)
+		}
+	}
+}
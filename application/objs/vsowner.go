@@ -10,10 +10,12 @@ import (
 )
 
 // ValueStoreOwner contains information related to the owner of the ValueStore.
+// The Lock is only set for the TimeLockSVA.
 type ValueStoreOwner struct {
 	SVA       SVA
 	CurveSpec constants.CurveSpec
 	Account   []byte
+	Lock      *ValueStoreLock
 }
 
 // New makes a new ValueStoreOwner.
@@ -21,6 +23,22 @@ func (vso *ValueStoreOwner) New(acct []byte, curveSpec constants.CurveSpec) {
 	vso.SVA = ValueStoreSVA
 	vso.CurveSpec = curveSpec
 	vso.Account = utils.CopySlice(acct)
+	vso.Lock = nil
+}
+
+// NewLocked makes a new ValueStoreOwner whose value is locked by lock.
+func (vso *ValueStoreOwner) NewLocked(acct []byte, curveSpec constants.CurveSpec, lock *ValueStoreLock) error {
+	if vso == nil {
+		return errorz.ErrInvalid{}.New("vso.newLocked; vso not initialized")
+	}
+	if err := lock.Validate(); err != nil {
+		return err
+	}
+	vso.SVA = TimeLockSVA
+	vso.CurveSpec = curveSpec
+	vso.Account = utils.CopySlice(acct)
+	vso.Lock = &ValueStoreLock{UnlockEpoch: lock.UnlockEpoch, VestingEndEpoch: lock.VestingEndEpoch}
+	return vso.Validate()
 }
 
 // NewFromOwner takes an Owner object and creates the corresponding
@@ -52,6 +70,13 @@ func (vso *ValueStoreOwner) MarshalBinary() ([]byte, error) {
 	owner = append(owner, []byte{uint8(vso.SVA)}...)
 	owner = append(owner, []byte{uint8(vso.CurveSpec)}...)
 	owner = append(owner, utils.CopySlice(vso.Account)...)
+	if vso.SVA == TimeLockSVA {
+		lock, err := vso.Lock.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		owner = append(owner, lock...)
+	}
 	return owner, nil
 }
 
@@ -69,6 +94,9 @@ func (vso *ValueStoreOwner) Validate() error {
 	if err := vso.validateAccount(); err != nil {
 		return err
 	}
+	if err := vso.validateLock(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -91,12 +119,21 @@ func (vso *ValueStoreOwner) UnmarshalBinary(o []byte) error {
 	if err != nil {
 		return err
 	}
+	var lock *ValueStoreLock
+	if sva == TimeLockSVA {
+		lock = &ValueStoreLock{}
+		owner, err = lock.UnmarshalBinary(owner)
+		if err != nil {
+			return err
+		}
+	}
 	if err := extractZero(owner); err != nil {
 		return err
 	}
 	vso.SVA = sva
 	vso.CurveSpec = curveSpec
 	vso.Account = account
+	vso.Lock = lock
 	if err := vso.Validate(); err != nil {
 		return err
 	}
@@ -114,6 +151,12 @@ func (vso *ValueStoreOwner) ValidateSignature(msg []byte, sig *ValueStoreSignatu
 	if vso.CurveSpec != sig.CurveSpec {
 		return errorz.ErrInvalid{}.New("vso.validateSignature; mismatched curve spec")
 	}
+	if vso.SVA == MultiSigSVA || sig.SVA == MultiSigSVA {
+		if vso.SVA != sig.SVA {
+			return errorz.ErrInvalid{}.New("vso.validateSignature; mismatched signature verification algorithm")
+		}
+		return validateMultiSig(msg, vso.Account, vso.CurveSpec, sig.Signature)
+	}
 	signature := sig.Signature
 	switch vso.CurveSpec {
 	case constants.CurveSecp256k1:
@@ -157,7 +200,7 @@ func (vso *ValueStoreOwner) validateSVA() error {
 	if vso == nil {
 		return errorz.ErrInvalid{}.New("vso.validateSVA; vso not initialized")
 	}
-	if vso.SVA != ValueStoreSVA {
+	if vso.SVA != ValueStoreSVA && vso.SVA != MultiSigSVA && vso.SVA != TimeLockSVA {
 		return errorz.ErrInvalid{}.New("vso.validateSVA; invalid signature verification algorithm")
 	}
 	return nil
@@ -173,6 +216,19 @@ func (vso *ValueStoreOwner) validateAccount() error {
 	return nil
 }
 
+func (vso *ValueStoreOwner) validateLock() error {
+	if vso == nil {
+		return errorz.ErrInvalid{}.New("vso.validateLock; vso not initialized")
+	}
+	if vso.SVA != TimeLockSVA {
+		if vso.Lock != nil {
+			return errorz.ErrInvalid{}.New("vso.validateLock; lock is only valid for the time lock SVA")
+		}
+		return nil
+	}
+	return vso.Lock.Validate()
+}
+
 // Sign signs message msg with signer s.
 func (vso *ValueStoreOwner) Sign(msg []byte, s Signer) (*ValueStoreSignature, error) {
 	sig := &ValueStoreSignature{
@@ -224,6 +280,10 @@ func (vss *ValueStoreSignature) UnmarshalBinary(signature []byte) error {
 		return err
 	}
 	vss.CurveSpec = curveSpec
+	if sva == MultiSigSVA {
+		vss.Signature = utils.CopySlice(signature)
+		return vss.Validate()
+	}
 	signature, null, err := extractSignature(signature, curveSpec)
 	if err != nil {
 		return err
@@ -259,6 +319,9 @@ func (vss *ValueStoreSignature) Validate() error {
 	if err := vss.validateCurveSpec(); err != nil {
 		return err
 	}
+	if vss.SVA == MultiSigSVA {
+		return validateMultiSigLen(vss.Signature, vss.CurveSpec)
+	}
 	return validateSignatureLen(vss.Signature, vss.CurveSpec)
 }
 
@@ -266,7 +329,7 @@ func (vss *ValueStoreSignature) validateSVA() error {
 	if vss == nil {
 		return errorz.ErrInvalid{}.New("vss.validateSVA; vss not initialized")
 	}
-	if vss.SVA != ValueStoreSVA {
+	if vss.SVA != ValueStoreSVA && vss.SVA != MultiSigSVA {
 		return errorz.ErrInvalid{}.New("vss.validateSVA; invalid signature verification algorithm")
 	}
 	return nil
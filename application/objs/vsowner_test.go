@@ -412,3 +412,63 @@ func TestVSSignatureUnmarshalBinary(t *testing.T) {
 		t.Fatal("Should pass")
 	}
 }
+
+func TestVSOwnerLocked(t *testing.T) {
+	acct := make([]byte, constants.OwnerLen)
+	vso := &ValueStoreOwner{}
+	if err := vso.NewLocked(acct, constants.CurveSecp256k1, &ValueStoreLock{}); err == nil {
+		t.Fatal("Should raise an error (0)")
+	}
+	if err := vso.NewLocked(acct, constants.CurveSecp256k1, &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 4}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := vso.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2+constants.OwnerLen+8 {
+		t.Fatal("invalid length of locked owner")
+	}
+	vso2 := &ValueStoreOwner{}
+	if err := vso2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if vso2.SVA != TimeLockSVA || vso2.Lock == nil || vso2.Lock.UnlockEpoch != 2 || vso2.Lock.VestingEndEpoch != 4 {
+		t.Fatal("locked owners do not match")
+	}
+	if err := vso2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("Should raise an error (1)")
+	}
+
+	// a lock requires the time lock SVA
+	vso2.SVA = ValueStoreSVA
+	if err := vso2.Validate(); err == nil {
+		t.Fatal("Should raise an error (2)")
+	}
+	vso2.SVA = TimeLockSVA
+	vso2.Lock = nil
+	if err := vso2.Validate(); err == nil {
+		t.Fatal("Should raise an error (3)")
+	}
+
+	// locked ValueStores are signed by the account
+	signer := &crypto.Secp256k1Signer{}
+	if err := signer.SetPrivk(crypto.Hasher([]byte("secret"))); err != nil {
+		t.Fatal(err)
+	}
+	pubk, err := signer.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vso.NewLocked(crypto.GetAccount(pubk), constants.CurveSecp256k1, &ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 2}); err != nil {
+		t.Fatal(err)
+	}
+	msg := crypto.Hasher([]byte("msg"))
+	sig, err := vso.Sign(msg, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vso.ValidateSignature(msg, sig); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -354,6 +354,88 @@ func TestDeleteMined(t *testing.T) {
 	mustNotAdd(t, hndlr, tx, 1)
 }
 
+func TestGetConsumers(t *testing.T) {
+	hndlr, _, cleanup := setup(t)
+	defer cleanup()
+	vout, tx := makeTxInitial()
+	mustAddTx(t, hndlr, tx, 1)
+	// a distinct tx spending the same utxos
+	base := makeTxConsuming(vout)
+	tx2 := &objs.Tx{
+		Vin:  base.Vin,
+		Vout: base.Vout,
+		Fee:  base.Fee,
+		Memo: []byte("tx2"),
+	}
+	err := tx2.SetTxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		vs, err := vout[i].ValueStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = vs.Sign(tx2.Vin[i], testingOwner())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustAddTx(t, hndlr, tx2, 1)
+	utxoIDs, err := tx.ConsumedUTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := tx.GeneratedUTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumers, err := hndlr.GetConsumers(nil, append(utxoIDs, generated[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(consumers))
+	assert.Equal(t, 2, len(consumers[0]))
+	assert.Equal(t, 2, len(consumers[1]))
+	assert.Equal(t, 0, len(consumers[2]))
+	mustDelTx(t, hndlr, tx2)
+	consumers, err = hndlr.GetConsumers(nil, utxoIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := tx.TxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [][]byte{txHash}, consumers[0])
+	assert.Equal(t, [][]byte{txHash}, consumers[1])
+}
+
+func TestRecentlyMined(t *testing.T) {
+	hndlr, _, cleanup := setup(t)
+	defer cleanup()
+	_, tx := makeTxInitial()
+	mustAddTx(t, hndlr, tx, 1)
+	txHash, err := tx.TxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined, err := hndlr.RecentlyMined(nil, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, mined)
+	err = hndlr.DeleteMined(nil, 1, [][]byte{txHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined, err = hndlr.RecentlyMined(nil, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, mined)
+}
+
 func TestMissing(t *testing.T) {
 	hndlr, _, cleanup := setup(t)
 	defer cleanup()
@@ -141,6 +141,11 @@ func (pti *PendingTxIndexer) DropBefore(txn *badger.Txn, epoch uint32) ([][]byte
 	return txHashes, nil
 }
 
+// GetConsumers returns the txhashes in the indexer which consume the utxoID
+func (pti *PendingTxIndexer) GetConsumers(txn *badger.Txn, utxoID []byte) ([][]byte, error) {
+	return pti.reflink.GetTxHashes(txn, utxoID)
+}
+
 // GetEpoch returns the epoch when the tx expires
 func (pti *PendingTxIndexer) GetEpoch(txn *badger.Txn, txHash []byte) (uint32, error) {
 	return pti.expiration.GetEpoch(txn, txHash)
@@ -147,6 +147,51 @@ func (pt *Handler) Contains(txnState *badger.Txn, currentHeight uint32, txHashes
 	return missing, nil
 }
 
+// GetConsumers returns, for each utxoID, the txHashes of the txs in the pool
+// which consume it.
+func (pt *Handler) GetConsumers(txnState *badger.Txn, utxoIDs [][]byte) ([][][]byte, error) {
+	consumers := make([][][]byte, len(utxoIDs))
+	err := pt.db.View(func(txn *badger.Txn) error {
+		for i := 0; i < len(utxoIDs); i++ {
+			txHashes, err := pt.indexer.GetConsumers(txn, utils.CopySlice(utxoIDs[i]))
+			if err != nil {
+				utils.DebugTrace(pt.logger, err)
+				return err
+			}
+			consumers[i] = txHashes
+		}
+		return nil
+	})
+	if err != nil {
+		utils.DebugTrace(pt.logger, err)
+		return nil, err
+	}
+	return consumers, nil
+}
+
+// RecentlyMined returns true if the tx was mined recently enough that the pool
+// still refuses to add it again.
+func (pt *Handler) RecentlyMined(txnState *badger.Txn, txHash []byte) (bool, error) {
+	mined := false
+	err := pt.db.View(func(txn *badger.Txn) error {
+		cooldownKey := pt.makePendingTxCooldownKey(txHash)
+		_, err := utils.GetValue(txn, cooldownKey)
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			utils.DebugTrace(pt.logger, err)
+			return err
+		}
+		mined = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return mined, nil
+}
+
 // DeleteMined removes all specified transactions from the pool as well as any
 // other transactions that reference a consumed UTXO from the set of passed in
 // transactions.
@@ -231,8 +231,13 @@ func (tm *txHandler) PendingTxAdd(txn *badger.Txn, chainID, height uint32, tx []
 		utils.DebugTrace(tm.logger, err)
 		return err
 	}
-	if len(missing) == 0 {
-		return errorz.ErrInvalid{}.New("txhandler.PendingTxAdd; duplicate")
+	poolSteps, err := tm.poolSteps(txn, txHashes, missing)
+	if err != nil {
+		return err
+	}
+	if err := objs.RunPendingSteps(poolSteps); err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return err
 	}
 	missingMap := make(map[string]int)
 	for i := 0; i < len(txHashes); i++ {
@@ -241,6 +246,9 @@ func (tm *txHandler) PendingTxAdd(txn *badger.Txn, chainID, height uint32, tx []
 	consumedUTXOs, err := tm.IsValid(txn, tx, height)
 	if err != nil {
 		utils.DebugTrace(tm.logger, err)
+		if errorz.CodeOf(err) == errorz.CodeInvalid {
+			return errorz.WithCode(errorz.CodeTxState, err)
+		}
 		return err
 	}
 	if err := txs.PostValidatePending(height, consumedUTXOs, tm.storage); err != nil {
@@ -266,10 +274,44 @@ func (tm *txHandler) PendingTxAdd(txn *badger.Txn, chainID, height uint32, tx []
 	return nil
 }
 
+// poolSteps returns the rules of the pending tx pool itself for the txs with
+// txHashes, of which the txs with the missing txHashes are not in the pool.
+func (tm *txHandler) poolSteps(txn *badger.Txn, txHashes, missing [][]byte) ([]objs.PendingStep, error) {
+	mined := false
+	for i := 0; i < len(txHashes) && !mined; i++ {
+		var err error
+		mined, err = tm.pTxHdlr.RecentlyMined(txn, txHashes[i])
+		if err != nil {
+			utils.DebugTrace(tm.logger, err)
+			return nil, err
+		}
+	}
+	return []objs.PendingStep{
+		{Code: errorz.CodeTxDuplicate, Validate: func() error {
+			if len(missing) == 0 {
+				return errorz.ErrInvalid{}.New("txhandler.poolSteps; duplicate")
+			}
+			return nil
+		}},
+		{Code: errorz.CodeTxAlreadyMined, Validate: func() error {
+			if mined {
+				return errorz.ErrInvalid{}.New("txhandler.poolSteps; already mined")
+			}
+			return nil
+		}},
+	}, nil
+}
+
 func (tm *txHandler) MinedTxGet(txn *badger.Txn, txHash [][]byte) ([]*objs.Tx, [][]byte, error) {
 	return tm.mTxHdlr.Get(txn, txHash)
 }
 
+// MinedTxGetByMemo returns the txHashes of the mined transactions carrying
+// the memo with memoHash.
+func (tm *txHandler) MinedTxGetByMemo(txn *badger.Txn, memoHash []byte, maxCount int, startTxHash []byte) ([][]byte, []byte, error) {
+	return tm.mTxHdlr.GetTxHashesForMemo(txn, memoHash, maxCount, startTxHash)
+}
+
 // PendingTxGet returns a list of transactions and a list of missing
 // transaction hashes from the pending transaction pool.
 func (tm *txHandler) PendingTxGet(txn *badger.Txn, height uint32, txHash [][]byte) ([]*objs.Tx, [][]byte, error) {
@@ -292,10 +334,16 @@ func (tm *txHandler) UTXOGetData(txn *badger.Txn, owner *objs.Owner, dataIdx []b
 	return tm.uHdlr.GetData(txn, owner, dataIdx)
 }
 
+// UTXOGetDataStore returns the DataStore utxo stored by owner at the data
+// index.
+func (tm *txHandler) UTXOGetDataStore(txn *badger.Txn, owner *objs.Owner, dataIdx []byte) (*objs.TXOut, error) {
+	return tm.uHdlr.GetDataStore(txn, owner, dataIdx)
+}
+
 // GetValueForOwner returns a list of utxoIDs (of ValueStores) and the total returned value.
 // The purpose of this function is to return a list UTXOs of a certain value
-// which may be consumed within a transaction.
-func (tm *txHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minValue *uint256.Uint256, pt *objs.PaginationToken) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
+// which may be consumed within a transaction at currentHeight.
+func (tm *txHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, minValue *uint256.Uint256, pt *objs.PaginationToken) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
 	const maxCount = 256
 	allIds := [][]byte{}
 
@@ -315,6 +363,12 @@ func (tm *txHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minVal
 	}{
 		{tm.uHdlr.GetValueForOwner, objs.LastPaginatedUtxo},
 		{tm.dHdlr.GetValueForOwner, objs.LastPaginatedDeposit},
+		{func(txn *badger.Txn, owner *objs.Owner, minValue *uint256.Uint256, maxCount int, lastKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+			return tm.uHdlr.GetRefundValueForOwner(txn, owner, currentHeight, minValue, maxCount, lastKey)
+		}, objs.LastPaginatedAtomicSwapRefund},
+		{func(txn *badger.Txn, owner *objs.Owner, minValue *uint256.Uint256, maxCount int, lastKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+			return tm.uHdlr.GetUnlockedValueForOwner(txn, owner, currentHeight, minValue, maxCount, lastKey)
+		}, objs.LastPaginatedUnlockedValue},
 	}
 
 	started := pt == nil
@@ -329,7 +383,7 @@ func (tm *txHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minVal
 				started = true
 				lastKey = pt.LastKey
 			} else {
-				break
+				continue
 			}
 		}
 
@@ -360,6 +414,52 @@ func (tm *txHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minVal
 	return allIds, totalValue, nil, nil
 }
 
+// GetAssetValueForOwner returns a list of utxoIDs of TokenStores holding the
+// asset assetID and their total amount.
+func (tm *txHandler) GetAssetValueForOwner(txn *badger.Txn, owner *objs.Owner, assetID []byte, minAmount *uint256.Uint256, pt *objs.PaginationToken) ([][]byte, *uint256.Uint256, *objs.PaginationToken, error) {
+	const maxCount = 256
+	totalAmount := uint256.Zero()
+	var lastKey []byte
+	if pt != nil {
+		if pt.LastPaginatedType != objs.LastPaginatedAsset {
+			return nil, nil, nil, errorz.ErrInvalid{}.New("txHandler.getAssetValueForOwner; invalid pagination token")
+		}
+		var err error
+		totalAmount, err = totalAmount.Add(totalAmount, pt.TotalValue)
+		if err != nil {
+			utils.DebugTrace(tm.logger, err)
+			return nil, nil, nil, err
+		}
+		lastKey = pt.LastKey
+	}
+	remainder, err := new(uint256.Uint256).Sub(minAmount, totalAmount)
+	if err != nil {
+		// underflow -> amount exceeded
+		return [][]byte{}, totalAmount, nil, nil
+	}
+	utxoIDs, amount, lk, err := tm.uHdlr.GetAssetValueForOwner(txn, owner, assetID, remainder, maxCount, lastKey)
+	if err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return nil, nil, nil, err
+	}
+	totalAmount, err = totalAmount.Add(totalAmount, amount)
+	if err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return nil, nil, nil, err
+	}
+	if lk != nil {
+		return utxoIDs, totalAmount, &objs.PaginationToken{LastPaginatedType: objs.LastPaginatedAsset, TotalValue: totalAmount, LastKey: lk}, nil
+	}
+	return utxoIDs, totalAmount, nil, nil
+}
+
+// GetLockedValueForOwner returns the value of the time locked ValueStores of
+// owner which is still locked at currentHeight and the value which has
+// already vested.
+func (tm *txHandler) GetLockedValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32) (*uint256.Uint256, *uint256.Uint256, error) {
+	return tm.uHdlr.GetLockedValueForOwner(txn, owner, currentHeight)
+}
+
 // UTXOGet returns a list of UTXOs from a list of utxoIDs.
 // The returned UTXOs are those which are present;
 // any missing UTXOs are not specified.
@@ -384,6 +484,34 @@ func (tm *txHandler) UTXOGet(txn *badger.Txn, utxoIDs [][]byte) ([]*objs.TXOut,
 	return f, nil
 }
 
+// UTXOGetAtHeight returns the UTXOs which were in the state at height, as
+// well as the utxoIDs which were not. Deposits are not returned.
+func (tm *txHandler) UTXOGetAtHeight(txn *badger.Txn, height uint32, utxoIDs [][]byte) ([]*objs.TXOut, [][]byte, error) {
+	return tm.uHdlr.GetAtHeight(txn, height, utxoIDs)
+}
+
+// GetStateRootForHeight returns the StateRoot at height.
+func (tm *txHandler) GetStateRootForHeight(txn *badger.Txn, height uint32) ([]byte, error) {
+	return tm.uHdlr.GetStateRootForHeight(txn, height)
+}
+
+// DepositGet returns a deposit by utxoID and whether it has been spent.
+// A nil TXOut is returned if the deposit is unknown.
+func (tm *txHandler) DepositGet(txn *badger.Txn, utxoID []byte) (*objs.TXOut, bool, error) {
+	found, _, spent, err := tm.dHdlr.Get(txn, [][]byte{utxoID})
+	if err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return nil, false, err
+	}
+	if len(found) > 0 {
+		return found[0], false, nil
+	}
+	if len(spent) > 0 {
+		return spent[0], true, nil
+	}
+	return nil, false, nil
+}
+
 // GetSnapShotStateData returns a list of found UTXOs (deposits and UTXOs) and spent deposits.
 func (tm *txHandler) GetSnapShotStateData(txn *badger.Txn, utxoIDs [][]byte) ([]*objs.TXOut, error) {
 	f := []*objs.TXOut{}
@@ -413,6 +541,11 @@ func (tm *txHandler) PaginateDataByOwner(txn *badger.Txn, owner *objs.Owner, hei
 	return tm.uHdlr.PaginateDataByOwner(txn, owner, height, numItems, startIndex)
 }
 
+// QueryDataByOwner returns the datastores of an owner selected by a query.
+func (tm *txHandler) QueryDataByOwner(txn *badger.Txn, owner *objs.Owner, height uint32, q *objs.DataStoreQuery, withMetadata bool) ([]*objs.PaginationResponse, []byte, error) {
+	return tm.uHdlr.QueryDataByOwner(txn, owner, height, q, withMetadata)
+}
+
 // GetHeightForTx returns height for a mined transaction.
 func (tm *txHandler) GetHeightForTx(txn *badger.Txn, txHash []byte) (uint32, error) {
 	return tm.mTxHdlr.GetHeightForTx(txn, txHash)
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"bytes"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/alicenet/alicenet/application/objs"
+	"github.com/alicenet/alicenet/application/wrapper"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// TxValidationError is a rule broken by a transaction. Code is one of the
+// CodeTx codes of errorz, as returned by PendingTxAdd. Input and Output are
+// the indices of the offending input or output; they are -1 when the error
+// concerns the transaction as a whole.
+type TxValidationError struct {
+	Code    errorz.Code
+	Input   int
+	Output  int
+	Message string
+}
+
+// TxConflict lists the txs in the pending tx pool which consume
+// the same UTXO as an input of a transaction.
+type TxConflict struct {
+	Input    int
+	TxHashes [][]byte
+}
+
+// TxValidation is the result of validating a transaction against
+// the current state without adding it to the pending tx pool.
+type TxValidation struct {
+	TxHash    []byte
+	Errors    []*TxValidationError
+	Conflicts []*TxConflict
+}
+
+// Valid returns true if the transaction would be accepted
+// by the pending tx pool.
+func (v *TxValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+func (v *TxValidation) addTxError(code errorz.Code, err error) {
+	v.Errors = append(v.Errors, &TxValidationError{Code: code, Input: -1, Output: -1, Message: err.Error()})
+}
+
+func (v *TxValidation) addInputError(code errorz.Code, idx int, err error) {
+	v.Errors = append(v.Errors, &TxValidationError{Code: code, Input: idx, Output: -1, Message: err.Error()})
+}
+
+func (v *TxValidation) addOutputError(code errorz.Code, idx int, err error) {
+	v.Errors = append(v.Errors, &TxValidationError{Code: code, Input: -1, Output: idx, Message: err.Error()})
+}
+
+// addStepError reports the broken rule of step, attributed to the offending
+// outputs or inputs when the rule allows it.
+func (v *TxValidation) addStepError(tx *objs.Tx, step objs.PendingStep, err error, height uint32, refUTXOs objs.Vout, storage *wrapper.Storage) {
+	errors := len(v.Errors)
+	switch step.Code {
+	case errorz.CodeTxOutputPreSignature:
+		for j := 0; j < len(tx.Vout); j++ {
+			if err := tx.Vout[j].ValidatePreSignature(); err != nil {
+				v.addOutputError(errorz.CodeTxOutputPreSignature, j, err)
+			}
+		}
+	case errorz.CodeTxFee:
+		for j := 0; j < len(tx.Vout); j++ {
+			if err := tx.Vout[j].ValidateFee(storage); err != nil {
+				v.addOutputError(errorz.CodeTxOutputFee, j, err)
+			}
+		}
+	case errorz.CodeTxSignature:
+		for i := 0; i < len(tx.Vin); i++ {
+			if err := refUTXOs[i].ValidateSignature(height, tx.Vin[i]); err != nil {
+				v.addInputError(errorz.CodeTxInputSignature, i, err)
+			}
+		}
+	}
+	if len(v.Errors) == errors {
+		v.addTxError(step.Code, err)
+	}
+}
+
+// ValidateTx runs the rules of PendingTxAdd for tx against the current state
+// without adding tx to the pending tx pool. Unlike PendingTxAdd, every rule
+// is run and the broken ones are reported in the returned TxValidation,
+// attributed to inputs and outputs where possible; an error is only returned
+// when the rules could not be run.
+func (tm *txHandler) ValidateTx(txn *badger.Txn, chainID, height uint32, tx *objs.Tx) (*TxValidation, error) {
+	v := &TxValidation{}
+	if err := tx.ValidatePendingFields(); err != nil {
+		v.addTxError(errorz.CodeTxMalformed, err)
+		return v, nil
+	}
+	txHash, err := tx.TxHash()
+	if err != nil {
+		v.addTxError(errorz.CodeTxMalformed, err)
+		return v, nil
+	}
+	v.TxHash = txHash
+	utxoIDs, err := tx.ConsumedUTXOID()
+	if err != nil {
+		v.addTxError(errorz.CodeTxMalformed, err)
+		return v, nil
+	}
+	generatedUTXOIDs, err := tx.GeneratedUTXOID()
+	if err != nil {
+		v.addTxError(errorz.CodeTxMalformed, err)
+		return v, nil
+	}
+
+	// the rules of PreValidatePending
+	for _, step := range tx.PreValidatePendingSteps(chainID) {
+		if err := step.Validate(); err != nil {
+			v.addStepError(tx, step, err, height, nil, tm.storage)
+		}
+	}
+
+	// the rules of the pending tx pool
+	missing, err := tm.pTxHdlr.Contains(txn, height, [][]byte{txHash})
+	if err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return nil, err
+	}
+	poolSteps, err := tm.poolSteps(txn, [][]byte{txHash}, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range poolSteps {
+		if err := step.Validate(); err != nil {
+			v.addStepError(tx, step, err, height, nil, tm.storage)
+		}
+	}
+	consumers, err := tm.pTxHdlr.GetConsumers(txn, utxoIDs)
+	if err != nil {
+		utils.DebugTrace(tm.logger, err)
+		return nil, err
+	}
+	for i := 0; i < len(consumers); i++ {
+		conflict := &TxConflict{Input: i}
+		for _, consumer := range consumers[i] {
+			if !bytes.Equal(consumer, txHash) {
+				conflict.TxHashes = append(conflict.TxHashes, consumer)
+			}
+		}
+		if len(conflict.TxHashes) > 0 {
+			v.Conflicts = append(v.Conflicts, conflict)
+		}
+	}
+
+	// the checks of the deposit and utxo handlers;
+	// refUTXOs is ordered as the consumed UTXOs returned by IsValid
+	stateErrors := len(v.Errors)
+	var deposits, utxos objs.Vout
+	isDeposit := tx.ConsumedIsDeposit()
+	for i := 0; i < len(utxoIDs); i++ {
+		utxoID := utils.CopySlice(utxoIDs[i])
+		inTrie, err := tm.uHdlr.TrieContains(txn, utxoID)
+		if err != nil {
+			utils.DebugTrace(tm.logger, err)
+			return nil, err
+		}
+		if isDeposit[i] {
+			found, missing, spent, err := tm.dHdlr.Get(txn, [][]byte{utxoID})
+			if err != nil {
+				utils.DebugTrace(tm.logger, err)
+				return nil, err
+			}
+			switch {
+			case len(missing) > 0:
+				v.addInputError(errorz.CodeTxInputMissing, i, errorz.ErrInvalid{}.New("txhandler.ValidateTx; deposit is missing"))
+			case len(spent) > 0 || inTrie:
+				v.addInputError(errorz.CodeTxInputSpent, i, errorz.ErrInvalid{}.New("txhandler.ValidateTx; deposit is already spent"))
+			default:
+				deposits = append(deposits, found...)
+			}
+			continue
+		}
+		if !inTrie {
+			v.addInputError(errorz.CodeTxInputMissing, i, errorz.ErrInvalid{}.New("txhandler.ValidateTx; consumed utxo not in trie"))
+			continue
+		}
+		found, _, err := tm.uHdlr.Get(txn, [][]byte{utxoID})
+		if err != nil {
+			utils.DebugTrace(tm.logger, err)
+			return nil, err
+		}
+		utxos = append(utxos, found...)
+	}
+	for j := 0; j < len(generatedUTXOIDs); j++ {
+		inTrie, err := tm.uHdlr.TrieContains(txn, utils.CopySlice(generatedUTXOIDs[j]))
+		if err != nil {
+			utils.DebugTrace(tm.logger, err)
+			return nil, err
+		}
+		if inTrie {
+			v.addOutputError(errorz.CodeTxOutputExists, j, errorz.ErrInvalid{}.New("txhandler.ValidateTx; utxoID already in trie"))
+		}
+	}
+	refUTXOs := append(deposits, utxos...)
+
+	// the rules of PostValidatePending, once every input is known
+	if len(refUTXOs) == len(tx.Vin) {
+		for _, step := range tx.PostValidatePendingSteps(height, refUTXOs, tm.storage) {
+			if err := step.Validate(); err != nil {
+				v.addStepError(tx, step, err, height, refUTXOs, tm.storage)
+			}
+		}
+	}
+
+	// any remaining reason for IsValid to reject the tx
+	if _, err := tm.IsValid(txn, []*objs.Tx{tx}, height); err != nil {
+		if len(v.Errors) == stateErrors {
+			v.addTxError(errorz.CodeTxState, err)
+		}
+	}
+	return v, nil
+}
@@ -33,14 +33,19 @@ import (
 // TODO SET UP PRUNING
 
 // NewUTXOHandler constructs a new UTXOHandler.
-func NewUTXOHandler(dB *badger.DB) *UTXOHandler {
+func NewUTXOHandler(dB *badger.DB, storage *wrapper.Storage) *UTXOHandler {
 	return &UTXOHandler{
-		logger:     logging.GetLogger(constants.LoggerApp),
-		trie:       utxotrie.NewUTXOTrie(dB),
-		expIndex:   indexer.NewExpSizeIndex(dbprefix.PrefixMinedUTXOEpcKey, dbprefix.PrefixMinedUTXOEpcRefKey),
-		dataIndex:  indexer.NewDataIndex(dbprefix.PrefixMinedUTXODataKey, dbprefix.PrefixMinedUTXODataRefKey),
-		valueIndex: indexer.NewValueIndex(dbprefix.PrefixMinedUTXOValueKey, dbprefix.PrefixMinedUTXOValueRefKey),
-		db:         dB,
+		logger:           logging.GetLogger(constants.LoggerApp),
+		trie:             utxotrie.NewUTXOTrie(dB),
+		expIndex:         indexer.NewExpSizeIndex(dbprefix.PrefixMinedUTXOEpcKey, dbprefix.PrefixMinedUTXOEpcRefKey),
+		dataIndex:        indexer.NewDataIndex(dbprefix.PrefixMinedUTXODataKey, dbprefix.PrefixMinedUTXODataRefKey),
+		valueIndex:       indexer.NewValueIndex(dbprefix.PrefixMinedUTXOValueKey, dbprefix.PrefixMinedUTXOValueRefKey),
+		swapValueIndex:   indexer.NewValueIndex(dbprefix.PrefixMinedUTXOSwapValueKey, dbprefix.PrefixMinedUTXOSwapValueRefKey),
+		altValueIndex:    indexer.NewValueIndex(dbprefix.PrefixMinedUTXOAltValueKey, dbprefix.PrefixMinedUTXOAltValueRefKey),
+		lockedValueIndex: indexer.NewValueIndex(dbprefix.PrefixMinedUTXOLockedValueKey, dbprefix.PrefixMinedUTXOLockedValueRefKey),
+		assetIndex:       indexer.NewAssetIndex(dbprefix.PrefixMinedUTXOAssetKey, dbprefix.PrefixMinedUTXOAssetRefKey),
+		db:               dB,
+		storage:          storage,
 	}
 }
 
@@ -53,6 +58,18 @@ type UTXOHandler struct {
 	expIndex   *indexer.ExpSizeIndex
 	dataIndex  *indexer.DataIndex
 	valueIndex *indexer.ValueIndex
+	// swapValueIndex indexes the AtomicSwaps by their recipient; they are
+	// kept apart from the valueIndex since they can only be claimed with
+	// the preimage of the hash lock
+	swapValueIndex *indexer.ValueIndex
+	// altValueIndex indexes the AtomicSwaps by their refunder
+	altValueIndex *indexer.ValueIndex
+	// lockedValueIndex indexes the time locked ValueStores by their owner
+	lockedValueIndex *indexer.ValueIndex
+	// assetIndex indexes the TokenStores by asset and owner
+	assetIndex *indexer.AssetIndex
+	// storage tells whether the application fork is active
+	storage *wrapper.Storage
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -139,6 +156,17 @@ func (ut *UTXOHandler) IsValid(txn *badger.Txn, txs objs.TxVec, currentHeight ui
 			utils.DebugTrace(ut.logger, err)
 			return nil, err
 		}
+		// the spend path of an AtomicSwap depends on the height at which it
+		// is consumed; a claim by the recipient which is still pending once
+		// the AtomicSwap has expired is no longer valid
+		if err := ut.validateAtomicSwapSpends(tx, utxos, currentHeight); err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return nil, err
+		}
+		if err := tx.ValidateDataStoreUpdates(currentHeight, refUTXOs, ut.storage); err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return nil, err
+		}
 		for j := 0; j < len(utxos); j++ {
 			utxo = utxos[j]
 			if utxo.HasDataStore() {
@@ -354,6 +382,12 @@ func (ut *UTXOHandler) TrieContains(txn *badger.Txn, utxoID []byte) (bool, error
 	return true, nil
 }
 
+// GetStateRootForHeight returns the StateRoot at height;
+// badger.ErrKeyNotFound is returned if the state at height is not retained.
+func (ut *UTXOHandler) GetStateRootForHeight(txn *badger.Txn, height uint32) ([]byte, error) {
+	return ut.trie.GetStateRootForHeight(txn, height)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 ///////////OPERATORS ON UTXO STORAGE////////////////////////////////////////////
@@ -393,8 +427,61 @@ func (ut *UTXOHandler) Get(txn *badger.Txn, utxoIDs [][]byte) ([]*objs.TXOut, []
 	return f, m, nil
 }
 
+// GetAtHeight returns the UTXOs which were in the state trie at height,
+// as well as the utxoIDs which were not. Consumed UTXOs are kept in storage,
+// so the UTXOs of any retained height may be returned.
+func (ut *UTXOHandler) GetAtHeight(txn *badger.Txn, height uint32, utxoIDs [][]byte) ([]*objs.TXOut, [][]byte, error) {
+	missing, err := ut.trie.ContainsAtHeight(txn, height, utxoIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	isMissing := make(map[string]bool, len(missing))
+	for i := 0; i < len(missing); i++ {
+		isMissing[string(missing[i])] = true
+	}
+	f := []*objs.TXOut{}
+	m := [][]byte{}
+	for i := 0; i < len(utxoIDs); i++ {
+		utxoID := utils.CopySlice(utxoIDs[i])
+		if isMissing[string(utxoID)] {
+			m = append(m, utxoID)
+			continue
+		}
+		utxo, err := ut.getInternal(txn, utils.CopySlice(utxoID))
+		if err != nil {
+			if err != badger.ErrKeyNotFound {
+				utils.DebugTrace(ut.logger, err)
+				return nil, nil, err
+			}
+			m = append(m, utxoID)
+			continue
+		}
+		f = append(f, utxo)
+	}
+	return f, m, nil
+}
+
 // GetData returns the data stored in a utxo by owner and the data index.
 func (ut *UTXOHandler) GetData(txn *badger.Txn, owner *objs.Owner, dataIdx []byte) ([]byte, error) {
+	utxo, err := ut.GetDataStore(txn, owner, dataIdx)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := utxo.DataStore()
+	if err != nil {
+		utils.DebugTrace(ut.logger, err)
+		return nil, err
+	}
+	rd, err := ds.RawData()
+	if err != nil {
+		utils.DebugTrace(ut.logger, err)
+		return nil, err
+	}
+	return rd, nil
+}
+
+// GetDataStore returns the DataStore UTXO stored at the index of the owner.
+func (ut *UTXOHandler) GetDataStore(txn *badger.Txn, owner *objs.Owner, dataIdx []byte) (*objs.TXOut, error) {
 	utxoID, err := ut.dataIndex.GetUTXOID(txn, owner, dataIdx)
 	if err != nil {
 		utils.DebugTrace(ut.logger, err)
@@ -405,20 +492,10 @@ func (ut *UTXOHandler) GetData(txn *badger.Txn, owner *objs.Owner, dataIdx []byt
 		utils.DebugTrace(ut.logger, err)
 		return nil, err
 	}
-	if utxo.HasDataStore() {
-		ds, err := utxo.DataStore()
-		if err != nil {
-			utils.DebugTrace(ut.logger, err)
-			return nil, err
-		}
-		rd, err := ds.RawData()
-		if err != nil {
-			utils.DebugTrace(ut.logger, err)
-			return nil, err
-		}
-		return rd, nil
+	if !utxo.HasDataStore() {
+		return nil, errorz.ErrInvalid{}.New("utxoHandler.GetDataStore; not a datastore")
 	}
-	return nil, errorz.ErrInvalid{}.New("utxoHandler.GetData; not a datastore")
+	return utxo, nil
 }
 
 // GetExpiredForProposal returns a list of UTXOs, the IDs of those UTXOs, and
@@ -526,6 +603,137 @@ func (ut *UTXOHandler) GetValueForOwner(txn *badger.Txn, owner *objs.Owner, minV
 	return ut.valueIndex.GetValueForOwner(txn, owner, minValue, nil, maxCount, startKey)
 }
 
+// GetClaimValueForOwner allows a list of utxoIDs to be returned that are
+// equal or greater than the value passed as minValue, and are AtomicSwaps
+// which may still be claimed by owner at currentHeight.
+func (ut *UTXOHandler) GetClaimValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, minValue *uint256.Uint256, maxCount int, startKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+	excludeFn := func(utxoID []byte) (bool, error) {
+		return ut.isAtomicSwapExpired(txn, utxoID, currentHeight)
+	}
+	return ut.swapValueIndex.GetValueForOwner(txn, owner, minValue, excludeFn, maxCount, startKey)
+}
+
+// GetRefundValueForOwner allows a list of utxoIDs to be returned that are
+// equal or greater than the value passed as minValue, and are AtomicSwaps
+// which have expired at currentHeight and may be refunded to owner.
+func (ut *UTXOHandler) GetRefundValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, minValue *uint256.Uint256, maxCount int, startKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+	excludeFn := func(utxoID []byte) (bool, error) {
+		expired, err := ut.isAtomicSwapExpired(txn, utxoID, currentHeight)
+		if err != nil {
+			return false, err
+		}
+		return !expired, nil
+	}
+	return ut.altValueIndex.GetValueForOwner(txn, owner, minValue, excludeFn, maxCount, startKey)
+}
+
+// GetAssetValueForOwner allows a list of utxoIDs to be returned that hold
+// the asset assetID and whose amounts sum to at least minAmount.
+func (ut *UTXOHandler) GetAssetValueForOwner(txn *badger.Txn, owner *objs.Owner, assetID []byte, minAmount *uint256.Uint256, maxCount int, startKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+	return ut.assetIndex.GetAmountForOwner(txn, assetID, owner, minAmount, nil, maxCount, startKey)
+}
+
+// GetUnlockedValueForOwner allows a list of utxoIDs to be returned that are
+// equal or greater than the value passed as minValue, and are time locked
+// ValueStores of owner whose value is fully unlocked at currentHeight.
+func (ut *UTXOHandler) GetUnlockedValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, minValue *uint256.Uint256, maxCount int, startKey []byte) ([][]byte, *uint256.Uint256, []byte, error) {
+	epoch := utils.Epoch(currentHeight)
+	excludeFn := func(utxoID []byte) (bool, error) {
+		lock, _, err := ut.getLock(txn, utxoID)
+		if err != nil {
+			return false, err
+		}
+		return !lock.IsUnlocked(epoch), nil
+	}
+	return ut.lockedValueIndex.GetValueForOwner(txn, owner, minValue, excludeFn, maxCount, startKey)
+}
+
+// GetLockedValueForOwner returns the value of the time locked ValueStores of
+// owner which is still locked at currentHeight, along with the value which
+// has already vested; the vested value may only be spent by locking the
+// rest of the ValueStore again. Fully unlocked ValueStores are returned by
+// GetUnlockedValueForOwner instead.
+func (ut *UTXOHandler) GetLockedValueForOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32) (*uint256.Uint256, *uint256.Uint256, error) {
+	const maxCount = 256
+	epoch := utils.Epoch(currentHeight)
+	locked := uint256.Zero()
+	vested := uint256.Zero()
+	var lastKey []byte
+	for {
+		utxoIDs, _, lk, err := ut.lockedValueIndex.GetValueForOwner(txn, owner, uint256.Max(), nil, maxCount, lastKey)
+		if err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return nil, nil, err
+		}
+		for i := 0; i < len(utxoIDs); i++ {
+			lock, value, err := ut.getLock(txn, utxoIDs[i])
+			if err != nil {
+				utils.DebugTrace(ut.logger, err)
+				return nil, nil, err
+			}
+			if lock.IsUnlocked(epoch) {
+				continue
+			}
+			lockedValue, err := lock.LockedValue(value, epoch)
+			if err != nil {
+				return nil, nil, err
+			}
+			vestedValue, err := new(uint256.Uint256).Sub(value, lockedValue)
+			if err != nil {
+				return nil, nil, err
+			}
+			if locked, err = locked.Add(locked, lockedValue); err != nil {
+				return nil, nil, err
+			}
+			if vested, err = vested.Add(vested, vestedValue); err != nil {
+				return nil, nil, err
+			}
+		}
+		if lk == nil {
+			return locked, vested, nil
+		}
+		lastKey = lk
+	}
+}
+
+// getLock returns the lock and the value of a time locked ValueStore.
+func (ut *UTXOHandler) getLock(txn *badger.Txn, utxoID []byte) (*objs.ValueStoreLock, *uint256.Uint256, error) {
+	utxo, err := ut.getInternal(txn, utxoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	vs, err := utxo.ValueStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	lock, err := vs.Lock()
+	if err != nil {
+		return nil, nil, err
+	}
+	if lock == nil {
+		return nil, nil, errorz.ErrInvalid{}.New("utxoHandler.getLock; ValueStore is not time locked")
+	}
+	value, err := vs.Value()
+	if err != nil {
+		return nil, nil, err
+	}
+	return lock, value, nil
+}
+
+// isAtomicSwapExpired returns true if the AtomicSwap with the given utxoID
+// may only be consumed by its refunder at currentHeight.
+func (ut *UTXOHandler) isAtomicSwapExpired(txn *badger.Txn, utxoID []byte, currentHeight uint32) (bool, error) {
+	utxo, err := ut.getInternal(txn, utxoID)
+	if err != nil {
+		return false, err
+	}
+	as, err := utxo.AtomicSwap()
+	if err != nil {
+		return false, err
+	}
+	return as.IsExpired(currentHeight)
+}
+
 // PaginateDataByOwner ...
 func (ut *UTXOHandler) PaginateDataByOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, numItems int, startIndex []byte) ([]*objs.PaginationResponse, error) {
 	exclude := make(map[string]bool)
@@ -577,6 +785,63 @@ func (ut *UTXOHandler) PaginateDataByOwner(txn *badger.Txn, owner *objs.Owner, c
 	}
 }
 
+// QueryDataByOwner returns the datastores of the owner selected by the query
+// which are not expired at currentHeight. If more datastores match, the index
+// of the last one returned is returned as well to continue the query with.
+// withMetadata includes the IssuedAt and EpochOfExpiration of the datastores.
+func (ut *UTXOHandler) QueryDataByOwner(txn *badger.Txn, owner *objs.Owner, currentHeight uint32, q *objs.DataStoreQuery, withMetadata bool) ([]*objs.PaginationResponse, []byte, error) {
+	resp := []*objs.PaginationResponse{}
+	var next []byte
+	err := ut.dataIndex.QueryDataStores(txn, owner, q, func(item *objs.PaginationResponse) (bool, error) {
+		utxo, missing, err := ut.Get(txn, [][]byte{item.UTXOID})
+		if err != nil {
+			return false, err
+		}
+		if len(missing) > 0 || !utxo[0].HasDataStore() {
+			return true, nil
+		}
+		ds, err := utxo[0].DataStore()
+		if err != nil {
+			return false, err
+		}
+		expired, err := ds.IsExpired(currentHeight)
+		if err != nil {
+			return false, err
+		}
+		if expired {
+			return true, nil
+		}
+		ok, err := ut.TrieContains(txn, item.UTXOID)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return true, nil
+		}
+		if len(resp) == q.Limit {
+			next = utils.CopySlice(resp[len(resp)-1].Index)
+			return false, nil
+		}
+		if withMetadata {
+			item.IssuedAt, err = ds.IssuedAt()
+			if err != nil {
+				return false, err
+			}
+			item.EpochOfExpiration, err = ds.EpochOfExpiration()
+			if err != nil {
+				return false, err
+			}
+		}
+		resp = append(resp, item)
+		return true, nil
+	})
+	if err != nil {
+		utils.DebugTrace(ut.logger, err)
+		return nil, nil, err
+	}
+	return resp, next, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 ///////////PRIVATE METHODS//////////////////////////////////////////////////////
@@ -636,18 +901,17 @@ func (ut *UTXOHandler) addOne(txn *badger.Txn, utxo *objs.TXOut) error {
 			return err
 		}
 	case utxo.HasValueStore():
-		owner, err := utxo.GenericOwner()
-		if err != nil {
+		if err := ut.addValueStoreToIndexes(txn, utxoID, utxo); err != nil {
 			utils.DebugTrace(ut.logger, err)
 			return err
 		}
-		value, err := utxo.Value()
-		if err != nil {
+	case utxo.HasAtomicSwap():
+		if err := ut.addAtomicSwapToIndexes(txn, utxoID, utxo); err != nil {
 			utils.DebugTrace(ut.logger, err)
 			return err
 		}
-		err = ut.valueIndex.Add(txn, utxoID, owner, value)
-		if err != nil {
+	case utxo.HasTokenStore():
+		if err := ut.addTokenStoreToIndexes(txn, utxoID, utxo); err != nil {
 			utils.DebugTrace(ut.logger, err)
 			return err
 		}
@@ -695,7 +959,29 @@ func (ut *UTXOHandler) dropFromIndexes(txn *badger.Txn, utxoID []byte) error {
 			return err
 		}
 	case utxo.HasValueStore():
-		err = ut.valueIndex.Drop(txn, utxoID)
+		index, err := ut.valueStoreIndex(utxo)
+		if err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
+		err = index.Drop(txn, utxoID)
+		if err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
+	case utxo.HasAtomicSwap():
+		err = ut.swapValueIndex.Drop(txn, utxoID)
+		if err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
+		err = ut.altValueIndex.Drop(txn, utxoID)
+		if err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
+	case utxo.HasTokenStore():
+		err = ut.assetIndex.Drop(txn, utxoID)
 		if err != nil {
 			utils.DebugTrace(ut.logger, err)
 			return err
@@ -706,6 +992,119 @@ func (ut *UTXOHandler) dropFromIndexes(txn *badger.Txn, utxoID []byte) error {
 	return nil
 }
 
+// validateAtomicSwapSpends validates the signatures of the consumed
+// AtomicSwaps against the spend path which is open at currentHeight.
+func (ut *UTXOHandler) validateAtomicSwapSpends(tx *objs.Tx, utxos []*objs.TXOut, currentHeight uint32) error {
+	txIns := make(map[string]*objs.TXIn)
+	for i := 0; i < len(tx.Vin); i++ {
+		utxoID, err := tx.Vin[i].UTXOID()
+		if err != nil {
+			return err
+		}
+		txIns[string(utxoID)] = tx.Vin[i]
+	}
+	for i := 0; i < len(utxos); i++ {
+		if !utxos[i].HasAtomicSwap() {
+			continue
+		}
+		utxoID, err := utxos[i].UTXOID()
+		if err != nil {
+			return err
+		}
+		txIn, ok := txIns[string(utxoID)]
+		if !ok {
+			return errorz.ErrInvalid{}.New("utxoHandler.validateAtomicSwapSpends; missing txIn for consumed utxo")
+		}
+		if err := utxos[i].ValidateSignature(currentHeight, txIn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addValueStoreToIndexes indexes the value of a ValueStore under its owner;
+// time locked ValueStores are kept apart so that they are not returned as
+// spendable value before they are unlocked.
+func (ut *UTXOHandler) addValueStoreToIndexes(txn *badger.Txn, utxoID []byte, utxo *objs.TXOut) error {
+	owner, err := utxo.GenericOwner()
+	if err != nil {
+		return err
+	}
+	value, err := utxo.Value()
+	if err != nil {
+		return err
+	}
+	index, err := ut.valueStoreIndex(utxo)
+	if err != nil {
+		return err
+	}
+	return index.Add(txn, utxoID, owner, value)
+}
+
+// valueStoreIndex returns the value index of a ValueStore.
+func (ut *UTXOHandler) valueStoreIndex(utxo *objs.TXOut) (*indexer.ValueIndex, error) {
+	vs, err := utxo.ValueStore()
+	if err != nil {
+		return nil, err
+	}
+	lock, err := vs.Lock()
+	if err != nil {
+		return nil, err
+	}
+	if lock != nil {
+		return ut.lockedValueIndex, nil
+	}
+	return ut.valueIndex, nil
+}
+
+// addAtomicSwapToIndexes indexes the value of an AtomicSwap under both the
+// recipient and the refunder; an AtomicSwap is not part of the plain value of
+// either of them.
+func (ut *UTXOHandler) addAtomicSwapToIndexes(txn *badger.Txn, utxoID []byte, utxo *objs.TXOut) error {
+	as, err := utxo.AtomicSwap()
+	if err != nil {
+		return err
+	}
+	owner, err := as.GenericOwner()
+	if err != nil {
+		return err
+	}
+	altOwner, err := as.AlternateGenericOwner()
+	if err != nil {
+		return err
+	}
+	value, err := as.Value()
+	if err != nil {
+		return err
+	}
+	if err := ut.swapValueIndex.Add(txn, utxoID, owner, value); err != nil {
+		return err
+	}
+	return ut.altValueIndex.Add(txn, utxoID, altOwner, value)
+}
+
+// addTokenStoreToIndexes indexes the amount of a TokenStore under its asset
+// and owner; a TokenStore is not part of the value of its owner.
+func (ut *UTXOHandler) addTokenStoreToIndexes(txn *badger.Txn, utxoID []byte, utxo *objs.TXOut) error {
+	ts, err := utxo.TokenStore()
+	if err != nil {
+		return err
+	}
+	owner, err := ts.GenericOwner()
+	if err != nil {
+		return err
+	}
+	assetID, err := ts.AssetID()
+	if err != nil {
+		return err
+	}
+	amount, err := ts.Amount()
+	if err != nil {
+		return err
+	}
+	return ut.assetIndex.Add(txn, utxoID, assetID, owner, amount)
+}
+
 func (ut *UTXOHandler) makeUTXOKey(utxoID []byte) []byte {
 	utxoIDCopy := utils.CopySlice(utxoID)
 	key := dbprefix.PrefixMinedUTXO()
@@ -759,28 +1158,27 @@ func (ut *UTXOHandler) addOneFastSync(txn *badger.Txn, utxo *objs.TXOut) error {
 			return err
 		}
 	case utxo.HasValueStore():
-		owner, err := utxo.GenericOwner()
-		if err != nil {
-			utils.DebugTrace(ut.logger, err)
-			return err
-		}
 		///////// workaround for deposit utxos ////////////////
 		// Workaround for a historical deposit that was on the incorrect chain. Required to sync production
 		// nodes.
 		// Todo: remove this code after the UTXO state trie has been rebuilt
 		if !utxo.IsDeposit() {
-			value, err := utxo.Value()
-			if err != nil {
-				utils.DebugTrace(ut.logger, err)
-				return err
-			}
-			err = ut.valueIndex.Add(txn, utxoID, owner, value)
-			if err != nil {
+			if err := ut.addValueStoreToIndexes(txn, utxoID, utxo); err != nil {
 				utils.DebugTrace(ut.logger, err)
 				return err
 			}
 		}
 		///////////////////////////////////////////////////////
+	case utxo.HasAtomicSwap():
+		if err := ut.addAtomicSwapToIndexes(txn, utxoID, utxo); err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
+	case utxo.HasTokenStore():
+		if err := ut.addTokenStoreToIndexes(txn, utxoID, utxo); err != nil {
+			utils.DebugTrace(ut.logger, err)
+			return err
+		}
 	default:
 		panic("utxoHandler.addOneFastSync; utxo type not defined")
 	}
@@ -1,6 +1,7 @@
 package utxohandler
 
 import (
+	"bytes"
 	"strconv"
 	"testing"
 
@@ -8,11 +9,19 @@ import (
 
 	"github.com/alicenet/alicenet/application/objs"
 	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/application/wrapper"
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/dynamics/mocks"
 	"github.com/alicenet/alicenet/utils"
 )
 
+// makeForkedStorage returns a storage for which the application fork is
+// active from the first epoch.
+func makeForkedStorage() *wrapper.Storage {
+	return wrapper.NewStorage(mocks.NewMockStorageGetter(), 1)
+}
+
 func makeDeposit(t *testing.T, s objs.Signer, chainID uint32, i int, value *uint256.Uint256) *objs.ValueStore {
 	t.Helper()
 	pubkey, err := s.Pubkey()
@@ -95,7 +104,7 @@ func TestUTXOTrie(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	hndlr := NewUTXOHandler(db)
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
 	err = hndlr.Init(1)
 	if err != nil {
 		t.Fatal(err)
@@ -163,3 +172,696 @@ func TestUTXOTrie(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func makeAtomicSwapTx(t *testing.T, s objs.Signer, v *objs.ValueStore, owner *objs.AtomicSwapOwner) *objs.Tx {
+	t.Helper()
+	txIn, err := v.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := v.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo := &objs.TXOut{}
+	err = utxo.CreateAtomicSwap(1, value, uint256.Zero(), owner, 1, 2, make([]byte, constants.HashLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &objs.Tx{Vin: []*objs.TXIn{txIn}, Vout: []*objs.TXOut{utxo}, Fee: uint256.Zero()}
+	err = tx.SetTxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Sign(tx.Vin[0], s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestUTXOHandlerAtomicSwap(t *testing.T) {
+	opts := badger.DefaultOptions(t.TempDir())
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	signer := &crypto.Secp256k1Signer{}
+	err = signer.SetPrivk(crypto.Hasher([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	refunder := &crypto.Secp256k1Signer{}
+	err = refunder.SetPrivk(crypto.Hasher([]byte("refunder")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := signer.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refunderPubkey, err := refunder.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashKey := crypto.Hasher([]byte("hashKey"))
+	owner := &objs.AtomicSwapOwner{}
+	err = owner.New(crypto.Hasher(hashKey), crypto.GetAccount(pubkey), constants.CurveSecp256k1, crypto.GetAccount(refunderPubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient := &objs.Owner{}
+	err = recipient.New(crypto.GetAccount(pubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refundOwner := &objs.Owner{}
+	err = refundOwner.New(crypto.GetAccount(refunderPubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
+	err = hndlr.Init(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := makeDeposit(t, signer, 1, 1, uint256.One())
+	utxoDep := &objs.TXOut{}
+	err = utxoDep.NewValueStore(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := makeAtomicSwapTx(t, signer, d, owner)
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{tx}, 1, objs.Vout{utxoDep}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{tx}, 2); err != nil {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := tx.Vout[0].AtomicSwap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoID, err := as.UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredHeight := constants.EpochLength + 1
+	err = db.View(func(txn *badger.Txn) error {
+		utxoIDs, _, _, err := hndlr.GetValueForOwner(txn, recipient, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("atomic swap should not be part of the value of the recipient")
+		}
+		utxoIDs, _, _, err = hndlr.GetClaimValueForOwner(txn, recipient, 3, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || string(utxoIDs[0]) != string(utxoID) {
+			t.Fatal("recipient should find the atomic swap before expiration")
+		}
+		utxoIDs, _, _, err = hndlr.GetClaimValueForOwner(txn, recipient, expiredHeight, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("recipient should not find the atomic swap after expiration")
+		}
+		utxoIDs, _, _, err = hndlr.GetRefundValueForOwner(txn, refundOwner, 3, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("refunder should not find the atomic swap before expiration")
+		}
+		utxoIDs, _, _, err = hndlr.GetRefundValueForOwner(txn, refundOwner, expiredHeight, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || string(utxoIDs[0]) != string(utxoID) {
+			t.Fatal("refunder should find the atomic swap after expiration")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	makeSpend := func(sign func(*objs.TXIn) error) *objs.Tx {
+		txIn, err := as.MakeTxIn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		spend := &objs.Tx{Vin: []*objs.TXIn{txIn}, Fee: uint256.Zero()}
+		spend.Vout = []*objs.TXOut{makeTxs(t, signer, d).Vout[0]}
+		if err := spend.SetTxHash(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sign(spend.Vin[0]); err != nil {
+			t.Fatal(err)
+		}
+		return spend
+	}
+	claim := makeSpend(func(txIn *objs.TXIn) error { return as.SignAsPrimary(txIn, signer, hashKey) })
+	refund := makeSpend(func(txIn *objs.TXIn) error { return as.SignAsAlternate(txIn, refunder) })
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{claim}, 3, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{refund}, 3, nil); err == nil {
+			t.Fatal("refund should not be valid before expiration")
+		}
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{claim}, expiredHeight, nil); err == nil {
+			t.Fatal("claim should not be valid after expiration")
+		}
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{refund}, expiredHeight, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{refund}, expiredHeight); err != nil {
+			t.Fatal(err)
+		}
+		utxoIDs, _, _, err := hndlr.GetRefundValueForOwner(txn, refundOwner, expiredHeight, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("consumed atomic swap should be dropped from the indexes")
+		}
+		utxoIDs, _, _, err = hndlr.GetClaimValueForOwner(txn, recipient, 3, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("consumed atomic swap should be dropped from the indexes")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func makeLockedSpend(t *testing.T, s objs.Signer, v *objs.ValueStore, vout ...*objs.TXOut) *objs.Tx {
+	t.Helper()
+	txIn, err := v.MakeTxIn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &objs.Tx{Vin: []*objs.TXIn{txIn}, Vout: vout, Fee: uint256.Zero()}
+	err = tx.SetTxHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Sign(tx.Vin[0], s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func makeLockedUTXO(t *testing.T, s objs.Signer, value uint64, lock *objs.ValueStoreLock) *objs.TXOut {
+	t.Helper()
+	pubkey, err := s.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := new(uint256.Uint256).FromUint64(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := &objs.ValueStore{}
+	err = vs.New(1, v, uint256.Zero(), crypto.GetAccount(pubkey), constants.CurveSecp256k1, make([]byte, constants.HashLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		vso := &objs.ValueStoreOwner{}
+		if err := vso.NewLocked(crypto.GetAccount(pubkey), constants.CurveSecp256k1, lock); err != nil {
+			t.Fatal(err)
+		}
+		vs.VSPreImage.Owner = vso
+	}
+	utxo := &objs.TXOut{}
+	if err := utxo.NewValueStore(vs); err != nil {
+		t.Fatal(err)
+	}
+	return utxo
+}
+
+func TestUTXOHandlerLockedValueStore(t *testing.T) {
+	opts := badger.DefaultOptions(t.TempDir())
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	signer := &crypto.Secp256k1Signer{}
+	err = signer.SetPrivk(crypto.Hasher([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := signer.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &objs.Owner{}
+	err = owner.New(crypto.GetAccount(pubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
+	err = hndlr.Init(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ten, err := new(uint256.Uint256).FromUint64(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	five, err := new(uint256.Uint256).FromUint64(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := makeDeposit(t, signer, 1, 1, ten)
+	utxoDep := &objs.TXOut{}
+	err = utxoDep.NewValueStore(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := makeLockedSpend(t, signer, d, makeLockedUTXO(t, signer, 10, &objs.ValueStoreLock{UnlockEpoch: 2, VestingEndEpoch: 4}))
+	epoch3 := 2*constants.EpochLength + 1
+	epoch4 := 3*constants.EpochLength + 1
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{tx}, 1, objs.Vout{utxoDep}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{tx}, 2); err != nil {
+			t.Fatal(err)
+		}
+		utxoIDs, _, _, err := hndlr.GetValueForOwner(txn, owner, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("locked value should not be spendable")
+		}
+		utxoIDs, _, _, err = hndlr.GetUnlockedValueForOwner(txn, owner, epoch3, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatal("partially vested value should not be unlocked")
+		}
+		locked, vested, err := hndlr.GetLockedValueForOwner(txn, owner, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !locked.Eq(ten) || !vested.IsZero() {
+			t.Fatalf("invalid locked value: %v vested: %v", locked, vested)
+		}
+		locked, vested, err = hndlr.GetLockedValueForOwner(txn, owner, epoch3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !locked.Eq(five) || !vested.Eq(five) {
+			t.Fatalf("invalid locked value: %v vested: %v", locked, vested)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := tx.Vout[0].ValueStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relock := &objs.ValueStoreLock{UnlockEpoch: 3, VestingEndEpoch: 4}
+	early := makeLockedSpend(t, signer, vs, makeLockedUTXO(t, signer, 6, nil), makeLockedUTXO(t, signer, 4, relock))
+	vested := makeLockedSpend(t, signer, vs, makeLockedUTXO(t, signer, 5, nil), makeLockedUTXO(t, signer, 5, relock))
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{early}, epoch3, nil); err == nil {
+			t.Fatal("unvested value should not be spendable")
+		}
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{vested}, epoch3, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{vested}, epoch3); err != nil {
+			t.Fatal(err)
+		}
+		utxoIDs, value, _, err := hndlr.GetValueForOwner(txn, owner, uint256.Max(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || !value.Eq(five) {
+			t.Fatal("vested value should be spendable")
+		}
+		utxoIDs, value, _, err = hndlr.GetUnlockedValueForOwner(txn, owner, epoch4, uint256.Max(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || !value.Eq(five) {
+			t.Fatal("the rest should be unlocked once vested")
+		}
+		locked, vested, err := hndlr.GetLockedValueForOwner(txn, owner, epoch4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !locked.IsZero() || !vested.IsZero() {
+			t.Fatalf("invalid locked value: %v vested: %v", locked, vested)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func makeDataStoreUTXO(t *testing.T, s objs.Signer, txOutIdx uint32, index []byte, numEpochs uint32) *objs.TXOut {
+	t.Helper()
+	pubkey, err := s.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawData := crypto.Hasher(index)
+	deposit, err := objs.BaseDepositEquation(uint32(len(rawData)), numEpochs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &objs.DataStoreOwner{}
+	owner.New(crypto.GetAccount(pubkey), constants.CurveSecp256k1)
+	ds := &objs.DataStore{
+		DSLinker: &objs.DSLinker{
+			DSPreImage: &objs.DSPreImage{
+				ChainID:  1,
+				Index:    index,
+				IssuedAt: 1,
+				Deposit:  deposit,
+				RawData:  rawData,
+				TXOutIdx: txOutIdx,
+				Owner:    owner,
+				Fee:      uint256.Zero(),
+			},
+			TxHash: make([]byte, constants.HashLen),
+		},
+	}
+	utxo := &objs.TXOut{}
+	if err := utxo.NewDataStore(ds); err != nil {
+		t.Fatal(err)
+	}
+	return utxo
+}
+
+func TestUTXOHandlerQueryDataByOwner(t *testing.T) {
+	opts := badger.DefaultOptions(t.TempDir())
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	signer := &crypto.Secp256k1Signer{}
+	err = signer.SetPrivk(crypto.Hasher([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := signer.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &objs.Owner{}
+	err = owner.New(crypto.GetAccount(pubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
+	err = hndlr.Init(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	makeIndex := func(p, k byte) []byte {
+		idx := make([]byte, constants.HashLen)
+		idx[0] = p
+		idx[1] = k
+		return idx
+	}
+	// the datastores with prefix 1 expire at epoch 4, the others at epoch 12
+	vout := objs.Vout{
+		makeDataStoreUTXO(t, signer, 0, makeIndex(1, 0), 2),
+		makeDataStoreUTXO(t, signer, 1, makeIndex(2, 0), 10),
+		makeDataStoreUTXO(t, signer, 2, makeIndex(2, 1), 10),
+		makeDataStoreUTXO(t, signer, 3, makeIndex(2, 2), 10),
+	}
+	total, err := vout.ValuePlusFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := makeDeposit(t, signer, 1, 1, total)
+	utxoDep := &objs.TXOut{}
+	err = utxoDep.NewValueStore(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := makeLockedSpend(t, signer, d, vout...)
+	for _, utxo := range tx.Vout {
+		ds, err := utxo.DataStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ds.PreSign(signer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	epoch4 := 3*constants.EpochLength + 1
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{tx}, 1, objs.Vout{utxoDep}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{tx}, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		q := &objs.DataStoreQuery{Prefix: []byte{2}, Limit: 2}
+		items, next, err := hndlr.QueryDataByOwner(txn, owner, 2, q, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 2 || !bytes.Equal(items[1].Index, makeIndex(2, 1)) || !bytes.Equal(next, makeIndex(2, 1)) {
+			t.Fatalf("invalid first page: %v next: %x", items, next)
+		}
+		if items[0].IssuedAt != 0 || items[0].EpochOfExpiration != 0 {
+			t.Fatal("metadata should not be included")
+		}
+		q.After = next
+		items, next, err = hndlr.QueryDataByOwner(txn, owner, 2, q, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || !bytes.Equal(items[0].Index, makeIndex(2, 2)) || next != nil {
+			t.Fatalf("invalid last page: %v next: %x", items, next)
+		}
+		if items[0].IssuedAt != 1 || items[0].EpochOfExpiration != 12 {
+			t.Fatalf("invalid metadata: %v", items[0])
+		}
+
+		// expired datastores are not returned
+		q = &objs.DataStoreQuery{Reverse: true, Limit: 10}
+		items, _, err = hndlr.QueryDataByOwner(txn, owner, 2, q, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 4 || !bytes.Equal(items[0].Index, makeIndex(2, 2)) {
+			t.Fatalf("invalid datastores: %v", items)
+		}
+		items, _, err = hndlr.QueryDataByOwner(txn, owner, epoch4, q, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 3 {
+			t.Fatalf("invalid datastores once expired: %v", items)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUTXOHandlerAssetValueForOwner(t *testing.T) {
+	opts := badger.DefaultOptions(t.TempDir())
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	signer := &crypto.Secp256k1Signer{}
+	err = signer.SetPrivk(crypto.Hasher([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := signer.Pubkey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &objs.Owner{}
+	err = owner.New(crypto.GetAccount(pubkey), constants.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
+	err = hndlr.Init(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := makeDeposit(t, signer, 1, 1, uint256.One())
+	utxoDep := &objs.TXOut{}
+	err = utxoDep.NewValueStore(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depID, err := d.UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID := objs.MakeAssetID(depID)
+	amount, err := new(uint256.Uint256).FromUint64(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoTS := &objs.TXOut{}
+	err = utxoTS.CreateTokenStore(1, assetID, amount, uint256.Zero(), crypto.GetAccount(pubkey), constants.CurveSecp256k1, make([]byte, constants.HashLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := makeLockedSpend(t, signer, d, makeLockedUTXO(t, signer, 1, nil), utxoTS)
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.IsValid(txn, []*objs.Tx{tx}, 1, objs.Vout{utxoDep}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{tx}, 2); err != nil {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tsID, err := tx.Vout[1].UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.View(func(txn *badger.Txn) error {
+		utxoIDs, total, _, err := hndlr.GetAssetValueForOwner(txn, owner, assetID, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || !bytes.Equal(utxoIDs[0], tsID) || !total.Eq(amount) {
+			t.Fatalf("invalid asset value: %x %v", utxoIDs, total)
+		}
+		// the native value of the owner does not include the tokenstore
+		utxoIDs, _, _, err = hndlr.GetValueForOwner(txn, owner, uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 1 || bytes.Equal(utxoIDs[0], tsID) {
+			t.Fatalf("invalid native value: %x", utxoIDs)
+		}
+		utxoIDs, _, _, err = hndlr.GetAssetValueForOwner(txn, owner, crypto.Hasher(assetID), uint256.One(), 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxoIDs) != 0 {
+			t.Fatalf("invalid value for unknown asset: %x", utxoIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUTXOHandlerGetAtHeight(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	signer := &crypto.Secp256k1Signer{}
+	if err := signer.SetPrivk(crypto.Hasher([]byte("secret"))); err != nil {
+		t.Fatal(err)
+	}
+	hndlr := NewUTXOHandler(db, makeForkedStorage())
+	if err := hndlr.Init(1); err != nil {
+		t.Fatal(err)
+	}
+	tx1 := makeTxs(t, signer, makeDeposit(t, signer, 1, 1, uint256.One()))
+	vs, err := tx1.Vout[0].ValueStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2 := makeTxs(t, signer, vs)
+	utxoID1, err := tx1.Vout[0].UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoID2, err := tx2.Vout[0].UTXOID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(txn *badger.Txn) error {
+		if _, err := hndlr.ApplyState(txn, []*objs.Tx{tx1}, 1); err != nil {
+			return err
+		}
+		_, err := hndlr.ApplyState(txn, []*objs.Tx{tx2}, 2)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.View(func(txn *badger.Txn) error {
+		found, missing, err := hndlr.GetAtHeight(txn, 1, [][]byte{utxoID1, utxoID2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || len(missing) != 1 || !bytes.Equal(missing[0], utxoID2) {
+			t.Fatal("wrong utxos at height 1")
+		}
+		// the utxo consumed at height 2 is still returned for height 1
+		if id, err := found[0].UTXOID(); err != nil || !bytes.Equal(id, utxoID1) {
+			t.Fatal("wrong utxo at height 1")
+		}
+		found, missing, err = hndlr.GetAtHeight(txn, 2, [][]byte{utxoID1, utxoID2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || len(missing) != 1 || !bytes.Equal(missing[0], utxoID1) {
+			t.Fatal("wrong utxos at height 2")
+		}
+		if _, _, err := hndlr.GetAtHeight(txn, 3, [][]byte{utxoID1}); err != badger.ErrKeyNotFound {
+			t.Fatalf("height 3 should not be retained: %v", err)
+		}
+		root1, err := hndlr.GetStateRootForHeight(txn, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root2, err := hndlr.GetStateRootForHeight(txn, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(root1, root2) {
+			t.Fatal("state roots should differ")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
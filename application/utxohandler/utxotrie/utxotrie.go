@@ -28,7 +28,6 @@ func setRootForHeight(txn *badger.Txn, height uint32, root []byte) error {
 	return utils.SetValue(txn, key, root)
 }
 
-//nolint:unused,deadcode
 func getRootForHeight(txn *badger.Txn, height uint32) ([]byte, error) {
 	key := makeheightKey(height)
 	return utils.GetValue(txn, key)
@@ -147,6 +146,26 @@ func (ut *UTXOTrie) Get(txn *badger.Txn, utxoIDs [][]byte) ([][]byte, [][]byte,
 	return utxoHashes, missing, nil
 }
 
+// GetTrieForHeight returns the State Trie as it was at height;
+// badger.ErrKeyNotFound is returned if the root for height is not stored.
+func (ut *UTXOTrie) GetTrieForHeight(txn *badger.Txn, height uint32) (*trie.SMT, error) {
+	root, err := getRootForHeight(txn, height)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(root, make([]byte, constants.HashLen)) {
+		root = nil
+	}
+	t := trie.NewSMT(root, trie.Hasher, func() []byte { return getTriePrefix() })
+	return t, nil
+}
+
+// GetStateRootForHeight returns the StateRoot at height;
+// badger.ErrKeyNotFound is returned if the root for height is not stored.
+func (ut *UTXOTrie) GetStateRootForHeight(txn *badger.Txn, height uint32) ([]byte, error) {
+	return getRootForHeight(txn, height)
+}
+
 // Contains returns utxoIDs missing from state trie;
 // that is, a list of utxoIDs are given as input,
 // and if a utxoID is missing from the state trie,
@@ -157,21 +176,17 @@ func (ut *UTXOTrie) Contains(txn *badger.Txn, utxoIDs [][]byte) ([][]byte, error
 		utils.DebugTrace(ut.logger, err)
 		return nil, err
 	}
-	missing := [][]byte{}
-	for j := 0; j < len(utxoIDs); j++ {
-		utxoID := utils.CopySlice(utxoIDs[j])
-		txHash, err := current.Get(txn, utils.CopySlice(utxoID))
-		if err != nil {
-			if err != badger.ErrKeyNotFound {
-				utils.DebugTrace(ut.logger, err)
-				return nil, err
-			}
-		}
-		if len(txHash) == 0 {
-			missing = append(missing, utils.CopySlice(utxoID))
-		}
+	return ut.missing(txn, current, utxoIDs)
+}
+
+// ContainsAtHeight returns the utxoIDs missing from the state trie
+// as it was at height.
+func (ut *UTXOTrie) ContainsAtHeight(txn *badger.Txn, height uint32, utxoIDs [][]byte) ([][]byte, error) {
+	t, err := ut.GetTrieForHeight(txn, height)
+	if err != nil {
+		return nil, err
 	}
-	return missing, nil
+	return ut.missing(txn, t, utxoIDs)
 }
 
 // ApplyState updates the Current State Trie to include the additional transactions
@@ -369,6 +384,24 @@ func (ut *UTXOTrie) session(txn *badger.Txn) (*trie.SMT, func(txn *badger.Txn, c
 	return current, fn, nil
 }
 
+func (ut *UTXOTrie) missing(txn *badger.Txn, t *trie.SMT, utxoIDs [][]byte) ([][]byte, error) {
+	missing := [][]byte{}
+	for j := 0; j < len(utxoIDs); j++ {
+		utxoID := utils.CopySlice(utxoIDs[j])
+		txHash, err := t.Get(txn, utils.CopySlice(utxoID))
+		if err != nil {
+			if err != badger.ErrKeyNotFound {
+				utils.DebugTrace(ut.logger, err)
+				return nil, err
+			}
+		}
+		if len(txHash) == 0 {
+			missing = append(missing, utils.CopySlice(utxoID))
+		}
+	}
+	return missing, nil
+}
+
 func (ut *UTXOTrie) StoreSnapShotNode(txn *badger.Txn, batch, root []byte, layer int) ([][]byte, int, []trie.LeafNode, error) {
 	t := trie.NewSMT(root, trie.Hasher, func() []byte { return getTriePrefix() })
 	return t.StoreSnapShotNode(txn, batch, root, layer)
@@ -4,21 +4,37 @@ import (
 	"github.com/alicenet/alicenet/application/objs/uint256"
 	"github.com/alicenet/alicenet/dynamics"
 	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
 )
 
 // Storage wraps the dynamics.StorageGetter interface to make
 // it easier to interact within application logic.
 type Storage struct {
-	storage dynamics.StorageGetter
+	storage   dynamics.StorageGetter
+	forkEpoch uint32
 }
 
 // NewStorage creates a new storage struct which wraps
-// the StorageGetter interface.
-func NewStorage(storageInter dynamics.StorageGetter) *Storage {
-	storage := &Storage{storage: storageInter}
+// the StorageGetter interface. forkEpoch is the first epoch of the
+// application fork, as configured by chain.forkEpoch; zero keeps the rules
+// from before the fork.
+func NewStorage(storageInter dynamics.StorageGetter, forkEpoch uint32) *Storage {
+	storage := &Storage{storage: storageInter, forkEpoch: forkEpoch}
 	return storage
 }
 
+// IsForked returns true if the rules of the application fork apply to a
+// transaction mined at currentHeight.
+func (s *Storage) IsForked(currentHeight uint32) (bool, error) {
+	if s == nil {
+		return false, errorz.ErrInvalid{}.New("storage.IsForked; struct not initialized")
+	}
+	if s.forkEpoch == 0 {
+		return false, nil
+	}
+	return utils.Epoch(currentHeight) >= s.forkEpoch, nil
+}
+
 // GetMaxBlockSize returns MaxBytes
 func (s *Storage) GetMaxBlockSize() (uint32, error) {
 	if s == nil {
@@ -80,3 +96,20 @@ func (s *Storage) GetMinScaledTransactionFee() (*uint256.Uint256, error) {
 	}
 	return feeUint256, nil
 }
+
+// GetMemoByteFee returns the fee per byte of the memo of a transaction.
+func (s *Storage) GetMemoByteFee() (*uint256.Uint256, error) {
+	if s == nil {
+		return nil, errorz.ErrInvalid{}.New("storage.GetMemoByteFee; struct not initialized")
+	}
+	if s.storage == nil {
+		return nil, errorz.ErrInvalid{}.New("storage.GetMemoByteFee; storage not initialized")
+	}
+	fee := s.storage.GetMemoByteFee()
+	feeUint256 := &uint256.Uint256{}
+	_, err := feeUint256.FromBigInt(fee)
+	if err != nil {
+		return nil, err
+	}
+	return feeUint256, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/dynamics/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -76,7 +77,7 @@ func TestStorageWithoutStorageGetterGetMinTxFeeFails(t *testing.T) {
 func TestStorageInitialisedReturnsExpectedMaxBytes(t *testing.T) {
 	t.Parallel()
 	msg := mocks.NewMockStorageGetter()
-	s := NewStorage(msg)
+	s := NewStorage(msg, 1)
 	expectedMaxBytes := uint32(123)
 	msg.GetMaxBlockSizeFunc.SetDefaultReturn(expectedMaxBytes)
 
@@ -88,7 +89,7 @@ func TestStorageInitialisedReturnsExpectedMaxBytes(t *testing.T) {
 func TestStorageInitialisedReturnsExpectedDataStoreEpochFee(t *testing.T) {
 	t.Parallel()
 	msg := mocks.NewMockStorageGetter()
-	s := NewStorage(msg)
+	s := NewStorage(msg, 1)
 	expectedFee := big.NewInt(123)
 	expectedFeeUint256 := &uint256.Uint256{}
 	_, err := expectedFeeUint256.FromBigInt(expectedFee)
@@ -103,7 +104,7 @@ func TestStorageInitialisedReturnsExpectedDataStoreEpochFee(t *testing.T) {
 func TestStorageInitialisedReturnsExpectedValueStoreFee(t *testing.T) {
 	t.Parallel()
 	msg := mocks.NewMockStorageGetter()
-	s := NewStorage(msg)
+	s := NewStorage(msg, 1)
 	expectedFee := big.NewInt(123)
 	expectedFeeUint256 := &uint256.Uint256{}
 	_, err := expectedFeeUint256.FromBigInt(expectedFee)
@@ -119,7 +120,7 @@ func TestStorageInitialisedReturnsExpectedValueStoreFee(t *testing.T) {
 func TestStorageInitialisedReturnsExpectedMinTxFee(t *testing.T) {
 	t.Parallel()
 	msg := mocks.NewMockStorageGetter()
-	s := NewStorage(msg)
+	s := NewStorage(msg, 1)
 	expectedFee := big.NewInt(123)
 	expectedFeeUint256 := &uint256.Uint256{}
 	_, err := expectedFeeUint256.FromBigInt(expectedFee)
@@ -131,3 +132,40 @@ func TestStorageInitialisedReturnsExpectedMinTxFee(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFeeUint256, fee)
 }
+
+func TestStorageIsForkedFailsWhenNotInitialised(t *testing.T) {
+	t.Parallel()
+	s := make([]*Storage, 1)
+	forked, err := s[0].IsForked(1)
+	assert.Error(t, err)
+	assert.False(t, forked)
+}
+
+func TestStorageIsForked(t *testing.T) {
+	t.Parallel()
+	msg := mocks.NewMockStorageGetter()
+	s := NewStorage(msg, 3)
+	testCases := []struct {
+		height uint32
+		forked bool
+	}{
+		{1, false},
+		{2 * constants.EpochLength, false},
+		{2*constants.EpochLength + 1, true},
+		{10 * constants.EpochLength, true},
+	}
+	for _, tc := range testCases {
+		forked, err := s.IsForked(tc.height)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.forked, forked, "height %v", tc.height)
+	}
+}
+
+func TestStorageIsForkedDisabled(t *testing.T) {
+	t.Parallel()
+	msg := mocks.NewMockStorageGetter()
+	s := NewStorage(msg, 0)
+	forked, err := s.IsForked(constants.MaxUint32)
+	assert.NoError(t, err)
+	assert.False(t, forked)
+}
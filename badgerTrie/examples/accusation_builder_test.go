@@ -521,7 +521,7 @@ func TestGenerateAccusations(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	hndlr := utxo.NewUTXOHandler(db)
+	hndlr := utxo.NewUTXOHandler(db, nil)
 	err = hndlr.Init(1)
 	if err != nil {
 		t.Fatal(err)
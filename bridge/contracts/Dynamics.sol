@@ -15,7 +15,7 @@ contract Dynamics is Initializable, IDynamics, ImmutableSnapshots {
     using DoublyLinkedListLogic for DoublyLinkedList;
 
     bytes8 internal constant _UNIVERSAL_DEPLOY_CODE = 0x38585839386009f3;
-    Version internal constant _CURRENT_VERSION = Version.V1;
+    Version internal constant _CURRENT_VERSION = Version.V2;
 
     DoublyLinkedList internal _dynamicValues;
     Configuration internal _configuration;
@@ -33,6 +33,7 @@ contract Dynamics is Initializable, IDynamics, ImmutableSnapshots {
             3000000,
             0,
             0,
+            0,
             0
         );
         // minimum 2 epochs,
@@ -295,20 +296,30 @@ contract Dynamics is Initializable, IDynamics, ImmutableSnapshots {
     ) internal view returns (DynamicValues memory values) {
         uint256 ptr;
         uint256 retPtr;
-        uint8[8] memory sizes = [8, 24, 32, 32, 32, 64, 64, 128];
+        uint8[9] memory sizes = [8, 24, 32, 32, 32, 64, 64, 128, 64];
         uint256 dynamicValuesTotalSize = 48;
         uint256 extCodeSize;
+        uint256 encoderVersion;
         assembly ("memory-safe") {
             ptr := mload(0x40)
             retPtr := values
             extCodeSize := extcodesize(addr)
             extcodecopy(addr, ptr, 0, extCodeSize)
+            encoderVersion := shr(248, mload(ptr))
         }
         if (extCodeSize == 0 || extCodeSize < dynamicValuesTotalSize) {
             revert DynamicsErrors.InvalidExtCodeSize(addr, extCodeSize);
         }
+        // the first byte is the encoder version; V1 doesn't encode the memoByteFee
+        uint256 numFields = sizes.length - 1;
+        if (encoderVersion >= uint256(Version.V2)) {
+            if (extCodeSize < dynamicValuesTotalSize + 8) {
+                revert DynamicsErrors.InvalidExtCodeSize(addr, extCodeSize);
+            }
+            numFields = sizes.length;
+        }
 
-        for (uint8 i = 0; i < sizes.length; i++) {
+        for (uint8 i = 0; i < numFields; i++) {
             uint8 size = sizes[i];
             assembly ("memory-safe") {
                 mstore(retPtr, shr(sub(256, size), mload(ptr)))
@@ -334,6 +345,9 @@ contract Dynamics is Initializable, IDynamics, ImmutableSnapshots {
             newValue.valueStoreFee,
             newValue.minScaledTransactionFee
         );
+        if (newValue.encoderVersion >= Version.V2) {
+            data = abi.encodePacked(data, newValue.memoByteFee);
+        }
         return data;
     }
 
@@ -4,7 +4,8 @@ pragma solidity ^0.8.16;
 // enum to keep track of versions of the dynamic struct for the encoding and
 // decoding algorithms
 enum Version {
-    V1
+    V1,
+    V2
 }
 struct DynamicValues {
     // first slot
@@ -17,6 +18,8 @@ struct DynamicValues {
     uint64 valueStoreFee;
     // Second slot
     uint128 minScaledTransactionFee;
+    // only encoded from V2 on
+    uint64 memoByteFee;
 }
 
 struct Configuration {
@@ -981,6 +981,7 @@ task("change-dynamic-value", "Change a certain dynamic value")
     "minScaledTransactionFee",
     "new minScaledTransaction fee value"
   )
+  .addOptionalParam("memoByteFee", "new fee value per byte of memo")
   .addOptionalParam(
     "waitConfirmation",
     "wait specified number of blocks between transactions",
@@ -1041,6 +1042,13 @@ task("change-dynamic-value", "Change a certain dynamic value")
         ? taskArgs.minScaledTransactionFee
         : currentValue.minScaledTransactionFee;
 
+    // the memo byte fee is only encoded from V2 on
+    newValue.encoderVersion = await dynamics.getEncodingVersion();
+    newValue.memoByteFee =
+      taskArgs.memoByteFee !== undefined
+        ? taskArgs.memoByteFee
+        : currentValue.memoByteFee;
+
     let epoch;
     if (taskArgs.relativeEpoch !== undefined && taskArgs.relativeEpoch >= 2) {
       epoch = taskArgs.relativeEpoch;
@@ -5,7 +5,7 @@ task("go-go-gen", "Builds generate.go file from all json files in path")
   .addParam("in", "relative path of the output files")
   .addParam("out", "relative path of the output files")
   .addParam("pkg", "pkg the go generate command should use")
-  .setAction(async (taskArgs) => {
+  .setAction(async (taskArgs, hre) => {
     let outputData = `#! /bin/bash\n`;
     outputData =
       outputData +
@@ -23,12 +23,22 @@ task("go-go-gen", "Builds generate.go file from all json files in path")
     if (outpath === ".") {
       outpath = "";
     }
-    fs.readdirSync(taskArgs.in).forEach((file) => {
-      const source = file.replace(".json", "");
+    const sources = fs
+      .readdirSync(taskArgs.in)
+      .filter((file) => file.endsWith(".json"))
+      .map((file) => file.replace(".json", ""));
+    for (const source of sources) {
+      // the bytecode is embedded in the bindings so the contracts can be
+      // deployed from go, abigen expects it without the 0x prefix
+      const artifact = await hre.artifacts.readArtifact(source);
+      fs.writeFileSync(
+        `${taskArgs.in}/${source}.bin`,
+        artifact.bytecode.replace(/^0x/, "")
+      );
       outputData =
         outputData +
-        `abigen --abi ${taskArgs.in}/${source}.json --pkg ${taskArgs.pkg} --type ${source} --out ${outpath}${source}.go\n`;
-    });
+        `abigen --abi ${taskArgs.in}/${source}.json --bin ${taskArgs.in}/${source}.bin --pkg ${taskArgs.pkg} --type ${source} --out ${outpath}${source}.go\n`;
+    }
     if (!fs.existsSync(`${taskArgs.pkg}`)) {
       fs.mkdirSync(`${taskArgs.pkg}`);
     }
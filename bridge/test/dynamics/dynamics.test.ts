@@ -151,6 +151,7 @@ describe("Testing Dynamics methods", async () => {
     dataStoreFee: BigNumber.from(0),
     valueStoreFee: BigNumber.from(0),
     minScaledTransactionFee: BigNumber.from(0),
+    memoByteFee: BigNumber.from(0),
   };
   const currentConfiguration: ConfigurationStruct = {
     minEpochsBetweenUpdates: BigNumber.from(2),
@@ -297,6 +298,9 @@ describe("Testing Dynamics methods", async () => {
     expect(latestDynamicsValue.minScaledTransactionFee).to.be.deep.equal(
       currentDynamicValues.minScaledTransactionFee
     );
+    expect(latestDynamicsValue.memoByteFee).to.be.deep.equal(
+      currentDynamicValues.memoByteFee
+    );
   });
 
   it("Should change dynamic values in a valid epoch and emit corresponding event", async () => {
@@ -315,6 +319,28 @@ describe("Testing Dynamics methods", async () => {
     ).to.be.equal(newDynamicValues.valueStoreFee);
   });
 
+  it("Should change the memo byte fee with the V2 encoding", async () => {
+    const newDynamicValues = { ...currentDynamicValues };
+    newDynamicValues.encoderVersion = 1;
+    newDynamicValues.memoByteFee = BigNumber.from(1000);
+    const encoded = await fixture.dynamics.encodeDynamicValues(
+      newDynamicValues
+    );
+    // the V2 encoding appends the memo byte fee to the 48 bytes of V1
+    expect(ethers.utils.hexDataLength(encoded)).to.be.equal(56);
+    expect(ethers.utils.hexDataSlice(encoded, 48)).to.be.equal(
+      "0x00000000000003e8"
+    );
+    await changeDynamicValues(fixture, newDynamicValues);
+    await commitSnapshots(fixture, minEpochsBetweenUpdates.toNumber());
+    const latestDynamicsValue =
+      (await fixture.dynamics.getLatestDynamicValues()) as DynamicValuesStruct;
+    expect(latestDynamicsValue.encoderVersion).to.be.equal(1);
+    expect(latestDynamicsValue.memoByteFee).to.be.equal(
+      newDynamicValues.memoByteFee
+    );
+  });
+
   it("Should get previous dynamic values", async () => {
     const newDynamicValues = { ...currentDynamicValues };
     newDynamicValues.valueStoreFee = BigNumber.from(1);
@@ -0,0 +1,255 @@
+package bridge
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/alcb"
+	"github.com/alicenet/alicenet/layer1/evm"
+	"github.com/alicenet/alicenet/layer1/handlers"
+	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/alicenet/alicenet/localrpc"
+	"github.com/alicenet/alicenet/logging"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// Command is the cobra.Command grouping the commands that move value between
+// ALCB on layer1 and AliceNet.
+var Command = cobra.Command{
+	Use:   "bridge",
+	Short: "Deposit ALCB into AliceNet and burn AliceNet value with a proof for layer1",
+	Long: "bridge sends the layer1 transactions from ethereum.defaultAccount and signs the AliceNet " +
+		"transactions with the same key. AliceNet is reached through transport.localStateListeningAddress",
+}
+
+// DepositCommand deposits ALCB and waits for the deposit UTXO.
+var DepositCommand = cobra.Command{
+	Use:   "deposit <amount> [owner address] [secp|bn]",
+	Short: "Deposit ALCB, in its smallest unit, and wait until it is a UTXO on AliceNet",
+	Long: "deposit burns ALCB of the default account on layer1 and waits until the AliceNet node " +
+		"turns it into a deposit UTXO. The owner defaults to the default account with a secp256k1 key",
+	Args: cobra.RangeArgs(1, 3),
+	Run:  deposit,
+}
+
+// StatusCommand prints the status of a deposit.
+var StatusCommand = cobra.Command{
+	Use:   "status <deposit nonce>",
+	Short: "Show the status of a deposit and its UTXO",
+	Args:  cobra.ExactArgs(1),
+	Run:   status,
+}
+
+// BurnCommand burns AliceNet value and prints the proof of the burn.
+var BurnCommand = cobra.Command{
+	Use:   "burn <amount>",
+	Short: "Burn AliceNet value of the default account and print the proof of the burn",
+	Args:  cobra.ExactArgs(1),
+	Run:   burn,
+}
+
+// ProofCommand prints the proof of a mined burn transaction.
+var ProofCommand = cobra.Command{
+	Use:   "proof <tx hash>",
+	Short: "Print the proof of a mined burn transaction",
+	Args:  cobra.ExactArgs(1),
+	Run:   proof,
+}
+
+// setup connects to ethereum if needed and to the AliceNet node.
+type setup struct {
+	ctx    context.Context
+	logger *logrus.Entry
+	eth    *evm.Client
+	client *localrpc.Client
+}
+
+func newSetup(logger *logrus.Entry, withEthereum bool) (*setup, func()) {
+	ctx, cf := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	s := &setup{ctx: ctx, logger: logger}
+	closers := []func(){cf}
+	cleanup := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	if withEthereum {
+		eth, err := evm.NewClient(
+			logger.Logger,
+			config.Configuration.Ethereum.Endpoint,
+			config.Configuration.Ethereum.Keystore,
+			config.Configuration.Ethereum.PassCodes,
+			config.Configuration.Ethereum.DefaultAccount,
+			true,
+			constants.EthereumFinalityDelay,
+			config.Configuration.Ethereum.TxMaxGasFeeAllowedInGwei,
+			config.Configuration.Ethereum.EndpointMinimumPeers,
+		)
+		if err != nil {
+			cleanup()
+			logger.Fatalf("Could not connect to Ethereum: %v", err)
+		}
+		s.eth = eth
+		closers = append(closers, eth.Close)
+	}
+
+	s.client = &localrpc.Client{Address: config.Configuration.Transport.LocalStateListeningAddress, TimeOut: constants.MsgTimeout}
+	if err := s.client.Connect(ctx); err != nil {
+		cleanup()
+		logger.Fatalf("Could not connect to the node at %v: %v", s.client.Address, err)
+	}
+	closers = append(closers, func() { s.client.Close() })
+	return s, cleanup
+}
+
+func deposit(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("bridge").WithField("method", "deposit")
+	amount, ok := new(big.Int).SetString(args[0], 10)
+	if !ok || amount.Sign() <= 0 {
+		logger.Fatalf("Invalid amount %q", args[0])
+	}
+	accountType := constants.CurveSecp256k1
+	if len(args) > 2 {
+		switch strings.ToLower(args[2]) {
+		case "secp":
+		case "bn":
+			accountType = constants.CurveBN256Eth
+		default:
+			logger.Fatalf("Invalid account type %q, use secp or bn", args[2])
+		}
+	}
+
+	s, cleanup := newSetup(logger, true)
+	defer cleanup()
+	owner := s.eth.GetDefaultAccount().Address
+	if len(args) > 1 {
+		if !common.IsHexAddress(args[1]) {
+			logger.Fatalf("Invalid owner address %q", args[1])
+		}
+		owner = common.HexToAddress(args[1])
+	}
+
+	// the watcher state is only needed while the command runs
+	rawDB, err := utils.OpenBadger(s.ctx.Done(), "", true)
+	if err != nil {
+		logger.Fatalf("Could not open the database: %v", err)
+	}
+	watcherDB := &db.Database{}
+	watcherDB.Init(rawDB)
+	watcher := transaction.WatcherFromNetwork(s.eth, watcherDB, false, constants.TxPollingTime)
+	defer watcher.Close()
+
+	contracts := handlers.NewAllSmartContractsHandle(s.eth, common.HexToAddress(config.Configuration.Ethereum.FactoryAddress))
+	depositor := alcb.NewDepositor(s.eth, contracts, watcher, s.eth.GetDefaultAccount(), logger)
+	d, err := depositor.Deposit(s.ctx, accountType, owner, amount)
+	if err != nil {
+		logger.Fatalf("Deposit failed: %v", err)
+	}
+	fmt.Printf("Deposit %v of %v ALCB to %v mined in %v\n", d.Nonce, d.Amount, d.Owner.Hex(), d.TxHash.Hex())
+
+	st, err := alcb.WaitForDeposit(s.ctx, s.client, d.Nonce, constants.MonitorInterval, logger)
+	if err != nil {
+		logger.Fatalf("Failed waiting for the deposit UTXO: %v", err)
+	}
+	fmt.Printf("Deposit UTXO %x is available\n", st.UTXOID)
+}
+
+func status(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("bridge").WithField("method", "status")
+	nonce, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		logger.Fatalf("Invalid deposit nonce %q: %v", args[0], err)
+	}
+
+	s, cleanup := newSetup(logger, false)
+	defer cleanup()
+	st, err := s.client.GetDepositStatus(s.ctx, uint32(nonce))
+	if err != nil {
+		logger.Fatalf("Request failed: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Nonce:\t%v\n", nonce)
+	fmt.Fprintf(w, "Seen:\t%v\n", st.Seen)
+	fmt.Fprintf(w, "Processed:\t%v\n", st.Processed)
+	fmt.Fprintf(w, "LatestDepositSeen:\t%v\n", st.LatestDepositSeen)
+	fmt.Fprintf(w, "LatestDepositProcessed:\t%v\n", st.LatestDepositProcessed)
+	fmt.Fprintf(w, "UTXOID:\t%x\n", st.UTXOID)
+	if st.UTXO != nil {
+		value, err := st.UTXO.Value()
+		if err != nil {
+			logger.Fatalf("Invalid deposit UTXO: %v", err)
+		}
+		fmt.Fprintf(w, "Value:\t%v\n", value)
+		fmt.Fprintf(w, "Spent:\t%v\n", st.Spent)
+	}
+	if err := w.Flush(); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func burn(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("bridge").WithField("method", "burn")
+	amount := new(uint256.Uint256)
+	if v, ok := new(big.Int).SetString(args[0], 10); !ok || v.Sign() <= 0 {
+		logger.Fatalf("Invalid amount %q", args[0])
+	} else if _, err := amount.FromBigInt(v); err != nil {
+		logger.Fatalf("Invalid amount %q: %v", args[0], err)
+	}
+
+	s, cleanup := newSetup(logger, true)
+	defer cleanup()
+	signer, err := s.eth.CreateSecp256k1Signer()
+	if err != nil {
+		logger.Fatalf("Could not load the key of the default account: %v", err)
+	}
+	txHash, err := alcb.Burn(s.ctx, s.client, signer, amount, constants.MonitorInterval, logger)
+	if err != nil {
+		logger.Fatalf("Burn failed: %v", err)
+	}
+	printProof(s, txHash)
+}
+
+func proof(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("bridge").WithField("method", "proof")
+	txHash, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+	if err != nil || len(txHash) != constants.HashLen {
+		logger.Fatalf("Invalid tx hash %q", args[0])
+	}
+
+	s, cleanup := newSetup(logger, false)
+	defer cleanup()
+	printProof(s, txHash)
+}
+
+func printProof(s *setup, txHash []byte) {
+	p, err := alcb.MakeBurnProof(s.ctx, s.client, txHash)
+	if err != nil {
+		s.logger.Fatalf("Could not build the proof of %x: %v", txHash, err)
+	}
+	if _, err := p.Verify(); err != nil {
+		s.logger.Fatalf("The proof of %x does not verify: %v", txHash, err)
+	}
+	out, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		s.logger.Fatal(err)
+	}
+	fmt.Println(string(out))
+}
@@ -195,9 +195,12 @@ func initializeFilesAndFolders(cmd *cobra.Command, args []string) {
 			BootNodeAddresses:          "<BOOTNODE_ADDRESS>",
 			OriginLimit:                3,
 			LocalStateListeningAddress: "0.0.0.0:8883",
+			AdminListeningAddress:      "127.0.0.1:8882",
 			P2PListeningAddress:        "0.0.0.0:4342",
 			PeerLimitMax:               24,
 			PeerLimitMin:               3,
+			SyncMsgRateLimit:           64,
+			SyncByteRateLimit:          4 * 1024 * 1024,
 		},
 		Ethereum: config.EthereumConfig{
 			Endpoint:                 ethereumEndpointURL,
@@ -211,6 +214,14 @@ func initializeFilesAndFolders(cmd *cobra.Command, args []string) {
 			TxMaxGasFeeAllowedInGwei: 500,
 			TxMetricsDisplay:         false,
 		},
+		Gas: config.GasConfig{
+			Strategy:              "suggested",
+			FeeHistoryBlocks:      20,
+			FeeHistoryPercentile:  50,
+			TipCapBumpPercentage:  50,
+			MaxTipCapInGwei:       100,
+			BudgetAlertPercentage: 80,
+		},
 		Utils: config.UtilsConfig{
 			Status: true,
 		},
@@ -9,11 +9,16 @@ import (
 	"time"
 
 	"github.com/alicenet/alicenet/cmd/bootnode"
+	"github.com/alicenet/alicenet/cmd/bridge"
 	"github.com/alicenet/alicenet/cmd/ethkey"
 	"github.com/alicenet/alicenet/cmd/firewalld"
 	"github.com/alicenet/alicenet/cmd/initialization"
+	"github.com/alicenet/alicenet/cmd/multisig"
 	"github.com/alicenet/alicenet/cmd/node"
+	"github.com/alicenet/alicenet/cmd/tasks"
+	"github.com/alicenet/alicenet/cmd/upgrade"
 	"github.com/alicenet/alicenet/cmd/utils"
+	"github.com/alicenet/alicenet/cmd/validator"
 	"github.com/alicenet/alicenet/config"
 	"github.com/alicenet/alicenet/logging"
 	"github.com/sirupsen/logrus"
@@ -142,6 +147,7 @@ func main() {
 			{"chain.transactionDBInMemory", "", "", &config.Configuration.Chain.TransactionDbInMemory},
 			{"chain.monitorDB", "", "", &config.Configuration.Chain.MonitorDbPath},
 			{"chain.monitorDBInMemory", "", "", &config.Configuration.Chain.MonitorDbInMemory},
+			{"chain.forkEpoch", "", "First epoch of the application fork rules, 0 to keep the rules from before the fork", &config.Configuration.Chain.ForkEpoch},
 			{"ethereum.endpoint", "", "", &config.Configuration.Ethereum.Endpoint},
 			{"ethereum.endpointMinimumPeers", "", "Minimum peers required", &config.Configuration.Ethereum.EndpointMinimumPeers},
 			{"ethereum.keystore", "", "", &config.Configuration.Ethereum.Keystore},
@@ -161,9 +167,16 @@ func main() {
 			{"transport.p2pListeningAddress", "", "", &config.Configuration.Transport.P2PListeningAddress},
 			{"transport.upnp", "", "", &config.Configuration.Transport.UPnP},
 			{"transport.localStateListeningAddress", "", "", &config.Configuration.Transport.LocalStateListeningAddress},
+			{"transport.adminListeningAddress", "", "Loopback address of the admin rpc service, empty to disable it", &config.Configuration.Transport.AdminListeningAddress},
 			{"transport.timeout", "", "", &config.Configuration.Transport.Timeout},
 			{"transport.firewallMode", "", "", &config.Configuration.Transport.FirewallMode},
 			{"transport.firewallHost", "", "", &config.Configuration.Transport.FirewallHost},
+			{"transport.consensusMsgRateLimit", "", "Consensus gossip messages per second accepted from each peer, 0 for unlimited", &config.Configuration.Transport.ConsensusMsgRateLimit},
+			{"transport.consensusByteRateLimit", "", "Consensus gossip bytes per second accepted from each peer, 0 for unlimited", &config.Configuration.Transport.ConsensusByteRateLimit},
+			{"transport.txGossipMsgRateLimit", "", "Transaction gossip messages per second accepted from each peer, 0 for unlimited", &config.Configuration.Transport.TxGossipMsgRateLimit},
+			{"transport.txGossipByteRateLimit", "", "Transaction gossip bytes per second accepted from each peer, 0 for unlimited", &config.Configuration.Transport.TxGossipByteRateLimit},
+			{"transport.syncMsgRateLimit", "", "Sync requests per second served to each peer, 0 for unlimited", &config.Configuration.Transport.SyncMsgRateLimit},
+			{"transport.syncByteRateLimit", "", "Sync bytes per second served to each peer, 0 for unlimited", &config.Configuration.Transport.SyncByteRateLimit},
 			{"firewalld.enabled", "", "", &config.Configuration.Firewalld.Enabled},
 			{"firewalld.socketFile", "", "", &config.Configuration.Firewalld.SocketFile},
 		},
@@ -181,7 +194,50 @@ func main() {
 
 		&firewalld.Command: {},
 
-		&node.Command: {},
+		&node.Command: {
+			{"gas.strategy", "", "Strategy computing the tip of layer1 transactions: suggested, feeHistory, fixed or exponential", &config.Configuration.Gas.Strategy},
+			{"gas.taskStrategies", "", "Comma separated task=strategy list overriding the strategy per task type", &config.Configuration.Gas.TaskStrategies},
+			{"gas.feeHistoryBlocks", "", "Number of blocks used by the feeHistory strategy", &config.Configuration.Gas.FeeHistoryBlocks},
+			{"gas.feeHistoryPercentile", "", "Percentile of the tips used by the feeHistory strategy", &config.Configuration.Gas.FeeHistoryPercentile},
+			{"gas.fixedTipCapInGwei", "", "Tip of the fixed strategy", &config.Configuration.Gas.FixedTipCapInGwei},
+			{"gas.tipCapBumpPercentage", "", "Increase of the tip on every retry of the exponential strategy", &config.Configuration.Gas.TipCapBumpPercentage},
+			{"gas.maxTipCapInGwei", "", "Maximum tip of the exponential strategy", &config.Configuration.Gas.MaxTipCapInGwei},
+			{"gas.epochBudgetInGwei", "", "Maximum layer1 fees spent by the default account per AliceNet epoch, 0 to disable", &config.Configuration.Gas.EpochBudgetInGwei},
+			{"gas.budgetAlertPercentage", "", "Percentage of the epoch budget spent at which a warning is logged", &config.Configuration.Gas.BudgetAlertPercentage},
+			{"upgrade.enabled", "", "Upgrade the node automatically to the new versions announced on layer1", &config.Configuration.Upgrade.Enabled},
+			{"upgrade.dryRun", "", "Fetch, check and stage the new versions without restarting the node", &config.Configuration.Upgrade.DryRun},
+			{"upgrade.source", "", "Directory or https URL template of the node releases", &config.Configuration.Upgrade.Source},
+			{"upgrade.publicKey", "", "Hex encoded ed25519 public key the node releases are signed with, required unless layer1 announces their binary hash", &config.Configuration.Upgrade.PublicKey},
+			{"upgrade.stagingDir", "", "Directory where the node releases are staged", &config.Configuration.Upgrade.StagingDir},
+		},
+
+		&tasks.Command:          {},
+		&tasks.ListCommand:      {},
+		&tasks.ShowCommand:      {},
+		&tasks.KillCommand:      {},
+		&tasks.RetryCommand:     {},
+		&tasks.GasCommand:       {},
+		&tasks.SnapshotsCommand: {},
+		&tasks.TrafficCommand:   {},
+
+		&validator.Command:           {},
+		&validator.StakeCommand:      {},
+		&validator.RegisterCommand:   {},
+		&validator.StatusCommand:     {},
+		&validator.UnregisterCommand: {},
+		&validator.ClaimCommand:      {},
+		&bridge.Command:              {},
+		&bridge.DepositCommand:       {},
+		&bridge.StatusCommand:        {},
+		&bridge.BurnCommand:          {},
+		&bridge.ProofCommand:         {},
+		&upgrade.Command:             {},
+		&upgrade.StatusCommand:       {},
+		&multisig.Command:            {},
+		&multisig.OwnerCommand:       {},
+		&multisig.SignCommand:        {},
+		&multisig.AggregateCommand:   {},
+		&multisig.CombineCommand:     {},
 
 		&ethkey.Generate: {
 			{"ethkey.passwordfile", "", "the file that contains the password for the keyfile", &config.Configuration.EthKey.PasswordFile},
@@ -210,15 +266,41 @@ func main() {
 
 	// Establish command hierarchy
 	hierarchy := map[*cobra.Command]*cobra.Command{
-		&firewalld.Command:      &rootCommand,
-		&bootnode.Command:       &rootCommand,
-		&node.Command:           &rootCommand,
-		&ethkey.Generate:        &rootCommand,
-		&ethkey.Inspect:         &rootCommand,
-		&ethkey.ChangePassword:  &rootCommand,
-		&utils.Command:          &rootCommand,
-		&utils.SendWeiCommand:   &utils.Command,
-		&initialization.Command: &rootCommand,
+		&firewalld.Command:           &rootCommand,
+		&bootnode.Command:            &rootCommand,
+		&node.Command:                &rootCommand,
+		&ethkey.Generate:             &rootCommand,
+		&ethkey.Inspect:              &rootCommand,
+		&ethkey.ChangePassword:       &rootCommand,
+		&utils.Command:               &rootCommand,
+		&utils.SendWeiCommand:        &utils.Command,
+		&initialization.Command:      &rootCommand,
+		&tasks.Command:               &rootCommand,
+		&tasks.ListCommand:           &tasks.Command,
+		&tasks.ShowCommand:           &tasks.Command,
+		&tasks.KillCommand:           &tasks.Command,
+		&tasks.RetryCommand:          &tasks.Command,
+		&tasks.GasCommand:            &tasks.Command,
+		&tasks.SnapshotsCommand:      &tasks.Command,
+		&tasks.TrafficCommand:        &tasks.Command,
+		&validator.Command:           &rootCommand,
+		&validator.StakeCommand:      &validator.Command,
+		&validator.RegisterCommand:   &validator.Command,
+		&validator.StatusCommand:     &validator.Command,
+		&validator.UnregisterCommand: &validator.Command,
+		&validator.ClaimCommand:      &validator.Command,
+		&bridge.Command:              &rootCommand,
+		&bridge.DepositCommand:       &bridge.Command,
+		&bridge.StatusCommand:        &bridge.Command,
+		&bridge.BurnCommand:          &bridge.Command,
+		&bridge.ProofCommand:         &bridge.Command,
+		&upgrade.Command:             &rootCommand,
+		&upgrade.StatusCommand:       &upgrade.Command,
+		&multisig.Command:            &rootCommand,
+		&multisig.OwnerCommand:       &multisig.Command,
+		&multisig.SignCommand:        &multisig.Command,
+		&multisig.AggregateCommand:   &multisig.Command,
+		&multisig.CombineCommand:     &multisig.Command,
 	}
 
 	// Convert option abstraction into concrete settings for Cobra and Viper
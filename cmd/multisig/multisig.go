@@ -0,0 +1,227 @@
+package multisig
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/alicenet/alicenet/application/objs"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/logging"
+)
+
+// Command is the cobra.Command grouping the commands that collect the
+// signatures of multisig owners offline.
+var Command = cobra.Command{
+	Use:   "multisig",
+	Short: "Create multisig owners and collect their signatures offline",
+	Long: "multisig works without a node. The message signed by the owners is the marshalled " +
+		"TXInLinker of the input consuming the object, or the marshalled DSLinker of a new DataStore",
+}
+
+// OwnerCommand prints a multisig owner and its account.
+var OwnerCommand = cobra.Command{
+	Use:   "owner <threshold> <secp|bn> <public key>...",
+	Short: "Print the hex encoded multisig owner and the account objects are sent to",
+	Long: "owner commits to the hex encoded public keys of which threshold must sign. " +
+		"A threshold of 0 with a single bn public key uses a bn256 group public key",
+	Args: cobra.MinimumNArgs(3),
+	Run:  owner,
+}
+
+// SignCommand prints the partial signature of one owner.
+var SignCommand = cobra.Command{
+	Use:   "sign <owner> <message> <private key file>",
+	Short: "Print the partial signature of the message with the hex encoded private key in the file",
+	Long: "sign uses the share of the group secret key for a group owner; the shares are then " +
+		"aggregated with the aggregate command",
+	Args: cobra.ExactArgs(3),
+	Run:  sign,
+}
+
+// AggregateCommand aggregates the signature shares of a group owner.
+var AggregateCommand = cobra.Command{
+	Use:   "aggregate <owner> <group shares file> <signature share>...",
+	Short: "Print the group signature aggregated from the signature shares",
+	Long: "aggregate needs the file with the hex encoded public key shares of all the group " +
+		"members, one per line and in the order of the group",
+	Args: cobra.MinimumNArgs(3),
+	Run:  aggregate,
+}
+
+// CombineCommand combines the partial signatures into the signature of the
+// owner.
+var CombineCommand = cobra.Command{
+	Use:   "combine <owner> <message> <partial signature>...",
+	Short: "Print the signature to place in TXIn.Signature or DataStore.Signature",
+	Args:  cobra.MinimumNArgs(3),
+	Run:   combine,
+}
+
+func owner(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("multisig").WithField("method", "owner")
+	threshold, err := strconv.ParseUint(args[0], 10, 8)
+	if err != nil {
+		logger.Fatalf("Invalid threshold %q: %v", args[0], err)
+	}
+	curveSpec := constants.CurveSecp256k1
+	switch strings.ToLower(args[1]) {
+	case "secp":
+	case "bn":
+		curveSpec = constants.CurveBN256Eth
+	default:
+		logger.Fatalf("Invalid curve %q, use secp or bn", args[1])
+	}
+	pubks := make([][]byte, len(args[2:]))
+	for i, arg := range args[2:] {
+		pubks[i] = decodeHex(logger, "public key", arg)
+	}
+
+	mso := &objs.MultiSigOwner{}
+	if threshold == 0 {
+		if curveSpec != constants.CurveBN256Eth || len(pubks) != 1 {
+			logger.Fatal("A group owner needs a single bn public key")
+		}
+		err = mso.NewGroup(pubks[0])
+	} else {
+		err = mso.New(curveSpec, uint8(threshold), pubks)
+	}
+	if err != nil {
+		logger.Fatalf("Invalid owner: %v", err)
+	}
+	ownerBytes, err := mso.MarshalBinary()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	account, err := mso.Account()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	fmt.Printf("Owner:\t%x\n", ownerBytes)
+	fmt.Printf("Account:\t%x\n", account)
+}
+
+func sign(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("multisig").WithField("method", "sign")
+	mso := decodeOwner(logger, args[0])
+	msg := decodeHex(logger, "message", args[1])
+	privk := readHexFile(logger, args[2])
+
+	var sig []byte
+	var err error
+	switch {
+	case mso.IsGroup():
+		signer := &crypto.BNGroupSigner{}
+		if err := signer.SetPrivk(privk); err != nil {
+			logger.Fatalf("Invalid private key: %v", err)
+		}
+		sig, err = mso.SignGroupShare(msg, signer)
+	case mso.CurveSpec == constants.CurveSecp256k1:
+		signer := &crypto.Secp256k1Signer{}
+		if err := signer.SetPrivk(privk); err != nil {
+			logger.Fatalf("Invalid private key: %v", err)
+		}
+		sig, err = mso.Sign(msg, signer)
+	default:
+		signer := &crypto.BNSigner{}
+		if err := signer.SetPrivk(privk); err != nil {
+			logger.Fatalf("Invalid private key: %v", err)
+		}
+		sig, err = mso.Sign(msg, signer)
+	}
+	if err != nil {
+		logger.Fatalf("Signing failed: %v", err)
+	}
+	fmt.Printf("%x\n", sig)
+}
+
+func aggregate(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("multisig").WithField("method", "aggregate")
+	mso := decodeOwner(logger, args[0])
+	if !mso.IsGroup() {
+		logger.Fatal("The owner is not a group owner")
+	}
+	data, err := os.ReadFile(args[1])
+	if err != nil {
+		logger.Fatalf("Could not read %v: %v", args[1], err)
+	}
+	groupShares := [][]byte{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			groupShares = append(groupShares, decodeHex(logger, "public key share", line))
+		}
+	}
+	sigs := make([][]byte, len(args[2:]))
+	for i, arg := range args[2:] {
+		sigs[i] = decodeHex(logger, "signature share", arg)
+	}
+
+	signer := &crypto.BNGroupSigner{}
+	if err := signer.SetGroupPubk(mso.PublicKeys[0]); err != nil {
+		logger.Fatalf("Invalid group public key: %v", err)
+	}
+	sig, err := signer.Aggregate(sigs, groupShares)
+	if err != nil {
+		logger.Fatalf("Aggregation failed: %v", err)
+	}
+	fmt.Printf("%x\n", sig)
+}
+
+func combine(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("multisig").WithField("method", "combine")
+	mso := decodeOwner(logger, args[0])
+	msg := decodeHex(logger, "message", args[1])
+	sigs := make([][]byte, len(args[2:]))
+	for i, arg := range args[2:] {
+		sigs[i] = decodeHex(logger, "partial signature", arg)
+	}
+
+	mss, err := mso.Combine(msg, sigs)
+	if err != nil {
+		logger.Fatalf("Combining failed: %v", err)
+	}
+	// the ValueStore and DataStore encodings of a multisig signature are the same
+	vss, err := mss.ValueStoreSignature()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	sig, err := vss.MarshalBinary()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	fmt.Printf("%x\n", sig)
+}
+
+func decodeOwner(logger *logrus.Entry, arg string) *objs.MultiSigOwner {
+	mso := &objs.MultiSigOwner{}
+	rest, err := mso.UnmarshalBinary(decodeHex(logger, "owner", arg))
+	if err != nil {
+		logger.Fatalf("Invalid owner: %v", err)
+	}
+	if len(rest) != 0 {
+		logger.Fatalf("Invalid owner: trailing bytes")
+	}
+	return mso
+}
+
+func decodeHex(logger *logrus.Entry, name string, arg string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+	if err != nil {
+		logger.Fatalf("Invalid %v %q: %v", name, arg, err)
+	}
+	return b
+}
+
+func readHexFile(logger *logrus.Entry, path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Fatalf("Could not read %v: %v", path, err)
+	}
+	return decodeHex(logger, "private key", strings.TrimSpace(string(data)))
+}
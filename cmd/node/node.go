@@ -28,6 +28,7 @@ import (
 	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/evm"
 	"github.com/alicenet/alicenet/layer1/executor"
+	"github.com/alicenet/alicenet/layer1/gas"
 	"github.com/alicenet/alicenet/layer1/handlers"
 	"github.com/alicenet/alicenet/layer1/monitor"
 	"github.com/alicenet/alicenet/layer1/transaction"
@@ -36,6 +37,7 @@ import (
 	"github.com/alicenet/alicenet/peering"
 	"github.com/alicenet/alicenet/proto"
 	"github.com/alicenet/alicenet/status"
+	"github.com/alicenet/alicenet/upgrade"
 	aUtils "github.com/alicenet/alicenet/utils"
 	"github.com/dgraph-io/badger/v2"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -54,7 +56,7 @@ var Command = cobra.Command{
 
 func initEthereumConnection(
 	logger *logrus.Logger,
-) (layer1.Client, layer1.AllSmartContracts, *mncrypto.Secp256k1Signer, []byte) {
+) (*evm.Client, layer1.AllSmartContracts, *mncrypto.Secp256k1Signer, []byte) {
 	// Ethereum connection setup
 	logger.Infof("Connecting to Ethereum...")
 	eth, err := evm.NewClient(
@@ -71,6 +73,12 @@ func initEthereumConnection(
 		logger.Fatalf("NewEthereumEndpoint(...) failed: %v", err)
 		panic(err)
 	}
+	gasStrategies, err := gas.NewStrategies(config.Configuration.Gas)
+	if err != nil {
+		logger.Fatalf("Invalid gas configuration: %v", err)
+	}
+	eth.SetGasStrategies(gasStrategies)
+
 	// Load the ethereum state
 	if !eth.IsAccessible() {
 		logger.Fatal("Ethereum endpoint not accessible...")
@@ -122,6 +130,10 @@ func initPeerManager(
 		panic(err)
 	}
 	p2pDispatch.RegisterP2PGetPeers(peerManager)
+	p2pDispatch.RegisterP2PGossipTransactionBatch(peerManager)
+	p2pDispatch.RegisterP2PGossipConsensusBatch(peerManager)
+	p2pDispatch.RegisterP2PGossipTxInventory(peerManager)
+	peerManager.SetTxInventory(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipTransaction(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipProposal(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipPreVote(consGossipHandlers)
@@ -161,17 +173,41 @@ func initLocalStateServer(localStateHandler *localrpc.Handlers) *localrpc.Handle
 	localStateDispatch.RegisterLocalStateGetUTXO(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetTransactionStatus(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetMinedTransaction(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetMinedTransactionsByMemo(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetStateBatch(localStateHandler)
+	localStateDispatch.RegisterLocalStateValidateTransaction(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetPendingTransaction(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetRoundStateForValidator(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetValidatorSet(localStateHandler)
 	localStateDispatch.RegisterLocalStateIterateNameSpace(localStateHandler)
+	localStateDispatch.RegisterLocalStateQueryNameSpace(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetData(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetTxBlockNumber(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetFees(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetDepositStatus(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetDataStoreStatus(localStateHandler)
+	localStateDispatch.RegisterLocalStateQueryLayer1Events(localStateHandler)
 
 	return localStateServer
 }
 
+// Setup the admin RPC server, used by the node operator to inspect and control the layer1 tasks.
+// It returns nil if the admin service is disabled.
+func initAdminServer(adminHandler *localrpc.AdminHandlers) *localrpc.AdminHandler {
+	if config.Configuration.Transport.AdminListeningAddress == "" {
+		return nil
+	}
+	adminServer, err := localrpc.NewAdminServerHandler(
+		logging.GetLogger(constants.LoggerTransport),
+		config.Configuration.Transport.AdminListeningAddress,
+		adminHandler,
+	)
+	if err != nil {
+		panic(err)
+	}
+	return adminServer
+}
+
 func initDatabase(ctx context.Context, path string, inMemory bool) *badger.DB {
 	db, err := aUtils.OpenBadger(ctx.Done(), path, inMemory)
 	if err != nil {
@@ -186,6 +222,24 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	logger.Infof("Starting node with args %v", args)
 	defer func() { logger.Warning("Graceful unwind of core process complete.") }()
 
+	// restart into the staged version once every service has unwound
+	var upgrader *upgrade.Manager
+	restartUpgrade := false
+	defer func() {
+		if !restartUpgrade {
+			return
+		}
+		path, err := upgrader.Install()
+		if err != nil {
+			logger.Errorf("Could not install the new version: %v", err)
+			return
+		}
+		logger.Warnf("Restarting into %v", path)
+		if err := syscall.Exec(path, os.Args, os.Environ()); err != nil {
+			logger.Errorf("Could not restart into the new version: %v", err)
+		}
+	}()
+
 	// create execution context for application
 	ctx := context.Background()
 	nodeCtx, cf := context.WithCancel(ctx)
@@ -304,7 +358,11 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	consTxPool := evidence.NewPool(consDB)
 
 	appDepositHandler.Init()
-	if err := app.Init(consDB, rawTxPoolDb, appDepositHandler, storage); err != nil {
+	if config.Configuration.Chain.ForkEpoch > uint64(constants.MaxUint32) {
+		panic(fmt.Sprintf("chain.forkEpoch %v is not a valid epoch", config.Configuration.Chain.ForkEpoch))
+	}
+	forkEpoch := uint32(config.Configuration.Chain.ForkEpoch)
+	if err := app.Init(consDB, rawTxPoolDb, appDepositHandler, storage, forkEpoch); err != nil {
 		panic(err)
 	}
 
@@ -345,6 +403,20 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	// Setup monitor
 	monDB.Init(rawMonitorDb)
 
+	// latest AliceNet height known by the node
+	currentHeight := func() (uint32, error) {
+		var height uint32
+		err := consDB.View(func(txn *badger.Txn) error {
+			ownState, err := consDB.GetOwnState(txn)
+			if err != nil {
+				return err
+			}
+			height = ownState.SyncToBH.BClaims.Height
+			return nil
+		})
+		return height, err
+	}
+
 	// Layer 1 transaction watcher
 	txWatcher := transaction.WatcherFromNetwork(
 		eth,
@@ -354,6 +426,26 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	)
 	defer txWatcher.Close()
 
+	var gasBudget *gas.Budget
+	if config.Configuration.Gas.EpochBudgetInGwei > 0 {
+		gasBudget, err = gas.NewBudget(
+			eth.GetDefaultAccount().Address,
+			config.Configuration.Gas.EpochBudgetInGwei,
+			config.Configuration.Gas.BudgetAlertPercentage,
+			func() (uint32, error) {
+				height, err := currentHeight()
+				return aUtils.Epoch(height), err
+			},
+			monDB,
+			logger.WithField("Component", "gasBudget"),
+		)
+		if err != nil {
+			panic(err)
+		}
+		eth.SetGasBudget(gasBudget)
+		txWatcher.SetGasBudget(gasBudget)
+	}
+
 	// Setup tasks scheduler
 	tasksHandler, err := executor.NewTaskHandler(
 		monDB,
@@ -366,6 +458,13 @@ func validatorNode(cmd *cobra.Command, args []string) {
 		panic(err)
 	}
 
+	adminHandler := &localrpc.AdminHandlers{}
+	adminHandler.Init(tasksHandler.(executor.TaskInspector))
+	adminHandler.SetGasReporting(txWatcher, gasBudget)
+	adminHandler.SetSnapshotReports(monDB)
+	adminHandler.SetPeerTraffic(peerManager)
+	adminServer := initAdminServer(adminHandler)
+
 	monitorInterval := constants.MonitorInterval
 	mon, err := monitor.NewMonitor(
 		consDB,
@@ -407,6 +506,31 @@ func validatorNode(cmd *cobra.Command, args []string) {
 		storage,
 	)
 	localStateHandler.Init(consDB, app, consGossipHandlers, publicKey, consSync.Safe, storage)
+	localStateHandler.SetDepositTracker(mon)
+	localStateHandler.SetEventIndex(mon)
+
+	executable, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	upgrader, err = upgrade.NewManager(
+		config.Configuration.Upgrade,
+		executable,
+		currentHeight,
+		logger.WithField("Component", "upgrade"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if config.Configuration.Upgrade.Enabled {
+		if err := mon.Subscribe(upgrader.Subscription()); err != nil {
+			panic(err)
+		}
+	}
+	// a new canonical major version restarts the node into the staged release
+	// instead of only shutting it down
+	mon.SetUpgradeFunc(upgrader.VersionCanonical)
+	adminHandler.SetUpgradeStatus(upgrader)
 	statusLogger.Init(consLSEngine, peerManager, consAdminHandlers, mon)
 
 	//////////////////////////////////////////////////////////////////////////////
@@ -425,6 +549,10 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	}
 	defer mon.Close()
 
+	upgrader.Start()
+	defer upgrader.Close()
+	upgrader.VersionAvailable(latestVersion)
+
 	go peerManager.Start()
 	defer peerManager.Close()
 
@@ -440,6 +568,11 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	go localStateHandler.Start()
 	defer localStateHandler.Stop()
 
+	if adminServer != nil {
+		go adminServer.Serve()
+		defer adminServer.Close()
+	}
+
 	go consGossipHandlers.Start()
 	defer consGossipHandlers.Close()
 
@@ -456,6 +589,8 @@ func validatorNode(cmd *cobra.Command, args []string) {
 	case <-consSync.CloseChan():
 	case <-mon.CloseChan():
 	case <-tasksHandler.CloseChan():
+	case <-upgrader.RestartChan():
+		restartUpgrade = true
 	case <-signals:
 	}
 	go countSignals(logger, 5, signals)
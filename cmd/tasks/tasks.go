@@ -0,0 +1,296 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/logging"
+	pb "github.com/alicenet/alicenet/proto"
+)
+
+// Command is the cobra.Command grouping the commands that inspect and control
+// the layer1 tasks of a running node through its admin rpc service.
+var Command = cobra.Command{
+	Use:   "tasks",
+	Short: "Inspect and control the layer1 tasks of a running node",
+	Long:  "tasks talks to the admin rpc service of a running node, set with transport.adminListeningAddress, to inspect and control its DKG, snapshot and other layer1 tasks",
+}
+
+// ListCommand lists the scheduled and recently finished tasks.
+var ListCommand = cobra.Command{
+	Use:   "list",
+	Short: "List the scheduled and recently finished tasks",
+	Args:  cobra.NoArgs,
+	Run:   list,
+}
+
+// ShowCommand prints all the information of a task.
+var ShowCommand = cobra.Command{
+	Use:   "show <task id>",
+	Short: "Show a task",
+	Args:  cobra.ExactArgs(1),
+	Run:   show,
+}
+
+// KillCommand kills a scheduled task.
+var KillCommand = cobra.Command{
+	Use:   "kill <task id>",
+	Short: "Kill a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	Run:   kill,
+}
+
+// RetryCommand makes a running task retry right away after a failed attempt.
+var RetryCommand = cobra.Command{
+	Use:   "retry <task id>",
+	Short: "Retry a running task right away instead of waiting for its retry delay",
+	Args:  cobra.ExactArgs(1),
+	Run:   retry,
+}
+
+// GasCommand prints the layer1 fees spent per task type and the epoch budget.
+var GasCommand = cobra.Command{
+	Use:   "gas",
+	Short: "Show the gas and fees spent per task type and contract function, and the epoch gas budget",
+	Args:  cobra.NoArgs,
+	Run:   gasReport,
+}
+
+// SnapshotsCommand prints who was elected to submit each snapshot and who
+// submitted it.
+var SnapshotsCommand = cobra.Command{
+	Use:   "snapshots [from height]",
+	Short: "Show the elected and actual submitters of the snapshots, their latency and gas",
+	Long:  "snapshots prints, for every snapshot seen by the node, the first elected validator, the submitter and its rank in the leader order, the layer1 blocks it took and the gas used. A summary per validator counts the snapshots each one led, submitted and skipped while being allowed to submit",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   snapshotReports,
+}
+
+// TrafficCommand prints the traffic exchanged with each active peer.
+var TrafficCommand = cobra.Command{
+	Use:   "traffic",
+	Short: "Show the bytes and messages exchanged with each active peer, per p2p method",
+	Args:  cobra.NoArgs,
+	Run:   peerTraffic,
+}
+
+// withClient connects to the admin service and calls fn with a client.
+func withClient(logger *logrus.Entry, fn func(ctx context.Context, client pb.AdminClient) error) {
+	address := config.Configuration.Transport.AdminListeningAddress
+	if address == "" {
+		logger.Fatal("The admin rpc address was not specified, set transport.adminListeningAddress")
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), constants.MsgTimeout)
+	defer cf()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		logger.Fatalf("Failed to connect to the admin rpc service at %v: %v", address, err)
+	}
+	defer conn.Close()
+
+	if err := fn(ctx, pb.NewAdminClient(conn)); err != nil {
+		logger.Fatalf("Request failed: %v", err)
+	}
+}
+
+func list(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "list")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		resp, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSTATE\tSTART\tEND\tATTEMPTS\tLAST ERROR")
+		for _, task := range resp.Tasks {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", task.Id, task.Name, taskState(task), task.Start, task.End, task.Attempts, lastError(task))
+		}
+		return w.Flush()
+	})
+}
+
+func show(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "show")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		resp, err := client.GetTask(ctx, &pb.GetTaskRequest{Id: args[0]})
+		if err != nil {
+			return err
+		}
+		task := resp.Task
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Id:\t%v\n", task.Id)
+		fmt.Fprintf(w, "Name:\t%v\n", task.Name)
+		fmt.Fprintf(w, "State:\t%v\n", taskState(task))
+		fmt.Fprintf(w, "Start:\t%v\n", task.Start)
+		fmt.Fprintf(w, "End:\t%v\n", task.End)
+		fmt.Fprintf(w, "AllowMultiExecution:\t%v\n", task.AllowMultiExecution)
+		fmt.Fprintf(w, "Attempts:\t%v\n", task.Attempts)
+		fmt.Fprintf(w, "LastErr:\t%v\n", task.LastErr)
+		fmt.Fprintf(w, "TxHash:\t%v\n", task.TxHash)
+		if task.Finished {
+			fmt.Fprintf(w, "FinishedOnBlock:\t%v\n", task.ReceivedOnBlock)
+			fmt.Fprintf(w, "Err:\t%v\n", task.Err)
+		}
+		return w.Flush()
+	})
+}
+
+func kill(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "kill")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		_, err := client.KillTask(ctx, &pb.KillTaskRequest{Id: args[0]})
+		return err
+	})
+	fmt.Printf("Task %v killed\n", args[0])
+}
+
+func retry(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "retry")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		_, err := client.RetryTask(ctx, &pb.RetryTaskRequest{Id: args[0]})
+		return err
+	})
+	fmt.Printf("Task %v retrying\n", args[0])
+}
+
+// taskState of a task, finished tasks are reported as Succeeded or Failed.
+func taskState(task *pb.TaskInfo) string {
+	if !task.Finished {
+		return task.State
+	}
+	if task.Err != "" {
+		return "Failed"
+	}
+	return "Succeeded"
+}
+
+// lastError of a task, the error it finished with or the one of its last attempt.
+func lastError(task *pb.TaskInfo) string {
+	if task.Finished && task.Err != "" {
+		return task.Err
+	}
+	return task.LastErr
+}
+
+func gasReport(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "gas")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		resp, err := client.GetGasReport(ctx, &pb.GetGasReportRequest{})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if resp.HasBudget {
+			fmt.Fprintf(w, "Budget of %v in epoch %v:\t%v of %v wei spent, %v wei reserved\n", resp.BudgetAccount, resp.BudgetEpoch, resp.BudgetSpent, resp.BudgetLimit, resp.BudgetReserved)
+			fmt.Fprintln(w)
+		}
+		for _, section := range []struct {
+			title    string
+			profiles []*pb.GasProfile
+		}{{"TASK", resp.Tasks}, {"FUNCTION", resp.Functions}} {
+			fmt.Fprintf(w, "%v\tTXS\tSUCCESS\tAVERAGE GAS\tTOTAL GAS\tSPENT (WEI)\n", section.title)
+			for _, p := range section.profiles {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", p.Name, p.TotalCount, p.TotalSuccess, p.AverageGas, p.TotalGas, p.TotalSpent)
+			}
+			fmt.Fprintln(w)
+		}
+		return w.Flush()
+	})
+}
+
+// validatorSnapshots counts how a validator took part in the snapshots.
+type validatorSnapshots struct {
+	led, submitted, skipped int
+}
+
+func snapshotReports(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "snapshots")
+	fromHeight := uint64(0)
+	if len(args) == 1 {
+		var err error
+		fromHeight, err = strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			logger.Fatalf("Invalid height %v: %v", args[0], err)
+		}
+	}
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HEIGHT	LEADER	SUBMITTER	RANK	ELECTION START	ALLOWED AT	COMMITTED AT	LATENCY	GAS USED")
+		validators := make(map[string]*validatorSnapshots)
+		count := func(validator string) *validatorSnapshots {
+			if _, ok := validators[validator]; !ok {
+				validators[validator] = &validatorSnapshots{}
+			}
+			return validators[validator]
+		}
+
+		next := uint32(fromHeight)
+		for {
+			resp, err := client.GetSnapshotReports(ctx, &pb.GetSnapshotReportsRequest{FromHeight: next})
+			if err != nil {
+				return err
+			}
+			for _, r := range resp.Reports {
+				leader := ""
+				if len(r.LeaderOrder) > 0 {
+					leader = r.LeaderOrder[0]
+					count(leader).led++
+				}
+				count(r.Submitter).submitted++
+				for rank := 0; rank < int(r.SubmitterRank); rank++ {
+					count(r.LeaderOrder[rank]).skipped++
+				}
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", r.Height, leader, r.Submitter, r.SubmitterRank, r.ElectionStart, r.SubmitterAllowedAt, r.CommittedAt, r.Latency, r.GasUsed)
+			}
+			if resp.NextHeight == 0 {
+				break
+			}
+			next = resp.NextHeight
+		}
+
+		names := make([]string, 0, len(validators))
+		for validator := range validators {
+			names = append(names, validator)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "VALIDATOR	LED	SUBMITTED	SKIPPED")
+		for _, validator := range names {
+			v := validators[validator]
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", validator, v.led, v.submitted, v.skipped)
+		}
+		return w.Flush()
+	})
+}
+
+func peerTraffic(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("tasks").WithField("method", "traffic")
+	withClient(logger, func(ctx context.Context, client pb.AdminClient) error {
+		resp, err := client.GetPeerTraffic(ctx, &pb.GetPeerTrafficRequest{})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, peer := range resp.Peers {
+			fmt.Fprintf(w, "Peer %v:\t%v bytes read, %v bytes written\n", peer.P2PAddr, peer.BytesRead, peer.BytesWritten)
+			fmt.Fprintln(w, "METHOD\tMSGS IN\tMSGS OUT\tBYTES IN\tBYTES OUT\tTHROTTLED")
+			for _, m := range peer.Methods {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", m.Method, m.MsgsIn, m.MsgsOut, m.BytesIn, m.BytesOut, m.Throttled)
+			}
+			fmt.Fprintln(w)
+		}
+		return w.Flush()
+	})
+}
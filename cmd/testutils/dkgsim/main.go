@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/simulator"
+)
+
+// dkgsim runs an ETHDKG ceremony in process without a layer1 node, or audits
+// the ETHDKG logs of a ceremony that already happened.
+//
+//	dkgsim -n 5 -faults 1=bad-shares,3=missing-gpkj -out logs.json
+//	dkgsim -replay logs.json
+func main() {
+	participants := flag.Int("n", 4, "number of participants")
+	phaseLength := flag.Uint64("phase-length", 10, "ETHDKG phase length in blocks")
+	confirmations := flag.Uint64("confirmations", 2, "ETHDKG confirmation length in blocks")
+	faults := flag.String("faults", "", "comma separated index=fault list, e.g. 1=bad-shares,2=missing-gpkj")
+	out := flag.String("out", "", "file to write the emitted ETHDKG logs to as json")
+	replay := flag.String("replay", "", "json file with ETHDKG logs to audit instead of simulating")
+	verbose := flag.Bool("v", false, "print the task logs of every participant")
+	flag.Parse()
+
+	if *replay != "" {
+		if err := runReplay(*replay); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	faultMap, err := parseFaults(*faults)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cfg := simulator.Config{
+		Participants:       *participants,
+		PhaseLength:        *phaseLength,
+		ConfirmationLength: *confirmations,
+		Faults:             faultMap,
+	}
+	if *verbose {
+		logger := logrus.New()
+		logger.SetLevel(logrus.DebugLevel)
+		cfg.Logger = logrus.NewEntry(logger)
+	}
+	if err := runSimulation(cfg, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func parseFaults(value string) (map[int]simulator.Fault, error) {
+	faults := make(map[int]simulator.Fault)
+	if value == "" {
+		return faults, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid fault %q, expected index=fault", entry)
+		}
+		idx, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid participant index %q: %v", parts[0], err)
+		}
+		fault, err := simulator.ParseFault(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		faults[idx] = fault
+	}
+	return faults, nil
+}
+
+func runSimulation(cfg simulator.Config, out string) error {
+	s, err := simulator.NewSimulator(cfg)
+	if err != nil {
+		return err
+	}
+	result, err := s.Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Completed\n    %v\n", result.Completed)
+	fmt.Printf("Phase\n    %v\n", result.Phase)
+	fmt.Printf("Height\n    %v\n", result.Height)
+	fmt.Println("Participants")
+	for idx, participant := range result.Participants {
+		fault := cfg.Faults[idx]
+		fmt.Printf("    %v %v %v\n", idx, participant.Hex(), fault)
+	}
+	if len(result.Accusations) > 0 {
+		fmt.Println("Accusations")
+		for _, accusation := range result.Accusations {
+			fmt.Printf("    %v: %v accused %v at %v\n", accusation.Kind, accusation.Accuser.Hex(), accusation.Accused.Hex(), accusation.Height)
+		}
+	}
+	if result.Completed {
+		fmt.Printf("MasterPublicKey\n    %x\n", result.MasterPublicKey)
+	}
+
+	if out == "" {
+		return nil
+	}
+	raw, err := json.MarshalIndent(result.Logs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, raw, 0o600)
+}
+
+func runReplay(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var logs []types.Log
+	if err := json.Unmarshal(raw, &logs); err != nil {
+		return err
+	}
+	audit, err := simulator.Replay(logs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Nonce\n    %v\n", audit.Nonce)
+	fmt.Printf("Phase\n    %v\n", audit.Phase)
+	fmt.Printf("Completed\n    %v\n", audit.Completed)
+	fmt.Printf("Registered\n    %v of %v\n", len(audit.Participants), audit.NumberOfValidators)
+	printAddresses("MissingShares", audit.MissingShares)
+	printAddresses("MissingKeyShares", audit.MissingKeyShares)
+	printAddresses("MissingGPKj", audit.MissingGPKj)
+	printAddresses("BadGPKj", audit.BadGPKj)
+	fmt.Printf("MasterPublicKeyValid\n    %v\n", audit.MasterPublicKeyValid)
+	if audit.Completed {
+		fmt.Printf("GroupKeyMatches\n    %v\n", audit.GroupKeyMatches)
+	}
+	return nil
+}
+
+func printAddresses(title string, addresses []common.Address) {
+	if len(addresses) == 0 {
+		return
+	}
+	fmt.Println(title)
+	for _, address := range addresses {
+		fmt.Printf("    %v\n", address.Hex())
+	}
+}
@@ -0,0 +1,70 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/logging"
+	pb "github.com/alicenet/alicenet/proto"
+)
+
+// Command is the cobra.Command grouping the commands that follow the automatic
+// upgrades of a running node through its admin rpc service.
+var Command = cobra.Command{
+	Use:   "upgrade",
+	Short: "Follow the automatic upgrades of a running node",
+	Long:  "upgrade talks to the admin rpc service of a running node, set with transport.adminListeningAddress. Automatic upgrades are enabled on the node with the upgrade options",
+}
+
+// StatusCommand prints the status of the automatic upgrades.
+var StatusCommand = cobra.Command{
+	Use:   "status",
+	Short: "Show the version being staged or staged, and when the node restarts into it",
+	Args:  cobra.NoArgs,
+	Run:   status,
+}
+
+func status(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("upgrade").WithField("method", "status")
+	address := config.Configuration.Transport.AdminListeningAddress
+	if address == "" {
+		logger.Fatal("The admin rpc address was not specified, set transport.adminListeningAddress")
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), constants.MsgTimeout)
+	defer cf()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		logger.Fatalf("Failed to connect to the admin rpc service at %v: %v", address, err)
+	}
+	defer conn.Close()
+
+	s, err := pb.NewAdminClient(conn).GetUpgradeStatus(ctx, &pb.GetUpgradeStatusRequest{})
+	if err != nil {
+		logger.Fatalf("Request failed: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Enabled:\t%v\n", s.Enabled)
+	fmt.Fprintf(w, "DryRun:\t%v\n", s.DryRun)
+	fmt.Fprintf(w, "State:\t%v\n", s.State)
+	fmt.Fprintf(w, "CurrentEpoch:\t%v\n", s.CurrentEpoch)
+	if s.Version != "" {
+		fmt.Fprintf(w, "Version:\t%v\n", s.Version)
+		fmt.Fprintf(w, "ExecutionEpoch:\t%v\n", s.ExecutionEpoch)
+		fmt.Fprintf(w, "Artifact:\t%v\n", s.Artifact)
+		fmt.Fprintf(w, "Checksum:\t%v\n", s.Checksum)
+		fmt.Fprintf(w, "StagedPath:\t%v\n", s.StagedPath)
+		fmt.Fprintf(w, "Err:\t%v\n", s.Err)
+	}
+	if err := w.Flush(); err != nil {
+		logger.Fatal(err)
+	}
+}
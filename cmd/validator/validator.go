@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/staking"
+	"github.com/alicenet/alicenet/layer1/evm"
+	"github.com/alicenet/alicenet/layer1/handlers"
+	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/alicenet/alicenet/logging"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// Command is the cobra.Command grouping the commands that take an account
+// through the validator lifecycle on layer1.
+var Command = cobra.Command{
+	Use:   "validator",
+	Short: "Stake, register, inspect and leave the validator pool",
+	Long: "validator sends the layer1 transactions of the validator lifecycle from ethereum.defaultAccount, " +
+		"using the keystore and pass codes of the node configuration. Registering and unregistering " +
+		"validators can only be done by the owner of the AliceNetFactory",
+}
+
+// StakeCommand mints a PublicStaking position.
+var StakeCommand = cobra.Command{
+	Use:   "stake <amount>",
+	Short: "Stake an amount of ALCA, in its smallest unit, into a new PublicStaking position",
+	Args:  cobra.ExactArgs(1),
+	Run:   stake,
+}
+
+// RegisterCommand registers a validator with a PublicStaking position.
+var RegisterCommand = cobra.Command{
+	Use:   "register <validator address> <token id>",
+	Short: "Register a validator with a PublicStaking position and wait until it joins the pool",
+	Args:  cobra.ExactArgs(2),
+	Run:   register,
+}
+
+// StatusCommand prints the status of the account in the validator pool.
+var StatusCommand = cobra.Command{
+	Use:   "status",
+	Short: "Show the validator status, positions and pending rewards of the account",
+	Args:  cobra.NoArgs,
+	Run:   status,
+}
+
+// UnregisterCommand removes a validator from the pool.
+var UnregisterCommand = cobra.Command{
+	Use:   "unregister <validator address>",
+	Short: "Unregister a validator and wait until it leaves the pool",
+	Args:  cobra.ExactArgs(1),
+	Run:   unregister,
+}
+
+// ClaimCommand collects the profits of a validator, or claims the position of
+// an exiting validator.
+var ClaimCommand = cobra.Command{
+	Use:   "claim",
+	Short: "Collect the profits of a validator, or claim back the position after leaving the pool",
+	Args:  cobra.NoArgs,
+	Run:   claim,
+}
+
+// withStaker connects to ethereum and calls fn with a Staker for the default
+// account. The context is cancelled on SIGINT and SIGTERM.
+func withStaker(logger *logrus.Entry, fn func(ctx context.Context, staker *staking.Staker) error) {
+	ctx, cf := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cf()
+
+	eth, err := evm.NewClient(
+		logger.Logger,
+		config.Configuration.Ethereum.Endpoint,
+		config.Configuration.Ethereum.Keystore,
+		config.Configuration.Ethereum.PassCodes,
+		config.Configuration.Ethereum.DefaultAccount,
+		true,
+		constants.EthereumFinalityDelay,
+		config.Configuration.Ethereum.TxMaxGasFeeAllowedInGwei,
+		config.Configuration.Ethereum.EndpointMinimumPeers,
+	)
+	if err != nil {
+		logger.Fatalf("Could not connect to Ethereum: %v", err)
+	}
+	defer eth.Close()
+	contracts := handlers.NewAllSmartContractsHandle(eth, common.HexToAddress(config.Configuration.Ethereum.FactoryAddress))
+
+	// the watcher state is only needed while the command runs
+	rawDB, err := utils.OpenBadger(ctx.Done(), "", true)
+	if err != nil {
+		logger.Fatalf("Could not open the database: %v", err)
+	}
+	watcherDB := &db.Database{}
+	watcherDB.Init(rawDB)
+	watcher := transaction.WatcherFromNetwork(eth, watcherDB, false, constants.TxPollingTime)
+	defer watcher.Close()
+
+	staker := staking.NewStaker(eth, contracts, watcher, eth.GetDefaultAccount(), logger)
+	if err := fn(ctx, staker); err != nil {
+		logger.Fatalf("Request failed: %v", err)
+	}
+}
+
+func stake(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("validator").WithField("method", "stake")
+	amount := parseInt(logger, "amount", args[0])
+	withStaker(logger, func(ctx context.Context, staker *staking.Staker) error {
+		tokenID, err := staker.Stake(ctx, amount)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Minted PublicStaking position %v with %v ALCA\n", tokenID, amount)
+		return nil
+	})
+}
+
+func register(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("validator").WithField("method", "register")
+	validator := parseAddress(logger, args[0])
+	tokenID := parseInt(logger, "token id", args[1])
+	withStaker(logger, func(ctx context.Context, staker *staking.Staker) error {
+		if err := staker.Register(ctx, validator, tokenID); err != nil {
+			return err
+		}
+		fmt.Printf("Validator %v joined the pool with position %v\n", validator.Hex(), tokenID)
+		return nil
+	})
+}
+
+func unregister(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("validator").WithField("method", "unregister")
+	validator := parseAddress(logger, args[0])
+	withStaker(logger, func(ctx context.Context, staker *staking.Staker) error {
+		if err := staker.Unregister(ctx, validator); err != nil {
+			return err
+		}
+		fmt.Printf("Validator %v left the pool, its position can be claimed after the exit period\n", validator.Hex())
+		return nil
+	})
+}
+
+func claim(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("validator").WithField("method", "claim")
+	withStaker(logger, func(ctx context.Context, staker *staking.Staker) error {
+		result, err := staker.Claim(ctx)
+		if err != nil {
+			return err
+		}
+		if result.ExitingPosition {
+			fmt.Printf("Claimed PublicStaking position %v\n", result.TokenID)
+		} else {
+			fmt.Printf("Collected %v wei and %v ALCA from position %v\n", result.PayoutEth, result.PayoutToken, result.TokenID)
+		}
+		return nil
+	})
+}
+
+func status(cmd *cobra.Command, args []string) {
+	logger := logging.GetLogger("validator").WithField("method", "status")
+	withStaker(logger, func(ctx context.Context, staker *staking.Staker) error {
+		s, err := staker.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Account:\t%v\n", s.Account.Hex())
+		fmt.Fprintf(w, "Wei balance:\t%v\n", s.EthBalance)
+		fmt.Fprintf(w, "ALCA balance:\t%v\n", s.ALCABalance)
+		fmt.Fprintf(w, "Stake amount:\t%v\n", s.StakeAmount)
+		fmt.Fprintf(w, "Consensus running:\t%v\n", s.ConsensusRunning)
+		fmt.Fprintf(w, "Maintenance scheduled:\t%v\n", s.MaintenanceScheduled)
+		fmt.Fprintf(w, "Is validator:\t%v\n", s.IsValidator)
+		fmt.Fprintf(w, "Is accusable:\t%v\n", s.IsAccusable)
+		fmt.Fprintf(w, "In exiting queue:\t%v\n", s.IsInExitingQueue)
+		if s.IsValidator {
+			fmt.Fprintf(w, "ValidatorStaking position:\t%v\n", s.TokenID)
+			fmt.Fprintf(w, "Pending wei:\t%v\n", s.PendingEth)
+			fmt.Fprintf(w, "Pending ALCA:\t%v\n", s.PendingToken)
+		}
+		if s.IsInExitingQueue {
+			fmt.Fprintf(w, "Exiting PublicStaking position:\t%v\n", s.TokenID)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if len(s.Positions) == 0 {
+			return nil
+		}
+
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TOKEN ID\tSHARES\tFREE AFTER\tWITHDRAW FREE AFTER\tPENDING WEI\tPENDING ALCA")
+		for _, p := range s.Positions {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", p.TokenID, p.Shares, p.FreeAfter, p.WithdrawFreeAfter, p.PendingEth, p.PendingToken)
+		}
+		return w.Flush()
+	})
+}
+
+func parseInt(logger *logrus.Entry, name string, value string) *big.Int {
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok || v.Sign() < 0 {
+		logger.Fatalf("Could not parse the %v %q as a base 10 integer", name, value)
+	}
+	return v
+}
+
+func parseAddress(logger *logrus.Entry, value string) common.Address {
+	if !common.IsHexAddress(value) {
+		logger.Fatalf("Invalid address %q", value)
+	}
+	return common.HexToAddress(value)
+}
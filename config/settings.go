@@ -31,6 +31,7 @@ type ChainConfig struct {
 	TransactionDbInMemory bool
 	MonitorDbPath         string
 	MonitorDbInMemory     bool
+	ForkEpoch             uint64
 }
 
 type EthereumConfig struct {
@@ -59,7 +60,15 @@ type TransportConfig struct {
 	BootNodeAddresses          string
 	P2PListeningAddress        string
 	LocalStateListeningAddress string
+	AdminListeningAddress      string
 	UPnP                       bool
+	// The rate limits are per peer and per second; 0 means unlimited.
+	ConsensusMsgRateLimit  int
+	ConsensusByteRateLimit int
+	TxGossipMsgRateLimit   int
+	TxGossipByteRateLimit  int
+	SyncMsgRateLimit       int
+	SyncByteRateLimit      int
 }
 
 type DeployConfig struct {
@@ -71,6 +80,26 @@ type UtilsConfig struct {
 	Status bool
 }
 
+type GasConfig struct {
+	Strategy              string
+	TaskStrategies        string
+	FeeHistoryBlocks      uint64
+	FeeHistoryPercentile  uint64
+	FixedTipCapInGwei     uint64
+	TipCapBumpPercentage  uint64
+	MaxTipCapInGwei       uint64
+	EpochBudgetInGwei     uint64
+	BudgetAlertPercentage uint64
+}
+
+type UpgradeConfig struct {
+	Enabled    bool
+	DryRun     bool
+	Source     string
+	PublicKey  string
+	StagingDir string
+}
+
 type ValidatorConfig struct {
 	Repl         bool
 	SymmetricKey string
@@ -127,6 +156,8 @@ type RootConfiguration struct {
 	Ethereum              EthereumConfig
 	Transport             TransportConfig
 	Utils                 UtilsConfig
+	Gas                   GasConfig
+	Upgrade               UpgradeConfig
 	Validator             ValidatorConfig
 	Firewalld             FirewalldConfig
 	Chain                 ChainConfig
@@ -70,6 +70,11 @@ transactionDB = "{{ .Chain.TransactionDbPath }}"
 # SET TRUE FOR TESTING PURPOSES.
 transactionDBInMemory = {{ .Chain.TransactionDbInMemory }}
 
+# First epoch at which the application fork rules apply (new output types,
+# transaction memos and data store updates). Every node of a network must use
+# the same value. Set 0 to keep the rules from before the fork.
+forkEpoch = {{ .Chain.ForkEpoch }}
+
 [ethereum]
 
 # Ethereum address that will be used to sign transactions and connect to the
@@ -122,6 +127,38 @@ txMaxGasFeeAllowedInGwei = {{ .Ethereum.TxMaxGasFeeAllowedInGwei }}
 # logs.
 txMetricsDisplay = {{ .Ethereum.TxMetricsDisplay }}
 
+[gas]
+
+# OPTIONAL: How the tip of the layer1 transactions is computed. One of
+# "suggested" (the tip suggested by the endpoint), "feeHistory" (a percentile
+# of the tips paid in the latest blocks), "fixed" (fixedTipCapInGwei, never
+# raised) or "exponential" (raised by tipCapBumpPercentage on every retry up to
+# maxTipCapInGwei).
+strategy = "{{ .Gas.Strategy }}"
+
+# Strategy per task type, as a comma separated list of task=strategy, e.g.
+# "snapshots.SnapshotTask=fixed,dkg.RegisterTask=feeHistory".
+taskStrategies = "{{ .Gas.TaskStrategies }}"
+
+# Number of blocks and percentile of the tips used by the feeHistory strategy.
+feeHistoryBlocks = {{ .Gas.FeeHistoryBlocks }}
+feeHistoryPercentile = {{ .Gas.FeeHistoryPercentile }}
+
+# Tip of the fixed strategy.
+fixedTipCapInGwei = {{ .Gas.FixedTipCapInGwei }}
+
+# Increase of the tip on every retry of the exponential strategy and the tip
+# it never goes past.
+tipCapBumpPercentage = {{ .Gas.TipCapBumpPercentage }}
+maxTipCapInGwei = {{ .Gas.MaxTipCapInGwei }}
+
+# OPTIONAL: Maximum amount (in GWEI) the default account spends in layer1 fees
+# per AliceNet epoch. Once it is spent, no transaction is sent until the next
+# epoch. A warning is logged when alertPercentage of the budget is spent. 0
+# disables the budget.
+epochBudgetInGwei = {{ .Gas.EpochBudgetInGwei }}
+budgetAlertPercentage = {{ .Gas.BudgetAlertPercentage }}
+
 
 #######################################################################
 ###                   Logging Config Options                        ###
@@ -139,6 +176,29 @@ consensus = "{{ .Logging.Consensus }}"
 # noisy.
 status = {{ .Utils.Status }}
 
+[upgrade]
+
+# OPTIONAL: Upgrade the node automatically when a new AliceNet node version is
+# announced on layer1. The release is fetched from source, checked against its
+# .sha256 file (and its .sig ed25519 signature if publicKey is set), staged
+# and the node restarts into it at the execution epoch of the new version.
+enabled = {{ .Upgrade.Enabled }}
+
+# Fetch, check and stage the release but do not restart the node.
+dryRun = {{ .Upgrade.DryRun }}
+
+# Local directory with the releases, named alicenet-<version>-<os>-<arch>, or a
+# path or http(s) URL written as a Go template with the .Version, .OS and .Arch
+# fields.
+source = "{{ .Upgrade.Source }}"
+
+# Hex encoded ed25519 public key the releases are signed with.
+publicKey = "{{ .Upgrade.PublicKey }}"
+
+# Directory where the release is staged, the directory of the node binary if
+# empty.
+stagingDir = "{{ .Upgrade.StagingDir }}"
+
 
 #######################################################
 ###       Network Configuration Options             ###
@@ -155,6 +215,11 @@ bootNodeAddresses = "{{ .Transport.BootNodeAddresses }}"
 # Address and port where your node will be listening for rpc requests.
 localStateListeningAddress = "{{ .Transport.LocalStateListeningAddress }}"
 
+# Address and port where your node will be listening for admin rpc requests,
+# used by the alicenet tasks commands. It should only be reachable from the
+# node host. Leave it empty to disable the admin service.
+adminListeningAddress = "{{ .Transport.AdminListeningAddress }}"
+
 # Maximum number of peers that we can connect from a same ip.
 originLimit = {{ .Transport.OriginLimit }}
 
@@ -177,6 +242,16 @@ privateKey = "{{ .Transport.PrivateKey }}"
 # If UPNP should be used to discover opened ports to connect with the peers.
 upnp = {{ .Transport.UPnP }}
 
+# Rate limits applied to the requests of each peer, per traffic class. The
+# message limits count messages per second and the byte limits count bytes per
+# second. A limit of 0 means unlimited.
+consensusMsgRateLimit = {{ .Transport.ConsensusMsgRateLimit }}
+consensusByteRateLimit = {{ .Transport.ConsensusByteRateLimit }}
+txGossipMsgRateLimit = {{ .Transport.TxGossipMsgRateLimit }}
+txGossipByteRateLimit = {{ .Transport.TxGossipByteRateLimit }}
+syncMsgRateLimit = {{ .Transport.SyncMsgRateLimit }}
+syncByteRateLimit = {{ .Transport.SyncByteRateLimit }}
+
 
 #######################################################
 ###       Validator Configuration Options           ###
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"bytes"
+
+	trie "github.com/alicenet/alicenet/badgerTrie"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// MakeTxRootProof returns the binary MerkleProof of the inclusion of txHash
+// in the TxRoot that objs.MakeTxRoot computes from txHashes. The trie is only
+// kept in memory.
+func MakeTxRootProof(txHashes [][]byte, txHash []byte) ([]byte, []byte, error) {
+	if len(txHash) != constants.HashLen {
+		return nil, nil, errorz.ErrInvalid{}.New("MakeTxRootProof; incorrect txHash length")
+	}
+	found := false
+	values := [][]byte{}
+	for i := 0; i < len(txHashes); i++ {
+		if len(txHashes[i]) != constants.HashLen {
+			return nil, nil, errorz.ErrInvalid{}.New("MakeTxRootProof; incorrect txHash length")
+		}
+		if bytes.Equal(txHashes[i], txHash) {
+			found = true
+		}
+		values = append(values, crypto.Hasher(txHashes[i]))
+	}
+	if !found {
+		return nil, nil, errorz.ErrInvalid{}.New("MakeTxRootProof; txHash is not in txHashes")
+	}
+	keys, values, err := utils.SortKVs(txHashes, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	smt := trie.NewSMT(nil, crypto.Hasher, txRootPrefix)
+	root, err := smt.Update(nil, keys, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	bitmap, path, keyHeight, included, proofKey, proofValue, err := smt.MerkleProofCompressed(nil, txHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !included {
+		return nil, nil, errorz.ErrInvalid{}.New("MakeTxRootProof; txHash not included in the trie")
+	}
+	mproof := &MerkleProof{
+		Included:   included,
+		KeyHeight:  keyHeight,
+		Key:        utils.CopySlice(txHash),
+		ProofKey:   proofKey,
+		ProofValue: proofValue,
+		Bitmap:     bitmap,
+		Path:       path,
+	}
+	proof, err := mproof.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return root, proof, nil
+}
+
+// VerifyTxRootProof verifies a proof made by MakeTxRootProof against the
+// TxRoot of a block.
+func VerifyTxRootProof(txRoot, txHash, proof []byte) (bool, error) {
+	mproof := &MerkleProof{}
+	if err := mproof.UnmarshalBinary(proof); err != nil {
+		return false, err
+	}
+	if !mproof.Included || !bytes.Equal(mproof.Key, txHash) {
+		return false, nil
+	}
+	smt := trie.NewSMT(txRoot, crypto.Hasher, txRootPrefix)
+	return smt.VerifyInclusionCR(txRoot, mproof.Bitmap, txHash, crypto.Hasher(txHash), mproof.Path, mproof.KeyHeight), nil
+}
+
+func txRootPrefix() []byte {
+	return []byte("!!")
+}
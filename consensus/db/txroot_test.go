@@ -0,0 +1,40 @@
+package db
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/consensus/objs"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+func TestTxRootProof(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 64} {
+		txHashes := [][]byte{}
+		for i := 0; i < n; i++ {
+			txHashes = append(txHashes, crypto.Hasher([]byte(strconv.Itoa(n*1000+i))))
+		}
+		txRoot, err := objs.MakeTxRoot(txHashes)
+		require.Nil(t, err)
+
+		for _, txHash := range txHashes {
+			root, proof, err := MakeTxRootProof(txHashes, txHash)
+			require.Nil(t, err)
+			assert.Equal(t, txRoot, root)
+
+			ok, err := VerifyTxRootProof(txRoot, txHash, proof)
+			require.Nil(t, err)
+			assert.True(t, ok, "n=%v", n)
+
+			ok, err = VerifyTxRootProof(crypto.Hasher(txRoot), txHash, proof)
+			require.Nil(t, err)
+			assert.False(t, ok)
+		}
+	}
+
+	_, _, err := MakeTxRootProof([][]byte{crypto.Hasher([]byte("a"))}, crypto.Hasher([]byte("b")))
+	assert.NotNil(t, err)
+}
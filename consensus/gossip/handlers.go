@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"github.com/dgraph-io/badger/v2"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"time"
 
 	"github.com/alicenet/alicenet/consensus/db"
@@ -35,6 +33,7 @@ var (
 
 type appHandler interface {
 	PendingTxAdd(txn *badger.Txn, chainID, height uint32, tx []interfaces.Transaction) error
+	PendingTxContains(txn *badger.Txn, height uint32, txHashes [][]byte) ([][]byte, error)
 	UnmarshalTx([]byte) (interfaces.Transaction, error)
 }
 
@@ -168,7 +167,7 @@ func (mb *Handlers) heightAndSync() (uint32, uint32, bool, bool, error) {
 func (mb *Handlers) HandleP2PGossipTransaction(ctx context.Context, msg *pb.GossipTransactionMessage) (*pb.GossipTransactionAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipTransactionAck{}
@@ -181,7 +180,7 @@ func (mb *Handlers) HandleP2PGossipTransaction(ctx context.Context, msg *pb.Goss
 	height := mb.height.Get()
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return ack, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return ack, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -189,12 +188,12 @@ func (mb *Handlers) HandleP2PGossipTransaction(ctx context.Context, msg *pb.Goss
 		tx, err := mb.app.UnmarshalTx(tx)
 		if err != nil {
 			utils.DebugTrace(mb.logger, err)
-			return status.Error(codes.InvalidArgument, err.Error())
+			return err
 		}
 		err = mb.app.PendingTxAdd(txn, chainID, height, []interfaces.Transaction{tx})
 		if err != nil {
 			utils.DebugTrace(mb.logger, err)
-			return status.Error(codes.InvalidArgument, err.Error())
+			return err
 		}
 		return nil
 	})
@@ -205,6 +204,33 @@ func (mb *Handlers) HandleP2PGossipTransaction(ctx context.Context, msg *pb.Goss
 	return ack, nil
 }
 
+// TxHash returns the hash of a marshalled transaction. It allows the peer
+// manager to announce transactions by hash.
+func (mb *Handlers) TxHash(tx []byte) ([]byte, error) {
+	t, err := mb.app.UnmarshalTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return t.TxHash()
+}
+
+// MissingTxs returns the hashes of the transactions that are not in the
+// pending transaction pool. It allows the peer manager to only pull the
+// announced transactions that are unknown to the local node.
+func (mb *Handlers) MissingTxs(txHashes [][]byte) ([][]byte, error) {
+	var missing [][]byte
+	err := mb.database.View(func(txn *badger.Txn) error {
+		var err error
+		missing, err = mb.app.PendingTxContains(txn, mb.height.Get(), txHashes)
+		return err
+	})
+	if err != nil {
+		utils.DebugTrace(mb.logger, err)
+		return nil, err
+	}
+	return missing, nil
+}
+
 // HandleP2PGossipProposal adds a proposal to the database
 // This method should be invoked when a remote peer
 // sends this type of object to the local node over
@@ -212,7 +238,7 @@ func (mb *Handlers) HandleP2PGossipTransaction(ctx context.Context, msg *pb.Goss
 func (mb *Handlers) HandleP2PGossipProposal(ctx context.Context, msg *pb.GossipProposalMessage) (*pb.GossipProposalAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipProposalAck{}
@@ -224,15 +250,15 @@ func (mb *Handlers) HandleP2PGossipProposal(ctx context.Context, msg *pb.GossipP
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -250,7 +276,7 @@ func (mb *Handlers) HandleP2PGossipProposal(ctx context.Context, msg *pb.GossipP
 func (mb *Handlers) HandleP2PGossipPreVote(ctx context.Context, msg *pb.GossipPreVoteMessage) (*pb.GossipPreVoteAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipPreVoteAck{}
@@ -266,17 +292,17 @@ func (mb *Handlers) HandleP2PGossipPreVote(ctx context.Context, msg *pb.GossipPr
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddPreVote(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -288,7 +314,7 @@ func (mb *Handlers) HandleP2PGossipPreVote(ctx context.Context, msg *pb.GossipPr
 func (mb *Handlers) HandleP2PGossipPreVoteNil(ctx context.Context, msg *pb.GossipPreVoteNilMessage) (*pb.GossipPreVoteNilAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipPreVoteNilAck{}
@@ -300,21 +326,21 @@ func (mb *Handlers) HandleP2PGossipPreVoteNil(ctx context.Context, msg *pb.Gossi
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddPreVoteNil(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -326,7 +352,7 @@ func (mb *Handlers) HandleP2PGossipPreVoteNil(ctx context.Context, msg *pb.Gossi
 func (mb *Handlers) HandleP2PGossipPreCommit(ctx context.Context, msg *pb.GossipPreCommitMessage) (*pb.GossipPreCommitAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipPreCommitAck{}
@@ -338,21 +364,21 @@ func (mb *Handlers) HandleP2PGossipPreCommit(ctx context.Context, msg *pb.Gossip
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddPreCommit(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -364,7 +390,7 @@ func (mb *Handlers) HandleP2PGossipPreCommit(ctx context.Context, msg *pb.Gossip
 func (mb *Handlers) HandleP2PGossipPreCommitNil(ctx context.Context, msg *pb.GossipPreCommitNilMessage) (*pb.GossipPreCommitNilAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipPreCommitNilAck{}
@@ -376,21 +402,21 @@ func (mb *Handlers) HandleP2PGossipPreCommitNil(ctx context.Context, msg *pb.Gos
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddPreCommitNil(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -402,7 +428,7 @@ func (mb *Handlers) HandleP2PGossipPreCommitNil(ctx context.Context, msg *pb.Gos
 func (mb *Handlers) HandleP2PGossipNextRound(ctx context.Context, msg *pb.GossipNextRoundMessage) (*pb.GossipNextRoundAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipNextRoundAck{}
@@ -414,21 +440,21 @@ func (mb *Handlers) HandleP2PGossipNextRound(ctx context.Context, msg *pb.Gossip
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddNextRound(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -440,7 +466,7 @@ func (mb *Handlers) HandleP2PGossipNextRound(ctx context.Context, msg *pb.Gossip
 func (mb *Handlers) HandleP2PGossipNextHeight(ctx context.Context, msg *pb.GossipNextHeightMessage) (*pb.GossipNextHeightAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipNextHeightAck{}
@@ -452,21 +478,21 @@ func (mb *Handlers) HandleP2PGossipNextHeight(ctx context.Context, msg *pb.Gossi
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddNextHeight(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
@@ -478,7 +504,7 @@ func (mb *Handlers) HandleP2PGossipNextHeight(ctx context.Context, msg *pb.Gossi
 func (mb *Handlers) HandleP2PGossipBlockHeader(ctx context.Context, msg *pb.GossipBlockHeaderMessage) (*pb.GossipBlockHeaderAck, error) {
 	select {
 	case <-mb.ctx.Done():
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	default:
 	}
 	ack := &pb.GossipBlockHeaderAck{}
@@ -487,21 +513,21 @@ func (mb *Handlers) HandleP2PGossipBlockHeader(ctx context.Context, msg *pb.Goss
 	err := obj.UnmarshalBinary(rawmsg)
 	if err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	if err := mb.shandlers.PreValidate(obj); err != nil {
 		utils.DebugTrace(mb.logger, fmt.Errorf("BlockHeight:%d | SigGroup:%x | %q", obj.BClaims.Height, obj.SigGroup, err))
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	mutex, ok := mb.getLock(ctx)
 	if !ok {
-		return nil, status.Error(codes.Canceled, errorz.ErrClosing.Error())
+		return nil, errorz.ErrClosing
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	if err := mb.shandlers.AddBlockHeader(obj); err != nil {
 		utils.DebugTrace(mb.logger, err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 	return ack, nil
 }
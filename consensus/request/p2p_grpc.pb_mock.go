@@ -9,6 +9,7 @@ import (
 	"github.com/alicenet/alicenet/interfaces"
 	"github.com/alicenet/alicenet/middleware"
 	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/types"
 )
 
 type P2PClientMock struct {
@@ -23,6 +24,10 @@ func (p2p *P2PClientMock) NodeAddr() interfaces.NodeAddr {
 	return nil
 }
 
+func (p2p *P2PClientMock) Features() types.Features {
+	return 0
+}
+
 func (p2p *P2PClientMock) CloseChan() <-chan struct{} {
 	return nil
 }
@@ -126,6 +131,24 @@ func (p2p *P2PClientMock) GossipBlockHeader(ctx context.Context, in *proto.Gossi
 	return args.Get(0).(*proto.GossipBlockHeaderAck), args.Error(1)
 }
 
+func (p2p *P2PClientMock) GossipTransactionBatch(ctx context.Context, in *proto.GossipBatchMessage, opts ...grpc.CallOption) (*proto.GossipBatchAck, error) {
+	middleware.SetPeer(p2p, opts...)
+	args := p2p.Called(ctx, in, opts)
+	return args.Get(0).(*proto.GossipBatchAck), args.Error(1)
+}
+
+func (p2p *P2PClientMock) GossipConsensusBatch(ctx context.Context, in *proto.GossipBatchMessage, opts ...grpc.CallOption) (*proto.GossipBatchAck, error) {
+	middleware.SetPeer(p2p, opts...)
+	args := p2p.Called(ctx, in, opts)
+	return args.Get(0).(*proto.GossipBatchAck), args.Error(1)
+}
+
+func (p2p *P2PClientMock) GossipTxInventory(ctx context.Context, in *proto.GossipTxInventoryMessage, opts ...grpc.CallOption) (*proto.GossipTxInventoryAck, error) {
+	middleware.SetPeer(p2p, opts...)
+	args := p2p.Called(ctx, in, opts)
+	return args.Get(0).(*proto.GossipTxInventoryAck), args.Error(1)
+}
+
 func (p2p *P2PClientMock) GetPeers(ctx context.Context, in *proto.GetPeersRequest, opts ...grpc.CallOption) (*proto.GetPeersResponse, error) {
 	middleware.SetPeer(p2p, opts...)
 	args := p2p.Called(ctx, in, opts)
@@ -4,6 +4,10 @@ const (
 	// MaxTxVectorLength is the maximum size of input output vectors.
 	// This prevents uint32 overflow.
 	MaxTxVectorLength int = 128
+
+	// MaxTxMemoSize is the largest size in bytes of the memo of a
+	// transaction.
+	MaxTxMemoSize int = 256
 )
 
 const (
@@ -113,3 +113,43 @@ func PrefixDepositValueKey() []byte {
 func PrefixPendingTxCooldownKey() []byte {
 	return []byte("n7")
 }
+
+func PrefixMinedUTXOAltValueRefKey() []byte {
+	return []byte("n8")
+}
+
+func PrefixMinedUTXOAltValueKey() []byte {
+	return []byte("n9")
+}
+
+func PrefixMinedUTXOLockedValueRefKey() []byte {
+	return []byte("ne")
+}
+
+func PrefixMinedUTXOLockedValueKey() []byte {
+	return []byte("nf")
+}
+
+func PrefixMinedUTXOAssetRefKey() []byte {
+	return []byte("ng")
+}
+
+func PrefixMinedUTXOAssetKey() []byte {
+	return []byte("nh")
+}
+
+func PrefixMinedTxMemoRefKey() []byte {
+	return []byte("ni")
+}
+
+func PrefixMinedTxMemoKey() []byte {
+	return []byte("nj")
+}
+
+func PrefixMinedUTXOSwapValueRefKey() []byte {
+	return []byte("nk")
+}
+
+func PrefixMinedUTXOSwapValueKey() []byte {
+	return []byte("o0")
+}
@@ -22,6 +22,22 @@ func PrefixTaskExecutorState() []byte {
 	return []byte("ld")
 }
 
+func PrefixLayer1Event() []byte {
+	return []byte("le")
+}
+
+func PrefixLayer1EventNameIndex() []byte {
+	return []byte("lf")
+}
+
+func PrefixLayer1EventContractIndex() []byte {
+	return []byte("lg")
+}
+
+func PrefixGasBudgetState() []byte {
+	return []byte("lh")
+}
+
 // TASKS
 // All functions in this file are prefix designators for database state types.
 // These functions name the resource being referenced in the function name.
@@ -35,3 +51,7 @@ func PrefixEthereumDKGState() []byte {
 func PrefixEthereumSnapshotState() []byte {
 	return []byte("tb")
 }
+
+func PrefixEthereumSnapshotReport() []byte {
+	return []byte("tc")
+}
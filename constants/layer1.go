@@ -13,6 +13,22 @@ const (
 	MonitorTimeout time.Duration = 1 * time.Minute
 )
 
+// Task retry constants.
+const (
+	// Number of runs of an ETHDKG phase task, the first one included. The runs
+	// stop anyway at the end of the phase.
+	ETHDKGTaskMaxAttempts uint64 = 3
+	// How many blocks we wait for running again an ETHDKG phase task that failed.
+	ETHDKGTaskRetryBackoff uint64 = 1
+	// Number of runs of a snapshot task, the first one included.
+	SnapshotTaskMaxAttempts uint64 = 5
+	// How many blocks we wait for running again a snapshot task that failed. The
+	// wait doubles on every retry up to SnapshotTaskMaxRetryBackoff.
+	SnapshotTaskRetryBackoff uint64 = 2
+	// Maximum number of blocks we wait between two runs of a snapshot task.
+	SnapshotTaskMaxRetryBackoff uint64 = 16
+)
+
 // Transaction Watcher constants.
 const (
 	// How many blocks we should wait for removing a receipt from the cache.
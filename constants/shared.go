@@ -44,6 +44,10 @@ const (
 	// CurveBN256EthPubkeyLen specifies the length of the public key for the curve
 	// BN256; this is the uncompressed form.
 	CurveBN256EthPubkeyLen = 128
+
+	// CurveSecp256k1PubkeyLen specifies the length of the public key for the
+	// curve Secp256k1; this is the uncompressed form.
+	CurveSecp256k1PubkeyLen = 65
 )
 
 const (
@@ -58,6 +62,10 @@ const (
 const (
 	// OwnerLen is the constant which specifies the length of accounts in bytes.
 	OwnerLen int = 20
+
+	// MaxMultiSigPublicKeys is the maximum number of public keys of a
+	// multisig owner.
+	MaxMultiSigPublicKeys int = 16
 )
 
 // Status log keys.
@@ -69,6 +77,7 @@ const (
 	StatusBlkHsh    = "BlkHsh"
 	StatusTxCt      = "TxCt"
 	StatusSyncToBlk = "SyncToBlk"
+	StatusBandwidth = "BW"
 )
 
 // Logger names.
@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 // GRPC Server Configuration Params
 // Setup to provide backpressure.
 const (
@@ -14,3 +16,43 @@ const (
 	P2PStreamWorkers        = 4
 	DiscoStreamWorkers      = 1
 )
+
+// RateLimitBurstWindow is the amount of time worth of traffic a peer may
+// send in a burst before the per peer rate limits apply.
+const RateLimitBurstWindow = 5 * time.Second
+
+// BandwidthStateTTL is how long the traffic counters and rate limit state of
+// a peer are kept after its last message. The state outlives the connection
+// so that a peer can not reset its rate limits by reconnecting.
+const BandwidthStateTTL = 10 * time.Minute
+
+// Gossip batching parameters for peers that negotiated batched gossip.
+const (
+	// GossipBatchMaxEntries is the maximum number of objects in a batch.
+	GossipBatchMaxEntries = 256
+	// GossipBatchMaxBytes is the uncompressed size at which a batch is sent.
+	GossipBatchMaxBytes = 1024 * 1024
+	// GossipBatchMaxDecodedBytes bounds the decompressed size of a received
+	// batch.
+	GossipBatchMaxDecodedBytes = 4 * GossipBatchMaxBytes
+	// GossipBatchCompressThreshold is the size above which a batch is
+	// compressed.
+	GossipBatchCompressThreshold = 512
+	// GossipBatchTxDelay is the time transactions are held back to fill a
+	// batch. Consensus messages are never held back.
+	GossipBatchTxDelay = 50 * time.Millisecond
+	// GossipFilterSize is the number of transaction hashes remembered for
+	// each peer to avoid gossiping a transaction the peer already has.
+	GossipFilterSize = 8192
+)
+
+// Inventory based transaction gossip parameters.
+const (
+	// TxInventoryMinPeers is the number of active peers below which
+	// transactions are pushed in full instead of being announced. Announcing
+	// only pays off once a transaction reaches a node from several peers.
+	TxInventoryMinPeers = 8
+	// TxInventoryMaxHashes is the maximum number of hashes in an
+	// announcement.
+	TxInventoryMaxHashes = GossipBatchMaxEntries
+)
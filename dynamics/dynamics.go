@@ -45,6 +45,7 @@ type StorageGetter interface {
 	GetMinScaledTransactionFee() *big.Int
 	GetDataStoreFee() *big.Int
 	GetValueStoreFee() *big.Int
+	GetMemoByteFee() *big.Int
 	ChangeDynamicValues(txn *badger.Txn, epoch uint32, rawDynamics []byte) error
 	UpdateCurrentDynamicValue(*badger.Txn, uint32) error
 	GetDynamicValueInThePast(txn *badger.Txn, epoch uint32) (uint32, *DynamicValues, error)
@@ -271,6 +272,15 @@ func (s *Storage) GetDataStoreFee() *big.Int {
 	return s.DynamicValues.GetDataStoreFee()
 }
 
+// GetMemoByteFee returns the transaction fee per byte of memo
+func (s *Storage) GetMemoByteFee() *big.Int {
+	<-s.startChan
+
+	s.RLock()
+	defer s.RUnlock()
+	return s.DynamicValues.GetMemoByteFee()
+}
+
 // createLinkedList creates the linked list and store a DynamicValue for epoch 1
 // as the first node. This function also update s.DynamicValues.
 func (s *Storage) createLinkedList(txn *badger.Txn, epoch uint32, newDynamicValue *DynamicValues) error {
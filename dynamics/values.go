@@ -16,16 +16,22 @@ type Version int
 // Possible versions that we can have during the dynamic value event change
 const (
 	V1 Version = iota
+	V2
 )
 
 func (version Version) String() string {
 	return [...]string{
 		"V1",
+		"V2",
 	}[version]
 }
 
 const DynamicsValueV1Size int = 48
 
+// DynamicsValueV2Size is the size of the V2 encoding, which appends the
+// MemoByteFee to the V1 encoding.
+const DynamicsValueV2Size int = 56
+
 type DynamicValues struct {
 	EncoderVersion          Version
 	ProposalTimeout         time.Duration
@@ -35,6 +41,7 @@ type DynamicValues struct {
 	DataStoreFee            *big.Int
 	ValueStoreFee           *big.Int
 	MinScaledTransactionFee *big.Int
+	MemoByteFee             *big.Int
 }
 
 func DecodeDynamicValues(data []byte) (*DynamicValues, error) {
@@ -72,6 +79,15 @@ func DecodeDynamicValues(data []byte) (*DynamicValues, error) {
 	valueStoreFee := new(big.Int).SetBytes(data[24:32])
 	minScaledTransactionFee := new(big.Int).SetBytes(data[32:48])
 
+	// the fee per memo byte was introduced with V2; it is free before
+	memoByteFee := new(big.Int)
+	if Version(encoderVersion) == V2 {
+		if len(data) < DynamicsValueV2Size {
+			return nil, &ErrInvalidDynamicValueStructLen{fmt.Sprintf("%x", data), len(data), DynamicsValueV2Size}
+		}
+		memoByteFee.SetBytes(data[48:56])
+	}
+
 	dynamicValuesV1 := &DynamicValues{
 		Version(encoderVersion),
 		proposalTimeout,
@@ -81,6 +97,7 @@ func DecodeDynamicValues(data []byte) (*DynamicValues, error) {
 		dataStoreFee,
 		valueStoreFee,
 		minScaledTransactionFee,
+		memoByteFee,
 	}
 
 	return dynamicValuesV1, nil
@@ -197,6 +214,16 @@ func (dv *DynamicValues) GetValueStoreFee() *big.Int {
 	return dv.ValueStoreFee
 }
 
+// GetMemoByteFee returns the minimum tx burned fee per byte of memo. The values
+// stored before V2 have no MemoByteFee; it is zero for them and, since Storage
+// only holds a read lock to call this, it is not set here.
+func (dv *DynamicValues) GetMemoByteFee() *big.Int {
+	if dv.MemoByteFee == nil {
+		return new(big.Int)
+	}
+	return dv.MemoByteFee
+}
+
 func decodeUInt32WithArbitraryLength(data []byte) (uint32, error) {
 	size := 4
 	if len(data) == 0 || len(data) > size {
@@ -179,6 +179,7 @@ func TestDecodeDynamicValuesV1(t *testing.T) {
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(0),
 			},
 		},
 		{
@@ -193,6 +194,7 @@ func TestDecodeDynamicValuesV1(t *testing.T) {
 				new(big.Int).SetUint64(13_739_847_691_614_928_557),
 				new(big.Int).SetUint64(13_739_847_691_614_928_557),
 				randUint128,
+				new(big.Int).SetUint64(0),
 			},
 		},
 		{
@@ -207,6 +209,7 @@ func TestDecodeDynamicValuesV1(t *testing.T) {
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(0),
 			},
 		},
 		{
@@ -221,6 +224,7 @@ func TestDecodeDynamicValuesV1(t *testing.T) {
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
 				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(0),
 			},
 		},
 		{
@@ -235,6 +239,22 @@ func TestDecodeDynamicValuesV1(t *testing.T) {
 				new(big.Int).SetUint64(18_446_744_073_709_551_615),
 				new(big.Int).SetUint64(18_446_744_073_709_551_615),
 				maxUint128,
+				new(big.Int).SetUint64(0),
+			},
+		},
+		{
+			name:  "Correctly decode dynamic values V2",
+			input: "01000fa000000bb800000bb8002dc6c0000000000000000000000000000000000000000000000000000000000000000000000000000003e8",
+			expected: &DynamicValues{
+				V2,
+				time.Duration(4) * time.Second,
+				time.Duration(3) * time.Second,
+				time.Duration(3) * time.Second,
+				3_000_000,
+				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(0),
+				new(big.Int).SetUint64(1_000),
 			},
 		},
 	}
@@ -279,6 +299,29 @@ func TestShouldNotDecodeIncorrectSize2DynamicValuesV1(t *testing.T) {
 	}
 }
 
+func TestShouldNotDecodeIncorrectSizeDynamicValuesV2(t *testing.T) {
+	t.Parallel()
+	data, err := hex.DecodeString("01000fa000000bb800000bb8002dc6c00000000000000000000000000000000000000000000000000000000000000000")
+	assert.Nil(t, err)
+	_, err = DecodeDynamicValues(data)
+	targetError := &ErrInvalidDynamicValueStructLen{}
+	if !errors.As(err, &targetError) {
+		t.Fatalf("expected function to fail with error type: &ErrInvalidDynamicValueStructLen{} got %v", err)
+	}
+}
+
+func TestDynamicValuesMemoByteFeeBeforeV2(t *testing.T) {
+	t.Parallel()
+	// values stored as json before V2 have no MemoByteFee
+	v := []byte(`{"EncoderVersion":0,"ProposalTimeout":4000000000,"PreVoteTimeout":3000000000,"PreCommitTimeout":3000000000,"MaxBlockSize":3000000,"DataStoreFee":0,"ValueStoreFee":0,"MinScaledTransactionFee":0}`)
+	dv := &DynamicValues{}
+	err := dv.Unmarshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, dv.GetMemoByteFee().Sign())
+	// the getter is called under a read lock, so it must not set the field
+	assert.Nil(t, dv.MemoByteFee)
+}
+
 func TestShouldDecodeUint32(t *testing.T) {
 	t.Parallel()
 	data, err := hex.DecodeString("babebabe")
@@ -0,0 +1,220 @@
+package errorz
+
+import (
+	"errors"
+)
+
+// Code is a stable identifier of a class of errors. Codes are sent to the
+// clients of the gRPC APIs of the node, so a value must never be reused for
+// another class of errors.
+//
+// Errors fall into two groups:
+//
+// Permanent errors will fail again if the same request is repeated; the
+// request itself must change. These are CodeInvalid, CodeStale,
+// CodeConsensus, CodeCorrupt and CodeUnknown.
+//
+// Retryable errors depend on the state of the node; the same request may
+// succeed once the node has caught up or another node is used. These are
+// CodeConsensusLocal, CodeMissingTransactions, CodeBadResponse, CodeClosing
+// and CodeNotReady.
+//
+// The CodeTx codes refine CodeInvalid with the rule of the pending
+// transaction pool which a transaction breaks; they are all permanent.
+type Code uint32
+
+const (
+	// CodeUnknown is set for errors which are not part of the catalogue.
+	CodeUnknown Code = 0
+	// CodeInvalid is set for ErrInvalid; the object breaks the rules.
+	CodeInvalid Code = 1
+	// CodeStale is set for ErrStale; the object is for a past height or round.
+	CodeStale Code = 2
+	// CodeConsensus is set for an ErrConsensus caused by a remote object.
+	CodeConsensus Code = 3
+	// CodeConsensusLocal is set for an ErrConsensus caused by local state.
+	CodeConsensusLocal Code = 4
+	// CodeMissingTransactions is set for ErrMissingTransactions.
+	CodeMissingTransactions Code = 5
+	// CodeBadResponse is set for ErrBadResponse.
+	CodeBadResponse Code = 6
+	// CodeClosing is set for ErrClosing.
+	CodeClosing Code = 7
+	// CodeCorrupt is set for ErrCorrupt.
+	CodeCorrupt Code = 8
+	// CodeNotReady is set for ErrNotReady.
+	CodeNotReady Code = 9
+	// CodeTxMalformed is set when the tx is missing fields or consumes
+	// or generates the same object twice.
+	CodeTxMalformed Code = 10
+	// CodeTxChainID is set when an object of the tx is for another chain.
+	CodeTxChainID Code = 11
+	// CodeTxHash is set when the TxHash of an object does not match the tx.
+	CodeTxHash Code = 12
+	// CodeTxDuplicate is set when the tx is already in the pending tx pool.
+	CodeTxDuplicate Code = 13
+	// CodeTxAlreadyMined is set when the tx was mined recently.
+	CodeTxAlreadyMined Code = 14
+	// CodeTxInputMissing is set when an input does not reference a known
+	// UTXO or deposit.
+	CodeTxInputMissing Code = 15
+	// CodeTxInputSpent is set when an input references a spent deposit.
+	CodeTxInputSpent Code = 16
+	// CodeTxInputSignature is set when the signature of an input is invalid.
+	CodeTxInputSignature Code = 17
+	// CodeTxOutputPreSignature is set when the presignature of an output
+	// is invalid.
+	CodeTxOutputPreSignature Code = 18
+	// CodeTxOutputFee is set when an output does not pay the required fee.
+	CodeTxOutputFee Code = 19
+	// CodeTxOutputExists is set when an output is already in the state trie.
+	CodeTxOutputExists Code = 20
+	// CodeTxValueMismatch is set when the value consumed does not equal the
+	// value generated plus fees.
+	CodeTxValueMismatch Code = 21
+	// CodeTxDataStoreUpdate is set when the tx does not update a DataStore
+	// as required.
+	CodeTxDataStoreUpdate Code = 22
+	// CodeTxFee is set when the tx does not pay the required fee.
+	CodeTxFee Code = 23
+	// CodeTxSignature is set when the signatures cannot be matched to the
+	// consumed UTXOs.
+	CodeTxSignature Code = 24
+	// CodeTxState is set for any other reason the tx is not a valid
+	// state transition.
+	CodeTxState Code = 25
+	// CodeTxFork is set when the tx uses a feature of the application fork
+	// before the fork is active.
+	CodeTxFork Code = 26
+)
+
+var codeNames = map[Code]string{
+	CodeUnknown:              "UNKNOWN",
+	CodeInvalid:              "INVALID",
+	CodeStale:                "STALE",
+	CodeConsensus:            "CONSENSUS",
+	CodeConsensusLocal:       "CONSENSUS_LOCAL",
+	CodeMissingTransactions:  "MISSING_TRANSACTIONS",
+	CodeBadResponse:          "BAD_RESPONSE",
+	CodeClosing:              "CLOSING",
+	CodeCorrupt:              "CORRUPT",
+	CodeNotReady:             "NOT_READY",
+	CodeTxMalformed:          "TX_MALFORMED",
+	CodeTxChainID:            "TX_CHAIN_ID",
+	CodeTxHash:               "TX_HASH",
+	CodeTxDuplicate:          "TX_DUPLICATE",
+	CodeTxAlreadyMined:       "TX_ALREADY_MINED",
+	CodeTxInputMissing:       "TX_INPUT_MISSING",
+	CodeTxInputSpent:         "TX_INPUT_SPENT",
+	CodeTxInputSignature:     "TX_INPUT_SIGNATURE",
+	CodeTxOutputPreSignature: "TX_OUTPUT_PRE_SIGNATURE",
+	CodeTxOutputFee:          "TX_OUTPUT_FEE",
+	CodeTxOutputExists:       "TX_OUTPUT_EXISTS",
+	CodeTxValueMismatch:      "TX_VALUE_MISMATCH",
+	CodeTxDataStoreUpdate:    "TX_DATASTORE_UPDATE",
+	CodeTxFee:                "TX_FEE",
+	CodeTxSignature:          "TX_SIGNATURE",
+	CodeTxState:              "TX_STATE",
+	CodeTxFork:               "TX_FORK",
+}
+
+// String returns the name of the code.
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return codeNames[CodeUnknown]
+}
+
+// Retryable returns true if a request which failed with the code may
+// succeed when it is repeated unchanged.
+func (c Code) Retryable() bool {
+	switch c {
+	case CodeConsensusLocal, CodeMissingTransactions, CodeBadResponse, CodeClosing, CodeNotReady:
+		return true
+	default:
+		return false
+	}
+}
+
+// Code returns CodeInvalid.
+func (e *ErrInvalid) Code() Code {
+	return CodeInvalid
+}
+
+// Code returns CodeStale.
+func (e *ErrStale) Code() Code {
+	return CodeStale
+}
+
+// Code returns CodeConsensusLocal if the error is local
+// and CodeConsensus otherwise.
+func (e *ErrConsensus) Code() Code {
+	if e.isLocal {
+		return CodeConsensusLocal
+	}
+	return CodeConsensus
+}
+
+// ErrCode attaches a code of the catalogue to the error it wraps; the code
+// takes precedence over the code of the wrapped error.
+type ErrCode struct {
+	code Code
+	err  error
+}
+
+// WithCode returns err with the code attached; nil errors stay nil.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ErrCode{code: code, err: err}
+}
+
+// Error returns the message of the wrapped error.
+func (e *ErrCode) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ErrCode) Unwrap() error {
+	return e.err
+}
+
+// Code returns the attached code.
+func (e *ErrCode) Code() Code {
+	return e.code
+}
+
+type coder interface {
+	Code() Code
+}
+
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{ErrMissingTransactions, CodeMissingTransactions},
+	{ErrBadResponse, CodeBadResponse},
+	{ErrClosing, CodeClosing},
+	{ErrCorrupt, CodeCorrupt},
+	{ErrNotReady, CodeNotReady},
+}
+
+// CodeOf returns the code of err. The outermost error of the chain with a
+// code decides; gRPC status errors carrying a code are decoded as well.
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeUnknown
+	}
+	var c coder
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+	return codeFromStatus(err)
+}
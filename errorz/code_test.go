@@ -0,0 +1,130 @@
+package errorz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, CodeUnknown},
+		{"plain", errors.New("plain"), CodeUnknown},
+		{"invalid", ErrInvalid{}.New("tx.validateFees; tx.fee below minTxFee"), CodeInvalid},
+		{"stale", NewErrStale("height %v", 1), CodeStale},
+		{"consensus", NewErrConsensus("remote", false), CodeConsensus},
+		{"consensus local", NewErrConsensus("local", true), CodeConsensusLocal},
+		{"missing transactions", ErrMissingTransactions, CodeMissingTransactions},
+		{"closing wrapped", fmt.Errorf("stopping: %w", ErrClosing), CodeClosing},
+		{"not ready", ErrNotReady, CodeNotReady},
+		{"invalid wrapping consensus", NewErrInvalid("outer").Wrap(NewErrConsensus("inner", true)), CodeInvalid},
+		{"with code", WithCode(CodeTxDuplicate, ErrInvalid{}.New("txhandler.PendingTxAdd; tx already in pool")), CodeTxDuplicate},
+		{"with code wrapped", fmt.Errorf("adding: %w", WithCode(CodeTxChainID, ErrInvalid{}.New("wrong chainID"))), CodeTxChainID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeRetryable(t *testing.T) {
+	for _, c := range []Code{CodeConsensusLocal, CodeMissingTransactions, CodeBadResponse, CodeClosing, CodeNotReady} {
+		if !c.Retryable() {
+			t.Errorf("%v should be retryable", c)
+		}
+	}
+	for _, c := range []Code{CodeUnknown, CodeInvalid, CodeStale, CodeConsensus, CodeCorrupt} {
+		if c.Retryable() {
+			t.Errorf("%v should be permanent", c)
+		}
+	}
+	if WithCode(CodeTxFee, nil) != nil {
+		t.Error("nil errors should stay nil")
+	}
+	if Code(100).String() != "UNKNOWN" {
+		t.Error("codes outside of the catalogue should be named UNKNOWN")
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	if GRPCStatus(nil, codes.Unknown) != nil {
+		t.Fatal("nil errors should stay nil")
+	}
+
+	err := GRPCStatus(ErrInvalid{}.New("tx.validateFees; tx.fee below minTxFee"), codes.Unknown)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("wrong status code %v", status.Code(err))
+	}
+	if CodeOf(err) != CodeInvalid {
+		t.Fatalf("wrong code %v", CodeOf(err))
+	}
+
+	err = GRPCStatus(WithCode(CodeTxFee, ErrInvalid{}.New("tx.validateFees; tx.fee below minTxFee")), codes.Unknown)
+	if status.Code(err) != codes.InvalidArgument || CodeOf(err) != CodeTxFee {
+		t.Fatalf("rule codes should survive the status: %v", err)
+	}
+
+	err = GRPCStatus(ErrNotReady, codes.Unknown)
+	if status.Code(err) != codes.Unavailable || !CodeOf(err).Retryable() {
+		t.Fatalf("not ready should be retryable: %v", err)
+	}
+
+	err = GRPCStatus(fmt.Errorf("stopping: %w", ErrClosing), codes.Unknown)
+	if status.Code(err) != codes.Canceled || !CodeOf(err).Retryable() {
+		t.Fatalf("closing should be canceled and retryable: %v", err)
+	}
+
+	err = GRPCStatus(errors.New("plain"), codes.InvalidArgument)
+	if status.Code(err) != codes.InvalidArgument || CodeOf(err) != CodeUnknown {
+		t.Fatalf("plain errors should use the fallback: %v", err)
+	}
+
+	statusErr := status.Error(codes.NotFound, "missing")
+	if GRPCStatus(statusErr, codes.Unknown) != statusErr {
+		t.Fatal("status errors should be returned as they are")
+	}
+}
+
+func TestStatusServerInterceptor(t *testing.T) {
+	interceptor := StatusServerInterceptor(codes.InvalidArgument)
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.P2P/GetPendingTxs"}
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, NewErrStale("height %v", 1)
+	}
+	_, err := interceptor(context.Background(), nil, info, failing)
+	if status.Code(err) != codes.FailedPrecondition || CodeOf(err) != CodeStale {
+		t.Fatalf("stale errors should keep their code: %v", err)
+	}
+
+	plain := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("plain")
+	}
+	_, err = interceptor(context.Background(), nil, info, plain)
+	if status.Code(err) != codes.InvalidArgument || CodeOf(err) != CodeUnknown {
+		t.Fatalf("plain errors should use the fallback: %v", err)
+	}
+	_, err = StatusServerInterceptor(codes.Unknown)(context.Background(), nil, info, plain)
+	if status.Code(err) != codes.Unknown {
+		t.Fatalf("plain errors should use the fallback: %v", err)
+	}
+
+	succeeding := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	resp, err := interceptor(context.Background(), nil, info, succeeding)
+	if err != nil || resp != "ok" {
+		t.Fatalf("responses should pass through: %v %v", resp, err)
+	}
+}
@@ -12,6 +12,7 @@ var (
 	ErrBadResponse         = errors.New("bad response from p2p request to remote peer")
 	ErrClosing             = errors.New("shutting down, halt actions")
 	ErrCorrupt             = errors.New("something went wrong that requires shutdown")
+	ErrNotReady            = errors.New("not in sync - unsafe to serve requests at this time")
 )
 
 type Err struct {
@@ -0,0 +1,108 @@
+package errorz
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the domain of the ErrorInfo attached to gRPC status errors.
+const Domain = "alicenet"
+
+// grpcCodes maps the codes of the catalogue onto gRPC status codes; the
+// retryable codes map onto codes.Unavailable, except for CodeClosing which
+// keeps the codes.Canceled the handlers have always returned while stopping.
+var grpcCodes = map[Code]codes.Code{
+	CodeInvalid:              codes.InvalidArgument,
+	CodeStale:                codes.FailedPrecondition,
+	CodeConsensus:            codes.InvalidArgument,
+	CodeConsensusLocal:       codes.Unavailable,
+	CodeMissingTransactions:  codes.Unavailable,
+	CodeBadResponse:          codes.Unavailable,
+	CodeClosing:              codes.Canceled,
+	CodeCorrupt:              codes.Internal,
+	CodeNotReady:             codes.Unavailable,
+	CodeTxMalformed:          codes.InvalidArgument,
+	CodeTxChainID:            codes.InvalidArgument,
+	CodeTxHash:               codes.InvalidArgument,
+	CodeTxDuplicate:          codes.InvalidArgument,
+	CodeTxAlreadyMined:       codes.InvalidArgument,
+	CodeTxInputMissing:       codes.InvalidArgument,
+	CodeTxInputSpent:         codes.InvalidArgument,
+	CodeTxInputSignature:     codes.InvalidArgument,
+	CodeTxOutputPreSignature: codes.InvalidArgument,
+	CodeTxOutputFee:          codes.InvalidArgument,
+	CodeTxOutputExists:       codes.InvalidArgument,
+	CodeTxValueMismatch:      codes.InvalidArgument,
+	CodeTxDataStoreUpdate:    codes.InvalidArgument,
+	CodeTxFee:                codes.InvalidArgument,
+	CodeTxSignature:          codes.InvalidArgument,
+	CodeTxState:              codes.InvalidArgument,
+	CodeTxFork:               codes.InvalidArgument,
+}
+
+// GRPCStatus converts err into a gRPC status error which carries the code of
+// err in an ErrorInfo detail. Errors without a code get the fallback gRPC
+// status code. gRPC status errors are returned as they are.
+func GRPCStatus(err error, fallback codes.Code) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := CodeOf(err)
+	grpcCode, ok := grpcCodes[code]
+	if !ok {
+		grpcCode = fallback
+	}
+	st := status.New(grpcCode, err.Error())
+	info := &errdetails.ErrorInfo{
+		Reason: code.String(),
+		Domain: Domain,
+		Metadata: map[string]string{
+			"code":      strconv.FormatUint(uint64(code), 10),
+			"retryable": strconv.FormatBool(code.Retryable()),
+		},
+	}
+	stWithInfo, detailsErr := st.WithDetails(info)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return stWithInfo.Err()
+}
+
+// StatusServerInterceptor returns a unary server interceptor which converts
+// the errors returned by the handlers into gRPC status errors carrying their
+// Code, see GRPCStatus. Errors without a code get the fallback gRPC status
+// code.
+func StatusServerInterceptor(fallback codes.Code) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, GRPCStatus(err, fallback)
+	}
+}
+
+// codeFromStatus returns the code carried by a gRPC status error.
+func codeFromStatus(err error) Code {
+	st, ok := status.FromError(err)
+	if !ok {
+		return CodeUnknown
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != Domain {
+			continue
+		}
+		code, err := strconv.ParseUint(info.Metadata["code"], 10, 32)
+		if err != nil {
+			return CodeUnknown
+		}
+		return Code(code)
+	}
+	return CodeUnknown
+}
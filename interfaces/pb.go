@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/types"
 )
 
 // StateServer implements the State server service from the protobuf definition.
@@ -19,6 +20,7 @@ type P2PClientRaw interface {
 type P2PClient interface {
 	Close() error
 	NodeAddr() NodeAddr
+	Features() types.Features
 	CloseChan() <-chan struct{}
 	pb.P2PClient
 }
@@ -47,3 +49,9 @@ type P2PDiscoveryServer interface {
 type BootNodeServer interface {
 	pb.BootNodeServer
 }
+
+// AdminServer implements the Admin server service from the protobuf
+// definition.
+type AdminServer interface {
+	pb.AdminServer
+}
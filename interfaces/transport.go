@@ -32,6 +32,9 @@ type P2PConn interface {
 	NodeAddr() NodeAddr
 	Protocol() types.Protocol
 	ProtoVersion() types.ProtoVersion
+	Features() types.Features
+	BytesRead() uint64
+	BytesWritten() uint64
 	CloseChan() <-chan struct{}
 }
 
@@ -41,6 +44,10 @@ type P2PMuxConn interface {
 	ClientConn() P2PConn
 	ServerConn() P2PConn
 	NodeAddr() NodeAddr
+	ProtoVersion() types.ProtoVersion
+	Features() types.Features
+	BytesRead() uint64
+	BytesWritten() uint64
 	CloseChan() <-chan struct{}
 	Close() error
 }
@@ -55,6 +62,7 @@ type P2PMuxConn interface {
 // to be connected to, by the local node.
 type P2PTransport interface {
 	NodeAddr() NodeAddr
+	Features() types.Features
 	Accept() (P2PConn, error)
 	Dial(NodeAddr, types.Protocol) (P2PConn, error)
 	Close() error
@@ -0,0 +1,176 @@
+package alcb
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	aobjs "github.com/alicenet/alicenet/application/objs"
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/consensus/objs"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+func newSigner(t *testing.T) (*crypto.Secp256k1Signer, []byte) {
+	signer := &crypto.Secp256k1Signer{}
+	require.Nil(t, signer.SetPrivk(crypto.Hasher([]byte("burner"))))
+	pubk, err := signer.Pubkey()
+	require.Nil(t, err)
+	return signer, crypto.GetAccount(pubk)
+}
+
+func newUTXO(t *testing.T, account []byte, value uint64) *aobjs.TXOut {
+	owner := &aobjs.ValueStoreOwner{}
+	owner.New(account, constants.CurveSecp256k1)
+	v, err := new(uint256.Uint256).FromUint64(value)
+	require.Nil(t, err)
+	utxo := &aobjs.TXOut{}
+	err = utxo.NewValueStore(&aobjs.ValueStore{
+		VSPreImage: &aobjs.VSPreImage{
+			ChainID:  1,
+			Value:    v,
+			Owner:    owner,
+			TXOutIdx: 0,
+			Fee:      uint256.Zero(),
+		},
+		TxHash: crypto.Hasher([]byte("funding")),
+	})
+	require.Nil(t, err)
+	return utxo
+}
+
+func u256(v uint64) *uint256.Uint256 {
+	u, _ := new(uint256.Uint256).FromUint64(v)
+	return u
+}
+
+func TestNewBurnTx(t *testing.T) {
+	signer, account := newSigner(t)
+	utxos := aobjs.Vout{newUTXO(t, account, 100)}
+
+	tx, err := NewBurnTx(utxos, signer, u256(10), u256(4), u256(3))
+	require.Nil(t, err)
+	assert.Equal(t, u256(10), tx.Fee)
+	require.Equal(t, 1, len(tx.Vout))
+	change, err := tx.Vout[0].Value()
+	require.Nil(t, err)
+	assert.Equal(t, u256(87), change)
+	assert.Nil(t, tx.ValidateEqualVinVout(1, utxos))
+	assert.Nil(t, tx.ValidateSignature(1, utxos))
+
+	_, err = NewBurnTx(utxos, signer, u256(97), u256(4), u256(3))
+	assert.True(t, errors.Is(err, ErrInsufficientValue))
+	_, err = NewBurnTx(utxos, signer, u256(2), u256(4), u256(3))
+	assert.True(t, errors.Is(err, ErrBurnBelowMinFee))
+}
+
+type fakeBurnClient struct {
+	BurnClient
+	tx     *aobjs.Tx
+	height uint32
+	bh     *objs.BlockHeader
+}
+
+func (f *fakeBurnClient) GetMinedTransaction(ctx context.Context, txHash []byte) (*aobjs.Tx, error) {
+	return f.tx, nil
+}
+
+func (f *fakeBurnClient) GetBlockHeightForTx(ctx context.Context, txHash []byte) (uint32, error) {
+	return f.height, nil
+}
+
+func (f *fakeBurnClient) GetBlockHeader(ctx context.Context, height uint32) (*objs.BlockHeader, error) {
+	return f.bh, nil
+}
+
+func TestBurnProof(t *testing.T) {
+	signer, account := newSigner(t)
+	tx, err := NewBurnTx(aobjs.Vout{newUTXO(t, account, 100)}, signer, u256(10), u256(4), u256(3))
+	require.Nil(t, err)
+	txHash, err := tx.TxHash()
+	require.Nil(t, err)
+
+	txHashes := [][]byte{crypto.Hasher([]byte("a")), txHash, crypto.Hasher([]byte("b"))}
+	txRoot, err := objs.MakeTxRoot(txHashes)
+	require.Nil(t, err)
+	bClaims := &objs.BClaims{
+		ChainID:    1,
+		Height:     20,
+		TxCount:    3,
+		PrevBlock:  crypto.Hasher([]byte("prev")),
+		TxRoot:     txRoot,
+		StateRoot:  crypto.Hasher([]byte("state")),
+		HeaderRoot: crypto.Hasher([]byte("header")),
+	}
+	blockHash, err := bClaims.BlockHash()
+	require.Nil(t, err)
+	groupSigner := &crypto.BNGroupSigner{}
+	require.Nil(t, groupSigner.SetPrivk(crypto.Hasher([]byte("group"))))
+	sigGroup, err := groupSigner.Sign(blockHash)
+	require.Nil(t, err)
+
+	client := &fakeBurnClient{
+		tx:     tx,
+		height: 20,
+		bh:     &objs.BlockHeader{BClaims: bClaims, SigGroup: sigGroup, TxHshLst: txHashes},
+	}
+	proof, err := MakeBurnProof(context.Background(), client, txHash)
+	require.Nil(t, err)
+	assert.Equal(t, account, []byte(proof.Owner))
+	assert.Equal(t, uint8(constants.CurveSecp256k1), proof.CurveSpec)
+
+	groupKey, err := proof.Verify()
+	require.Nil(t, err)
+	pubk, err := groupSigner.PubkeyShare()
+	require.Nil(t, err)
+	assert.Equal(t, pubk, groupKey)
+
+	proof.TxHash = crypto.Hasher([]byte("a"))
+	_, err = proof.Verify()
+	assert.True(t, errors.Is(err, ErrInvalidBurnProof))
+}
+
+func TestDepositor_DepositFromReceipt(t *testing.T) {
+	alcbAddress := common.HexToAddress("0x0a")
+	owner := common.HexToAddress("0x02")
+	alcb := mocks.NewMockIALCB()
+	alcb.ParseDepositReceivedFunc.SetDefaultReturn(&bindings.ALCBDepositReceived{
+		DepositID:   big.NewInt(5),
+		AccountType: uint8(constants.CurveBN256Eth),
+		Depositor:   owner,
+		Amount:      big.NewInt(1000),
+	}, nil)
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.ALCBFunc.SetDefaultReturn(alcb)
+	ethereumContracts.ALCBAddressFunc.SetDefaultReturn(alcbAddress)
+	contracts := mocks.NewMockAllSmartContracts()
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+	depositor := NewDepositor(mocks.NewMockClient(), contracts, mocks.NewMockWatcher(), accounts.Account{}, mocks.NewMockLogger().WithField("test", t.Name()))
+
+	_, err := depositor.depositFromReceipt(&types.Receipt{})
+	assert.True(t, errors.Is(err, ErrDepositNotFound))
+
+	depositReceived := events.GetALCBEvents()["DepositReceived"]
+	deposit, err := depositor.depositFromReceipt(&types.Receipt{
+		Logs: []*types.Log{{Address: alcbAddress, Topics: []common.Hash{depositReceived.ID}}},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, uint32(5), deposit.Nonce)
+	assert.Equal(t, constants.CurveBN256Eth, deposit.AccountType)
+	assert.Equal(t, owner, deposit.Owner)
+	assert.Equal(t, big.NewInt(1000), deposit.Amount)
+
+	_, err = depositor.Deposit(context.Background(), constants.CurveSpec(3), owner, big.NewInt(1))
+	assert.True(t, errors.Is(err, ErrInvalidAccountType))
+}
@@ -0,0 +1,318 @@
+package alcb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+
+	aobjs "github.com/alicenet/alicenet/application/objs"
+	"github.com/alicenet/alicenet/application/objs/uint256"
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/consensus/objs"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+)
+
+var (
+	ErrInsufficientValue = errors.New("not enough value to burn")
+	ErrBurnBelowMinFee   = errors.New("the amount to burn is below the minimum transaction fee")
+	ErrInvalidBurnProof  = errors.New("invalid burn proof")
+)
+
+// BurnClient is the part of the localrpc.Client needed to burn value and
+// prove it.
+type BurnClient interface {
+	GetTxFees(ctx context.Context) ([]string, error)
+	GetValueForOwner(ctx context.Context, curveSpec constants.CurveSpec, account []byte, minValue *uint256.Uint256) ([][]byte, *uint256.Uint256, error)
+	GetUTXO(ctx context.Context, utxoIDs [][]byte) (aobjs.Vout, error)
+	SendTransaction(ctx context.Context, tx *aobjs.Tx) ([]byte, error)
+	GetMinedTransaction(ctx context.Context, txHash []byte) (*aobjs.Tx, error)
+	GetBlockHeightForTx(ctx context.Context, txHash []byte) (uint32, error)
+	GetBlockHeader(ctx context.Context, height uint32) (*objs.BlockHeader, error)
+}
+
+// BurnProof proves that a transaction burning AliceNet value was mined. The
+// BClaims and SigGroup of the block can be checked on layer1 against the
+// master public key of the validators, and TxRootProof against the TxRoot of
+// the BClaims.
+type BurnProof struct {
+	TxHash      hexutil.Bytes `json:"txHash"`
+	Height      uint32        `json:"height"`
+	Owner       hexutil.Bytes `json:"owner"`
+	CurveSpec   uint8         `json:"curveSpec"`
+	Amount      string        `json:"amount"`
+	Tx          hexutil.Bytes `json:"tx"`
+	BClaims     hexutil.Bytes `json:"bClaims"`
+	SigGroup    hexutil.Bytes `json:"sigGroup"`
+	TxRootProof hexutil.Bytes `json:"txRootProof"`
+}
+
+// signerAccount returns the curve and account of an AliceNet signer.
+func signerAccount(signer aobjs.Signer) (constants.CurveSpec, []byte, error) {
+	var curveSpec constants.CurveSpec
+	switch signer.(type) {
+	case *crypto.Secp256k1Signer:
+		curveSpec = constants.CurveSecp256k1
+	case *crypto.BNSigner:
+		curveSpec = constants.CurveBN256Eth
+	default:
+		return 0, nil, ErrInvalidAccountType
+	}
+	pubk, err := signer.Pubkey()
+	if err != nil {
+		return 0, nil, err
+	}
+	return curveSpec, crypto.GetAccount(pubk), nil
+}
+
+// NewBurnTx returns a transaction that consumes utxos, burns amount as its
+// fee and returns the rest, minus valueStoreFee, to the owner of signer.
+func NewBurnTx(utxos aobjs.Vout, signer aobjs.Signer, amount, minTxFee, valueStoreFee *uint256.Uint256) (*aobjs.Tx, error) {
+	if amount.Lt(minTxFee) {
+		return nil, fmt.Errorf("%w: amount %v, minimum %v", ErrBurnBelowMinFee, amount, minTxFee)
+	}
+	if len(utxos) == 0 {
+		return nil, ErrInsufficientValue
+	}
+	curveSpec, account, err := signerAccount(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &aobjs.Tx{
+		Vin:  aobjs.Vin{},
+		Vout: aobjs.Vout{},
+		Fee:  amount.Clone(),
+	}
+	var chainID uint32
+	valueIn := uint256.Zero()
+	for _, utxo := range utxos {
+		vs, err := utxo.ValueStore()
+		if err != nil {
+			return nil, err
+		}
+		value, err := vs.Value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := valueIn.Add(valueIn, value); err != nil {
+			return nil, err
+		}
+		if chainID, err = vs.ChainID(); err != nil {
+			return nil, err
+		}
+		txIn, err := utxo.MakeTxIn()
+		if err != nil {
+			return nil, err
+		}
+		tx.Vin = append(tx.Vin, txIn)
+	}
+
+	// the change has to pay its own fee and be worth something
+	spent, err := new(uint256.Uint256).Add(amount, valueStoreFee)
+	if err != nil {
+		return nil, err
+	}
+	if !valueIn.Gt(spent) {
+		return nil, fmt.Errorf("%w: value %v, amount plus fee %v", ErrInsufficientValue, valueIn, spent)
+	}
+	change, err := new(uint256.Uint256).Sub(valueIn, spent)
+	if err != nil {
+		return nil, err
+	}
+	owner := &aobjs.ValueStoreOwner{}
+	owner.New(account, curveSpec)
+	changeUTXO := &aobjs.TXOut{}
+	err = changeUTXO.NewValueStore(&aobjs.ValueStore{
+		VSPreImage: &aobjs.VSPreImage{
+			ChainID:  chainID,
+			Value:    change,
+			Owner:    owner,
+			TXOutIdx: 0,
+			Fee:      valueStoreFee.Clone(),
+		},
+		TxHash: make([]byte, constants.HashLen),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx.Vout = append(tx.Vout, changeUTXO)
+
+	if err := tx.SetTxHash(); err != nil {
+		return nil, err
+	}
+	for idx, utxo := range utxos {
+		vs, err := utxo.ValueStore()
+		if err != nil {
+			return nil, err
+		}
+		if err := vs.Sign(tx.Vin[idx], signer); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// Burn burns amount of the value owned by signer and waits until the
+// transaction is mined. It returns the hash of the transaction.
+func Burn(ctx context.Context, client BurnClient, signer aobjs.Signer, amount *uint256.Uint256, pollInterval time.Duration, logger *logrus.Entry) ([]byte, error) {
+	curveSpec, account, err := signerAccount(signer)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := client.GetTxFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(fees) < 2 {
+		return nil, errors.New("invalid fee response")
+	}
+	minTxFee, valueStoreFee := new(uint256.Uint256), new(uint256.Uint256)
+	if err := minTxFee.UnmarshalString(fees[0]); err != nil {
+		return nil, err
+	}
+	if err := valueStoreFee.UnmarshalString(fees[1]); err != nil {
+		return nil, err
+	}
+
+	minValue, err := new(uint256.Uint256).Add(amount, valueStoreFee)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := minValue.Add(minValue, uint256.One()); err != nil {
+		return nil, err
+	}
+	utxoIDs, total, err := client.GetValueForOwner(ctx, curveSpec, account, minValue)
+	if err != nil {
+		return nil, err
+	}
+	if total.Lt(minValue) {
+		return nil, fmt.Errorf("%w: value %v, required %v", ErrInsufficientValue, total, minValue)
+	}
+	utxos, err := client.GetUTXO(ctx, utxoIDs)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := NewBurnTx(utxos, signer, amount, minTxFee, valueStoreFee)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := tx.TxHash()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	logger = logger.WithField("TxHash", fmt.Sprintf("%x", txHash))
+	logger.Infof("Burning %v", amount)
+	for {
+		if _, err := client.GetMinedTransaction(ctx, txHash); err == nil {
+			return txHash, nil
+		}
+		logger.Debug("Waiting for the burn transaction to be mined")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// MakeBurnProof builds the BurnProof of a mined transaction.
+func MakeBurnProof(ctx context.Context, client BurnClient, txHash []byte) (*BurnProof, error) {
+	tx, err := client.GetMinedTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	height, err := client.GetBlockHeightForTx(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	bh, err := client.GetBlockHeader(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	txRoot, txRootProof, err := db.MakeTxRootProof(bh.TxHshLst, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(txRoot, bh.BClaims.TxRoot) {
+		return nil, fmt.Errorf("%w: TxRoot mismatch at height %v", ErrInvalidBurnProof, height)
+	}
+	// the change of a burn goes back to the owner of the burned value
+	vs, err := tx.Vout[0].ValueStore()
+	if err != nil {
+		return nil, err
+	}
+	owner, err := vs.Owner()
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	bClaims, err := bh.BClaims.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	amount, err := tx.Fee.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+	return &BurnProof{
+		TxHash:      txHash,
+		Height:      height,
+		Owner:       owner.Account,
+		CurveSpec:   uint8(owner.CurveSpec),
+		Amount:      amount,
+		Tx:          rawTx,
+		BClaims:     bClaims,
+		SigGroup:    bh.SigGroup,
+		TxRootProof: txRootProof,
+	}, nil
+}
+
+// Verify checks the proof as layer1 would: the transaction is included in the
+// block and the block is signed by the validators. It returns the group key
+// that signed the block, which has to be the master public key of the
+// validator set at that height.
+func (p *BurnProof) Verify() ([]byte, error) {
+	tx := &aobjs.Tx{}
+	if err := tx.UnmarshalBinary(p.Tx); err != nil {
+		return nil, err
+	}
+	txHash, err := tx.TxHash()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(txHash, p.TxHash) {
+		return nil, fmt.Errorf("%w: tx hash mismatch", ErrInvalidBurnProof)
+	}
+	bClaims := &objs.BClaims{}
+	if err := bClaims.UnmarshalBinary(p.BClaims); err != nil {
+		return nil, err
+	}
+	if bClaims.Height != p.Height {
+		return nil, fmt.Errorf("%w: height mismatch", ErrInvalidBurnProof)
+	}
+	included, err := db.VerifyTxRootProof(bClaims.TxRoot, p.TxHash, p.TxRootProof)
+	if err != nil {
+		return nil, err
+	}
+	if !included {
+		return nil, fmt.Errorf("%w: tx not included in TxRoot", ErrInvalidBurnProof)
+	}
+	blockHash, err := bClaims.BlockHash()
+	if err != nil {
+		return nil, err
+	}
+	return new(crypto.BNGroupValidator).Validate(blockHash, p.SigGroup)
+}
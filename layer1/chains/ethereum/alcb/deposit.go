@@ -0,0 +1,161 @@
+// Package alcb moves value between ALCB on layer1 and AliceNet.
+//
+// A deposit burns ALCB on layer1 and emits a DepositReceived event, the
+// monitor of every node turns it into a deposit UTXO whose id is the deposit
+// nonce. The reverse path burns AliceNet value as the fee of a transaction and
+// builds a BurnProof of it that can be checked on layer1 against the group key
+// of the validators.
+package alcb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/alicenet/alicenet/localrpc"
+)
+
+var (
+	ErrInvalidAccountType  = errors.New("invalid account type, only secp256k1 and BN accounts can own deposits")
+	ErrInsufficientALCB    = errors.New("not enough ALCB to deposit")
+	ErrTransactionReverted = errors.New("the transaction was reverted")
+	ErrDepositNotFound     = errors.New("could not find the DepositReceived event in the transaction receipt")
+)
+
+// Deposit is an ALCB deposit made on layer1.
+type Deposit struct {
+	Nonce       uint32
+	AccountType constants.CurveSpec
+	Owner       common.Address
+	Amount      *big.Int
+	TxHash      common.Hash
+}
+
+// Depositor sends ALCB deposits from a layer1 account.
+type Depositor struct {
+	eth       layer1.Client
+	contracts layer1.AllSmartContracts
+	watcher   transaction.Watcher
+	account   accounts.Account
+	logger    *logrus.Entry
+}
+
+// NewDepositor creates a Depositor that sends the deposits from account.
+func NewDepositor(
+	eth layer1.Client,
+	contracts layer1.AllSmartContracts,
+	watcher transaction.Watcher,
+	account accounts.Account,
+	logger *logrus.Entry,
+) *Depositor {
+	return &Depositor{
+		eth:       eth,
+		contracts: contracts,
+		watcher:   watcher,
+		account:   account,
+		logger:    logger,
+	}
+}
+
+// Deposit burns amount of the account's ALCB and credits it to owner on
+// AliceNet. It returns once the deposit transaction has been mined.
+func (d *Depositor) Deposit(ctx context.Context, accountType constants.CurveSpec, owner common.Address, amount *big.Int) (*Deposit, error) {
+	if accountType != constants.CurveSecp256k1 && accountType != constants.CurveBN256Eth {
+		return nil, ErrInvalidAccountType
+	}
+	alcb := d.contracts.EthereumContracts().ALCB()
+	callOpts, err := d.eth.GetCallOpts(ctx, d.account)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := alcb.BalanceOf(callOpts, d.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: balance %v, amount %v", ErrInsufficientALCB, balance, amount)
+	}
+
+	txOpts, err := d.eth.GetTransactionOpts(ctx, d.account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction options: %w", err)
+	}
+	txn, err := alcb.Deposit(txOpts, uint8(accountType), owner, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the deposit: %w", err)
+	}
+	d.logger.WithField("Txn", txn.Hash().Hex()).Infof("Depositing %v ALCB to %v", amount, owner.Hex())
+	receipt, err := d.watcher.SubscribeAndWait(ctx, txn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt of the deposit: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %v", ErrTransactionReverted, receipt.TxHash.Hex())
+	}
+	return d.depositFromReceipt(receipt)
+}
+
+// depositFromReceipt returns the deposit of the DepositReceived event in the
+// receipt.
+func (d *Depositor) depositFromReceipt(receipt *types.Receipt) (*Deposit, error) {
+	c := d.contracts.EthereumContracts()
+	depositReceived := events.GetALCBEvents()["DepositReceived"]
+	for _, log := range receipt.Logs {
+		if log.Address != c.ALCBAddress() || len(log.Topics) == 0 || log.Topics[0] != depositReceived.ID {
+			continue
+		}
+		event, err := c.ALCB().ParseDepositReceived(*log)
+		if err != nil {
+			return nil, err
+		}
+		return &Deposit{
+			Nonce:       uint32(event.DepositID.Uint64()),
+			AccountType: constants.CurveSpec(event.AccountType),
+			Owner:       event.Depositor,
+			Amount:      event.Amount,
+			TxHash:      receipt.TxHash,
+		}, nil
+	}
+	return nil, ErrDepositNotFound
+}
+
+// DepositClient is the part of the localrpc.Client needed to follow a
+// deposit.
+type DepositClient interface {
+	GetDepositStatus(ctx context.Context, nonce uint32) (*localrpc.DepositStatus, error)
+}
+
+// WaitForDeposit polls the node until the deposit with nonce has been turned
+// into a UTXO.
+func WaitForDeposit(ctx context.Context, client DepositClient, nonce uint32, pollInterval time.Duration, logger *logrus.Entry) (*localrpc.DepositStatus, error) {
+	for {
+		status, err := client.GetDepositStatus(ctx, nonce)
+		if err != nil {
+			logger.Debugf("Failed to get the deposit status: %v", err)
+		} else if status.UTXO != nil {
+			return status, nil
+		} else {
+			logger.WithFields(logrus.Fields{
+				"LatestDepositSeen":      status.LatestDepositSeen,
+				"LatestDepositProcessed": status.LatestDepositProcessed,
+			}).Debugf("Waiting for deposit %v", nonce)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
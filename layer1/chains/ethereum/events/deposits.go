@@ -13,9 +13,10 @@ import (
 	"github.com/alicenet/alicenet/errorz"
 	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/monitor/interfaces"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
 )
 
-func ProcessDepositReceived(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, log types.Log, cdb, monDB *db.Database, depositHandler interfaces.DepositHandler, chainID uint32) error {
+func ProcessDepositReceived(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *objects.MonitorState, log types.Log, cdb, monDB *db.Database, depositHandler interfaces.DepositHandler, chainID uint32) error {
 	event, err := contracts.EthereumContracts().ALCB().ParseDepositReceived(log)
 	if err != nil {
 		return err
@@ -27,6 +28,13 @@ func ProcessDepositReceived(eth layer1.Client, contracts layer1.AllSmartContract
 		"Amount":    event.Amount,
 	}).Debug("Deposit received")
 
+	nonce := uint32(event.DepositID.Uint64())
+	state.Lock()
+	if nonce > state.LatestDepositSeen {
+		state.LatestDepositSeen = nonce
+	}
+	state.Unlock()
+
 	err = cdb.Update(func(txn *badger.Txn) error {
 		depositNonce := event.DepositID.Bytes()
 		account := event.Depositor.Bytes()
@@ -46,6 +54,13 @@ func ProcessDepositReceived(eth layer1.Client, contracts layer1.AllSmartContract
 		if !errors.As(err, &e) {
 			return err
 		}
+		return nil
+	}
+
+	state.Lock()
+	if nonce > state.LatestDepositProcessed {
+		state.LatestDepositProcessed = nonce
 	}
+	state.Unlock()
 	return nil
 }
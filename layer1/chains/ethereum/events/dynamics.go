@@ -3,6 +3,7 @@ package events
 import (
 	"fmt"
 
+	"github.com/alicenet/alicenet/bridge/bindings"
 	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dynamics"
 	"github.com/alicenet/alicenet/layer1/executor"
@@ -95,7 +96,7 @@ func ProcessNewCanonicalAliceNetNodeVersion(
 	contracts layer1.AllSmartContracts,
 	logger *logrus.Entry,
 	log types.Log,
-	exitFunc func(),
+	exitFunc func(version bindings.CanonicalVersion),
 ) error {
 	logger = logger.WithField("method", "ProcessNewCanonicalAliceNetNodeVersion")
 	logger.Info("Processing new AliceNet node version becoming canonical...")
@@ -126,7 +127,7 @@ func ProcessNewCanonicalAliceNetNodeVersion(
 			event.Version.Minor,
 			event.Version.Patch,
 		)
-		exitFunc()
+		exitFunc(event.Version)
 	}
 	return nil
 }
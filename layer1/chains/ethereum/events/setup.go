@@ -142,7 +142,7 @@ func SetupEventMap(
 	adminHandler monInterfaces.AdminHandler,
 	depositHandler monInterfaces.DepositHandler,
 	taskHandler executor.TaskHandler,
-	exitFunc func(),
+	exitFunc func(version bindings.CanonicalVersion),
 	chainID uint32,
 ) error {
 	RegisterETHDKGEvents(em, monDB, adminHandler, taskHandler)
@@ -156,7 +156,7 @@ func SetupEventMap(
 
 	if err := em.Register(depositReceived.ID.String(), depositReceived.Name,
 		func(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *objects.MonitorState, log types.Log) error {
-			return ProcessDepositReceived(eth, contracts, logger, log, cdb, monDB, depositHandler, chainID)
+			return ProcessDepositReceived(eth, contracts, logger, state, log, cdb, monDB, depositHandler, chainID)
 		}); err != nil {
 		return err
 	}
@@ -170,7 +170,7 @@ func SetupEventMap(
 
 	if err := em.Register(snapshotTakenEvent.ID.String(), snapshotTakenEvent.Name,
 		func(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *objects.MonitorState, log types.Log) error {
-			return ProcessSnapshotTaken(contracts, logger, log, adminHandler, taskHandler)
+			return ProcessSnapshotTaken(contracts, logger, state, log, adminHandler, taskHandler)
 		}); err != nil {
 		return err
 	}
@@ -3,13 +3,19 @@ package events
 import (
 	"math/big"
 
+	"github.com/alicenet/alicenet/bridge/bindings"
 	"github.com/alicenet/alicenet/consensus/objs"
 	"github.com/alicenet/alicenet/crypto/bn256"
 	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
 	"github.com/alicenet/alicenet/layer1/executor"
 	monInterfaces "github.com/alicenet/alicenet/layer1/monitor/interfaces"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,6 +23,7 @@ import (
 func ProcessSnapshotTaken(
 	contracts layer1.AllSmartContracts,
 	logger *logrus.Entry,
+	monitorState *objects.MonitorState,
 	log types.Log,
 	adminHandler monInterfaces.AdminHandler,
 	taskHandler executor.TaskHandler,
@@ -82,9 +89,75 @@ func ProcessSnapshotTaken(
 		return err
 	}
 
+	// the report is informative only, it must not stop the events processing
+	_, err = taskHandler.ScheduleTask(
+		snapshots.NewSnapshotReportTask(newSnapshotReport(monitorState, event, header.SigGroup)),
+		"",
+	)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to schedule the snapshot report")
+	}
+
 	return nil
 }
 
+// newSnapshotReport returns the report of a snapshot with the data of the
+// event and the leader order of the election. The data read from layer1 is
+// added by the SnapshotReportTask.
+func newSnapshotReport(
+	monitorState *objects.MonitorState,
+	event *bindings.SnapshotsSnapshotTaken,
+	sigGroup []byte,
+) *state.SnapshotReport {
+	report := &state.SnapshotReport{
+		Height:        uint32(event.Height.Uint64()),
+		Epoch:         event.Epoch.Uint64(),
+		TxHash:        event.Raw.TxHash,
+		CommittedAt:   event.Raw.BlockNumber,
+		Submitter:     event.Validator,
+		SubmitterRank: -1,
+	}
+
+	validators := getSnapshotValidators(monitorState, report.Height)
+	for _, idx := range utils.LeaderOrder(len(validators), crypto.Keccak256(sigGroup)) {
+		report.LeaderOrder = append(report.LeaderOrder, validators[idx])
+	}
+	for rank, validator := range report.LeaderOrder {
+		if validator == report.Submitter {
+			report.SubmitterRank = rank
+			break
+		}
+	}
+	return report
+}
+
+// getSnapshotValidators returns the accounts of the validator set of an
+// AliceNet height, in the order of the validator set.
+func getSnapshotValidators(monitorState *objects.MonitorState, height uint32) []common.Address {
+	monitorState.RLock()
+	defer monitorState.RUnlock()
+
+	var validatorSet objects.ValidatorSet
+	epoch, found := uint32(0), false
+	for e, vs := range monitorState.ValidatorSets {
+		if vs.NotBeforeAliceNetHeight <= height &&
+			(!found || vs.NotBeforeAliceNetHeight > validatorSet.NotBeforeAliceNetHeight) {
+			epoch, validatorSet, found = e, vs, true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	validators := make([]common.Address, validatorSet.ValidatorCount)
+	for _, member := range monitorState.Validators[epoch] {
+		if member.Index > 0 && int(member.Index) <= len(validators) {
+			validators[member.Index-1] = member.Account
+		}
+	}
+	return validators
+}
+
 // ProcessSnapshotTakenOld handles receiving snapshots.
 func ProcessSnapshotTakenOld(
 	eth layer1.Client,
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/dynamics"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+func TestProcessDynamicValueChanged_MemoByteFee(t *testing.T) {
+	database := mocks.NewTestDB()
+	logger := logrus.NewEntry(logrus.New())
+	storage := &dynamics.Storage{}
+	require.Nil(t, storage.Init(database, logger.Logger))
+
+	// V1 values at epoch 1 and the V2 values with a memo byte fee of 1000 at
+	// epoch 2
+	v1, err := hex.DecodeString("00000fa000000bb800000bb8002dc6c00000000000000000000000000000000000000000000000000000000000000000")
+	require.Nil(t, err)
+	v2, err := hex.DecodeString("01000fa000000bb800000bb8002dc6c0000000000000000000000000000000000000000000000000000000000000000000000000000003e8")
+	require.Nil(t, err)
+
+	var event *bindings.DynamicsDynamicValueChanged
+	dynamicsContract := mocks.NewMockIDynamics()
+	dynamicsContract.ParseDynamicValueChangedFunc.SetDefaultHook(func(log types.Log) (*bindings.DynamicsDynamicValueChanged, error) {
+		return event, nil
+	})
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.DynamicsFunc.SetDefaultReturn(dynamicsContract)
+	contracts := mocks.NewMockAllSmartContracts()
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	adminHandler := mocks.NewMockAdminHandler()
+	adminHandler.UpdateDynamicStorageFunc.SetDefaultHook(func(epoch uint32, rawDynamics []byte) error {
+		return database.Update(func(txn *badger.Txn) error {
+			return storage.ChangeDynamicValues(txn, epoch, rawDynamics)
+		})
+	})
+
+	event = &bindings.DynamicsDynamicValueChanged{Epoch: big.NewInt(1), RawDynamicValues: v1}
+	require.Nil(t, events.ProcessDynamicValueChanged(contracts, logger, types.Log{}, adminHandler))
+	event = &bindings.DynamicsDynamicValueChanged{Epoch: big.NewInt(2), RawDynamicValues: v2}
+	require.Nil(t, events.ProcessDynamicValueChanged(contracts, logger, types.Log{}, adminHandler))
+
+	updateEpoch := func(epoch uint32) {
+		t.Helper()
+		err := database.Update(func(txn *badger.Txn) error {
+			return storage.UpdateCurrentDynamicValue(txn, epoch)
+		})
+		require.Nil(t, err)
+	}
+	// the values of an event are in effect once its epoch is over
+	updateEpoch(2)
+	assert.Equal(t, 0, storage.GetMemoByteFee().Sign())
+	updateEpoch(3)
+	assert.Equal(t, big.NewInt(1000), storage.GetMemoByteFee())
+}
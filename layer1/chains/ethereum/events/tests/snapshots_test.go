@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/crypto/bn256"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	snapshotTasks "github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
+	execMocks "github.com/alicenet/alicenet/layer1/executor/mocks"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/test/mocks"
+	"github.com/alicenet/alicenet/utils"
+)
+
+func TestProcessSnapshotTaken_Report(t *testing.T) {
+	db := mocks.NewTestDB()
+	logger := logrus.NewEntry(logrus.New())
+
+	monitorState := objects.NewMonitorState()
+	monitorState.ValidatorSets[1] = objects.ValidatorSet{ValidatorCount: 4, NotBeforeAliceNetHeight: 1}
+	validators := make([]common.Address, 4)
+	for i := range validators {
+		validators[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+		monitorState.Validators[1] = append(
+			monitorState.Validators[1],
+			objects.Validator{Account: validators[i], Index: uint8(i + 1)},
+		)
+	}
+
+	masterPublicKey := [4]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	signature := [2]*big.Int{big.NewInt(5), big.NewInt(6)}
+	sigGroup, err := bn256.MarshalBigIntSlice(append(masterPublicKey[:], signature[:]...))
+	require.Nil(t, err)
+	order := utils.LeaderOrder(4, crypto.Keccak256(sigGroup))
+
+	event := &bindings.SnapshotsSnapshotTaken{
+		ChainId:         big.NewInt(1),
+		Epoch:           big.NewInt(2),
+		Height:          big.NewInt(2048),
+		Validator:       validators[order[2]],
+		MasterPublicKey: masterPublicKey,
+		Signature:       signature,
+		BClaims:         bindings.BClaimsParserLibraryBClaims{ChainId: 1, Height: 2048},
+		Raw:             types.Log{BlockNumber: 160, TxHash: common.HexToHash("0x01")},
+	}
+
+	snapshots := mocks.NewMockISnapshots()
+	snapshots.ParseSnapshotTakenFunc.SetDefaultHook(func(log types.Log) (*bindings.SnapshotsSnapshotTaken, error) {
+		return event, nil
+	})
+	snapshots.GetSnapshotDesperationDelayFunc.SetDefaultReturn(big.NewInt(10), nil)
+	snapshots.GetSnapshotDesperationFactorFunc.SetDefaultReturn(big.NewInt(40), nil)
+	snapshots.GetCommittedHeightFromSnapshotFunc.SetDefaultReturn(big.NewInt(100), nil)
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.SnapshotsFunc.SetDefaultReturn(snapshots)
+	contracts := mocks.NewMockAllSmartContracts()
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	eth := mocks.NewMockClient()
+	eth.GetTransactionReceiptFunc.SetDefaultReturn(&types.Receipt{GasUsed: 250_000}, nil)
+
+	adminHandler := mocks.NewMockAdminHandler()
+	taskHandler := execMocks.NewMockTaskHandler()
+	// runs the report task scheduled by the event processing
+	runReportTask := func() {
+		t.Helper()
+		history := taskHandler.ScheduleTaskFunc.History()
+		require.NotEmpty(t, history)
+		task, ok := history[len(history)-1].Arg0.(*snapshotTasks.SnapshotReportTask)
+		require.True(t, ok)
+		err := task.Initialize(db, logger, eth, contracts, "SnapshotReportTask", "", 0, 0, true, nil, nil)
+		require.Nil(t, err)
+		txn, taskErr := task.Execute(context.Background())
+		require.Nil(t, taskErr)
+		assert.Nil(t, txn)
+	}
+
+	err = events.ProcessSnapshotTaken(contracts, logger, monitorState, types.Log{}, adminHandler, taskHandler)
+	require.Nil(t, err)
+	// nothing is read from layer1 while the event is processed
+	assert.Empty(t, eth.GetTransactionReceiptFunc.History())
+	assert.Empty(t, snapshots.GetSnapshotDesperationDelayFunc.History())
+	_, err = state.GetSnapshotReport(db, 2048)
+	assert.ErrorIs(t, err, badger.ErrKeyNotFound)
+	runReportTask()
+
+	report, err := state.GetSnapshotReport(db, 2048)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(2), report.Epoch)
+	assert.Equal(t, uint64(100), report.ElectionStart)
+	assert.Equal(t, uint64(160), report.CommittedAt)
+	assert.Equal(t, uint64(60), report.Latency())
+	assert.Equal(t, uint64(250_000), report.GasUsed)
+	assert.Equal(t, 2, report.SubmitterRank)
+	assert.Equal(t, validators[order[0]], report.LeaderOrder[0])
+	assert.Len(t, report.LeaderOrder, 4)
+	// 100 + delay 10 + (1 + 40/1) blocks since desperation
+	assert.Equal(t, uint64(151), report.SubmitterAllowedAt)
+
+	// the next snapshot election starts at the block of this one
+	event.Epoch = big.NewInt(3)
+	event.Height = big.NewInt(3072)
+	event.Validator = common.HexToAddress("0xff")
+	event.Raw.BlockNumber = 200
+	err = events.ProcessSnapshotTaken(contracts, logger, monitorState, types.Log{}, adminHandler, taskHandler)
+	require.Nil(t, err)
+	runReportTask()
+
+	reports, next, err := state.GetSnapshotReports(db, 0, 1)
+	require.Nil(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, uint32(2048), reports[0].Height)
+	assert.Equal(t, uint32(3072), next)
+
+	reports, next, err = state.GetSnapshotReports(db, next, 0)
+	require.Nil(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, uint32(0), next)
+	assert.Equal(t, uint64(160), reports[0].ElectionStart)
+	assert.Equal(t, -1, reports[0].SubmitterRank)
+	assert.Equal(t, uint64(0), reports[0].SubmitterAllowedAt)
+	// only the first election start was read from the contract
+	assert.Len(t, snapshots.GetCommittedHeightFromSnapshotFunc.History(), 1)
+
+	// the first snapshot has no previous election
+	event.Epoch = big.NewInt(1)
+	event.Height = big.NewInt(1024)
+	event.Raw.BlockNumber = 80
+	err = events.ProcessSnapshotTaken(contracts, logger, monitorState, types.Log{}, adminHandler, taskHandler)
+	require.Nil(t, err)
+	runReportTask()
+
+	report, err = state.GetSnapshotReport(db, 1024)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(0), report.ElectionStart)
+	assert.Equal(t, uint64(0), report.Latency())
+	assert.Len(t, snapshots.GetCommittedHeightFromSnapshotFunc.History(), 1)
+}
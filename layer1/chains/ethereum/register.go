@@ -22,5 +22,6 @@ func GetTaskRegistry(tr *marshaller.TypeRegistry) *marshaller.TypeRegistry {
 	tr.RegisterInstanceType(&dkg.DisputeMissingRegistrationTask{})
 	tr.RegisterInstanceType(&dkg.ShareDistributionTask{})
 	tr.RegisterInstanceType(&snapshots.SnapshotTask{})
+	tr.RegisterInstanceType(&snapshots.SnapshotReportTask{})
 	return tr
 }
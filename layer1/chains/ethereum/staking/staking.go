@@ -0,0 +1,520 @@
+// Package staking implements the lifecycle of a validator on layer1: staking
+// ALCA into a PublicStaking position, registering the position in the
+// ValidatorPool, leaving the pool and claiming the profits and the position
+// back.
+//
+// The ValidatorPool only accepts registrations and unregistrations from the
+// AliceNetFactory, so Register and Unregister have to be called with the
+// account that owns the factory. The position to register has to be owned by
+// that account or by the factory itself.
+package staking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/layer1/transaction"
+)
+
+var (
+	ErrInsufficientALCA      = errors.New("not enough ALCA to stake")
+	ErrNotFactoryOwner       = errors.New("the account does not own the AliceNetFactory, only the factory can register and unregister validators")
+	ErrNotPositionOwner      = errors.New("the position is not owned by the account or by the AliceNetFactory")
+	ErrInsufficientStake     = errors.New("the position does not have enough shares to register a validator")
+	ErrNothingToClaim        = errors.New("the account is neither a validator nor in the exiting queue")
+	ErrTransactionReverted   = errors.New("the transaction was reverted")
+	ErrPositionNotFound      = errors.New("could not find the minted position in the transaction receipt")
+	ErrNotValidator          = errors.New("the account is not a validator")
+	ErrValidatorAlreadyExist = errors.New("the account is already a validator")
+)
+
+// Position is a PublicStaking position owned by the account.
+type Position struct {
+	TokenID           *big.Int
+	Shares            *big.Int
+	FreeAfter         *big.Int
+	WithdrawFreeAfter *big.Int
+	PendingEth        *big.Int
+	PendingToken      *big.Int
+}
+
+// Status of the account in the ValidatorPool.
+type Status struct {
+	Account              common.Address
+	EthBalance           *big.Int
+	ALCABalance          *big.Int
+	StakeAmount          *big.Int
+	IsValidator          bool
+	IsAccusable          bool
+	IsInExitingQueue     bool
+	ConsensusRunning     bool
+	MaintenanceScheduled bool
+	// TokenID is the ValidatorStaking position of a validator, or the
+	// PublicStaking position of an exiting validator.
+	TokenID *big.Int
+	// PendingEth and PendingToken are the profits of the ValidatorStaking
+	// position that can be collected by a validator.
+	PendingEth   *big.Int
+	PendingToken *big.Int
+	Positions    []Position
+}
+
+// ClaimResult is the outcome of a Claim.
+type ClaimResult struct {
+	// ExitingPosition is true if the PublicStaking position of an exiting
+	// validator was claimed, false if the profits of a validator were
+	// collected.
+	ExitingPosition bool
+	TokenID         *big.Int
+	PayoutEth       *big.Int
+	PayoutToken     *big.Int
+}
+
+// Staker sends the validator lifecycle transactions of an account and waits
+// for their receipts.
+type Staker struct {
+	eth          layer1.Client
+	contracts    layer1.AllSmartContracts
+	watcher      transaction.Watcher
+	account      accounts.Account
+	logger       *logrus.Entry
+	pollInterval time.Duration
+}
+
+// NewStaker creates a Staker that sends the transactions from account.
+func NewStaker(
+	eth layer1.Client,
+	contracts layer1.AllSmartContracts,
+	watcher transaction.Watcher,
+	account accounts.Account,
+	logger *logrus.Entry,
+) *Staker {
+	return &Staker{
+		eth:          eth,
+		contracts:    contracts,
+		watcher:      watcher,
+		account:      account,
+		logger:       logger,
+		pollInterval: constants.MonitorInterval,
+	}
+}
+
+// Stake approves amount of ALCA to PublicStaking if needed and mints a
+// position with it. It returns the token id of the new position.
+func (s *Staker) Stake(ctx context.Context, amount *big.Int) (*big.Int, error) {
+	c := s.contracts.EthereumContracts()
+	callOpts, err := s.eth.GetCallOpts(ctx, s.account)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := c.ALCA().BalanceOf(callOpts, s.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: balance %v, amount %v", ErrInsufficientALCA, balance, amount)
+	}
+	allowance, err := c.ALCA().Allowance(callOpts, s.account.Address, c.PublicStakingAddress())
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) < 0 {
+		s.logger.Infof("Approving %v ALCA to PublicStaking", amount)
+		_, err = s.send(ctx, "ALCA.approve", func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.ALCA().Approve(txOpts, c.PublicStakingAddress(), amount)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Infof("Minting a PublicStaking position with %v ALCA", amount)
+	receipt, err := s.send(ctx, "PublicStaking.mint", func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.PublicStaking().Mint(txOpts, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.receivedPosition(receipt)
+}
+
+// Register registers validator in the ValidatorPool with the PublicStaking
+// position tokenID, and waits until the ValidatorJoined event is finalized.
+// The account has to own the AliceNetFactory. A position owned by the account
+// is handed to the factory first.
+func (s *Staker) Register(ctx context.Context, validator common.Address, tokenID *big.Int) error {
+	c := s.contracts.EthereumContracts()
+	callOpts, err := s.eth.GetCallOpts(ctx, s.account)
+	if err != nil {
+		return err
+	}
+	if err := s.checkFactoryOwner(callOpts); err != nil {
+		return err
+	}
+	isValidator, err := c.ValidatorPool().IsValidator(callOpts, validator)
+	if err != nil {
+		return err
+	}
+	if isValidator {
+		return ErrValidatorAlreadyExist
+	}
+	stakeAmount, err := c.ValidatorPool().GetStakeAmount(callOpts)
+	if err != nil {
+		return err
+	}
+	position, err := c.PublicStaking().GetPosition(callOpts, tokenID)
+	if err != nil {
+		return err
+	}
+	if position.Shares.Cmp(stakeAmount) < 0 {
+		return fmt.Errorf("%w: shares %v, stake amount %v", ErrInsufficientStake, position.Shares, stakeAmount)
+	}
+
+	owner, err := c.PublicStaking().OwnerOf(callOpts, tokenID)
+	if err != nil {
+		return err
+	}
+	factory := c.ContractFactoryAddress()
+	switch owner {
+	case factory:
+	case s.account.Address:
+		s.logger.Infof("Transferring position %v to the factory", tokenID)
+		_, err = s.send(ctx, "PublicStaking.transferFrom", func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.PublicStaking().TransferFrom(txOpts, s.account.Address, factory, tokenID)
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: owner %v", ErrNotPositionOwner, owner.Hex())
+	}
+
+	s.logger.Infof("Approving position %v to the ValidatorPool", tokenID)
+	approve, err := packCall(bindings.PublicStakingMetaData.ABI, "approve", c.ValidatorPoolAddress(), tokenID)
+	if err != nil {
+		return err
+	}
+	_, err = s.callAny(ctx, "PublicStaking.approve", c.PublicStakingAddress(), approve)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Infof("Registering %v with position %v", validator.Hex(), tokenID)
+	register, err := packCall(
+		bindings.ValidatorPoolMetaData.ABI,
+		"registerValidators",
+		[]common.Address{validator},
+		[]*big.Int{tokenID},
+	)
+	if err != nil {
+		return err
+	}
+	receipt, err := s.callAny(ctx, "ValidatorPool.registerValidators", c.ValidatorPoolAddress(), register)
+	if err != nil {
+		return err
+	}
+	return s.waitForMembership(ctx, validator, receipt.BlockNumber.Uint64(), true)
+}
+
+// Unregister removes validator from the ValidatorPool and waits until the
+// ValidatorLeft event is finalized. The account has to own the
+// AliceNetFactory.
+func (s *Staker) Unregister(ctx context.Context, validator common.Address) error {
+	c := s.contracts.EthereumContracts()
+	callOpts, err := s.eth.GetCallOpts(ctx, s.account)
+	if err != nil {
+		return err
+	}
+	if err := s.checkFactoryOwner(callOpts); err != nil {
+		return err
+	}
+	isValidator, err := c.ValidatorPool().IsValidator(callOpts, validator)
+	if err != nil {
+		return err
+	}
+	if !isValidator {
+		return ErrNotValidator
+	}
+
+	s.logger.Infof("Unregistering %v", validator.Hex())
+	unregister, err := packCall(bindings.ValidatorPoolMetaData.ABI, "unregisterValidators", []common.Address{validator})
+	if err != nil {
+		return err
+	}
+	receipt, err := s.callAny(ctx, "ValidatorPool.unregisterValidators", c.ValidatorPoolAddress(), unregister)
+	if err != nil {
+		return err
+	}
+	return s.waitForMembership(ctx, validator, receipt.BlockNumber.Uint64(), false)
+}
+
+// Claim collects the profits of the account if it's a validator, or claims
+// back its PublicStaking position if it's in the exiting queue.
+func (s *Staker) Claim(ctx context.Context) (*ClaimResult, error) {
+	c := s.contracts.EthereumContracts()
+	callOpts, err := s.eth.GetCallOpts(ctx, s.account)
+	if err != nil {
+		return nil, err
+	}
+	exiting, err := c.ValidatorPool().IsInExitingQueue(callOpts, s.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if exiting {
+		s.logger.Info("Claiming the exiting position")
+		receipt, err := s.send(ctx, "ValidatorPool.claimExitingNFTPosition", func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.ValidatorPool().ClaimExitingNFTPosition(txOpts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		tokenID, err := s.receivedPosition(receipt)
+		if err != nil {
+			return nil, err
+		}
+		return &ClaimResult{ExitingPosition: true, TokenID: tokenID}, nil
+	}
+
+	isValidator, err := c.ValidatorPool().IsValidator(callOpts, s.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !isValidator {
+		return nil, ErrNothingToClaim
+	}
+	_, _, tokenID, err := c.ValidatorPool().TryGetTokenID(callOpts, s.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	profits, err := c.ValidatorStaking().EstimateAllProfits(callOpts, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Collecting profits of position %v", tokenID)
+	_, err = s.send(ctx, "ValidatorPool.collectProfits", func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.ValidatorPool().CollectProfits(txOpts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimResult{TokenID: tokenID, PayoutEth: profits.PayoutEth, PayoutToken: profits.PayoutToken}, nil
+}
+
+// Status returns the status of the account in the ValidatorPool and its
+// PublicStaking positions.
+func (s *Staker) Status(ctx context.Context) (*Status, error) {
+	c := s.contracts.EthereumContracts()
+	callOpts, err := s.eth.GetCallOpts(ctx, s.account)
+	if err != nil {
+		return nil, err
+	}
+	address := s.account.Address
+	status := &Status{Account: address}
+	if status.EthBalance, err = s.eth.GetBalance(address); err != nil {
+		return nil, err
+	}
+	if status.ALCABalance, err = c.ALCA().BalanceOf(callOpts, address); err != nil {
+		return nil, err
+	}
+	pool := c.ValidatorPool()
+	if status.StakeAmount, err = pool.GetStakeAmount(callOpts); err != nil {
+		return nil, err
+	}
+	if status.IsValidator, err = pool.IsValidator(callOpts, address); err != nil {
+		return nil, err
+	}
+	if status.IsAccusable, err = pool.IsAccusable(callOpts, address); err != nil {
+		return nil, err
+	}
+	if status.IsInExitingQueue, err = pool.IsInExitingQueue(callOpts, address); err != nil {
+		return nil, err
+	}
+	if status.ConsensusRunning, err = pool.IsConsensusRunning(callOpts); err != nil {
+		return nil, err
+	}
+	if status.MaintenanceScheduled, err = pool.IsMaintenanceScheduled(callOpts); err != nil {
+		return nil, err
+	}
+	if status.IsValidator || status.IsInExitingQueue {
+		_, _, status.TokenID, err = pool.TryGetTokenID(callOpts, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if status.IsValidator {
+		profits, err := c.ValidatorStaking().EstimateAllProfits(callOpts, status.TokenID)
+		if err != nil {
+			return nil, err
+		}
+		status.PendingEth, status.PendingToken = profits.PayoutEth, profits.PayoutToken
+	}
+
+	count, err := c.PublicStaking().BalanceOf(callOpts, address)
+	if err != nil {
+		return nil, err
+	}
+	for i := int64(0); i < count.Int64(); i++ {
+		tokenID, err := c.PublicStaking().TokenOfOwnerByIndex(callOpts, address, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		position, err := c.PublicStaking().GetPosition(callOpts, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		profits, err := c.PublicStaking().EstimateAllProfits(callOpts, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		status.Positions = append(status.Positions, Position{
+			TokenID:           tokenID,
+			Shares:            position.Shares,
+			FreeAfter:         position.FreeAfter,
+			WithdrawFreeAfter: position.WithdrawFreeAfter,
+			PendingEth:        profits.PayoutEth,
+			PendingToken:      profits.PayoutToken,
+		})
+	}
+	return status, nil
+}
+
+// checkFactoryOwner returns ErrNotFactoryOwner if the account does not own the
+// AliceNetFactory.
+func (s *Staker) checkFactoryOwner(callOpts *bind.CallOpts) error {
+	owner, err := s.contracts.EthereumContracts().ContractFactory().Owner(callOpts)
+	if err != nil {
+		return err
+	}
+	if owner != s.account.Address {
+		return fmt.Errorf("%w: owner %v", ErrNotFactoryOwner, owner.Hex())
+	}
+	return nil
+}
+
+// callAny sends a call to target through the AliceNetFactory.
+func (s *Staker) callAny(ctx context.Context, name string, target common.Address, input []byte) (*types.Receipt, error) {
+	return s.send(ctx, name, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.contracts.EthereumContracts().ContractFactory().CallAny(txOpts, target, big.NewInt(0), input)
+	})
+}
+
+// send a transaction created by fn from the account and wait for its receipt.
+func (s *Staker) send(
+	ctx context.Context,
+	name string,
+	fn func(txOpts *bind.TransactOpts) (*types.Transaction, error),
+) (*types.Receipt, error) {
+	txOpts, err := s.eth.GetTransactionOpts(ctx, s.account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction options for %v: %w", name, err)
+	}
+	txn, err := fn(txOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %v: %w", name, err)
+	}
+	s.logger.WithField("Txn", txn.Hash().Hex()).Debugf("Waiting for %v receipt", name)
+	receipt, err := s.watcher.SubscribeAndWait(ctx, txn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt of %v: %w", name, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %v %v", ErrTransactionReverted, name, receipt.TxHash.Hex())
+	}
+	return receipt, nil
+}
+
+// receivedPosition returns the PublicStaking position transferred to the
+// account in the receipt.
+func (s *Staker) receivedPosition(receipt *types.Receipt) (*big.Int, error) {
+	c := s.contracts.EthereumContracts()
+	transferEvent := events.GetPublicStakingEvents()["Transfer"]
+	for _, log := range receipt.Logs {
+		if log.Address != c.PublicStakingAddress() || len(log.Topics) == 0 || log.Topics[0] != transferEvent.ID {
+			continue
+		}
+		transfer, err := c.PublicStaking().ParseTransfer(*log)
+		if err != nil {
+			return nil, err
+		}
+		if transfer.To == s.account.Address {
+			return transfer.TokenId, nil
+		}
+	}
+	return nil, ErrPositionNotFound
+}
+
+// waitForMembership follows the ValidatorPool events starting at fromBlock,
+// with the same processing the monitor does, until validator joins or leaves
+// the pool.
+func (s *Staker) waitForMembership(ctx context.Context, validator common.Address, fromBlock uint64, joined bool) error {
+	c := s.contracts.EthereumContracts()
+	vpEvents := events.GetValidatorPoolEvents()
+	joinedEvent, leftEvent := vpEvents["ValidatorJoined"], vpEvents["ValidatorLeft"]
+
+	state := objects.NewMonitorState()
+	if !joined {
+		state.PotentialValidators[validator] = objects.PotentialValidator{Account: validator}
+	}
+	for {
+		height, err := s.eth.GetFinalizedHeight(ctx)
+		if err != nil {
+			return err
+		}
+		if height >= fromBlock {
+			logs, err := s.eth.GetEvents(ctx, fromBlock, height, []common.Address{c.ValidatorPoolAddress()})
+			if err != nil {
+				return err
+			}
+			for _, log := range logs {
+				if len(log.Topics) < 2 || common.BytesToAddress(log.Topics[1].Bytes()) != validator {
+					continue
+				}
+				switch log.Topics[0] {
+				case joinedEvent.ID:
+					err = events.ProcessValidatorJoined(s.eth, s.contracts, s.logger, state, log)
+				case leftEvent.ID:
+					err = events.ProcessValidatorLeft(s.eth, s.contracts, s.logger, state, log)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			fromBlock = height + 1
+		}
+		if _, present := state.PotentialValidators[validator]; present == joined {
+			return nil
+		}
+
+		s.logger.Debugf("Waiting for %v to be finalized", validator.Hex())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// packCall encodes a call to method of the contract with the given abi.
+func packCall(contractABI string, method string, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack(method, args...)
+}
@@ -0,0 +1,175 @@
+package staking
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+var (
+	publicStakingAddress = common.HexToAddress("0x0a")
+	validatorPoolAddress = common.HexToAddress("0x0b")
+	factoryAddress       = common.HexToAddress("0x0c")
+)
+
+type testStaker struct {
+	*Staker
+	eth           *mocks.MockClient
+	factory       *mocks.MockIAliceNetFactory
+	publicStaking *mocks.MockIPublicStaking
+	pool          *mocks.MockIValidatorPool
+	watcher       *mocks.MockWatcher
+}
+
+func setupStaker(t *testing.T) *testStaker {
+	eth := mocks.NewMockClient()
+	eth.GetBalanceFunc.SetDefaultReturn(big.NewInt(0), nil)
+
+	factory := mocks.NewMockIAliceNetFactory()
+	publicStaking := mocks.NewMockIPublicStaking()
+	pool := mocks.NewMockIValidatorPool()
+	validatorStaking := mocks.NewMockIValidatorStaking()
+	txn := types.NewTx(&types.LegacyTx{})
+	factory.CallAnyFunc.SetDefaultReturn(txn, nil)
+	pool.ClaimExitingNFTPositionFunc.SetDefaultReturn(txn, nil)
+	pool.CollectProfitsFunc.SetDefaultReturn(txn, nil)
+	alca := mocks.NewMockIALCA()
+	alca.BalanceOfFunc.SetDefaultReturn(big.NewInt(0), nil)
+
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.ContractFactoryFunc.SetDefaultReturn(factory)
+	ethereumContracts.ContractFactoryAddressFunc.SetDefaultReturn(factoryAddress)
+	ethereumContracts.PublicStakingFunc.SetDefaultReturn(publicStaking)
+	ethereumContracts.PublicStakingAddressFunc.SetDefaultReturn(publicStakingAddress)
+	ethereumContracts.ValidatorPoolFunc.SetDefaultReturn(pool)
+	ethereumContracts.ValidatorPoolAddressFunc.SetDefaultReturn(validatorPoolAddress)
+	ethereumContracts.ValidatorStakingFunc.SetDefaultReturn(validatorStaking)
+	ethereumContracts.ALCAFunc.SetDefaultReturn(alca)
+	contracts := mocks.NewMockAllSmartContracts()
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	watcher := mocks.NewMockWatcher()
+	watcher.SubscribeAndWaitFunc.SetDefaultReturn(&types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}, nil)
+
+	account := accounts.Account{Address: common.HexToAddress("0x01")}
+	staker := NewStaker(eth, contracts, watcher, account, mocks.NewMockLogger().WithField("test", t.Name()))
+	staker.pollInterval = time.Millisecond
+	return &testStaker{
+		Staker:        staker,
+		eth:           eth,
+		factory:       factory,
+		publicStaking: publicStaking,
+		pool:          pool,
+		watcher:       watcher,
+	}
+}
+
+func TestStaker_RegisterNotFactoryOwner(t *testing.T) {
+	s := setupStaker(t)
+	s.factory.OwnerFunc.SetDefaultReturn(common.HexToAddress("0x02"), nil)
+
+	err := s.Register(context.Background(), s.account.Address, big.NewInt(1))
+	require.ErrorIs(t, err, ErrNotFactoryOwner)
+	assert.Equal(t, 0, len(s.factory.CallAnyFunc.History()))
+}
+
+func TestStaker_RegisterPositionOfAnotherAccount(t *testing.T) {
+	s := setupStaker(t)
+	s.factory.OwnerFunc.SetDefaultReturn(s.account.Address, nil)
+	s.pool.GetStakeAmountFunc.SetDefaultReturn(big.NewInt(100), nil)
+	s.publicStaking.GetPositionFunc.SetDefaultReturn(struct {
+		Shares            *big.Int
+		FreeAfter         *big.Int
+		WithdrawFreeAfter *big.Int
+		AccumulatorEth    *big.Int
+		AccumulatorToken  *big.Int
+	}{Shares: big.NewInt(100)}, nil)
+	s.publicStaking.OwnerOfFunc.SetDefaultReturn(common.HexToAddress("0x02"), nil)
+
+	err := s.Register(context.Background(), s.account.Address, big.NewInt(1))
+	require.ErrorIs(t, err, ErrNotPositionOwner)
+	assert.Equal(t, 0, len(s.factory.CallAnyFunc.History()))
+}
+
+func TestStaker_UnregisterWaitsForValidatorLeft(t *testing.T) {
+	s := setupStaker(t)
+	s.factory.OwnerFunc.SetDefaultReturn(s.account.Address, nil)
+	s.pool.IsValidatorFunc.SetDefaultReturn(true, nil)
+	s.pool.ParseValidatorLeftFunc.SetDefaultReturn(&bindings.ValidatorPoolValidatorLeft{
+		Account:              s.account.Address,
+		PublicStakingTokenID: big.NewInt(1),
+	}, nil)
+
+	leftEvent := events.GetValidatorPoolEvents()["ValidatorLeft"]
+	log := types.Log{
+		Address: validatorPoolAddress,
+		Topics:  []common.Hash{leftEvent.ID, common.BytesToHash(s.account.Address.Bytes())},
+	}
+	// the event is only returned once the block is finalized
+	s.eth.GetFinalizedHeightFunc.PushReturn(9, nil)
+	s.eth.GetFinalizedHeightFunc.SetDefaultReturn(10, nil)
+	s.eth.GetEventsFunc.SetDefaultReturn([]types.Log{log}, nil)
+
+	err := s.Unregister(context.Background(), s.account.Address)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(s.factory.CallAnyFunc.History()))
+	assert.Equal(t, validatorPoolAddress, s.factory.CallAnyFunc.History()[0].Arg1)
+	require.Equal(t, 1, len(s.eth.GetEventsFunc.History()))
+	assert.Equal(t, uint64(10), s.eth.GetEventsFunc.History()[0].Arg1)
+}
+
+func TestStaker_ClaimExitingPosition(t *testing.T) {
+	s := setupStaker(t)
+	s.pool.IsInExitingQueueFunc.SetDefaultReturn(true, nil)
+	transferEvent := events.GetPublicStakingEvents()["Transfer"]
+	s.watcher.SubscribeAndWaitFunc.SetDefaultReturn(&types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs:   []*types.Log{{Address: publicStakingAddress, Topics: []common.Hash{transferEvent.ID}}},
+	}, nil)
+	s.publicStaking.ParseTransferFunc.SetDefaultReturn(&bindings.PublicStakingTransfer{
+		From:    validatorPoolAddress,
+		To:      s.account.Address,
+		TokenId: big.NewInt(7),
+	}, nil)
+
+	result, err := s.Claim(context.Background())
+	require.Nil(t, err)
+	assert.True(t, result.ExitingPosition)
+	assert.Equal(t, big.NewInt(7), result.TokenID)
+	assert.Equal(t, 1, len(s.pool.ClaimExitingNFTPositionFunc.History()))
+	assert.Equal(t, 0, len(s.pool.CollectProfitsFunc.History()))
+}
+
+func TestStaker_ClaimNothing(t *testing.T) {
+	s := setupStaker(t)
+
+	_, err := s.Claim(context.Background())
+	require.ErrorIs(t, err, ErrNothingToClaim)
+	assert.Equal(t, 0, len(s.watcher.SubscribeAndWaitFunc.History()))
+}
+
+func TestStaker_Reverted(t *testing.T) {
+	s := setupStaker(t)
+	s.pool.IsValidatorFunc.SetDefaultReturn(true, nil)
+	s.watcher.SubscribeAndWaitFunc.SetDefaultReturn(&types.Receipt{Status: types.ReceiptStatusFailed}, nil)
+	validatorStaking := s.contracts.EthereumContracts().ValidatorStaking().(*mocks.MockIValidatorStaking)
+	validatorStaking.EstimateAllProfitsFunc.SetDefaultReturn(struct {
+		PayoutEth   *big.Int
+		PayoutToken *big.Int
+	}{big.NewInt(1), big.NewInt(2)}, nil)
+
+	_, err := s.Claim(context.Background())
+	require.ErrorIs(t, err, ErrTransactionReverted)
+	assert.Equal(t, 1, len(s.pool.CollectProfitsFunc.History()))
+}
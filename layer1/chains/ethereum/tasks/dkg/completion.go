@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/alicenet/alicenet/utils"
@@ -20,14 +21,20 @@ type CompletionTask struct {
 	StartBlockHash common.Hash `json:"startBlockHash"`
 }
 
-// asserting that CompletionTask struct implements interface tasks.Task.
-var _ tasks.Task = &CompletionTask{}
+// asserting that CompletionTask struct implements the interfaces tasks.Task and
+// tasks.ConditionalTask.
+var (
+	_ tasks.Task            = &CompletionTask{}
+	_ tasks.ConditionalTask = &CompletionTask{}
+)
 
 // NewCompletionTask creates a background task that attempts to call Complete on ethdkg.
 func NewCompletionTask(start, end uint64) *CompletionTask {
-	return &CompletionTask{
+	task := &CompletionTask{
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the CompletionTask.
@@ -135,3 +142,8 @@ func (t *CompletionTask) ShouldExecute(ctx context.Context) (bool, *tasks.TaskEr
 
 	return true, nil
 }
+
+// CanStart checks that ETHDKG is in the dispute gpkj submission or the completion phase on-chain.
+func (t *CompletionTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.DisputeGPKJSubmission, state.Completion)
+}
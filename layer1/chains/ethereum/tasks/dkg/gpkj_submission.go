@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	monInterfaces "github.com/alicenet/alicenet/layer1/monitor/interfaces"
@@ -18,18 +19,25 @@ type GPKjSubmissionTask struct {
 	adminHandler monInterfaces.AdminHandler
 }
 
-// asserting that GPKjSubmissionTask struct implements interface Task.
-var _ tasks.Task = &GPKjSubmissionTask{}
+// asserting that GPKjSubmissionTask struct implements the interfaces tasks.Task,
+// tasks.ConditionalTask and tasks.GiveUpHandler.
+var (
+	_ tasks.Task            = &GPKjSubmissionTask{}
+	_ tasks.ConditionalTask = &GPKjSubmissionTask{}
+	_ tasks.GiveUpHandler   = &GPKjSubmissionTask{}
+)
 
 // NewGPKjSubmissionTask creates a background task that attempts to submit the gpkj in ETHDKG.
 func NewGPKjSubmissionTask(
 	start, end uint64,
 	adminHandler monInterfaces.AdminHandler,
 ) *GPKjSubmissionTask {
-	return &GPKjSubmissionTask{
+	task := &GPKjSubmissionTask{
 		BaseTask:     tasks.NewBaseTask(start, end, false, nil),
 		adminHandler: adminHandler,
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the GPKjSubmissionTask.
@@ -168,3 +176,13 @@ func (t *GPKjSubmissionTask) ShouldExecute(ctx context.Context) (bool, *tasks.Ta
 func (t *GPKjSubmissionTask) SetAdminHandler(adminHandler monInterfaces.AdminHandler) {
 	t.adminHandler = adminHandler
 }
+
+// CanStart checks that ETHDKG is in the gpkj submission phase on-chain.
+func (t *GPKjSubmissionTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.GPKJSubmission)
+}
+
+// GiveUp logs that the task failed for good.
+func (t *GPKjSubmissionTask) GiveUp(err error) {
+	giveUpPhase(t.GetLogger(), state.GPKJSubmission, err)
+}
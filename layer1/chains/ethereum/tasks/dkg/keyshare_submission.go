@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,14 +16,21 @@ type KeyShareSubmissionTask struct {
 	*tasks.BaseTask
 }
 
-// asserting that KeyShareSubmissionTask struct implements interface tasks.Task.
-var _ tasks.Task = &KeyShareSubmissionTask{}
+// asserting that KeyShareSubmissionTask struct implements the interfaces tasks.Task,
+// tasks.ConditionalTask and tasks.GiveUpHandler.
+var (
+	_ tasks.Task            = &KeyShareSubmissionTask{}
+	_ tasks.ConditionalTask = &KeyShareSubmissionTask{}
+	_ tasks.GiveUpHandler   = &KeyShareSubmissionTask{}
+)
 
 // NewKeyShareSubmissionTask creates a new task.
 func NewKeyShareSubmissionTask(start, end uint64) *KeyShareSubmissionTask {
-	return &KeyShareSubmissionTask{
+	task := &KeyShareSubmissionTask{
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the KeyShareSubmissionTask.
@@ -126,18 +134,8 @@ func (t *KeyShareSubmissionTask) ShouldExecute(ctx context.Context) (bool, *task
 		return false, tasks.NewTaskErr(fmt.Sprintf(tasks.FailedGettingCallOpts, err), true)
 	}
 
-	phase, err := t.GetContractsHandler().EthereumContracts().Ethdkg().GetETHDKGPhase(callOpts)
-	if err != nil {
-		return false, tasks.NewTaskErr(fmt.Sprintf("error getting ETHDKGPhase: %v", err), true)
-	}
-
-	// DisputeShareDistribution || KeyShareSubmission
-	if phase != uint8(state.DisputeShareDistribution) && phase != uint8(state.KeyShareSubmission) {
-		logger.Debugf("on dispute ShareDistribution phase should not submit keyShare")
-		return false, nil
-	}
-
-	// Check the key share submission status
+	// The phase is checked on-chain by CanStart, check the key share submission
+	// status
 	status, err := state.CheckKeyShare(
 		ctx,
 		t.GetContractsHandler().EthereumContracts().Ethdkg(),
@@ -157,3 +155,13 @@ func (t *KeyShareSubmissionTask) ShouldExecute(ctx context.Context) (bool, *task
 
 	return true, nil
 }
+
+// CanStart checks that ETHDKG is in the dispute share distribution or the key share submission phase on-chain.
+func (t *KeyShareSubmissionTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.DisputeShareDistribution, state.KeyShareSubmission)
+}
+
+// GiveUp logs that the task failed for good.
+func (t *KeyShareSubmissionTask) GiveUp(err error) {
+	giveUpPhase(t.GetLogger(), state.KeyShareSubmission, err)
+}
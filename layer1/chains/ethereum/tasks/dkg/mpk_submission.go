@@ -9,6 +9,7 @@ import (
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
 	"github.com/alicenet/alicenet/crypto/bn256"
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/alicenet/alicenet/utils"
@@ -23,14 +24,21 @@ type MPKSubmissionTask struct {
 	StartBlockHash common.Hash `json:"startBlockHash"`
 }
 
-// asserting that MPKSubmissionTask struct implements interface tasks.Task.
-var _ tasks.Task = &MPKSubmissionTask{}
+// asserting that MPKSubmissionTask struct implements the interfaces tasks.Task,
+// tasks.ConditionalTask and tasks.GiveUpHandler.
+var (
+	_ tasks.Task            = &MPKSubmissionTask{}
+	_ tasks.ConditionalTask = &MPKSubmissionTask{}
+	_ tasks.GiveUpHandler   = &MPKSubmissionTask{}
+)
 
 // NewMPKSubmissionTask creates a new task.
 func NewMPKSubmissionTask(start, end uint64) *MPKSubmissionTask {
-	return &MPKSubmissionTask{
+	task := &MPKSubmissionTask{
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the MPKSubmissionTask
@@ -259,3 +267,13 @@ func isMasterPublicKeyEmpty(masterPublicKey [4]*big.Int) bool {
 		masterPublicKey[2].Cmp(big.NewInt(0)) == 0 &&
 		masterPublicKey[3].Cmp(big.NewInt(0)) == 0)
 }
+
+// CanStart checks that ETHDKG is in the master public key submission phase on-chain.
+func (t *MPKSubmissionTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.MPKSubmission)
+}
+
+// GiveUp logs that the task failed for good.
+func (t *MPKSubmissionTask) GiveUp(err error) {
+	giveUpPhase(t.GetLogger(), state.MPKSubmission, err)
+}
@@ -0,0 +1,45 @@
+package dkg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
+	"github.com/alicenet/alicenet/layer1/executor/tasks"
+	"github.com/sirupsen/logrus"
+)
+
+// newPhaseRetryPolicy returns how the task submitting the data of the
+// validator for an ETHDKG phase is run again when it fails.
+func newPhaseRetryPolicy() *tasks.RetryPolicy {
+	return &tasks.RetryPolicy{
+		MaxAttempts:    constants.ETHDKGTaskMaxAttempts,
+		InitialBackoff: constants.ETHDKGTaskRetryBackoff,
+		MaxBackoff:     constants.ETHDKGTaskRetryBackoff,
+	}
+}
+
+// inPhase checks if the ETHDKG contract is in one of the phases.
+func inPhase(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts, phases ...state.EthDKGPhase) (bool, *tasks.TaskErr) {
+	callOpts, err := eth.GetCallOpts(ctx, eth.GetDefaultAccount())
+	if err != nil {
+		return false, tasks.NewTaskErr(fmt.Sprintf(tasks.FailedGettingCallOpts, err), true)
+	}
+	phase, err := contracts.EthereumContracts().Ethdkg().GetETHDKGPhase(callOpts)
+	if err != nil {
+		return false, tasks.NewTaskErr(fmt.Sprintf("error getting ETHDKGPhase: %v", err), true)
+	}
+	for _, p := range phases {
+		if phase == uint8(p) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// giveUpPhase logs that the validator could not take part in an ETHDKG phase.
+func giveUpPhase(logger *logrus.Entry, phase state.EthDKGPhase, err error) {
+	logger.WithError(err).Errorf("giving up on phase %v, the validator may be accused of not taking part in it", phase)
+}
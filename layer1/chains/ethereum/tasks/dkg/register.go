@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/utils"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
@@ -16,14 +17,21 @@ type RegisterTask struct {
 	*tasks.BaseTask
 }
 
-// asserting that RegisterTask struct implements interface tasks.Task.
-var _ tasks.Task = &RegisterTask{}
+// asserting that RegisterTask struct implements the interfaces tasks.Task,
+// tasks.ConditionalTask and tasks.GiveUpHandler.
+var (
+	_ tasks.Task            = &RegisterTask{}
+	_ tasks.ConditionalTask = &RegisterTask{}
+	_ tasks.GiveUpHandler   = &RegisterTask{}
+)
 
 // NewRegisterTask creates a background task that attempts to register with ETHDKG.
 func NewRegisterTask(start, end uint64) *RegisterTask {
-	return &RegisterTask{
+	task := &RegisterTask{
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the RegisterTask
@@ -146,3 +154,13 @@ func (t *RegisterTask) ShouldExecute(ctx context.Context) (bool, *tasks.TaskErr)
 
 	return true, nil
 }
+
+// CanStart checks that ETHDKG is in the registration phase on-chain.
+func (t *RegisterTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.RegistrationOpen)
+}
+
+// GiveUp logs that the task failed for good.
+func (t *RegisterTask) GiveUp(err error) {
+	giveUpPhase(t.GetLogger(), state.RegistrationOpen, err)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,14 +16,21 @@ type ShareDistributionTask struct {
 	*tasks.BaseTask
 }
 
-// asserting that ShareDistributionTask struct implements interface tasks.Task.
-var _ tasks.Task = &ShareDistributionTask{}
+// asserting that ShareDistributionTask struct implements the interfaces tasks.Task,
+// tasks.ConditionalTask and tasks.GiveUpHandler.
+var (
+	_ tasks.Task            = &ShareDistributionTask{}
+	_ tasks.ConditionalTask = &ShareDistributionTask{}
+	_ tasks.GiveUpHandler   = &ShareDistributionTask{}
+)
 
 // NewShareDistributionTask creates a new task.
 func NewShareDistributionTask(start, end uint64) *ShareDistributionTask {
-	return &ShareDistributionTask{
+	task := &ShareDistributionTask{
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 	}
+	task.SetRetryPolicy(newPhaseRetryPolicy())
+	return task
 }
 
 // Prepare prepares for work to be done in the ShareDistributionTask.
@@ -152,3 +160,13 @@ func (t *ShareDistributionTask) ShouldExecute(ctx context.Context) (bool, *tasks
 	logger.Debugf("could not confirm if shares were distributed")
 	return true, nil
 }
+
+// CanStart checks that ETHDKG is in the share distribution phase on-chain.
+func (t *ShareDistributionTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	return inPhase(ctx, eth, contracts, state.ShareDistribution)
+}
+
+// GiveUp logs that the task failed for good.
+func (t *ShareDistributionTask) GiveUp(err error) {
+	giveUpPhase(t.GetLogger(), state.ShareDistribution, err)
+}
@@ -0,0 +1,750 @@
+package simulator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/crypto/bn256"
+	"github.com/alicenet/alicenet/crypto/bn256/cloudflare"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
+)
+
+var (
+	ErrWrongPhase        = errors.New("ethdkg is not in the expected phase")
+	ErrNotValidator      = errors.New("sender is not a validator")
+	ErrNotParticipant    = errors.New("sender is not a participant of the current round")
+	ErrAlreadySubmitted  = errors.New("participant already submitted")
+	ErrCeremonyHalted    = errors.New("ceremony was halted by an accusation")
+	ErrInvalidSubmission = errors.New("invalid submission")
+	ErrInvalidAccusation = errors.New("invalid accusation")
+)
+
+// ContractAddress is the address the simulated ETHDKG logs are emitted from.
+var ContractAddress = common.HexToAddress("0x0000000000000000000000000000000000e7dc90")
+
+// AccusationKind identifies the dispute that led to an accusation.
+type AccusationKind int
+
+// The possible accusations, one per dispute task.
+const (
+	NotRegistered AccusationKind = iota
+	DidNotDistributeShares
+	DistributedBadShares
+	DidNotSubmitKeyShares
+	DidNotSubmitGPKj
+	SubmittedBadGPKj
+)
+
+func (kind AccusationKind) String() string {
+	return [...]string{
+		"NotRegistered",
+		"DidNotDistributeShares",
+		"DistributedBadShares",
+		"DidNotSubmitKeyShares",
+		"DidNotSubmitGPKj",
+		"SubmittedBadGPKj",
+	}[kind]
+}
+
+// Accusation is an accusation accepted by the simulated contract.
+type Accusation struct {
+	Kind    AccusationKind
+	Accuser common.Address
+	Accused common.Address
+	Height  uint64
+}
+
+type participantRecord struct {
+	bindings.Participant
+	address         common.Address
+	encryptedShares []*big.Int
+	commitments     [][2]*big.Int
+	keyShareG2      [4]*big.Int
+}
+
+func newParticipantRecord(address common.Address) *participantRecord {
+	return &participantRecord{
+		address: address,
+		Participant: bindings.Participant{
+			PublicKey:                   [2]*big.Int{big.NewInt(0), big.NewInt(0)},
+			CommitmentsFirstCoefficient: [2]*big.Int{big.NewInt(0), big.NewInt(0)},
+			KeyShares:                   [2]*big.Int{big.NewInt(0), big.NewInt(0)},
+			Gpkj:                        [4]*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+		},
+	}
+}
+
+func (p *participantRecord) distributed() bool {
+	return p.DistributedSharesHash != [32]byte{}
+}
+
+func (p *participantRecord) submittedKeyShare() bool {
+	return p.KeyShares[0].Sign() != 0 || p.KeyShares[1].Sign() != 0
+}
+
+func (p *participantRecord) submittedGPKj() bool {
+	return p.Gpkj[0].Sign() != 0 || p.Gpkj[1].Sign() != 0 || p.Gpkj[2].Sign() != 0 || p.Gpkj[3].Sign() != 0
+}
+
+// contract is an in-memory implementation of the ETHDKG contract. It keeps
+// the participants state, enforces the phase windows, verifies accusations
+// and emits the same logs as the deployed contract.
+type contract struct {
+	sync.Mutex
+	height             func() uint64
+	events             map[string]abi.Event
+	phaseLength        uint64
+	confirmationLength uint64
+
+	nonce        uint64
+	phase        state.EthDKGPhase
+	phaseStart   uint64
+	validators   map[common.Address]bool
+	participants map[common.Address]*participantRecord
+	registered   int
+	mpk          [4]*big.Int
+	halted       bool
+	accused      map[common.Address]bool
+	accusations  []Accusation
+	logs         []types.Log
+	pending      []types.Log
+}
+
+func newContract(height func() uint64, phaseLength, confirmationLength uint64) *contract {
+	return &contract{
+		height:             height,
+		events:             events.GetETHDKGEvents(),
+		phaseLength:        phaseLength,
+		confirmationLength: confirmationLength,
+		mpk:                [4]*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+	}
+}
+
+// emit packs an event the way the solidity contract does and queues it for
+// delivery at the next block.
+func (c *contract) emit(name string, args ...interface{}) error {
+	event, ok := c.events[name]
+	if !ok {
+		return fmt.Errorf("unknown ETHDKG event %v", name)
+	}
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %v: %v", name, err)
+	}
+	height := c.height()
+	log := types.Log{
+		Address:     ContractAddress,
+		Topics:      []common.Hash{event.ID},
+		Data:        data,
+		BlockNumber: height,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(uint64(len(c.logs) + len(c.pending) + 1))),
+		Index:       uint(len(c.pending)),
+	}
+	c.pending = append(c.pending, log)
+	return nil
+}
+
+// takeLogs returns the logs emitted since the last call.
+func (c *contract) takeLogs() []types.Log {
+	c.Lock()
+	defer c.Unlock()
+	logs := c.pending
+	c.pending = nil
+	c.logs = append(c.logs, logs...)
+	return logs
+}
+
+func (c *contract) inWindow(start uint64) bool {
+	height := c.height()
+	return height >= start && height < start+c.phaseLength
+}
+
+func (c *contract) record(from common.Address) (*participantRecord, error) {
+	if !c.validators[from] {
+		return nil, ErrNotValidator
+	}
+	p, ok := c.participants[from]
+	if !ok || p.Nonce != c.nonce {
+		return nil, ErrNotParticipant
+	}
+	return p, nil
+}
+
+func (c *contract) sortedParticipants() []*participantRecord {
+	list := make([]*participantRecord, 0, len(c.participants))
+	for _, p := range c.participants {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Index < list[j].Index })
+	return list
+}
+
+func (c *contract) countWhere(f func(*participantRecord) bool) int {
+	count := 0
+	for _, p := range c.participants {
+		if f(p) {
+			count++
+		}
+	}
+	return count
+}
+
+// openRegistration starts a new round with the given validators.
+func (c *contract) openRegistration(validators []common.Address) error {
+	c.Lock()
+	defer c.Unlock()
+	c.nonce++
+	c.phase = state.RegistrationOpen
+	c.phaseStart = c.height() + c.confirmationLength
+	c.validators = make(map[common.Address]bool)
+	for _, v := range validators {
+		c.validators[v] = true
+	}
+	c.participants = make(map[common.Address]*participantRecord)
+	c.registered = 0
+	c.halted = false
+	c.accused = make(map[common.Address]bool)
+	return c.emit(
+		"RegistrationOpened",
+		new(big.Int).SetUint64(c.phaseStart),
+		big.NewInt(int64(len(validators))),
+		new(big.Int).SetUint64(c.nonce),
+		new(big.Int).SetUint64(c.phaseLength),
+		new(big.Int).SetUint64(c.confirmationLength),
+	)
+}
+
+func (c *contract) checkSubmission(phase state.EthDKGPhase, start uint64) error {
+	if c.halted {
+		return ErrCeremonyHalted
+	}
+	if c.phase != phase || !c.inWindow(start) {
+		return fmt.Errorf("%w: %v", ErrWrongPhase, phase)
+	}
+	return nil
+}
+
+func (c *contract) register(from common.Address, publicKey [2]*big.Int) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.checkSubmission(state.RegistrationOpen, c.phaseStart); err != nil {
+		return err
+	}
+	if !c.validators[from] {
+		return ErrNotValidator
+	}
+	if _, ok := c.participants[from]; ok {
+		return ErrAlreadySubmitted
+	}
+	if _, err := bn256.BigIntArrayToG1(publicKey); err != nil {
+		return fmt.Errorf("%w: public key: %v", ErrInvalidSubmission, err)
+	}
+	c.registered++
+	p := newParticipantRecord(from)
+	p.PublicKey = publicKey
+	p.Nonce = c.nonce
+	p.Index = uint64(c.registered)
+	p.Phase = uint8(state.RegistrationOpen)
+	c.participants[from] = p
+	err := c.emit(
+		"AddressRegistered",
+		from,
+		new(big.Int).SetUint64(p.Index),
+		new(big.Int).SetUint64(c.nonce),
+		publicKey,
+	)
+	if err != nil {
+		return err
+	}
+	if c.registered < len(c.validators) {
+		return nil
+	}
+	height := c.height()
+	c.phase = state.ShareDistribution
+	c.phaseStart = height + c.confirmationLength
+	return c.emit("RegistrationComplete", new(big.Int).SetUint64(height))
+}
+
+func (c *contract) distributeShares(from common.Address, encryptedShares []*big.Int, commitments [][2]*big.Int) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.checkSubmission(state.ShareDistribution, c.phaseStart); err != nil {
+		return err
+	}
+	p, err := c.record(from)
+	if err != nil {
+		return err
+	}
+	if p.distributed() {
+		return ErrAlreadySubmitted
+	}
+	n := len(c.participants)
+	if len(encryptedShares) != n-1 || len(commitments) != state.ThresholdForUserCount(n)+1 {
+		return fmt.Errorf("%w: wrong number of shares or commitments", ErrInvalidSubmission)
+	}
+	for _, commitment := range commitments {
+		if _, err := bn256.BigIntArrayToG1(commitment); err != nil {
+			return fmt.Errorf("%w: commitment: %v", ErrInvalidSubmission, err)
+		}
+	}
+	hash, _, _, err := state.ComputeDistributedSharesHash(encryptedShares, commitments)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubmission, err)
+	}
+	p.DistributedSharesHash = hash
+	p.CommitmentsFirstCoefficient = commitments[0]
+	p.Phase = uint8(state.ShareDistribution)
+	p.encryptedShares = encryptedShares
+	p.commitments = commitments
+	err = c.emit(
+		"SharesDistributed",
+		from,
+		new(big.Int).SetUint64(p.Index),
+		new(big.Int).SetUint64(c.nonce),
+		encryptedShares,
+		commitments,
+	)
+	if err != nil {
+		return err
+	}
+	if c.countWhere((*participantRecord).distributed) < n {
+		return nil
+	}
+	height := c.height()
+	c.phase = state.DisputeShareDistribution
+	c.phaseStart = height + c.confirmationLength
+	return c.emit("ShareDistributionComplete", new(big.Int).SetUint64(height))
+}
+
+// keyShareStart returns the block the key share submission window opens.
+// The contract only moves to KeyShareSubmission with the first key share, so
+// until then the window is derived from the share dispute phase.
+func (c *contract) keyShareStart() uint64 {
+	if c.phase == state.DisputeShareDistribution {
+		return c.phaseStart + c.phaseLength
+	}
+	return c.phaseStart
+}
+
+func (c *contract) submitKeyShare(from common.Address, keyShareG1, proof [2]*big.Int, keyShareG2 [4]*big.Int) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.halted {
+		return ErrCeremonyHalted
+	}
+	if c.phase == state.DisputeShareDistribution && c.inWindow(c.keyShareStart()) {
+		c.phaseStart = c.keyShareStart()
+		c.phase = state.KeyShareSubmission
+	}
+	if err := c.checkSubmission(state.KeyShareSubmission, c.phaseStart); err != nil {
+		return err
+	}
+	p, err := c.record(from)
+	if err != nil {
+		return err
+	}
+	if !p.distributed() {
+		return ErrNotParticipant
+	}
+	if p.submittedKeyShare() {
+		return ErrAlreadySubmitted
+	}
+	// a single key share pairs correctly exactly when it would add up to a
+	// valid master public key
+	if _, err := state.GenerateMasterPublicKey([][2]*big.Int{keyShareG1}, [][4]*big.Int{keyShareG2}); err != nil {
+		return fmt.Errorf("%w: key share: %v", ErrInvalidSubmission, err)
+	}
+	p.KeyShares = keyShareG1
+	p.Phase = uint8(state.KeyShareSubmission)
+	p.keyShareG2 = keyShareG2
+	err = c.emit(
+		"KeyShareSubmitted",
+		from,
+		new(big.Int).SetUint64(p.Index),
+		new(big.Int).SetUint64(c.nonce),
+		keyShareG1,
+		proof,
+		keyShareG2,
+	)
+	if err != nil {
+		return err
+	}
+	if c.countWhere((*participantRecord).submittedKeyShare) < len(c.participants) {
+		return nil
+	}
+	height := c.height()
+	c.phase = state.MPKSubmission
+	c.phaseStart = height + c.confirmationLength
+	return c.emit("KeyShareSubmissionComplete", new(big.Int).SetUint64(height))
+}
+
+func (c *contract) submitMasterPublicKey(from common.Address, mpk [4]*big.Int) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.checkSubmission(state.MPKSubmission, c.phaseStart); err != nil {
+		return err
+	}
+	if _, err := c.record(from); err != nil {
+		return err
+	}
+	var g1s [][2]*big.Int
+	var g2s [][4]*big.Int
+	for _, p := range c.sortedParticipants() {
+		g1s = append(g1s, p.KeyShares)
+		g2s = append(g2s, p.keyShareG2)
+	}
+	expected, err := state.GenerateMasterPublicKey(g1s, g2s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubmission, err)
+	}
+	for i := range expected {
+		if mpk[i] == nil || expected[i].Cmp(mpk[i]) != 0 {
+			return fmt.Errorf("%w: master public key does not match the key shares", ErrInvalidSubmission)
+		}
+	}
+	height := c.height()
+	c.mpk = mpk
+	c.phase = state.GPKJSubmission
+	c.phaseStart = height
+	return c.emit("MPKSet", new(big.Int).SetUint64(height), new(big.Int).SetUint64(c.nonce), mpk)
+}
+
+func (c *contract) submitGPKj(from common.Address, gpkj [4]*big.Int) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.checkSubmission(state.GPKJSubmission, c.phaseStart); err != nil {
+		return err
+	}
+	p, err := c.record(from)
+	if err != nil {
+		return err
+	}
+	if p.submittedGPKj() {
+		return ErrAlreadySubmitted
+	}
+	if _, err := bn256.BigIntArrayToG2(gpkj); err != nil {
+		return fmt.Errorf("%w: gpkj: %v", ErrInvalidSubmission, err)
+	}
+	p.Gpkj = gpkj
+	p.Phase = uint8(state.GPKJSubmission)
+	if c.countWhere((*participantRecord).submittedGPKj) < len(c.participants) {
+		return nil
+	}
+	height := c.height()
+	c.phase = state.DisputeGPKJSubmission
+	c.phaseStart = height + c.confirmationLength
+	return c.emit("GPKJSubmissionComplete", new(big.Int).SetUint64(height))
+}
+
+func (c *contract) complete(from common.Address) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.checkSubmission(state.DisputeGPKJSubmission, c.phaseStart+c.phaseLength); err != nil {
+		return err
+	}
+	if _, err := c.record(from); err != nil {
+		return err
+	}
+	participants := c.sortedParticipants()
+	epoch := big.NewInt(0)
+	for _, p := range participants {
+		err := c.emit(
+			"ValidatorMemberAdded",
+			p.address,
+			new(big.Int).SetUint64(p.Index),
+			new(big.Int).SetUint64(c.nonce),
+			epoch,
+			p.Gpkj[0], p.Gpkj[1], p.Gpkj[2], p.Gpkj[3],
+		)
+		if err != nil {
+			return err
+		}
+	}
+	c.phase = state.Completion
+	return c.emit(
+		"ValidatorSetCompleted",
+		big.NewInt(int64(len(participants))),
+		new(big.Int).SetUint64(c.nonce),
+		epoch,
+		new(big.Int).SetUint64(c.height()),
+		big.NewInt(0),
+		c.mpk[0], c.mpk[1], c.mpk[2], c.mpk[3],
+	)
+}
+
+// accuse records an accusation, evicting the accused and halting the round.
+func (c *contract) accuse(kind AccusationKind, accuser, accused common.Address) {
+	c.accused[accused] = true
+	c.halted = true
+	c.accusations = append(c.accusations, Accusation{
+		Kind:    kind,
+		Accuser: accuser,
+		Accused: accused,
+		Height:  c.height(),
+	})
+}
+
+func (c *contract) checkAccuser(accuser common.Address) error {
+	if !c.validators[accuser] || c.accused[accuser] {
+		return ErrNotValidator
+	}
+	return nil
+}
+
+// accuseMissing handles the accusations of participants that did not submit
+// during a phase. The whole accusation is rejected if any of the accused
+// did submit.
+func (c *contract) accuseMissing(
+	kind AccusationKind,
+	accuser common.Address,
+	accused []common.Address,
+	open bool,
+	missing func(address common.Address, p *participantRecord, ok bool) bool,
+) error {
+	c.Lock()
+	defer c.Unlock()
+	if !open {
+		return fmt.Errorf("%w: %v accusation", ErrWrongPhase, kind)
+	}
+	if err := c.checkAccuser(accuser); err != nil {
+		return err
+	}
+	for _, address := range accused {
+		if !c.validators[address] || c.accused[address] {
+			return fmt.Errorf("%w: %v is not a validator", ErrInvalidAccusation, address.Hex())
+		}
+		p, ok := c.participants[address]
+		if !missing(address, p, ok && p.Nonce == c.nonce) {
+			return fmt.Errorf("%w: %v did participate", ErrInvalidAccusation, address.Hex())
+		}
+	}
+	for _, address := range accused {
+		c.accuse(kind, accuser, address)
+	}
+	return nil
+}
+
+func (c *contract) accuseNotRegistered(accuser common.Address, accused []common.Address) error {
+	c.Lock()
+	open := c.phase == state.RegistrationOpen && c.inWindow(c.phaseStart+c.phaseLength)
+	c.Unlock()
+	return c.accuseMissing(NotRegistered, accuser, accused, open,
+		func(_ common.Address, _ *participantRecord, registered bool) bool {
+			return !registered
+		})
+}
+
+func (c *contract) accuseDidNotDistributeShares(accuser common.Address, accused []common.Address) error {
+	c.Lock()
+	open := c.phase == state.ShareDistribution && c.inWindow(c.phaseStart+c.phaseLength)
+	c.Unlock()
+	return c.accuseMissing(DidNotDistributeShares, accuser, accused, open,
+		func(_ common.Address, p *participantRecord, registered bool) bool {
+			return registered && !p.distributed()
+		})
+}
+
+func (c *contract) accuseDidNotSubmitKeyShares(accuser common.Address, accused []common.Address) error {
+	c.Lock()
+	open := (c.phase == state.DisputeShareDistribution || c.phase == state.KeyShareSubmission) &&
+		c.inWindow(c.keyShareStart()+c.phaseLength)
+	c.Unlock()
+	return c.accuseMissing(DidNotSubmitKeyShares, accuser, accused, open,
+		func(_ common.Address, p *participantRecord, registered bool) bool {
+			return registered && !p.submittedKeyShare()
+		})
+}
+
+func (c *contract) accuseDidNotSubmitGPKj(accuser common.Address, accused []common.Address) error {
+	c.Lock()
+	open := c.phase == state.GPKJSubmission && c.inWindow(c.phaseStart+c.phaseLength)
+	c.Unlock()
+	return c.accuseMissing(DidNotSubmitGPKj, accuser, accused, open,
+		func(_ common.Address, p *participantRecord, registered bool) bool {
+			return registered && !p.submittedGPKj()
+		})
+}
+
+// accuseDistributedBadShares checks the shared key proof, decrypts the share
+// the accused sent to the accuser and accepts the accusation only if the
+// share does not match the accused commitments.
+func (c *contract) accuseDistributedBadShares(
+	accuser, accused common.Address,
+	encryptedShares []*big.Int,
+	commitments [][2]*big.Int,
+	sharedKey, sharedKeyProof [2]*big.Int,
+) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.phase != state.DisputeShareDistribution || !c.inWindow(c.phaseStart) {
+		return fmt.Errorf("%w: %v accusation", ErrWrongPhase, DistributedBadShares)
+	}
+	if err := c.checkAccuser(accuser); err != nil {
+		return err
+	}
+	if c.accused[accused] {
+		return fmt.Errorf("%w: %v was already accused", ErrInvalidAccusation, accused.Hex())
+	}
+	accuserRecord, err := c.record(accuser)
+	if err != nil {
+		return err
+	}
+	accusedRecord, err := c.record(accused)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAccusation, err)
+	}
+	if !accusedRecord.distributed() {
+		return fmt.Errorf("%w: %v did not distribute shares", ErrInvalidAccusation, accused.Hex())
+	}
+	hash, _, _, err := state.ComputeDistributedSharesHash(encryptedShares, commitments)
+	if err != nil || hash != accusedRecord.DistributedSharesHash {
+		return fmt.Errorf("%w: shares do not match the distributed shares hash", ErrInvalidAccusation)
+	}
+
+	accuserPublicKey, err := bn256.BigIntArrayToG1(accuserRecord.PublicKey)
+	if err != nil {
+		return err
+	}
+	accusedPublicKey, err := bn256.BigIntArrayToG1(accusedRecord.PublicKey)
+	if err != nil {
+		return err
+	}
+	sharedKeyG1, err := bn256.BigIntArrayToG1(sharedKey)
+	if err != nil {
+		return fmt.Errorf("%w: shared key: %v", ErrInvalidAccusation, err)
+	}
+	g1Base := new(cloudflare.G1).ScalarBaseMult(common.Big1)
+	if err := cloudflare.VerifyDLEQProofG1(g1Base, accuserPublicKey, accusedPublicKey, sharedKeyG1, sharedKeyProof); err != nil {
+		return fmt.Errorf("%w: shared key proof: %v", ErrInvalidAccusation, err)
+	}
+
+	accuserIndex := int(accuserRecord.Index)
+	encShareIdx := accuserIndex - 1
+	if accusedRecord.Index < accuserRecord.Index {
+		encShareIdx--
+	}
+	publicCoefficients := make([]*cloudflare.G1, len(commitments))
+	for i, commitment := range commitments {
+		publicCoefficients[i], err = bn256.BigIntArrayToG1(commitment)
+		if err != nil {
+			return err
+		}
+	}
+	secret := cloudflare.DecryptSS(encryptedShares[encShareIdx], sharedKeyG1, accuserIndex)
+	valid, err := cloudflare.CompareSharedSecret(secret, accuserIndex, publicCoefficients)
+	if err != nil {
+		return err
+	}
+	if valid {
+		return fmt.Errorf("%w: share from %v is valid", ErrInvalidAccusation, accused.Hex())
+	}
+	c.accuse(DistributedBadShares, accuser, accused)
+	return nil
+}
+
+// accuseSubmittedBadGPKj checks the group data provided by the accuser
+// against the stored shares hashes and accepts the accusation if the
+// accused gpkj fails the pairing check.
+func (c *contract) accuseSubmittedBadGPKj(
+	accuser common.Address,
+	validators []common.Address,
+	encryptedSharesHash [][32]byte,
+	commitments [][][2]*big.Int,
+	accused common.Address,
+) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.phase != state.DisputeGPKJSubmission || !c.inWindow(c.phaseStart) {
+		return fmt.Errorf("%w: %v accusation", ErrWrongPhase, SubmittedBadGPKj)
+	}
+	if err := c.checkAccuser(accuser); err != nil {
+		return err
+	}
+	if c.accused[accused] {
+		return fmt.Errorf("%w: %v was already accused", ErrInvalidAccusation, accused.Hex())
+	}
+	participants := c.sortedParticipants()
+	if len(validators) != len(participants) || len(encryptedSharesHash) != len(participants) ||
+		len(commitments) != len(participants) {
+		return fmt.Errorf("%w: group data does not cover every participant", ErrInvalidAccusation)
+	}
+	var gpkjs [][4]*big.Int
+	var list state.ParticipantList
+	for idx, p := range participants {
+		if validators[idx] != p.address {
+			return fmt.Errorf("%w: validators are not sorted by index", ErrInvalidAccusation)
+		}
+		commitmentsBin, err := bn256.MarshalG1BigSlice(commitments[idx])
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAccusation, err)
+		}
+		hash := crypto.Hasher(append(encryptedSharesHash[idx][:], crypto.Hasher(commitmentsBin)...))
+		if !bytes.Equal(hash, p.DistributedSharesHash[:]) {
+			return fmt.Errorf("%w: shares of %v do not match the distributed shares hash", ErrInvalidAccusation, p.address.Hex())
+		}
+		gpkjs = append(gpkjs, p.Gpkj)
+		list = append(list, &state.Participant{Address: p.address, Index: int(p.Index), PublicKey: p.PublicKey})
+	}
+	_, bad, _, err := state.CategorizeGroupSigners(gpkjs, list, commitments)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAccusation, err)
+	}
+	for _, p := range bad {
+		if p.Address == accused {
+			c.accuse(SubmittedBadGPKj, accuser, accused)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: gpkj of %v is valid", ErrInvalidAccusation, accused.Hex())
+}
+
+// participantState returns the state the contract reports for an address.
+func (c *contract) participantState(address common.Address) bindings.Participant {
+	c.Lock()
+	defer c.Unlock()
+	if p, ok := c.participants[address]; ok {
+		return p.Participant
+	}
+	return newParticipantRecord(address).Participant
+}
+
+func (c *contract) getPhase() state.EthDKGPhase {
+	c.Lock()
+	defer c.Unlock()
+	return c.phase
+}
+
+func (c *contract) getNonce() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.nonce
+}
+
+func (c *contract) getMasterPublicKey() [4]*big.Int {
+	c.Lock()
+	defer c.Unlock()
+	return c.mpk
+}
+
+func (c *contract) getAccusations() []Accusation {
+	c.Lock()
+	defer c.Unlock()
+	return append([]Accusation{}, c.accusations...)
+}
+
+func (c *contract) getLogs() []types.Log {
+	c.Lock()
+	defer c.Unlock()
+	return append(append([]types.Log{}, c.logs...), c.pending...)
+}
@@ -0,0 +1,253 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
+)
+
+var ErrNoCeremony = errors.New("logs do not contain a RegistrationOpened event")
+
+// Audit is the outcome of a ceremony reconstructed from its ETHDKG logs.
+//
+// Bad shares cannot be audited from the logs since checking a share requires
+// the transport private key of the participant it was sent to; those show up
+// as an incomplete ceremony instead. The gpkjs are only published when the
+// ceremony completes, so the gpkj checks are only done for completed
+// ceremonies.
+type Audit struct {
+	Nonce              uint64
+	Phase              state.EthDKGPhase
+	Completed          bool
+	NumberOfValidators int
+	// Participants are the registered participants sorted by index.
+	Participants []common.Address
+	// MissingRegistrations is the number of validators that did not register.
+	MissingRegistrations int
+	MissingShares        []common.Address
+	MissingKeyShares     []common.Address
+	MissingGPKj          []common.Address
+	BadGPKj              []common.Address
+	MasterPublicKey      [4]*big.Int
+	// MasterPublicKeyValid is true if the master public key matches the sum
+	// of the submitted key shares.
+	MasterPublicKeyValid bool
+	// GroupKeyMatches is true if the group key of the completed validator set
+	// is the master public key.
+	GroupKeyMatches bool
+}
+
+// Replay rebuilds the last ceremony found in the logs with the same state
+// transitions a node applies and audits its outcome. Logs that are not
+// ETHDKG events are ignored.
+func Replay(logs []types.Log) (*Audit, error) {
+	filterer, err := bindings.NewETHDKGFilterer(common.Address{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	eventIDs := make(map[common.Hash]string)
+	for name, event := range events.GetETHDKGEvents() {
+		eventIDs[event.ID] = name
+	}
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+	logger := logrus.NewEntry(discard)
+
+	var dkgState *state.DkgState
+	var groupKey [4]*big.Int
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		name, ok := eventIDs[log.Topics[0]]
+		if !ok {
+			continue
+		}
+		if name != "RegistrationOpened" && dkgState == nil {
+			continue
+		}
+		switch name {
+		case "RegistrationOpened":
+			event, err := filterer.ParseRegistrationOpened(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState = state.NewDkgState(accounts.Account{})
+			dkgState.OnRegistrationOpened(
+				event.StartBlock.Uint64(),
+				event.PhaseLength.Uint64(),
+				event.ConfirmationLength.Uint64(),
+				event.Nonce.Uint64(),
+			)
+			dkgState.NumberOfValidators = int(event.NumberValidators.Int64())
+			groupKey = [4]*big.Int{}
+		case "AddressRegistered":
+			event, err := filterer.ParseAddressRegistered(log)
+			if err != nil {
+				return nil, err
+			}
+			if event.Nonce.Uint64() == dkgState.Nonce {
+				dkgState.OnAddressRegistered(event.Account, int(event.Index.Int64()), event.Nonce.Uint64(), event.PublicKey)
+			}
+		case "RegistrationComplete":
+			event, err := filterer.ParseRegistrationComplete(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnRegistrationComplete(event.BlockNumber.Uint64())
+		case "SharesDistributed":
+			event, err := filterer.ParseSharesDistributed(log)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := dkgState.Participants[event.Account]; !ok {
+				return nil, fmt.Errorf("shares distributed by unregistered %v", event.Account.Hex())
+			}
+			if err := dkgState.OnSharesDistributed(logger, event.Account, event.EncryptedShares, event.Commitments); err != nil {
+				return nil, err
+			}
+		case "ShareDistributionComplete":
+			event, err := filterer.ParseShareDistributionComplete(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnShareDistributionComplete(event.BlockNumber.Uint64())
+		case "KeyShareSubmitted":
+			event, err := filterer.ParseKeyShareSubmitted(log)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := dkgState.Participants[event.Account]; !ok {
+				return nil, fmt.Errorf("key share submitted by unregistered %v", event.Account.Hex())
+			}
+			dkgState.OnKeyShareSubmitted(event.Account, event.KeyShareG1, event.KeyShareG1CorrectnessProof, event.KeyShareG2)
+		case "KeyShareSubmissionComplete":
+			event, err := filterer.ParseKeyShareSubmissionComplete(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnKeyShareSubmissionComplete(event.BlockNumber.Uint64())
+		case "MPKSet":
+			event, err := filterer.ParseMPKSet(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnMPKSet(event.BlockNumber.Uint64())
+			dkgState.MasterPublicKey = event.Mpk
+		case "GPKJSubmissionComplete":
+			event, err := filterer.ParseGPKJSubmissionComplete(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnGPKJSubmissionComplete(event.BlockNumber.Uint64())
+		case "ValidatorMemberAdded":
+			event, err := filterer.ParseValidatorMemberAdded(log)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := dkgState.Participants[event.Account]; !ok {
+				return nil, fmt.Errorf("validator member %v did not register", event.Account.Hex())
+			}
+			dkgState.OnGPKjSubmitted(event.Account, [4]*big.Int{event.Share0, event.Share1, event.Share2, event.Share3})
+		case "ValidatorSetCompleted":
+			event, err := filterer.ParseValidatorSetCompleted(log)
+			if err != nil {
+				return nil, err
+			}
+			dkgState.OnCompletion()
+			groupKey = [4]*big.Int{event.GroupKey0, event.GroupKey1, event.GroupKey2, event.GroupKey3}
+		}
+	}
+	if dkgState == nil {
+		return nil, ErrNoCeremony
+	}
+	return audit(dkgState, groupKey)
+}
+
+func audit(dkgState *state.DkgState, groupKey [4]*big.Int) (*Audit, error) {
+	participants := dkgState.GetSortedParticipants()
+	a := &Audit{
+		Nonce:                dkgState.Nonce,
+		Phase:                dkgState.Phase,
+		Completed:            dkgState.Phase == state.Completion,
+		NumberOfValidators:   dkgState.NumberOfValidators,
+		MissingRegistrations: dkgState.NumberOfValidators - len(participants),
+		MasterPublicKey:      dkgState.MasterPublicKey,
+	}
+	for _, p := range participants {
+		a.Participants = append(a.Participants, p.Address)
+		if dkgState.Phase >= state.ShareDistribution && len(p.EncryptedShares) == 0 {
+			a.MissingShares = append(a.MissingShares, p.Address)
+		}
+		if dkgState.Phase >= state.DisputeShareDistribution && p.KeyShareG1s[0] == nil {
+			a.MissingKeyShares = append(a.MissingKeyShares, p.Address)
+		}
+	}
+
+	if !isMasterPublicKeySet(dkgState.MasterPublicKey) {
+		return a, nil
+	}
+	var g1s [][2]*big.Int
+	var g2s [][4]*big.Int
+	for _, p := range participants {
+		g1s = append(g1s, p.KeyShareG1s)
+		g2s = append(g2s, p.KeyShareG2s)
+	}
+	if len(a.MissingKeyShares) == 0 {
+		mpk, err := state.GenerateMasterPublicKey(g1s, g2s)
+		a.MasterPublicKeyValid = err == nil && equalKeys(mpk, dkgState.MasterPublicKey)
+	}
+
+	if !a.Completed {
+		return a, nil
+	}
+	a.GroupKeyMatches = equalKeys(groupKey, dkgState.MasterPublicKey)
+	var gpkjs [][4]*big.Int
+	var commitments [][][2]*big.Int
+	for _, p := range participants {
+		gpkjs = append(gpkjs, p.GPKj)
+		commitments = append(commitments, p.Commitments)
+	}
+	if len(a.MissingShares) > 0 {
+		return a, nil
+	}
+	_, bad, missing, err := state.CategorizeGroupSigners(gpkjs, participants, commitments)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range bad {
+		a.BadGPKj = append(a.BadGPKj, p.Address)
+	}
+	for _, p := range missing {
+		a.MissingGPKj = append(a.MissingGPKj, p.Address)
+	}
+	return a, nil
+}
+
+func isMasterPublicKeySet(mpk [4]*big.Int) bool {
+	for _, value := range mpk {
+		if value != nil && value.Sign() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func equalKeys(a, b [4]*big.Int) bool {
+	for i := range a {
+		if a[i] == nil || b[i] == nil || a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
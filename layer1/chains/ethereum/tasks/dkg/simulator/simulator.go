@@ -0,0 +1,673 @@
+// Package simulator runs ETHDKG ceremonies in process. The participants run
+// the real DKG tasks and event processors against an in-memory ETHDKG
+// contract, so a ceremony can be dry-run, including misbehaving participants,
+// without a layer1 node. A recorded set of ETHDKG logs can also be replayed to
+// audit the outcome of a past ceremony.
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/crypto/bn256"
+	"github.com/alicenet/alicenet/crypto/bn256/cloudflare"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
+	"github.com/alicenet/alicenet/layer1/executor"
+	"github.com/alicenet/alicenet/layer1/executor/tasks"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+var ErrNotEnoughParticipants = errors.New("ethdkg requires at least 4 participants")
+
+// Fault is a misbehaviour injected into a simulated participant.
+type Fault int
+
+// The possible faults. Missing* participants skip the corresponding
+// submission, Bad* participants submit data that fails verification.
+const (
+	Honest Fault = iota
+	MissingRegistration
+	MissingShares
+	BadShares
+	MissingKeyShares
+	MissingGPKj
+	BadGPKj
+)
+
+var faultNames = [...]string{
+	"honest",
+	"missing-registration",
+	"missing-shares",
+	"bad-shares",
+	"missing-key-shares",
+	"missing-gpkj",
+	"bad-gpkj",
+}
+
+func (fault Fault) String() string {
+	return faultNames[fault]
+}
+
+// ParseFault returns the fault with the given name.
+func ParseFault(name string) (Fault, error) {
+	for fault, faultName := range faultNames {
+		if strings.EqualFold(name, faultName) {
+			return Fault(fault), nil
+		}
+	}
+	return Honest, fmt.Errorf("unknown fault %q, expected one of %v", name, strings.Join(faultNames[:], ", "))
+}
+
+// skippedTask returns the task a faulty participant never runs.
+func (fault Fault) skippedTask() tasks.Task {
+	switch fault {
+	case MissingRegistration:
+		return &dkg.RegisterTask{}
+	case MissingShares:
+		return &dkg.ShareDistributionTask{}
+	case MissingKeyShares:
+		return &dkg.KeyShareSubmissionTask{}
+	case MissingGPKj:
+		return &dkg.GPKjSubmissionTask{}
+	default:
+		return nil
+	}
+}
+
+// Config of a simulated ceremony.
+type Config struct {
+	// Participants is the number of validators taking part, at least 4.
+	Participants int
+	// PhaseLength and ConfirmationLength are the ETHDKG phase parameters in
+	// blocks.
+	PhaseLength        uint64
+	ConfirmationLength uint64
+	// Faults maps the position of a participant, starting at 0, to the fault
+	// it injects. Participants not in the map are honest.
+	Faults map[int]Fault
+	// Logger receives the logs of the tasks and event processors. The logs
+	// are discarded if it is nil.
+	Logger *logrus.Entry
+}
+
+// Result is the outcome of a simulated ceremony.
+type Result struct {
+	Completed bool
+	// Phase is the last phase the contract reached.
+	Phase state.EthDKGPhase
+	// Height is the block the simulation stopped at.
+	Height          uint64
+	Participants    []common.Address
+	Accusations     []Accusation
+	MasterPublicKey [4]*big.Int
+	// Logs are the logs emitted by the contract, in order. They can be fed to
+	// Replay.
+	Logs []types.Log
+}
+
+type gpkjSubmission struct {
+	account common.Address
+	gpkj    [4]*big.Int
+}
+
+// Simulator drives the participants of a ceremony one block at a time.
+type Simulator struct {
+	logger       *logrus.Entry
+	config       Config
+	height       uint64
+	contract     *contract
+	participants []*participant
+	eventIDs     map[common.Hash]string
+	filterer     *bindings.ETHDKGFilterer
+	gpkjs        []gpkjSubmission
+	evicted      int
+	txCount      uint64
+}
+
+type participant struct {
+	account      accounts.Account
+	fault        Fault
+	logger       *logrus.Entry
+	db           *db.Database
+	client       *mocks.MockClient
+	contracts    *mocks.MockAllSmartContracts
+	adminHandler *mocks.MockAdminHandler
+	tasks        *taskList
+}
+
+// NewSimulator creates the contract and the participants of a ceremony.
+func NewSimulator(cfg Config) (*Simulator, error) {
+	if cfg.Participants < 4 {
+		return nil, fmt.Errorf("%w: got %d", ErrNotEnoughParticipants, cfg.Participants)
+	}
+	if cfg.PhaseLength == 0 {
+		return nil, errors.New("phase length must be positive")
+	}
+	if cfg.Logger == nil {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		cfg.Logger = logrus.NewEntry(logger)
+	}
+	filterer, err := bindings.NewETHDKGFilterer(ContractAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		logger:   cfg.Logger,
+		config:   cfg,
+		height:   1,
+		eventIDs: make(map[common.Hash]string),
+		filterer: filterer,
+	}
+	s.contract = newContract(func() uint64 { return s.height }, cfg.PhaseLength, cfg.ConfirmationLength)
+	for name, event := range s.contract.events {
+		s.eventIDs[event.ID] = name
+	}
+
+	var validators []common.Address
+	for idx := 0; idx < cfg.Participants; idx++ {
+		validators = append(validators, common.BigToAddress(big.NewInt(int64(idx+1))))
+	}
+	for idx, address := range validators {
+		// the dkg state is persisted as json, which requires the account url
+		account := accounts.Account{
+			Address: address,
+			URL:     accounts.URL{Scheme: "simulator", Path: address.Hex()},
+		}
+		p, err := s.newParticipant(idx, account, validators)
+		if err != nil {
+			return nil, err
+		}
+		s.participants = append(s.participants, p)
+	}
+	return s, nil
+}
+
+func (s *Simulator) newParticipant(idx int, account accounts.Account, validators []common.Address) (*participant, error) {
+	p := &participant{
+		account:      account,
+		fault:        s.config.Faults[idx],
+		logger:       s.logger.WithField("participant", idx),
+		db:           mocks.NewTestDB(),
+		client:       mocks.NewMockClient(),
+		contracts:    mocks.NewMockAllSmartContracts(),
+		adminHandler: mocks.NewMockAdminHandler(),
+	}
+	p.tasks = &taskList{fault: p.fault}
+
+	monState := objects.NewMonitorState()
+	for _, validator := range validators {
+		monState.PotentialValidators[validator] = objects.PotentialValidator{Account: validator}
+	}
+	if err := monState.PersistState(p.db); err != nil {
+		return nil, err
+	}
+
+	p.client.GetDefaultAccountFunc.SetDefaultReturn(account)
+	p.client.GetCurrentHeightFunc.SetDefaultHook(func(context.Context) (uint64, error) {
+		return s.height, nil
+	})
+	p.client.GetTransactionOptsFunc.SetDefaultHook(func(ctx context.Context, acct accounts.Account) (*bind.TransactOpts, error) {
+		return &bind.TransactOpts{From: acct.Address, Context: ctx}, nil
+	})
+	p.client.GetCallOptsFunc.SetDefaultHook(func(ctx context.Context, acct accounts.Account) (*bind.CallOpts, error) {
+		return &bind.CallOpts{From: acct.Address, Context: ctx}, nil
+	})
+	p.client.GetBlockByNumberFunc.SetDefaultHook(func(_ context.Context, number *big.Int) (*types.Block, error) {
+		return types.NewBlockWithHeader(&types.Header{Number: number, Difficulty: common.Big0}), nil
+	})
+
+	ethContracts := mocks.NewMockEthereumContracts()
+	ethContracts.EthdkgFunc.SetDefaultReturn(s.newBinding())
+	p.contracts.EthereumContractsFunc.SetDefaultReturn(ethContracts)
+	return p, nil
+}
+
+// newBinding returns an ETHDKG binding backed by the simulated contract.
+// Transactions are applied right away and a reverted transaction is
+// reported as an error, as it would be when estimating its gas.
+func (s *Simulator) newBinding() bindings.IETHDKG {
+	c := s.contract
+	ethdkg := mocks.NewMockIETHDKG()
+
+	ethdkg.RegisterFunc.SetDefaultHook(func(opts *bind.TransactOpts, publicKey [2]*big.Int) (*types.Transaction, error) {
+		return s.transact(c.register(opts.From, publicKey))
+	})
+	ethdkg.DistributeSharesFunc.SetDefaultHook(func(opts *bind.TransactOpts, encryptedShares []*big.Int, commitments [][2]*big.Int) (*types.Transaction, error) {
+		return s.transact(c.distributeShares(opts.From, encryptedShares, commitments))
+	})
+	ethdkg.SubmitKeyShareFunc.SetDefaultHook(func(opts *bind.TransactOpts, keyShareG1, proof [2]*big.Int, keyShareG2 [4]*big.Int) (*types.Transaction, error) {
+		return s.transact(c.submitKeyShare(opts.From, keyShareG1, proof, keyShareG2))
+	})
+	ethdkg.SubmitMasterPublicKeyFunc.SetDefaultHook(func(opts *bind.TransactOpts, mpk [4]*big.Int) (*types.Transaction, error) {
+		return s.transact(c.submitMasterPublicKey(opts.From, mpk))
+	})
+	ethdkg.SubmitGPKJFunc.SetDefaultHook(func(opts *bind.TransactOpts, gpkj [4]*big.Int) (*types.Transaction, error) {
+		if err := c.submitGPKj(opts.From, gpkj); err != nil {
+			return nil, err
+		}
+		s.gpkjs = append(s.gpkjs, gpkjSubmission{account: opts.From, gpkj: gpkj})
+		return s.transact(nil)
+	})
+	ethdkg.CompleteFunc.SetDefaultHook(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.transact(c.complete(opts.From))
+	})
+
+	ethdkg.AccuseParticipantNotRegisteredFunc.SetDefaultHook(func(opts *bind.TransactOpts, accused []common.Address) (*types.Transaction, error) {
+		return s.transact(c.accuseNotRegistered(opts.From, accused))
+	})
+	ethdkg.AccuseParticipantDidNotDistributeSharesFunc.SetDefaultHook(func(opts *bind.TransactOpts, accused []common.Address) (*types.Transaction, error) {
+		return s.transact(c.accuseDidNotDistributeShares(opts.From, accused))
+	})
+	ethdkg.AccuseParticipantDistributedBadSharesFunc.SetDefaultHook(func(opts *bind.TransactOpts, accused common.Address, encryptedShares []*big.Int, commitments [][2]*big.Int, sharedKey, sharedKeyProof [2]*big.Int) (*types.Transaction, error) {
+		return s.transact(c.accuseDistributedBadShares(opts.From, accused, encryptedShares, commitments, sharedKey, sharedKeyProof))
+	})
+	ethdkg.AccuseParticipantDidNotSubmitKeySharesFunc.SetDefaultHook(func(opts *bind.TransactOpts, accused []common.Address) (*types.Transaction, error) {
+		return s.transact(c.accuseDidNotSubmitKeyShares(opts.From, accused))
+	})
+	ethdkg.AccuseParticipantDidNotSubmitGPKJFunc.SetDefaultHook(func(opts *bind.TransactOpts, accused []common.Address) (*types.Transaction, error) {
+		return s.transact(c.accuseDidNotSubmitGPKj(opts.From, accused))
+	})
+	ethdkg.AccuseParticipantSubmittedBadGPKJFunc.SetDefaultHook(func(opts *bind.TransactOpts, validators []common.Address, encryptedSharesHash [][32]byte, commitments [][][2]*big.Int, accused common.Address) (*types.Transaction, error) {
+		return s.transact(c.accuseSubmittedBadGPKj(opts.From, validators, encryptedSharesHash, commitments, accused))
+	})
+
+	ethdkg.GetParticipantInternalStateFunc.SetDefaultHook(func(_ *bind.CallOpts, address common.Address) (bindings.Participant, error) {
+		return c.participantState(address), nil
+	})
+	ethdkg.GetNonceFunc.SetDefaultHook(func(*bind.CallOpts) (*big.Int, error) {
+		return new(big.Int).SetUint64(c.getNonce()), nil
+	})
+	ethdkg.GetETHDKGPhaseFunc.SetDefaultHook(func(*bind.CallOpts) (uint8, error) {
+		return uint8(c.getPhase()), nil
+	})
+	ethdkg.GetMasterPublicKeyFunc.SetDefaultHook(func(*bind.CallOpts) ([4]*big.Int, error) {
+		return c.getMasterPublicKey(), nil
+	})
+	ethdkg.GetMasterPublicKeyHashFunc.SetDefaultHook(func(*bind.CallOpts) ([32]byte, error) {
+		var hash [32]byte
+		mpk := c.getMasterPublicKey()
+		mpkBin, err := bn256.MarshalBigIntSlice(mpk[:])
+		if err != nil {
+			return hash, err
+		}
+		copy(hash[:], crypto.Hasher(mpkBin))
+		return hash, nil
+	})
+
+	ethdkg.ParseAddressRegisteredFunc.SetDefaultHook(s.filterer.ParseAddressRegistered)
+	ethdkg.ParseGPKJSubmissionCompleteFunc.SetDefaultHook(s.filterer.ParseGPKJSubmissionComplete)
+	ethdkg.ParseKeyShareSubmissionCompleteFunc.SetDefaultHook(s.filterer.ParseKeyShareSubmissionComplete)
+	ethdkg.ParseKeyShareSubmittedFunc.SetDefaultHook(s.filterer.ParseKeyShareSubmitted)
+	ethdkg.ParseMPKSetFunc.SetDefaultHook(s.filterer.ParseMPKSet)
+	ethdkg.ParseRegistrationCompleteFunc.SetDefaultHook(s.filterer.ParseRegistrationComplete)
+	ethdkg.ParseRegistrationOpenedFunc.SetDefaultHook(s.filterer.ParseRegistrationOpened)
+	ethdkg.ParseShareDistributionCompleteFunc.SetDefaultHook(s.filterer.ParseShareDistributionComplete)
+	ethdkg.ParseSharesDistributedFunc.SetDefaultHook(s.filterer.ParseSharesDistributed)
+	ethdkg.ParseValidatorMemberAddedFunc.SetDefaultHook(s.filterer.ParseValidatorMemberAdded)
+	ethdkg.ParseValidatorSetCompletedFunc.SetDefaultHook(s.filterer.ParseValidatorSetCompleted)
+	return ethdkg
+}
+
+func (s *Simulator) transact(err error) (*types.Transaction, error) {
+	if err != nil {
+		return nil, err
+	}
+	s.txCount++
+	return types.NewTx(&types.LegacyTx{Nonce: s.txCount}), nil
+}
+
+// Run opens the registration and advances the chain until the ceremony
+// completes or every participant runs out of tasks.
+func (s *Simulator) Run(ctx context.Context) (*Result, error) {
+	var validators []common.Address
+	for _, p := range s.participants {
+		validators = append(validators, p.account.Address)
+	}
+	if err := s.contract.openRegistration(validators); err != nil {
+		return nil, err
+	}
+
+	// every phase has a submission and a dispute window, the limit only
+	// guards against a simulation that never settles
+	maxHeight := s.height + 16*(s.config.PhaseLength+s.config.ConfirmationLength)
+	for s.height++; s.height <= maxHeight; s.height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		delivered, err := s.deliver()
+		if err != nil {
+			return nil, err
+		}
+		if s.contract.getPhase() == state.Completion && !delivered {
+			break
+		}
+		active := false
+		for _, p := range s.participants {
+			if p.runTasks(ctx, s.height) {
+				active = true
+			}
+		}
+		if err := s.evictAccused(); err != nil {
+			return nil, err
+		}
+		if !active && !delivered && len(s.contract.pending) == 0 && len(s.gpkjs) == 0 {
+			break
+		}
+	}
+	return s.result(), nil
+}
+
+func (s *Simulator) result() *Result {
+	result := &Result{
+		Phase:           s.contract.getPhase(),
+		Height:          s.height,
+		Accusations:     s.contract.getAccusations(),
+		MasterPublicKey: s.contract.getMasterPublicKey(),
+		Logs:            s.contract.getLogs(),
+	}
+	result.Completed = result.Phase == state.Completion
+	for _, p := range s.participants {
+		result.Participants = append(result.Participants, p.account.Address)
+	}
+	return result
+}
+
+// deliver passes the logs of the previous block to every participant. It
+// returns false if there was nothing to deliver.
+func (s *Simulator) deliver() (bool, error) {
+	logs := s.contract.takeLogs()
+	gpkjs := s.gpkjs
+	s.gpkjs = nil
+	for _, p := range s.participants {
+		for _, log := range logs {
+			if err := s.processLog(p, log); err != nil {
+				return false, fmt.Errorf("participant %v failed to process %v: %w", p.account.Address.Hex(), s.eventIDs[log.Topics[0]], err)
+			}
+		}
+		// ETHDKG does not emit an event per gpkj, the nodes only learn the
+		// submitted gpkjs with ValidatorMemberAdded. Hand them over the same
+		// way the task tests do so that the gpkj disputes can run.
+		if len(gpkjs) > 0 {
+			dkgState, err := state.GetDkgState(p.db)
+			if err != nil {
+				return false, err
+			}
+			for _, submission := range gpkjs {
+				if _, ok := dkgState.Participants[submission.account]; ok {
+					dkgState.OnGPKjSubmitted(submission.account, submission.gpkj)
+				}
+			}
+			if err := state.SaveDkgState(p.db, dkgState); err != nil {
+				return false, err
+			}
+		}
+	}
+	return len(logs) > 0 || len(gpkjs) > 0, nil
+}
+
+// processLog runs the monitor event processor of a log.
+func (s *Simulator) processLog(p *participant, log types.Log) error {
+	logger := p.logger
+	switch s.eventIDs[log.Topics[0]] {
+	case "RegistrationOpened":
+		monState, err := objects.GetMonitorState(p.db)
+		if err != nil {
+			return err
+		}
+		return events.ProcessRegistrationOpened(p.client, p.contracts, logger, log, monState, p.db, p.tasks)
+	case "AddressRegistered":
+		return events.ProcessAddressRegistered(p.contracts, logger, log, p.db)
+	case "RegistrationComplete":
+		return events.ProcessRegistrationComplete(p.contracts, logger, log, p.db, p.tasks)
+	case "SharesDistributed":
+		return events.ProcessShareDistribution(p.contracts, logger, log, p.db)
+	case "ShareDistributionComplete":
+		return events.ProcessShareDistributionComplete(p.contracts, logger, log, p.db, p.tasks)
+	case "KeyShareSubmitted":
+		return events.ProcessKeyShareSubmitted(p.contracts, logger, log, p.db)
+	case "KeyShareSubmissionComplete":
+		return events.ProcessKeyShareSubmissionComplete(p.contracts, logger, log, p.db, p.tasks)
+	case "MPKSet":
+		return events.ProcessMPKSet(p.contracts, logger, log, p.adminHandler, p.db, p.tasks)
+	case "GPKJSubmissionComplete":
+		return events.ProcessGPKJSubmissionComplete(p.contracts, logger, log, p.db, p.tasks)
+	case "ValidatorSetCompleted":
+		// the validator set itself belongs to the monitor, only the end of
+		// the ceremony matters here
+		dkgState, err := state.GetDkgState(p.db)
+		if err != nil {
+			return err
+		}
+		dkgState.OnCompletion()
+		return state.SaveDkgState(p.db, dkgState)
+	default:
+		return nil
+	}
+}
+
+// evictAccused removes the accused participants from the validators every
+// participant knows about, as the validator pool would after an accusation.
+func (s *Simulator) evictAccused() error {
+	accusations := s.contract.getAccusations()
+	for ; s.evicted < len(accusations); s.evicted++ {
+		accused := accusations[s.evicted].Accused
+		for _, p := range s.participants {
+			monState, err := objects.GetMonitorState(p.db)
+			if err != nil {
+				return err
+			}
+			delete(monState.PotentialValidators, accused)
+			if err := monState.PersistState(p.db); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runTasks runs one step of every task scheduled for the block. It returns
+// false once the participant has no task left.
+func (p *participant) runTasks(ctx context.Context, height uint64) bool {
+	active := false
+	for _, st := range p.tasks.scheduled {
+		if st.done {
+			continue
+		}
+		// the task manager kills the tasks that outlive their window
+		if height >= st.task.GetEnd() {
+			st.finish(tasks.ErrTaskKilled)
+			continue
+		}
+		active = true
+		if height < st.task.GetStart() {
+			continue
+		}
+		p.step(ctx, st)
+	}
+	return active
+}
+
+// step mirrors the task manager and executor: the task starts once its
+// start condition holds, it is prepared until it succeeds, then executed while
+// ShouldExecute holds. Every retry waits for the next block.
+func (p *participant) step(ctx context.Context, st *scheduledTask) {
+	task := st.task
+	if !st.initialized {
+		if conditional, ok := task.(tasks.ConditionalTask); ok {
+			canStart, taskErr := conditional.CanStart(ctx, p.client, p.contracts)
+			if taskErr != nil && !taskErr.IsRecoverable() {
+				st.finish(taskErr)
+				return
+			}
+			if !canStart {
+				return
+			}
+		}
+		name := reflect.TypeOf(task).Elem().Name()
+		err := task.Initialize(
+			p.db,
+			p.logger.WithField("task", name),
+			p.client,
+			p.contracts,
+			name,
+			st.id,
+			task.GetStart(),
+			task.GetEnd(),
+			task.GetAllowMultiExecution(),
+			nil,
+			nil,
+		)
+		if err != nil {
+			st.done = true
+			return
+		}
+		st.initialized = true
+	}
+	if !st.prepared {
+		if taskErr := task.Prepare(ctx); taskErr != nil {
+			if !taskErr.IsRecoverable() {
+				st.finish(taskErr)
+			}
+			return
+		}
+		if err := p.injectFault(task); err != nil {
+			st.finish(err)
+			return
+		}
+		st.prepared = true
+	}
+	shouldExecute, taskErr := task.ShouldExecute(ctx)
+	if taskErr != nil {
+		if !taskErr.IsRecoverable() {
+			st.finish(taskErr)
+		}
+		return
+	}
+	if !shouldExecute {
+		st.finish(nil)
+		return
+	}
+	if _, taskErr := task.Execute(ctx); taskErr != nil {
+		if !taskErr.IsRecoverable() {
+			st.finish(taskErr)
+		}
+		return
+	}
+	st.finish(nil)
+}
+
+// injectFault corrupts the prepared data of a task, the same way the task
+// tests build misbehaving participants.
+func (p *participant) injectFault(task tasks.Task) error {
+	switch task.(type) {
+	case *dkg.ShareDistributionTask:
+		if p.fault != BadShares {
+			return nil
+		}
+		dkgState, err := state.GetDkgState(p.db)
+		if err != nil {
+			return err
+		}
+		shares := dkgState.Participants[p.account.Address].EncryptedShares
+		shares[0] = new(big.Int).Add(shares[0], common.Big1)
+		return state.SaveDkgState(p.db, dkgState)
+	case *dkg.GPKjSubmissionTask:
+		if p.fault != BadGPKj {
+			return nil
+		}
+		dkgState, err := state.GetDkgState(p.db)
+		if err != nil {
+			return err
+		}
+		gskjBad := new(big.Int).Add(dkgState.GroupPrivateKey, common.Big1)
+		gpkjBad, err := bn256.G2ToBigIntArray(new(cloudflare.G2).ScalarBaseMult(gskjBad))
+		if err != nil {
+			return err
+		}
+		dkgState.GroupPrivateKey = gskjBad
+		dkgState.Participants[p.account.Address].GPKj = gpkjBad
+		return state.SaveDkgState(p.db, dkgState)
+	default:
+		return nil
+	}
+}
+
+type scheduledTask struct {
+	task        tasks.Task
+	id          string
+	initialized bool
+	prepared    bool
+	done        bool
+}
+
+func (st *scheduledTask) finish(err error) {
+	st.done = true
+	if st.initialized {
+		st.task.Finish(err)
+	}
+}
+
+// taskList is the task handler of a simulated participant. Tasks are run
+// by the simulator instead of an executor.
+type taskList struct {
+	fault     Fault
+	scheduled []*scheduledTask
+}
+
+// asserting that taskList implements executor.TaskHandler.
+var _ executor.TaskHandler = &taskList{}
+
+func (tl *taskList) ScheduleTask(task tasks.Task, id string) (*executor.HandlerResponse, error) {
+	if skipped := tl.fault.skippedTask(); skipped != nil && reflect.TypeOf(skipped) == reflect.TypeOf(task) {
+		return nil, nil
+	}
+	if id == "" {
+		id = fmt.Sprintf("%d", len(tl.scheduled))
+	}
+	tl.scheduled = append(tl.scheduled, &scheduledTask{task: task, id: id})
+	return nil, nil
+}
+
+func (tl *taskList) KillTaskByType(task tasks.Task) (*executor.HandlerResponse, error) {
+	for _, st := range tl.scheduled {
+		if !st.done && reflect.TypeOf(st.task) == reflect.TypeOf(task) {
+			st.finish(tasks.ErrTaskKilled)
+		}
+	}
+	return nil, nil
+}
+
+func (tl *taskList) KillTaskById(id string) (*executor.HandlerResponse, error) {
+	for _, st := range tl.scheduled {
+		if !st.done && st.id == id {
+			st.finish(tasks.ErrTaskKilled)
+		}
+	}
+	return nil, nil
+}
+
+func (tl *taskList) Start() {}
+
+func (tl *taskList) Close() {}
+
+func (tl *taskList) CloseChan() <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,112 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
+)
+
+func runCeremony(t *testing.T, faults map[int]Fault) *Result {
+	t.Helper()
+	s, err := NewSimulator(Config{
+		Participants:       4,
+		PhaseLength:        10,
+		ConfirmationLength: 2,
+		Faults:             faults,
+	})
+	assert.Nil(t, err)
+	result, err := s.Run(context.Background())
+	assert.Nil(t, err)
+	return result
+}
+
+func TestSimulatorHonestCeremony(t *testing.T) {
+	result := runCeremony(t, nil)
+	assert.True(t, result.Completed)
+	assert.Empty(t, result.Accusations)
+	assert.True(t, isMasterPublicKeySet(result.MasterPublicKey))
+
+	// logs survive a json round trip, which is how the dkgsim tool stores them
+	raw, err := json.Marshal(result.Logs)
+	assert.Nil(t, err)
+	var logs []types.Log
+	assert.Nil(t, json.Unmarshal(raw, &logs))
+
+	audit, err := Replay(logs)
+	assert.Nil(t, err)
+	assert.True(t, audit.Completed)
+	assert.Equal(t, result.Participants, audit.Participants)
+	assert.Equal(t, 0, audit.MissingRegistrations)
+	assert.Empty(t, audit.MissingShares)
+	assert.Empty(t, audit.MissingKeyShares)
+	assert.Empty(t, audit.MissingGPKj)
+	assert.Empty(t, audit.BadGPKj)
+	assert.True(t, audit.MasterPublicKeyValid)
+	assert.True(t, audit.GroupKeyMatches)
+	assert.True(t, equalKeys(result.MasterPublicKey, audit.MasterPublicKey))
+}
+
+func TestSimulatorFaults(t *testing.T) {
+	testCases := []struct {
+		fault   Fault
+		kind    AccusationKind
+		accuser int
+	}{
+		{MissingRegistration, NotRegistered, -1},
+		{MissingShares, DidNotDistributeShares, -1},
+		// the first encrypted share of the faulty participant is the one sent
+		// to the participant with index 1
+		{BadShares, DistributedBadShares, 0},
+		{MissingKeyShares, DidNotSubmitKeyShares, -1},
+		{MissingGPKj, DidNotSubmitGPKj, -1},
+		{BadGPKj, SubmittedBadGPKj, -1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.fault.String(), func(t *testing.T) {
+			result := runCeremony(t, map[int]Fault{1: tc.fault})
+			assert.False(t, result.Completed)
+			if !assert.Equal(t, 1, len(result.Accusations)) {
+				return
+			}
+			accusation := result.Accusations[0]
+			assert.Equal(t, tc.kind, accusation.Kind)
+			assert.Equal(t, result.Participants[1], accusation.Accused)
+			assert.NotEqual(t, result.Participants[1], accusation.Accuser)
+			if tc.accuser >= 0 {
+				assert.Equal(t, result.Participants[tc.accuser], accusation.Accuser)
+			}
+		})
+	}
+}
+
+func TestReplayIncompleteCeremony(t *testing.T) {
+	result := runCeremony(t, map[int]Fault{2: MissingKeyShares})
+	audit, err := Replay(result.Logs)
+	assert.Nil(t, err)
+	assert.False(t, audit.Completed)
+	assert.Equal(t, state.KeyShareSubmission, audit.Phase)
+	assert.Empty(t, audit.MissingShares)
+	assert.Equal(t, result.Participants[2:3], audit.MissingKeyShares)
+	assert.False(t, audit.MasterPublicKeyValid)
+
+	_, err = Replay(nil)
+	assert.ErrorIs(t, err, ErrNoCeremony)
+}
+
+func TestParseFault(t *testing.T) {
+	fault, err := ParseFault("bad-gpkj")
+	assert.Nil(t, err)
+	assert.Equal(t, BadGPKj, fault)
+	_, err = ParseFault("lazy")
+	assert.NotNil(t, err)
+}
+
+func TestNewSimulatorTooFewParticipants(t *testing.T) {
+	_, err := NewSimulator(Config{Participants: 3, PhaseLength: 10})
+	assert.ErrorIs(t, err, ErrNotEnoughParticipants)
+}
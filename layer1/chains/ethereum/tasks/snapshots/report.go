@@ -0,0 +1,110 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
+	"github.com/alicenet/alicenet/layer1/executor/tasks"
+	"github.com/alicenet/alicenet/utils"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SnapshotReportTask completes and saves the report of a snapshot with the
+// data that has to be read from layer1. It doesn't send any transaction.
+type SnapshotReportTask struct {
+	*tasks.BaseTask
+	Report *state.SnapshotReport `json:"report"`
+}
+
+// asserting that SnapshotReportTask struct implements interface tasks.Task.
+var _ tasks.Task = &SnapshotReportTask{}
+
+// NewSnapshotReportTask creates a task that completes the report of a
+// snapshot. The report must have the data of the SnapshotTaken event and the
+// leader order already.
+func NewSnapshotReportTask(report *state.SnapshotReport) *SnapshotReportTask {
+	return &SnapshotReportTask{
+		BaseTask: tasks.NewBaseTask(0, 0, true, nil),
+		Report:   report,
+	}
+}
+
+// Prepare prepares for work to be done in the SnapshotReportTask.
+func (t *SnapshotReportTask) Prepare(ctx context.Context) *tasks.TaskErr {
+	return nil
+}
+
+// Execute reads the gas used by the snapshot and the election parameters, and
+// saves the report.
+func (t *SnapshotReportTask) Execute(ctx context.Context) (*types.Transaction, *tasks.TaskErr) {
+	logger := t.GetLogger().WithField("method", "Execute()").WithField("AliceNetHeight", t.Report.Height)
+	logger.Debug("initiate execution")
+
+	eth := t.GetClient()
+	report := *t.Report
+
+	receipt, err := eth.GetTransactionReceipt(ctx, report.TxHash)
+	if err != nil {
+		return nil, tasks.NewTaskErr(fmt.Sprintf("could not get the snapshot receipt: %v", err), true)
+	}
+	report.GasUsed = receipt.GasUsed
+
+	callOpts, err := eth.GetCallOpts(ctx, eth.GetDefaultAccount())
+	if err != nil {
+		return nil, tasks.NewTaskErr(fmt.Sprintf(tasks.FailedGettingCallOpts, err), true)
+	}
+	c := t.GetContractsHandler().EthereumContracts().Snapshots()
+	desperationDelay, err := c.GetSnapshotDesperationDelay(callOpts)
+	if err != nil {
+		return nil, tasks.NewTaskErr(fmt.Sprintf("could not get the desperation delay: %v", err), true)
+	}
+	desperationFactor, err := c.GetSnapshotDesperationFactor(callOpts)
+	if err != nil {
+		return nil, tasks.NewTaskErr(fmt.Sprintf("could not get the desperation factor: %v", err), true)
+	}
+	report.DesperationDelay = int(desperationDelay.Int64())
+	report.DesperationFactor = int(desperationFactor.Int64())
+
+	// the election starts at the block of the previous snapshot
+	report.ElectionStart = 0
+	if report.Height > constants.EpochLength {
+		previous, err := state.GetSnapshotReport(t.GetDB(), report.Height-constants.EpochLength)
+		if err == nil {
+			report.ElectionStart = previous.CommittedAt
+		} else if !errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, tasks.NewTaskErr(fmt.Sprintf("could not get the previous snapshot report: %v", err), false)
+		}
+	}
+	if report.ElectionStart == 0 && report.Epoch > 1 {
+		committedAt, err := c.GetCommittedHeightFromSnapshot(callOpts, new(big.Int).SetUint64(report.Epoch-1))
+		if err != nil {
+			return nil, tasks.NewTaskErr(fmt.Sprintf("could not get the previous snapshot height: %v", err), true)
+		}
+		report.ElectionStart = committedAt.Uint64()
+	}
+
+	report.SubmitterAllowedAt = 0
+	if report.SubmitterRank >= 0 && report.ElectionStart != 0 {
+		report.SubmitterAllowedAt = report.ElectionStart
+		if report.SubmitterRank > 0 {
+			report.SubmitterAllowedAt += uint64(report.DesperationDelay) +
+				uint64(utils.GetBlocksSinceDesperationToLead(report.SubmitterRank, report.DesperationFactor))
+		}
+	}
+
+	if err := state.SaveSnapshotReport(t.GetDB(), &report); err != nil {
+		return nil, tasks.NewTaskErr(fmt.Sprintf("could not save the snapshot report: %v", err), false)
+	}
+	logger.Debug("snapshot report saved")
+	return nil, nil
+}
+
+// ShouldExecute checks if it makes sense to execute the task.
+func (t *SnapshotReportTask) ShouldExecute(ctx context.Context) (bool, *tasks.TaskErr) {
+	return true, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/alicenet/alicenet/utils"
@@ -20,8 +21,12 @@ type SnapshotTask struct {
 	ValidatorIndex  int
 }
 
-// asserting that SnapshotTask struct implements interface tasks.Task.
-var _ tasks.Task = &SnapshotTask{}
+// asserting that SnapshotTask struct implements the interfaces tasks.Task and
+// tasks.GiveUpHandler.
+var (
+	_ tasks.Task          = &SnapshotTask{}
+	_ tasks.GiveUpHandler = &SnapshotTask{}
+)
 
 func NewSnapshotTask(height uint64, numOfValidators, validatorIndex int) *SnapshotTask {
 	snapshotTask := &SnapshotTask{
@@ -30,6 +35,11 @@ func NewSnapshotTask(height uint64, numOfValidators, validatorIndex int) *Snapsh
 		NumOfValidators: numOfValidators,
 		ValidatorIndex:  validatorIndex,
 	}
+	snapshotTask.SetRetryPolicy(&tasks.RetryPolicy{
+		MaxAttempts:    constants.SnapshotTaskMaxAttempts,
+		InitialBackoff: constants.SnapshotTaskRetryBackoff,
+		MaxBackoff:     constants.SnapshotTaskMaxRetryBackoff,
+	})
 	return snapshotTask
 }
 
@@ -214,3 +224,15 @@ func (t *SnapshotTask) ShouldExecute(ctx context.Context) (bool, *tasks.TaskErr)
 
 	return true, nil
 }
+
+// GiveUp logs that the snapshot was not submitted by this validator. Another
+// validator submits it once the desperation delay is over.
+func (t *SnapshotTask) GiveUp(err error) {
+	t.GetLogger().WithFields(
+		logrus.Fields{
+			"AliceNetHeight":  t.Height,
+			"numOfValidators": t.NumOfValidators,
+			"EthdkgIndex":     t.ValidatorIndex,
+		},
+	).WithError(err).Error("giving up on the snapshot")
+}
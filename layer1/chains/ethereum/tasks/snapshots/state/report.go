@@ -0,0 +1,123 @@
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants/dbprefix"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// MaxSnapshotReports is the maximum number of reports returned by
+// GetSnapshotReports.
+const MaxSnapshotReports = 100
+
+// SnapshotReport tells who was elected to submit the snapshot of an AliceNet
+// height and who actually submitted it. Block numbers are layer1 heights.
+type SnapshotReport struct {
+	Height uint32      `json:"height"`
+	Epoch  uint64      `json:"epoch"`
+	TxHash common.Hash `json:"txHash"`
+	// ElectionStart is the block of the previous snapshot, where the election
+	// of the submitter starts. It's 0 if unknown.
+	ElectionStart     uint64 `json:"electionStart"`
+	CommittedAt       uint64 `json:"committedAt"`
+	DesperationDelay  int    `json:"desperationDelay"`
+	DesperationFactor int    `json:"desperationFactor"`
+	// LeaderOrder are the validators in the order they were allowed to
+	// submit the snapshot.
+	LeaderOrder []common.Address `json:"leaderOrder"`
+	Submitter   common.Address   `json:"submitter"`
+	// SubmitterRank is the position of the submitter in LeaderOrder, or -1 if
+	// it's not there.
+	SubmitterRank int `json:"submitterRank"`
+	// SubmitterAllowedAt is the block the submitter was allowed to submit the
+	// snapshot at. It's 0 if unknown.
+	SubmitterAllowedAt uint64 `json:"submitterAllowedAt"`
+	GasUsed            uint64 `json:"gasUsed"`
+}
+
+// Latency returns the blocks between the election start and the snapshot
+// commit, or 0 if the election start is unknown.
+func (r *SnapshotReport) Latency() uint64 {
+	if r.ElectionStart == 0 || r.CommittedAt < r.ElectionStart {
+		return 0
+	}
+	return r.CommittedAt - r.ElectionStart
+}
+
+func snapshotReportKey(height uint32) []byte {
+	key := dbprefix.PrefixEthereumSnapshotReport()
+	return binary.BigEndian.AppendUint32(key, height)
+}
+
+// SaveSnapshotReport in the database, replacing the report of the same height.
+func SaveSnapshotReport(monDB *db.Database, report *SnapshotReport) error {
+	rawData, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return monDB.Update(func(txn *badger.Txn) error {
+		return utils.SetValue(txn, snapshotReportKey(report.Height), rawData)
+	})
+}
+
+// GetSnapshotReport of an AliceNet height. It returns badger.ErrKeyNotFound if
+// there's no report.
+func GetSnapshotReport(monDB *db.Database, height uint32) (*SnapshotReport, error) {
+	report := &SnapshotReport{}
+	err := monDB.View(func(txn *badger.Txn) error {
+		rawData, err := utils.GetValue(txn, snapshotReportKey(height))
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(rawData, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetSnapshotReports returns up to limit reports in height order, starting at
+// fromHeight, and the height the next page starts at, which is 0 once there are
+// no more reports.
+func GetSnapshotReports(monDB *db.Database, fromHeight uint32, limit int) ([]*SnapshotReport, uint32, error) {
+	if limit <= 0 || limit > MaxSnapshotReports {
+		limit = MaxSnapshotReports
+	}
+	reports := []*SnapshotReport{}
+	next := uint32(0)
+	err := monDB.View(func(txn *badger.Txn) error {
+		prefix := dbprefix.PrefixEthereumSnapshotReport()
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Seek(snapshotReportKey(fromHeight)); iter.ValidForPrefix(prefix); iter.Next() {
+			if len(reports) == limit {
+				next = binary.BigEndian.Uint32(iter.Item().Key()[len(prefix):])
+				break
+			}
+			rawData, err := iter.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			report := &SnapshotReport{}
+			if err := json.Unmarshal(rawData, report); err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, next, nil
+}
@@ -38,17 +38,17 @@ import (
 	"github.com/alicenet/alicenet/utils"
 )
 
-var HardHat *tests.Hardhat
+var Layer1Node tests.Layer1Node
 
 func TestMain(m *testing.M) {
-	hardhat, err := tests.StartHardHatNodeWithDefaultHost()
+	node, err := tests.StartLayer1Node(true)
 	if err != nil {
 		panic(err)
 	}
-	HardHat = hardhat
+	Layer1Node = node
 	code := 1
 	func() {
-		defer hardhat.Close()
+		defer node.Close()
 		code = m.Run()
 	}()
 	os.Exit(code)
@@ -57,7 +57,7 @@ func TestMain(m *testing.M) {
 func setupEthereum(t *testing.T, n int) *tests.ClientFixture {
 	t.Helper()
 	logger := logging.GetLogger("test").WithField("test", t.Name())
-	fixture := tests.NewClientFixture(HardHat, 0, n, logger, true, true, true)
+	fixture := tests.NewClientFixture(Layer1Node, 0, n, logger, true, true, true)
 	assert.NotNil(t, fixture)
 
 	eth := fixture.Client
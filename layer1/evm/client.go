@@ -14,11 +14,13 @@ import (
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
 	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/gas"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ethereum/go-ethereum/core/types"
 	eCrypto "github.com/ethereum/go-ethereum/crypto"
@@ -89,6 +91,26 @@ type Client struct {
 	chainID              *big.Int
 	txMaxGasFeeAllowed   *big.Int
 	endpointMinimumPeers uint64
+	gasOracle            gas.Oracle
+	gasStrategies        *gas.Strategies
+	gasBudget            *gas.Budget
+}
+
+// gasOracle falls back to a 1 GWEI tip on endpoints without
+// eth_maxPriorityFeePerGas.
+type gasOracle struct {
+	*ethclient.Client
+}
+
+func (o *gasOracle) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	tipCap, err := o.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		if err.Error() == ETH_MAX_PRIORITY_FEE_PER_GAS_NOT_FOUND {
+			return big.NewInt(1_000_000_000), nil
+		}
+		return nil, fmt.Errorf("could not get suggested gas tip cap: %w", err)
+	}
+	return tipCap, nil
 }
 
 // NewClient creates a new Ethereum abstraction.
@@ -121,6 +143,7 @@ func NewClient(
 		accounts:           make(map[common.Address]accountInfo),
 		finalityDelay:      finalityDelay,
 		txMaxGasFeeAllowed: txMaxGasFeeAllowedInWei,
+		gasStrategies:      &gas.Strategies{Default: gas.SuggestedStrategy{}},
 	}
 
 	// Load accounts + passCodes
@@ -163,6 +186,7 @@ func NewClient(
 	cl.rpcClient = rpcClient
 	ethClient := ethclient.NewClient(rpcClient)
 	cl.internalClient = ethClient
+	cl.gasOracle = &gasOracle{ethClient}
 	cl.chainID, err = ethClient.ChainID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Error in NewEndpoint at ethClient.ChainID: %v", err)
@@ -280,15 +304,33 @@ func (cl *Client) setDefaultAccount(acct accounts.Account) {
 	cl.defaultAccount = acct
 }
 
-// bump the tip cap for retries.
-func (cl *Client) bumpTipCap(gasTipCap *big.Int) *big.Int {
-	// calculate percentage% increase in GasTipCap
-	gasTipCapPercent := new(
-		big.Int,
-	).Mul(gasTipCap, big.NewInt(int64(constants.EthereumTipCapPercentageBump)))
-	gasTipCapPercent = new(big.Int).Div(gasTipCapPercent, big.NewInt(100))
-	resultTipCap := new(big.Int).Add(gasTipCap, gasTipCapPercent)
-	return resultTipCap
+// SetGasStrategies sets the strategies computing the tip of the
+// transactions. The strategy is chosen by the task in the context of the
+// calls, see gas.WithTask.
+func (cl *Client) SetGasStrategies(strategies *gas.Strategies) {
+	cl.gasStrategies = strategies
+}
+
+// SetGasBudget sets the budget limiting the fees spent per epoch. No
+// transaction is sent by the budget account once its budget is spent.
+func (cl *Client) SetGasBudget(budget *gas.Budget) {
+	cl.gasBudget = budget
+}
+
+// gasStrategy returns the strategy of the task in ctx.
+func (cl *Client) gasStrategy(ctx context.Context) gas.Strategy {
+	return cl.gasStrategies.For(gas.TaskFromContext(ctx))
+}
+
+// checkGasBudget returns an error if account spent its budget.
+func (cl *Client) checkGasBudget(account common.Address) error {
+	if cl.gasBudget == nil {
+		return nil
+	}
+	if err := cl.gasBudget.Allow(account); err != nil {
+		return &ErrTxTooExpensive{err.Error()}
+	}
+	return nil
 }
 
 // getSyncProgress returns a flag if we are syncing, a pointer to a struct if we are, or an error.
@@ -347,6 +389,34 @@ func (cl *Client) GetTransactionReceipt(
 	return cl.internalClient.TransactionReceipt(ctx, txHash)
 }
 
+// GetTransactionReceiptAndGasPrice returns the receipt of a transaction and
+// the effective gas price it paid, both from the same eth_getTransactionReceipt
+// call. The gas price is nil if the endpoint does not report it.
+func (cl *Client) GetTransactionReceiptAndGasPrice(
+	ctx context.Context,
+	txHash common.Hash,
+) (*types.Receipt, *big.Int, error) {
+	var raw json.RawMessage
+	if err := cl.rpcClient.CallContext(ctx, &raw, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+	rcpt := &types.Receipt{}
+	if err := json.Unmarshal(raw, rcpt); err != nil {
+		return nil, nil, err
+	}
+	// the receipt of go-ethereum does not decode the effective gas price yet
+	var fields struct {
+		EffectiveGasPrice *hexutil.Big `json:"effectiveGasPrice"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+	return rcpt, (*big.Int)(fields.EffectiveGasPrice), nil
+}
+
 func (cl *Client) GetInternalClient() *ethclient.Client {
 	return cl.internalClient
 }
@@ -542,7 +612,8 @@ func (cl *Client) GetTxMaxGasFeeAllowed() *big.Int {
 	return cl.txMaxGasFeeAllowed
 }
 
-// Get the base fee and suggestedGasTip for the latest ethereum block.
+// Get the base fee for the latest ethereum block and the tip computed by the
+// gas strategy of the task in ctx.
 func (cl *Client) GetBlockBaseFeeAndSuggestedGasTip(
 	ctx context.Context,
 ) (*big.Int, *big.Int, error) {
@@ -557,13 +628,9 @@ func (cl *Client) GetBlockBaseFeeAndSuggestedGasTip(
 		block.GasLimit())
 
 	baseFee := block.BaseFee()
-	tipCap, err := cl.internalClient.SuggestGasTipCap(ctx)
+	tipCap, err := cl.gasStrategy(ctx).TipCap(ctx, cl.gasOracle)
 	if err != nil {
-		if err.Error() == ETH_MAX_PRIORITY_FEE_PER_GAS_NOT_FOUND {
-			tipCap = big.NewInt(1_000_000_000)
-		} else {
-			return nil, nil, fmt.Errorf("could not get suggested gas tip cap: %w", err)
-		}
+		return nil, nil, err
 	}
 	return baseFee, tipCap, nil
 }
@@ -573,6 +640,9 @@ func (cl *Client) GetTransactionOpts(
 	ctx context.Context,
 	account accounts.Account,
 ) (*bind.TransactOpts, error) {
+	if err := cl.checkGasBudget(account.Address); err != nil {
+		return nil, err
+	}
 	opts, err := bind.NewKeyStoreTransactorWithChainID(cl.keystore, account, cl.chainID)
 	if err != nil {
 		return nil, fmt.Errorf("could not create transactor for %v: %v", account.Address.Hex(), err)
@@ -657,27 +727,22 @@ func (cl *Client) RetryTransaction(
 	if err != nil {
 		return nil, err
 	}
+	if err := cl.checkGasBudget(fromAddr); err != nil {
+		return nil, err
+	}
 
-	var newTipCap *big.Int
-
-	if tx.GasTipCap() == nil || gasTipCap.Cmp(tx.GasTipCap()) >= 0 {
-		newTipCap = gasTipCap
-	} else {
-		newTipCap = tx.GasTipCap()
+	// the tip of the strategy of the task that sent the tx, gasTipCap is the
+	// tip of the default strategy
+	strategy := cl.gasStrategy(ctx)
+	currentTipCap, err := strategy.TipCap(ctx, cl.gasOracle)
+	if err != nil {
+		cl.logger.Debugf("Could not compute the tip of the gas strategy, using %v: %v", gasTipCap, err)
+		currentTipCap = gasTipCap
 	}
 
 	// Increasing tip cap to replace old tx and make the tx more likely to be chosen
 	// by a layer1 miner
-	increasedTipCap := cl.bumpTipCap(newTipCap)
-
-	// In case we reach the new tip cap, we start to use the the suggested tip
-	// again. If we reached this point, our previous transaction may be already get
-	// pruned from the node and our tx will likely fail. So, we try to restart the
-	// gas tip and see if a transaction will be accepted in an attempt to pay less.
-	maxTipCap := new(big.Int).Mul(gasTipCap, big.NewInt(constants.EthereumMaxGasTipMultiplier))
-	if increasedTipCap.Cmp(maxTipCap) > 0 {
-		increasedTipCap = gasTipCap
-	}
+	increasedTipCap := strategy.BumpTipCap(tx.GasTipCap(), currentTipCap)
 
 	gasFeeCap, err := ComputeGasFeeCap(cl, baseFee, increasedTipCap)
 	if err != nil {
@@ -762,6 +827,9 @@ func (cl *Client) TransferNativeToken(
 	from, to common.Address,
 	wei *big.Int,
 ) (*types.Transaction, error) {
+	if err := cl.checkGasBudget(from); err != nil {
+		return nil, err
+	}
 	ctx, cancel := cl.GetTimeoutContext()
 	defer cancel()
 
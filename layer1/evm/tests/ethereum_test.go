@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/alicenet/alicenet/layer1/evm"
+	"github.com/alicenet/alicenet/layer1/tests"
 	"github.com/alicenet/alicenet/logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,11 +56,20 @@ func TestEthereum_SendTransactionAllAccountUnlocked(t *testing.T) {
 }
 
 func TestEthereum_HardhatNode(t *testing.T) {
-	isRunning, err := HardHat.IsHardHatRunning()
+	hardhat, ok := Layer1Node.(*tests.Hardhat)
+	if !ok {
+		t.Skipf("the layer1 node is not hardhat, run with %v=hardhat", tests.Layer1BackendEnv)
+	}
+	isRunning, err := hardhat.IsHardHatRunning()
 	assert.Nil(t, err)
 	assert.True(t, isRunning)
 }
 
+func TestEthereum_Layer1Node(t *testing.T) {
+	_, eth, _ := setupEthereum(t, 2, false)
+	assert.True(t, eth.IsAccessible())
+}
+
 func TestEthereum_NewEthereumEndpoint(t *testing.T) {
 	fixture, eth, _ := setupEthereum(t, 4, false)
 
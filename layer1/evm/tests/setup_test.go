@@ -13,7 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var HardHat *tests.Hardhat
+var Layer1Node tests.Layer1Node
 
 func setupEthereum(
 	t *testing.T,
@@ -24,7 +24,7 @@ func setupEthereum(
 	logging.GetLogger("ethereum").SetLevel(logrus.InfoLevel)
 	logger := logging.GetLogger("test").WithField("test", t.Name())
 
-	fixture := tests.NewClientFixture(HardHat, 0, n, logger, unlockAllAccounts, false, false)
+	fixture := tests.NewClientFixture(Layer1Node, 0, n, logger, unlockAllAccounts, false, false)
 	assert.NotNil(t, fixture)
 	eth := fixture.Client
 	assert.NotNil(t, eth)
@@ -39,14 +39,14 @@ func setupEthereum(
 }
 
 func TestMain(m *testing.M) {
-	hardhat, err := tests.StartHardHatNodeWithDefaultHost()
+	node, err := tests.StartLayer1Node(false)
 	if err != nil {
 		panic(err)
 	}
-	HardHat = hardhat
+	Layer1Node = node
 	code := 1
 	func() {
-		defer hardhat.Close()
+		defer node.Close()
 		code = m.Run()
 	}()
 	os.Exit(code)
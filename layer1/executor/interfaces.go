@@ -16,6 +16,15 @@ type TaskHandler interface {
 	CloseChan() <-chan struct{}
 }
 
+// TaskInspector to be implemented by the Handler that exposes the tasks of the
+// TaskManager to the admin clients.
+type TaskInspector interface {
+	ListTasks() ([]TaskInfo, error)
+	GetTask(id string) (TaskInfo, error)
+	KillTaskById(id string) (*HandlerResponse, error)
+	RetryTask(id string) error
+}
+
 // TaskResponse to be implemented by a response structure that will be returned to the
 // TaskHandler client.
 type TaskResponse interface {
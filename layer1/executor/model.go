@@ -8,6 +8,7 @@ import (
 	"github.com/alicenet/alicenet/layer1/executor/marshaller"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
 	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 //////////////////////////////////////////////////////////////////////////////////////////////
@@ -22,10 +23,14 @@ type TaskAction int
 // * KillByType          - To kill/prune all the tasks with the same type immediately
 // * KillById            - To kill/prune a task by id immediately
 // * Schedule            - To schedule a new task
+// * Inspect             - To get the TaskInfo of a task by id, or of all of them
+// * Retry               - To wake up a running task waiting to retry a failed attempt
 const (
 	KillByType TaskAction = iota
 	KillById
 	Schedule
+	Inspect
+	Retry
 )
 
 func (action TaskAction) String() string {
@@ -33,6 +38,8 @@ func (action TaskAction) String() string {
 		"KillByType",
 		"KillById",
 		"Schedule",
+		"Inspect",
+		"Retry",
 	}[action]
 }
 
@@ -114,6 +121,12 @@ type BaseRequest struct {
 	AllowMultiExecution bool                          `json:"allowMultiExecution"`
 	SubscribeOptions    *transaction.SubscribeOptions `json:"subscribeOptions"`
 	InternalState       InternalTaskState             `json:"internalState"`
+	// Dependencies are the ids of the tasks that still have to succeed before
+	// this task starts.
+	Dependencies []string           `json:"dependencies,omitempty"`
+	RetryPolicy  *tasks.RetryPolicy `json:"retryPolicy,omitempty"`
+	// FailedRuns counts the runs of the task that failed and were retried.
+	FailedRuns uint64 `json:"failedRuns"`
 }
 
 // ManagerRequestInfo used for controlling, recovering and managing a task request.
@@ -126,8 +139,10 @@ type ManagerRequestInfo struct {
 }
 
 // ManagerResponseInfo used to cache the responses from the TaskExecutor and to the Handler.
+// Request keeps the request information around after the task leaves the Schedule.
 type ManagerResponseInfo struct {
 	ExecutorResponse
+	Request         BaseRequest      `json:"request"`
 	HandlerResponse *HandlerResponse `json:"-"`
 	ReceivedOnBlock uint64           `json:"receivedOnBlock"`
 }
@@ -141,8 +156,9 @@ type requestStored struct {
 
 // responseStored for recovery.
 type responseStored struct {
-	ErrMsg          string `json:"errMsg"`
-	ReceivedOnBlock uint64 `json:"receivedOnBlock"`
+	ErrMsg          string      `json:"errMsg"`
+	Request         BaseRequest `json:"request"`
+	ReceivedOnBlock uint64      `json:"receivedOnBlock"`
 }
 
 // taskManagerBackup used to store requestStored and responseStored for recovery.
@@ -155,9 +171,26 @@ type taskManagerBackup struct {
 // managerResponse is used to communicate the Task response from TaskManager to Handler.
 type managerResponse struct {
 	HandlerResponse *HandlerResponse
+	Tasks           []TaskInfo
 	Err             error
 }
 
+// TaskInfo is a snapshot of a task request and of its execution that is shared
+// with the admin clients. Tasks that are no longer scheduled are Finished and
+// are reported until their response is cleaned from the TaskManager.
+type TaskInfo struct {
+	BaseRequest
+	Finished        bool
+	Err             string
+	ReceivedOnBlock uint64
+	// Attempts counts the calls to the task Prepare and Execute methods.
+	Attempts uint64
+	// LastErr is the error returned by the last attempt, if any.
+	LastErr string
+	// TxHash is the hash of the last transaction sent by the task, if any.
+	TxHash common.Hash
+}
+
 // ManagerResponseChannel is a non-blocking channel that can only be written and closed once.
 // It's used for communication between TaskManager and Handler.
 type ManagerResponseChannel struct {
@@ -181,10 +214,19 @@ func (rc *ManagerResponseChannel) sendResponse(response *managerResponse) {
 
 // listen until the response is received.
 func (rc *ManagerResponseChannel) listen(closeChan <-chan struct{}) (*HandlerResponse, error) {
+	response, err := rc.receive(closeChan)
+	if err != nil {
+		return nil, err
+	}
+	return response.HandlerResponse, response.Err
+}
+
+// receive the whole managerResponse.
+func (rc *ManagerResponseChannel) receive(closeChan <-chan struct{}) (*managerResponse, error) {
 	// wait for request to be processed
 	select {
 	case response := <-rc.channel:
-		return response.HandlerResponse, response.Err
+		return response, nil
 	case <-closeChan:
 		return nil, tasks.ErrTaskExecutionMechanismClosed
 	}
@@ -240,6 +282,10 @@ var (
 	ErrTaskTypeNotInRegistry      = errors.New("the task type is not in registry")
 	ErrTaskIdEmpty                = errors.New("the task id is empty")
 	ErrTaskKilledBeforeExecution  = errors.New("the task killed by request before execution")
+	ErrTaskNotRunning             = errors.New("the task is not running")
+	ErrUnknownDependency          = errors.New("the task depends on a task that is not scheduled")
+	ErrDependencyFailed           = errors.New("a dependency of the task failed")
+	ErrWrongRetryPolicy           = errors.New("the retry policy of the task allows no attempts")
 	ErrReceivedRequestClosedChan  = errors.New("received a request on a closed channel")
 	ErrReceivedResponseClosedChan = errors.New("received a taskResponse on a closed channel")
 )
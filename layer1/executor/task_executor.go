@@ -12,10 +12,12 @@ import (
 	"github.com/alicenet/alicenet/constants/dbprefix"
 	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/executor/tasks"
+	"github.com/alicenet/alicenet/layer1/gas"
 	"github.com/alicenet/alicenet/layer1/transaction"
 	"github.com/alicenet/alicenet/logging"
 	"github.com/alicenet/alicenet/utils"
 	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 )
@@ -23,6 +25,7 @@ import (
 type TaskExecutor struct {
 	sync.RWMutex
 	TxsBackup map[string]*types.Transaction `json:"transactionsBackup"`
+	attempts  map[string]*taskAttempts      `json:"-"`
 	closeChan chan struct{}                 `json:"-"`
 	closeOnce sync.Once                     `json:"-"`
 	txWatcher transaction.Watcher           `json:"-"`
@@ -38,6 +41,7 @@ func newTaskExecutor(
 ) (*TaskExecutor, error) {
 	taskExecutor := &TaskExecutor{
 		TxsBackup: make(map[string]*types.Transaction),
+		attempts:  make(map[string]*taskAttempts),
 		closeChan: make(chan struct{}),
 		closeOnce: sync.Once{},
 		txWatcher: txWatcher,
@@ -87,11 +91,92 @@ func (te *TaskExecutor) onError(err error) error {
 	return err
 }
 
+// taskAttempts keeps track of the execution attempts of a task. It's not
+// persisted, the count starts again when a task is recovered.
+type taskAttempts struct {
+	attempts uint64
+	lastErr  string
+	txHash   common.Hash
+	retry    chan struct{}
+}
+
+// startAttempts starts tracking the attempts of a task.
+func (te *TaskExecutor) startAttempts(uuid string) {
+	te.Lock()
+	defer te.Unlock()
+	if _, present := te.attempts[uuid]; !present {
+		te.attempts[uuid] = &taskAttempts{retry: make(chan struct{}, 1)}
+	}
+}
+
+// recordAttempt of a task with its result.
+func (te *TaskExecutor) recordAttempt(uuid string, err *tasks.TaskErr) {
+	te.Lock()
+	defer te.Unlock()
+	attempts, present := te.attempts[uuid]
+	if !present {
+		return
+	}
+	attempts.attempts++
+	attempts.lastErr = ""
+	if err != nil {
+		attempts.lastErr = err.Error()
+	}
+}
+
+// getAttempts returns the attempts count, the last attempt error and the last
+// transaction hash of a task.
+func (te *TaskExecutor) getAttempts(uuid string) (uint64, string, common.Hash, bool) {
+	te.RLock()
+	defer te.RUnlock()
+	attempts, present := te.attempts[uuid]
+	if !present {
+		return 0, "", common.Hash{}, false
+	}
+	return attempts.attempts, attempts.lastErr, attempts.txHash, true
+}
+
+// removeAttempts stops tracking the attempts of a task.
+func (te *TaskExecutor) removeAttempts(uuid string) {
+	te.Lock()
+	defer te.Unlock()
+	delete(te.attempts, uuid)
+}
+
+// retryTask wakes up a task that is waiting to retry, so it retries right away.
+func (te *TaskExecutor) retryTask(uuid string) error {
+	te.RLock()
+	defer te.RUnlock()
+	attempts, present := te.attempts[uuid]
+	if !present {
+		return ErrTaskNotRunning
+	}
+	select {
+	case attempts.retry <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// retryChan returns the channel used to wake up a task waiting to retry.
+func (te *TaskExecutor) retryChan(uuid string) <-chan struct{} {
+	te.RLock()
+	defer te.RUnlock()
+	attempts, present := te.attempts[uuid]
+	if !present {
+		return nil
+	}
+	return attempts.retry
+}
+
 // addTxBackup adds txn to the backup map for recovery.
 func (te *TaskExecutor) addTxBackup(uuid string, tx *types.Transaction) error {
 	te.Lock()
 	defer te.Unlock()
 	te.TxsBackup[uuid] = tx
+	if attempts, present := te.attempts[uuid]; present {
+		attempts.txHash = tx.Hash()
+	}
 	return te.persistState()
 }
 
@@ -172,7 +257,8 @@ func (te *TaskExecutor) processTask(
 	contracts layer1.AllSmartContracts,
 	taskResponseChan tasks.InternalTaskResponseChan,
 ) error {
-	taskCtx, cf := context.WithCancel(context.Background())
+	// the task name selects the gas strategy of the task transactions
+	taskCtx, cf := context.WithCancel(gas.WithTask(context.Background(), name))
 	defer cf()
 
 	err := task.Initialize(
@@ -191,6 +277,7 @@ func (te *TaskExecutor) processTask(
 	if err != nil {
 		return err
 	}
+	te.startAttempts(task.GetId())
 	retryDelay := constants.MonitorRetryDelay
 	isComplete := false
 	if txn, present := te.getTxBackup(task.GetId()); present {
@@ -238,6 +325,7 @@ func (te *TaskExecutor) prepareTask(
 		}
 
 		taskErr := task.Prepare(ctx)
+		te.recordAttempt(task.GetId(), taskErr)
 		// no errors or unrecoverable errors
 		if taskErr == nil {
 			return nil
@@ -269,6 +357,7 @@ func (te *TaskExecutor) executeTask(
 			return nil
 		}
 		txn, taskErr := task.Execute(ctx)
+		te.recordAttempt(task.GetId(), taskErr)
 		if taskErr != nil {
 			if taskErr.IsRecoverable() {
 				logger.Tracef("got a recoverable error during task.execute: %v", taskErr.Error())
@@ -461,7 +550,7 @@ func (te *TaskExecutor) loadState() error {
 
 }
 
-// sleepOrExit sleeps a certain amount of time.
+// sleepOrExit sleeps a certain amount of time, or until a retry is requested.
 // It fails in case the task is closed.
 func (te *TaskExecutor) sleepOrExit(task tasks.Task, delay time.Duration) error {
 	select {
@@ -469,6 +558,9 @@ func (te *TaskExecutor) sleepOrExit(task tasks.Task, delay time.Duration) error
 		return tasks.ErrTaskKilled
 	case <-te.closeChan:
 		return tasks.ErrTaskExecutionMechanismClosed
+	case <-te.retryChan(task.GetId()):
+		task.GetLogger().Debug("retry requested, waking up the task")
+		return nil
 	case <-time.After(delay):
 		return nil
 	}
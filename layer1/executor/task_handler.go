@@ -14,6 +14,7 @@ import (
 )
 
 var _ TaskHandler = &Handler{}
+var _ TaskInspector = &Handler{}
 
 type Handler struct {
 	manager        *TaskManager
@@ -120,6 +121,50 @@ func (h *Handler) KillTaskById(id string) (*HandlerResponse, error) {
 	return req.response.listen(h.closeChan)
 }
 
+// ListTasks returns the scheduled tasks and the ones that finished recently.
+func (h *Handler) ListTasks() ([]TaskInfo, error) {
+	return h.inspect("")
+}
+
+// GetTask returns the scheduled or recently finished task with the given id.
+func (h *Handler) GetTask(id string) (TaskInfo, error) {
+	if id == "" {
+		return TaskInfo{}, ErrTaskIdEmpty
+	}
+	taskList, err := h.inspect(id)
+	if err != nil {
+		return TaskInfo{}, err
+	}
+	return taskList[0], nil
+}
+
+// RetryTask sends the Retry Task request to the TaskManager. A running task
+// that is waiting after a failed attempt retries right away, instead of
+// waiting for the retry delay.
+func (h *Handler) RetryTask(id string) error {
+	req := managerRequest{id: id, action: Retry, response: NewManagerResponseChannel()}
+	err := h.waitForRequestProcessing(req)
+	if err != nil {
+		return err
+	}
+	_, err = req.response.listen(h.closeChan)
+	return err
+}
+
+// inspect sends the Inspect Task request to the TaskManager.
+func (h *Handler) inspect(id string) ([]TaskInfo, error) {
+	req := managerRequest{id: id, action: Inspect, response: NewManagerResponseChannel()}
+	err := h.waitForRequestProcessing(req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := req.response.receive(h.closeChan)
+	if err != nil {
+		return nil, err
+	}
+	return response.Tasks, response.Err
+}
+
 // waitForRequestProcessing or context deadline.
 func (h *Handler) waitForRequestProcessing(req managerRequest) error {
 	// wait for request to be accepted
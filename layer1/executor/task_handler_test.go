@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alicenet/alicenet/bridge/bindings"
 	"github.com/alicenet/alicenet/consensus/objs"
+	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/constants/dbprefix"
 	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/layer1"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/dkg/state"
@@ -25,6 +28,7 @@ import (
 	"github.com/dgraph-io/badger/v2"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -573,3 +577,382 @@ func TestTasksHandlerAndManager_SendNilResponseAndCloseRequestChan(t *testing.T)
 	<-time.After(100 * time.Millisecond)
 	require.Equal(t, 0, getScheduleLen(t, handler.manager))
 }
+
+func TestTasksHandlerAndManager_ListAndGetTasks(t *testing.T) {
+	handler, _, _, _, _ := getTaskHandler(t, true)
+	handler.Start()
+
+	task1 := dkg.NewCompletionTask(20, 40)
+	task1.AllowMultiExecution = true
+	taskId1 := uuid.New().String()
+	_, err := handler.ScheduleTask(task1, taskId1)
+	require.Nil(t, err)
+
+	task2 := dkg.NewCompletionTask(10, 40)
+	task2.AllowMultiExecution = true
+	taskId2 := uuid.New().String()
+	_, err = handler.ScheduleTask(task2, taskId2)
+	require.Nil(t, err)
+
+	taskList, err := handler.ListTasks()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(taskList))
+	require.Equal(t, taskId2, taskList[0].Id)
+	require.Equal(t, taskId1, taskList[1].Id)
+	require.Equal(t, "dkg.CompletionTask", taskList[0].Name)
+	require.Equal(t, NotStarted, taskList[0].InternalState)
+	require.False(t, taskList[0].Finished)
+
+	info, err := handler.GetTask(taskId1)
+	require.Nil(t, err)
+	require.Equal(t, uint64(20), info.Start)
+	require.Equal(t, uint64(40), info.End)
+
+	_, err = handler.GetTask("")
+	require.Equal(t, ErrTaskIdEmpty, err)
+	_, err = handler.GetTask("123")
+	require.Equal(t, ErrNotScheduled, err)
+
+	// killed tasks are still reported until their response is cleaned
+	_, err = handler.KillTaskById(taskId2)
+	require.Nil(t, err)
+	info, err = handler.GetTask(taskId2)
+	require.Nil(t, err)
+	require.True(t, info.Finished)
+	require.Equal(t, "dkg.CompletionTask", info.Name)
+	require.Equal(t, ErrTaskKilledBeforeExecution.Error(), info.Err)
+
+	require.Equal(t, ErrTaskNotRunning, handler.RetryTask(taskId1))
+	require.Equal(t, ErrNotScheduled, handler.RetryTask(taskId2))
+	require.Equal(t, ErrTaskIdEmpty, handler.RetryTask(""))
+}
+
+func TestTasksHandlerAndManager_RetryRunningTask(t *testing.T) {
+	handler, client, contracts, _, acc := getTaskHandler(t, true)
+	client.GetFinalizedHeightFunc.SetDefaultReturn(12, nil)
+	handler.Start()
+	dkgState := state.NewDkgState(acc)
+	dkgState.OnRegistrationOpened(
+		10,
+		40,
+		40,
+		1,
+	)
+	publicKey := [2]*big.Int{big.NewInt(0), big.NewInt(0)}
+	dkgState.TransportPublicKey = publicKey
+
+	err := state.SaveDkgState(handler.manager.database, dkgState)
+	require.Nil(t, err)
+
+	ethDkgMock := mocks.NewMockIETHDKG()
+	ethDkgMock.RegisterFunc.SetDefaultReturn(nil, errors.New("network error"))
+	ethDkgMock.GetNonceFunc.SetDefaultReturn(big.NewInt(1), nil)
+	participantState := bindings.Participant{
+		PublicKey: publicKey,
+		Nonce:     uint64(1),
+	}
+	ethDkgMock.GetParticipantInternalStateFunc.SetDefaultReturn(participantState, nil)
+
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.EthdkgFunc.SetDefaultReturn(ethDkgMock)
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	task := dkg.NewRegisterTask(10, 40)
+	taskId := uuid.New().String()
+	_, err = handler.ScheduleTask(task, taskId)
+	require.Nil(t, err)
+
+	// wait for the first execution attempt to fail
+	var info TaskInfo
+	failTime := time.After(
+		time.Duration(tasks.ManagerProcessingTime.Seconds()+float64(1)) * time.Second,
+	)
+	for info.Attempts < 2 {
+		select {
+		case <-failTime:
+			t.Fatal("didnt process task in time")
+		case <-time.After(10 * time.Millisecond):
+		}
+		info, err = handler.GetTask(taskId)
+		require.Nil(t, err)
+	}
+	require.Equal(t, Running, info.InternalState)
+	require.Contains(t, info.LastErr, "network error")
+
+	// the retry happens way before the retry delay
+	require.Nil(t, handler.RetryTask(taskId))
+	attempts := info.Attempts
+	failTime = time.After(constants.MonitorRetryDelay / 2)
+	for info.Attempts == attempts {
+		select {
+		case <-failTime:
+			t.Fatal("task was not retried")
+		case <-time.After(10 * time.Millisecond):
+		}
+		info, err = handler.GetTask(taskId)
+		require.Nil(t, err)
+	}
+}
+
+// gaveUp keeps the errors of the dependentTasks that were given up, by key.
+var gaveUp sync.Map
+
+// dependentTask is a task with a start condition and a give up hook.
+type dependentTask struct {
+	*tasks.BaseTask
+	Key          string `json:"key"`
+	Ready        bool   `json:"ready"`
+	ConditionErr string `json:"conditionErr"`
+}
+
+var (
+	_ tasks.Task            = &dependentTask{}
+	_ tasks.ConditionalTask = &dependentTask{}
+	_ tasks.GiveUpHandler   = &dependentTask{}
+)
+
+func newDependentTask(key string, start uint64, end uint64) *dependentTask {
+	return &dependentTask{BaseTask: tasks.NewBaseTask(start, end, true, nil), Key: key, Ready: true}
+}
+
+func (t *dependentTask) Prepare(ctx context.Context) *tasks.TaskErr {
+	return nil
+}
+
+func (t *dependentTask) Execute(ctx context.Context) (*types.Transaction, *tasks.TaskErr) {
+	return nil, nil
+}
+
+func (t *dependentTask) ShouldExecute(ctx context.Context) (bool, *tasks.TaskErr) {
+	return true, nil
+}
+
+func (t *dependentTask) CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *tasks.TaskErr) {
+	if t.ConditionErr != "" {
+		return false, tasks.NewTaskErr(t.ConditionErr, false)
+	}
+	return t.Ready, nil
+}
+
+func (t *dependentTask) GiveUp(err error) {
+	gaveUp.Store(t.Key, err)
+}
+
+// getTaskManager returns a TaskManager that is not started, so its methods can
+// be called directly.
+func getTaskManager(t *testing.T, height uint64) *TaskManager {
+	t.Helper()
+	handler, _, _, _, _ := getTaskHandler(t, true)
+	manager := handler.manager
+	manager.marshaller.RegisterInstanceType(&dependentTask{})
+	manager.LastHeightSeen = height
+	return manager
+}
+
+// finishTask sends the response of a running task to the manager.
+func finishTask(t *testing.T, manager *TaskManager, id string, err error) {
+	t.Helper()
+	task, present := manager.Schedule[id]
+	require.True(t, present)
+	task.InternalState = Running
+	manager.Schedule[id] = task
+	require.Nil(t, manager.processTaskResponse(ExecutorResponse{Id: id, Err: err}))
+}
+
+func getTaskIds(taskList []ManagerRequestInfo) []string {
+	ids := make([]string, 0, len(taskList))
+	for _, task := range taskList {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
+
+func TestTasksManager_Dependencies(t *testing.T) {
+	manager := getTaskManager(t, 12)
+
+	_, err := manager.schedule(newDependentTask("a", 10, 40), "a")
+	require.Nil(t, err)
+	taskB := newDependentTask("b", 10, 40)
+	taskB.SetDependencies("a")
+	_, err = manager.schedule(taskB, "b")
+	require.Nil(t, err)
+	taskC := newDependentTask("c", 10, 40)
+	taskC.SetDependencies("a", "b")
+	respC, err := manager.schedule(taskC, "c")
+	require.Nil(t, err)
+
+	unknown := newDependentTask("d", 10, 40)
+	unknown.SetDependencies("d")
+	_, err = manager.schedule(unknown, "d")
+	require.ErrorIs(t, err, ErrUnknownDependency)
+
+	toStart, _ := manager.findTasks()
+	require.Equal(t, []string{"a"}, getTaskIds(toStart))
+
+	finishTask(t, manager, "a", nil)
+	require.Empty(t, manager.Schedule["b"].Dependencies)
+	require.Equal(t, []string{"b"}, manager.Schedule["c"].Dependencies)
+	toStart, _ = manager.findTasks()
+	require.Equal(t, []string{"b"}, getTaskIds(toStart))
+
+	// the failure is propagated to the dependents
+	finishTask(t, manager, "b", errors.New("failed"))
+	require.Empty(t, manager.Schedule)
+	require.True(t, respC.IsReady())
+	require.ErrorIs(t, respC.GetResponseBlocking(context.Background()), ErrDependencyFailed)
+
+	// dependencies that already finished are resolved on schedule
+	taskE := newDependentTask("e", 10, 40)
+	taskE.SetDependencies("a")
+	_, err = manager.schedule(taskE, "e")
+	require.Nil(t, err)
+	require.Empty(t, manager.Schedule["e"].Dependencies)
+
+	taskF := newDependentTask("f", 10, 40)
+	taskF.SetDependencies("c")
+	_, err = manager.schedule(taskF, "f")
+	require.ErrorIs(t, err, ErrDependencyFailed)
+}
+
+func TestTasksManager_KillPrunesDependents(t *testing.T) {
+	manager := getTaskManager(t, 12)
+
+	_, err := manager.schedule(newDependentTask("a", 20, 40), "a")
+	require.Nil(t, err)
+	taskB := newDependentTask("b", 10, 40)
+	taskB.SetDependencies("a")
+	respB, err := manager.schedule(taskB, "b")
+	require.Nil(t, err)
+
+	require.Nil(t, manager.killTaskById("a"))
+	require.Empty(t, manager.Schedule)
+	err = respB.GetResponseBlocking(context.Background())
+	require.ErrorIs(t, err, ErrDependencyFailed)
+	require.Contains(t, err.Error(), ErrTaskKilledBeforeExecution.Error())
+}
+
+func TestTasksManager_RetryPolicy(t *testing.T) {
+	manager := getTaskManager(t, 10)
+
+	task := newDependentTask("retry", 0, 100)
+	task.SetRetryPolicy(&tasks.RetryPolicy{MaxAttempts: 3, InitialBackoff: 2, MaxBackoff: 3})
+	resp, err := manager.schedule(task, "retry")
+	require.Nil(t, err)
+
+	finishTask(t, manager, "retry", errors.New("failed"))
+	request := manager.Schedule["retry"]
+	require.Equal(t, uint64(12), request.Start)
+	require.Equal(t, uint64(1), request.FailedRuns)
+	require.Equal(t, NotStarted, request.InternalState)
+	require.NotSame(t, task, request.Task)
+	require.False(t, resp.IsReady())
+
+	toStart, _ := manager.findTasks()
+	require.Empty(t, toStart)
+	manager.LastHeightSeen = 20
+	toStart, _ = manager.findTasks()
+	require.Equal(t, []string{"retry"}, getTaskIds(toStart))
+
+	// the backoff doubles up to its cap
+	finishTask(t, manager, "retry", errors.New("failed"))
+	request = manager.Schedule["retry"]
+	require.Equal(t, uint64(23), request.Start)
+	require.Equal(t, uint64(2), request.FailedRuns)
+
+	finishTask(t, manager, "retry", errors.New("failed again"))
+	require.Empty(t, manager.Schedule)
+	require.True(t, resp.IsReady())
+	require.Equal(t, "failed again", resp.GetResponseBlocking(context.Background()).Error())
+	gaveUpErr, present := gaveUp.Load("retry")
+	require.True(t, present)
+	require.Equal(t, "failed again", gaveUpErr.(error).Error())
+
+	// killed tasks are neither retried nor given up
+	task = newDependentTask("killed", 0, 100)
+	task.SetRetryPolicy(&tasks.RetryPolicy{MaxAttempts: 3, InitialBackoff: 2})
+	_, err = manager.schedule(task, "killed")
+	require.Nil(t, err)
+	finishTask(t, manager, "killed", tasks.ErrTaskKilled)
+	require.Empty(t, manager.Schedule)
+	_, present = gaveUp.Load("killed")
+	require.False(t, present)
+
+	// retries past the end block are not scheduled
+	task = newDependentTask("late", 0, 25)
+	task.SetRetryPolicy(&tasks.RetryPolicy{MaxAttempts: 3, InitialBackoff: 5})
+	_, err = manager.schedule(task, "late")
+	require.Nil(t, err)
+	finishTask(t, manager, "late", errors.New("failed"))
+	require.Empty(t, manager.Schedule)
+
+	task = newDependentTask("noAttempts", 0, 100)
+	task.SetRetryPolicy(&tasks.RetryPolicy{})
+	_, err = manager.schedule(task, "noAttempts")
+	require.Equal(t, ErrWrongRetryPolicy, err)
+}
+
+func TestTasksManager_StartConditions(t *testing.T) {
+	manager := getTaskManager(t, 12)
+
+	waiting := newDependentTask("waiting", 0, 0)
+	waiting.Ready = false
+	_, err := manager.schedule(waiting, "waiting")
+	require.Nil(t, err)
+	_, err = manager.schedule(newDependentTask("ready", 0, 0), "ready")
+	require.Nil(t, err)
+	failing := newDependentTask("failing", 0, 0)
+	failing.ConditionErr = "never"
+	respFailing, err := manager.schedule(failing, "failing")
+	require.Nil(t, err)
+	_, err = manager.schedule(dkg.NewDisputeMissingRegistrationTask(10, 40), "unconditional")
+	require.Nil(t, err)
+
+	toStart, _ := manager.findTasks()
+	ready := getTaskIds(manager.checkStartConditions(toStart))
+	require.ElementsMatch(t, []string{"ready", "unconditional"}, ready)
+	require.Contains(t, manager.Schedule, "waiting")
+	require.NotContains(t, manager.Schedule, "failing")
+	require.Equal(t, "never", respFailing.GetResponseBlocking(context.Background()).Error())
+}
+
+func TestTasksManager_ETHDKGPhaseConditions(t *testing.T) {
+	manager := getTaskManager(t, 12)
+	ethDkgMock := mocks.NewMockIETHDKG()
+	ethDkgMock.GetETHDKGPhaseFunc.SetDefaultReturn(uint8(state.ShareDistribution), nil)
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.EthdkgFunc.SetDefaultReturn(ethDkgMock)
+	manager.contracts.(*mocks.MockAllSmartContracts).EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	_, err := manager.schedule(dkg.NewRegisterTask(10, 40), "register")
+	require.Nil(t, err)
+	_, err = manager.schedule(dkg.NewShareDistributionTask(10, 40), "shares")
+	require.Nil(t, err)
+	require.Equal(t, constants.ETHDKGTaskMaxAttempts, manager.Schedule["register"].RetryPolicy.MaxAttempts)
+
+	// the tasks only start in their phase
+	toStart, _ := manager.findTasks()
+	require.ElementsMatch(t, []string{"shares"}, getTaskIds(manager.checkStartConditions(toStart)))
+
+	ethDkgMock.GetETHDKGPhaseFunc.SetDefaultReturn(uint8(state.RegistrationOpen), nil)
+	require.ElementsMatch(t, []string{"register"}, getTaskIds(manager.checkStartConditions(toStart)))
+
+	// the phase can't be read, the tasks wait
+	ethDkgMock.GetETHDKGPhaseFunc.SetDefaultReturn(0, errors.New("network error"))
+	require.Empty(t, manager.checkStartConditions(toStart))
+	require.Equal(t, 2, len(manager.Schedule))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &tasks.RetryPolicy{InitialBackoff: 3}
+	require.Equal(t, uint64(3), policy.Backoff(1))
+	require.Equal(t, uint64(6), policy.Backoff(2))
+	require.Equal(t, uint64(24), policy.Backoff(4))
+
+	policy.MaxBackoff = 10
+	require.Equal(t, uint64(6), policy.Backoff(2))
+	require.Equal(t, uint64(10), policy.Backoff(3))
+	require.Equal(t, uint64(10), policy.Backoff(100))
+
+	policy = &tasks.RetryPolicy{InitialBackoff: 1}
+	require.Equal(t, uint64(1)<<63, policy.Backoff(1000))
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -177,8 +179,19 @@ func (tm *TaskManager) eventLoop() {
 				} else {
 					response.HandlerResponse = sharedResponse
 				}
+			case Inspect:
+				response.Tasks, response.Err = tm.inspectTasks(taskRequest.id)
+			case Retry:
+				err := tm.retryTask(taskRequest.id)
+				if err != nil {
+					tm.logger.WithError(err).Errorf("Failed to retryTask %v", taskRequest.id)
+					response.Err = err
+				}
 			}
 			taskRequest.response.sendResponse(response)
+			if taskRequest.action == Inspect {
+				continue
+			}
 			err := tm.persistState()
 			if err != nil {
 				tm.logger.WithError(err).Logger.Warn("persist state failed")
@@ -216,7 +229,7 @@ func (tm *TaskManager) eventLoop() {
 			tm.LastHeightSeen = height
 
 			toStart, expired := tm.findTasks()
-			err = tm.startTasks(toStart)
+			err = tm.startTasks(tm.checkStartConditions(toStart))
 			if err != nil {
 				tm.logger.WithError(err).Errorf("Failed to startTasks %d", tm.LastHeightSeen)
 			}
@@ -283,6 +296,19 @@ func (tm *TaskManager) schedule(task tasks.Task, id string) (*HandlerResponse, e
 			return nil, ErrTaskNotAllowMultipleExecutions
 		}
 
+		dependencies, err := tm.pendingDependencies(task)
+		if err != nil {
+			return nil, err
+		}
+
+		var retryPolicy *tasks.RetryPolicy
+		if retryable, ok := task.(tasks.RetryableTask); ok {
+			retryPolicy = retryable.GetRetryPolicy()
+			if retryPolicy != nil && retryPolicy.MaxAttempts == 0 {
+				return nil, ErrWrongRetryPolicy
+			}
+		}
+
 		taskReq := ManagerRequestInfo{
 			BaseRequest: BaseRequest{
 				Id:                  id,
@@ -292,12 +318,15 @@ func (tm *TaskManager) schedule(task tasks.Task, id string) (*HandlerResponse, e
 				AllowMultiExecution: task.GetAllowMultiExecution(),
 				SubscribeOptions:    task.GetSubscribeOptions(),
 				InternalState:       NotStarted,
+				Dependencies:        dependencies,
+				RetryPolicy:         retryPolicy,
 			},
 			Task: task,
 		}
 		tm.Schedule[id] = taskReq
 
 		taskResp := ManagerResponseInfo{
+			Request:         taskReq.BaseRequest,
 			HandlerResponse: newHandlerResponse(),
 		}
 		tm.Responses[id] = taskResp
@@ -307,6 +336,32 @@ func (tm *TaskManager) schedule(task tasks.Task, id string) (*HandlerResponse, e
 	}
 }
 
+// pendingDependencies returns the dependencies of a task that didn't succeed
+// yet. A task can only depend on tasks that were scheduled before it, so the
+// dependencies always form a DAG.
+func (tm *TaskManager) pendingDependencies(task tasks.Task) ([]string, error) {
+	dependent, ok := task.(tasks.DependentTask)
+	if !ok {
+		return nil, nil
+	}
+
+	var pending []string
+	for _, dependency := range dependent.GetDependencies() {
+		if _, scheduled := tm.Schedule[dependency]; scheduled {
+			pending = append(pending, dependency)
+			continue
+		}
+		resp, responded := tm.Responses[dependency]
+		if !responded {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDependency, dependency)
+		}
+		if resp.Err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrDependencyFailed, dependency, resp.Err)
+		}
+	}
+	return pending, nil
+}
+
 // processTaskResponse from the TaskExecutor and writes it to the HandlerResponse.
 func (tm *TaskManager) processTaskResponse(executorResponse ExecutorResponse) error {
 	select {
@@ -331,12 +386,18 @@ func (tm *TaskManager) processTaskResponse(executorResponse ExecutorResponse) er
 			return nil
 		}
 
+		logger := getTaskLoggerComplete(tm.logger, task)
+		if start, retry := tm.nextRun(task, executorResponse.Err); retry {
+			logger.Warnf("Task executed with error, retrying at block %d: %v", start, executorResponse.Err)
+			return tm.rescheduleTask(task, start)
+		}
+
 		taskResp.ReceivedOnBlock = tm.LastHeightSeen
 		taskResp.ExecutorResponse = executorResponse
+		taskResp.Request = task.BaseRequest
 		taskResp.HandlerResponse.writeResponse(executorResponse.Err)
 		tm.Responses[executorResponse.Id] = taskResp
 
-		logger := getTaskLoggerComplete(tm.logger, task)
 		if executorResponse.Err != nil {
 			if !errors.Is(executorResponse.Err, tasks.ErrTaskKilled) {
 				logger.Errorf("Task executed with error: %v", executorResponse.Err)
@@ -350,10 +411,147 @@ func (tm *TaskManager) processTaskResponse(executorResponse ExecutorResponse) er
 		if err != nil {
 			return err
 		}
+
+		if executorResponse.Err == nil {
+			tm.resolveDependents(executorResponse.Id)
+			return nil
+		}
+		if giveUpHandler, ok := task.Task.(tasks.GiveUpHandler); ok &&
+			!errors.Is(executorResponse.Err, tasks.ErrTaskKilled) {
+			giveUpHandler.GiveUp(executorResponse.Err)
+		}
+		return tm.failDependents(executorResponse.Id, executorResponse.Err)
+	}
+}
+
+// nextRun returns the block where a task that failed with err runs again, and
+// false if the task is not retried.
+func (tm *TaskManager) nextRun(task ManagerRequestInfo, err error) (uint64, bool) {
+	if err == nil || errors.Is(err, tasks.ErrTaskKilled) || task.RetryPolicy == nil {
+		return 0, false
+	}
+	failedRuns := task.FailedRuns + 1
+	if failedRuns >= task.RetryPolicy.MaxAttempts {
+		return 0, false
+	}
+	start := tm.LastHeightSeen + task.RetryPolicy.Backoff(failedRuns)
+	if task.End != 0 && start >= task.End {
+		return 0, false
+	}
+	return start, true
+}
+
+// rescheduleTask that failed to run again from the start block. The new run
+// uses a fresh instance of the task, recovered from the failed one as if the
+// node was restarted.
+func (tm *TaskManager) rescheduleTask(task ManagerRequestInfo, start uint64) error {
+	wrapped, err := func() (*marshaller.InstanceWrapper, error) {
+		task.Task.Lock()
+		defer task.Task.Unlock()
+		return tm.marshaller.WrapInstance(task.Task)
+	}()
+	if err != nil {
+		return err
+	}
+	newTask, err := tm.unwrapTask(wrapped)
+	if err != nil {
+		return err
+	}
+
+	// the transaction of the failed run is not watched anymore
+	err = tm.taskExecutor.removeTxBackup(task.Id)
+	if err != nil {
+		return err
+	}
+
+	task.Task = newTask
+	task.Start = start
+	task.FailedRuns++
+	task.InternalState = NotStarted
+	tm.Schedule[task.Id] = task
+	return nil
+}
+
+// resolveDependents removes a task that succeeded from the dependencies of the
+// scheduled tasks.
+func (tm *TaskManager) resolveDependents(id string) {
+	for dependentId, task := range tm.Schedule {
+		pending := make([]string, 0, len(task.Dependencies))
+		for _, dependency := range task.Dependencies {
+			if dependency != id {
+				pending = append(pending, dependency)
+			}
+		}
+		if len(pending) != len(task.Dependencies) {
+			task.Dependencies = pending
+			tm.Schedule[dependentId] = task
+		}
+	}
+}
+
+// failDependents prunes the scheduled tasks that depend on a task that failed.
+// The failure is propagated to the whole subgraph of dependents.
+func (tm *TaskManager) failDependents(id string, err error) error {
+	dependents := make([]ManagerRequestInfo, 0)
+	for _, task := range tm.Schedule {
+		for _, dependency := range task.Dependencies {
+			if dependency == id {
+				dependents = append(dependents, task)
+				break
+			}
+		}
+	}
+
+	for _, task := range dependents {
+		// a previous pruning may have removed the task already
+		if _, present := tm.Schedule[task.Id]; !present {
+			continue
+		}
+		getTaskLoggerComplete(tm.logger, task).Warnf("Dependency %s failed, pruning task", id)
+		pruneErr := tm.pruneTask(task, fmt.Errorf("%w: %s: %v", ErrDependencyFailed, id, err))
+		if pruneErr != nil {
+			return pruneErr
+		}
 	}
 	return nil
 }
 
+// checkStartConditions returns the tasks whose on-chain start condition holds.
+// Tasks without condition are always returned. The tasks whose condition
+// failed with an unrecoverable error are pruned.
+func (tm *TaskManager) checkStartConditions(taskList []ManagerRequestInfo) []ManagerRequestInfo {
+	ready := make([]ManagerRequestInfo, 0, len(taskList))
+	for _, task := range taskList {
+		conditional, ok := task.Task.(tasks.ConditionalTask)
+		if !ok {
+			ready = append(ready, task)
+			continue
+		}
+
+		networkCtx, networkCf := context.WithTimeout(context.Background(), tasks.ManagerNetworkTimeout)
+		canStart, taskErr := conditional.CanStart(networkCtx, tm.eth, tm.contracts)
+		networkCf()
+		logger := getTaskLoggerComplete(tm.logger, task)
+		if taskErr != nil {
+			if taskErr.IsRecoverable() {
+				logger.WithError(taskErr).Debug("Failed to check the task start condition")
+				continue
+			}
+			logger.WithError(taskErr).Error("Task start condition failed, pruning task")
+			if err := tm.pruneTask(task, taskErr); err != nil {
+				logger.WithError(err).Error("Failed to prune task")
+			}
+			continue
+		}
+		if canStart {
+			ready = append(ready, task)
+		} else {
+			logger.Trace("Task start condition doesn't hold yet")
+		}
+	}
+	return ready
+}
+
 // startTasks spawning a go routine to handle Task execution using the TaskExecutor.
 func (tm *TaskManager) startTasks(taskList []ManagerRequestInfo) error {
 	select {
@@ -464,28 +662,117 @@ func (tm *TaskManager) killTask(task ManagerRequestInfo) error {
 		getTaskLoggerComplete(tm.logger, task).Error("Task already killed")
 	} else {
 		getTaskLoggerComplete(tm.logger, task).Trace("Task is not running yet, pruning directly")
+		return tm.pruneTask(task, ErrTaskKilledBeforeExecution)
+	}
+	return nil
+}
+
+// pruneTask that is not running yet, writing err as its response. The tasks
+// depending on it are pruned as well.
+func (tm *TaskManager) pruneTask(task ManagerRequestInfo, err error) error {
+	taskResp, present := tm.Responses[task.Id]
+	if !present {
+		tm.logger.Warnf("response structure doesn't exist for a pruned task with id %s", task.Id)
+		taskResp.HandlerResponse = newHandlerResponse()
+	}
 
-		taskResp, present := tm.Responses[task.Id]
+	taskResp.ReceivedOnBlock = tm.LastHeightSeen
+	executorResponse := ExecutorResponse{
+		Id:  task.Id,
+		Err: err,
+	}
+	taskResp.ExecutorResponse = executorResponse
+	taskResp.Request = task.BaseRequest
+	taskResp.HandlerResponse.writeResponse(executorResponse.Err)
+	tm.Responses[executorResponse.Id] = taskResp
+
+	removeErr := tm.remove(task.Id)
+	if removeErr != nil {
+		tm.logger.WithError(removeErr).Errorf("Failed to prune task id: %s", task.Id)
+		return removeErr
+	}
+	return tm.failDependents(task.Id, err)
+}
+
+// retryTask wakes up a running task that is waiting to retry a failed attempt.
+func (tm *TaskManager) retryTask(id string) error {
+	select {
+	case <-tm.closeChan:
+		return tasks.ErrTaskExecutionMechanismClosed
+	default:
+		if id == "" {
+			return ErrTaskIdEmpty
+		}
+
+		task, present := tm.Schedule[id]
 		if !present {
-			tm.logger.Warnf("response structure doesn't exist for a received killing request with id %s", task.Id)
+			return ErrNotScheduled
+		}
+		if task.InternalState != Running {
+			return ErrTaskNotRunning
 		}
 
-		taskResp.ReceivedOnBlock = tm.LastHeightSeen
-		executorResponse := ExecutorResponse{
-			Id:  task.Id,
-			Err: ErrTaskKilledBeforeExecution,
+		tm.logger.Tracef("received request to retry task with id: %s", id)
+		return tm.taskExecutor.retryTask(id)
+	}
+}
+
+// inspectTasks returns the TaskInfo of the task with the given id, or of all
+// the scheduled and recently finished tasks sorted by start height if the id is
+// empty.
+func (tm *TaskManager) inspectTasks(id string) ([]TaskInfo, error) {
+	if id != "" {
+		info, err := tm.getTaskInfo(id)
+		if err != nil {
+			return nil, err
 		}
-		taskResp.ExecutorResponse = executorResponse
-		taskResp.HandlerResponse.writeResponse(executorResponse.Err)
-		tm.Responses[executorResponse.Id] = taskResp
+		return []TaskInfo{info}, nil
+	}
 
-		err := tm.remove(task.Id)
+	ids := make(map[string]struct{}, len(tm.Responses))
+	for id := range tm.Schedule {
+		ids[id] = struct{}{}
+	}
+	for id := range tm.Responses {
+		ids[id] = struct{}{}
+	}
+	infos := make([]TaskInfo, 0, len(ids))
+	for id := range ids {
+		info, err := tm.getTaskInfo(id)
 		if err != nil {
-			tm.logger.WithError(err).Errorf("Failed to kill task id: %s", task.Id)
-			return err
+			return nil, err
 		}
+		infos = append(infos, info)
 	}
-	return nil
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Start != infos[j].Start {
+			return infos[i].Start < infos[j].Start
+		}
+		return infos[i].Id < infos[j].Id
+	})
+	return infos, nil
+}
+
+// getTaskInfo from the Schedule, or from the Responses if the task is finished.
+func (tm *TaskManager) getTaskInfo(id string) (TaskInfo, error) {
+	request, scheduled := tm.Schedule[id]
+	response, responded := tm.Responses[id]
+	if !scheduled && !responded {
+		return TaskInfo{}, ErrNotScheduled
+	}
+
+	info := TaskInfo{BaseRequest: request.BaseRequest}
+	if !scheduled {
+		info.BaseRequest = response.Request
+		info.Finished = true
+		info.ReceivedOnBlock = response.ReceivedOnBlock
+		if response.Err != nil {
+			info.Err = response.Err.Error()
+		}
+	}
+	info.Id = id
+	info.Attempts, info.LastErr, info.TxHash, _ = tm.taskExecutor.getAttempts(id)
+	return info, nil
 }
 
 // cleanResponses after constants.TaskManagerResponseToleranceBeforeRemoving amount of blocks.
@@ -494,6 +781,7 @@ func (tm *TaskManager) cleanResponses() {
 		if resp.ReceivedOnBlock != 0 &&
 			resp.ReceivedOnBlock+tasks.ManagerResponseToleranceBeforeRemoving <= tm.LastHeightSeen {
 			delete(tm.Responses, id)
+			tm.taskExecutor.removeAttempts(id)
 		}
 	}
 }
@@ -520,7 +808,8 @@ func (tm *TaskManager) findTasks() ([]ManagerRequestInfo, []ManagerRequestInfo)
 
 		if ((taskRequest.Start == 0 && taskRequest.End == 0) ||
 			(taskRequest.Start != 0 && taskRequest.Start <= tm.LastHeightSeen && taskRequest.End == 0) ||
-			(taskRequest.Start <= tm.LastHeightSeen && taskRequest.End > tm.LastHeightSeen)) && taskRequest.InternalState == NotStarted {
+			(taskRequest.Start <= tm.LastHeightSeen && taskRequest.End > tm.LastHeightSeen)) &&
+			taskRequest.InternalState == NotStarted && len(taskRequest.Dependencies) == 0 {
 
 			toStart = append(toStart, taskRequest)
 			continue
@@ -671,6 +960,7 @@ func (tm *TaskManager) MarshalJSON() ([]byte, error) {
 
 	for k, v := range tm.Responses {
 		responseStored := responseStored{
+			Request:         v.Request,
 			ReceivedOnBlock: v.ReceivedOnBlock,
 		}
 
@@ -697,41 +987,33 @@ func (tm *TaskManager) UnmarshalJSON(raw []byte) error {
 		return err
 	}
 
-	adminInterface := reflect.TypeOf((*monitorInterfaces.AdminClient)(nil)).Elem()
-
 	tm.Schedule = make(map[string]ManagerRequestInfo)
 	tm.Responses = make(map[string]ManagerResponseInfo)
 	tm.LastHeightSeen = aa.LastHeightSeen
 	for k, v := range aa.Schedule {
-		t, err := tm.marshaller.UnwrapInstance(v.WrappedTask)
+		t, err := tm.unwrapTask(v.WrappedTask)
 		if err != nil {
 			return err
 		}
 
-		// Marshalling service handlers is mostly non-sense, so
-		isAdminClient := reflect.TypeOf(t).Implements(adminInterface)
-		if isAdminClient {
-			adminClient := t.(monitorInterfaces.AdminClient)
-			adminClient.SetAdminHandler(tm.adminHandler)
-		}
-
 		tm.Schedule[k] = ManagerRequestInfo{
 			BaseRequest: v.BaseRequest,
-			Task:        t.(tasks.Task),
+			Task:        t,
 			killedAt:    v.killedAt,
 		}
 	}
 
 	for k, v := range aa.Responses {
 		resp := ManagerResponseInfo{
+			Request:         v.Request,
 			ReceivedOnBlock: v.ReceivedOnBlock,
 			HandlerResponse: newHandlerResponse(),
 		}
 
 		if v.ReceivedOnBlock != 0 {
-			resp.ExecutorResponse = ExecutorResponse{
-				Id:  k,
-				Err: errors.New(v.ErrMsg),
+			resp.ExecutorResponse = ExecutorResponse{Id: k}
+			if v.ErrMsg != "" {
+				resp.ExecutorResponse.Err = errors.New(v.ErrMsg)
 			}
 			resp.HandlerResponse.writeResponse(resp.ExecutorResponse.Err)
 		}
@@ -741,6 +1023,24 @@ func (tm *TaskManager) UnmarshalJSON(raw []byte) error {
 	return nil
 }
 
+// unwrapTask recovers a task from its wrapper, setting back the services that
+// are not marshalled.
+func (tm *TaskManager) unwrapTask(wrapped *marshaller.InstanceWrapper) (tasks.Task, error) {
+	t, err := tm.marshaller.UnwrapInstance(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshalling service handlers is mostly non-sense, so
+	adminInterface := reflect.TypeOf((*monitorInterfaces.AdminClient)(nil)).Elem()
+	isAdminClient := reflect.TypeOf(t).Implements(adminInterface)
+	if isAdminClient {
+		adminClient := t.(monitorInterfaces.AdminClient)
+		adminClient.SetAdminHandler(tm.adminHandler)
+	}
+	return t.(tasks.Task), nil
+}
+
 // getTaskLoggerComplete with all the fields.
 func getTaskLoggerComplete(logger *logrus.Entry, taskReq ManagerRequestInfo) *logrus.Entry {
 	return logger.WithFields(logrus.Fields{
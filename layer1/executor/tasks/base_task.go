@@ -27,7 +27,12 @@ type BaseTask struct {
 	// Which block the task should be ended. In case the end is 0 the task runs
 	// forever (until the task succeeds, or it's killed, be careful when using this).
 	// Otherwise, the task will end at the specified block.
-	End              uint64                   `json:"end"`
+	End uint64 `json:"end"`
+	// Ids of the tasks that have to succeed before this task starts.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// How the task is run again when it fails. In case nil, the task is not
+	// retried.
+	RetryPolicy      *RetryPolicy             `json:"retryPolicy,omitempty"`
 	isInitialized    bool                     `json:"-"`
 	killChan         chan struct{}            `json:"-"`
 	killOnce         sync.Once                `json:"-"`
@@ -38,6 +43,11 @@ type BaseTask struct {
 	taskResponseChan InternalTaskResponseChan `json:"-"`
 }
 
+var (
+	_ DependentTask = &BaseTask{}
+	_ RetryableTask = &BaseTask{}
+)
+
 // NewBaseTask creates a new Base task. BaseTask should be the base of any task.
 // This function is called outside the scheduler to create the object to be
 // scheduled.
@@ -151,6 +161,47 @@ func (bt *BaseTask) GetSubscribeOptions() *transaction.SubscribeOptions {
 	return &subscribeOptionsClone
 }
 
+// SetDependencies sets the ids of the tasks that have to succeed before this
+// task starts. It should be called before scheduling the task.
+func (bt *BaseTask) SetDependencies(ids ...string) {
+	bt.mutex.Lock()
+	defer bt.mutex.Unlock()
+	bt.Dependencies = append([]string(nil), ids...)
+}
+
+// GetDependencies gets the ids of the tasks that have to succeed before this
+// task starts.
+func (bt *BaseTask) GetDependencies() []string {
+	bt.mutex.RLock()
+	defer bt.mutex.RUnlock()
+	return append([]string(nil), bt.Dependencies...)
+}
+
+// SetRetryPolicy sets how the task is run again when it fails. It should be
+// called before scheduling the task.
+func (bt *BaseTask) SetRetryPolicy(policy *RetryPolicy) {
+	bt.mutex.Lock()
+	defer bt.mutex.Unlock()
+	if policy == nil {
+		bt.RetryPolicy = nil
+		return
+	}
+	policyClone := *policy
+	bt.RetryPolicy = &policyClone
+}
+
+// GetRetryPolicy gets how the task is run again when it fails. In case nil,
+// the task is not retried.
+func (bt *BaseTask) GetRetryPolicy() *RetryPolicy {
+	bt.mutex.RLock()
+	defer bt.mutex.RUnlock()
+	if bt.RetryPolicy == nil {
+		return nil
+	}
+	policyClone := *bt.RetryPolicy
+	return &policyClone
+}
+
 // GetClient returns the layer1 client implemented by the task.
 func (bt *BaseTask) GetClient() layer1.Client {
 	bt.mutex.RLock()
@@ -30,6 +30,21 @@ func NewSimpleExampleTask(start uint64, end uint64) *SimpleExampleTask {
 		BaseTask: tasks.NewBaseTask(start, end, false, nil),
 		Foo:      0,
 	}
+
+	// OPTIONAL: instead of checking in `ShouldExecute` if the previous tasks of a
+	// chain were done, a task can declare the ids of the tasks that have to succeed
+	// before it starts. The task manager can also run the task again a few blocks
+	// later in case it fails. E.g
+
+	/*
+		exampleTask.SetDependencies(previousTaskId)
+		exampleTask.SetRetryPolicy(&tasks.RetryPolicy{MaxAttempts: 3, InitialBackoff: 5, MaxBackoff: 20})
+	*/
+
+	// Tasks can also wait for an on-chain condition implementing
+	// `tasks.ConditionalTask`, and react to a failure that is not retried anymore
+	// implementing `tasks.GiveUpHandler`.
+
 	return exampleTask
 }
 
@@ -39,6 +39,34 @@ type TaskState interface {
 	LoadState(txn *badger.Txn) error
 }
 
+// DependentTask to be implemented by the tasks that can only start after other
+// tasks, identified by their ids, succeeded.
+type DependentTask interface {
+	GetDependencies() []string
+}
+
+// ConditionalTask to be implemented by the tasks that can only start once an
+// on-chain condition holds. The TaskManager checks CanStart on every
+// processing round after the task start block, until it returns true. The task
+// is not initialized yet when CanStart is called. In case of unrecoverable
+// errors the task is dropped.
+type ConditionalTask interface {
+	CanStart(ctx context.Context, eth layer1.Client, contracts layer1.AllSmartContracts) (bool, *TaskErr)
+}
+
+// RetryableTask to be implemented by the tasks that are run again by the
+// TaskManager when they fail.
+type RetryableTask interface {
+	GetRetryPolicy() *RetryPolicy
+}
+
+// GiveUpHandler to be implemented by the tasks that react to a failure that is
+// not retried anymore. GiveUp is called from the TaskManager loop, so it
+// should return quickly.
+type GiveUpHandler interface {
+	GiveUp(err error)
+}
+
 // InternalTaskResponseChan to be implemented by a response channel used
 // for communication between the TaskManager and TaskExecutor.
 type InternalTaskResponseChan interface {
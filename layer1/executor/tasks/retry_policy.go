@@ -0,0 +1,30 @@
+package tasks
+
+// RetryPolicy tells the TaskManager how to run again a task that failed with
+// an unrecoverable error or a stale transaction. Backoffs are in layer1 blocks.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of runs of the task, the first one
+	// included.
+	MaxAttempts uint64 `json:"maxAttempts"`
+	// InitialBackoff is the number of blocks waited before the first retry. The
+	// backoff doubles on every following retry.
+	InitialBackoff uint64 `json:"initialBackoff"`
+	// MaxBackoff caps the backoff. In case 0, the backoff has no cap.
+	MaxBackoff uint64 `json:"maxBackoff"`
+}
+
+// Backoff returns the number of blocks to wait before running the task again
+// after failedRuns failed runs.
+func (p *RetryPolicy) Backoff(failedRuns uint64) uint64 {
+	backoff := p.InitialBackoff
+	for i := uint64(1); i < failedRuns; i++ {
+		if backoff > ^uint64(0)/2 || (p.MaxBackoff != 0 && backoff >= p.MaxBackoff) {
+			break
+		}
+		backoff *= 2
+	}
+	if p.MaxBackoff != 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
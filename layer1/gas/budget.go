@@ -0,0 +1,252 @@
+package gas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants/dbprefix"
+	"github.com/alicenet/alicenet/utils"
+)
+
+var ErrBudgetExceeded = errors.New("epoch gas budget exceeded")
+
+// BudgetStatus is the spend of the current epoch.
+type BudgetStatus struct {
+	Account common.Address
+	Epoch   uint32
+	Limit   *big.Int
+	Spent   *big.Int
+	// Reserved is the worst case fee of the transactions that are not mined
+	// yet.
+	Reserved *big.Int
+}
+
+// budgetState is the part of the Budget persisted in the monitor database.
+type budgetState struct {
+	Epoch    uint32                   `json:"epoch"`
+	Spent    *big.Int                 `json:"spent"`
+	Reserved map[common.Hash]*big.Int `json:"reserved"`
+	Alerted  bool                     `json:"alerted"`
+}
+
+// Budget limits the fees an account spends per AliceNet epoch. The worst case
+// fee of a transaction is reserved when it is submitted, and replaced by the
+// fee it paid once it is mined. The spend is persisted in the monitor
+// database, so it survives a restart of the node.
+type Budget struct {
+	sync.Mutex
+	account         common.Address
+	limit           *big.Int
+	alertPercentage int64
+	currentEpoch    func() (uint32, error)
+	database        *db.Database
+	logger          *logrus.Entry
+
+	state budgetState
+}
+
+// NewBudget creates a Budget of limitInGwei per epoch for account. A warning
+// is logged once alertPercentage of the budget is spent. currentEpoch returns
+// the latest AliceNet epoch known by the node. The spend is loaded from and
+// persisted to database, if not nil.
+func NewBudget(account common.Address, limitInGwei uint64, alertPercentage uint64, currentEpoch func() (uint32, error), database *db.Database, logger *logrus.Entry) (*Budget, error) {
+	b := &Budget{
+		account:         account,
+		limit:           new(big.Int).Mul(new(big.Int).SetUint64(limitInGwei), gwei),
+		alertPercentage: int64(alertPercentage),
+		currentEpoch:    currentEpoch,
+		database:        database,
+		logger:          logger,
+		state: budgetState{
+			Spent:    new(big.Int),
+			Reserved: make(map[common.Hash]*big.Int),
+		},
+	}
+	if err := b.loadState(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Allow returns ErrBudgetExceeded if account spent or reserved its budget for
+// the current epoch. Other accounts are always allowed.
+func (b *Budget) Allow(account common.Address) error {
+	if account != b.account {
+		return nil
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.advance()
+	committed := b.committed()
+	if committed.Cmp(b.limit) >= 0 {
+		return fmt.Errorf("%w: spent and reserved %v of %v wei in epoch %v", ErrBudgetExceeded, committed, b.limit, b.state.Epoch)
+	}
+	return nil
+}
+
+// Reserve reserves maxFee, the worst case fee of a transaction of account
+// just submitted, until the transaction is mined or dropped.
+func (b *Budget) Reserve(account common.Address, txHash common.Hash, maxFee *big.Int) {
+	if account != b.account || maxFee == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.advance()
+	b.state.Reserved[txHash] = new(big.Int).Set(maxFee)
+	b.checkLimit()
+	b.persistState()
+}
+
+// Release drops the reservation of a transaction that was not mined.
+func (b *Budget) Release(txHash common.Hash) {
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.state.Reserved[txHash]; !ok {
+		return
+	}
+	delete(b.state.Reserved, txHash)
+	b.persistState()
+}
+
+// Record adds the fees paid by a mined transaction of account to the spend of
+// the current epoch and drops its reservation. If the fees are unknown, the
+// reserved worst case fee is spent instead.
+func (b *Budget) Record(account common.Address, txHash common.Hash, fees *big.Int) {
+	if account != b.account {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.advance()
+	reserved, ok := b.state.Reserved[txHash]
+	delete(b.state.Reserved, txHash)
+	switch {
+	case fees != nil:
+		b.state.Spent.Add(b.state.Spent, fees)
+	case ok:
+		b.state.Spent.Add(b.state.Spent, reserved)
+	}
+	b.checkLimit()
+	b.persistState()
+}
+
+// Status returns the spend of the current epoch.
+func (b *Budget) Status() BudgetStatus {
+	b.Lock()
+	defer b.Unlock()
+	b.advance()
+	return BudgetStatus{
+		Account:  b.account,
+		Epoch:    b.state.Epoch,
+		Limit:    new(big.Int).Set(b.limit),
+		Spent:    new(big.Int).Set(b.state.Spent),
+		Reserved: b.reserved(),
+	}
+}
+
+// checkLimit logs when the committed fees cross the alert percentage or the
+// limit of the budget.
+func (b *Budget) checkLimit() {
+	committed := b.committed()
+	logger := b.logger.WithFields(logrus.Fields{
+		"Epoch":    b.state.Epoch,
+		"Spent":    b.state.Spent.String(),
+		"Reserved": b.reserved().String(),
+		"Limit":    b.limit.String(),
+	})
+	if committed.Cmp(b.limit) >= 0 {
+		logger.Error("Epoch gas budget exhausted, no transaction is sent until the next epoch")
+		return
+	}
+	if !b.state.Alerted && b.alertPercentage > 0 && committed.Cmp(percentage(b.limit, b.alertPercentage)) >= 0 {
+		b.state.Alerted = true
+		logger.Warnf("%v%% of the epoch gas budget spent or reserved", b.alertPercentage)
+	}
+}
+
+// reserved returns the sum of the reservations.
+func (b *Budget) reserved() *big.Int {
+	total := new(big.Int)
+	for _, fee := range b.state.Reserved {
+		total.Add(total, fee)
+	}
+	return total
+}
+
+// committed returns the fees spent and reserved in the current epoch.
+func (b *Budget) committed() *big.Int {
+	return new(big.Int).Add(b.state.Spent, b.reserved())
+}
+
+// advance resets the spend when a new epoch starts. The reservations are kept
+// since their transactions may be mined in the new epoch. If the epoch is
+// unknown the spend stays in the last known epoch.
+func (b *Budget) advance() {
+	epoch, err := b.currentEpoch()
+	if err != nil {
+		b.logger.Debugf("Could not get the current epoch: %v", err)
+		return
+	}
+	if epoch > b.state.Epoch {
+		b.state.Epoch = epoch
+		b.state.Spent = new(big.Int)
+		b.state.Alerted = false
+		b.persistState()
+	}
+}
+
+// loadState loads the spend persisted by a previous run of the node.
+func (b *Budget) loadState() error {
+	if b.database == nil {
+		return nil
+	}
+	return b.database.View(func(txn *badger.Txn) error {
+		rawData, err := utils.GetValue(txn, dbprefix.PrefixGasBudgetState())
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		state := budgetState{}
+		if err := json.Unmarshal(rawData, &state); err != nil {
+			return err
+		}
+		if state.Spent == nil {
+			state.Spent = new(big.Int)
+		}
+		if state.Reserved == nil {
+			state.Reserved = make(map[common.Hash]*big.Int)
+		}
+		b.state = state
+		return nil
+	})
+}
+
+// persistState saves the spend in the database. A failure is only logged,
+// the budget keeps working from memory.
+func (b *Budget) persistState() {
+	if b.database == nil {
+		return
+	}
+	rawData, err := json.Marshal(b.state)
+	if err != nil {
+		b.logger.Warnf("Could not marshal the gas budget: %v", err)
+		return
+	}
+	err = b.database.Update(func(txn *badger.Txn) error {
+		return utils.SetValue(txn, dbprefix.PrefixGasBudgetState(), rawData)
+	})
+	if err != nil {
+		b.logger.Warnf("Could not persist the gas budget: %v", err)
+	}
+}
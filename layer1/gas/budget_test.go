@@ -0,0 +1,123 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/utils"
+)
+
+func newTestDB(t *testing.T) *db.Database {
+	t.Helper()
+	rawDB, err := utils.OpenBadger(context.Background().Done(), "", true)
+	require.Nil(t, err)
+	t.Cleanup(func() { rawDB.Close() })
+	database := &db.Database{}
+	database.Init(rawDB)
+	return database
+}
+
+func TestBudget(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	epoch := uint32(1)
+	var epochErr error
+	budget, err := NewBudget(account, 10, 50, func() (uint32, error) { return epoch, epochErr }, nil, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+
+	assert.Nil(t, budget.Allow(account))
+	budget.Record(account, common.Hash{1}, big.NewInt(6_000_000_000))
+	budget.Record(other, common.Hash{2}, big.NewInt(100_000_000_000))
+	assert.Nil(t, budget.Allow(account))
+	assert.True(t, budget.state.Alerted)
+
+	budget.Record(account, common.Hash{3}, big.NewInt(4_000_000_000))
+	assert.ErrorIs(t, budget.Allow(account), ErrBudgetExceeded)
+	assert.Nil(t, budget.Allow(other))
+	status := budget.Status()
+	assert.Equal(t, uint32(1), status.Epoch)
+	assert.Equal(t, big.NewInt(10_000_000_000), status.Spent)
+	assert.Equal(t, big.NewInt(10_000_000_000), status.Limit)
+
+	// the spend stays in the last known epoch if the epoch is unknown
+	epoch, epochErr = 2, errors.New("no state")
+	assert.ErrorIs(t, budget.Allow(account), ErrBudgetExceeded)
+
+	epochErr = nil
+	assert.Nil(t, budget.Allow(account))
+	status = budget.Status()
+	assert.Equal(t, uint32(2), status.Epoch)
+	assert.Equal(t, 0, status.Spent.Sign())
+	assert.False(t, budget.state.Alerted)
+}
+
+func TestBudgetReservations(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	epoch := uint32(1)
+	budget, err := NewBudget(account, 10, 0, func() (uint32, error) { return epoch, nil }, nil, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+
+	// the worst case fee of submitted transactions counts against the budget
+	budget.Reserve(account, common.Hash{1}, big.NewInt(6_000_000_000))
+	budget.Reserve(account, common.Hash{2}, big.NewInt(4_000_000_000))
+	assert.ErrorIs(t, budget.Allow(account), ErrBudgetExceeded)
+	status := budget.Status()
+	assert.Equal(t, 0, status.Spent.Sign())
+	assert.Equal(t, big.NewInt(10_000_000_000), status.Reserved)
+
+	// a mined transaction spends what it paid instead of its reservation
+	budget.Record(account, common.Hash{1}, big.NewInt(1_000_000_000))
+	assert.Nil(t, budget.Allow(account))
+	status = budget.Status()
+	assert.Equal(t, big.NewInt(1_000_000_000), status.Spent)
+	assert.Equal(t, big.NewInt(4_000_000_000), status.Reserved)
+
+	// the reservation is spent when the fees of a mined transaction are unknown
+	budget.Reserve(account, common.Hash{3}, big.NewInt(2_000_000_000))
+	budget.Record(account, common.Hash{3}, nil)
+	assert.Equal(t, big.NewInt(3_000_000_000), budget.Status().Spent)
+
+	// a dropped transaction releases its reservation
+	budget.Release(common.Hash{2})
+	assert.Equal(t, 0, budget.Status().Reserved.Sign())
+
+	// pending reservations are kept in the next epoch
+	budget.Reserve(account, common.Hash{4}, big.NewInt(2_000_000_000))
+	epoch = 2
+	status = budget.Status()
+	assert.Equal(t, 0, status.Spent.Sign())
+	assert.Equal(t, big.NewInt(2_000_000_000), status.Reserved)
+}
+
+func TestBudgetPersisted(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	epoch := uint32(3)
+	currentEpoch := func() (uint32, error) { return epoch, nil }
+	database := newTestDB(t)
+	budget, err := NewBudget(account, 10, 0, currentEpoch, database, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+	budget.Record(account, common.Hash{1}, big.NewInt(5_000_000_000))
+	budget.Reserve(account, common.Hash{2}, big.NewInt(5_000_000_000))
+
+	// the spend survives a restart of the node
+	restarted, err := NewBudget(account, 10, 0, currentEpoch, database, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+	assert.ErrorIs(t, restarted.Allow(account), ErrBudgetExceeded)
+	status := restarted.Status()
+	assert.Equal(t, uint32(3), status.Epoch)
+	assert.Equal(t, big.NewInt(5_000_000_000), status.Spent)
+	assert.Equal(t, big.NewInt(5_000_000_000), status.Reserved)
+
+	restarted.Release(common.Hash{2})
+	restarted, err = NewBudget(account, 10, 0, currentEpoch, database, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+	assert.Nil(t, restarted.Allow(account))
+}
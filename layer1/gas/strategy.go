@@ -0,0 +1,244 @@
+// Package gas computes the fees of the layer1 transactions sent by the node
+// and keeps the fees it spends within a budget.
+//
+// The tip of a transaction is computed by a Strategy, which can be chosen per
+// task type. The executor runs every task with its name in the context, so the
+// client sending a transaction picks the strategy of the task it is sent for.
+package gas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+)
+
+var ErrInvalidStrategy = errors.New("invalid gas strategy")
+
+// Names of the strategies in the configuration.
+const (
+	SuggestedStrategyName   = "suggested"
+	FeeHistoryStrategyName  = "feeHistory"
+	FixedStrategyName       = "fixed"
+	ExponentialStrategyName = "exponential"
+)
+
+var gwei = big.NewInt(1_000_000_000)
+
+// Oracle is the part of the layer1 endpoint queried by the strategies.
+type Oracle interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// Strategy computes the tip cap of the layer1 transactions.
+type Strategy interface {
+	// TipCap returns the tip cap of a new transaction.
+	TipCap(ctx context.Context, oracle Oracle) (*big.Int, error)
+	// BumpTipCap returns the tip cap of a transaction replacing a stale one
+	// sent with previous, given the tip cap the strategy computes now.
+	BumpTipCap(previous, current *big.Int) *big.Int
+}
+
+// SuggestedStrategy uses the tip suggested by the endpoint. Retries raise the
+// tip by constants.EthereumTipCapPercentageBump, and start again from the
+// suggested tip once it is constants.EthereumMaxGasTipMultiplier times higher.
+type SuggestedStrategy struct{}
+
+var _ Strategy = SuggestedStrategy{}
+
+func (SuggestedStrategy) TipCap(ctx context.Context, oracle Oracle) (*big.Int, error) {
+	return oracle.SuggestGasTipCap(ctx)
+}
+
+func (SuggestedStrategy) BumpTipCap(previous, current *big.Int) *big.Int {
+	tipCap := current
+	if previous != nil && previous.Cmp(current) > 0 {
+		tipCap = previous
+	}
+	bumped := percentage(tipCap, 100+constants.EthereumTipCapPercentageBump)
+
+	// If we reached this point, our previous transaction may have been pruned
+	// from the endpoint already, so we try again with the current tip in an
+	// attempt to pay less.
+	maxTipCap := new(big.Int).Mul(current, big.NewInt(constants.EthereumMaxGasTipMultiplier))
+	if bumped.Cmp(maxTipCap) > 0 {
+		return current
+	}
+	return bumped
+}
+
+// FeeHistoryStrategy uses the median over the latest Blocks blocks of the
+// Percentile of the tips paid in each block. Retries are bumped like the
+// SuggestedStrategy.
+type FeeHistoryStrategy struct {
+	Blocks     uint64
+	Percentile float64
+}
+
+var _ Strategy = FeeHistoryStrategy{}
+
+func (s FeeHistoryStrategy) TipCap(ctx context.Context, oracle Oracle) (*big.Int, error) {
+	history, err := oracle.FeeHistory(ctx, s.Blocks, nil, []float64{s.Percentile})
+	if err != nil {
+		return nil, fmt.Errorf("could not get the fee history: %w", err)
+	}
+	tips := make([]*big.Int, 0, len(history.Reward))
+	for _, rewards := range history.Reward {
+		if len(rewards) > 0 && rewards[0] != nil && rewards[0].Sign() > 0 {
+			tips = append(tips, rewards[0])
+		}
+	}
+	// empty blocks pay no tips, fall back to the endpoint suggestion
+	if len(tips) == 0 {
+		return oracle.SuggestGasTipCap(ctx)
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+	return new(big.Int).Set(tips[len(tips)/2]), nil
+}
+
+func (s FeeHistoryStrategy) BumpTipCap(previous, current *big.Int) *big.Int {
+	return SuggestedStrategy{}.BumpTipCap(previous, current)
+}
+
+// FixedStrategy always uses Tip. Stale transactions are sent again with the
+// same tip, which the endpoint rejects until the transaction is pruned from
+// its pool, so a fixed tip never pays more than configured.
+type FixedStrategy struct {
+	Tip *big.Int
+}
+
+var _ Strategy = FixedStrategy{}
+
+func (s FixedStrategy) TipCap(ctx context.Context, oracle Oracle) (*big.Int, error) {
+	return new(big.Int).Set(s.Tip), nil
+}
+
+func (s FixedStrategy) BumpTipCap(previous, current *big.Int) *big.Int {
+	return new(big.Int).Set(s.Tip)
+}
+
+// ExponentialStrategy starts from the tip suggested by the endpoint and raises
+// it by BumpPercentage on every retry. The tip never goes past Max.
+type ExponentialStrategy struct {
+	BumpPercentage int64
+	Max            *big.Int
+}
+
+var _ Strategy = ExponentialStrategy{}
+
+func (s ExponentialStrategy) TipCap(ctx context.Context, oracle Oracle) (*big.Int, error) {
+	tipCap, err := oracle.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.bound(tipCap), nil
+}
+
+func (s ExponentialStrategy) BumpTipCap(previous, current *big.Int) *big.Int {
+	tipCap := current
+	if previous != nil && previous.Cmp(current) > 0 {
+		tipCap = previous
+	}
+	return s.bound(percentage(tipCap, 100+s.BumpPercentage))
+}
+
+func (s ExponentialStrategy) bound(tipCap *big.Int) *big.Int {
+	if tipCap.Cmp(s.Max) > 0 {
+		return new(big.Int).Set(s.Max)
+	}
+	return tipCap
+}
+
+// Strategies holds the strategy of every task type.
+type Strategies struct {
+	Default Strategy
+	Tasks   map[string]Strategy
+}
+
+// NewStrategies creates the strategies from the configuration. An empty
+// strategy name is the SuggestedStrategy.
+func NewStrategies(cfg config.GasConfig) (*Strategies, error) {
+	defaultStrategy, err := newStrategy(cfg.Strategy, cfg)
+	if err != nil {
+		return nil, err
+	}
+	strategies := &Strategies{Default: defaultStrategy, Tasks: make(map[string]Strategy)}
+	for _, entry := range strings.Split(cfg.TaskStrategies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		task, name, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(task) == "" {
+			return nil, fmt.Errorf("%w: %q is not a task=strategy pair", ErrInvalidStrategy, entry)
+		}
+		strategy, err := newStrategy(strings.TrimSpace(name), cfg)
+		if err != nil {
+			return nil, err
+		}
+		strategies.Tasks[strings.TrimSpace(task)] = strategy
+	}
+	return strategies, nil
+}
+
+// For returns the strategy of a task type, or the default one.
+func (s *Strategies) For(task string) Strategy {
+	if strategy, ok := s.Tasks[task]; ok {
+		return strategy
+	}
+	return s.Default
+}
+
+func newStrategy(name string, cfg config.GasConfig) (Strategy, error) {
+	switch name {
+	case "", SuggestedStrategyName:
+		return SuggestedStrategy{}, nil
+	case FeeHistoryStrategyName:
+		if cfg.FeeHistoryBlocks == 0 || cfg.FeeHistoryPercentile > 100 {
+			return nil, fmt.Errorf("%w: feeHistory needs a number of blocks and a percentile between 0 and 100", ErrInvalidStrategy)
+		}
+		return FeeHistoryStrategy{Blocks: cfg.FeeHistoryBlocks, Percentile: float64(cfg.FeeHistoryPercentile)}, nil
+	case FixedStrategyName:
+		if cfg.FixedTipCapInGwei == 0 {
+			return nil, fmt.Errorf("%w: fixed needs a tip", ErrInvalidStrategy)
+		}
+		return FixedStrategy{Tip: new(big.Int).Mul(new(big.Int).SetUint64(cfg.FixedTipCapInGwei), gwei)}, nil
+	case ExponentialStrategyName:
+		if cfg.TipCapBumpPercentage == 0 || cfg.MaxTipCapInGwei == 0 {
+			return nil, fmt.Errorf("%w: exponential needs a bump percentage and a maximum tip", ErrInvalidStrategy)
+		}
+		return ExponentialStrategy{
+			BumpPercentage: int64(cfg.TipCapBumpPercentage),
+			Max:            new(big.Int).Mul(new(big.Int).SetUint64(cfg.MaxTipCapInGwei), gwei),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidStrategy, name)
+}
+
+// percentage returns value * percent / 100.
+func percentage(value *big.Int, percent int64) *big.Int {
+	result := new(big.Int).Mul(value, big.NewInt(percent))
+	return result.Div(result, big.NewInt(100))
+}
+
+type taskKey struct{}
+
+// WithTask returns a context carrying the name of the task type the
+// transactions are sent for.
+func WithTask(ctx context.Context, task string) context.Context {
+	return context.WithValue(ctx, taskKey{}, task)
+}
+
+// TaskFromContext returns the task type set with WithTask, or an empty string.
+func TaskFromContext(ctx context.Context) string {
+	task, _ := ctx.Value(taskKey{}).(string)
+	return task
+}
@@ -0,0 +1,123 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/config"
+)
+
+type fakeOracle struct {
+	tipCap  *big.Int
+	rewards [][]*big.Int
+	err     error
+}
+
+func (o *fakeOracle) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return o.tipCap, o.err
+}
+
+func (o *fakeOracle) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return &ethereum.FeeHistory{Reward: o.rewards}, nil
+}
+
+func TestSuggestedStrategy(t *testing.T) {
+	s := SuggestedStrategy{}
+	tipCap, err := s.TipCap(context.Background(), &fakeOracle{tipCap: big.NewInt(100)})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), tipCap)
+
+	// bumps the highest of the previous and current tips
+	assert.Equal(t, big.NewInt(150), s.BumpTipCap(big.NewInt(80), big.NewInt(100)))
+	assert.Equal(t, big.NewInt(300), s.BumpTipCap(big.NewInt(200), big.NewInt(100)))
+	// starts again from the current tip when it is too far away
+	assert.Equal(t, big.NewInt(100), s.BumpTipCap(big.NewInt(900), big.NewInt(100)))
+}
+
+func TestFeeHistoryStrategy(t *testing.T) {
+	s := FeeHistoryStrategy{Blocks: 5, Percentile: 50}
+	oracle := &fakeOracle{
+		tipCap: big.NewInt(7),
+		rewards: [][]*big.Int{
+			{big.NewInt(30)}, {big.NewInt(10)}, {big.NewInt(0)}, {big.NewInt(20)}, {},
+		},
+	}
+	tipCap, err := s.TipCap(context.Background(), oracle)
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(20), tipCap)
+
+	// empty blocks fall back to the suggested tip
+	oracle.rewards = [][]*big.Int{{big.NewInt(0)}}
+	tipCap, err = s.TipCap(context.Background(), oracle)
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(7), tipCap)
+
+	oracle.err = errors.New("network error")
+	_, err = s.TipCap(context.Background(), oracle)
+	assert.NotNil(t, err)
+}
+
+func TestFixedStrategy(t *testing.T) {
+	s := FixedStrategy{Tip: big.NewInt(42)}
+	tipCap, err := s.TipCap(context.Background(), &fakeOracle{tipCap: big.NewInt(1000)})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(42), tipCap)
+	assert.Equal(t, big.NewInt(42), s.BumpTipCap(big.NewInt(42), big.NewInt(1000)))
+}
+
+func TestExponentialStrategy(t *testing.T) {
+	s := ExponentialStrategy{BumpPercentage: 100, Max: big.NewInt(500)}
+	tipCap, err := s.TipCap(context.Background(), &fakeOracle{tipCap: big.NewInt(100)})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), tipCap)
+
+	tipCap = s.BumpTipCap(tipCap, big.NewInt(100))
+	assert.Equal(t, big.NewInt(200), tipCap)
+	tipCap = s.BumpTipCap(tipCap, big.NewInt(100))
+	assert.Equal(t, big.NewInt(400), tipCap)
+	tipCap = s.BumpTipCap(tipCap, big.NewInt(100))
+	assert.Equal(t, big.NewInt(500), tipCap)
+	assert.Equal(t, big.NewInt(500), s.BumpTipCap(tipCap, big.NewInt(100)))
+
+	tipCap, err = s.TipCap(context.Background(), &fakeOracle{tipCap: big.NewInt(1000)})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(500), tipCap)
+}
+
+func TestNewStrategies(t *testing.T) {
+	strategies, err := NewStrategies(config.GasConfig{
+		TaskStrategies:    " snapshots.SnapshotTask=fixed, dkg.RegisterTask = suggested ,",
+		FixedTipCapInGwei: 2,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, SuggestedStrategy{}, strategies.Default)
+	assert.Equal(t, FixedStrategy{Tip: big.NewInt(2_000_000_000)}, strategies.For("snapshots.SnapshotTask"))
+	assert.Equal(t, SuggestedStrategy{}, strategies.For("dkg.RegisterTask"))
+	assert.Equal(t, SuggestedStrategy{}, strategies.For("dkg.CompletionTask"))
+
+	for _, cfg := range []config.GasConfig{
+		{Strategy: "unknown"},
+		{Strategy: FixedStrategyName},
+		{Strategy: FeeHistoryStrategyName, FeeHistoryPercentile: 50},
+		{Strategy: ExponentialStrategyName, TipCapBumpPercentage: 10},
+		{TaskStrategies: "snapshots.SnapshotTask"},
+		{TaskStrategies: "=fixed", FixedTipCapInGwei: 1},
+	} {
+		_, err := NewStrategies(cfg)
+		assert.ErrorIs(t, err, ErrInvalidStrategy, "%+v", cfg)
+	}
+}
+
+func TestTaskFromContext(t *testing.T) {
+	assert.Equal(t, "", TaskFromContext(context.Background()))
+	assert.Equal(t, "dkg.RegisterTask", TaskFromContext(WithTask(context.Background(), "dkg.RegisterTask")))
+}
@@ -0,0 +1,262 @@
+// Package indexer keeps the history of the layer1 events processed by the
+// monitor, indexed by block, event name and contract address, in the monitor
+// database.
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants/dbprefix"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// MaxQueryLimit is the maximum number of events returned by a Query, unless
+// the events of the last block go past it.
+const MaxQueryLimit = 1000
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Event is a layer1 event processed by the monitor. Fields holds the decoded
+// arguments of the event, or the raw data if its ABI is unknown.
+type Event struct {
+	Contract common.Address    `json:"contract"`
+	Name     string            `json:"name"`
+	Block    uint64            `json:"block"`
+	TxHash   common.Hash       `json:"txHash"`
+	LogIndex uint32            `json:"logIndex"`
+	Fields   map[string]string `json:"fields"`
+}
+
+// Query selects events from FromBlock to ToBlock, both inclusive. A zero
+// ToBlock has no upper bound, an empty Name or zero Contract matches any.
+type Query struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Name      string
+	Contract  common.Address
+	Limit     int
+}
+
+// Indexer records and queries layer1 events.
+type Indexer struct {
+	db     *db.Database
+	events map[common.Hash]abi.Event
+}
+
+// NewIndexer creates an Indexer storing the events in monDB. The events of the
+// given ABIs are stored with their decoded arguments.
+func NewIndexer(monDB *db.Database, abiEvents ...map[string]abi.Event) *Indexer {
+	ix := &Indexer{db: monDB, events: make(map[common.Hash]abi.Event)}
+	for _, events := range abiEvents {
+		for _, event := range events {
+			ix.events[event.ID] = event
+		}
+	}
+	return ix
+}
+
+// Record stores the event of log under name. Recording the same log again
+// overwrites it.
+func (ix *Indexer) Record(name string, log types.Log) error {
+	if len(log.Topics) == 0 {
+		return fmt.Errorf("log of %v has no topics", name)
+	}
+	event := &Event{
+		Contract: log.Address,
+		Name:     name,
+		Block:    log.BlockNumber,
+		TxHash:   log.TxHash,
+		LogIndex: uint32(log.Index),
+	}
+	abiEvent, ok := ix.events[log.Topics[0]]
+	if ok {
+		event.Name = abiEvent.Name
+	}
+	fields, err := decodeFields(abiEvent, log)
+	if !ok || err != nil {
+		fields = map[string]string{"data": hexutil.Encode(log.Data)}
+		for i, topic := range log.Topics[1:] {
+			fields[fmt.Sprintf("topic%d", i+1)] = topic.Hex()
+		}
+	}
+	event.Fields = fields
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	suffix := eventSuffix(event.Block, event.LogIndex)
+	return ix.db.Update(func(txn *badger.Txn) error {
+		if err := utils.SetValue(txn, append(dbprefix.PrefixLayer1Event(), suffix...), raw); err != nil {
+			return err
+		}
+		if err := utils.SetValue(txn, append(namePrefix(event.Name), suffix...), []byte{}); err != nil {
+			return err
+		}
+		return utils.SetValue(txn, append(contractPrefix(event.Contract), suffix...), []byte{})
+	})
+}
+
+// Query returns the events matching q in block order and the block the next
+// page starts at, which is zero once there are no more events. The events of
+// a block are never split between pages.
+func (ix *Indexer) Query(q *Query) ([]*Event, uint64, error) {
+	if q.ToBlock != 0 && q.ToBlock < q.FromBlock {
+		return nil, 0, fmt.Errorf("%w: to block %v is lower than from block %v", ErrInvalidQuery, q.ToBlock, q.FromBlock)
+	}
+	limit := q.Limit
+	if limit <= 0 || limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+
+	// the most selective index that can serve the query
+	prefix := dbprefix.PrefixLayer1Event()
+	isIndex := true
+	switch {
+	case q.Name != "":
+		prefix = namePrefix(q.Name)
+	case q.Contract != (common.Address{}):
+		prefix = contractPrefix(q.Contract)
+	default:
+		isIndex = false
+	}
+
+	result := []*Event{}
+	next := uint64(0)
+	err := ix.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		opts.PrefetchValues = !isIndex
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		start := append(utils.CopySlice(prefix), eventSuffix(q.FromBlock, 0)...)
+		for iter.Seek(start); iter.ValidForPrefix(prefix); iter.Next() {
+			key := iter.Item().KeyCopy(nil)
+			suffix := key[len(prefix):]
+			block := binary.BigEndian.Uint64(suffix[:8])
+			if q.ToBlock != 0 && block > q.ToBlock {
+				break
+			}
+			if len(result) >= limit && block != result[len(result)-1].Block {
+				next = block
+				break
+			}
+
+			var raw []byte
+			var err error
+			if isIndex {
+				raw, err = utils.GetValue(txn, append(dbprefix.PrefixLayer1Event(), suffix...))
+			} else {
+				raw, err = iter.Item().ValueCopy(nil)
+			}
+			if err != nil {
+				return err
+			}
+			event := &Event{}
+			if err := json.Unmarshal(raw, event); err != nil {
+				return err
+			}
+			if q.Contract != (common.Address{}) && event.Contract != q.Contract {
+				continue
+			}
+			result = append(result, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, next, nil
+}
+
+func eventSuffix(block uint64, logIndex uint32) []byte {
+	suffix := make([]byte, 12)
+	binary.BigEndian.PutUint64(suffix[:8], block)
+	binary.BigEndian.PutUint32(suffix[8:], logIndex)
+	return suffix
+}
+
+// namePrefix ends with a zero byte so that the prefix of a name does not
+// match longer names.
+func namePrefix(name string) []byte {
+	prefix := append(dbprefix.PrefixLayer1EventNameIndex(), []byte(name)...)
+	return append(prefix, 0)
+}
+
+func contractPrefix(contract common.Address) []byte {
+	return append(dbprefix.PrefixLayer1EventContractIndex(), contract.Bytes()...)
+}
+
+// decodeFields unpacks the indexed and data arguments of the event.
+func decodeFields(event abi.Event, log types.Log) (map[string]string, error) {
+	values := make(map[string]interface{})
+	var indexed abi.Arguments
+	hasData := false
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		} else {
+			hasData = true
+		}
+	}
+	if hasData {
+		if err := event.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+			return nil, err
+		}
+	}
+	if len(log.Topics) != len(indexed)+1 {
+		return nil, fmt.Errorf("expected %v topics, got %v", len(indexed)+1, len(log.Topics))
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(values))
+	for name, value := range values {
+		s, err := formatValue(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = s
+	}
+	return fields, nil
+}
+
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String(), nil
+	case common.Address:
+		return v.Hex(), nil
+	case common.Hash:
+		return v.Hex(), nil
+	case []byte:
+		return hexutil.Encode(v), nil
+	case string:
+		return v, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b), nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
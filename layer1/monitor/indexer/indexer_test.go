@@ -0,0 +1,131 @@
+package indexer_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+var (
+	validatorPool = common.HexToAddress("0x0a")
+	dynamics      = common.HexToAddress("0x0b")
+)
+
+func majorSlashedLog(block uint64, index uint, account common.Address) types.Log {
+	event := events.GetValidatorPoolEvents()["ValidatorMajorSlashed"]
+	return types.Log{
+		Address:     validatorPool,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(account.Bytes())},
+		BlockNumber: block,
+		TxHash:      common.BigToHash(big.NewInt(int64(block))),
+		Index:       index,
+	}
+}
+
+func dynamicValueChangedLog(t *testing.T, block uint64, index uint) types.Log {
+	event := events.GetDynamicsEvents()["DynamicValueChanged"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(7), []byte{1, 2})
+	require.Nil(t, err)
+	return types.Log{
+		Address:     dynamics,
+		Topics:      []common.Hash{event.ID},
+		Data:        data,
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func newIndexer(t *testing.T) *indexer.Indexer {
+	monDB := mocks.NewTestDB()
+	t.Cleanup(func() { monDB.DB().Close() })
+	return indexer.NewIndexer(monDB, events.GetValidatorPoolEvents(), events.GetDynamicsEvents())
+}
+
+func TestIndexer_Record(t *testing.T) {
+	ix := newIndexer(t)
+	account := common.HexToAddress("0x546F99F244b7B58B855330AE0E2BC1b30b41302F")
+	require.Nil(t, ix.Record("ValidatorMajorSlashed", majorSlashedLog(10, 1, account)))
+	require.Nil(t, ix.Record("DynamicValueChanged", dynamicValueChangedLog(t, 11, 0)))
+	unknown := types.Log{Address: dynamics, Topics: []common.Hash{{1}, {2}}, Data: []byte{3}, BlockNumber: 12}
+	require.Nil(t, ix.Record("Unknown", unknown))
+	assert.NotNil(t, ix.Record("Unknown", types.Log{}))
+
+	got, next, err := ix.Query(&indexer.Query{})
+	require.Nil(t, err)
+	assert.Equal(t, uint64(0), next)
+	require.Equal(t, 3, len(got))
+	assert.Equal(t, &indexer.Event{
+		Contract: validatorPool,
+		Name:     "ValidatorMajorSlashed",
+		Block:    10,
+		TxHash:   common.BigToHash(big.NewInt(10)),
+		LogIndex: 1,
+		Fields:   map[string]string{"account": account.Hex()},
+	}, got[0])
+	assert.Equal(t, map[string]string{"epoch": "7", "rawDynamicValues": "0x0102"}, got[1].Fields)
+	assert.Equal(t, "Unknown", got[2].Name)
+	assert.Equal(t, map[string]string{"data": "0x03", "topic1": common.Hash{2}.Hex()}, got[2].Fields)
+
+	// recording again overwrites the event
+	require.Nil(t, ix.Record("ValidatorMajorSlashed", majorSlashedLog(10, 1, account)))
+	got, _, err = ix.Query(&indexer.Query{})
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(got))
+}
+
+func TestIndexer_Query(t *testing.T) {
+	ix := newIndexer(t)
+	for block := uint64(1); block <= 5; block++ {
+		require.Nil(t, ix.Record("ValidatorMajorSlashed", majorSlashedLog(block, 0, common.HexToAddress("0x01"))))
+		require.Nil(t, ix.Record("ValidatorMajorSlashed", majorSlashedLog(block, 1, common.HexToAddress("0x02"))))
+		require.Nil(t, ix.Record("DynamicValueChanged", dynamicValueChangedLog(t, block, 2)))
+	}
+
+	blocks := func(events []*indexer.Event) []uint64 {
+		result := []uint64{}
+		for _, e := range events {
+			result = append(result, e.Block)
+		}
+		return result
+	}
+
+	got, next, err := ix.Query(&indexer.Query{FromBlock: 2, ToBlock: 3})
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{2, 2, 2, 3, 3, 3}, blocks(got))
+	assert.Equal(t, uint64(0), next)
+
+	got, next, err = ix.Query(&indexer.Query{Name: "DynamicValueChanged", FromBlock: 4})
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{4, 5}, blocks(got))
+	assert.Equal(t, uint64(0), next)
+
+	got, _, err = ix.Query(&indexer.Query{Name: "Dynamic"})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(got))
+
+	got, _, err = ix.Query(&indexer.Query{Contract: validatorPool, ToBlock: 2})
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{1, 1, 2, 2}, blocks(got))
+
+	got, _, err = ix.Query(&indexer.Query{Contract: dynamics, Name: "ValidatorMajorSlashed"})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(got))
+
+	// the events of a block are not split between pages
+	got, next, err = ix.Query(&indexer.Query{Limit: 4})
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{1, 1, 1, 2, 2, 2}, blocks(got))
+	assert.Equal(t, uint64(3), next)
+
+	_, _, err = ix.Query(&indexer.Query{FromBlock: 3, ToBlock: 2})
+	assert.True(t, errors.Is(err, indexer.ErrInvalidQuery))
+}
@@ -8,6 +8,8 @@ import (
 	aobjs "github.com/alicenet/alicenet/application/objs"
 	"github.com/alicenet/alicenet/consensus/objs"
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
 )
 
 type AdminHandler interface {
@@ -24,6 +26,23 @@ type DepositHandler interface {
 	Add(*badger.Txn, uint32, []byte, *big.Int, *aobjs.Owner) error
 }
 
+// DepositTracker reports the latest ALCB deposit nonces seen on layer1 and
+// added to the deposit handler.
+type DepositTracker interface {
+	GetLatestDeposits() (seen uint32, processed uint32)
+}
+
 type AdminClient interface {
 	SetAdminHandler(AdminHandler)
 }
+
+// Subscriber registers contract event subscriptions driven by the monitor.
+type Subscriber interface {
+	Subscribe(sub *objects.Subscription) error
+}
+
+// EventIndex queries the history of the layer1 events processed by the
+// monitor.
+type EventIndex interface {
+	QueryEvents(q *indexer.Query) ([]*indexer.Event, uint64, error)
+}
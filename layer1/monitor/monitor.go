@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alicenet/alicenet/bridge/bindings"
 	"github.com/alicenet/alicenet/config"
 	"github.com/alicenet/alicenet/consensus/db"
 	"github.com/alicenet/alicenet/consensus/objs"
@@ -18,6 +19,7 @@ import (
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots"
 	"github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
 	"github.com/alicenet/alicenet/layer1/executor"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
 	"github.com/alicenet/alicenet/layer1/monitor/interfaces"
 	"github.com/alicenet/alicenet/layer1/monitor/objects"
 	"github.com/alicenet/alicenet/logging"
@@ -43,6 +45,8 @@ type monitor struct {
 	contracts            layer1.AllSmartContracts
 	eventFilterAddresses []common.Address
 	eventMap             *objects.EventMap
+	subscriptions        *objects.SubscriptionRegistry
+	eventIndex           *indexer.Indexer
 	db                   *db.Database
 	cdb                  *db.Database
 	tickInterval         time.Duration
@@ -56,6 +60,10 @@ type monitor struct {
 
 	//for communication with the TasksScheduler
 	taskHandler executor.TaskHandler
+
+	// upgradeFunc is asked to restart the node into a new major version when
+	// it becomes canonical, see SetUpgradeFunc.
+	upgradeFunc func(version bindings.CanonicalVersion) bool
 }
 
 // NewMonitor creates a new Monitor.
@@ -92,6 +100,7 @@ func NewMonitor(cdb *db.Database,
 		statusChan:           make(chan string, 1),
 		batchSize:            batchSize,
 		taskHandler:          taskHandler,
+		subscriptions:        objects.NewSubscriptionRegistry(),
 	}
 
 	eventMap := objects.NewEventMap()
@@ -102,12 +111,23 @@ func NewMonitor(cdb *db.Database,
 		adminHandler,
 		depositHandler,
 		taskHandler,
-		mon.Close,
+		mon.exit,
 		chainId,
 	)
 	if err != nil {
 		return nil, err
 	}
+	mon.eventIndex = indexer.NewIndexer(
+		monDB,
+		events.GetETHDKGEvents(),
+		events.GetALCBEvents(),
+		events.GetSnapshotEvents(),
+		events.GetGovernanceEvents(),
+		events.GetValidatorPoolEvents(),
+		events.GetPublicStakingEvents(),
+		events.GetDynamicsEvents(),
+	)
+	eventMap.SetRecorder(mon.eventIndex.Record)
 	mon.eventMap = eventMap
 	mon.State = objects.NewMonitorState()
 
@@ -121,11 +141,48 @@ func NewMonitor(cdb *db.Database,
 	return mon, nil
 }
 
+var _ interfaces.DepositTracker = &monitor{}
+
+// GetLatestDeposits returns the latest deposit nonces seen and processed by the monitor.
+func (mon *monitor) GetLatestDeposits() (uint32, uint32) {
+	mon.State.RLock()
+	defer mon.State.RUnlock()
+	return mon.State.LatestDepositSeen, mon.State.LatestDepositProcessed
+}
+
+var _ interfaces.EventIndex = &monitor{}
+
+// QueryEvents returns the layer1 events processed by the monitor that match q.
+func (mon *monitor) QueryEvents(q *indexer.Query) ([]*indexer.Event, uint64, error) {
+	return mon.eventIndex.Query(q)
+}
+
 // GetStatus of the monitor.
 func (mon *monitor) GetStatus() <-chan string {
 	return mon.statusChan
 }
 
+// SetUpgradeFunc sets the function called when a new major version of the
+// node becomes canonical. It returns true if it restarts the node into the new
+// version, otherwise the monitor closes to shut down the node.
+func (mon *monitor) SetUpgradeFunc(fn func(version bindings.CanonicalVersion) bool) {
+	mon.Lock()
+	defer mon.Unlock()
+	mon.upgradeFunc = fn
+}
+
+// exit hands a new canonical major version to the upgrade function, or closes
+// the monitor if the node is not restarted into it.
+func (mon *monitor) exit(version bindings.CanonicalVersion) {
+	mon.RLock()
+	upgradeFunc := mon.upgradeFunc
+	mon.RUnlock()
+	if upgradeFunc != nil && upgradeFunc(version) {
+		return
+	}
+	mon.Close()
+}
+
 // Close the event loop.
 func (mon *monitor) Close() {
 	mon.closeOnce.Do(func() {
@@ -192,12 +249,13 @@ func (mon *monitor) Start() error {
 // eventLoop to process the events and chain changes.
 func (mon *monitor) eventLoop(logger *logrus.Entry) {
 	gcTimer := time.After(constants.MonDBGCFreq)
+	subscriptionsBehind := false
 	for {
 		ctx, cf := context.WithTimeout(context.Background(), mon.timeout)
 		tock := mon.tickInterval
 		bmax := utils.Max(mon.State.HighestBlockFinalized, mon.State.HighestBlockProcessed)
 		bmin := utils.Min(mon.State.HighestBlockFinalized, mon.State.HighestBlockProcessed)
-		if !(bmax-bmin < mon.batchSize) {
+		if !(bmax-bmin < mon.batchSize) || subscriptionsBehind {
 			tock = time.Millisecond * 100
 		}
 		select {
@@ -220,6 +278,14 @@ func (mon *monitor) eventLoop(logger *logrus.Entry) {
 				logger.Errorf("Failed MonitorTick(...): %v", err)
 			}
 
+			subCtx, subCf := context.WithTimeout(context.Background(), mon.timeout)
+			behind, err := ProcessSubscriptions(subCtx, mon.eth, mon.contracts, mon.State, mon.logger, mon.subscriptions, mon.batchSize)
+			subCf()
+			if err != nil {
+				logger.Errorf("Failed ProcessSubscriptions(...): %v", err)
+			}
+			subscriptionsBehind = behind
+
 			diff, shouldWrite := oldMonitorState.Diff(mon.State)
 
 			if shouldWrite {
@@ -403,6 +469,10 @@ func ProcessEvents(
 					logEntry.Errorf("Failed processing event: %v", err)
 					return currentBlock - 1, err
 				}
+				if err := eventMap.Record(info.Name, log); err != nil {
+					logEntry.Errorf("Failed recording event: %v", err)
+					return currentBlock - 1, err
+				}
 			} else {
 				panic(fmt.Errorf("no processor configured for %v", info.Name))
 			}
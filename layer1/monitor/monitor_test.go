@@ -402,6 +402,47 @@ func TestProcessProcessNewCanonicalAliceNetNodeVersion(t *testing.T) {
 	}
 }
 
+func TestProcessNewCanonicalAliceNetNodeVersion_Upgrade(t *testing.T) {
+	config.Configuration.Version = "v2.1.6"
+	mon, taskHandler, eth, contracts, _ := getMonitor(t)
+	taskHandler.Start()
+	eth.EndpointInSyncFunc.SetDefaultReturn(true, 4, nil)
+	eth.GetFinalizedHeightFunc.SetDefaultReturn(1, nil)
+
+	localVersion, _ := utils.GetLocalVersion()
+	localVersion.Major++
+	dynamics := mocks.NewMockIDynamics()
+	dynamics.ParseNewCanonicalAliceNetNodeVersionFunc.SetDefaultReturn(&bindings.DynamicsNewCanonicalAliceNetNodeVersion{Version: localVersion}, nil)
+	contracts.DynamicsFunc.SetDefaultReturn(dynamics)
+
+	// the upgrade function takes over the restart, the monitor keeps running
+	upgrades := make(chan bindings.CanonicalVersion, 1)
+	mon.SetUpgradeFunc(func(version bindings.CanonicalVersion) bool {
+		upgrades <- version
+		return true
+	})
+	logs := []types.Log{
+		{Topics: []common.Hash{events.GetDynamicsEvents()["NewCanonicalAliceNetNodeVersion"].ID}},
+	}
+	eth.GetEventsFunc.SetDefaultReturn(nil, nil)
+	eth.GetEventsFunc.PushReturn(logs, nil)
+
+	err := mon.Start()
+	assert.Nil(t, err)
+	select {
+	case <-time.After(4500 * time.Millisecond):
+		t.Fatal("didn't update event in time")
+	case version := <-upgrades:
+		assert.Equal(t, localVersion, version)
+	}
+	// let the monitor finish processing the block
+	select {
+	case <-mon.CloseChan():
+		t.Fatal("the monitor closed")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
 func TestPersistSnapshot(t *testing.T) {
 	mon, taskHandler, eth, _, _ := getMonitor(t)
 	eth.GetFinalizedHeightFunc.SetDefaultReturn(1, nil)
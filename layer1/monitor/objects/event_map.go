@@ -15,9 +15,14 @@ type EventInformation struct {
 	Processor EventProcessor
 }
 
+// EventRecorder is called with every log processed successfully by the
+// processor of a registered event.
+type EventRecorder func(name string, log types.Log) error
+
 type EventMap struct {
 	sync.RWMutex
 	registry map[string]*EventInformation
+	recorder EventRecorder
 }
 
 func NewEventMap() *EventMap {
@@ -50,3 +55,23 @@ func (em *EventMap) Lookup(ID string) (*EventInformation, bool) {
 
 	return info, present
 }
+
+// SetRecorder sets the recorder of the processed events.
+func (em *EventMap) SetRecorder(recorder EventRecorder) {
+	em.Lock()
+	defer em.Unlock()
+
+	em.recorder = recorder
+}
+
+// Record calls the recorder, if any, with a processed event.
+func (em *EventMap) Record(name string, log types.Log) error {
+	em.RLock()
+	recorder := em.recorder
+	em.RUnlock()
+
+	if recorder == nil {
+		return nil
+	}
+	return recorder(name, log)
+}
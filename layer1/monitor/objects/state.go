@@ -35,6 +35,7 @@ type MonitorState struct {
 	Validators             map[uint32][]Validator                `json:"validators"`
 	PotentialValidators    map[common.Address]PotentialValidator `json:"potentialValidators"`
 	CanonicalVersion       bindings.CanonicalVersion             `json:"canonicalVersion"`
+	SubscriptionCursors    map[string]uint64                     `json:"subscriptionCursors"`
 }
 
 // ValidatorSet is summary information about a ValidatorSet that participated on ETHDKG.
@@ -63,6 +64,7 @@ func NewMonitorState() *MonitorState {
 		ValidatorSets:       make(map[uint32]ValidatorSet),
 		Validators:          make(map[uint32][]Validator),
 		PotentialValidators: make(map[common.Address]PotentialValidator),
+		SubscriptionCursors: make(map[string]uint64),
 	}
 }
 
@@ -103,6 +105,9 @@ func (s *MonitorState) Clone() *MonitorState {
 	ns.LatestDepositProcessed = s.LatestDepositProcessed
 	ns.LatestDepositSeen = s.LatestDepositSeen
 	ns.PeerCount = s.PeerCount
+	for name, cursor := range s.SubscriptionCursors {
+		ns.SubscriptionCursors[name] = cursor
+	}
 
 	return ns
 }
@@ -261,5 +266,15 @@ func (s *MonitorState) Diff(o *MonitorState) (string, bool) {
 		)
 	}
 
+	for name, cursor := range o.SubscriptionCursors {
+		if s.SubscriptionCursors[name] != cursor {
+			shouldWrite = true
+			d = append(
+				d,
+				fmt.Sprintf("SubscriptionCursors[%v]: %v -> %v", name, s.SubscriptionCursors[name], cursor),
+			)
+		}
+	}
+
 	return strings.Join(d, ", "), shouldWrite
 }
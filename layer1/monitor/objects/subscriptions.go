@@ -0,0 +1,109 @@
+package objects
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/layer1"
+)
+
+var (
+	ErrInvalidSubscription   = errors.New("invalid subscription")
+	ErrDuplicateSubscription = errors.New("subscription already registered")
+)
+
+// ContractAddress resolves the address of the contract a subscription
+// listens to. It is called on every monitor tick, so the address follows the
+// contracts handle.
+type ContractAddress func(contracts layer1.AllSmartContracts) common.Address
+
+// Subscription is a consumer of an event of a contract. The monitor fetches
+// the logs of the contract in its own batches and calls Processor for every
+// log of the event, in order, keeping a cursor per subscription in the
+// MonitorState.
+type Subscription struct {
+	// Name identifies the subscription and its cursor, it must be unique.
+	Name     string
+	Contract ContractAddress
+	Event    abi.Event
+	// StartBlock is the first block to process when there is no cursor yet.
+	// If zero, the subscription starts at the highest block processed by the
+	// monitor when it is first driven.
+	StartBlock uint64
+	// Confirmations is how many blocks past the finalized height the monitor
+	// waits before processing a block.
+	Confirmations uint64
+	Processor     EventProcessor
+}
+
+// TypedProcessor builds an EventProcessor that decodes the log with one of the
+// Parse functions of the generated bindings before calling fn.
+func TypedProcessor[T any](
+	parse func(contracts layer1.AllSmartContracts, log types.Log) (*T, error),
+	fn func(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *MonitorState, event *T) error,
+) EventProcessor {
+	return func(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *MonitorState, log types.Log) error {
+		event, err := parse(contracts, log)
+		if err != nil {
+			return err
+		}
+		return fn(eth, contracts, logger, state, event)
+	}
+}
+
+// Matches returns true if log is an event of the subscription.
+func (s *Subscription) Matches(address common.Address, log types.Log) bool {
+	return log.Address == address && len(log.Topics) > 0 && log.Topics[0] == s.Event.ID
+}
+
+// SubscriptionRegistry holds the subscriptions driven by the monitor.
+type SubscriptionRegistry struct {
+	sync.RWMutex
+	subscriptions map[string]*Subscription
+}
+
+func NewSubscriptionRegistry() *SubscriptionRegistry {
+	return &SubscriptionRegistry{subscriptions: make(map[string]*Subscription)}
+}
+
+// Subscribe adds a subscription to the registry.
+func (sr *SubscriptionRegistry) Subscribe(sub *Subscription) error {
+	if sub == nil || sub.Name == "" || sub.Contract == nil || sub.Processor == nil || sub.Event.ID == (common.Hash{}) {
+		return ErrInvalidSubscription
+	}
+
+	sr.Lock()
+	defer sr.Unlock()
+	if _, present := sr.subscriptions[sub.Name]; present {
+		return fmt.Errorf("%w: %v", ErrDuplicateSubscription, sub.Name)
+	}
+	sr.subscriptions[sub.Name] = sub
+	return nil
+}
+
+// Unsubscribe removes a subscription from the registry. Its cursor is kept in
+// the MonitorState, so subscribing again resumes from it.
+func (sr *SubscriptionRegistry) Unsubscribe(name string) {
+	sr.Lock()
+	defer sr.Unlock()
+	delete(sr.subscriptions, name)
+}
+
+// List returns the subscriptions sorted by name.
+func (sr *SubscriptionRegistry) List() []*Subscription {
+	sr.RLock()
+	defer sr.RUnlock()
+	subs := make([]*Subscription, 0, len(sr.subscriptions))
+	for _, sub := range sr.subscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].Name < subs[j].Name })
+	return subs
+}
@@ -0,0 +1,67 @@
+package objects
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/layer1"
+)
+
+func newSubscription(name string) *Subscription {
+	return &Subscription{
+		Name:     name,
+		Contract: func(layer1.AllSmartContracts) common.Address { return common.HexToAddress("0x01") },
+		Event:    abi.Event{Name: "Event", ID: common.HexToHash("0x02")},
+		Processor: func(layer1.Client, layer1.AllSmartContracts, *logrus.Entry, *MonitorState, types.Log) error {
+			return nil
+		},
+	}
+}
+
+func TestSubscriptionRegistry(t *testing.T) {
+	sr := NewSubscriptionRegistry()
+	assert.True(t, errors.Is(sr.Subscribe(nil), ErrInvalidSubscription))
+	invalid := newSubscription("b")
+	invalid.Event = abi.Event{}
+	assert.True(t, errors.Is(sr.Subscribe(invalid), ErrInvalidSubscription))
+
+	assert.Nil(t, sr.Subscribe(newSubscription("b")))
+	assert.Nil(t, sr.Subscribe(newSubscription("a")))
+	assert.True(t, errors.Is(sr.Subscribe(newSubscription("a")), ErrDuplicateSubscription))
+
+	subs := sr.List()
+	assert.Equal(t, 2, len(subs))
+	assert.Equal(t, "a", subs[0].Name)
+	assert.Equal(t, "b", subs[1].Name)
+
+	sr.Unsubscribe("a")
+	assert.Equal(t, 1, len(sr.List()))
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	sub := newSubscription("a")
+	address := common.HexToAddress("0x01")
+	assert.True(t, sub.Matches(address, types.Log{Address: address, Topics: []common.Hash{sub.Event.ID}}))
+	assert.False(t, sub.Matches(address, types.Log{Address: common.HexToAddress("0x03"), Topics: []common.Hash{sub.Event.ID}}))
+	assert.False(t, sub.Matches(address, types.Log{Address: address, Topics: []common.Hash{{}}}))
+	assert.False(t, sub.Matches(address, types.Log{Address: address}))
+}
+
+func TestMonitorState_SubscriptionCursors(t *testing.T) {
+	s := NewMonitorState()
+	s.SubscriptionCursors["a"] = 10
+	c := s.Clone()
+	assert.Equal(t, uint64(10), c.SubscriptionCursors["a"])
+
+	c.SubscriptionCursors["a"] = 12
+	diff, shouldWrite := s.Diff(c)
+	assert.True(t, shouldWrite)
+	assert.Equal(t, "SubscriptionCursors[a]: 10 -> 12", diff)
+	assert.Equal(t, uint64(10), s.SubscriptionCursors["a"])
+}
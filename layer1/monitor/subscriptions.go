@@ -0,0 +1,157 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/monitor/interfaces"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/utils"
+)
+
+var _ interfaces.Subscriber = &monitor{}
+
+// Subscribe registers a subscription to be driven by the monitor. It can be
+// called before or after the monitor is started.
+func (mon *monitor) Subscribe(sub *objects.Subscription) error {
+	return mon.subscriptions.Subscribe(sub)
+}
+
+// subscriptionBatch is a set of subscriptions that are at the same cursor and
+// have the same target block, so their logs are fetched together.
+type subscriptionBatch struct {
+	from, to  uint64
+	subs      []*objects.Subscription
+	addresses []common.Address
+}
+
+// ProcessSubscriptions advances the subscriptions of the registry by up to
+// batchSize blocks each, stopping confirmations blocks before the highest
+// finalized block. It returns true if any subscription is still behind after
+// the call. A subscription whose processor fails stays at the block before
+// the failing one and is retried on the next call.
+func ProcessSubscriptions(
+	ctx context.Context,
+	eth layer1.Client,
+	contracts layer1.AllSmartContracts,
+	monitorState *objects.MonitorState,
+	logger *logrus.Entry,
+	registry *objects.SubscriptionRegistry,
+	batchSize uint64,
+) (bool, error) {
+	if !monitorState.IsInitialized {
+		return false, nil
+	}
+	finalized := monitorState.HighestBlockFinalized
+
+	batches := make(map[[2]uint64]*subscriptionBatch)
+	behind := false
+	for _, sub := range registry.List() {
+		monitorState.Lock()
+		cursor, ok := monitorState.SubscriptionCursors[sub.Name]
+		if !ok {
+			cursor = monitorState.HighestBlockProcessed
+			if sub.StartBlock > 0 {
+				cursor = sub.StartBlock - 1
+			}
+			monitorState.SubscriptionCursors[sub.Name] = cursor
+		}
+		monitorState.Unlock()
+
+		if finalized < sub.Confirmations || cursor >= finalized-sub.Confirmations {
+			continue
+		}
+		target := finalized - sub.Confirmations
+		to := utils.Min(target, cursor+batchSize)
+		if to < target {
+			behind = true
+		}
+		key := [2]uint64{cursor, to}
+		batch, ok := batches[key]
+		if !ok {
+			batch = &subscriptionBatch{from: cursor, to: to}
+			batches[key] = batch
+		}
+		batch.subs = append(batch.subs, sub)
+		address := sub.Contract(contracts)
+		found := false
+		for _, a := range batch.addresses {
+			if a == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			batch.addresses = append(batch.addresses, address)
+		}
+	}
+
+	keys := make([][2]uint64, 0, len(batches))
+	for key := range batches {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	var firstErr error
+	for _, key := range keys {
+		if err := processSubscriptionBatch(ctx, eth, contracts, monitorState, logger, batches[key]); err != nil {
+			behind = true
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return behind, firstErr
+}
+
+func processSubscriptionBatch(
+	ctx context.Context,
+	eth layer1.Client,
+	contracts layer1.AllSmartContracts,
+	monitorState *objects.MonitorState,
+	logger *logrus.Entry,
+	batch *subscriptionBatch,
+) error {
+	logsList, err := getLogsConcurrentWithSort(ctx, batch.addresses, eth, batch.from, batch.to)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, sub := range batch.subs {
+		subLogger := logger.WithFields(logrus.Fields{
+			"Subscription": sub.Name,
+			"Event":        sub.Event.Name,
+		})
+		address := sub.Contract(contracts)
+		cursor := batch.from
+	blocks:
+		for i, logs := range logsList {
+			block := batch.from + uint64(i) + 1
+			for _, log := range logs {
+				if !sub.Matches(address, log) {
+					continue
+				}
+				if err := sub.Processor(eth, contracts, subLogger.WithField("Block", block), monitorState, log); err != nil {
+					subLogger.WithField("Block", block).Errorf("Failed processing event: %v", err)
+					if firstErr == nil {
+						firstErr = err
+					}
+					break blocks
+				}
+			}
+			cursor = block
+		}
+
+		monitorState.Lock()
+		monitorState.SubscriptionCursors[sub.Name] = cursor
+		monitorState.Unlock()
+	}
+	return firstErr
+}
@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/test/mocks"
+)
+
+func TestProcessSubscriptions(t *testing.T) {
+	alcbAddress := common.HexToAddress("0x0a")
+	depositReceived := events.GetALCBEvents()["DepositReceived"]
+
+	// a deposit in every block, the deposit id is the block number
+	eth := mocks.NewMockClient()
+	eth.GetEventsFunc.SetDefaultHook(func(ctx context.Context, start, end uint64, addresses []common.Address) ([]types.Log, error) {
+		assert.Equal(t, []common.Address{alcbAddress}, addresses)
+		return []types.Log{
+			{Address: alcbAddress, BlockNumber: start, Topics: []common.Hash{depositReceived.ID}},
+			{Address: alcbAddress, BlockNumber: start, Topics: []common.Hash{{}}},
+		}, nil
+	})
+	alcb := mocks.NewMockIALCB()
+	alcb.ParseDepositReceivedFunc.SetDefaultHook(func(log types.Log) (*bindings.ALCBDepositReceived, error) {
+		return &bindings.ALCBDepositReceived{DepositID: new(big.Int).SetUint64(log.BlockNumber)}, nil
+	})
+	ethereumContracts := mocks.NewMockEthereumContracts()
+	ethereumContracts.ALCBFunc.SetDefaultReturn(alcb)
+	ethereumContracts.ALCBAddressFunc.SetDefaultReturn(alcbAddress)
+	contracts := mocks.NewMockAllSmartContracts()
+	contracts.EthereumContractsFunc.SetDefaultReturn(ethereumContracts)
+
+	var seen, confirmed []uint64
+	failAt := uint64(0)
+	newSub := func(name string, confirmations uint64, deposits *[]uint64) *objects.Subscription {
+		return &objects.Subscription{
+			Name:          name,
+			Contract:      func(c layer1.AllSmartContracts) common.Address { return c.EthereumContracts().ALCBAddress() },
+			Event:         depositReceived,
+			StartBlock:    5,
+			Confirmations: confirmations,
+			Processor: objects.TypedProcessor(
+				func(c layer1.AllSmartContracts, log types.Log) (*bindings.ALCBDepositReceived, error) {
+					return c.EthereumContracts().ALCB().ParseDepositReceived(log)
+				},
+				func(eth layer1.Client, c layer1.AllSmartContracts, logger *logrus.Entry, state *objects.MonitorState, event *bindings.ALCBDepositReceived) error {
+					if event.DepositID.Uint64() == failAt {
+						return errors.New("failed")
+					}
+					*deposits = append(*deposits, event.DepositID.Uint64())
+					return nil
+				},
+			),
+		}
+	}
+	registry := objects.NewSubscriptionRegistry()
+	require.Nil(t, registry.Subscribe(newSub("seen", 0, &seen)))
+	require.Nil(t, registry.Subscribe(newSub("confirmed", 3, &confirmed)))
+
+	state := objects.NewMonitorState()
+	logger := mocks.NewMockLogger().WithField("test", t.Name())
+	behind, err := ProcessSubscriptions(context.Background(), eth, contracts, state, logger, registry, 4)
+	require.Nil(t, err)
+	assert.False(t, behind)
+	assert.Equal(t, 0, len(state.SubscriptionCursors))
+
+	state.IsInitialized = true
+	state.HighestBlockFinalized = 10
+	behind, err = ProcessSubscriptions(context.Background(), eth, contracts, state, logger, registry, 4)
+	require.Nil(t, err)
+	assert.True(t, behind)
+	assert.Equal(t, []uint64{5, 6, 7, 8}, seen)
+	assert.Equal(t, []uint64{5, 6, 7}, confirmed)
+	assert.Equal(t, uint64(8), state.SubscriptionCursors["seen"])
+	assert.Equal(t, uint64(7), state.SubscriptionCursors["confirmed"])
+
+	// a failing processor keeps its subscription before the failing block
+	failAt = 9
+	behind, err = ProcessSubscriptions(context.Background(), eth, contracts, state, logger, registry, 4)
+	assert.NotNil(t, err)
+	assert.True(t, behind)
+	assert.Equal(t, []uint64{5, 6, 7, 8}, seen)
+	assert.Equal(t, uint64(8), state.SubscriptionCursors["seen"])
+	assert.Equal(t, uint64(7), state.SubscriptionCursors["confirmed"])
+
+	failAt = 0
+	behind, err = ProcessSubscriptions(context.Background(), eth, contracts, state, logger, registry, 4)
+	require.Nil(t, err)
+	assert.False(t, behind)
+	assert.Equal(t, []uint64{5, 6, 7, 8, 9, 10}, seen)
+	assert.Equal(t, []uint64{5, 6, 7}, confirmed)
+	assert.Equal(t, uint64(10), state.SubscriptionCursors["seen"])
+	assert.Equal(t, uint64(7), state.SubscriptionCursors["confirmed"])
+}
@@ -165,6 +165,8 @@ type Hardhat struct {
 	configPath string
 }
 
+var _ Layer1Node = &Hardhat{}
+
 func StartHardHatNodeWithDefaultHost() (*Hardhat, error) {
 	return StartHardHatNode("127.0.0.1", "8545")
 }
@@ -232,6 +234,11 @@ func StartHardHatNode(hostname, port string) (*Hardhat, error) {
 	return hardhat, nil
 }
 
+// GetEndpoint returns the url of the hardhat node.
+func (h *Hardhat) GetEndpoint() string {
+	return h.url
+}
+
 func (h *Hardhat) WaitForHardHatNode(ctx context.Context) error {
 	logger := logging.GetLogger("test")
 	c := http.Client{}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Layer1BackendEnv is the environment variable choosing the layer1 node
+// started by StartLayer1Node, either "simulated" or "hardhat".
+const Layer1BackendEnv string = "LAYER1_BACKEND"
+
+// Layer1Node is a local layer1 node the test fixtures run against.
+type Layer1Node interface {
+	GetEndpoint() string
+	DeployFactoryAndContracts(tmpDir, baseFilesDir string) (string, error)
+	RegisterValidators(factoryAddress string, validators []string) error
+	Close() error
+}
+
+// StartLayer1Node starts the layer1 node of the tests. The node is the
+// in-process simulated node, unless the hardhat node is chosen with the
+// LAYER1_BACKEND environment variable or the tests deploy contracts while the
+// bindings don't embed their bytecode yet (see ErrMissingBytecode).
+func StartLayer1Node(deployContracts bool) (Layer1Node, error) {
+	backend, found := os.LookupEnv(Layer1BackendEnv)
+	if !found {
+		backend = "simulated"
+		if deployContracts && !HasContractsBytecode() {
+			backend = "hardhat"
+		}
+	}
+	switch backend {
+	case "simulated":
+		node, err := StartSimulatedNode()
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	case "hardhat":
+		hardhat, err := StartHardHatNodeWithDefaultHost()
+		if err != nil {
+			return nil, err
+		}
+		return hardhat, nil
+	default:
+		return nil, fmt.Errorf("unknown %v: %v", Layer1BackendEnv, backend)
+	}
+}
+
+// HasContractsBytecode returns true if the bindings embed the bytecode of
+// all the contracts of the default deployment config.
+func HasContractsBytecode() bool {
+	baseFilesDir := filepath.Join(GetProjectRootPath(), "scripts", "base-files")
+	deployments, err := readDeploymentConfig(baseFilesDir)
+	if err != nil {
+		return false
+	}
+	return len(missingBytecode(deployments)) == 0
+}
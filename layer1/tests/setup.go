@@ -104,8 +104,8 @@ func InitializePrivateKeys(n int) []*ecdsa.PrivateKey {
 	return privateKeys
 }
 
-// GetAdminAccount gets the admin account for the hardhat node. If that admin
-// account is changed in the hardhat configs change this.
+// GetAdminAccount gets the admin account for the hardhat and simulated nodes.
+// If that admin account is changed in the hardhat configs change this.
 func GetAdminAccount() (common.Address, *ecdsa.PrivateKey) {
 	privateKey, err := crypto.HexToECDSA(TestAdminPrivateKey)
 	if err != nil {
@@ -214,7 +214,7 @@ type ClientFixture struct {
 }
 
 func NewClientFixture(
-	hardhat *Hardhat,
+	node Layer1Node,
 	finalityDelay uint64,
 	numAccounts int,
 	logger *logrus.Entry,
@@ -229,7 +229,7 @@ func NewClientFixture(
 	defaultAccount, _ := GetAdminAccount()
 	eth, err := evm.NewClient(
 		logger.Logger,
-		hardhat.url,
+		node.GetEndpoint(),
 		keyStorePath,
 		passCodePath,
 		defaultAccount.Hex(),
@@ -242,7 +242,7 @@ func NewClientFixture(
 		panic(fmt.Errorf("failed to create ethereum client: %v", err))
 	}
 
-	ResetHardhatConfigs(hardhat.url)
+	ResetHardhatConfigs(node.GetEndpoint())
 
 	MonitorDb := mocks.NewTestDB()
 
@@ -257,7 +257,7 @@ func NewClientFixture(
 	var contracts layer1.AllSmartContracts
 	if deployContracts {
 		baseFilesDir := filepath.Join(GetProjectRootPath(), "scripts", "base-files")
-		factoryAddress, err = hardhat.DeployFactoryAndContracts(tempDir, baseFilesDir)
+		factoryAddress, err = node.DeployFactoryAndContracts(tempDir, baseFilesDir)
 		if err != nil {
 			panic(fmt.Errorf("failed to deploy factory: %v", err))
 		}
@@ -270,7 +270,7 @@ func NewClientFixture(
 		}
 		contracts = handlers.NewAllSmartContractsHandle(eth, common.HexToAddress(factoryAddress))
 		if registerValidators {
-			err = hardhat.RegisterValidators(factoryAddress, validatorsAddresses)
+			err = node.RegisterValidators(factoryAddress, validatorsAddresses)
 			if err != nil {
 				panic(err)
 			}
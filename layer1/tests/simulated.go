@@ -0,0 +1,655 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/alicenet/alicenet/logging"
+)
+
+const (
+	// SimulatedNodeGasLimit is the gas limit of the blocks of the simulated
+	// node, same as the hardhat default.
+	SimulatedNodeGasLimit uint64 = 30_000_000
+	// SimulatedNodeDefaultCallGas is the gas used by the calls that don't
+	// specify one.
+	SimulatedNodeDefaultCallGas uint64 = 50_000_000
+)
+
+var (
+	ErrBaseFeeNotSupported = errors.New("the simulated node cannot set the base fee of the next block")
+	ErrBlockNotFound       = errors.New("block not found")
+)
+
+// SimulatedNode is an in-process layer1 node built on go-ethereum's
+// simulated backend. It serves over http the part of the ethereum json rpc
+// api used by evm.Client, and the hardhat_* and evm_* rpcs used by the test
+// helpers to control the mining and the time, so that the fixtures run
+// offline without a hardhat process.
+type SimulatedNode struct {
+	mu         sync.Mutex
+	backend    *backends.SimulatedBackend
+	autoMine   bool
+	stopMining chan struct{}
+	url        string
+	server     *http.Server
+	rpcServer  *rpc.Server
+}
+
+var _ Layer1Node = &SimulatedNode{}
+
+// StartSimulatedNode starts a simulated node listening on a random local
+// port. The test admin account is funded at genesis.
+func StartSimulatedNode() (*SimulatedNode, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not listen for the simulated node: %w", err)
+	}
+
+	node := &SimulatedNode{
+		backend:   newSimulatedBackend(),
+		autoMine:  true,
+		url:       fmt.Sprintf("http://%s", listener.Addr().String()),
+		rpcServer: rpc.NewServer(),
+	}
+	apis := map[string]interface{}{
+		"eth":     &simulatedEthAPI{node},
+		"net":     &simulatedNetAPI{},
+		"hardhat": &simulatedHardhatAPI{node},
+		"evm":     &simulatedEvmAPI{node},
+	}
+	for name, api := range apis {
+		if err := node.rpcServer.RegisterName(name, api); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("could not register %s api: %w", name, err)
+		}
+	}
+
+	node.server = &http.Server{Handler: node.rpcServer, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := node.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.GetLogger("test").Errorf("simulated node stopped serving: %v", err)
+		}
+	}()
+
+	logging.GetLogger("test").Infof("Simulated node started at %v", node.url)
+	return node, nil
+}
+
+func newSimulatedBackend() *backends.SimulatedBackend {
+	admin, _ := GetAdminAccount()
+	balance, _ := new(big.Int).SetString("1500000000000000000000000000000", 10)
+	return backends.NewSimulatedBackend(
+		core.GenesisAlloc{admin: {Balance: balance}},
+		SimulatedNodeGasLimit,
+	)
+}
+
+// GetEndpoint returns the url of the json rpc server of the node.
+func (n *SimulatedNode) GetEndpoint() string {
+	return n.url
+}
+
+// Backend returns the simulated backend currently run by the node. The
+// backend is replaced when the node is reset.
+func (n *SimulatedNode) Backend() *backends.SimulatedBackend {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.backend
+}
+
+// Close stops the json rpc server and the simulated chain.
+func (n *SimulatedNode) Close() error {
+	n.SetIntervalMining(0)
+	ctx, cf := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cf()
+	err := n.server.Shutdown(ctx)
+	n.rpcServer.Stop()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.backend.Close()
+	return err
+}
+
+// MineBlocks mines a number of blocks. The first block includes the pending
+// transactions.
+func (n *SimulatedNode) MineBlocks(blocks uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := uint64(0); i < blocks; i++ {
+		n.backend.Commit()
+	}
+}
+
+// SetAutoMine enables/disables mining a block for every transaction sent.
+func (n *SimulatedNode) SetAutoMine(autoMine bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.autoMine = autoMine
+}
+
+// SetIntervalMining mines a block every interval. An interval of 0 stops the
+// interval mining.
+func (n *SimulatedNode) SetIntervalMining(interval time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopMining != nil {
+		close(n.stopMining)
+		n.stopMining = nil
+	}
+	if interval == 0 {
+		return
+	}
+	stop := make(chan struct{})
+	n.stopMining = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				n.MineBlocks(1)
+			}
+		}
+	}()
+}
+
+// IncreaseTime shifts the timestamp of the next block. It fails if there are
+// pending transactions.
+func (n *SimulatedNode) IncreaseTime(adjustment time.Duration) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.backend.AdjustTime(adjustment)
+}
+
+// Reset restarts the node from its genesis. Auto mining is enabled again.
+func (n *SimulatedNode) Reset() {
+	n.SetIntervalMining(0)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.backend.Close()
+	n.backend = newSimulatedBackend()
+	n.autoMine = true
+}
+
+// sendTransaction adds the transaction to the pending block, and mines it if
+// auto mining is enabled.
+func (n *SimulatedNode) sendTransaction(ctx context.Context, tx *types.Transaction) (err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// the simulated backend panics on transactions that cannot be included
+	// (e.g. fee cap below the base fee, insufficient funds).
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("transaction %v rejected: %v", tx.Hash().Hex(), r)
+		}
+	}()
+	if err := n.backend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if n.autoMine {
+		n.backend.Commit()
+	}
+	return nil
+}
+
+// callAt executes a call on the state at the end of block. The simulated
+// backend only executes calls on the latest and pending states.
+func (n *SimulatedNode) callAt(msg ethereum.CallMsg, block *types.Block) ([]byte, error) {
+	chain := n.Backend().Blockchain()
+	stateDB, err := chain.StateAt(block.Root())
+	if err != nil {
+		return nil, err
+	}
+	if msg.Gas == 0 {
+		msg.Gas = SimulatedNodeDefaultCallGas
+	}
+	if msg.Value == nil {
+		msg.Value = new(big.Int)
+	}
+	stateDB.SetBalance(msg.From, math.MaxBig256)
+	message := types.NewMessage(
+		msg.From,
+		msg.To,
+		stateDB.GetNonce(msg.From),
+		msg.Value,
+		msg.Gas,
+		new(big.Int),
+		new(big.Int),
+		new(big.Int),
+		msg.Data,
+		nil,
+		true,
+	)
+	evm := vm.NewEVM(
+		core.NewEVMBlockContext(block.Header(), chain, nil),
+		core.NewEVMTxContext(message),
+		stateDB,
+		chain.Config(),
+		vm.Config{NoBaseFee: true},
+	)
+	result, err := core.NewStateTransition(evm, message, new(core.GasPool).AddGas(math.MaxUint64)).
+		TransitionDb()
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Revert()) > 0 {
+		return nil, newRevertError(result.Revert())
+	}
+	return result.Return(), result.Err
+}
+
+// revertError is an execution reverted error carrying the revert data, as
+// returned by the ethereum nodes.
+type revertError struct {
+	reason string
+	data   []byte
+}
+
+func newRevertError(data []byte) *revertError {
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return &revertError{data: data}
+	}
+	return &revertError{reason: reason, data: data}
+}
+
+func (e *revertError) Error() string {
+	if e.reason == "" {
+		return vm.ErrExecutionReverted.Error()
+	}
+	return fmt.Sprintf("%v: %v", vm.ErrExecutionReverted, e.reason)
+}
+
+// ErrorCode returns the json rpc error code of the reverted executions.
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the revert data.
+func (e *revertError) ErrorData() interface{} {
+	return hexutil.Encode(e.data)
+}
+
+// simulatedCallArgs are the arguments of eth_call and eth_estimateGas.
+type simulatedCallArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+}
+
+func (args *simulatedCallArgs) toCallMsg() ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		To:        args.To,
+		GasPrice:  (*big.Int)(args.GasPrice),
+		GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+		GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+		Value:     (*big.Int)(args.Value),
+	}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	return msg
+}
+
+// blockNumberArg converts a block number parameter to the argument of the
+// simulated backend, nil being the latest block.
+func blockNumberArg(number rpc.BlockNumber) *big.Int {
+	if number < 0 {
+		return nil
+	}
+	return big.NewInt(number.Int64())
+}
+
+// simulatedEthAPI serves the eth namespace.
+type simulatedEthAPI struct {
+	node *SimulatedNode
+}
+
+func (api *simulatedEthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.node.Backend().Blockchain().Config().ChainID)
+}
+
+func (api *simulatedEthAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.node.Backend().Blockchain().CurrentBlock().NumberU64())
+}
+
+func (api *simulatedEthAPI) Syncing() bool {
+	return false
+}
+
+func (api *simulatedEthAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.node.Backend().SuggestGasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+func (api *simulatedEthAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := api.node.Backend().SuggestGasTipCap(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
+func (api *simulatedEthAPI) GetBalance(
+	ctx context.Context,
+	address common.Address,
+	number rpc.BlockNumber,
+) (*hexutil.Big, error) {
+	balance, err := api.node.Backend().BalanceAt(ctx, address, blockNumberArg(number))
+	return (*hexutil.Big)(balance), err
+}
+
+func (api *simulatedEthAPI) GetCode(
+	ctx context.Context,
+	address common.Address,
+	number rpc.BlockNumber,
+) (hexutil.Bytes, error) {
+	if number == rpc.PendingBlockNumber {
+		return api.node.Backend().PendingCodeAt(ctx, address)
+	}
+	return api.node.Backend().CodeAt(ctx, address, blockNumberArg(number))
+}
+
+func (api *simulatedEthAPI) GetTransactionCount(
+	ctx context.Context,
+	address common.Address,
+	number rpc.BlockNumber,
+) (hexutil.Uint64, error) {
+	var nonce uint64
+	var err error
+	if number == rpc.PendingBlockNumber {
+		nonce, err = api.node.Backend().PendingNonceAt(ctx, address)
+	} else {
+		nonce, err = api.node.Backend().NonceAt(ctx, address, blockNumberArg(number))
+	}
+	return hexutil.Uint64(nonce), err
+}
+
+func (api *simulatedEthAPI) GetBlockByNumber(
+	number rpc.BlockNumber,
+	fullTx bool,
+) (map[string]interface{}, error) {
+	chain := api.node.Backend().Blockchain()
+	block := chain.CurrentBlock()
+	if number >= 0 {
+		block = chain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, nil
+	}
+	return api.marshalBlock(block, fullTx)
+}
+
+func (api *simulatedEthAPI) GetBlockByHash(
+	hash common.Hash,
+	fullTx bool,
+) (map[string]interface{}, error) {
+	block := api.node.Backend().Blockchain().GetBlockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+	return api.marshalBlock(block, fullTx)
+}
+
+func (api *simulatedEthAPI) GetTransactionByHash(
+	ctx context.Context,
+	hash common.Hash,
+) (map[string]interface{}, error) {
+	backend := api.node.Backend()
+	tx, isPending, err := backend.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isPending {
+		return api.marshalTransaction(tx, nil, 0)
+	}
+	receipt, err := backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	block := backend.Blockchain().GetBlockByHash(receipt.BlockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	return api.marshalTransaction(tx, block, receipt.TransactionIndex)
+}
+
+func (api *simulatedEthAPI) GetTransactionReceipt(
+	ctx context.Context,
+	hash common.Hash,
+) (*types.Receipt, error) {
+	receipt, err := api.node.Backend().TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Logs == nil {
+		receipt.Logs = []*types.Log{}
+	}
+	return receipt, nil
+}
+
+func (api *simulatedEthAPI) SendRawTransaction(
+	ctx context.Context,
+	input hexutil.Bytes,
+) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.node.sendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+func (api *simulatedEthAPI) Call(
+	ctx context.Context,
+	args simulatedCallArgs,
+	number rpc.BlockNumber,
+) (hexutil.Bytes, error) {
+	backend := api.node.Backend()
+	msg := args.toCallMsg()
+	current := backend.Blockchain().CurrentBlock()
+	switch {
+	case number == rpc.PendingBlockNumber:
+		return backend.PendingCallContract(ctx, msg)
+	case number < 0 || uint64(number) == current.NumberU64():
+		return backend.CallContract(ctx, msg, nil)
+	}
+	block := backend.Blockchain().GetBlockByNumber(uint64(number))
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	return api.node.callAt(msg, block)
+}
+
+func (api *simulatedEthAPI) EstimateGas(
+	ctx context.Context,
+	args simulatedCallArgs,
+	_ *rpc.BlockNumber,
+) (hexutil.Uint64, error) {
+	gas, err := api.node.Backend().EstimateGas(ctx, args.toCallMsg())
+	return hexutil.Uint64(gas), err
+}
+
+func (api *simulatedEthAPI) GetLogs(
+	ctx context.Context,
+	criteria filters.FilterCriteria,
+) ([]types.Log, error) {
+	logs, err := api.node.Backend().FilterLogs(ctx, ethereum.FilterQuery(criteria))
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	return logs, nil
+}
+
+// marshalBlock encodes a block the way the ethereum nodes do, with either the
+// hashes or the full transactions.
+func (api *simulatedEthAPI) marshalBlock(
+	block *types.Block,
+	fullTx bool,
+) (map[string]interface{}, error) {
+	fields, err := toJSONFields(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	chain := api.node.Backend().Blockchain()
+	fields["size"] = hexutil.Uint64(block.Size())
+	fields["totalDifficulty"] = (*hexutil.Big)(chain.GetTd(block.Hash(), block.NumberU64()))
+
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !fullTx {
+			txs[i] = tx.Hash()
+			continue
+		}
+		txs[i], err = api.marshalTransaction(tx, block, uint(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	fields["transactions"] = txs
+
+	uncles := make([]common.Hash, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+	fields["uncles"] = uncles
+	return fields, nil
+}
+
+// marshalTransaction encodes a transaction with its sender and, once mined,
+// its position in the chain.
+func (api *simulatedEthAPI) marshalTransaction(
+	tx *types.Transaction,
+	block *types.Block,
+	index uint,
+) (map[string]interface{}, error) {
+	fields, err := toJSONFields(tx)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSigner(api.node.Backend().Blockchain().Config())
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	fields["from"] = from
+	if block != nil {
+		fields["blockHash"] = block.Hash()
+		fields["blockNumber"] = (*hexutil.Big)(block.Number())
+		fields["transactionIndex"] = hexutil.Uint64(index)
+	}
+	return fields, nil
+}
+
+func toJSONFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// simulatedNetAPI serves the net namespace.
+type simulatedNetAPI struct{}
+
+func (api *simulatedNetAPI) PeerCount() hexutil.Uint {
+	return 0
+}
+
+// simulatedHardhatAPI serves the hardhat rpcs used by the test helpers.
+type simulatedHardhatAPI struct {
+	node *SimulatedNode
+}
+
+func (api *simulatedHardhatAPI) Mine(blocks *hexutil.Uint64) {
+	count := uint64(1)
+	if blocks != nil {
+		count = uint64(*blocks)
+	}
+	api.node.MineBlocks(count)
+}
+
+func (api *simulatedHardhatAPI) Reset() bool {
+	api.node.Reset()
+	return true
+}
+
+func (api *simulatedHardhatAPI) SetNextBlockBaseFeePerGas(_ hexutil.Big) error {
+	return ErrBaseFeeNotSupported
+}
+
+// simulatedEvmAPI serves the evm rpcs used by the test helpers.
+type simulatedEvmAPI struct {
+	node *SimulatedNode
+}
+
+func (api *simulatedEvmAPI) Mine() string {
+	api.node.MineBlocks(1)
+	return "0x0"
+}
+
+func (api *simulatedEvmAPI) SetAutomine(autoMine bool) bool {
+	api.node.SetAutoMine(autoMine)
+	return true
+}
+
+func (api *simulatedEvmAPI) SetIntervalMining(intervalInMilliSeconds uint64) bool {
+	api.node.SetIntervalMining(time.Duration(intervalInMilliSeconds) * time.Millisecond)
+	return true
+}
+
+func (api *simulatedEvmAPI) IncreaseTime(seconds uint64) (uint64, error) {
+	if err := api.node.IncreaseTime(time.Duration(seconds) * time.Second); err != nil {
+		return 0, err
+	}
+	return seconds, nil
+}
@@ -0,0 +1,458 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/logging"
+)
+
+const (
+	DeploymentConfigFile string = "deploymentConfig.json"
+	// ValidatorStakeAmount is the amount of ALCA staked by each validator
+	// registered by the test fixtures (5M ALCA).
+	ValidatorStakeAmount string = "5000000000000000000000000"
+)
+
+var ErrMissingBytecode = errors.New(
+	"the bindings don't embed the bytecode of the contracts, regenerate them with npm run generate in bridge",
+)
+
+// contractsMetaData are the bindings of the contracts that the simulated
+// node can deploy.
+var contractsMetaData = map[string]*bind.MetaData{
+	"AliceNetFactory":  bindings.AliceNetFactoryMetaData,
+	"ALCA":             bindings.ALCAMetaData,
+	"ALCABurner":       bindings.ALCABurnerMetaData,
+	"ALCAMinter":       bindings.ALCAMinterMetaData,
+	"ALCB":             bindings.ALCBMetaData,
+	"Dynamics":         bindings.DynamicsMetaData,
+	"ETHDKG":           bindings.ETHDKGMetaData,
+	"Governance":       bindings.GovernanceMetaData,
+	"PublicStaking":    bindings.PublicStakingMetaData,
+	"Snapshots":        bindings.SnapshotsMetaData,
+	"ValidatorPool":    bindings.ValidatorPoolMetaData,
+	"ValidatorStaking": bindings.ValidatorStakingMetaData,
+}
+
+// contractDeployment is an entry of the deployment config of the hardhat
+// deployContracts task.
+type contractDeployment struct {
+	Name            string                 `json:"name"`
+	Salt            string                 `json:"salt"`
+	DeployType      string                 `json:"deployType"`
+	ConstructorArgs map[string]interface{} `json:"constructorArgs"`
+	InitializerArgs map[string]interface{} `json:"initializerArgs"`
+}
+
+// readDeploymentConfig reads the contracts of the deployment config in
+// their deployment order.
+func readDeploymentConfig(baseFilesDir string) ([]contractDeployment, error) {
+	file, err := os.Open(filepath.Join(baseFilesDir, DeploymentConfigFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not open deployment config: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("could not read deployment config: %w", err)
+	}
+	var deployments []contractDeployment
+	for decoder.More() {
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("could not read deployment config: %w", err)
+		}
+		var deployment contractDeployment
+		if err := decoder.Decode(&deployment); err != nil {
+			return nil, fmt.Errorf("could not read deployment config: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+	return deployments, nil
+}
+
+// missingBytecode returns the contracts without bytecode in the bindings.
+func missingBytecode(deployments []contractDeployment) []string {
+	var missing []string
+	for _, deployment := range deployments {
+		metaData, ok := contractsMetaData[deployment.Name]
+		if !ok || metaData.Bin == "" {
+			missing = append(missing, deployment.Name)
+		}
+	}
+	return missing
+}
+
+// DeployFactoryAndContracts deploys the AliceNetFactory and, through it, the
+// contracts of the deployment config using the bytecode embedded in the
+// bindings. It returns the factory address.
+func (n *SimulatedNode) DeployFactoryAndContracts(_, baseFilesDir string) (string, error) {
+	deployments, err := readDeploymentConfig(baseFilesDir)
+	if err != nil {
+		return "", err
+	}
+	if missing := missingBytecode(deployments); len(missing) > 0 {
+		return "", fmt.Errorf("%w: %v", ErrMissingBytecode, strings.Join(missing, ", "))
+	}
+
+	d, err := n.newDeployer()
+	if err != nil {
+		return "", err
+	}
+	defer d.client.Close()
+
+	factoryAddress := common.Address{}
+	for _, deployment := range deployments {
+		if deployment.Name == "AliceNetFactory" {
+			factoryAddress, err = d.deployFactory(deployment)
+		} else {
+			err = d.deployContract(factoryAddress, deployment)
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not deploy %v: %w", deployment.Name, err)
+		}
+		logging.GetLogger("test").Debugf("Deployed %v", deployment.Name)
+	}
+	return factoryAddress.Hex(), nil
+}
+
+// RegisterValidators stakes ALCA for the validators and registers them in
+// the ValidatorPool, same as the hardhat registerValidators task.
+func (n *SimulatedNode) RegisterValidators(factoryAddress string, validators []string) error {
+	d, err := n.newDeployer()
+	if err != nil {
+		return err
+	}
+	defer d.client.Close()
+	return d.registerValidators(common.HexToAddress(factoryAddress), validators)
+}
+
+// deployer sends the deployment transactions of the test admin.
+type deployer struct {
+	client *ethclient.Client
+	opts   *bind.TransactOpts
+}
+
+func (n *SimulatedNode) newDeployer() (*deployer, error) {
+	client, err := ethclient.Dial(n.url)
+	if err != nil {
+		return nil, err
+	}
+	_, adminKey := GetAdminAccount()
+	chainID := n.Backend().Blockchain().Config().ChainID
+	opts, err := bind.NewKeyedTransactorWithChainID(adminKey, chainID)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &deployer{client: client, opts: opts}, nil
+}
+
+// waitMined waits for the receipt of tx and checks its status.
+func (d *deployer) waitMined(tx *types.Transaction) (*types.Receipt, error) {
+	ctx, cf := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cf()
+	receipt, err := bind.WaitMined(ctx, d.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %v reverted", tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+func (d *deployer) deployFactory(deployment contractDeployment) (common.Address, error) {
+	contractAbi, bytecode, err := contractCode(deployment.Name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	args, err := orderedArgs(contractAbi.Constructor.Inputs, deployment.ConstructorArgs)
+	if err != nil {
+		return common.Address{}, err
+	}
+	address, tx, _, err := bind.DeployContract(d.opts, *contractAbi, bytecode, d.client, args...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if _, err := d.waitMined(tx); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
+
+func (d *deployer) deployContract(factoryAddress common.Address, deployment contractDeployment) error {
+	factory, err := bindings.NewAliceNetFactory(factoryAddress, d.client)
+	if err != nil {
+		return err
+	}
+	contractAbi, bytecode, err := contractCode(deployment.Name)
+	if err != nil {
+		return err
+	}
+	args, err := orderedArgs(contractAbi.Constructor.Inputs, deployment.ConstructorArgs)
+	if err != nil {
+		return err
+	}
+	constructorData, err := contractAbi.Pack("", args...)
+	if err != nil {
+		return err
+	}
+	deployCode := append(bytecode, constructorData...)
+	salt, err := parseSalt(deployment.Salt)
+	if err != nil {
+		return err
+	}
+
+	switch deployment.DeployType {
+	case "deployCreateAndRegister":
+		tx, err := factory.DeployCreateAndRegister(d.opts, deployCode, salt)
+		if err != nil {
+			return err
+		}
+		_, err = d.waitMined(tx)
+		return err
+	case "deployUpgradeable":
+		initCallData := []byte{}
+		if initialize, ok := contractAbi.Methods["initialize"]; ok {
+			args, err := orderedArgs(initialize.Inputs, deployment.InitializerArgs)
+			if err != nil {
+				return err
+			}
+			initCallData, err = contractAbi.Pack("initialize", args...)
+			if err != nil {
+				return err
+			}
+		}
+		tx, err := factory.DeployCreate(d.opts, deployCode)
+		if err != nil {
+			return err
+		}
+		receipt, err := d.waitMined(tx)
+		if err != nil {
+			return err
+		}
+		var implementation *bindings.AliceNetFactoryDeployedRaw
+		for _, log := range receipt.Logs {
+			if implementation, err = factory.ParseDeployedRaw(*log); err == nil {
+				break
+			}
+		}
+		if implementation == nil {
+			return errors.New("could not find the DeployedRaw event")
+		}
+		if tx, err = factory.DeployProxy(d.opts, salt); err != nil {
+			return err
+		}
+		if _, err := d.waitMined(tx); err != nil {
+			return err
+		}
+		tx, err = factory.UpgradeProxy(d.opts, salt, implementation.ContractAddr, initCallData)
+		if err != nil {
+			return err
+		}
+		_, err = d.waitMined(tx)
+		return err
+	default:
+		return fmt.Errorf("unknown deploy type %q", deployment.DeployType)
+	}
+}
+
+func (d *deployer) registerValidators(factoryAddress common.Address, validators []string) error {
+	factory, err := bindings.NewAliceNetFactory(factoryAddress, d.client)
+	if err != nil {
+		return err
+	}
+	addresses := make(map[string]common.Address)
+	for _, name := range []string{"ALCA", "PublicStaking", "ValidatorPool"} {
+		var salt [32]byte
+		copy(salt[:], name)
+		addresses[name], err = factory.Lookup(&bind.CallOpts{}, salt)
+		if err != nil {
+			return fmt.Errorf("could not lookup %v: %w", name, err)
+		}
+	}
+	alcaAbi, err := bindings.ALCAMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	stakingAbi, err := bindings.PublicStakingMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	poolAbi, err := bindings.ValidatorPoolMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	// stake the ALCA of the factory, minting a position per validator
+	stakeAmount, _ := new(big.Int).SetString(ValidatorStakeAmount, 10)
+	total := new(big.Int).Mul(stakeAmount, big.NewInt(int64(len(validators))))
+	approve, err := alcaAbi.Pack("approve", addresses["PublicStaking"], total)
+	if err != nil {
+		return err
+	}
+	calls := []bindings.AliceNetFactoryBaseMultiCallArgs{
+		{Target: addresses["ALCA"], Value: new(big.Int), Data: approve},
+	}
+	for range validators {
+		mint, err := stakingAbi.Pack("mint", stakeAmount)
+		if err != nil {
+			return err
+		}
+		calls = append(
+			calls,
+			bindings.AliceNetFactoryBaseMultiCallArgs{Target: addresses["PublicStaking"], Value: new(big.Int), Data: mint},
+		)
+	}
+	tx, err := factory.MultiCall(d.opts, calls)
+	if err != nil {
+		return err
+	}
+	receipt, err := d.waitMined(tx)
+	if err != nil {
+		return err
+	}
+
+	// register the validators with the minted positions
+	transferTopic := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	var validatorAddresses []common.Address
+	var tokenIDs []*big.Int
+	calls = nil
+	for _, log := range receipt.Logs {
+		if log.Address != addresses["PublicStaking"] || len(log.Topics) != 4 ||
+			log.Topics[0] != transferTopic {
+			continue
+		}
+		tokenID := log.Topics[3].Big()
+		approve, err := stakingAbi.Pack("approve", addresses["ValidatorPool"], tokenID)
+		if err != nil {
+			return err
+		}
+		calls = append(
+			calls,
+			bindings.AliceNetFactoryBaseMultiCallArgs{Target: addresses["PublicStaking"], Value: new(big.Int), Data: approve},
+		)
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	for _, validator := range validators {
+		validatorAddresses = append(validatorAddresses, common.HexToAddress(validator))
+	}
+	register, err := poolAbi.Pack("registerValidators", validatorAddresses, tokenIDs)
+	if err != nil {
+		return err
+	}
+	calls = append(
+		calls,
+		bindings.AliceNetFactoryBaseMultiCallArgs{Target: addresses["ValidatorPool"], Value: new(big.Int), Data: register},
+	)
+	if tx, err = factory.MultiCall(d.opts, calls); err != nil {
+		return err
+	}
+	_, err = d.waitMined(tx)
+	return err
+}
+
+func contractCode(name string) (*abi.ABI, []byte, error) {
+	metaData, ok := contractsMetaData[name]
+	if !ok || metaData.Bin == "" {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMissingBytecode, name)
+	}
+	contractAbi, err := metaData.GetAbi()
+	if err != nil {
+		return nil, nil, err
+	}
+	bytecode, err := hexutil.Decode(metaData.Bin)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contractAbi, bytecode, nil
+}
+
+func parseSalt(value string) ([32]byte, error) {
+	var salt [32]byte
+	if value == "" {
+		return salt, nil
+	}
+	decoded, err := hexutil.Decode(value)
+	if err != nil || len(decoded) != len(salt) {
+		return salt, fmt.Errorf("invalid salt %q", value)
+	}
+	copy(salt[:], decoded)
+	return salt, nil
+}
+
+// orderedArgs converts the named deployment config values to the arguments
+// of the abi inputs.
+func orderedArgs(inputs abi.Arguments, values map[string]interface{}) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(inputs))
+	for _, input := range inputs {
+		value, ok := values[input.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing argument %v", input.Name)
+		}
+		arg, err := convertArg(input.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %v: %w", input.Name, err)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func convertArg(argType abi.Type, value interface{}) (interface{}, error) {
+	text := fmt.Sprint(value)
+	switch argType.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(text) {
+			return nil, fmt.Errorf("invalid address %q", text)
+		}
+		return common.HexToAddress(text), nil
+	case abi.BoolTy:
+		return text == "true", nil
+	case abi.StringTy:
+		return text, nil
+	case abi.UintTy, abi.IntTy:
+		number, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		goType := argType.GetType()
+		if goType == reflect.TypeOf(number) {
+			return number, nil
+		}
+		if argType.T == abi.UintTy {
+			return reflect.ValueOf(number.Uint64()).Convert(goType).Interface(), nil
+		}
+		return reflect.ValueOf(number.Int64()).Convert(goType).Interface(), nil
+	case abi.FixedBytesTy:
+		decoded, err := hexutil.Decode(text)
+		if err != nil || len(decoded) != argType.Size {
+			return nil, fmt.Errorf("invalid bytes%d %q", argType.Size, text)
+		}
+		arg := reflect.New(argType.GetType()).Elem()
+		reflect.Copy(arg, reflect.ValueOf(decoded))
+		return arg.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %v", argType)
+	}
+}
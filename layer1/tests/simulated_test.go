@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startSimulatedNode(t *testing.T) (*SimulatedNode, *ethclient.Client) {
+	t.Helper()
+	node, err := StartSimulatedNode()
+	require.Nil(t, err)
+	client, err := ethclient.Dial(node.GetEndpoint())
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		client.Close()
+		assert.Nil(t, node.Close())
+	})
+	return node, client
+}
+
+func sendTransfer(t *testing.T, client *ethclient.Client, to common.Address) *types.Transaction {
+	t.Helper()
+	ctx := context.Background()
+	admin, adminKey := GetAdminAccount()
+	chainID, err := client.ChainID(ctx)
+	require.Nil(t, err)
+	nonce, err := client.PendingNonceAt(ctx, admin)
+	require.Nil(t, err)
+	header, err := client.HeaderByNumber(ctx, nil)
+	require.Nil(t, err)
+	tx, err := types.SignNewTx(adminKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: new(big.Int).Mul(header.BaseFee, big.NewInt(2)),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1000),
+	})
+	require.Nil(t, err)
+	require.Nil(t, client.SendTransaction(ctx, tx))
+	return tx
+}
+
+func TestSimulatedNode_AutoMine(t *testing.T) {
+	_, client := startSimulatedNode(t)
+	ctx := context.Background()
+
+	chainID, err := client.ChainID(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1337), chainID.Int64())
+
+	to := common.HexToAddress("0x1000")
+	tx := sendTransfer(t, client, to)
+
+	height, err := client.BlockNumber(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, uint64(1), receipt.BlockNumber.Uint64())
+
+	minedTx, isPending, err := client.TransactionByHash(ctx, tx.Hash())
+	assert.Nil(t, err)
+	assert.False(t, isPending)
+	assert.Equal(t, tx.Hash(), minedTx.Hash())
+
+	block, err := client.BlockByNumber(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(block.Transactions()))
+
+	balance, err := client.BalanceAt(ctx, to, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), balance.Int64())
+	balance, err = client.BalanceAt(ctx, to, big.NewInt(0))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), balance.Int64())
+}
+
+func TestSimulatedNode_ManualMining(t *testing.T) {
+	node, client := startSimulatedNode(t)
+	ctx := context.Background()
+
+	SetAutoMine(node.GetEndpoint(), false)
+	tx := sendTransfer(t, client, common.HexToAddress("0x2000"))
+
+	_, err := client.TransactionReceipt(ctx, tx.Hash())
+	assert.Equal(t, ethereum.NotFound, err)
+	_, isPending, err := client.TransactionByHash(ctx, tx.Hash())
+	assert.Nil(t, err)
+	assert.True(t, isPending)
+
+	MineBlocks(node.GetEndpoint(), 3)
+	height, err := client.BlockNumber(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), height)
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), receipt.BlockNumber.Uint64())
+
+	// calls are executed at any height
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &common.Address{}}, big.NewInt(1))
+	assert.Nil(t, err)
+	assert.Empty(t, result)
+
+	ResetHardhatConfigs(node.GetEndpoint())
+	height, err = client.BlockNumber(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), height)
+	sendTransfer(t, client, common.HexToAddress("0x2000"))
+	height, err = client.BlockNumber(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), height)
+}
+
+func TestSimulatedNode_GetLogs(t *testing.T) {
+	node, client := startSimulatedNode(t)
+	MineBlocks(node.GetEndpoint(), 5)
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		ToBlock:   big.NewInt(5),
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestSimulatedNode_DeployFactoryAndContracts(t *testing.T) {
+	node, _ := startSimulatedNode(t)
+	baseFilesDir := filepath.Join(GetProjectRootPath(), "scripts", "base-files")
+	factoryAddress, err := node.DeployFactoryAndContracts(t.TempDir(), baseFilesDir)
+	if !HasContractsBytecode() {
+		assert.ErrorIs(t, err, ErrMissingBytecode)
+		return
+	}
+	assert.Nil(t, err)
+	assert.True(t, common.IsHexAddress(factoryAddress))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/constants/dbprefix"
 	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/gas"
 	"github.com/alicenet/alicenet/logging"
 	"github.com/alicenet/alicenet/utils"
 )
@@ -50,6 +52,7 @@ type monitored struct {
 	FromAddress       common.Address     `json:"fromAddress"`       // address of the transaction signer
 	Selector          *FuncSelector      `json:"selector"`          // 4 bytes that identify the function being called by the tx
 	FunctionSignature string             `json:"functionSignature"` // function signature as we see on the smart contracts
+	Task              string             `json:"task"`              // type of the task that sent the tx, empty if not sent by a task
 	RetryGroup        common.Hash        `json:"retryGroup"`        // internal group Id to keep track of all tx that were created during the retry of a tx
 	EnableAutoRetry   bool               `json:"disableAutoRetry"`  // whether we should disable the auto retry of a transaction
 	MaxStaleBlocks    uint64             `json:"maxStaleBlocks"`    // maximum number of blocks before we consider a transaction stale
@@ -63,6 +66,7 @@ func newMonitored(
 	fromAddr common.Address,
 	selector *FuncSelector,
 	sig string,
+	task string,
 	retryGroup common.Hash,
 	enableAutoRetry bool,
 	maxStaleBlocks uint64,
@@ -72,6 +76,7 @@ func newMonitored(
 		FromAddress:       fromAddr,
 		Selector:          selector,
 		FunctionSignature: sig,
+		Task:              task,
 		RetryGroup:        retryGroup,
 		EnableAutoRetry:   enableAutoRetry,
 		MaxStaleBlocks:    maxStaleBlocks,
@@ -84,6 +89,7 @@ func newReplacedMonitored(newTxn *types.Transaction, originalMonitoredTxn monito
 		originalMonitoredTxn.FromAddress,
 		originalMonitoredTxn.Selector,
 		originalMonitoredTxn.FunctionSignature,
+		originalMonitoredTxn.Task,
 		originalMonitoredTxn.RetryGroup,
 		originalMonitoredTxn.EnableAutoRetry,
 		originalMonitoredTxn.MaxStaleBlocks,
@@ -234,6 +240,7 @@ func (a *block) Equal(b *block) bool {
 // SubscribeResponseChannel should be set.
 type SubscribeRequest struct {
 	txn              *types.Transaction        // the transaction that should watched
+	task             string                    // type of the task that sent the transaction
 	subscribeOptions *SubscribeOptions         // whether we should disable the auto retry of a transaction
 	responseChannel  *SubscribeResponseChannel // channel where we're going to send the request response
 }
@@ -282,28 +289,39 @@ func (rc *SubscribeResponseChannel) sendResponse(response *SubscribeResponse) {
 
 // Profile to keep track of gas metrics in the overall system.
 type Profile struct {
-	AverageGas   uint64 `json:"averageGas"`
-	MinimumGas   uint64 `json:"minimumGas"`
-	MaximumGas   uint64 `json:"maximumGas"`
-	TotalGas     uint64 `json:"totalGas"`
-	TotalCount   uint64 `json:"totalCount"`
-	TotalSuccess uint64 `json:"totalSuccess"`
+	AverageGas   uint64   `json:"averageGas"`
+	MinimumGas   uint64   `json:"minimumGas"`
+	MaximumGas   uint64   `json:"maximumGas"`
+	TotalGas     uint64   `json:"totalGas"`
+	TotalCount   uint64   `json:"totalCount"`
+	TotalSuccess uint64   `json:"totalSuccess"`
+	TotalSpent   *big.Int `json:"totalSpent"` // fees paid in wei
+}
+
+// GasReport is the gas spent by the transactions watched, per function called
+// and per type of the task that sent them.
+type GasReport struct {
+	Functions map[string]Profile
+	Tasks     map[string]Profile
 }
 
 // WatcherBackend is a backend struct used to monitor Ethereum transactions and retrieve their receipts.
 type WatcherBackend struct {
-	mainCtx            context.Context           `json:"-"`             // main context for the background services
-	lastProcessedBlock *block                    `json:"-"`             // Last ethereum block that we checked for receipts
-	MonitoredTxns      map[common.Hash]monitored `json:"monitoredTxns"` // Map of transactions whose receipts we're looking for
-	ReceiptCache       map[common.Hash]receipt   `json:"receiptCache"`  // Receipts retrieved from transactions. The keys are txGroup hashes
-	Aggregates         map[FuncSelector]Profile  `json:"aggregates"`    // Struct to keep track of the gas metrics used by the system
-	RetryGroups        map[common.Hash]group     `json:"retryGroups"`   // Map of groups of transactions that were retried
-	client             layer1.Client             `json:"-"`             // An interface with the ethereum functionality we need
-	logger             *logrus.Entry             `json:"-"`             // Logger to log messages
-	requestChannel     <-chan SubscribeRequest   `json:"-"`             // Channel used to send request to this backend service
-	database           *db.Database              `json:"-"`             // database where we are going to persist and load state
-	metricsDisplay     bool                      `json:"-"`             // flag to display the metrics in the logs. The metrics are still collect even if this flag is false.
-	TxPollingTime      time.Duration             `json:"-"`             // time in seconds which will be polling for transactions receipts
+	mainCtx            context.Context           `json:"-"`              // main context for the background services
+	lastProcessedBlock *block                    `json:"-"`              // Last ethereum block that we checked for receipts
+	MonitoredTxns      map[common.Hash]monitored `json:"monitoredTxns"`  // Map of transactions whose receipts we're looking for
+	ReceiptCache       map[common.Hash]receipt   `json:"receiptCache"`   // Receipts retrieved from transactions. The keys are txGroup hashes
+	Aggregates         map[FuncSelector]Profile  `json:"aggregates"`     // Struct to keep track of the gas metrics used by the system
+	TaskAggregates     map[string]Profile        `json:"taskAggregates"` // Gas metrics per type of the task that sent the transactions
+	aggregatesMutex    sync.RWMutex              `json:"-"`              // guards the aggregates, read by GasReport, and the budget
+	budget             *gas.Budget               `json:"-"`              // budget the fees of the transactions are recorded to
+	RetryGroups        map[common.Hash]group     `json:"retryGroups"`    // Map of groups of transactions that were retried
+	client             layer1.Client             `json:"-"`              // An interface with the ethereum functionality we need
+	logger             *logrus.Entry             `json:"-"`              // Logger to log messages
+	requestChannel     <-chan SubscribeRequest   `json:"-"`              // Channel used to send request to this backend service
+	database           *db.Database              `json:"-"`              // database where we are going to persist and load state
+	metricsDisplay     bool                      `json:"-"`              // flag to display the metrics in the logs. The metrics are still collect even if this flag is false.
+	TxPollingTime      time.Duration             `json:"-"`              // time in seconds which will be polling for transactions receipts
 }
 
 // newWatcherBackend creates a new watcher backend.
@@ -317,6 +335,7 @@ func newWatcherBackend(mainCtx context.Context, requestChannel <-chan SubscribeR
 		MonitoredTxns:      make(map[common.Hash]monitored),
 		ReceiptCache:       make(map[common.Hash]receipt),
 		Aggregates:         make(map[FuncSelector]Profile),
+		TaskAggregates:     make(map[string]Profile),
 		RetryGroups:        make(map[common.Hash]group),
 		lastProcessedBlock: &block{0, common.HexToHash("")},
 		metricsDisplay:     metricsDisplay,
@@ -334,7 +353,12 @@ func (wb *WatcherBackend) LoadState() error {
 		if err != nil {
 			return err
 		}
+		wb.aggregatesMutex.Lock()
 		err = json.Unmarshal(rawData, wb)
+		if wb.TaskAggregates == nil {
+			wb.TaskAggregates = make(map[string]Profile)
+		}
+		wb.aggregatesMutex.Unlock()
 		if err != nil {
 			return err
 		}
@@ -352,7 +376,9 @@ func (wb *WatcherBackend) LoadState() error {
 // PersistState persists the watcher backend state into the database.
 func (wb *WatcherBackend) PersistState() error {
 	logger := logging.GetLogger("staterecover").WithField("State", "txWatcherBackend")
+	wb.aggregatesMutex.RLock()
 	rawData, err := json.Marshal(wb)
+	wb.aggregatesMutex.RUnlock()
 	if err != nil {
 		return fmt.Errorf("failed to marshal %v", err)
 	}
@@ -417,6 +443,11 @@ func (wb *WatcherBackend) Loop() {
 						WithField("Profile", fmt.Sprintf("%+v", profile)).
 						Info("Status")
 				}
+				for task, profile := range wb.TaskAggregates {
+					wb.logger.WithField("Task", task).
+						WithField("Profile", fmt.Sprintf("%+v", profile)).
+						Info("Status")
+				}
 			}
 			statusTime = time.After(constants.TxStatusTime)
 		}
@@ -460,8 +491,9 @@ func (wb *WatcherBackend) queue(req SubscribeRequest) (*SharedReceipt, error) {
 				enableAutoRetry = true
 				maxStaleBlocks = wb.client.GetTxMaxStaleBlocks()
 			}
-			newMonitoredTxn := newMonitored(req.txn, fromAddr, selector, sig, txnHash, enableAutoRetry, maxStaleBlocks)
+			newMonitoredTxn := newMonitored(req.txn, fromAddr, selector, sig, req.task, txnHash, enableAutoRetry, maxStaleBlocks)
 			wb.MonitoredTxns[txnHash] = newMonitoredTxn
+			wb.reserveGas(req.txn, fromAddr)
 			txGroup := newGroup()
 			txGroup.add(txnHash)
 			wb.RetryGroups[txnHash] = txGroup
@@ -599,6 +631,7 @@ func (wb *WatcherBackend) handleWorkerResponse(logEntry *logrus.Entry, workRespo
 			if workResponse.retriedTxn.err == nil && workResponse.retriedTxn.txn != nil {
 				newTxnHash := workResponse.retriedTxn.txn.Hash()
 				wb.MonitoredTxns[newTxnHash] = newReplacedMonitored(workResponse.retriedTxn.txn, monitoredTxn)
+				wb.reserveGas(workResponse.retriedTxn.txn, monitoredTxn.FromAddress)
 				// update retry group
 				txGroup := wb.RetryGroups[monitoredTxn.RetryGroup]
 				txGroup.add(newTxnHash)
@@ -650,7 +683,9 @@ func (wb *WatcherBackend) dispatchFinishedTxs(finishedTxs map[common.Hash]Monito
 					"group": monitoredTxn.RetryGroup,
 				})
 				if workResponse.receipt != nil {
-					wb.Aggregates[*monitoredTxn.Selector] = wb.computeGasProfile(workResponse.receipt, monitoredTxn)
+					wb.recordGas(workResponse.receipt, workResponse.fees, monitoredTxn)
+				} else {
+					wb.releaseGas(txnHash)
 				}
 				txGroup.sendReceipt(logger, workResponse.receipt, workResponse.err)
 				err := txGroup.remove(txnHash)
@@ -672,14 +707,77 @@ func (wb *WatcherBackend) dispatchFinishedTxs(finishedTxs map[common.Hash]Monito
 	}
 }
 
-// computeGasProfile computes the gas profile for every transaction that returned a receipt.
-func (wb *WatcherBackend) computeGasProfile(rcpt *types.Receipt, monitoredTxn monitored) Profile {
-	var profile Profile
-	if _, present := wb.Aggregates[*monitoredTxn.Selector]; present {
-		profile = wb.Aggregates[*monitoredTxn.Selector]
-	} else {
-		profile = Profile{}
+// recordGas adds the gas used and the fees paid by a transaction that returned
+// a receipt to the profiles of its function and task, and to the budget where
+// they replace its reservation.
+func (wb *WatcherBackend) recordGas(rcpt *types.Receipt, fees *big.Int, monitoredTxn monitored) {
+	wb.aggregatesMutex.Lock()
+	wb.Aggregates[*monitoredTxn.Selector] = computeGasProfile(wb.Aggregates[*monitoredTxn.Selector], rcpt, fees)
+	if monitoredTxn.Task != "" {
+		wb.TaskAggregates[monitoredTxn.Task] = computeGasProfile(wb.TaskAggregates[monitoredTxn.Task], rcpt, fees)
+	}
+	budget := wb.budget
+	wb.aggregatesMutex.Unlock()
+
+	if budget != nil {
+		budget.Record(monitoredTxn.FromAddress, monitoredTxn.Txn.Hash(), fees)
+	}
+}
+
+// reserveGas reserves the worst case fee of a transaction just submitted in
+// the budget.
+func (wb *WatcherBackend) reserveGas(txn *types.Transaction, fromAddr common.Address) {
+	wb.aggregatesMutex.RLock()
+	budget := wb.budget
+	wb.aggregatesMutex.RUnlock()
+	if budget != nil {
+		maxFee := new(big.Int).Mul(txn.GasFeeCap(), new(big.Int).SetUint64(txn.Gas()))
+		budget.Reserve(fromAddr, txn.Hash(), maxFee)
+	}
+}
+
+// releaseGas drops the reservation of a transaction that finished without
+// being mined.
+func (wb *WatcherBackend) releaseGas(txnHash common.Hash) {
+	wb.aggregatesMutex.RLock()
+	budget := wb.budget
+	wb.aggregatesMutex.RUnlock()
+	if budget != nil {
+		budget.Release(txnHash)
 	}
+}
+
+// gasReport returns a copy of the aggregates.
+func (wb *WatcherBackend) gasReport() GasReport {
+	wb.aggregatesMutex.RLock()
+	defer wb.aggregatesMutex.RUnlock()
+	report := GasReport{
+		Functions: make(map[string]Profile, len(wb.Aggregates)),
+		Tasks:     make(map[string]Profile, len(wb.TaskAggregates)),
+	}
+	for selector, profile := range wb.Aggregates {
+		name, ok := bindings.FunctionMapping[selector]
+		if !ok {
+			name = fmt.Sprintf("%x", selector)
+		}
+		report.Functions[name] = copyProfile(profile)
+	}
+	for task, profile := range wb.TaskAggregates {
+		report.Tasks[task] = copyProfile(profile)
+	}
+	return report
+}
+
+func copyProfile(profile Profile) Profile {
+	if profile.TotalSpent != nil {
+		profile.TotalSpent = new(big.Int).Set(profile.TotalSpent)
+	}
+	return profile
+}
+
+// computeGasProfile adds a transaction that returned a receipt to a gas
+// profile.
+func computeGasProfile(profile Profile, rcpt *types.Receipt, fees *big.Int) Profile {
 	// Update transaction profile
 	profile.AverageGas = (profile.AverageGas*profile.TotalCount + rcpt.GasUsed) / (profile.TotalCount + 1)
 	if profile.MaximumGas < rcpt.GasUsed {
@@ -693,6 +791,13 @@ func (wb *WatcherBackend) computeGasProfile(rcpt *types.Receipt, monitoredTxn mo
 	if rcpt.Status == uint64(1) {
 		profile.TotalSuccess++
 	}
+	if fees != nil {
+		spent := new(big.Int).Set(fees)
+		if profile.TotalSpent != nil {
+			spent.Add(spent, profile.TotalSpent)
+		}
+		profile.TotalSpent = spent
+	}
 	return profile
 }
 
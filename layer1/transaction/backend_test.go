@@ -1,11 +1,18 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/layer1/gas"
 )
 
 func TestInfoSaveAndLoad(t *testing.T) {
@@ -34,3 +41,42 @@ func TestInfoSaveAndLoad(t *testing.T) {
 	fmt.Printf("%v\n", resultInfo.Selector)
 	assert.Equal(t, originalInfo, resultInfo)
 }
+
+func TestRecordGas(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	epoch := uint32(1)
+	budget, err := gas.NewBudget(account, 1, 0, func() (uint32, error) { return epoch, nil }, nil, logrus.NewEntry(logrus.New()))
+	assert.Nil(t, err)
+	wb := newWatcherBackend(context.Background(), nil, nil, logrus.New(), nil, false, 0)
+	wb.budget = budget
+
+	newMonitored := func(nonce uint64, task string) monitored {
+		txn := types.NewTx(&types.DynamicFeeTx{Nonce: nonce, Gas: 100, GasFeeCap: big.NewInt(1_000_000)})
+		return monitored{Txn: txn, FromAddress: account, Selector: &FuncSelector{1, 2, 3, 4}, Task: task}
+	}
+	untracked := newMonitored(2, "")
+	wb.reserveGas(untracked.Txn, account)
+	wb.recordGas(&types.Receipt{GasUsed: 100, Status: 1}, big.NewInt(400_000_000), newMonitored(0, "snapshots.SnapshotTask"))
+	wb.recordGas(&types.Receipt{GasUsed: 300, Status: 0}, big.NewInt(600_000_000), newMonitored(1, "snapshots.SnapshotTask"))
+	assert.Equal(t, big.NewInt(100_000_000), budget.Status().Reserved)
+	// without fees the reservation of the transaction is spent
+	wb.recordGas(&types.Receipt{GasUsed: 200, Status: 1}, nil, untracked)
+	assert.Equal(t, 0, budget.Status().Reserved.Sign())
+	assert.Equal(t, big.NewInt(1_100_000_000), budget.Status().Spent)
+
+	report := wb.gasReport()
+	assert.Equal(t, Profile{
+		AverageGas:   200,
+		MinimumGas:   100,
+		MaximumGas:   300,
+		TotalGas:     400,
+		TotalCount:   2,
+		TotalSuccess: 1,
+		TotalSpent:   big.NewInt(1_000_000_000),
+	}, report.Tasks["snapshots.SnapshotTask"])
+	assert.Equal(t, 1, len(report.Tasks))
+	assert.Equal(t, uint64(3), report.Functions["01020304"].TotalCount)
+	assert.Equal(t, big.NewInt(1_000_000_000), report.Functions["01020304"].TotalSpent)
+
+	assert.ErrorIs(t, budget.Allow(account), gas.ErrBudgetExceeded)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/alicenet/alicenet/consensus/db"
 	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/gas"
 	"github.com/alicenet/alicenet/logging"
 )
 
@@ -113,6 +114,7 @@ func (f *FrontWatcher) Close() {
 func (w *FrontWatcher) Subscribe(ctx context.Context, txn *types.Transaction, options *SubscribeOptions) (ReceiptResponse, error) {
 	w.logger.WithField("Txn", txn.Hash().Hex()).Debug("Subscribing for a transaction")
 	req := NewSubscribeRequest(txn, options)
+	req.task = gas.TaskFromContext(ctx)
 	select {
 	case w.requestChannel <- req:
 	case <-ctx.Done():
@@ -121,6 +123,20 @@ func (w *FrontWatcher) Subscribe(ctx context.Context, txn *types.Transaction, op
 	return req.Listen(ctx)
 }
 
+// SetGasBudget sets the budget the fees of the watched transactions are
+// recorded to.
+func (f *FrontWatcher) SetGasBudget(budget *gas.Budget) {
+	f.backend.aggregatesMutex.Lock()
+	defer f.backend.aggregatesMutex.Unlock()
+	f.backend.budget = budget
+}
+
+// GasReport returns the gas used and the fees paid by the transactions
+// watched, per function called and per type of the task that sent them.
+func (f *FrontWatcher) GasReport() GasReport {
+	return f.backend.gasReport()
+}
+
 // Wait is a function that wait for a transaction receipt. This is blocking function that
 // will wait for a receipt to be received.
 func (w *FrontWatcher) Wait(ctx context.Context, receiptResponse ReceiptResponse) (*types.Receipt, error) {
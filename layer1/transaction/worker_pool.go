@@ -15,6 +15,7 @@ import (
 
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/gas"
 )
 
 // MonitorWorkRequest is an internal struct used to send work requests to the
@@ -31,6 +32,7 @@ type MonitorWorkResponse struct {
 	retriedTxn *retriedTransaction // transaction monitored object from the analyzed transaction
 	err        error               // any error found during the receipt retrieve (can be NonRecoverable, Recoverable or TransactionStale errors)
 	receipt    *types.Receipt      // receipt retrieved (can be nil) if a receipt was not found, or it's not ready yet
+	fees       *big.Int            // fees paid by the transaction, nil if unknown or without receipt
 }
 
 // retriedTransaction is an internal struct to keep track of retried transaction by the workers.
@@ -93,8 +95,8 @@ func (w *WorkerPool) worker() {
 					return
 				default:
 				}
-				rcpt, err := w.getReceipt(ctx, monitoredTx, currentHeight, txnHash)
-				finalResp, retry := w.handleResponse(ctx, monitoredTx, txnHash, rcpt, err, i)
+				rcpt, gasPrice, err := w.getReceipt(ctx, monitoredTx, currentHeight, txnHash)
+				finalResp, retry := w.handleResponse(ctx, monitoredTx, txnHash, rcpt, gasPrice, err, i)
 				if !retry {
 					select {
 					case w.responseWorkChannel <- finalResp:
@@ -108,7 +110,7 @@ func (w *WorkerPool) worker() {
 }
 
 // handleResponse handles the receipt for the txn and decides how to proceed based on it information.
-func (w *WorkerPool) handleResponse(ctx context.Context, monitoredTxn monitored, txnHash common.Hash, rcpt *types.Receipt, err error, iteration uint64) (MonitorWorkResponse, bool) {
+func (w *WorkerPool) handleResponse(ctx context.Context, monitoredTxn monitored, txnHash common.Hash, rcpt *types.Receipt, gasPrice *big.Int, err error, iteration uint64) (MonitorWorkResponse, bool) {
 	if err != nil {
 		switch err.(type) {
 		case *ErrRecoverable:
@@ -121,7 +123,7 @@ func (w *WorkerPool) handleResponse(ctx context.Context, monitoredTxn monitored,
 			if monitoredTxn.EnableAutoRetry {
 				defaultAccount := w.client.GetDefaultAccount()
 				if bytes.Equal(monitoredTxn.FromAddress[:], defaultAccount.Address[:]) {
-					newTxn, retryTxErr := w.client.RetryTransaction(ctx, monitoredTxn.Txn, w.baseFee, w.tipCap)
+					newTxn, retryTxErr := w.client.RetryTransaction(gas.WithTask(ctx, monitoredTxn.Task), monitoredTxn.Txn, w.baseFee, w.tipCap)
 					return MonitorWorkResponse{txnHash: txnHash, retriedTxn: &retriedTransaction{txn: newTxn, err: retryTxErr}}, false
 				}
 			}
@@ -132,12 +134,32 @@ func (w *WorkerPool) handleResponse(ctx context.Context, monitoredTxn monitored,
 		return MonitorWorkResponse{txnHash: txnHash, err: err}, false
 	} else {
 		// send receipt (even if it is nil) back to main thread
-		return MonitorWorkResponse{txnHash: txnHash, receipt: rcpt}, false
+		response := MonitorWorkResponse{txnHash: txnHash, receipt: rcpt}
+		if rcpt != nil && gasPrice != nil {
+			response.fees = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(rcpt.GasUsed))
+		}
+		return response, false
+	}
+}
+
+// receiptGasPriceClient is implemented by the clients that return the
+// effective gas price of a transaction together with its receipt.
+type receiptGasPriceClient interface {
+	GetTransactionReceiptAndGasPrice(ctx context.Context, txHash common.Hash) (*types.Receipt, *big.Int, error)
+}
+
+// transactionReceipt returns the receipt of a transaction and, if the client
+// supports it, the effective gas price it paid.
+func (w *WorkerPool) transactionReceipt(ctx context.Context, txnHash common.Hash) (*types.Receipt, *big.Int, error) {
+	if client, ok := w.client.(receiptGasPriceClient); ok {
+		return client.GetTransactionReceiptAndGasPrice(ctx, txnHash)
 	}
+	rcpt, err := w.client.GetTransactionReceipt(ctx, txnHash)
+	return rcpt, nil, err
 }
 
 // getReceipt is an internal function used by the workers to check/retrieve the receipts for a given transaction.
-func (w *WorkerPool) getReceipt(ctx context.Context, monitoredTx monitored, currentHeight uint64, txnHash common.Hash) (*types.Receipt, error) {
+func (w *WorkerPool) getReceipt(ctx context.Context, monitoredTx monitored, currentHeight uint64, txnHash common.Hash) (*types.Receipt, *big.Int, error) {
 	txnHex := txnHash.Hex()
 	blockTimeSpan := currentHeight - monitoredTx.MonitoringHeight
 	_, isPending, err := w.client.GetTransactionByHash(ctx, txnHash)
@@ -145,10 +167,10 @@ func (w *WorkerPool) getReceipt(ctx context.Context, monitoredTx monitored, curr
 		// if we couldn't locate a tx after NotFoundMaxBlocks blocks, and we are still
 		// failing in getting the tx data, probably means that it was dropped
 		if errors.Is(err, goEthereum.NotFound) {
-			return nil, &ErrTxNotFound{fmt.Sprintf("could not find tx %v in the height %v!", txnHex, currentHeight)}
+			return nil, nil, &ErrTxNotFound{fmt.Sprintf("could not find tx %v in the height %v!", txnHex, currentHeight)}
 		}
 		// probably a network error, should retry
-		return nil, &ErrRecoverable{fmt.Sprintf("error getting tx: %v: %v", txnHex, err)}
+		return nil, nil, &ErrRecoverable{fmt.Sprintf("error getting tx: %v: %v", txnHex, err)}
 	}
 	if isPending {
 		// We multiply MaxStaleBlocks by the number of times that we tried to retry a tx
@@ -164,24 +186,24 @@ func (w *WorkerPool) getReceipt(ctx context.Context, monitoredTx monitored, curr
 			maxPendingBlocks *= constants.TxBackOffDelayStaleTxMultiplier
 		}
 		if blockTimeSpan >= maxPendingBlocks {
-			return nil, &ErrTransactionStale{fmt.Sprintf("error tx: %v is stale on the memory pool for more than %v blocks!", txnHex, maxPendingBlocks)}
+			return nil, nil, &ErrTransactionStale{fmt.Sprintf("error tx: %v is stale on the memory pool for more than %v blocks!", txnHex, maxPendingBlocks)}
 		}
 	} else {
 		// tx is not pending, so check for receipt
-		rcpt, err := w.client.GetTransactionReceipt(ctx, txnHash)
+		rcpt, gasPrice, err := w.transactionReceipt(ctx, txnHash)
 		if err != nil {
 			// if can locate a tx (branch logic above), but we cannot locate a tx receipt
 			// after NotFoundMaxBlocks blocks, there's definitely something wrong
 			if errors.Is(err, goEthereum.NotFound) {
-				return nil, &ErrTxNotFound{fmt.Sprintf("could not find receipt for tx %v in the height %v!", txnHex, currentHeight)}
+				return nil, nil, &ErrTxNotFound{fmt.Sprintf("could not find receipt for tx %v in the height %v!", txnHex, currentHeight)}
 			}
 			// probably a network error, should retry
-			return nil, &ErrRecoverable{fmt.Sprintf("error getting receipt: %v: %v", txnHex, err)}
+			return nil, nil, &ErrRecoverable{fmt.Sprintf("error getting receipt: %v: %v", txnHex, err)}
 		}
 
 		if currentHeight >= rcpt.BlockNumber.Uint64()+w.client.GetFinalityDelay() {
-			return rcpt, nil
+			return rcpt, gasPrice, nil
 		}
 	}
-	return nil, nil
+	return nil, nil, nil
 }
@@ -0,0 +1,367 @@
+package localrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/alicenet/consensus/db"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/interfaces"
+	snapshotState "github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
+	"github.com/alicenet/alicenet/layer1/executor"
+	"github.com/alicenet/alicenet/layer1/gas"
+	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/alicenet/alicenet/peering"
+	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/upgrade"
+)
+
+var _ interfaces.AdminServer = (*AdminHandlers)(nil)
+
+// ErrAdminAddressNotLoopback is returned when the admin service is asked to
+// listen on an address that is reachable from other hosts. The admin service
+// has no authentication, anybody that reaches it can kill the layer1 tasks.
+var ErrAdminAddressNotLoopback = errors.New("the admin rpc service must listen on a loopback address")
+
+// AdminHandlers is the server side of the admin RPC system. It exposes the
+// layer1 tasks of the node to its operator.
+type AdminHandlers struct {
+	tasks     executor.TaskInspector
+	upgrades  UpgradeStatus
+	gasReport GasReporter
+	gasBudget *gas.Budget
+	monDB     *db.Database
+	traffic   TrafficReporter
+}
+
+// GasReporter reports the gas spent by the layer1 transactions of the node.
+type GasReporter interface {
+	GasReport() transaction.GasReport
+}
+
+// TrafficReporter reports the traffic exchanged with the active peers.
+type TrafficReporter interface {
+	Traffic() []peering.PeerTraffic
+}
+
+// UpgradeStatus reports the automatic upgrades of the node.
+type UpgradeStatus interface {
+	Status() upgrade.Status
+}
+
+// Init will initialize the AdminHandlers.
+func (ah *AdminHandlers) Init(tasks executor.TaskInspector) {
+	ah.tasks = tasks
+}
+
+// SetUpgradeStatus sets the source of the upgrade status, it is required by
+// GetUpgradeStatus.
+func (ah *AdminHandlers) SetUpgradeStatus(upgrades UpgradeStatus) {
+	ah.upgrades = upgrades
+}
+
+// SetGasReporting sets the sources of the gas report, they are required by
+// GetGasReport. budget may be nil if no budget is configured.
+func (ah *AdminHandlers) SetGasReporting(report GasReporter, budget *gas.Budget) {
+	ah.gasReport = report
+	ah.gasBudget = budget
+}
+
+// SetSnapshotReports sets the monitor database where the snapshot reports are
+// recorded, it is required by GetSnapshotReports.
+func (ah *AdminHandlers) SetSnapshotReports(monDB *db.Database) {
+	ah.monDB = monDB
+}
+
+// SetPeerTraffic sets the source of the peer traffic, it is required by
+// GetPeerTraffic.
+func (ah *AdminHandlers) SetPeerTraffic(traffic TrafficReporter) {
+	ah.traffic = traffic
+}
+
+// ListTasks returns the scheduled and recently finished tasks.
+func (ah *AdminHandlers) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	taskList, err := ah.tasks.ListTasks()
+	if err != nil {
+		return nil, taskError(err)
+	}
+	resp := &pb.ListTasksResponse{Tasks: make([]*pb.TaskInfo, 0, len(taskList))}
+	for _, info := range taskList {
+		resp.Tasks = append(resp.Tasks, taskInfoToProto(info))
+	}
+	return resp, nil
+}
+
+// GetTask returns a scheduled or recently finished task by id.
+func (ah *AdminHandlers) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.GetTaskResponse, error) {
+	info, err := ah.tasks.GetTask(req.Id)
+	if err != nil {
+		return nil, taskError(err)
+	}
+	return &pb.GetTaskResponse{Task: taskInfoToProto(info)}, nil
+}
+
+// KillTask kills a scheduled task by id.
+func (ah *AdminHandlers) KillTask(ctx context.Context, req *pb.KillTaskRequest) (*pb.KillTaskResponse, error) {
+	if _, err := ah.tasks.KillTaskById(req.Id); err != nil {
+		return nil, taskError(err)
+	}
+	return &pb.KillTaskResponse{}, nil
+}
+
+// RetryTask makes a running task retry right away after a failed attempt.
+func (ah *AdminHandlers) RetryTask(ctx context.Context, req *pb.RetryTaskRequest) (*pb.RetryTaskResponse, error) {
+	if err := ah.tasks.RetryTask(req.Id); err != nil {
+		return nil, taskError(err)
+	}
+	return &pb.RetryTaskResponse{}, nil
+}
+
+// GetUpgradeStatus returns the status of the automatic upgrades.
+func (ah *AdminHandlers) GetUpgradeStatus(ctx context.Context, req *pb.GetUpgradeStatusRequest) (*pb.GetUpgradeStatusResponse, error) {
+	if ah.upgrades == nil {
+		return nil, status.Error(codes.Unavailable, "the upgrade manager is not available")
+	}
+	s := ah.upgrades.Status()
+	resp := &pb.GetUpgradeStatusResponse{
+		Enabled:        s.Enabled,
+		DryRun:         s.DryRun,
+		State:          string(s.State),
+		ExecutionEpoch: s.Version.ExecutionEpoch,
+		CurrentEpoch:   s.CurrentEpoch,
+		Artifact:       s.Artifact,
+		Checksum:       s.Checksum,
+		StagedPath:     s.StagedPath,
+		Err:            s.Err,
+	}
+	if s.State != upgrade.Idle {
+		resp.Version = fmt.Sprintf("v%d.%d.%d", s.Version.Major, s.Version.Minor, s.Version.Patch)
+	}
+	return resp, nil
+}
+
+// GetGasReport returns the gas spent per task type and contract function, and
+// the spend of the epoch gas budget.
+func (ah *AdminHandlers) GetGasReport(ctx context.Context, req *pb.GetGasReportRequest) (*pb.GetGasReportResponse, error) {
+	if ah.gasReport == nil {
+		return nil, status.Error(codes.Unavailable, "the gas report is not available")
+	}
+	report := ah.gasReport.GasReport()
+	resp := &pb.GetGasReportResponse{
+		Tasks:     gasProfilesToProto(report.Tasks),
+		Functions: gasProfilesToProto(report.Functions),
+	}
+	if ah.gasBudget != nil {
+		budget := ah.gasBudget.Status()
+		resp.HasBudget = true
+		resp.BudgetAccount = budget.Account.Hex()
+		resp.BudgetEpoch = budget.Epoch
+		resp.BudgetLimit = budget.Limit.String()
+		resp.BudgetSpent = budget.Spent.String()
+		resp.BudgetReserved = budget.Reserved.String()
+	}
+	return resp, nil
+}
+
+// GetSnapshotReports returns who was elected to submit the snapshots and who
+// submitted them, in height order.
+func (ah *AdminHandlers) GetSnapshotReports(ctx context.Context, req *pb.GetSnapshotReportsRequest) (*pb.GetSnapshotReportsResponse, error) {
+	if ah.monDB == nil {
+		return nil, status.Error(codes.Unavailable, "the snapshot reports are not available")
+	}
+	reports, next, err := snapshotState.GetSnapshotReports(ah.monDB, req.FromHeight, int(req.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.GetSnapshotReportsResponse{
+		Reports:    make([]*pb.SnapshotReport, 0, len(reports)),
+		NextHeight: next,
+	}
+	for _, report := range reports {
+		leaderOrder := make([]string, 0, len(report.LeaderOrder))
+		for _, validator := range report.LeaderOrder {
+			leaderOrder = append(leaderOrder, validator.Hex())
+		}
+		resp.Reports = append(resp.Reports, &pb.SnapshotReport{
+			Height:             report.Height,
+			Epoch:              report.Epoch,
+			TxHash:             report.TxHash.Hex(),
+			ElectionStart:      report.ElectionStart,
+			CommittedAt:        report.CommittedAt,
+			Latency:            report.Latency(),
+			DesperationDelay:   uint64(report.DesperationDelay),
+			DesperationFactor:  uint64(report.DesperationFactor),
+			LeaderOrder:        leaderOrder,
+			Submitter:          report.Submitter.Hex(),
+			SubmitterRank:      int32(report.SubmitterRank),
+			SubmitterAllowedAt: report.SubmitterAllowedAt,
+			GasUsed:            report.GasUsed,
+		})
+	}
+	return resp, nil
+}
+
+// GetPeerTraffic returns the bytes and messages exchanged with each active peer,
+// per p2p method.
+func (ah *AdminHandlers) GetPeerTraffic(ctx context.Context, req *pb.GetPeerTrafficRequest) (*pb.GetPeerTrafficResponse, error) {
+	if ah.traffic == nil {
+		return nil, status.Error(codes.Unavailable, "the peer traffic is not available")
+	}
+	peers := ah.traffic.Traffic()
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Identity < peers[j].Identity })
+	resp := &pb.GetPeerTrafficResponse{Peers: make([]*pb.PeerTraffic, 0, len(peers))}
+	for _, peer := range peers {
+		methods := make([]*pb.MethodTraffic, 0, len(peer.Methods))
+		for method, counter := range peer.Methods {
+			methods = append(methods, &pb.MethodTraffic{
+				Method:    method,
+				MsgsIn:    counter.MsgsIn,
+				MsgsOut:   counter.MsgsOut,
+				BytesIn:   counter.BytesIn,
+				BytesOut:  counter.BytesOut,
+				Throttled: counter.Throttled,
+			})
+		}
+		sort.Slice(methods, func(i, j int) bool { return methods[i].Method < methods[j].Method })
+		resp.Peers = append(resp.Peers, &pb.PeerTraffic{
+			Identity:     peer.Identity,
+			P2PAddr:      peer.P2PAddr,
+			BytesRead:    peer.BytesRead,
+			BytesWritten: peer.BytesWritten,
+			Methods:      methods,
+		})
+	}
+	return resp, nil
+}
+
+// gasProfilesToProto converts the profiles sorted by name.
+func gasProfilesToProto(profiles map[string]transaction.Profile) []*pb.GasProfile {
+	result := make([]*pb.GasProfile, 0, len(profiles))
+	for name, profile := range profiles {
+		spent := "0"
+		if profile.TotalSpent != nil {
+			spent = profile.TotalSpent.String()
+		}
+		result = append(result, &pb.GasProfile{
+			Name:         name,
+			AverageGas:   profile.AverageGas,
+			MinimumGas:   profile.MinimumGas,
+			MaximumGas:   profile.MaximumGas,
+			TotalGas:     profile.TotalGas,
+			TotalCount:   profile.TotalCount,
+			TotalSuccess: profile.TotalSuccess,
+			TotalSpent:   spent,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func taskInfoToProto(info executor.TaskInfo) *pb.TaskInfo {
+	txHash := ""
+	if info.TxHash != (common.Hash{}) {
+		txHash = info.TxHash.Hex()
+	}
+	return &pb.TaskInfo{
+		Id:                  info.Id,
+		Name:                info.Name,
+		State:               info.InternalState.String(),
+		Start:               info.Start,
+		End:                 info.End,
+		AllowMultiExecution: info.AllowMultiExecution,
+		Finished:            info.Finished,
+		Err:                 info.Err,
+		ReceivedOnBlock:     info.ReceivedOnBlock,
+		Attempts:            info.Attempts,
+		LastErr:             info.LastErr,
+		TxHash:              txHash,
+	}
+}
+
+// taskError maps the task handler errors to grpc status codes.
+func taskError(err error) error {
+	switch {
+	case errors.Is(err, executor.ErrTaskIdEmpty):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, executor.ErrNotScheduled):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, executor.ErrTaskNotRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+// AdminHandler binds a Listener to the grpc server of the Admin service. The
+// service is not exposed through the RESTful API.
+type AdminHandler struct {
+	listener   net.Listener
+	grpcServer *grpc.Server
+	log        *logrus.Logger
+	closeOnce  sync.Once
+}
+
+// NewAdminServerHandler returns a RPC ServerHandler for the Admin Service.
+// The address must be a loopback address, so that the service is only
+// reachable from the node host.
+func NewAdminServerHandler(logger *logrus.Logger, addr string, service interfaces.AdminServer) (*AdminHandler, error) {
+	if err := checkLoopback(addr); err != nil {
+		return nil, err
+	}
+	grpcServer := grpc.NewServer(grpc.MaxConcurrentStreams(constants.MaxConcurrentStreams), grpc.ReadBufferSize(constants.ReadBufferSize))
+	pb.RegisterAdminServer(grpcServer, service)
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminHandler{
+		listener:   lis,
+		grpcServer: grpcServer,
+		log:        logger,
+	}, nil
+}
+
+// checkLoopback returns an error unless addr is a host:port pair whose host is
+// localhost or a loopback ip.
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("%w: %v", ErrAdminAddressNotLoopback, addr)
+	}
+	return nil
+}
+
+// Close will shutdown the server handler.
+func (ah *AdminHandler) Close() error {
+	ah.closeOnce.Do(func() {
+		ah.grpcServer.Stop()
+		ah.listener.Close()
+	})
+	return nil
+}
+
+func (ah *AdminHandler) Serve() {
+	defer ah.Close()
+	if err := ah.grpcServer.Serve(ah.listener); err != nil {
+		ah.log.Warn(err)
+	}
+}
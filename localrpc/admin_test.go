@@ -0,0 +1,357 @@
+package localrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	snapshotState "github.com/alicenet/alicenet/layer1/chains/ethereum/tasks/snapshots/state"
+	"github.com/alicenet/alicenet/layer1/executor"
+	"github.com/alicenet/alicenet/layer1/gas"
+	"github.com/alicenet/alicenet/layer1/transaction"
+	"github.com/alicenet/alicenet/peering"
+	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/test/mocks"
+	"github.com/alicenet/alicenet/upgrade"
+)
+
+type fakeTaskInspector struct {
+	tasks   map[string]executor.TaskInfo
+	retried []string
+}
+
+func (f *fakeTaskInspector) ListTasks() ([]executor.TaskInfo, error) {
+	taskList := make([]executor.TaskInfo, 0, len(f.tasks))
+	for _, info := range f.tasks {
+		taskList = append(taskList, info)
+	}
+	return taskList, nil
+}
+
+func (f *fakeTaskInspector) GetTask(id string) (executor.TaskInfo, error) {
+	if id == "" {
+		return executor.TaskInfo{}, executor.ErrTaskIdEmpty
+	}
+	info, ok := f.tasks[id]
+	if !ok {
+		return executor.TaskInfo{}, executor.ErrNotScheduled
+	}
+	return info, nil
+}
+
+func (f *fakeTaskInspector) KillTaskById(id string) (*executor.HandlerResponse, error) {
+	if _, ok := f.tasks[id]; !ok {
+		return nil, executor.ErrNotScheduled
+	}
+	delete(f.tasks, id)
+	return nil, nil
+}
+
+func (f *fakeTaskInspector) RetryTask(id string) error {
+	info, ok := f.tasks[id]
+	if !ok {
+		return executor.ErrNotScheduled
+	}
+	if info.InternalState != executor.Running {
+		return executor.ErrTaskNotRunning
+	}
+	f.retried = append(f.retried, id)
+	return nil
+}
+
+type fakeGasReporter transaction.GasReport
+
+func (f fakeGasReporter) GasReport() transaction.GasReport {
+	return transaction.GasReport(f)
+}
+
+type fakeTrafficReporter []peering.PeerTraffic
+
+func (f fakeTrafficReporter) Traffic() []peering.PeerTraffic {
+	return []peering.PeerTraffic(f)
+}
+
+type fakeUpgradeStatus upgrade.Status
+
+func (f fakeUpgradeStatus) Status() upgrade.Status {
+	return upgrade.Status(f)
+}
+
+func TestNewAdminServerHandler_Loopback(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:0", "localhost:0", "[::1]:0"} {
+		server, err := NewAdminServerHandler(logrus.New(), addr, &AdminHandlers{})
+		if err != nil {
+			// the host may not have an ipv6 loopback interface
+			assert.NotErrorIs(t, err, ErrAdminAddressNotLoopback, addr)
+			continue
+		}
+		server.Close()
+	}
+	for _, addr := range []string{"0.0.0.0:8885", ":8885", "[::]:8885", "192.168.1.10:8885", "example.com:8885"} {
+		_, err := NewAdminServerHandler(logrus.New(), addr, &AdminHandlers{})
+		assert.ErrorIs(t, err, ErrAdminAddressNotLoopback, addr)
+	}
+	_, err := NewAdminServerHandler(logrus.New(), "127.0.0.1", &AdminHandlers{})
+	assert.NotNil(t, err)
+}
+
+func TestAdminHandlers_Tasks(t *testing.T) {
+	inspector := &fakeTaskInspector{tasks: map[string]executor.TaskInfo{
+		"running": {
+			BaseRequest: executor.BaseRequest{Id: "running", Name: "dkg.RegisterTask", Start: 10, End: 40, InternalState: executor.Running},
+			Attempts:    3,
+			LastErr:     "network error",
+			TxHash:      common.HexToHash("0x01"),
+		},
+		"pending": {
+			BaseRequest: executor.BaseRequest{Id: "pending", Name: "dkg.CompletionTask", Start: 50, End: 80},
+		},
+	}}
+	handlers := &AdminHandlers{}
+	handlers.Init(inspector)
+
+	server, err := NewAdminServerHandler(logrus.New(), "127.0.0.1:0", handlers)
+	assert.Nil(t, err)
+	go server.Serve()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewAdminClient(conn)
+	ctx := context.Background()
+
+	list, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list.Tasks))
+
+	resp, err := client.GetTask(ctx, &pb.GetTaskRequest{Id: "running"})
+	assert.Nil(t, err)
+	assert.Equal(t, "dkg.RegisterTask", resp.Task.Name)
+	assert.Equal(t, "Running", resp.Task.State)
+	assert.Equal(t, uint64(3), resp.Task.Attempts)
+	assert.Equal(t, "network error", resp.Task.LastErr)
+	assert.Equal(t, common.HexToHash("0x01").Hex(), resp.Task.TxHash)
+
+	resp, err = client.GetTask(ctx, &pb.GetTaskRequest{Id: "pending"})
+	assert.Nil(t, err)
+	assert.Equal(t, "NotStarted", resp.Task.State)
+	assert.Equal(t, "", resp.Task.TxHash)
+
+	_, err = client.GetTask(ctx, &pb.GetTaskRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.RetryTask(ctx, &pb.RetryTaskRequest{Id: "running"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"running"}, inspector.retried)
+	_, err = client.RetryTask(ctx, &pb.RetryTaskRequest{Id: "pending"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.KillTask(ctx, &pb.KillTaskRequest{Id: "pending"})
+	assert.Nil(t, err)
+	_, err = client.GetTask(ctx, &pb.GetTaskRequest{Id: "pending"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAdminHandlers_UpgradeStatus(t *testing.T) {
+	handlers := &AdminHandlers{}
+	handlers.Init(&fakeTaskInspector{})
+
+	server, err := NewAdminServerHandler(logrus.New(), "127.0.0.1:0", handlers)
+	assert.Nil(t, err)
+	go server.Serve()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewAdminClient(conn)
+	ctx := context.Background()
+
+	_, err = client.GetUpgradeStatus(ctx, &pb.GetUpgradeStatusRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	handlers.SetUpgradeStatus(fakeUpgradeStatus{Enabled: true, State: upgrade.Idle, CurrentEpoch: 4})
+	resp, err := client.GetUpgradeStatus(ctx, &pb.GetUpgradeStatusRequest{})
+	assert.Nil(t, err)
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, "Idle", resp.State)
+	assert.Equal(t, "", resp.Version)
+	assert.Equal(t, uint32(4), resp.CurrentEpoch)
+
+	handlers.SetUpgradeStatus(fakeUpgradeStatus{
+		Enabled:    true,
+		State:      upgrade.Staged,
+		Version:    bindings.CanonicalVersion{Major: 1, Minor: 2, Patch: 3, ExecutionEpoch: 7},
+		StagedPath: "/tmp/.alicenet-v1.2.3.staged",
+	})
+	resp, err = client.GetUpgradeStatus(ctx, &pb.GetUpgradeStatusRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Staged", resp.State)
+	assert.Equal(t, "v1.2.3", resp.Version)
+	assert.Equal(t, uint32(7), resp.ExecutionEpoch)
+	assert.Equal(t, "/tmp/.alicenet-v1.2.3.staged", resp.StagedPath)
+}
+
+func TestAdminHandlers_GasReport(t *testing.T) {
+	handlers := &AdminHandlers{}
+	handlers.Init(&fakeTaskInspector{})
+
+	server, err := NewAdminServerHandler(logrus.New(), "127.0.0.1:0", handlers)
+	assert.Nil(t, err)
+	go server.Serve()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewAdminClient(conn)
+	ctx := context.Background()
+
+	_, err = client.GetGasReport(ctx, &pb.GetGasReportRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	report := fakeGasReporter{
+		Tasks: map[string]transaction.Profile{
+			"snapshots.SnapshotTask": {TotalCount: 2, TotalSuccess: 2, TotalGas: 600, TotalSpent: big.NewInt(1200)},
+			"dkg.RegisterTask":       {TotalCount: 1},
+		},
+		Functions: map[string]transaction.Profile{
+			"snapshot(bytes,bytes)": {TotalCount: 2, TotalSpent: big.NewInt(1200)},
+		},
+	}
+	handlers.SetGasReporting(report, nil)
+	resp, err := client.GetGasReport(ctx, &pb.GetGasReportRequest{})
+	assert.Nil(t, err)
+	assert.False(t, resp.HasBudget)
+	assert.Equal(t, 2, len(resp.Tasks))
+	assert.Equal(t, "dkg.RegisterTask", resp.Tasks[0].Name)
+	assert.Equal(t, "0", resp.Tasks[0].TotalSpent)
+	assert.Equal(t, "snapshots.SnapshotTask", resp.Tasks[1].Name)
+	assert.Equal(t, uint64(600), resp.Tasks[1].TotalGas)
+	assert.Equal(t, "1200", resp.Tasks[1].TotalSpent)
+	assert.Equal(t, 1, len(resp.Functions))
+
+	account := common.HexToAddress("0x1")
+	budget, err := gas.NewBudget(account, 5, 0, func() (uint32, error) { return 3, nil }, nil, logrus.NewEntry(logrus.New()))
+	assert.Nil(t, err)
+	budget.Record(account, common.Hash{1}, big.NewInt(1200))
+	handlers.SetGasReporting(report, budget)
+	resp, err = client.GetGasReport(ctx, &pb.GetGasReportRequest{})
+	assert.Nil(t, err)
+	assert.True(t, resp.HasBudget)
+	assert.Equal(t, account.Hex(), resp.BudgetAccount)
+	assert.Equal(t, uint32(3), resp.BudgetEpoch)
+	assert.Equal(t, "5000000000", resp.BudgetLimit)
+	assert.Equal(t, "1200", resp.BudgetSpent)
+	assert.Equal(t, "0", resp.BudgetReserved)
+}
+
+func TestAdminHandlers_SnapshotReports(t *testing.T) {
+	handlers := &AdminHandlers{}
+	handlers.Init(&fakeTaskInspector{})
+
+	server, err := NewAdminServerHandler(logrus.New(), "127.0.0.1:0", handlers)
+	assert.Nil(t, err)
+	go server.Serve()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewAdminClient(conn)
+	ctx := context.Background()
+
+	_, err = client.GetSnapshotReports(ctx, &pb.GetSnapshotReportsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	monDB := mocks.NewTestDB()
+	for _, height := range []uint32{1024, 2048} {
+		err = snapshotState.SaveSnapshotReport(monDB, &snapshotState.SnapshotReport{
+			Height:        height,
+			ElectionStart: 100,
+			CommittedAt:   130,
+			LeaderOrder:   []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")},
+			Submitter:     common.HexToAddress("0x2"),
+			SubmitterRank: 1,
+			GasUsed:       250_000,
+		})
+		assert.Nil(t, err)
+	}
+	handlers.SetSnapshotReports(monDB)
+
+	resp, err := client.GetSnapshotReports(ctx, &pb.GetSnapshotReportsRequest{Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2048), resp.NextHeight)
+	assert.Equal(t, 1, len(resp.Reports))
+	report := resp.Reports[0]
+	assert.Equal(t, uint32(1024), report.Height)
+	assert.Equal(t, uint64(30), report.Latency)
+	assert.Equal(t, int32(1), report.SubmitterRank)
+	assert.Equal(t, common.HexToAddress("0x2").Hex(), report.Submitter)
+	assert.Equal(t, 2, len(report.LeaderOrder))
+
+	resp, err = client.GetSnapshotReports(ctx, &pb.GetSnapshotReportsRequest{FromHeight: resp.NextHeight})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), resp.NextHeight)
+	assert.Equal(t, 1, len(resp.Reports))
+	assert.Equal(t, uint32(2048), resp.Reports[0].Height)
+}
+
+func TestAdminHandlers_PeerTraffic(t *testing.T) {
+	handlers := &AdminHandlers{}
+	handlers.Init(&fakeTaskInspector{})
+
+	server, err := NewAdminServerHandler(logrus.New(), "127.0.0.1:0", handlers)
+	assert.Nil(t, err)
+	go server.Serve()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewAdminClient(conn)
+	ctx := context.Background()
+
+	_, err = client.GetPeerTraffic(ctx, &pb.GetPeerTrafficRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	handlers.SetPeerTraffic(fakeTrafficReporter{
+		{
+			Identity:     "peerB",
+			P2PAddr:      "b@127.0.0.1:4242",
+			BytesRead:    300,
+			BytesWritten: 100,
+			Methods: map[string]peering.TrafficCounter{
+				"/proto.P2P/GossipTransaction": {MsgsIn: 3, BytesIn: 300, Throttled: 1},
+				"/proto.P2P/GetBlockHeaders":   {MsgsOut: 1, BytesOut: 100},
+			},
+		},
+		{Identity: "peerA", P2PAddr: "a@127.0.0.1:4242"},
+	})
+	resp, err := client.GetPeerTraffic(ctx, &pb.GetPeerTrafficRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(resp.Peers))
+	assert.Equal(t, "peerA", resp.Peers[0].Identity)
+	assert.Equal(t, 0, len(resp.Peers[0].Methods))
+	peer := resp.Peers[1]
+	assert.Equal(t, "peerB", peer.Identity)
+	assert.Equal(t, "b@127.0.0.1:4242", peer.P2PAddr)
+	assert.Equal(t, uint64(300), peer.BytesRead)
+	assert.Equal(t, uint64(100), peer.BytesWritten)
+	assert.Equal(t, 2, len(peer.Methods))
+	assert.Equal(t, "/proto.P2P/GetBlockHeaders", peer.Methods[0].Method)
+	assert.Equal(t, uint64(100), peer.Methods[0].BytesOut)
+	assert.Equal(t, "/proto.P2P/GossipTransaction", peer.Methods[1].Method)
+	assert.Equal(t, uint64(3), peer.Methods[1].MsgsIn)
+	assert.Equal(t, uint64(1), peer.Methods[1].Throttled)
+}
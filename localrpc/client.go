@@ -9,13 +9,17 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/alicenet/alicenet/application"
 	aobjs "github.com/alicenet/alicenet/application/objs"
 	"github.com/alicenet/alicenet/application/objs/uint256"
 	"github.com/alicenet/alicenet/consensus/objs"
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
 	pb "github.com/alicenet/alicenet/proto"
 )
 
@@ -200,6 +204,53 @@ func (lrpc *Client) SendTransaction(ctx context.Context, tx *aobjs.Tx) ([]byte,
 	return hex.DecodeString(data)
 }
 
+// ValidateTransaction checks a tx against the rules of the pending tx pool
+// without injecting it; it returns the broken rules along with the height
+// the tx was checked for.
+func (lrpc *Client) ValidateTransaction(ctx context.Context, tx *aobjs.Tx) (*application.TxValidation, uint32, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, 0, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	txb, err := ForwardTranslateTx(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	request := &pb.TransactionData{Tx: txb}
+	resp, err := lrpc.client.ValidateTransaction(subCtx, request)
+	if err != nil {
+		return nil, 0, err
+	}
+	txHash, err := ReverseTranslateByte(resp.TxHash)
+	if err != nil {
+		return nil, 0, err
+	}
+	codes := make(map[pb.TxErrorCode]errorz.Code, len(txErrorCodes))
+	for code, pbCode := range txErrorCodes {
+		codes[pbCode] = code
+	}
+	v := &application.TxValidation{TxHash: txHash}
+	for _, e := range resp.Errors {
+		v.Errors = append(v.Errors, &application.TxValidationError{
+			Code:    codes[e.Code],
+			Input:   int(e.Input),
+			Output:  int(e.Output),
+			Message: e.Message,
+		})
+	}
+	for _, c := range resp.Conflicts {
+		txHashes, err := ReverseTranslateByteSlice(c.TxHashes)
+		if err != nil {
+			return nil, 0, err
+		}
+		v.Conflicts = append(v.Conflicts, &application.TxConflict{Input: int(c.Input), TxHashes: txHashes})
+	}
+	return v, resp.Height, nil
+}
+
 // GetValueForOwner allows a caller to receive a list of UTXOs that are
 // controlled by the named account.
 func (lrpc *Client) GetValueForOwner(ctx context.Context, curveSpec constants.CurveSpec, account []byte, minValue *uint256.Uint256) ([][]byte, *uint256.Uint256, error) {
@@ -235,6 +286,39 @@ func (lrpc *Client) GetValueForOwner(ctx context.Context, curveSpec constants.Cu
 	return d, v, nil
 }
 
+// GetAssetValueForOwner allows a caller to receive a list of TokenStores of
+// the asset assetID that are controlled by the named account.
+func (lrpc *Client) GetAssetValueForOwner(ctx context.Context, curveSpec constants.CurveSpec, account []byte, assetID []byte, minAmount *uint256.Uint256) ([][]byte, *uint256.Uint256, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	o := ForwardTranslateByte(account)
+
+	minAmountString, err := minAmount.MarshalString()
+	if err != nil {
+		return nil, nil, err
+	}
+	request := &pb.GetValueRequest{Account: o, CurveSpec: uint32(curveSpec), Minvalue: minAmountString, AssetID: ForwardTranslateByte(assetID)}
+	resp, err := lrpc.client.GetValueForOwner(subCtx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	a := &uint256.Uint256{}
+	err = a.UnmarshalString(resp.TotalValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := ReverseTranslateByteSlice(resp.UTXOIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, a, nil
+}
+
 // GetUTXO allows the caller to request UTXOs by ID.
 func (lrpc *Client) GetUTXO(ctx context.Context, utxoIDs [][]byte) (aobjs.Vout, error) {
 	if err := lrpc.entrancyGuard(); err != nil {
@@ -264,6 +348,147 @@ func (lrpc *Client) GetUTXO(ctx context.Context, utxoIDs [][]byte) (aobjs.Vout,
 	return utxos, nil
 }
 
+// DataQuery selects the DataStore stored by an account at an index.
+type DataQuery struct {
+	CurveSpec constants.CurveSpec
+	Account   []byte
+	Index     []byte
+}
+
+// OwnerQuery selects the UTXOs of an account which hold at least MinValue;
+// AssetID selects TokenStores of the asset instead of the native value.
+type OwnerQuery struct {
+	CurveSpec constants.CurveSpec
+	Account   []byte
+	AssetID   []byte
+	MinValue  *uint256.Uint256
+}
+
+// StateBatchQuery is a set of queries answered by GetStateBatch.
+type StateBatchQuery struct {
+	// Height is the height the state is read at; zero selects the current
+	// height. Data and Owners can only be read at the current height.
+	Height  uint32
+	UTXOIDs [][]byte
+	Data    []*DataQuery
+	Owners  []*OwnerQuery
+}
+
+// DataResult is the DataStore selected by a DataQuery, if any.
+type DataResult struct {
+	Found   bool
+	UTXOID  []byte
+	RawData []byte
+}
+
+// OwnerValue is the value selected by an OwnerQuery.
+type OwnerValue struct {
+	UTXOIDs     [][]byte
+	Value       *uint256.Uint256
+	LockedValue *uint256.Uint256
+	VestedValue *uint256.Uint256
+}
+
+// StateBatch is the state read by GetStateBatch. Data and Owners hold one
+// result for each query, in order.
+type StateBatch struct {
+	Height         uint32
+	StateRoot      []byte
+	UTXOs          aobjs.Vout
+	MissingUTXOIDs [][]byte
+	Data           []*DataResult
+	Owners         []*OwnerValue
+}
+
+// GetStateBatch answers all the queries of q from the state at a single
+// height, which is returned along with the StateRoot at that height.
+func (lrpc *Client) GetStateBatch(ctx context.Context, q *StateBatchQuery) (*StateBatch, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	utxoIDs, err := ForwardTranslateByteSlice(q.UTXOIDs)
+	if err != nil {
+		return nil, err
+	}
+	request := &pb.StateBatchRequest{Height: q.Height, UTXOIDs: utxoIDs}
+	for _, d := range q.Data {
+		request.Data = append(request.Data, &pb.GetDataRequest{
+			CurveSpec: uint32(d.CurveSpec),
+			Account:   ForwardTranslateByte(d.Account),
+			Index:     ForwardTranslateByte(d.Index),
+		})
+	}
+	for _, o := range q.Owners {
+		ownerRequest := &pb.GetValueRequest{
+			CurveSpec: uint32(o.CurveSpec),
+			Account:   ForwardTranslateByte(o.Account),
+			AssetID:   ForwardTranslateByte(o.AssetID),
+		}
+		if o.MinValue != nil {
+			ownerRequest.Minvalue, err = o.MinValue.MarshalString()
+			if err != nil {
+				return nil, err
+			}
+		}
+		request.Owners = append(request.Owners, ownerRequest)
+	}
+	resp, err := lrpc.client.GetStateBatch(subCtx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &StateBatch{Height: resp.Height}
+	batch.StateRoot, err = ReverseTranslateByte(resp.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range resp.UTXOs {
+		u, err := ReverseTranslateTXOut(utxo)
+		if err != nil {
+			return nil, err
+		}
+		batch.UTXOs = append(batch.UTXOs, u)
+	}
+	batch.MissingUTXOIDs, err = ReverseTranslateByteSlice(resp.MissingUTXOIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range resp.Data {
+		dr := &DataResult{Found: d.Found}
+		dr.UTXOID, err = ReverseTranslateByte(d.UTXOID)
+		if err != nil {
+			return nil, err
+		}
+		dr.RawData, err = ReverseTranslateByte(d.Rawdata)
+		if err != nil {
+			return nil, err
+		}
+		batch.Data = append(batch.Data, dr)
+	}
+	for _, o := range resp.Owners {
+		ov := &OwnerValue{Value: &uint256.Uint256{}, LockedValue: &uint256.Uint256{}, VestedValue: &uint256.Uint256{}}
+		ov.UTXOIDs, err = ReverseTranslateByteSlice(o.UTXOIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err := ov.Value.UnmarshalString(o.TotalValue); err != nil {
+			return nil, err
+		}
+		if err := ov.LockedValue.UnmarshalString(o.LockedValue); err != nil {
+			return nil, err
+		}
+		if err := ov.VestedValue.UnmarshalString(o.VestedValue); err != nil {
+			return nil, err
+		}
+		batch.Owners = append(batch.Owners, ov)
+	}
+	return batch, nil
+}
+
 // GetMinedTransaction allows a caller to see if a mined tx is known. Due to
 // state pruning, transactions will only be stored for a maximum of four epochs.
 // after this time, the transaction is no longer available but all UTXOs are.
@@ -289,6 +514,38 @@ func (lrpc *Client) GetMinedTransaction(ctx context.Context, txHash []byte) (*ao
 	return tx, nil
 }
 
+// GetMinedTransactionsByMemo returns the hashes of the mined transactions
+// carrying the memo with memoHash, starting after startTxHash, along with the
+// txHash to continue from; it is empty once there are no more transactions.
+// limit may be zero for the largest number of transactions.
+func (lrpc *Client) GetMinedTransactionsByMemo(ctx context.Context, memoHash []byte, limit uint32, startTxHash []byte) ([][]byte, []byte, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	request := &pb.MemoTransactionsRequest{
+		MemoHash:    ForwardTranslateByte(memoHash),
+		Limit:       limit,
+		StartTxHash: ForwardTranslateByte(startTxHash),
+	}
+	resp, err := lrpc.client.GetMinedTransactionsByMemo(subCtx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	txHashes, err := ReverseTranslateByteSlice(resp.TxHashes)
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := ReverseTranslateByte(resp.NextTxHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txHashes, next, nil
+}
+
 // GetPendingTransaction allows a caller to inspect the Pending Tx Pool to see
 // if a tx is present.
 func (lrpc *Client) GetPendingTransaction(ctx context.Context, txHash []byte) (*aobjs.Tx, error) {
@@ -379,6 +636,53 @@ func (lrpc *Client) PaginateDataStoreUTXOByOwner(ctx context.Context, curveSpec
 	return result, nil
 }
 
+// QueryNameSpace returns the datastores of the account selected by the query,
+// along with the continuation of the query; q.After is set to the
+// continuation to get the next datastores, and it is empty once there are no
+// more. q.Limit may be zero for the largest number of datastores.
+func (lrpc *Client) QueryNameSpace(ctx context.Context, curveSpec constants.CurveSpec, account []byte, q *aobjs.DataStoreQuery, includeMetadata bool) ([]*aobjs.PaginationResponse, []byte, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	request := &pb.QueryNameSpaceRequest{
+		CurveSpec:         uint32(curveSpec),
+		Account:           ForwardTranslateByte(account),
+		Prefix:            ForwardTranslateByte(q.Prefix),
+		StartIndex:        ForwardTranslateByte(q.Start),
+		EndIndex:          ForwardTranslateByte(q.End),
+		Reverse:           q.Reverse,
+		Limit:             uint32(q.Limit),
+		ContinuationToken: q.After,
+		IncludeMetadata:   includeMetadata,
+	}
+	resp, err := lrpc.client.QueryNameSpace(subCtx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := []*aobjs.PaginationResponse{}
+	for _, r := range resp.Results {
+		utxoID, err := ReverseTranslateByte(r.UTXOID)
+		if err != nil {
+			return nil, nil, err
+		}
+		index, err := ReverseTranslateByte(r.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, &aobjs.PaginationResponse{
+			UTXOID:            utxoID,
+			Index:             index,
+			IssuedAt:          r.IssuedAt,
+			EpochOfExpiration: r.EpochOfExpiration,
+		})
+	}
+	return result, resp.ContinuationToken, nil
+}
+
 // GetBlockHeightForTx returns the block height at which a tx was mined.
 func (lrpc *Client) GetBlockHeightForTx(ctx context.Context, txHash []byte) (uint32, error) {
 	if err := lrpc.entrancyGuard(); err != nil {
@@ -424,3 +728,207 @@ func (lrpc *Client) GetTxFees(ctx context.Context) ([]string, error) {
 
 	return resp, nil
 }
+
+// DepositStatus is the status of an ALCB deposit known by the node.
+type DepositStatus struct {
+	LatestDepositSeen      uint32
+	LatestDepositProcessed uint32
+	Seen                   bool
+	Processed              bool
+	UTXOID                 []byte
+	Spent                  bool
+	// UTXO is nil until the deposit is added to the deposit handler.
+	UTXO *aobjs.TXOut
+}
+
+// GetDepositStatus returns the status of a deposit by the nonce of the ALCB
+// DepositReceived event.
+func (lrpc *Client) GetDepositStatus(ctx context.Context, nonce uint32) (*DepositStatus, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	request := &pb.DepositStatusRequest{Nonce: nonce}
+	resp, err := lrpc.client.GetDepositStatus(subCtx, request)
+	if err != nil {
+		return nil, err
+	}
+	utxoID, err := ReverseTranslateByte(resp.UTXOID)
+	if err != nil {
+		return nil, err
+	}
+	status := &DepositStatus{
+		LatestDepositSeen:      resp.LatestDepositSeen,
+		LatestDepositProcessed: resp.LatestDepositProcessed,
+		Seen:                   resp.Seen,
+		Processed:              resp.Processed,
+		UTXOID:                 utxoID,
+		Spent:                  resp.Spent,
+	}
+	if resp.UTXO != nil {
+		status.UTXO, err = ReverseTranslateTXOut(resp.UTXO)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// DataStoreStatus is a DataStore known by the node, with the values needed to
+// renew or transfer it in the next block.
+type DataStoreStatus struct {
+	UTXO              *aobjs.TXOut
+	UTXOID            []byte
+	BlockHeight       uint32
+	EpochOfExpiration uint32
+	// RemainingValue is the refund of the DataStore if it is consumed in the
+	// next block.
+	RemainingValue *uint256.Uint256
+	// DataStoreFee is the current fee per epoch of a DataStore.
+	DataStoreFee *uint256.Uint256
+}
+
+// GetDataStoreStatus returns the DataStore stored by the account at index.
+func (lrpc *Client) GetDataStoreStatus(ctx context.Context, curveSpec constants.CurveSpec, account, index []byte) (*DataStoreStatus, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	request := &pb.DataStoreStatusRequest{
+		CurveSpec: uint32(curveSpec),
+		Account:   ForwardTranslateByte(account),
+		Index:     ForwardTranslateByte(index),
+	}
+	resp, err := lrpc.client.GetDataStoreStatus(subCtx, request)
+	if err != nil {
+		return nil, err
+	}
+	utxo, err := ReverseTranslateTXOut(resp.UTXO)
+	if err != nil {
+		return nil, err
+	}
+	utxoID, err := ReverseTranslateByte(resp.UTXOID)
+	if err != nil {
+		return nil, err
+	}
+	refund := &uint256.Uint256{}
+	if err := refund.UnmarshalString(resp.RemainingValue); err != nil {
+		return nil, err
+	}
+	fee := &uint256.Uint256{}
+	if err := fee.UnmarshalString(resp.DataStoreFee); err != nil {
+		return nil, err
+	}
+	status := &DataStoreStatus{
+		UTXO:              utxo,
+		UTXOID:            utxoID,
+		BlockHeight:       resp.BlockHeight,
+		EpochOfExpiration: resp.EpochOfExpiration,
+		RemainingValue:    refund,
+		DataStoreFee:      fee,
+	}
+	return status, nil
+}
+
+// DataStoreUpdate is a renewal or a transfer of a DataStore to be mined in the
+// next block.
+type DataStoreUpdate struct {
+	// Consumed is the DataStore UTXO consumed by the transaction; its owner
+	// signs the input.
+	Consumed *aobjs.TXOut
+	// Successor is the DataStore generated by the transaction; its owner
+	// presigns it once TXOutIdx and TxHash are set.
+	Successor *aobjs.DataStore
+	// Cost is the value the transaction must add to the refund of Consumed
+	// besides the transaction fee.
+	Cost *uint256.Uint256
+}
+
+// PrepareDataStoreRenewal prepares the renewal of the DataStore stored by the
+// account at index for numEpochs more epochs.
+func (lrpc *Client) PrepareDataStoreRenewal(ctx context.Context, curveSpec constants.CurveSpec, account, index []byte, numEpochs uint32) (*DataStoreUpdate, error) {
+	return lrpc.prepareDataStoreUpdate(ctx, curveSpec, account, index, func(ds *aobjs.DataStore, height uint32, fee *uint256.Uint256) (*aobjs.DataStore, error) {
+		return ds.Renew(height, numEpochs, fee)
+	})
+}
+
+// PrepareDataStoreTransfer prepares the transfer of the DataStore stored by
+// the account at index to owner; numEpochs may be added to its lifetime.
+func (lrpc *Client) PrepareDataStoreTransfer(ctx context.Context, curveSpec constants.CurveSpec, account, index []byte, owner *aobjs.DataStoreOwner, numEpochs uint32) (*DataStoreUpdate, error) {
+	return lrpc.prepareDataStoreUpdate(ctx, curveSpec, account, index, func(ds *aobjs.DataStore, height uint32, fee *uint256.Uint256) (*aobjs.DataStore, error) {
+		return ds.Transfer(owner, height, numEpochs, fee)
+	})
+}
+
+func (lrpc *Client) prepareDataStoreUpdate(ctx context.Context, curveSpec constants.CurveSpec, account, index []byte, successor func(*aobjs.DataStore, uint32, *uint256.Uint256) (*aobjs.DataStore, error)) (*DataStoreUpdate, error) {
+	status, err := lrpc.GetDataStoreStatus(ctx, curveSpec, account, index)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := status.UTXO.DataStore()
+	if err != nil {
+		return nil, err
+	}
+	height := status.BlockHeight + 1
+	next, err := successor(ds, height, status.DataStoreFee)
+	if err != nil {
+		return nil, err
+	}
+	cost, err := ds.UpdateCost(next, height)
+	if err != nil {
+		return nil, err
+	}
+	return &DataStoreUpdate{Consumed: status.UTXO, Successor: next, Cost: cost}, nil
+}
+
+// QueryLayer1Events returns the layer1 events processed by the monitor of the
+// node that match the query, and the block the next page starts at, which is
+// zero once there are no more events.
+func (lrpc *Client) QueryLayer1Events(ctx context.Context, q *indexer.Query) ([]*indexer.Event, uint64, error) {
+	if err := lrpc.entrancyGuard(); err != nil {
+		return nil, 0, err
+	}
+	defer lrpc.wg.Done()
+	subCtx, cleanup := lrpc.contextGuard(ctx)
+	defer cleanup()
+
+	request := &pb.Layer1EventsRequest{
+		FromBlock: q.FromBlock,
+		ToBlock:   q.ToBlock,
+		Name:      q.Name,
+		Limit:     uint32(q.Limit),
+	}
+	if q.Contract != (common.Address{}) {
+		request.Contract = q.Contract.Hex()
+	}
+	resp, err := lrpc.client.QueryLayer1Events(subCtx, request)
+	if err != nil {
+		return nil, 0, err
+	}
+	events := make([]*indexer.Event, 0, len(resp.Events))
+	for _, e := range resp.Events {
+		contract, err := ReverseTranslateByte(e.Contract)
+		if err != nil {
+			return nil, 0, err
+		}
+		txHash, err := ReverseTranslateByte(e.TxHash)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, &indexer.Event{
+			Contract: common.BytesToAddress(contract),
+			Name:     e.Name,
+			Block:    e.Block,
+			TxHash:   common.BytesToHash(txHash),
+			LogIndex: e.LogIndex,
+			Fields:   e.Fields,
+		})
+	}
+	return events, resp.NextFromBlock, nil
+}
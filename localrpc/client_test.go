@@ -179,6 +179,7 @@ func TestClient_GetTxFees(t *testing.T) {
 				"0000000000000000000000000000000000000000000000000000000000000fa0",
 				"0000000000000000000000000000000000000000000000000000000000000bb8",
 				"0000000000000000000000000000000000000000000000000000000000000bb8",
+				"0000000000000000000000000000000000000000000000000000000000000000",
 			},
 		},
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
 
 	"github.com/alicenet/alicenet/application"
@@ -20,25 +21,33 @@ import (
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/crypto"
 	"github.com/alicenet/alicenet/dynamics"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
+	monInterfaces "github.com/alicenet/alicenet/layer1/monitor/interfaces"
 	"github.com/alicenet/alicenet/logging"
 	pb "github.com/alicenet/alicenet/proto"
 	"github.com/alicenet/alicenet/utils"
 )
 
 var (
-	_ pb.LocalStateGetBlockHeaderHandler            = (*Handlers)(nil)
-	_ pb.LocalStateGetPendingTransactionHandler     = (*Handlers)(nil)
-	_ pb.LocalStateGetRoundStateForValidatorHandler = (*Handlers)(nil)
-	_ pb.LocalStateGetValidatorSetHandler           = (*Handlers)(nil)
-	_ pb.LocalStateGetBlockNumberHandler            = (*Handlers)(nil)
-	_ pb.LocalStateGetChainIDHandler                = (*Handlers)(nil)
-	_ pb.LocalStateGetEpochNumberHandler            = (*Handlers)(nil)
-	_ pb.LocalStateSendTransactionHandler           = (*Handlers)(nil)
-	_ pb.LocalStateGetDataHandler                   = (*Handlers)(nil)
-	_ pb.LocalStateGetMinedTransactionHandler       = (*Handlers)(nil)
-	_ pb.LocalStateGetValueForOwnerHandler          = (*Handlers)(nil)
-	_ pb.LocalStateIterateNameSpaceHandler          = (*Handlers)(nil)
-	_ pb.LocalStateGetUTXOHandler                   = (*Handlers)(nil)
+	_ pb.LocalStateGetBlockHeaderHandler             = (*Handlers)(nil)
+	_ pb.LocalStateGetPendingTransactionHandler      = (*Handlers)(nil)
+	_ pb.LocalStateGetRoundStateForValidatorHandler  = (*Handlers)(nil)
+	_ pb.LocalStateGetValidatorSetHandler            = (*Handlers)(nil)
+	_ pb.LocalStateGetBlockNumberHandler             = (*Handlers)(nil)
+	_ pb.LocalStateGetChainIDHandler                 = (*Handlers)(nil)
+	_ pb.LocalStateGetEpochNumberHandler             = (*Handlers)(nil)
+	_ pb.LocalStateSendTransactionHandler            = (*Handlers)(nil)
+	_ pb.LocalStateGetDataHandler                    = (*Handlers)(nil)
+	_ pb.LocalStateGetMinedTransactionHandler        = (*Handlers)(nil)
+	_ pb.LocalStateGetMinedTransactionsByMemoHandler = (*Handlers)(nil)
+	_ pb.LocalStateValidateTransactionHandler        = (*Handlers)(nil)
+	_ pb.LocalStateGetValueForOwnerHandler           = (*Handlers)(nil)
+	_ pb.LocalStateIterateNameSpaceHandler           = (*Handlers)(nil)
+	_ pb.LocalStateGetUTXOHandler                    = (*Handlers)(nil)
+	_ pb.LocalStateGetStateBatchHandler              = (*Handlers)(nil)
+	_ pb.LocalStateGetDepositStatusHandler           = (*Handlers)(nil)
+	_ pb.LocalStateQueryLayer1EventsHandler          = (*Handlers)(nil)
 )
 
 func (srpc *Handlers) notReady() error {
@@ -48,9 +57,9 @@ func (srpc *Handlers) notReady() error {
 
 	select {
 	case <-srpc.ctx.Done():
-		return errors.New("closing")
+		return errorz.ErrClosing
 	case <-time.After(1 * time.Second):
-		return errors.New("not in sync - unsafe to serve requests at this time")
+		return errorz.ErrNotReady
 	}
 }
 
@@ -74,6 +83,9 @@ type Handlers struct {
 
 	safeHandler func() bool
 	safecount   uint32
+
+	deposits   monInterfaces.DepositTracker
+	eventIndex monInterfaces.EventIndex
 }
 
 // Init will initialize the Consensus Engine and all sub modules.
@@ -96,6 +108,18 @@ func (srpc *Handlers) Init(database *db.Database, app *application.Application,
 	srpc.safeHandler = safe
 }
 
+// SetDepositTracker sets the source of the deposits seen and processed on
+// layer1, it is required by GetDepositStatus.
+func (srpc *Handlers) SetDepositTracker(deposits monInterfaces.DepositTracker) {
+	srpc.deposits = deposits
+}
+
+// SetEventIndex sets the history of the layer1 events processed by the
+// monitor, it is required by QueryLayer1Events.
+func (srpc *Handlers) SetEventIndex(eventIndex monInterfaces.EventIndex) {
+	srpc.eventIndex = eventIndex
+}
+
 func (srpc *Handlers) Start() {
 	srpc.SafeMonitor()
 }
@@ -232,6 +256,87 @@ func (srpc *Handlers) HandleLocalStateSendTransaction(ctx context.Context, req *
 	return result, nil
 }
 
+// HandleLocalStateValidateTransaction checks a transaction against the rules
+// the node applies to pending transactions at the next height, without
+// adding it to the pending transaction pool.
+func (srpc *Handlers) HandleLocalStateValidateTransaction(ctx context.Context, req *pb.TransactionData) (*pb.TransactionValidation, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+
+	srpc.logger.Debugf("HandleLocalStateValidateTransaction: %v", req)
+	ntx, err := ReverseTranslateTx(req.Tx)
+	if err != nil {
+		return nil, err
+	}
+	txb, err := ntx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	err = ntx.UnmarshalBinary(txb)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.TransactionValidation{}
+	err = srpc.database.View(func(txn *badger.Txn) error {
+		os, err := srpc.database.GetOwnState(txn)
+		if err != nil {
+			return err
+		}
+		height := os.SyncToBH.BClaims.Height + 1
+		v, err := srpc.AppHandler.ValidateTx(txn, os.SyncToBH.BClaims.ChainID, height, ntx)
+		if err != nil {
+			return err
+		}
+		resp.Valid = v.Valid()
+		resp.TxHash = ForwardTranslateByte(v.TxHash)
+		resp.Height = height
+		for _, e := range v.Errors {
+			resp.Errors = append(resp.Errors, &pb.TxValidationError{
+				Code:    txErrorCodes[e.Code],
+				Input:   int32(e.Input),
+				Output:  int32(e.Output),
+				Message: e.Message,
+			})
+		}
+		for _, c := range v.Conflicts {
+			conflict := &pb.TxConflict{Input: uint32(c.Input)}
+			for _, txHash := range c.TxHashes {
+				conflict.TxHashes = append(conflict.TxHashes, ForwardTranslateByte(txHash))
+			}
+			resp.Conflicts = append(resp.Conflicts, conflict)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// txErrorCodes maps the rule codes of errorz onto the codes of the
+// ValidateTransaction response; codes missing from it map onto
+// TX_ERROR_UNSPECIFIED.
+var txErrorCodes = map[errorz.Code]pb.TxErrorCode{
+	errorz.CodeTxMalformed:          pb.TxErrorCode_TX_ERROR_MALFORMED,
+	errorz.CodeTxChainID:            pb.TxErrorCode_TX_ERROR_CHAIN_ID,
+	errorz.CodeTxHash:               pb.TxErrorCode_TX_ERROR_TX_HASH,
+	errorz.CodeTxDuplicate:          pb.TxErrorCode_TX_ERROR_DUPLICATE,
+	errorz.CodeTxAlreadyMined:       pb.TxErrorCode_TX_ERROR_ALREADY_MINED,
+	errorz.CodeTxInputMissing:       pb.TxErrorCode_TX_ERROR_INPUT_MISSING,
+	errorz.CodeTxInputSpent:         pb.TxErrorCode_TX_ERROR_INPUT_SPENT,
+	errorz.CodeTxInputSignature:     pb.TxErrorCode_TX_ERROR_INPUT_SIGNATURE,
+	errorz.CodeTxOutputPreSignature: pb.TxErrorCode_TX_ERROR_OUTPUT_PRE_SIGNATURE,
+	errorz.CodeTxOutputFee:          pb.TxErrorCode_TX_ERROR_OUTPUT_FEE,
+	errorz.CodeTxOutputExists:       pb.TxErrorCode_TX_ERROR_OUTPUT_EXISTS,
+	errorz.CodeTxValueMismatch:      pb.TxErrorCode_TX_ERROR_VALUE_MISMATCH,
+	errorz.CodeTxDataStoreUpdate:    pb.TxErrorCode_TX_ERROR_DATASTORE_UPDATE,
+	errorz.CodeTxFee:                pb.TxErrorCode_TX_ERROR_FEE,
+	errorz.CodeTxSignature:          pb.TxErrorCode_TX_ERROR_SIGNATURE,
+	errorz.CodeTxState:              pb.TxErrorCode_TX_ERROR_STATE,
+	errorz.CodeTxFork:               pb.TxErrorCode_TX_ERROR_FORK,
+}
+
 // HandleLocalStateGetValueForOwner ...
 func (srpc *Handlers) HandleLocalStateGetValueForOwner(ctx context.Context, req *pb.GetValueRequest) (*pb.GetValueResponse, error) {
 	if err := srpc.notReady(); err != nil {
@@ -239,6 +344,23 @@ func (srpc *Handlers) HandleLocalStateGetValueForOwner(ctx context.Context, req
 	}
 
 	srpc.logger.Debugf("HandleLocalStateGetValueForOwner: %v", req)
+	var result *pb.GetValueResponse
+	err := srpc.database.View(func(txn *badger.Txn) error {
+		os, err := srpc.database.GetOwnState(txn)
+		if err != nil {
+			return err
+		}
+		result, err = srpc.getValueForOwner(txn, os.SyncToBH.BClaims.Height, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getValueForOwner answers a GetValueRequest in txn at height.
+func (srpc *Handlers) getValueForOwner(txn *badger.Txn, height uint32, req *pb.GetValueRequest) (*pb.GetValueResponse, error) {
 	minValue := uint256.Max()
 	if req.Minvalue != "" {
 		err := minValue.UnmarshalString(req.Minvalue)
@@ -256,30 +378,39 @@ func (srpc *Handlers) HandleLocalStateGetValueForOwner(ctx context.Context, req
 	if len(account) != 20 {
 		return nil, fmt.Errorf("invalid length (%v) for Account:%s", len(req.Account), req.Account)
 	}
+	var assetID []byte
+	if req.AssetID != "" {
+		assetID, err = ReverseTranslateByte(req.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		if len(assetID) != constants.HashLen {
+			return nil, fmt.Errorf("invalid length (%v) for AssetID:%s", len(assetID), req.AssetID)
+		}
+	}
 	var utxoIDs [][]byte
 	var value *uint256.Uint256
+	var lockedValue *uint256.Uint256
+	var vestedValue *uint256.Uint256
 	var paginationToken *objs.PaginationToken
-	var height uint32
-	err = srpc.database.View(func(txn *badger.Txn) error {
-		tmp, v, pt, err := srpc.AppHandler.GetValueForOwner(txn, constants.CurveSpec(req.CurveSpec), account, minValue, req.PaginationToken)
+	if assetID != nil {
+		// assets are never time locked
+		utxoIDs, value, paginationToken, err = srpc.AppHandler.GetAssetValueForOwner(txn, constants.CurveSpec(req.CurveSpec), account, assetID, minValue, req.PaginationToken)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		utxoIDs = tmp
-		value = v
-		paginationToken = pt
-
-		os, err := srpc.database.GetOwnState(txn)
+		lockedValue = uint256.Zero()
+		vestedValue = uint256.Zero()
+	} else {
+		// the value is spent by a transaction mined in the next block
+		utxoIDs, value, paginationToken, err = srpc.AppHandler.GetValueForOwner(txn, constants.CurveSpec(req.CurveSpec), account, height+1, minValue, req.PaginationToken)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		lockedValue, vestedValue, err = srpc.AppHandler.GetLockedValueForOwner(txn, constants.CurveSpec(req.CurveSpec), account, height+1)
+		if err != nil {
+			return nil, err
 		}
-		height = os.SyncToBH.BClaims.Height
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
 	}
 
 	out, err := ForwardTranslateByteSlice(utxoIDs)
@@ -291,6 +422,14 @@ func (srpc *Handlers) HandleLocalStateGetValueForOwner(ctx context.Context, req
 	if err != nil {
 		return nil, err
 	}
+	lockedValueString, err := lockedValue.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+	vestedValueString, err := vestedValue.MarshalString()
+	if err != nil {
+		return nil, err
+	}
 
 	var ptBytesRet []byte
 	if paginationToken != nil {
@@ -300,7 +439,7 @@ func (srpc *Handlers) HandleLocalStateGetValueForOwner(ctx context.Context, req
 		}
 	}
 
-	result := &pb.GetValueResponse{TotalValue: valueString, UTXOIDs: out, PaginationToken: ptBytesRet, BlockHeight: height}
+	result := &pb.GetValueResponse{TotalValue: valueString, UTXOIDs: out, PaginationToken: ptBytesRet, BlockHeight: height, LockedValue: lockedValueString, VestedValue: vestedValueString}
 	return result, nil
 }
 
@@ -427,20 +566,10 @@ func (srpc *Handlers) HandleLocalStateGetData(ctx context.Context, req *pb.GetDa
 	srpc.logger.Debugf("HandleLocalStateGetData: %v", req)
 
 	err := srpc.database.View(func(txn *badger.Txn) error {
-		account, err := ReverseTranslateByte(req.Account)
+		account, index, err := translateDataIndex(req)
 		if err != nil {
 			return err
 		}
-		if len(account) != 20 {
-			return fmt.Errorf("invalid length (%v) for Account:%s", len(req.Account), req.Account)
-		}
-		index, err := ReverseTranslateByte(req.Index)
-		if err != nil {
-			return err
-		}
-		if len(index) != 32 {
-			return fmt.Errorf("invalid length (%v) for Index:%s", len(req.Index), req.Index)
-		}
 		tmp, err := srpc.AppHandler.UTXOGetData(txn, constants.CurveSpec(req.CurveSpec), account, index)
 		if err != nil {
 			return err
@@ -457,6 +586,25 @@ func (srpc *Handlers) HandleLocalStateGetData(ctx context.Context, req *pb.GetDa
 	return result, nil
 }
 
+// translateDataIndex returns the account and the index of a GetDataRequest.
+func translateDataIndex(req *pb.GetDataRequest) ([]byte, []byte, error) {
+	account, err := ReverseTranslateByte(req.Account)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(account) != 20 {
+		return nil, nil, fmt.Errorf("invalid length (%v) for Account:%s", len(req.Account), req.Account)
+	}
+	index, err := ReverseTranslateByte(req.Index)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(index) != 32 {
+		return nil, nil, fmt.Errorf("invalid length (%v) for Index:%s", len(req.Index), req.Index)
+	}
+	return account, index, nil
+}
+
 // HandleLocalStateIterateNameSpace ...
 func (srpc *Handlers) HandleLocalStateIterateNameSpace(ctx context.Context, req *pb.IterateNameSpaceRequest) (*pb.IterateNameSpaceResponse, error) {
 	if err := srpc.notReady(); err != nil {
@@ -529,6 +677,74 @@ func (srpc *Handlers) HandleLocalStateIterateNameSpace(ctx context.Context, req
 	return resp, nil
 }
 
+// HandleLocalStateQueryNameSpace returns the datastores of an account selected
+// by an index prefix and range.
+func (srpc *Handlers) HandleLocalStateQueryNameSpace(ctx context.Context, req *pb.QueryNameSpaceRequest) (*pb.QueryNameSpaceResponse, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+
+	srpc.logger.Debugf("HandleLocalStateQueryNameSpace: %v", req)
+	if req.Limit > objs.MaxDataStoreQueryLimit {
+		return nil, fmt.Errorf("limit is not allowed to be greater than %v; got %v", objs.MaxDataStoreQueryLimit, req.Limit)
+	}
+	account, err := ReverseTranslateByte(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if len(account) != 20 {
+		return nil, fmt.Errorf("invalid length (%v) for account:%s", len(req.Account), req.Account)
+	}
+	q := &objs.DataStoreQuery{
+		Reverse: req.Reverse,
+		After:   req.ContinuationToken,
+		Limit:   int(req.Limit),
+	}
+	if q.Limit == 0 {
+		q.Limit = objs.MaxDataStoreQueryLimit
+	}
+	if q.Prefix, err = ReverseTranslateByte(req.Prefix); err != nil {
+		return nil, err
+	}
+	if q.Start, err = ReverseTranslateByte(req.StartIndex); err != nil {
+		return nil, err
+	}
+	if q.End, err = ReverseTranslateByte(req.EndIndex); err != nil {
+		return nil, err
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.QueryNameSpaceResponse{}
+	err = srpc.database.View(func(txn *badger.Txn) error {
+		os, err := srpc.database.GetOwnState(txn)
+		if err != nil {
+			return err
+		}
+		resp.BlockHeight = os.SyncToBH.BClaims.Height
+		// do not return the datastores which expire in the next block
+		items, next, err := srpc.AppHandler.QueryDataByOwner(txn, constants.CurveSpec(req.CurveSpec), account, resp.BlockHeight+1, q, req.IncludeMetadata)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			resp.Results = append(resp.Results, &pb.QueryNameSpaceResponse_Result{
+				UTXOID:            ForwardTranslateByte(item.UTXOID),
+				Index:             ForwardTranslateByte(item.Index),
+				IssuedAt:          item.IssuedAt,
+				EpochOfExpiration: item.EpochOfExpiration,
+			})
+		}
+		resp.ContinuationToken = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // HandleLocalStateGetUTXO ...
 func (srpc *Handlers) HandleLocalStateGetUTXO(ctx context.Context, req *pb.UTXORequest) (*pb.UTXOResponse, error) {
 	if err := srpc.notReady(); err != nil {
@@ -570,6 +786,132 @@ func (srpc *Handlers) HandleLocalStateGetUTXO(ctx context.Context, req *pb.UTXOR
 	return out, nil
 }
 
+// HandleLocalStateGetStateBatch answers the queries of the request in a single
+// read of the state, at the current height or at an earlier height which is
+// still retained. Only UTXOs can be read at an earlier height, as the indexes
+// of data and value only hold the current state.
+func (srpc *Handlers) HandleLocalStateGetStateBatch(ctx context.Context, req *pb.StateBatchRequest) (*pb.StateBatchResponse, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+
+	srpc.logger.Debugf("HandleLocalStateGetStateBatch: %v", req)
+	if n := len(req.UTXOIDs) + len(req.Data) + len(req.Owners); n > 256 {
+		return nil, errorz.ErrInvalid{}.New(fmt.Sprintf("localrpc.GetStateBatch; at most 256 queries are allowed; got %v", n))
+	}
+	for i := 0; i < len(req.UTXOIDs); i++ {
+		if len(req.UTXOIDs[i]) != 64 {
+			return nil, fmt.Errorf("invalid length (%v) for utxoID@index %v out of %v; %s", len(req.UTXOIDs[i]), i, len(req.UTXOIDs), req.UTXOIDs)
+		}
+	}
+	utxoIDs, err := ReverseTranslateByteSlice(req.UTXOIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pb.StateBatchResponse{}
+	err = srpc.database.View(func(txn *badger.Txn) error {
+		os, err := srpc.database.GetOwnState(txn)
+		if err != nil {
+			return err
+		}
+		currentHeight := os.SyncToBH.BClaims.Height
+		height := currentHeight
+		if req.Height != 0 {
+			height = req.Height
+		}
+		if height > currentHeight {
+			return errorz.ErrInvalid{}.New(fmt.Sprintf("localrpc.GetStateBatch; height %v is above the current height %v", height, currentHeight))
+		}
+		if height != currentHeight && (len(req.Data) > 0 || len(req.Owners) > 0) {
+			return errorz.ErrInvalid{}.New("localrpc.GetStateBatch; data and owners can only be read at the current height")
+		}
+		stateRoot, err := srpc.AppHandler.GetStateRootForHeight(txn, height)
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return errorz.NewErrStale("localrpc.GetStateBatch; the state at height %v is not retained", height)
+			}
+			return err
+		}
+		result.Height = height
+		result.StateRoot = ForwardTranslateByte(stateRoot)
+
+		utxos, missing, err := srpc.AppHandler.UTXOGetAtHeight(txn, height, utxoIDs)
+		if err != nil {
+			return err
+		}
+		for _, u := range utxos {
+			u2, err := ForwardTranslateTXOut(u)
+			if err != nil {
+				return err
+			}
+			result.UTXOs = append(result.UTXOs, u2)
+		}
+		for _, utxoID := range missing {
+			// deposits are not part of the state trie until they are spent,
+			// and their history is not kept
+			if height == currentHeight {
+				deposit, spent, err := srpc.AppHandler.DepositGet(txn, utxoID)
+				if err != nil {
+					return err
+				}
+				if deposit != nil && !spent {
+					u2, err := ForwardTranslateTXOut(deposit)
+					if err != nil {
+						return err
+					}
+					result.UTXOs = append(result.UTXOs, u2)
+					continue
+				}
+			}
+			result.MissingUTXOIDs = append(result.MissingUTXOIDs, ForwardTranslateByte(utxoID))
+		}
+
+		for _, d := range req.Data {
+			account, index, err := translateDataIndex(d)
+			if err != nil {
+				return err
+			}
+			dr := &pb.DataResult{}
+			utxo, err := srpc.AppHandler.UTXOGetDataStore(txn, constants.CurveSpec(d.CurveSpec), account, index)
+			if err != nil && err != badger.ErrKeyNotFound {
+				return err
+			}
+			if err == nil {
+				utxoID, err := utxo.UTXOID()
+				if err != nil {
+					return err
+				}
+				ds, err := utxo.DataStore()
+				if err != nil {
+					return err
+				}
+				rawData, err := ds.RawData()
+				if err != nil {
+					return err
+				}
+				dr.Found = true
+				dr.UTXOID = ForwardTranslateByte(utxoID)
+				dr.Rawdata = ForwardTranslateByte(rawData)
+			}
+			result.Data = append(result.Data, dr)
+		}
+
+		for _, o := range req.Owners {
+			vr, err := srpc.getValueForOwner(txn, height, o)
+			if err != nil {
+				return err
+			}
+			result.Owners = append(result.Owners, vr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // HandleLocalStateGetMinedTransaction ...
 func (srpc *Handlers) HandleLocalStateGetMinedTransaction(ctx context.Context, req *pb.MinedTransactionRequest) (*pb.MinedTransactionResponse, error) {
 	if err := srpc.notReady(); err != nil {
@@ -612,6 +954,53 @@ func (srpc *Handlers) HandleLocalStateGetMinedTransaction(ctx context.Context, r
 	return result, nil
 }
 
+// HandleLocalStateGetMinedTransactionsByMemo returns the hashes of the mined
+// transactions carrying a memo by the hash of the memo.
+func (srpc *Handlers) HandleLocalStateGetMinedTransactionsByMemo(ctx context.Context, req *pb.MemoTransactionsRequest) (*pb.MemoTransactionsResponse, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+
+	srpc.logger.Debugf("HandleLocalStateGetMinedTransactionsByMemo: %v", req)
+	if req.Limit > 256 {
+		return nil, fmt.Errorf("limit is not allowed to be greater than 256; got %v", req.Limit)
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 256
+	}
+	memoHash, err := ReverseTranslateByte(req.MemoHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(memoHash) != constants.HashLen {
+		return nil, fmt.Errorf("invalid length for MemoHash: %v", len(req.MemoHash))
+	}
+	startTxHash, err := ReverseTranslateByte(req.StartTxHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(startTxHash) != 0 && len(startTxHash) != constants.HashLen {
+		return nil, fmt.Errorf("invalid length for StartTxHash: %v", len(req.StartTxHash))
+	}
+	resp := &pb.MemoTransactionsResponse{}
+	err = srpc.database.View(func(txn *badger.Txn) error {
+		txHashes, next, err := srpc.AppHandler.MinedTxGetByMemo(txn, memoHash, limit, startTxHash)
+		if err != nil {
+			return err
+		}
+		for _, txHash := range txHashes {
+			resp.TxHashes = append(resp.TxHashes, ForwardTranslateByte(txHash))
+		}
+		resp.NextTxHash = ForwardTranslateByte(next)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // HandleLocalStateGetTransactionStatus ...
 func (srpc *Handlers) HandleLocalStateGetTransactionStatus(ctx context.Context, req *pb.TransactionStatusRequest) (*pb.TransactionStatusResponse, error) {
 	if err := srpc.notReady(); err != nil {
@@ -730,12 +1119,177 @@ func (srpc *Handlers) HandleLocalStateGetFees(ctx context.Context, req *pb.FeeRe
 	if err != nil {
 		return nil, err
 	}
+	memoFee := sg.GetMemoByteFee()
+	mfs, err := bigIntToString(memoFee)
+	if err != nil {
+		return nil, err
+	}
 	result := &pb.FeeResponse{
 		MinTxFee:      txfs,
 		ValueStoreFee: vsfs,
 		DataStoreFee:  dsfs,
+		MemoByteFee:   mfs,
+	}
+
+	return result, nil
+}
+
+// HandleLocalStateGetDepositStatus links a deposit nonce to the monitor
+// progress and to the deposit UTXO once it has been processed.
+func (srpc *Handlers) HandleLocalStateGetDepositStatus(ctx context.Context, req *pb.DepositStatusRequest) (*pb.DepositStatusResponse, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+	if srpc.deposits == nil {
+		return nil, errors.New("deposit tracking is not available")
+	}
+	if req.Nonce == 0 {
+		return nil, errors.New("invalid deposit nonce 0")
+	}
+
+	srpc.logger.Debugf("HandleLocalStateGetDepositStatus: %v", req)
+	seen, processed := srpc.deposits.GetLatestDeposits()
+	utxoID := utils.ForceSliceToLength(new(big.Int).SetUint64(uint64(req.Nonce)).Bytes(), constants.HashLen)
+	result := &pb.DepositStatusResponse{
+		LatestDepositSeen:      seen,
+		LatestDepositProcessed: processed,
+		Seen:                   req.Nonce <= seen,
+		Processed:              req.Nonce <= processed,
+		UTXOID:                 ForwardTranslateByte(utxoID),
+	}
+
+	err := srpc.database.View(func(txn *badger.Txn) error {
+		utxo, spent, err := srpc.AppHandler.DepositGet(txn, utxoID)
+		if err != nil {
+			return err
+		}
+		if utxo == nil {
+			return nil
+		}
+		result.UTXO, err = ForwardTranslateTXOut(utxo)
+		if err != nil {
+			return err
+		}
+		result.Spent = spent
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// HandleLocalStateGetDataStoreStatus returns the DataStore stored by an
+// account at an index, with the refund and the fee needed to renew or
+// transfer it in the next block.
+func (srpc *Handlers) HandleLocalStateGetDataStoreStatus(ctx context.Context, req *pb.DataStoreStatusRequest) (*pb.DataStoreStatusResponse, error) {
+	if err := srpc.notReady(); err != nil {
+		return nil, err
+	}
+
+	srpc.logger.Debugf("HandleLocalStateGetDataStoreStatus: %v", req)
+	account, err := ReverseTranslateByte(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if len(account) != 20 {
+		return nil, fmt.Errorf("invalid length (%v) for Account:%s", len(req.Account), req.Account)
+	}
+	index, err := ReverseTranslateByte(req.Index)
+	if err != nil {
+		return nil, err
+	}
+	if len(index) != 32 {
+		return nil, fmt.Errorf("invalid length (%v) for Index:%s", len(req.Index), req.Index)
+	}
+
+	result := &pb.DataStoreStatusResponse{}
+	err = srpc.database.View(func(txn *badger.Txn) error {
+		os, err := srpc.database.GetOwnState(txn)
+		if err != nil {
+			return err
+		}
+		height := os.SyncToBH.BClaims.Height
+		utxo, err := srpc.AppHandler.UTXOGetDataStore(txn, constants.CurveSpec(req.CurveSpec), account, index)
+		if err != nil {
+			return err
+		}
+		ds, err := utxo.DataStore()
+		if err != nil {
+			return err
+		}
+		eoe, err := ds.EpochOfExpiration()
+		if err != nil {
+			return err
+		}
+		// the datastore is consumed by a transaction mined in the next block
+		refund, err := ds.RemainingValue(height + 1)
+		if err != nil {
+			return err
+		}
+		refundString, err := refund.MarshalString()
+		if err != nil {
+			return err
+		}
+		utxoID, err := utxo.UTXOID()
+		if err != nil {
+			return err
+		}
+		result.UTXO, err = ForwardTranslateTXOut(utxo)
+		if err != nil {
+			return err
+		}
+		result.UTXOID = ForwardTranslateByte(utxoID)
+		result.BlockHeight = height
+		result.EpochOfExpiration = eoe
+		result.RemainingValue = refundString
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.DataStoreFee, err = bigIntToString(srpc.Storage.GetDataStoreFee())
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
+// HandleLocalStateQueryLayer1Events returns the layer1 events processed by the
+// monitor that match the request.
+func (srpc *Handlers) HandleLocalStateQueryLayer1Events(ctx context.Context, req *pb.Layer1EventsRequest) (*pb.Layer1EventsResponse, error) {
+	if srpc.eventIndex == nil {
+		return nil, errors.New("the layer1 event index is not available")
+	}
+	q := &indexer.Query{
+		FromBlock: req.FromBlock,
+		ToBlock:   req.ToBlock,
+		Name:      req.Name,
+		Limit:     int(req.Limit),
+	}
+	if req.Contract != "" {
+		if !common.IsHexAddress(req.Contract) {
+			return nil, fmt.Errorf("invalid contract address %q", req.Contract)
+		}
+		q.Contract = common.HexToAddress(req.Contract)
+	}
+
+	srpc.logger.Debugf("HandleLocalStateQueryLayer1Events: %v", req)
+	events, next, err := srpc.eventIndex.QueryEvents(q)
+	if err != nil {
+		return nil, err
+	}
+	result := &pb.Layer1EventsResponse{NextFromBlock: next}
+	for _, event := range events {
+		result.Events = append(result.Events, &pb.Layer1Event{
+			Contract: ForwardTranslateByte(event.Contract.Bytes()),
+			Name:     event.Name,
+			Block:    event.Block,
+			TxHash:   ForwardTranslateByte(event.TxHash.Bytes()),
+			LogIndex: event.LogIndex,
+			Fields:   event.Fields,
+		})
+	}
 	return result, nil
 }
 
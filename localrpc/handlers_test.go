@@ -8,9 +8,17 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/alicenet/alicenet/application/objs"
 	"github.com/alicenet/alicenet/application/objs/uint256"
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/errorz"
+	"github.com/alicenet/alicenet/layer1/monitor/indexer"
 	pb "github.com/alicenet/alicenet/proto"
 )
 
@@ -186,6 +194,7 @@ func TestHandlers_HandleLocalStateGetFees(t *testing.T) {
 				MinTxFee:      "0000000000000000000000000000000000000000000000000000000000000fa0",
 				ValueStoreFee: "0000000000000000000000000000000000000000000000000000000000000bb8",
 				DataStoreFee:  "0000000000000000000000000000000000000000000000000000000000000bb8",
+				MemoByteFee:   "0000000000000000000000000000000000000000000000000000000000000000",
 			},
 		},
 	}
@@ -341,6 +350,239 @@ func TestHandlers_HandleLocalStateGetUTXO(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleLocalStateGetStateBatch(t *testing.T) {
+	owner := &pb.GetValueRequest{CurveSpec: 1, Account: hex.EncodeToString(account)}
+	req := &pb.StateBatchRequest{
+		UTXOIDs: []string{hex.EncodeToString(utxoTx2IDs[0])},
+		Data:    []*pb.GetDataRequest{{CurveSpec: 1, Account: hex.EncodeToString(account), Index: hex.EncodeToString(make([]byte, 32))}},
+		Owners:  []*pb.GetValueRequest{owner},
+	}
+	got, err := srpc.HandleLocalStateGetStateBatch(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Height != 1 || len(got.StateRoot) != 64 {
+		t.Fatalf("wrong height %v or state root %v", got.Height, got.StateRoot)
+	}
+	// the test utxos are applied at height 2, above the synced height
+	if len(got.UTXOs) != 0 || !reflect.DeepEqual(got.MissingUTXOIDs, req.UTXOIDs) {
+		t.Fatalf("utxo should be missing at height 1: %v", got)
+	}
+	if len(got.Data) != 1 || got.Data[0].Found {
+		t.Fatalf("datastore should not be found: %v", got.Data)
+	}
+	value, err := srpc.HandleLocalStateGetValueForOwner(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Owners) != 1 || !reflect.DeepEqual(got.Owners[0], value) {
+		t.Fatalf("HandleLocalStateGetStateBatch() owner = %v, want %v", got.Owners, value)
+	}
+
+	pinned, err := srpc.HandleLocalStateGetStateBatch(ctx, &pb.StateBatchRequest{Height: 1, UTXOIDs: req.UTXOIDs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.Height != 1 || pinned.StateRoot != got.StateRoot {
+		t.Fatalf("wrong state at height 1: %v", pinned)
+	}
+
+	if _, err := srpc.HandleLocalStateGetStateBatch(ctx, &pb.StateBatchRequest{Height: 2, UTXOIDs: req.UTXOIDs}); errorz.CodeOf(err) != errorz.CodeInvalid {
+		t.Fatalf("height above the current height should be invalid: %v", err)
+	}
+	tooMany := &pb.StateBatchRequest{}
+	for i := 0; i < 257; i++ {
+		tooMany.UTXOIDs = append(tooMany.UTXOIDs, req.UTXOIDs[0])
+	}
+	if _, err := srpc.HandleLocalStateGetStateBatch(ctx, tooMany); errorz.CodeOf(err) != errorz.CodeInvalid {
+		t.Fatalf("too many queries should be invalid: %v", err)
+	}
+}
+
+type fakeDepositTracker struct {
+	seen, processed uint32
+}
+
+func (f *fakeDepositTracker) GetLatestDeposits() (uint32, uint32) {
+	return f.seen, f.processed
+}
+
+func TestHandlers_HandleLocalStateGetDepositStatus(t *testing.T) {
+	_, err := srpc.HandleLocalStateGetDepositStatus(ctx, &pb.DepositStatusRequest{Nonce: 1})
+	if err == nil {
+		t.Fatal("HandleLocalStateGetDepositStatus() expected an error without a deposit tracker")
+	}
+
+	srpc.SetDepositTracker(&fakeDepositTracker{seen: 11, processed: 10})
+	defer srpc.SetDepositTracker(nil)
+
+	depositor := crypto.GetAccount(crypto.Hasher([]byte("depositor")))
+	owner := &objs.Owner{}
+	if err := owner.New(depositor, constants.CurveSecp256k1); err != nil {
+		t.Fatal(err)
+	}
+	// the test database persists between runs, the deposit may already be there
+	got, err := srpc.HandleLocalStateGetDepositStatus(ctx, &pb.DepositStatusRequest{Nonce: 10})
+	if err != nil {
+		t.Fatalf("HandleLocalStateGetDepositStatus() error = %v", err)
+	}
+	if got.UTXO == nil {
+		err = consDB.Update(func(txn *badger.Txn) error {
+			return appDepositHandler.Add(txn, chainID, big.NewInt(10).Bytes(), big.NewInt(500), owner)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err = srpc.HandleLocalStateGetDepositStatus(ctx, &pb.DepositStatusRequest{Nonce: 10})
+	if err != nil {
+		t.Fatalf("HandleLocalStateGetDepositStatus() error = %v", err)
+	}
+	utxoID := "000000000000000000000000000000000000000000000000000000000000000a"
+	if !got.Seen || !got.Processed || got.Spent || got.UTXOID != utxoID || got.LatestDepositSeen != 11 || got.LatestDepositProcessed != 10 {
+		t.Errorf("HandleLocalStateGetDepositStatus() got = %v", got)
+	}
+	vs := got.UTXO.GetValueStore()
+	if vs == nil || vs.TxHash != utxoID || vs.VSPreImage.TXOutIdx != constants.MaxUint32 || vs.VSPreImage.Owner != "0101"+hex.EncodeToString(depositor) {
+		t.Errorf("HandleLocalStateGetDepositStatus() got UTXO = %v", got.UTXO)
+	}
+
+	got, err = srpc.HandleLocalStateGetDepositStatus(ctx, &pb.DepositStatusRequest{Nonce: 11})
+	if err != nil {
+		t.Fatalf("HandleLocalStateGetDepositStatus() error = %v", err)
+	}
+	if !got.Seen || got.Processed || got.UTXO != nil {
+		t.Errorf("HandleLocalStateGetDepositStatus() got = %v", got)
+	}
+
+	_, err = srpc.HandleLocalStateGetDepositStatus(ctx, &pb.DepositStatusRequest{})
+	if err == nil {
+		t.Error("HandleLocalStateGetDepositStatus() expected an error for nonce 0")
+	}
+}
+
+func TestHandlers_HandleLocalStateGetDataStoreStatus(t *testing.T) {
+	account := hex.EncodeToString(crypto.GetAccount(crypto.Hasher([]byte("owner"))))
+	index := hex.EncodeToString(crypto.Hasher([]byte("index")))
+	tests := []struct {
+		name string
+		req  *pb.DataStoreStatusRequest
+	}{
+		{"invalid account", &pb.DataStoreStatusRequest{CurveSpec: 1, Account: "0102", Index: index}},
+		{"invalid index", &pb.DataStoreStatusRequest{CurveSpec: 1, Account: account, Index: "0102"}},
+		{"missing datastore", &pb.DataStoreStatusRequest{CurveSpec: 1, Account: account, Index: index}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := srpc.HandleLocalStateGetDataStoreStatus(ctx, tt.req); err == nil {
+				t.Error("HandleLocalStateGetDataStoreStatus() expected an error")
+			}
+		})
+	}
+}
+
+func TestHandlers_HandleLocalStateQueryNameSpace(t *testing.T) {
+	account := hex.EncodeToString(crypto.GetAccount(crypto.Hasher([]byte("owner"))))
+	tests := []struct {
+		name    string
+		req     *pb.QueryNameSpaceRequest
+		wantErr bool
+	}{
+		{"invalid account", &pb.QueryNameSpaceRequest{CurveSpec: 1, Account: "0102"}, true},
+		{"invalid limit", &pb.QueryNameSpaceRequest{CurveSpec: 1, Account: account, Limit: 257}, true},
+		{"invalid range", &pb.QueryNameSpaceRequest{CurveSpec: 1, Account: account, StartIndex: "02", EndIndex: "01"}, true},
+		{"invalid continuation", &pb.QueryNameSpaceRequest{CurveSpec: 1, Account: account, ContinuationToken: []byte{1}}, true},
+		{"no datastores", &pb.QueryNameSpaceRequest{CurveSpec: 1, Account: account, Prefix: "01", Reverse: true, IncludeMetadata: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srpc.HandleLocalStateQueryNameSpace(ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleLocalStateQueryNameSpace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(got.Results) != 0 || len(got.ContinuationToken) != 0) {
+				t.Errorf("HandleLocalStateQueryNameSpace() got = %v", got)
+			}
+		})
+	}
+}
+
+func TestHandlers_HandleLocalStateGetMinedTransactionsByMemo(t *testing.T) {
+	memoHash := hex.EncodeToString(crypto.Hasher([]byte("memo")))
+	tests := []struct {
+		name    string
+		req     *pb.MemoTransactionsRequest
+		wantErr bool
+	}{
+		{"invalid memo hash", &pb.MemoTransactionsRequest{MemoHash: "0102"}, true},
+		{"invalid limit", &pb.MemoTransactionsRequest{MemoHash: memoHash, Limit: 257}, true},
+		{"invalid start", &pb.MemoTransactionsRequest{MemoHash: memoHash, StartTxHash: "0102"}, true},
+		{"no transactions", &pb.MemoTransactionsRequest{MemoHash: memoHash, Limit: 10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srpc.HandleLocalStateGetMinedTransactionsByMemo(ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleLocalStateGetMinedTransactionsByMemo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(got.TxHashes) != 0 || len(got.NextTxHash) != 0) {
+				t.Errorf("HandleLocalStateGetMinedTransactionsByMemo() got = %v", got)
+			}
+		})
+	}
+}
+
+type fakeEventIndex struct {
+	query  *indexer.Query
+	events []*indexer.Event
+}
+
+func (f *fakeEventIndex) QueryEvents(q *indexer.Query) ([]*indexer.Event, uint64, error) {
+	f.query = q
+	return f.events, 7, nil
+}
+
+func TestHandlers_HandleLocalStateQueryLayer1Events(t *testing.T) {
+	_, err := srpc.HandleLocalStateQueryLayer1Events(ctx, &pb.Layer1EventsRequest{})
+	if err == nil {
+		t.Fatal("HandleLocalStateQueryLayer1Events() expected an error without an event index")
+	}
+
+	contract := common.HexToAddress("0x546F99F244b7B58B855330AE0E2BC1b30b41302F")
+	index := &fakeEventIndex{events: []*indexer.Event{{
+		Contract: contract,
+		Name:     "ValidatorMinorSlashed",
+		Block:    5,
+		TxHash:   common.HexToHash("0x01"),
+		LogIndex: 2,
+		Fields:   map[string]string{"account": contract.Hex(), "publicStaking": "3"},
+	}}}
+	srpc.SetEventIndex(index)
+	defer srpc.SetEventIndex(nil)
+
+	got, err := srpc.HandleLocalStateQueryLayer1Events(ctx, &pb.Layer1EventsRequest{FromBlock: 1, ToBlock: 9, Name: "ValidatorMinorSlashed", Contract: contract.Hex(), Limit: 10})
+	if err != nil {
+		t.Fatalf("HandleLocalStateQueryLayer1Events() error = %v", err)
+	}
+	wantQuery := &indexer.Query{FromBlock: 1, ToBlock: 9, Name: "ValidatorMinorSlashed", Contract: contract, Limit: 10}
+	if !reflect.DeepEqual(index.query, wantQuery) {
+		t.Errorf("HandleLocalStateQueryLayer1Events() query = %v, want %v", index.query, wantQuery)
+	}
+	if got.NextFromBlock != 7 || len(got.Events) != 1 {
+		t.Fatalf("HandleLocalStateQueryLayer1Events() got = %v", got)
+	}
+	event := got.Events[0]
+	if event.Contract != hex.EncodeToString(contract.Bytes()) || event.Block != 5 || event.LogIndex != 2 || event.Fields["publicStaking"] != "3" {
+		t.Errorf("HandleLocalStateQueryLayer1Events() got event = %v", event)
+	}
+
+	_, err = srpc.HandleLocalStateQueryLayer1Events(ctx, &pb.Layer1EventsRequest{Contract: "0x01"})
+	if err == nil {
+		t.Error("HandleLocalStateQueryLayer1Events() expected an error for an invalid contract")
+	}
+}
+
 var hash []byte
 
 var (
@@ -460,6 +702,63 @@ func TestHandlers_HandleLocalStateGetTransactionStatus(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleLocalStateValidateTransaction(t *testing.T) {
+	_, _, consumedHash := insertTestUTXO(20000, storage.GetValueStoreFee())
+	validTx, validTxHash, _ := getTransactionRequest(consumedHash, crypto.GetAccount(pubKey), 20000)
+	missingTx, _, _ := getTransactionRequest(crypto.Hasher([]byte("missing")), crypto.GetAccount(pubKey), 20000)
+	_, _ = srpc.HandleLocalStateSendTransaction(ctx, tx1)
+	tests := []struct {
+		name      string
+		req       *pb.TransactionData
+		wantValid bool
+		wantCode  pb.TxErrorCode
+		wantInput int32
+	}{
+		{"valid", validTx, true, pb.TxErrorCode_TX_ERROR_UNSPECIFIED, 0},
+		{"pending", tx1, false, pb.TxErrorCode_TX_ERROR_DUPLICATE, -1},
+		{"missing input", missingTx, false, pb.TxErrorCode_TX_ERROR_INPUT_MISSING, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srpc.HandleLocalStateValidateTransaction(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("HandleLocalStateValidateTransaction() error = %v", err)
+			}
+			if got.Valid != tt.wantValid {
+				t.Fatalf("HandleLocalStateValidateTransaction() got = %v, wantValid %v", got, tt.wantValid)
+			}
+			if tt.wantValid {
+				if len(got.Errors) != 0 {
+					t.Errorf("HandleLocalStateValidateTransaction() got errors %v", got.Errors)
+				}
+				return
+			}
+			if got.Errors[0].Code != tt.wantCode || got.Errors[0].Input != tt.wantInput {
+				t.Errorf("HandleLocalStateValidateTransaction() got = %v, want code %v input %v", got.Errors[0], tt.wantCode, tt.wantInput)
+			}
+		})
+	}
+	// validation must not add the transaction to the pending tx pool
+	_, err := srpc.HandleLocalStateGetPendingTransaction(ctx, &pb.PendingTransactionRequest{TxHash: hex.EncodeToString(validTxHash)})
+	if err == nil {
+		t.Error("HandleLocalStateValidateTransaction() added the transaction to the pending tx pool")
+	}
+}
+
+func TestHandlers_statusCodes(t *testing.T) {
+	ctx := context.Background()
+	// tx1 is in the pending tx pool, so the tx is rejected as a duplicate
+	_, _ = srpc.HandleLocalStateSendTransaction(ctx, tx1)
+	_, err := lrpc.client.SendTransaction(ctx, tx1)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("wrong status code %v: %v", status.Code(err), err)
+	}
+	code := errorz.CodeOf(err)
+	if code != errorz.CodeTxDuplicate || code.Retryable() {
+		t.Fatalf("wrong error code %v", code)
+	}
+}
+
 func TestHandlers_notReady(t *testing.T) {
 	tests := []struct {
 		name    string
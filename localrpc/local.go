@@ -14,8 +14,10 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
 	"github.com/alicenet/alicenet/interfaces"
 	pb "github.com/alicenet/alicenet/proto"
 )
@@ -47,6 +49,12 @@ func (rpch *Handler) Close() error {
 	return nil
 }
 
+// statusErrorHandler does the same as errorz.StatusServerInterceptor for the
+// RESTful API, whose requests do not pass through the grpc server.
+func statusErrorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, r, errorz.GRPCStatus(err, codes.Unknown))
+}
+
 func (rpch *Handler) Serve() {
 	defer rpch.Close()
 	if err := rpch.server.Serve(rpch.listener); err != nil {
@@ -58,7 +66,7 @@ func (rpch *Handler) Serve() {
 // Service.
 func NewStateServerHandler(logger *logrus.Logger, addr string, service interfaces.StateServer) (*Handler, error) {
 	// create the grpc server
-	grpcServer := grpc.NewServer(grpc.MaxConcurrentStreams(constants.MaxConcurrentStreams), grpc.NumStreamWorkers(constants.LocalRPCMaxWorkers), grpc.ReadBufferSize(constants.ReadBufferSize))
+	grpcServer := grpc.NewServer(grpc.MaxConcurrentStreams(constants.MaxConcurrentStreams), grpc.NumStreamWorkers(constants.LocalRPCMaxWorkers), grpc.ReadBufferSize(constants.ReadBufferSize), grpc.UnaryInterceptor(errorz.StatusServerInterceptor(codes.Unknown)))
 	pb.RegisterLocalStateServer(grpcServer, service)
 
 	// make a server mux
@@ -73,8 +81,8 @@ func NewStateServerHandler(logger *logrus.Logger, addr string, service interface
 	// add redirect to file server
 	mux.HandleFunc("/swagger.json", serveSwagger)
 
-	// make a new grpc runtime mux
-	gwmux := runtime.NewServeMux()
+	// make a new grpc runtime mux which reports errors with their errorz.Code
+	gwmux := runtime.NewServeMux(runtime.WithErrorHandler(statusErrorHandler))
 
 	// make grpc handle cors request
 	cmux := cors.Default().Handler(gwmux)
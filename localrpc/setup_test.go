@@ -8,6 +8,7 @@ import (
 	"io"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
@@ -69,10 +70,27 @@ var (
 	consDB             *db.Database
 	consSync           *consensus.Synchronizer
 	storage            *dynamics.Storage
+	appDepositHandler  *deposit.Handler
 )
 
 func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+// runTests runs the tests against a copy of the chain in testDB which is kept
+// in a temporary directory, so the fixture itself is never written to.
+func runTests(m *testing.M) int {
 	loadSettings("validator.toml")
+	dbDir, err := os.MkdirTemp("", "localrpc")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dbDir)
+	if err := copyDir("testDB", dbDir); err != nil {
+		panic(err)
+	}
+	config.Configuration.Chain.StateDbPath = dbDir
+	config.Configuration.Chain.MonitorDbPath = filepath.Join(dbDir, "validatorMon")
 	signer, pubKey = getSignerData()
 	validatorNode()
 
@@ -143,9 +161,7 @@ func TestMain(m *testing.M) {
 	tx3, tx3Hash, tx3Signature = getTransactionRequest(consumedTx3Hash, crypto.GetAccount(pubKey), getTxValues(3))
 
 	// Start tests after validator is running
-	exitVal := m.Run()
-
-	os.Exit(exitVal)
+	return m.Run()
 }
 
 func validatorNode() {
@@ -188,7 +204,7 @@ func validatorNode() {
 
 	// app maintains the UTXO set of the AliceNet blockchain (module is separate from consensus e.d.)
 	app = &application.Application{}
-	appDepositHandler := &deposit.Handler{} // watches ETH blockchain about deposits
+	appDepositHandler = &deposit.Handler{} // watches ETH blockchain about deposits
 
 	// consDlManager is used to retrieve transactions or block headers (to verify validity for proposal vote)
 	consDlManager = &dman.DMan{}
@@ -228,7 +244,7 @@ func validatorNode() {
 	consTxPool := evidence.NewPool(consDB)
 
 	appDepositHandler.Init()
-	if err := app.Init(consDB, rawTxPoolDb, appDepositHandler, storage); err != nil {
+	if err := app.Init(consDB, rawTxPoolDb, appDepositHandler, storage, 1); err != nil {
 		panic(err)
 	}
 
@@ -408,6 +424,28 @@ func getTransactionRequest(ConsumedTxHash, account []byte, val uint64) (tx_ *pro
 	return transactionData, hash, signature
 }
 
+// copyDir copies the files of the directory src into dst.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode())
+	})
+}
+
 func getSignerData() (*crypto.Secp256k1Signer, []byte) {
 	signer := &crypto.Secp256k1Signer{}
 	err := signer.SetPrivk(crypto.Hasher([]byte("secret")))
@@ -461,6 +499,10 @@ func initPeerManager(consGossipHandlers *gossip.Handlers, consReqHandler *reques
 		panic(err)
 	}
 	p2pDispatch.RegisterP2PGetPeers(peerManager)
+	p2pDispatch.RegisterP2PGossipTransactionBatch(peerManager)
+	p2pDispatch.RegisterP2PGossipConsensusBatch(peerManager)
+	p2pDispatch.RegisterP2PGossipTxInventory(peerManager)
+	peerManager.SetTxInventory(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipTransaction(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipProposal(consGossipHandlers)
 	p2pDispatch.RegisterP2PGossipPreVote(consGossipHandlers)
@@ -499,13 +541,20 @@ func initLocalStateServer(localStateHandler *Handlers) *Handler {
 	localStateDispatch.RegisterLocalStateGetUTXO(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetTransactionStatus(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetMinedTransaction(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetMinedTransactionsByMemo(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetStateBatch(localStateHandler)
+	localStateDispatch.RegisterLocalStateValidateTransaction(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetPendingTransaction(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetRoundStateForValidator(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetValidatorSet(localStateHandler)
 	localStateDispatch.RegisterLocalStateIterateNameSpace(localStateHandler)
+	localStateDispatch.RegisterLocalStateQueryNameSpace(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetData(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetTxBlockNumber(localStateHandler)
 	localStateDispatch.RegisterLocalStateGetFees(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetDepositStatus(localStateHandler)
+	localStateDispatch.RegisterLocalStateGetDataStoreStatus(localStateHandler)
+	localStateDispatch.RegisterLocalStateQueryLayer1Events(localStateHandler)
 
 	return localStateServer
 }
@@ -517,7 +566,7 @@ func insertTestUTXO(value_ uint64, fee_ *big.Int) ([][]byte, []byte, []byte) {
 		CurveSpec: constants.CurveSecp256k1,
 		Account:   accountAddress,
 	}
-	hndlr := utxohandler.NewUTXOHandler(consDB.DB())
+	hndlr := utxohandler.NewUTXOHandler(consDB.DB(), nil)
 	err = hndlr.Init(1)
 	if err != nil {
 		fmt.Printf("could not create utxo handler %v \n", err)
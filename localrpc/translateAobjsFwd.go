@@ -85,6 +85,113 @@ func ForwardTranslateVSPreImage(f *from.VSPreImage) (*to.VSPreImage, error) {
 	return t, nil
 }
 
+func ForwardTranslateAtomicSwap(f *from.AtomicSwap) (*to.AtomicSwap, error) {
+	t := &to.AtomicSwap{}
+	if f == nil {
+		return nil, errors.New("atomicSwap object should not be nil")
+	}
+
+	newTxHash := ForwardTranslateByte(f.TxHash)
+
+	t.TxHash = newTxHash
+	if f.ASPreImage != nil {
+		newASPreImage, err := ForwardTranslateASPreImage(f.ASPreImage)
+		if err != nil {
+			return nil, err
+		}
+		t.ASPreImage = newASPreImage
+	}
+	return t, nil
+}
+
+func ForwardTranslateASPreImage(f *from.ASPreImage) (*to.ASPreImage, error) {
+	t := &to.ASPreImage{}
+	if f == nil {
+		return nil, errors.New("object of type ASPreImage should not be nil")
+	}
+
+	t.ChainID = f.ChainID
+
+	if f.Owner != nil {
+		ownerBytes, err := f.Owner.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		newOwner := ForwardTranslateByte(ownerBytes)
+
+		t.Owner = newOwner
+	}
+
+	t.TXOutIdx = f.TXOutIdx
+	t.IssuedAt = f.IssuedAt
+	t.Exp = f.Exp
+
+	var err error
+	t.Value, err = f.Value.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+
+	t.Fee, err = f.Fee.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func ForwardTranslateTokenStore(f *from.TokenStore) (*to.TokenStore, error) {
+	t := &to.TokenStore{}
+	if f == nil {
+		return nil, errors.New("tokenStore object should not be nil")
+	}
+
+	newTxHash := ForwardTranslateByte(f.TxHash)
+
+	t.TxHash = newTxHash
+	if f.TSPreImage != nil {
+		newTSPreImage, err := ForwardTranslateTSPreImage(f.TSPreImage)
+		if err != nil {
+			return nil, err
+		}
+		t.TSPreImage = newTSPreImage
+	}
+	return t, nil
+}
+
+func ForwardTranslateTSPreImage(f *from.TSPreImage) (*to.TSPreImage, error) {
+	t := &to.TSPreImage{}
+	if f == nil {
+		return nil, errors.New("object of type TSPreImage should not be nil")
+	}
+
+	t.ChainID = f.ChainID
+	t.AssetID = ForwardTranslateByte(f.AssetID)
+
+	if f.Owner != nil {
+		ownerBytes, err := f.Owner.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		newOwner := ForwardTranslateByte(ownerBytes)
+
+		t.Owner = newOwner
+	}
+
+	t.TXOutIdx = f.TXOutIdx
+
+	var err error
+	t.Amount, err = f.Amount.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+
+	t.Fee, err = f.Fee.MarshalString()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
 func ForwardTranslateTXInLinker(f *from.TXInLinker) (*to.TXInLinker, error) {
 	t := &to.TXInLinker{}
 	if f == nil {
@@ -134,6 +241,30 @@ func ForwardTranslateTXOut(f *from.TXOut) (*to.TXOut, error) {
 		tt := &to.TXOut_DataStore{DataStore: newObj}
 		t := &to.TXOut{Utxo: tt}
 		return t, nil
+	case f.HasAtomicSwap():
+		obj, err := f.AtomicSwap()
+		if err != nil {
+			return nil, err
+		}
+		newObj, err := ForwardTranslateAtomicSwap(obj)
+		if err != nil {
+			return nil, err
+		}
+		tt := &to.TXOut_AtomicSwap{AtomicSwap: newObj}
+		t := &to.TXOut{Utxo: tt}
+		return t, nil
+	case f.HasTokenStore():
+		obj, err := f.TokenStore()
+		if err != nil {
+			return nil, err
+		}
+		newObj, err := ForwardTranslateTokenStore(obj)
+		if err != nil {
+			return nil, err
+		}
+		tt := &to.TXOut_TokenStore{TokenStore: newObj}
+		t := &to.TXOut{Utxo: tt}
+		return t, nil
 	default:
 		return nil, errors.New("no txout in forward translate")
 	}
@@ -166,6 +297,9 @@ func ForwardTranslateTx(f *from.Tx) (*to.Tx, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(f.Memo) > 0 {
+		t.Memo = ForwardTranslateByte(f.Memo)
+	}
 	return t, nil
 }
 
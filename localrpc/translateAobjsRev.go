@@ -89,6 +89,126 @@ func ReverseTranslateVSPreImage(f *from.VSPreImage) (*to.VSPreImage, error) {
 	return t, nil
 }
 
+func ReverseTranslateAtomicSwap(f *from.AtomicSwap) (*to.AtomicSwap, error) {
+	t := &to.AtomicSwap{}
+	newTxHash, err := ReverseTranslateByte(f.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	t.TxHash = newTxHash
+
+	if f.ASPreImage != nil {
+		newASPreImage, err := ReverseTranslateASPreImage(f.ASPreImage)
+		if err != nil {
+			return nil, err
+		}
+		t.ASPreImage = newASPreImage
+	}
+
+	return t, nil
+}
+
+func ReverseTranslateASPreImage(f *from.ASPreImage) (*to.ASPreImage, error) {
+	t := &to.ASPreImage{}
+	t.ChainID = f.ChainID
+
+	if f.Owner != "" {
+		ownerBytes, err := ReverseTranslateByte(f.Owner)
+		if err != nil {
+			return nil, err
+		}
+		newOwner := &to.AtomicSwapOwner{}
+		err = newOwner.UnmarshalBinary(ownerBytes)
+		if err != nil {
+			return nil, err
+		}
+		t.Owner = newOwner
+	}
+
+	t.TXOutIdx = f.TXOutIdx
+	t.IssuedAt = f.IssuedAt
+	t.Exp = f.Exp
+
+	t.Value = &uint256.Uint256{}
+	err := t.Value.UnmarshalString(f.Value)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Fee) == 0 {
+		f.Fee = "0"
+	}
+	t.Fee = &uint256.Uint256{}
+	err = t.Fee.UnmarshalString(f.Fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func ReverseTranslateTokenStore(f *from.TokenStore) (*to.TokenStore, error) {
+	t := &to.TokenStore{}
+	newTxHash, err := ReverseTranslateByte(f.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	t.TxHash = newTxHash
+
+	if f.TSPreImage != nil {
+		newTSPreImage, err := ReverseTranslateTSPreImage(f.TSPreImage)
+		if err != nil {
+			return nil, err
+		}
+		t.TSPreImage = newTSPreImage
+	}
+
+	return t, nil
+}
+
+func ReverseTranslateTSPreImage(f *from.TSPreImage) (*to.TSPreImage, error) {
+	t := &to.TSPreImage{}
+	t.ChainID = f.ChainID
+
+	newAssetID, err := ReverseTranslateByte(f.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	t.AssetID = newAssetID
+
+	if f.Owner != "" {
+		ownerBytes, err := ReverseTranslateByte(f.Owner)
+		if err != nil {
+			return nil, err
+		}
+		newOwner := &to.ValueStoreOwner{}
+		err = newOwner.UnmarshalBinary(ownerBytes)
+		if err != nil {
+			return nil, err
+		}
+		t.Owner = newOwner
+	}
+
+	t.TXOutIdx = f.TXOutIdx
+
+	t.Amount = &uint256.Uint256{}
+	err = t.Amount.UnmarshalString(f.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Fee) == 0 {
+		f.Fee = "0"
+	}
+	t.Fee = &uint256.Uint256{}
+	err = t.Fee.UnmarshalString(f.Fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
 func ReverseTranslateTXInLinker(f *from.TXInLinker) (*to.TXInLinker, error) {
 	t := &to.TXInLinker{}
 	if f.TXInPreImage != nil {
@@ -135,6 +255,13 @@ func ReverseTranslateTx(f *from.Tx) (*to.Tx, error) {
 		return nil, err
 	}
 
+	if len(f.Memo) > 0 {
+		t.Memo, err = ReverseTranslateByte(f.Memo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return t, nil
 }
 
@@ -163,6 +290,28 @@ func ReverseTranslateTXOut(f *from.TXOut) (*to.TXOut, error) {
 		if err != nil {
 			return nil, err
 		}
+	case *from.TXOut_AtomicSwap:
+		ff := f.GetAtomicSwap()
+		obj, err := ReverseTranslateAtomicSwap(ff)
+		if err != nil {
+			return nil, err
+		}
+
+		err = t.NewAtomicSwap(obj)
+		if err != nil {
+			return nil, err
+		}
+	case *from.TXOut_TokenStore:
+		ff := f.GetTokenStore()
+		obj, err := ReverseTranslateTokenStore(ff)
+		if err != nil {
+			return nil, err
+		}
+
+		err = t.NewTokenStore(obj)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.New("invalid")
 	}
@@ -85,7 +85,6 @@ func (ps *activePeerStore) del(c interfaces.NodeAddr) {
 	}
 }
 
-//nolint:unused,deadcode
 func (ps *activePeerStore) get(c interfaces.NodeAddr) (interfaces.P2PClient, bool) {
 	ps.RLock()
 	defer ps.RUnlock()
@@ -0,0 +1,342 @@
+package peering
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/interfaces"
+)
+
+// trafficClass groups the P2P methods that share a rate limit budget.
+// Consensus gossip has a budget of its own so that votes are never starved
+// by transaction gossip or sync requests of the same peer.
+type trafficClass int
+
+const (
+	consensusTraffic trafficClass = iota
+	txTraffic
+	syncTraffic
+)
+
+// methodClass returns the traffic class of a full grpc method name.
+func methodClass(method string) trafficClass {
+	switch path.Base(method) {
+	case "GossipProposal", "GossipPreVote", "GossipPreVoteNil", "GossipPreCommit",
+		"GossipPreCommitNil", "GossipNextRound", "GossipNextHeight", "GossipBlockHeader",
+		"GossipConsensusBatch":
+		return consensusTraffic
+	case "GossipTransaction", "GossipTransactionBatch", "GossipTxInventory":
+		return txTraffic
+	default:
+		return syncTraffic
+	}
+}
+
+// tokenBucket is a token bucket that may be overdrawn. Messages are admitted
+// while the bucket holds tokens and their cost is charged once known, so a
+// large response delays the requests that follow it.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a bucket refilling at rate tokens per second.
+// A nil bucket is returned for a rate of zero, which never limits.
+func newTokenBucket(rate int, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	burst := float64(rate) * constants.RateLimitBurstWindow.Seconds()
+	return &tokenBucket{
+		rate:   float64(rate),
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	tb.tokens += elapsed * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+}
+
+func (tb *tokenBucket) allow(now time.Time) bool {
+	if tb == nil {
+		return true
+	}
+	tb.refill(now)
+	return tb.tokens > 0
+}
+
+func (tb *tokenBucket) charge(now time.Time, n int) {
+	if tb == nil {
+		return
+	}
+	tb.refill(now)
+	tb.tokens -= float64(n)
+}
+
+// budget is the per peer rate limit of a traffic class.
+type budget struct {
+	msgRate  int
+	byteRate int
+}
+
+// classLimiter enforces a budget for a single peer.
+type classLimiter struct {
+	msgs  *tokenBucket
+	bytes *tokenBucket
+}
+
+func (cl *classLimiter) allow(now time.Time) bool {
+	return cl.msgs.allow(now) && cl.bytes.allow(now)
+}
+
+// TrafficCounter counts the messages and bytes exchanged for a single P2P
+// method. Inbound counts requests served for the peer as well as responses to
+// our own requests, outbound counts the opposite direction.
+type TrafficCounter struct {
+	MsgsIn    uint64
+	MsgsOut   uint64
+	BytesIn   uint64
+	BytesOut  uint64
+	Throttled uint64
+}
+
+type peerTraffic struct {
+	methods  map[string]*TrafficCounter
+	limiters map[trafficClass]*classLimiter
+	// last is the time of the last message exchanged with the peer
+	last time.Time
+}
+
+func (pt *peerTraffic) counter(method string) *TrafficCounter {
+	c, ok := pt.methods[method]
+	if !ok {
+		c = &TrafficCounter{}
+		pt.methods[method] = c
+	}
+	return c
+}
+
+// bandwidthManager tracks the traffic of every peer and rate limits the
+// requests peers make against the local node. The state is keyed by the
+// identity of the peer and is kept across connections until the peer has been
+// idle for constants.BandwidthStateTTL. A rate limit of zero means unlimited.
+type bandwidthManager struct {
+	sync.Mutex
+	budgets  map[trafficClass]budget
+	peers    map[string]*peerTraffic
+	bytesIn  uint64
+	bytesOut uint64
+	now      func() time.Time
+}
+
+func newBandwidthManager(cfg config.TransportConfig) *bandwidthManager {
+	return &bandwidthManager{
+		budgets: map[trafficClass]budget{
+			consensusTraffic: {cfg.ConsensusMsgRateLimit, cfg.ConsensusByteRateLimit},
+			txTraffic:        {cfg.TxGossipMsgRateLimit, cfg.TxGossipByteRateLimit},
+			syncTraffic:      {cfg.SyncMsgRateLimit, cfg.SyncByteRateLimit},
+		},
+		peers: make(map[string]*peerTraffic),
+		now:   time.Now,
+	}
+}
+
+// peer returns the state of a peer and marks it as active at now.
+func (bm *bandwidthManager) peer(identity string, now time.Time) *peerTraffic {
+	pt, ok := bm.peers[identity]
+	if !ok {
+		bm.expire(now)
+		pt = &peerTraffic{
+			methods:  make(map[string]*TrafficCounter),
+			limiters: make(map[trafficClass]*classLimiter),
+		}
+		for class, b := range bm.budgets {
+			pt.limiters[class] = &classLimiter{
+				msgs:  newTokenBucket(b.msgRate, now),
+				bytes: newTokenBucket(b.byteRate, now),
+			}
+		}
+		bm.peers[identity] = pt
+	}
+	pt.last = now
+	return pt
+}
+
+// expire drops the state of the peers that have been idle for longer than
+// constants.BandwidthStateTTL. It is run whenever a new peer is tracked, so
+// the state is bounded by the peers seen within the TTL.
+func (bm *bandwidthManager) expire(now time.Time) {
+	for identity, pt := range bm.peers {
+		if now.Sub(pt.last) > constants.BandwidthStateTTL {
+			delete(bm.peers, identity)
+		}
+	}
+}
+
+// admit records an inbound request of a peer and returns false if the peer
+// has exhausted the budget of the method's traffic class.
+func (bm *bandwidthManager) admit(identity, method string, size int) bool {
+	bm.Lock()
+	defer bm.Unlock()
+	now := bm.now()
+	pt := bm.peer(identity, now)
+	c := pt.counter(method)
+	c.MsgsIn++
+	c.BytesIn += uint64(size)
+	bm.bytesIn += uint64(size)
+	limiter := pt.limiters[methodClass(method)]
+	if !limiter.allow(now) {
+		c.Throttled++
+		return false
+	}
+	limiter.msgs.charge(now, 1)
+	limiter.bytes.charge(now, size)
+	return true
+}
+
+// chargeEntries charges the entries of an admitted gossip batch beyond the
+// first against the message budget of the peer, so that a batch costs as much
+// as the messages it carries. The bucket may be overdrawn, in which case the
+// requests that follow the batch are throttled.
+func (bm *bandwidthManager) chargeEntries(identity string, class trafficClass, entries int) {
+	if entries <= 1 {
+		return
+	}
+	bm.Lock()
+	defer bm.Unlock()
+	now := bm.now()
+	bm.peer(identity, now).limiters[class].msgs.charge(now, entries-1)
+}
+
+// served records the response to an admitted request of a peer. The
+// response is charged against the budget of the peer since it is the
+// response that consumes the uplink of the local node.
+func (bm *bandwidthManager) served(identity, method string, size int) {
+	bm.Lock()
+	defer bm.Unlock()
+	now := bm.now()
+	pt := bm.peer(identity, now)
+	c := pt.counter(method)
+	c.MsgsOut++
+	c.BytesOut += uint64(size)
+	bm.bytesOut += uint64(size)
+	pt.limiters[methodClass(method)].bytes.charge(now, size)
+}
+
+// record counts a request made by the local node against a peer and the
+// response received for it. Outbound traffic is never rate limited.
+func (bm *bandwidthManager) record(identity, method string, reqSize, respSize int) {
+	bm.Lock()
+	defer bm.Unlock()
+	c := bm.peer(identity, bm.now()).counter(method)
+	c.MsgsOut++
+	c.BytesOut += uint64(reqSize)
+	bm.bytesOut += uint64(reqSize)
+	if respSize > 0 {
+		c.MsgsIn++
+		c.BytesIn += uint64(respSize)
+		bm.bytesIn += uint64(respSize)
+	}
+}
+
+// totals returns the message bytes received and sent since startup.
+func (bm *bandwidthManager) totals() (uint64, uint64) {
+	bm.Lock()
+	defer bm.Unlock()
+	return bm.bytesIn, bm.bytesOut
+}
+
+// methods returns a copy of the per method counters of a peer.
+func (bm *bandwidthManager) methods(identity string) map[string]TrafficCounter {
+	bm.Lock()
+	defer bm.Unlock()
+	out := make(map[string]TrafficCounter)
+	pt, ok := bm.peers[identity]
+	if !ok {
+		return out
+	}
+	for method, c := range pt.methods {
+		out[path.Base(method)] = *c
+	}
+	return out
+}
+
+// unaryServerInterceptor accounts and rate limits the requests made by peers
+// against the P2P server.
+func (bm *bandwidthManager) unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	identity := peerIdentity(ctx)
+	if !bm.admit(identity, info.FullMethod, messageSize(req)) {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", path.Base(info.FullMethod))
+	}
+	resp, err := handler(ctx, req)
+	if err == nil {
+		bm.served(identity, info.FullMethod, messageSize(resp))
+	}
+	return resp, err
+}
+
+// unaryClientInterceptor accounts the requests made by the local node against
+// the peer with the given identity.
+func (bm *bandwidthManager) unaryClientInterceptor(identity string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		respSize := 0
+		if err == nil {
+			respSize = messageSize(reply)
+		}
+		bm.record(identity, method, messageSize(req), respSize)
+		return err
+	}
+}
+
+func peerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	nodeAddr, ok := p.Addr.(interfaces.NodeAddr)
+	if !ok {
+		return p.Addr.String()
+	}
+	return nodeAddr.Identity()
+}
+
+// peerAddr returns the address of the peer that made a request.
+func peerAddr(ctx context.Context) (interfaces.NodeAddr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	nodeAddr, ok := p.Addr.(interfaces.NodeAddr)
+	return nodeAddr, ok
+}
+
+func messageSize(msg interface{}) int {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return 0
+	}
+	return proto.Size(m)
+}
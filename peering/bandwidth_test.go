@@ -0,0 +1,109 @@
+package peering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+)
+
+func newTestBandwidthManager(cfg config.TransportConfig) (*bandwidthManager, *time.Time) {
+	now := time.Unix(1000, 0)
+	bm := newBandwidthManager(cfg)
+	bm.now = func() time.Time { return now }
+	return bm, &now
+}
+
+func TestMethodClass(t *testing.T) {
+	assert.Equal(t, consensusTraffic, methodClass("/proto.P2P/GossipPreVote"))
+	assert.Equal(t, consensusTraffic, methodClass("/proto.P2P/GossipBlockHeader"))
+	assert.Equal(t, txTraffic, methodClass("/proto.P2P/GossipTransaction"))
+	assert.Equal(t, txTraffic, methodClass("/proto.P2P/GossipTransactionBatch"))
+	assert.Equal(t, consensusTraffic, methodClass("/proto.P2P/GossipConsensusBatch"))
+	assert.Equal(t, syncTraffic, methodClass("/proto.P2P/GetSnapShotNode"))
+	assert.Equal(t, syncTraffic, methodClass("/proto.P2P/GetPeers"))
+}
+
+func TestBandwidthManagerUnlimited(t *testing.T) {
+	bm, _ := newTestBandwidthManager(config.TransportConfig{})
+	for i := 0; i < 1000; i++ {
+		assert.True(t, bm.admit("peer", "/proto.P2P/GetSnapShotNode", 100))
+		bm.served("peer", "/proto.P2P/GetSnapShotNode", 1000)
+	}
+	methods := bm.methods("peer")
+	assert.Equal(t, TrafficCounter{MsgsIn: 1000, MsgsOut: 1000, BytesIn: 100000, BytesOut: 1000000}, methods["GetSnapShotNode"])
+	in, out := bm.totals()
+	assert.Equal(t, uint64(100000), in)
+	assert.Equal(t, uint64(1000000), out)
+}
+
+func TestBandwidthManagerMsgRateLimit(t *testing.T) {
+	bm, now := newTestBandwidthManager(config.TransportConfig{SyncMsgRateLimit: 2})
+	burst := int(2 * constants.RateLimitBurstWindow.Seconds())
+	for i := 0; i < burst; i++ {
+		assert.True(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	}
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	// other peers have a budget of their own
+	assert.True(t, bm.admit("other", "/proto.P2P/GetMinedTxs", 10))
+	// consensus gossip is not limited by the sync budget
+	assert.True(t, bm.admit("peer", "/proto.P2P/GossipPreVote", 10))
+
+	*now = now.Add(time.Second)
+	assert.True(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	assert.True(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	assert.Equal(t, uint64(2), bm.methods("peer")["GetMinedTxs"].Throttled)
+}
+
+func TestBandwidthManagerChargeEntries(t *testing.T) {
+	bm, now := newTestBandwidthManager(config.TransportConfig{TxGossipMsgRateLimit: 2})
+	burst := int(2 * constants.RateLimitBurstWindow.Seconds())
+	assert.True(t, bm.admit("peer", "/proto.P2P/GossipTransactionBatch", 10))
+	// a batch of twice the burst overdraws the bucket
+	bm.chargeEntries("peer", txTraffic, 2*burst)
+	assert.False(t, bm.admit("peer", "/proto.P2P/GossipTransaction", 10))
+	*now = now.Add(constants.RateLimitBurstWindow)
+	assert.False(t, bm.admit("peer", "/proto.P2P/GossipTransaction", 10))
+	*now = now.Add(constants.RateLimitBurstWindow + time.Second)
+	assert.True(t, bm.admit("peer", "/proto.P2P/GossipTransaction", 10))
+}
+
+func TestBandwidthManagerByteRateLimit(t *testing.T) {
+	bm, now := newTestBandwidthManager(config.TransportConfig{SyncByteRateLimit: 1000})
+	assert.True(t, bm.admit("peer", "/proto.P2P/GetSnapShotNode", 10))
+	// a large response overdraws the bucket
+	bm.served("peer", "/proto.P2P/GetSnapShotNode", 10000)
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetSnapShotNode", 10))
+
+	*now = now.Add(5 * time.Second)
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetSnapShotNode", 10))
+	*now = now.Add(6 * time.Second)
+	assert.True(t, bm.admit("peer", "/proto.P2P/GetSnapShotNode", 10))
+}
+
+func TestBandwidthManagerStateExpiry(t *testing.T) {
+	bm, now := newTestBandwidthManager(config.TransportConfig{SyncMsgRateLimit: 1})
+	burst := int(constants.RateLimitBurstWindow.Seconds())
+	for i := 0; i < burst; i++ {
+		assert.True(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	}
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+
+	// the state outlives the connection, so reconnecting does not reset the
+	// budget; tracking a new peer does not drop a peer which is not idle
+	assert.True(t, bm.admit("other", "/proto.P2P/GetMinedTxs", 10))
+	assert.False(t, bm.admit("peer", "/proto.P2P/GetMinedTxs", 10))
+	assert.Equal(t, uint64(2), bm.methods("peer")["GetMinedTxs"].Throttled)
+
+	// the state of a peer idle for longer than the TTL is dropped once a new
+	// peer is tracked
+	*now = now.Add(constants.BandwidthStateTTL + time.Second)
+	assert.True(t, bm.admit("third", "/proto.P2P/GetMinedTxs", 10))
+	assert.Empty(t, bm.methods("peer"))
+	assert.Empty(t, bm.methods("other"))
+	assert.NotEmpty(t, bm.methods("third"))
+}
@@ -18,6 +18,7 @@ import (
 // be bound to the *grpc.ClientConn.
 type clientHandler struct {
 	closeChan chan struct{}
+	bandwidth *bandwidthManager
 }
 
 // Close will block further outbound dialing.
@@ -49,15 +50,21 @@ func (rpcch *clientHandler) HandleConnection(p2pconn interfaces.P2PConn) (*grpc.
 			return nil, errors.New("connection is nil")
 		}
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-	conn, err := grpc.DialContext(ctx,
-		p2pconn.RemoteAddr().String(), // THIS WILL NEVER BE DIALED
+	opts := []grpc.DialOption{
 		grpc.WithContextDialer(contextDialer),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithBlock(),
 		grpc.WithDisableRetry(),
 		grpc.WithDisableHealthCheck(),
+	}
+	if rpcch.bandwidth != nil {
+		opts = append(opts, grpc.WithUnaryInterceptor(rpcch.bandwidth.unaryClientInterceptor(p2pconn.NodeAddr().Identity())))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx,
+		p2pconn.RemoteAddr().String(), // THIS WILL NEVER BE DIALED
+		opts...,
 	)
 	defer ctx.Done()
 	if err != nil {
@@ -76,7 +83,8 @@ func (rpcch *clientHandler) HandleConnection(p2pconn interfaces.P2PConn) (*grpc.
 	return conn, nil
 }
 
-// NewClientHandler creates a ClientHandler.
-func newClientHandler() *clientHandler {
-	return &clientHandler{}
+// NewClientHandler creates a ClientHandler. If bandwidth is not nil, the
+// traffic of all bound clients is accounted against it.
+func newClientHandler(bandwidth *bandwidthManager) *clientHandler {
+	return &clientHandler{bandwidth: bandwidth}
 }
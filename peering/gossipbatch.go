@@ -0,0 +1,304 @@
+package peering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/utils"
+)
+
+var (
+	ErrGossipBatchTooLarge     = errors.New("gossip batch exceeds the maximum size")
+	ErrUnknownCompression      = errors.New("unknown gossip batch compression")
+	ErrUnexpectedGossipKind    = errors.New("unexpected kind of object in gossip batch")
+	ErrGossipBatchEntryInvalid = errors.New("gossip batch entry is empty")
+)
+
+// hashRing is a bounded set of hashes that forgets the oldest hash once it
+// is full.
+type hashRing struct {
+	known map[string]struct{}
+	order []string
+	next  int
+}
+
+func (hr *hashRing) add(hash string) {
+	if _, ok := hr.known[hash]; ok {
+		return
+	}
+	if len(hr.order) < constants.GossipFilterSize {
+		hr.order = append(hr.order, hash)
+	} else {
+		delete(hr.known, hr.order[hr.next])
+		hr.order[hr.next] = hash
+		hr.next = (hr.next + 1) % len(hr.order)
+	}
+	hr.known[hash] = struct{}{}
+}
+
+// gossipFilter remembers the transactions recently exchanged with each peer,
+// both the ones the peer gossiped to us and the ones we delivered to it, so
+// that a transaction is not pushed to a peer that already has it.
+type gossipFilter struct {
+	sync.Mutex
+	peers  map[string]*hashRing
+	hasher func([]byte) ([]byte, error)
+}
+
+func newGossipFilter() *gossipFilter {
+	return &gossipFilter{peers: make(map[string]*hashRing)}
+}
+
+// setHasher sets the function that computes the hash of a transaction. The
+// hash of the raw transaction bytes is used until a hasher is set.
+func (gf *gossipFilter) setHasher(hasher func([]byte) ([]byte, error)) {
+	gf.Lock()
+	defer gf.Unlock()
+	gf.hasher = hasher
+}
+
+// txKey returns the hash under which a transaction is remembered.
+func (gf *gossipFilter) txKey(tx []byte) []byte {
+	gf.Lock()
+	hasher := gf.hasher
+	gf.Unlock()
+	if hasher != nil {
+		if hash, err := hasher(tx); err == nil {
+			return hash
+		}
+	}
+	return crypto.Hasher(tx)
+}
+
+// keyTransaction sets the filter key of a gossiped transaction, so that it is
+// computed once and not by the bus of every peer the transaction is sent to.
+// Other gossip is left untouched.
+func (gf *gossipFilter) keyTransaction(obj interface{}) {
+	if msg, ok := obj.(*GossipTransactionMessage); ok && msg.key == nil {
+		msg.key = gf.txKey(msg.req.Transaction)
+	}
+}
+
+// messageKey returns the filter key of a gossiped transaction.
+func (gf *gossipFilter) messageKey(msg *GossipTransactionMessage) []byte {
+	if msg.key != nil {
+		return msg.key
+	}
+	return gf.txKey(msg.req.Transaction)
+}
+
+// add records that the peer with the given identity knows the hash.
+func (gf *gossipFilter) add(identity string, hash []byte) {
+	gf.Lock()
+	defer gf.Unlock()
+	hr, ok := gf.peers[identity]
+	if !ok {
+		hr = &hashRing{known: make(map[string]struct{})}
+		gf.peers[identity] = hr
+	}
+	hr.add(string(hash))
+}
+
+// has returns true if the peer with the given identity knows the hash.
+func (gf *gossipFilter) has(identity string, hash []byte) bool {
+	gf.Lock()
+	defer gf.Unlock()
+	hr, ok := gf.peers[identity]
+	if !ok {
+		return false
+	}
+	_, ok = hr.known[string(hash)]
+	return ok
+}
+
+// remove drops the state of a disconnected peer.
+func (gf *gossipFilter) remove(identity string) {
+	gf.Lock()
+	defer gf.Unlock()
+	delete(gf.peers, identity)
+}
+
+// unaryServerInterceptor records the transactions announced by peers that
+// gossip them individually.
+func (gf *gossipFilter) unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if msg, ok := req.(*pb.GossipTransactionMessage); ok && len(msg.Transaction) > 0 {
+		gf.add(peerIdentity(ctx), gf.txKey(msg.Transaction))
+	}
+	return handler(ctx, req)
+}
+
+// gossipBatch collects the gossip queued for a single peer.
+type gossipBatch struct {
+	entries  []*pb.GossipBatchEntry
+	size     int
+	txHashes [][]byte
+}
+
+func (gb *gossipBatch) add(entry *pb.GossipBatchEntry) {
+	gb.entries = append(gb.entries, entry)
+	gb.size += len(entry.Object)
+}
+
+func (gb *gossipBatch) full() bool {
+	return len(gb.entries) >= constants.GossipBatchMaxEntries || gb.size >= constants.GossipBatchMaxBytes
+}
+
+// batchSender is the signature shared by the batched gossip RPCs.
+type batchSender func(ctx context.Context, in *pb.GossipBatchMessage, opts ...grpc.CallOption) (*pb.GossipBatchAck, error)
+
+// gossipEntry converts a gossip message queued on the bus into a batch
+// entry. Messages that are not gossip are rejected.
+func gossipEntry(obj interface{}) (*pb.GossipBatchEntry, bool) {
+	switch req := obj.(type) {
+	case *GossipTransactionMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_TRANSACTION, Object: req.req.Transaction}, true
+	case *GossipProposalMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_PROPOSAL, Object: req.req.Proposal}, true
+	case *GossipPreVoteMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_PREVOTE, Object: req.req.PreVote}, true
+	case *GossipPreVoteNilMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_PREVOTENIL, Object: req.req.PreVoteNil}, true
+	case *GossipPreCommitMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_PRECOMMIT, Object: req.req.PreCommit}, true
+	case *GossipPreCommitNilMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_PRECOMMITNIL, Object: req.req.PreCommitNil}, true
+	case *GossipNextRoundMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_NEXTROUND, Object: req.req.NextRound}, true
+	case *GossipNextHeightMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_NEXTHEIGHT, Object: req.req.NextHeight}, true
+	case *GossipBlockHeaderMessage:
+		return &pb.GossipBatchEntry{Kind: pb.GossipKind_BLOCKHEADER, Object: req.req.BlockHeader}, true
+	default:
+		return nil, false
+	}
+}
+
+// encodeGossipBatch serializes the entries of a batch. Batches larger than
+// GossipBatchCompressThreshold are compressed with snappy.
+func encodeGossipBatch(entries []*pb.GossipBatchEntry) (*pb.GossipBatchMessage, error) {
+	b, err := proto.Marshal(&pb.GossipBatch{Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < constants.GossipBatchCompressThreshold {
+		return &pb.GossipBatchMessage{Compression: pb.GossipCompression_NONE, Batch: b}, nil
+	}
+	return &pb.GossipBatchMessage{Compression: pb.GossipCompression_SNAPPY, Batch: snappy.Encode(nil, b)}, nil
+}
+
+// decodeGossipBatch returns the entries of a received batch.
+func decodeGossipBatch(msg *pb.GossipBatchMessage) ([]*pb.GossipBatchEntry, error) {
+	var b []byte
+	switch msg.Compression {
+	case pb.GossipCompression_NONE:
+		b = msg.Batch
+	case pb.GossipCompression_SNAPPY:
+		n, err := snappy.DecodedLen(msg.Batch)
+		if err != nil {
+			return nil, err
+		}
+		if n > constants.GossipBatchMaxDecodedBytes {
+			return nil, fmt.Errorf("%w: %d bytes", ErrGossipBatchTooLarge, n)
+		}
+		b, err = snappy.Decode(nil, msg.Batch)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCompression, msg.Compression)
+	}
+	batch := &pb.GossipBatch{}
+	if err := proto.Unmarshal(b, batch); err != nil {
+		return nil, err
+	}
+	if len(batch.Entries) > constants.GossipBatchMaxEntries {
+		return nil, fmt.Errorf("%w: %d entries", ErrGossipBatchTooLarge, len(batch.Entries))
+	}
+	for _, entry := range batch.Entries {
+		if len(entry.Object) == 0 {
+			return nil, ErrGossipBatchEntryInvalid
+		}
+	}
+	return batch.Entries, nil
+}
+
+// HandleP2PGossipTransactionBatch unpacks a batch of transactions gossiped by
+// a peer and passes each transaction to the gossip handlers.
+func (ps *PeerManager) HandleP2PGossipTransactionBatch(ctx context.Context, msg *pb.GossipBatchMessage) (*pb.GossipBatchAck, error) {
+	entries, err := decodeGossipBatch(msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Kind != pb.GossipKind_TRANSACTION {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedGossipKind, entry.Kind)
+		}
+	}
+	identity := peerIdentity(ctx)
+	ps.bandwidth.chargeEntries(identity, txTraffic, len(entries))
+	for _, entry := range entries {
+		ps.gossipFilter.add(identity, ps.gossipFilter.txKey(entry.Object))
+		ps.forwardGossip(ctx, entry)
+	}
+	return &pb.GossipBatchAck{}, nil
+}
+
+// HandleP2PGossipConsensusBatch unpacks a batch of consensus messages
+// gossiped by a peer and passes each message to the gossip handlers.
+func (ps *PeerManager) HandleP2PGossipConsensusBatch(ctx context.Context, msg *pb.GossipBatchMessage) (*pb.GossipBatchAck, error) {
+	entries, err := decodeGossipBatch(msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Kind == pb.GossipKind_TRANSACTION {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedGossipKind, entry.Kind)
+		}
+	}
+	ps.bandwidth.chargeEntries(peerIdentity(ctx), consensusTraffic, len(entries))
+	for _, entry := range entries {
+		ps.forwardGossip(ctx, entry)
+	}
+	return &pb.GossipBatchAck{}, nil
+}
+
+// forwardGossip passes a single batch entry to the P2P server as if it had
+// been gossiped individually. A bad entry does not affect the rest of the
+// batch.
+func (ps *PeerManager) forwardGossip(ctx context.Context, entry *pb.GossipBatchEntry) {
+	var err error
+	switch entry.Kind {
+	case pb.GossipKind_TRANSACTION:
+		_, err = ps.p2pServer.GossipTransaction(ctx, &pb.GossipTransactionMessage{Transaction: entry.Object})
+	case pb.GossipKind_PROPOSAL:
+		_, err = ps.p2pServer.GossipProposal(ctx, &pb.GossipProposalMessage{Proposal: entry.Object})
+	case pb.GossipKind_PREVOTE:
+		_, err = ps.p2pServer.GossipPreVote(ctx, &pb.GossipPreVoteMessage{PreVote: entry.Object})
+	case pb.GossipKind_PREVOTENIL:
+		_, err = ps.p2pServer.GossipPreVoteNil(ctx, &pb.GossipPreVoteNilMessage{PreVoteNil: entry.Object})
+	case pb.GossipKind_PRECOMMIT:
+		_, err = ps.p2pServer.GossipPreCommit(ctx, &pb.GossipPreCommitMessage{PreCommit: entry.Object})
+	case pb.GossipKind_PRECOMMITNIL:
+		_, err = ps.p2pServer.GossipPreCommitNil(ctx, &pb.GossipPreCommitNilMessage{PreCommitNil: entry.Object})
+	case pb.GossipKind_NEXTROUND:
+		_, err = ps.p2pServer.GossipNextRound(ctx, &pb.GossipNextRoundMessage{NextRound: entry.Object})
+	case pb.GossipKind_NEXTHEIGHT:
+		_, err = ps.p2pServer.GossipNextHeight(ctx, &pb.GossipNextHeightMessage{NextHeight: entry.Object})
+	case pb.GossipKind_BLOCKHEADER:
+		_, err = ps.p2pServer.GossipBlockHeader(ctx, &pb.GossipBlockHeaderMessage{BlockHeader: entry.Object})
+	default:
+		err = fmt.Errorf("%w: %v", ErrUnexpectedGossipKind, entry.Kind)
+	}
+	if err != nil {
+		utils.DebugTrace(ps.logger, err)
+	}
+}
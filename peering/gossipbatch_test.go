@@ -0,0 +1,181 @@
+package peering
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/crypto"
+	"github.com/alicenet/alicenet/interfaces"
+	pb "github.com/alicenet/alicenet/proto"
+)
+
+func TestGossipBatchRoundTrip(t *testing.T) {
+	small := []*pb.GossipBatchEntry{
+		{Kind: pb.GossipKind_PREVOTE, Object: []byte("prevote")},
+		{Kind: pb.GossipKind_BLOCKHEADER, Object: []byte("header")},
+	}
+	msg, err := encodeGossipBatch(small)
+	assert.Nil(t, err)
+	assert.Equal(t, pb.GossipCompression_NONE, msg.Compression)
+	entries, err := decodeGossipBatch(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.True(t, proto.Equal(small[1], entries[1]))
+
+	large := []*pb.GossipBatchEntry{}
+	for i := 0; i < 64; i++ {
+		large = append(large, &pb.GossipBatchEntry{Kind: pb.GossipKind_TRANSACTION, Object: bytes.Repeat([]byte{byte(i)}, 100)})
+	}
+	msg, err = encodeGossipBatch(large)
+	assert.Nil(t, err)
+	assert.Equal(t, pb.GossipCompression_SNAPPY, msg.Compression)
+	assert.Less(t, len(msg.Batch), 64*100)
+	entries, err = decodeGossipBatch(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, len(large), len(entries))
+	assert.True(t, proto.Equal(large[63], entries[63]))
+}
+
+func TestGossipBatchDecodeLimits(t *testing.T) {
+	huge := snappy.Encode(nil, make([]byte, constants.GossipBatchMaxDecodedBytes+1))
+	_, err := decodeGossipBatch(&pb.GossipBatchMessage{Compression: pb.GossipCompression_SNAPPY, Batch: huge})
+	assert.True(t, errors.Is(err, ErrGossipBatchTooLarge))
+
+	_, err = decodeGossipBatch(&pb.GossipBatchMessage{Compression: 7})
+	assert.True(t, errors.Is(err, ErrUnknownCompression))
+
+	msg, err := encodeGossipBatch([]*pb.GossipBatchEntry{{Kind: pb.GossipKind_TRANSACTION}})
+	assert.Nil(t, err)
+	_, err = decodeGossipBatch(msg)
+	assert.True(t, errors.Is(err, ErrGossipBatchEntryInvalid))
+}
+
+func TestGossipFilter(t *testing.T) {
+	gf := newGossipFilter()
+	gf.add("peer", []byte("a"))
+	assert.True(t, gf.has("peer", []byte("a")))
+	assert.False(t, gf.has("peer", []byte("b")))
+	assert.False(t, gf.has("other", []byte("a")))
+
+	// the oldest hash is forgotten once the filter is full
+	for i := 0; i < constants.GossipFilterSize; i++ {
+		gf.add("peer", crypto.Hasher([]byte{byte(i), byte(i >> 8)}))
+	}
+	assert.False(t, gf.has("peer", []byte("a")))
+	assert.True(t, gf.has("peer", crypto.Hasher([]byte{0, 0})))
+
+	gf.remove("peer")
+	assert.False(t, gf.has("peer", crypto.Hasher([]byte{0, 0})))
+}
+
+func TestCollectBatchSkipsKnownTransactions(t *testing.T) {
+	p2p := &P2PBus{identity: "peer", filter: newGossipFilter()}
+	known := []byte("known")
+	p2p.filter.add("peer", crypto.Hasher(known))
+
+	source := make(chan interface{}, 4)
+	source <- &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: known}}
+	source <- &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: []byte("new")}}
+	first := &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: []byte("first")}}
+
+	batch := p2p.collectBatch(first, source, 0)
+	assert.Equal(t, 2, len(batch.entries))
+	assert.Equal(t, [][]byte{crypto.Hasher([]byte("first")), crypto.Hasher([]byte("new"))}, batch.txHashes)
+	assert.Equal(t, 0, len(source))
+}
+
+func TestGossipBatchChargedPerEntry(t *testing.T) {
+	bm, _ := newTestBandwidthManager(config.TransportConfig{TxGossipMsgRateLimit: 2})
+	ps := &PeerManager{
+		logger:       logrus.New(),
+		p2pServer:    pb.UnimplementedP2PServer{},
+		bandwidth:    bm,
+		gossipFilter: newGossipFilter(),
+	}
+	burst := int(2 * constants.RateLimitBurstWindow.Seconds())
+	entries := make([]*pb.GossipBatchEntry, 0, burst)
+	for i := 0; i < burst; i++ {
+		entries = append(entries, &pb.GossipBatchEntry{Kind: pb.GossipKind_TRANSACTION, Object: []byte{byte(i + 1)}})
+	}
+	msg, err := encodeGossipBatch(entries)
+	assert.Nil(t, err)
+
+	// the batch is admitted as a single request and charged for the rest of
+	// its entries once decoded, which uses the whole budget of the peer
+	method := "/proto.P2P/GossipTransactionBatch"
+	assert.True(t, bm.admit("", method, messageSize(msg)))
+	_, err = ps.HandleP2PGossipTransactionBatch(context.Background(), msg)
+	assert.Nil(t, err)
+	assert.False(t, bm.admit("", "/proto.P2P/GossipTransaction", 10))
+	// consensus gossip is charged against a budget of its own
+	assert.True(t, bm.admit("", "/proto.P2P/GossipConsensusBatch", 10))
+}
+
+func TestGossipTransactionKeyedOnce(t *testing.T) {
+	filter := newGossipFilter()
+	calls := 0
+	filter.setHasher(func(tx []byte) ([]byte, error) {
+		calls++
+		return append([]byte("hash:"), tx...), nil
+	})
+	msg := &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: []byte("tx")}}
+	filter.keyTransaction(msg)
+	filter.keyTransaction(&GossipProposalMessage{req: &pb.GossipProposalMessage{Proposal: []byte("proposal")}})
+	assert.Equal(t, 1, calls)
+
+	// the buses of every peer reuse the key set before the fan-out
+	for _, identity := range []string{"peer1", "peer2", "peer3"} {
+		p2p := &P2PBus{identity: identity, filter: filter}
+		batch := p2p.collectBatch(msg, make(chan interface{}), 0)
+		assert.Equal(t, [][]byte{[]byte("hash:tx")}, batch.txHashes)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+// addrOnlyClient is a client of which only the address is used.
+type addrOnlyClient struct {
+	interfaces.P2PClient
+}
+
+func (addrOnlyClient) NodeAddr() interfaces.NodeAddr {
+	return nil
+}
+
+func TestGossipBatchWorkerSendsConcurrently(t *testing.T) {
+	client := addrOnlyClient{}
+	closeChan := make(chan struct{})
+	defer close(closeChan)
+	p2p := &P2PBus{client: client, identity: "peer", filter: newGossipFilter(), closeChan: closeChan, logger: logrus.New()}
+
+	started := make(chan *gossipBatch)
+	release := make(chan struct{})
+	send := func(batch *gossipBatch) {
+		started <- batch
+		<-release
+	}
+	source := make(chan interface{})
+	go p2p.gossipBatchWorker(source, 0, send, 2)
+
+	// the second batch is sent while the send of the first one is blocked
+	source <- &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: []byte("tx1")}}
+	first := <-started
+	source <- &GossipTransactionMessage{req: &pb.GossipTransactionMessage{Transaction: []byte("tx2")}}
+	select {
+	case second := <-started:
+		assert.Equal(t, [][]byte{crypto.Hasher([]byte("tx1"))}, first.txHashes)
+		assert.Equal(t, [][]byte{crypto.Hasher([]byte("tx2"))}, second.txHashes)
+	case <-time.After(time.Second):
+		t.Fatal("the second batch was not sent concurrently")
+	}
+	close(release)
+}
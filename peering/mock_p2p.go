@@ -10,6 +10,7 @@ import (
 
 	interfaces "github.com/alicenet/alicenet/interfaces"
 	proto "github.com/alicenet/alicenet/proto"
+	types "github.com/alicenet/alicenet/types"
 	gomock "github.com/golang/mock/gomock"
 	grpc "google.golang.org/grpc"
 )
@@ -65,6 +66,20 @@ func (mr *MockP2PClientMockRecorder) CloseChan() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseChan", reflect.TypeOf((*MockP2PClient)(nil).CloseChan))
 }
 
+// Features mocks base method
+func (m *MockP2PClient) Features() types.Features {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Features")
+	ret0, _ := ret[0].(types.Features)
+	return ret0
+}
+
+// Features indicates an expected call of Features
+func (mr *MockP2PClientMockRecorder) Features() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Features", reflect.TypeOf((*MockP2PClient)(nil).Features))
+}
+
 // GetBlockHeaders mocks base method
 func (m *MockP2PClient) GetBlockHeaders(arg0 context.Context, arg1 *proto.GetBlockHeadersRequest, arg2 ...grpc.CallOption) (*proto.GetBlockHeadersResponse, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +220,26 @@ func (mr *MockP2PClientMockRecorder) GossipBlockHeader(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipBlockHeader", reflect.TypeOf((*MockP2PClient)(nil).GossipBlockHeader), varargs...)
 }
 
+// GossipConsensusBatch mocks base method
+func (m *MockP2PClient) GossipConsensusBatch(arg0 context.Context, arg1 *proto.GossipBatchMessage, arg2 ...grpc.CallOption) (*proto.GossipBatchAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GossipConsensusBatch", varargs...)
+	ret0, _ := ret[0].(*proto.GossipBatchAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GossipConsensusBatch indicates an expected call of GossipConsensusBatch
+func (mr *MockP2PClientMockRecorder) GossipConsensusBatch(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipConsensusBatch", reflect.TypeOf((*MockP2PClient)(nil).GossipConsensusBatch), varargs...)
+}
+
 // GossipNextHeight mocks base method
 func (m *MockP2PClient) GossipNextHeight(arg0 context.Context, arg1 *proto.GossipNextHeightMessage, arg2 ...grpc.CallOption) (*proto.GossipNextHeightAck, error) {
 	m.ctrl.T.Helper()
@@ -365,6 +400,46 @@ func (mr *MockP2PClientMockRecorder) GossipTransaction(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipTransaction", reflect.TypeOf((*MockP2PClient)(nil).GossipTransaction), varargs...)
 }
 
+// GossipTransactionBatch mocks base method
+func (m *MockP2PClient) GossipTransactionBatch(arg0 context.Context, arg1 *proto.GossipBatchMessage, arg2 ...grpc.CallOption) (*proto.GossipBatchAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GossipTransactionBatch", varargs...)
+	ret0, _ := ret[0].(*proto.GossipBatchAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GossipTransactionBatch indicates an expected call of GossipTransactionBatch
+func (mr *MockP2PClientMockRecorder) GossipTransactionBatch(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipTransactionBatch", reflect.TypeOf((*MockP2PClient)(nil).GossipTransactionBatch), varargs...)
+}
+
+// GossipTxInventory mocks base method
+func (m *MockP2PClient) GossipTxInventory(arg0 context.Context, arg1 *proto.GossipTxInventoryMessage, arg2 ...grpc.CallOption) (*proto.GossipTxInventoryAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GossipTxInventory", varargs...)
+	ret0, _ := ret[0].(*proto.GossipTxInventoryAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GossipTxInventory indicates an expected call of GossipTxInventory
+func (mr *MockP2PClientMockRecorder) GossipTxInventory(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipTxInventory", reflect.TypeOf((*MockP2PClient)(nil).GossipTxInventory), varargs...)
+}
+
 // NodeAddr mocks base method
 func (m *MockP2PClient) NodeAddr() interfaces.NodeAddr {
 	m.ctrl.T.Helper()
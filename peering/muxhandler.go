@@ -101,11 +101,12 @@ func (rpcm *MuxHandler) gRPCclientHandler(conn interfaces.P2PMuxConn) (interface
 	return c, nil
 }
 
-// NewMuxServerHandler creates a new multiplexed grpc tunneling system for
-// P2PMuxConn objects.
-func NewMuxServerHandler(logger *logrus.Logger, addr net.Addr, service interfaces.P2PServer) *MuxHandler {
-	sh := newP2PServerHandler(logger, addr, service)
-	ch := newClientHandler()
+// newMuxServerHandler creates a new multiplexed grpc tunneling system for
+// P2PMuxConn objects. The traffic of both sides is counted per peer and method
+// in bandwidth, and the inbound gossip is deduplicated by filter.
+func newMuxServerHandler(logger *logrus.Logger, addr net.Addr, service interfaces.P2PServer, bandwidth *bandwidthManager, filter *gossipFilter) *MuxHandler {
+	sh := newP2PServerHandler(logger, addr, service, bandwidth, filter)
+	ch := newClientHandler(bandwidth)
 	return &MuxHandler{
 		ch:     ch,
 		sh:     sh,
@@ -14,6 +14,7 @@ import (
 	"github.com/alicenet/alicenet/logging"
 	"github.com/alicenet/alicenet/middleware"
 	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/types"
 	"github.com/alicenet/alicenet/utils"
 )
 
@@ -105,6 +106,9 @@ type GossipTransactionMessage struct {
 	ctx  context.Context
 	req  *pb.GossipTransactionMessage
 	opts []grpc.CallOption
+	// key is the gossip filter key of the transaction, it is computed once
+	// before the message is fanned out to the bus of every peer.
+	key []byte
 }
 
 //nolint:structcheck,unused
@@ -209,6 +213,24 @@ type GossipBlockHeaderAck struct {
 	err  error
 }
 
+type GossipTransactionBatchMessage struct {
+	ctx  context.Context
+	req  *pb.GossipBatchMessage
+	opts []grpc.CallOption
+}
+
+type GossipConsensusBatchMessage struct {
+	ctx  context.Context
+	req  *pb.GossipBatchMessage
+	opts []grpc.CallOption
+}
+
+type GossipTxInventoryMessage struct {
+	ctx  context.Context
+	req  *pb.GossipTxInventoryMessage
+	opts []grpc.CallOption
+}
+
 type GetPeersRequest struct {
 	ctx   context.Context
 	req   *pb.GetPeersRequest
@@ -222,10 +244,16 @@ type GetPeersResponse struct {
 }
 
 // NewP2PBus binds a peer to the common work sharing and broadcast channels of
-// the peer system.
-func newP2PBus(client interfaces.P2PClient, reqChan, gossipChan, gossipTxChan <-chan interface{}, closeChan <-chan struct{}, reqCount, gossipCount, gossipTxCount int, cleanup func()) *P2PBus {
+// the peer system. Peers that support batched gossip receive
+// their gossip in batches, and transactions known to the peer by filter are
+// not gossiped to it. Peers that support transaction inventories have
+// transactions announced to them unless pushTxs returns true.
+func newP2PBus(client interfaces.P2PClient, reqChan, gossipChan, gossipTxChan <-chan interface{}, closeChan <-chan struct{}, reqCount, gossipCount, gossipTxCount int, filter *gossipFilter, pushTxs func() bool, cleanup func()) *P2PBus {
 	p2p := &P2PBus{
 		client:            client,
+		identity:          client.NodeAddr().Identity(),
+		filter:            filter,
+		pushTxs:           pushTxs,
 		reqChan:           reqChan,
 		gossipChan:        gossipChan,
 		gossipTxChan:      gossipTxChan,
@@ -240,11 +268,19 @@ func newP2PBus(client interfaces.P2PClient, reqChan, gossipChan, gossipTxChan <-
 	go p2p.reqWorker()
 	p2p.numWorkers++
 	go p2p.reqWorker()
-	for i := 0; i < gossipCount; i++ {
-		go p2p.gossipWorker()
+	if client.Features().Has(types.FeatureBatchedGossip) {
+		go p2p.gossipBatchWorker(p2p.gossipChan, 0, p2p.sendConsensusBatch, gossipCount)
+	} else {
+		for i := 0; i < gossipCount; i++ {
+			go p2p.gossipWorker()
+		}
 	}
-	for i := 0; i < gossipTxCount; i++ {
-		go p2p.gossipTxWorker()
+	if client.Features().Has(types.FeatureBatchedGossip) || client.Features().Has(types.FeatureTxInventory) {
+		go p2p.gossipBatchWorker(p2p.gossipTxChan, constants.GossipBatchTxDelay, p2p.sendTxBatch, gossipTxCount)
+	} else {
+		for i := 0; i < gossipTxCount; i++ {
+			go p2p.gossipTxWorker()
+		}
 	}
 	go p2p.workerOversight()
 	go p2p.cleaner()
@@ -276,6 +312,9 @@ func (p2p *p2PBus) Feedback(amount int) {
 
 type P2PBus struct {
 	client            interfaces.P2PClient
+	identity          string
+	filter            *gossipFilter
+	pushTxs           func() bool
 	reqChan           <-chan interface{}
 	gossipChan        <-chan interface{}
 	gossipTxChan      <-chan interface{}
@@ -397,6 +436,155 @@ func (p2p *P2PBus) gossipTxWorker() {
 	}
 }
 
+// gossipBatchWorker packs the gossip queued for the peer into batches. The
+// batches are sent with send by a pool of senders, so that a slow send does
+// not hold back the batches collected after it.
+func (p2p *P2PBus) gossipBatchWorker(source <-chan interface{}, delay time.Duration, send func(*gossipBatch), senders int) {
+	p2p.logger.Debugf("Starting gossip batch worker for peer %v", p2p.client.NodeAddr())
+	batches := make(chan *gossipBatch)
+	for i := 0; i < senders; i++ {
+		go p2p.gossipBatchSender(batches, send)
+	}
+	for {
+		select {
+		case <-p2p.closeChan:
+			return
+		case msg := <-source:
+			batch := p2p.collectBatch(msg, source, delay)
+			select {
+			case <-p2p.closeChan:
+				return
+			case batches <- batch:
+			}
+		}
+	}
+}
+
+func (p2p *P2PBus) gossipBatchSender(batches <-chan *gossipBatch, send func(*gossipBatch)) {
+	for {
+		select {
+		case <-p2p.closeChan:
+			return
+		case batch := <-batches:
+			send(batch)
+		}
+	}
+}
+
+// collectBatch adds the messages queued on source to a batch until the batch
+// is full or delay has passed. With a delay of zero only the messages that
+// are already queued are collected.
+func (p2p *P2PBus) collectBatch(first interface{}, source <-chan interface{}, delay time.Duration) *gossipBatch {
+	batch := &gossipBatch{}
+	p2p.addToBatch(batch, first)
+	if delay == 0 {
+		for !batch.full() {
+			select {
+			case msg := <-source:
+				p2p.addToBatch(batch, msg)
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for !batch.full() {
+		select {
+		case <-p2p.closeChan:
+			return batch
+		case <-timer.C:
+			return batch
+		case msg := <-source:
+			p2p.addToBatch(batch, msg)
+		}
+	}
+	return batch
+}
+
+func (p2p *P2PBus) addToBatch(batch *gossipBatch, obj interface{}) {
+	entry, ok := gossipEntry(obj)
+	if !ok {
+		p2p.dispatch(obj)
+		return
+	}
+	if entry.Kind == pb.GossipKind_TRANSACTION {
+		hash := p2p.filter.messageKey(obj.(*GossipTransactionMessage))
+		if p2p.filter.has(p2p.identity, hash) {
+			return
+		}
+		batch.txHashes = append(batch.txHashes, hash)
+	}
+	batch.add(entry)
+}
+
+func (p2p *P2PBus) sendConsensusBatch(batch *gossipBatch) {
+	p2p.sendBatch(batch, p2p.client.GossipConsensusBatch)
+}
+
+// sendTxBatch announces the transactions of a batch to peers that support
+// transaction inventories, unless transactions are pushed in full. Pushed
+// transactions are batched if the peer supports it.
+func (p2p *P2PBus) sendTxBatch(batch *gossipBatch) {
+	if len(batch.entries) == 0 {
+		return
+	}
+	features := p2p.client.Features()
+	switch {
+	case features.Has(types.FeatureTxInventory) && !p2p.pushTxs():
+		p2p.sendTxInventory(batch)
+	case features.Has(types.FeatureBatchedGossip):
+		p2p.sendBatch(batch, p2p.client.GossipTransactionBatch)
+	default:
+		for i, entry := range batch.entries {
+			p2p.dispatch(&GossipTransactionMessage{ctx: context.Background(), req: &pb.GossipTransactionMessage{Transaction: entry.Object}, key: batch.txHashes[i]})
+		}
+	}
+}
+
+func (p2p *P2PBus) sendTxInventory(batch *gossipBatch) {
+	opts := []grpc.CallOption{
+		grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
+		grpc_retry.WithMax(3),
+	}
+	ctx, cf := context.WithTimeout(context.Background(), 3*constants.MsgTimeout)
+	defer cf()
+	_, err := p2p.client.GossipTxInventory(ctx, &pb.GossipTxInventoryMessage{TxHashes: batch.txHashes}, opts...)
+	if err != nil {
+		utils.DebugTrace(p2p.logger, err)
+		return
+	}
+	for _, hash := range batch.txHashes {
+		p2p.filter.add(p2p.identity, hash)
+	}
+}
+
+func (p2p *P2PBus) sendBatch(batch *gossipBatch, send batchSender) {
+	if len(batch.entries) == 0 {
+		return
+	}
+	msg, err := encodeGossipBatch(batch.entries)
+	if err != nil {
+		utils.DebugTrace(p2p.logger, err)
+		return
+	}
+	opts := []grpc.CallOption{
+		grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
+		grpc_retry.WithMax(3),
+	}
+	ctx, cf := context.WithTimeout(context.Background(), 3*constants.MsgTimeout)
+	defer cf()
+	_, err = send(ctx, msg, opts...)
+	if err != nil {
+		utils.DebugTrace(p2p.logger, err)
+		return
+	}
+	for _, hash := range batch.txHashes {
+		p2p.filter.add(p2p.identity, hash)
+	}
+}
+
 func (p2p *P2PBus) dispatch(obj interface{}) {
 	switch req := obj.(type) {
 	case *StatusRequest:
@@ -484,6 +672,10 @@ func (p2p *P2PBus) dispatch(obj interface{}) {
 			return
 		}
 	case *GossipTransactionMessage:
+		hash := p2p.filter.messageKey(req)
+		if p2p.filter.has(p2p.identity, hash) {
+			return
+		}
 		opts := []grpc.CallOption{
 			grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
 			grpc_retry.WithMax(3),
@@ -493,7 +685,9 @@ func (p2p *P2PBus) dispatch(obj interface{}) {
 		_, err := p2p.client.GossipTransaction(ctx, req.req, opts...)
 		if err != nil {
 			utils.DebugTrace(p2p.logger, err)
+			return
 		}
+		p2p.filter.add(p2p.identity, hash)
 	case *GossipProposalMessage:
 		opts := []grpc.CallOption{
 			grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
@@ -582,6 +776,39 @@ func (p2p *P2PBus) dispatch(obj interface{}) {
 		if err != nil {
 			utils.DebugTrace(p2p.logger, err)
 		}
+	case *GossipTransactionBatchMessage:
+		opts := []grpc.CallOption{
+			grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
+			grpc_retry.WithMax(3),
+		}
+		ctx, cf := context.WithTimeout(req.ctx, 3*constants.MsgTimeout)
+		defer cf()
+		_, err := p2p.client.GossipTransactionBatch(ctx, req.req, opts...)
+		if err != nil {
+			utils.DebugTrace(p2p.logger, err)
+		}
+	case *GossipConsensusBatchMessage:
+		opts := []grpc.CallOption{
+			grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
+			grpc_retry.WithMax(3),
+		}
+		ctx, cf := context.WithTimeout(req.ctx, 3*constants.MsgTimeout)
+		defer cf()
+		_, err := p2p.client.GossipConsensusBatch(ctx, req.req, opts...)
+		if err != nil {
+			utils.DebugTrace(p2p.logger, err)
+		}
+	case *GossipTxInventoryMessage:
+		opts := []grpc.CallOption{
+			grpc_retry.WithPerRetryTimeout(constants.MsgTimeout),
+			grpc_retry.WithMax(3),
+		}
+		ctx, cf := context.WithTimeout(req.ctx, 3*constants.MsgTimeout)
+		defer cf()
+		_, err := p2p.client.GossipTxInventory(ctx, req.req, opts...)
+		if err != nil {
+			utils.DebugTrace(p2p.logger, err)
+		}
 	case *GetPeersRequest:
 		ctx, cf := context.WithTimeout(req.ctx, constants.MsgTimeout)
 		defer cf()
@@ -733,7 +960,7 @@ func (p2p *P2PClient) GetPeers(ctx context.Context, in *pb.GetPeersRequest, opts
 }
 
 func (p2p *P2PClient) GossipTransaction(ctx context.Context, in *pb.GossipTransactionMessage, opts ...grpc.CallOption) (*pb.GossipTransactionAck, error) {
-	req := &GossipTransactionMessage{ctx, in, opts}
+	req := &GossipTransactionMessage{ctx: ctx, req: in, opts: opts}
 	select {
 	case p2p.gossipTxChan <- req:
 	default:
@@ -867,4 +1094,49 @@ func (p2p *P2PClient) GossipBlockHeader(ctx context.Context, in *pb.GossipBlockH
 	return &pb.GossipBlockHeaderAck{}, nil
 }
 
+func (p2p *P2PClient) GossipTransactionBatch(ctx context.Context, in *pb.GossipBatchMessage, opts ...grpc.CallOption) (*pb.GossipBatchAck, error) {
+	req := &GossipTransactionBatchMessage{ctx, in, opts}
+	select {
+	case p2p.gossipTxChan <- req:
+	default:
+		if !middleware.CanBlock(opts...) {
+			return nil, ErrWouldBlock
+		} else {
+			p2p.gossipTxChan <- req
+		}
+	}
+
+	return &pb.GossipBatchAck{}, nil
+}
+
+func (p2p *P2PClient) GossipConsensusBatch(ctx context.Context, in *pb.GossipBatchMessage, opts ...grpc.CallOption) (*pb.GossipBatchAck, error) {
+	req := &GossipConsensusBatchMessage{ctx, in, opts}
+	select {
+	case p2p.gossipChan <- req:
+	default:
+		if !middleware.CanBlock(opts...) {
+			return nil, ErrWouldBlock
+		} else {
+			p2p.gossipChan <- req
+		}
+	}
+
+	return &pb.GossipBatchAck{}, nil
+}
+
+func (p2p *P2PClient) GossipTxInventory(ctx context.Context, in *pb.GossipTxInventoryMessage, opts ...grpc.CallOption) (*pb.GossipTxInventoryAck, error) {
+	req := &GossipTxInventoryMessage{ctx, in, opts}
+	select {
+	case p2p.gossipTxChan <- req:
+	default:
+		if !middleware.CanBlock(opts...) {
+			return nil, ErrWouldBlock
+		} else {
+			p2p.gossipTxChan <- req
+		}
+	}
+
+	return &pb.GossipTxInventoryAck{}, nil
+}
+
 var ErrWouldBlock = errors.New("unable to broadcast due to blocking")
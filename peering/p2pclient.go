@@ -4,6 +4,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/alicenet/alicenet/interfaces"
+	"github.com/alicenet/alicenet/types"
 )
 
 type p2PClient struct {
@@ -24,3 +25,7 @@ func (c *p2PClient) CloseChan() <-chan struct{} {
 func (c *p2PClient) NodeAddr() interfaces.NodeAddr {
 	return c.nodeAddr
 }
+
+func (c *p2PClient) Features() types.Features {
+	return c.conn.Features()
+}
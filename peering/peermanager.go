@@ -10,6 +10,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/alicenet/alicenet/config"
 	"github.com/alicenet/alicenet/constants"
 	"github.com/alicenet/alicenet/interfaces"
 	"github.com/alicenet/alicenet/logging"
@@ -48,6 +49,12 @@ type PeerManager struct {
 	gossipChan               chan interface{}
 	gossipTxChan             chan interface{}
 	reqChan                  chan interface{}
+	peerConns                map[string]interfaces.P2PMuxConn
+	bandwidth                *bandwidthManager
+	gossipFilter             *gossipFilter
+	p2pServer                interfaces.P2PServer
+	txInventory              TxInventory
+	txPulls                  *txPulls
 	upnpMapper               *transport.UPnPMapper
 }
 
@@ -84,6 +91,8 @@ func NewPeerManager(p2pServer interfaces.P2PServer, chainID uint32, pLimMin, pLi
 			return nil, err
 		}
 	}
+	bandwidth := newBandwidthManager(config.Configuration.Transport)
+	filter := newGossipFilter()
 	// create the actual peer manager
 	pm := &PeerManager{
 		ctx:                      subCtx,
@@ -93,7 +102,7 @@ func NewPeerManager(p2pServer interfaces.P2PServer, chainID uint32, pLimMin, pLi
 		peeringCompleteThreshold: pLimMin, // config.Configuration.Transport.PeerLimitMin
 		peeringMaxThreshold:      pLimMax, // config.Configuration.Transport.PeerLimitMax
 		bootNodes:                &bootNodeList{},
-		clientHandler:            newClientHandler(),
+		clientHandler:            newClientHandler(nil),
 		active: &activePeerStore{
 			canClose:  true,
 			store:     make(map[string]interfaces.P2PClient),
@@ -109,8 +118,12 @@ func NewPeerManager(p2pServer interfaces.P2PServer, chainID uint32, pLimMin, pLi
 		},
 		mux:              &transport.P2PMux{},
 		transport:        p2ptransport,
-		p2pServerHandler: NewMuxServerHandler(logger, p2ptransport.NodeAddr(), p2pServer),
+		p2pServerHandler: newMuxServerHandler(logger, p2ptransport.NodeAddr(), p2pServer, bandwidth, filter),
 		upnpMapper:       upnpMapper,
+		bandwidth:        bandwidth,
+		gossipFilter:     filter,
+		p2pServer:        p2pServer,
+		txPulls:          &txPulls{inflight: make(map[string]struct{})},
 	}
 	pm.discServerHandler = NewP2PDiscoveryServerHandler(logger, p2ptransport.NodeAddr(), pm)
 	if fwMode { // config.Configuration.Transport.FirewallMode
@@ -132,6 +145,7 @@ func NewPeerManager(p2pServer interfaces.P2PServer, chainID uint32, pLimMin, pLi
 	pm.gossipTxChan = make(chan interface{}, ((pLimMax - pLimMin) / 2))
 	pm.gossipMap = make(map[string]chan interface{})
 	pm.gossipTxMap = make(map[string]chan interface{})
+	pm.peerConns = make(map[string]interfaces.P2PMuxConn)
 	return pm, nil
 }
 
@@ -180,6 +194,7 @@ func (ps *PeerManager) drainPeerChans(obj interface{}, chans chanList, source pe
 	for {
 		select {
 		case obj := <-source:
+			ps.gossipFilter.keyTransaction(obj)
 			go ps.sendOnPeerChans(obj, chans)
 		default:
 			return
@@ -193,6 +208,7 @@ func (ps *PeerManager) peerGossipLoop(source peerChan, cmap chanMap) {
 		case <-ps.CloseChan():
 			return
 		case obj := <-source:
+			ps.gossipFilter.keyTransaction(obj)
 			chans := ps.getPeerChans(cmap)
 			go ps.sendOnPeerChans(obj, chans)
 			ps.drainPeerChans(obj, chans, source)
@@ -341,14 +357,17 @@ func (ps *PeerManager) handleP2P(conn interfaces.P2PConn) {
 		ps.inactive.del(client.NodeAddr())
 		ps.gossipMap[key] = gossipChan
 		ps.gossipTxMap[key] = gossipTxChan
+		ps.peerConns[key] = muxconn
 	}()
 	cleanup := func() {
 		ps.Lock()
 		defer ps.Unlock()
 		delete(ps.gossipMap, key)
 		delete(ps.gossipTxMap, key)
+		delete(ps.peerConns, key)
+		ps.gossipFilter.remove(client.NodeAddr().Identity())
 	}
-	go newP2PBus(client, ps.reqChan, gossipChan, gossipTxChan, client.CloseChan(), 256, 5, 16, cleanup)
+	go newP2PBus(client, ps.reqChan, gossipChan, gossipTxChan, client.CloseChan(), 256, 5, 16, ps.gossipFilter, ps.pushTxs, cleanup)
 }
 
 // P2PClient returns a wrapper around the gossip and request bus channels for
@@ -411,9 +430,43 @@ func (ps *PeerManager) GetPeers(ctx context.Context, req *pb.GetPeersRequest) (*
 func (ps *PeerManager) Status(smap map[string]interface{}) (map[string]interface{}, error) {
 	active, inactive := ps.Counts()
 	smap["Peers"] = fmt.Sprintf("%d/%d/%d/%d", ps.peeringMaxThreshold, active, ps.peeringCompleteThreshold, inactive)
+	bytesIn, bytesOut := ps.bandwidth.totals()
+	smap[constants.StatusBandwidth] = fmt.Sprintf("%d/%d", bytesIn/1024, bytesOut/1024)
 	return smap, nil
 }
 
+// PeerTraffic is a snapshot of the traffic exchanged with a single peer.
+// The byte counts include the transport overhead while the per method
+// counters only include the size of the protobuf messages.
+type PeerTraffic struct {
+	Identity     string
+	P2PAddr      string
+	BytesRead    uint64
+	BytesWritten uint64
+	Methods      map[string]TrafficCounter
+}
+
+// Traffic returns a snapshot of the traffic exchanged with each active peer.
+func (ps *PeerManager) Traffic() []PeerTraffic {
+	ps.RLock()
+	conns := make([]interfaces.P2PMuxConn, 0, len(ps.peerConns))
+	for _, conn := range ps.peerConns {
+		conns = append(conns, conn)
+	}
+	ps.RUnlock()
+	out := make([]PeerTraffic, 0, len(conns))
+	for _, conn := range conns {
+		out = append(out, PeerTraffic{
+			Identity:     conn.NodeAddr().Identity(),
+			P2PAddr:      conn.NodeAddr().P2PAddr(),
+			BytesRead:    conn.BytesRead(),
+			BytesWritten: conn.BytesWritten(),
+			Methods:      ps.bandwidth.methods(conn.NodeAddr().Identity()),
+		})
+	}
+	return out
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 //P2P SERVER DISCOVERY LOOPS ///////////////////////////////////////////////////
@@ -6,8 +6,10 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/errorz"
 	"github.com/alicenet/alicenet/interfaces"
 	pb "github.com/alicenet/alicenet/proto"
 	"github.com/alicenet/alicenet/utils"
@@ -76,9 +78,17 @@ func NewP2PDiscoveryServerHandler(logger *logrus.Logger, addr net.Addr, service
 	return handler
 }
 
+// p2pStatusFallback is the gRPC status code of the errors without an
+// errorz.Code returned to peers. Those errors are caused by the objects a
+// peer sent, such as a transaction rejected by HandleP2PGossipTransaction.
+const p2pStatusFallback = codes.InvalidArgument
+
 // NewP2PServerHandler returns a RPC ServerHandler for the Pz2P Service.
-func newP2PServerHandler(logger *logrus.Logger, addr net.Addr, service interfaces.P2PServer) *ServerHandler {
-	srvr := grpc.NewServer(grpc.ConnectionTimeout(constants.SrvrMsgTimeout)) //, grpc.MaxConcurrentStreams(constants.P2PMaxConcurrentStreams), grpc.NumStreamWorkers(constants.P2PStreamWorkers)) //, grpc.ReadBufferSize(constants.ReadBufferSize))
+// All requests made by peers are accounted and rate limited by bandwidth and
+// the transactions they gossip are recorded in filter. Errors are returned to
+// peers with their errorz.Code.
+func newP2PServerHandler(logger *logrus.Logger, addr net.Addr, service interfaces.P2PServer, bandwidth *bandwidthManager, filter *gossipFilter) *ServerHandler {
+	srvr := grpc.NewServer(grpc.ConnectionTimeout(constants.SrvrMsgTimeout), grpc.ChainUnaryInterceptor(bandwidth.unaryServerInterceptor, filter.unaryServerInterceptor, errorz.StatusServerInterceptor(p2pStatusFallback))) //, grpc.MaxConcurrentStreams(constants.P2PMaxConcurrentStreams), grpc.NumStreamWorkers(constants.P2PStreamWorkers)) //, grpc.ReadBufferSize(constants.ReadBufferSize))
 	pb.RegisterP2PServer(srvr, service)
 	handler := &ServerHandler{
 		listener: NewListener(logger, addr),
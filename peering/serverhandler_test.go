@@ -0,0 +1,32 @@
+package peering
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/alicenet/errorz"
+)
+
+func TestP2PStatusServerInterceptor(t *testing.T) {
+	interceptor := errorz.StatusServerInterceptor(p2pStatusFallback)
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.P2P/GossipTransaction"}
+	closing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errorz.ErrClosing
+	}
+	_, err := interceptor(context.Background(), nil, info, closing)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Equal(t, errorz.CodeClosing, errorz.CodeOf(err))
+
+	rejected := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("invalid transaction")
+	}
+	_, err = interceptor(context.Background(), nil, info, rejected)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, errorz.CodeUnknown, errorz.CodeOf(err))
+}
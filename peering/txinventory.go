@@ -0,0 +1,132 @@
+package peering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/interfaces"
+	pb "github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/alicenet/utils"
+)
+
+var ErrTooManyTxHashes = errors.New("transaction inventory holds too many hashes")
+
+// TxInventory gives the peer manager access to the pending transaction pool
+// for inventory based transaction gossip.
+type TxInventory interface {
+	// TxHash returns the hash of a marshalled transaction.
+	TxHash(tx []byte) ([]byte, error)
+	// MissingTxs returns the hashes of the transactions that are not in the
+	// pending transaction pool.
+	MissingTxs(txHashes [][]byte) ([][]byte, error)
+}
+
+// txPulls tracks the transactions that are being pulled from a peer so that
+// a transaction announced by several peers is only requested once.
+type txPulls struct {
+	sync.Mutex
+	inflight map[string]struct{}
+}
+
+// start returns the hashes that are not already being pulled and marks them
+// as in flight.
+func (tp *txPulls) start(txHashes [][]byte) [][]byte {
+	tp.Lock()
+	defer tp.Unlock()
+	out := [][]byte{}
+	for _, hash := range txHashes {
+		if _, ok := tp.inflight[string(hash)]; ok {
+			continue
+		}
+		tp.inflight[string(hash)] = struct{}{}
+		out = append(out, hash)
+	}
+	return out
+}
+
+func (tp *txPulls) done(txHashes [][]byte) {
+	tp.Lock()
+	defer tp.Unlock()
+	for _, hash := range txHashes {
+		delete(tp.inflight, string(hash))
+	}
+}
+
+// SetTxInventory enables inventory based transaction gossip. Until it is
+// set, transactions are pushed to every peer in full.
+func (ps *PeerManager) SetTxInventory(inventory TxInventory) {
+	ps.Lock()
+	defer ps.Unlock()
+	ps.txInventory = inventory
+	ps.gossipFilter.setHasher(inventory.TxHash)
+}
+
+func (ps *PeerManager) getTxInventory() TxInventory {
+	ps.RLock()
+	defer ps.RUnlock()
+	return ps.txInventory
+}
+
+// pushTxs returns true if transactions must be pushed in full. Small
+// networks push since announcing only saves bandwidth once a transaction
+// reaches a node from several peers.
+func (ps *PeerManager) pushTxs() bool {
+	return ps.getTxInventory() == nil || ps.active.len() < constants.TxInventoryMinPeers
+}
+
+// HandleP2PGossipTxInventory records the transactions announced by a peer and
+// pulls the ones missing from the pending pool from that peer.
+func (ps *PeerManager) HandleP2PGossipTxInventory(ctx context.Context, msg *pb.GossipTxInventoryMessage) (*pb.GossipTxInventoryAck, error) {
+	if len(msg.TxHashes) > constants.TxInventoryMaxHashes {
+		return nil, fmt.Errorf("%w: %d", ErrTooManyTxHashes, len(msg.TxHashes))
+	}
+	addr, ok := peerAddr(ctx)
+	if !ok {
+		return &pb.GossipTxInventoryAck{}, nil
+	}
+	for _, hash := range msg.TxHashes {
+		ps.gossipFilter.add(addr.Identity(), hash)
+	}
+	inventory := ps.getTxInventory()
+	if inventory == nil {
+		return &pb.GossipTxInventoryAck{}, nil
+	}
+	missing, err := inventory.MissingTxs(msg.TxHashes)
+	if err != nil {
+		utils.DebugTrace(ps.logger, err)
+		return nil, err
+	}
+	missing = ps.txPulls.start(missing)
+	if len(missing) == 0 {
+		return &pb.GossipTxInventoryAck{}, nil
+	}
+	client, ok := ps.active.get(addr)
+	if !ok {
+		ps.txPulls.done(missing)
+		return &pb.GossipTxInventoryAck{}, nil
+	}
+	go ps.pullTxs(client, missing)
+	return &pb.GossipTxInventoryAck{}, nil
+}
+
+// pullTxs requests the given transactions from a peer and passes them to the
+// gossip handlers as if the peer had pushed them.
+func (ps *PeerManager) pullTxs(client interfaces.P2PClient, txHashes [][]byte) {
+	defer ps.txPulls.done(txHashes)
+	ctx, cf := context.WithTimeout(ps.ctx, constants.MsgTimeout)
+	defer cf()
+	resp, err := client.GetPendingTxs(ctx, &pb.GetPendingTxsRequest{TxHashes: txHashes})
+	if err != nil {
+		utils.DebugTrace(ps.logger, err)
+		return
+	}
+	for _, tx := range resp.Txs {
+		_, err := ps.p2pServer.GossipTransaction(ctx, &pb.GossipTransactionMessage{Transaction: tx})
+		if err != nil {
+			utils.DebugTrace(ps.logger, err)
+		}
+	}
+}
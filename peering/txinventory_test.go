@@ -0,0 +1,65 @@
+package peering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/constants"
+	"github.com/alicenet/alicenet/interfaces"
+	pb "github.com/alicenet/alicenet/proto"
+)
+
+type testTxInventory struct{}
+
+func (ti *testTxInventory) TxHash(tx []byte) ([]byte, error) {
+	return append([]byte("hash:"), tx...), nil
+}
+
+func (ti *testTxInventory) MissingTxs(txHashes [][]byte) ([][]byte, error) {
+	return txHashes, nil
+}
+
+func newTestInventoryPeerManager() *PeerManager {
+	return &PeerManager{
+		active:       &activePeerStore{store: make(map[string]interfaces.P2PClient)},
+		gossipFilter: newGossipFilter(),
+		txPulls:      &txPulls{inflight: make(map[string]struct{})},
+	}
+}
+
+func TestTxPulls(t *testing.T) {
+	tp := &txPulls{inflight: make(map[string]struct{})}
+	a, b := []byte("a"), []byte("b")
+	assert.Equal(t, [][]byte{a}, tp.start([][]byte{a}))
+	// a is already in flight
+	assert.Equal(t, [][]byte{b}, tp.start([][]byte{a, b}))
+	tp.done([][]byte{a})
+	assert.Equal(t, [][]byte{a}, tp.start([][]byte{a, b}))
+}
+
+func TestPushTxs(t *testing.T) {
+	ps := newTestInventoryPeerManager()
+	// without an inventory transactions are always pushed
+	assert.True(t, ps.pushTxs())
+
+	ps.SetTxInventory(&testTxInventory{})
+	assert.True(t, ps.pushTxs())
+	for i := 0; i < constants.TxInventoryMinPeers; i++ {
+		ps.active.store[fmt.Sprintf("peer%d", i)] = nil
+	}
+	assert.False(t, ps.pushTxs())
+
+	// the filter remembers transactions by their inventory hash
+	assert.Equal(t, []byte("hash:tx"), ps.gossipFilter.txKey([]byte("tx")))
+}
+
+func TestTxInventoryTooManyHashes(t *testing.T) {
+	ps := newTestInventoryPeerManager()
+	msg := &pb.GossipTxInventoryMessage{TxHashes: make([][]byte, constants.TxInventoryMaxHashes+1)}
+	_, err := ps.HandleP2PGossipTxInventory(context.Background(), msg)
+	assert.True(t, errors.Is(err, ErrTooManyTxHashes))
+}
@@ -106,6 +106,7 @@ func (pmx *P2PMux) serverMux(ctx context.Context, conn interfaces.P2PConn) (inte
 			nodeAddr:     conn.NodeAddr(),
 			protocol:     conn.Protocol(),
 			protoVersion: conn.ProtoVersion(),
+			features:     conn.Features(),
 			initiator:    conn.Initiator(),
 			session:      session,
 			closeChan:    conn.CloseChan(),
@@ -117,6 +118,7 @@ func (pmx *P2PMux) serverMux(ctx context.Context, conn interfaces.P2PConn) (inte
 			nodeAddr:     conn.NodeAddr(),
 			protocol:     conn.Protocol(),
 			protoVersion: conn.ProtoVersion(),
+			features:     conn.Features(),
 			initiator:    conn.Initiator(),
 			session:      session,
 			closeChan:    conn.CloseChan(),
@@ -182,6 +184,7 @@ func (pmx *P2PMux) clientMux(ctx context.Context, conn interfaces.P2PConn) (inte
 			initiator:    conn.Initiator(),
 			logger:       mlog,
 			protoVersion: conn.ProtoVersion(),
+			features:     conn.Features(),
 			nodeAddr:     conn.NodeAddr(),
 			session:      session,
 			closeChan:    conn.CloseChan(),
@@ -190,6 +193,7 @@ func (pmx *P2PMux) clientMux(ctx context.Context, conn interfaces.P2PConn) (inte
 		serverp2pconn := &P2PConn{
 			Conn:         serverConn,
 			protoVersion: conn.ProtoVersion(),
+			features:     conn.Features(),
 			logger:       mlog,
 			initiator:    conn.Initiator(),
 			nodeAddr:     conn.NodeAddr(),
@@ -5,6 +5,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"net"
 	"sync"
+	"sync/atomic"
 
 	"github.com/alicenet/alicenet/interfaces"
 	"github.com/alicenet/alicenet/types"
@@ -22,9 +23,12 @@ var (
 // communications.
 type P2PConn struct {
 	net.Conn
+	bytesRead    atomic.Uint64
+	bytesWritten atomic.Uint64
 	logger       *logrus.Logger
 	protocol     types.Protocol
 	protoVersion types.ProtoVersion
+	features     types.Features
 	initiator    types.P2PInitiator
 	nodeAddr     interfaces.NodeAddr
 	closeOnce    sync.Once
@@ -33,6 +37,30 @@ type P2PConn struct {
 	session      *yamux.Session
 }
 
+// Read See docs for net.Conn.
+func (pc *P2PConn) Read(b []byte) (int, error) {
+	n, err := pc.Conn.Read(b)
+	pc.bytesRead.Add(uint64(n))
+	return n, err
+}
+
+// Write See docs for net.Conn.
+func (pc *P2PConn) Write(b []byte) (int, error) {
+	n, err := pc.Conn.Write(b)
+	pc.bytesWritten.Add(uint64(n))
+	return n, err
+}
+
+// BytesRead returns the number of bytes read from the connection.
+func (pc *P2PConn) BytesRead() uint64 {
+	return pc.bytesRead.Load()
+}
+
+// BytesWritten returns the number of bytes written to the connection.
+func (pc *P2PConn) BytesWritten() uint64 {
+	return pc.bytesWritten.Load()
+}
+
 // CloseChan closes channel.
 func (pc *P2PConn) CloseChan() <-chan struct{} {
 	return pc.closeChan
@@ -89,8 +117,13 @@ func (pc *P2PConn) Protocol() types.Protocol {
 	return pc.protocol
 }
 
-// ProtoVersion returns the protocol version being used.
-// This is not used at this time.
+// ProtoVersion returns the protocol version negotiated with the remote peer.
 func (pc *P2PConn) ProtoVersion() types.ProtoVersion {
 	return pc.protoVersion
 }
+
+// Features returns the optional features supported by both the local node
+// and the remote peer.
+func (pc *P2PConn) Features() types.Features {
+	return pc.features
+}
@@ -71,3 +71,26 @@ func (pmc *P2PMuxConn) Initiator() types.P2PInitiator {
 func (pmc *P2PMuxConn) NodeAddr() interfaces.NodeAddr {
 	return pmc.nodeAddr
 }
+
+// ProtoVersion returns the protocol version negotiated with the remote peer.
+func (pmc *P2PMuxConn) ProtoVersion() types.ProtoVersion {
+	return pmc.baseConn.ProtoVersion()
+}
+
+// Features returns the optional features supported by both the local node
+// and the remote peer.
+func (pmc *P2PMuxConn) Features() types.Features {
+	return pmc.baseConn.Features()
+}
+
+// BytesRead returns the number of bytes read from the underlying connection,
+// including the multiplexing overhead of all streams.
+func (pmc *P2PMuxConn) BytesRead() uint64 {
+	return pmc.baseConn.BytesRead()
+}
+
+// BytesWritten returns the number of bytes written to the underlying
+// connection, including the multiplexing overhead of all streams.
+func (pmc *P2PMuxConn) BytesWritten() uint64 {
+	return pmc.baseConn.BytesWritten()
+}
@@ -13,6 +13,10 @@ import (
 // when remoteID and localID fail to agree.
 var ErrWrongChainID = errors.New("remote peer sent wrong chain identifier")
 
+// ErrNoCommonProtoVersion occurs in (peer|self)CapabilityHandshake
+// when the protocol version ranges of both peers do not overlap.
+var ErrNoCommonProtoVersion = errors.New("remote peer has no protocol version in common")
+
 // Verify that both peers are working on the same chain by
 // having them cross compare their chain identifiers.
 // This step MUST be done after an authenticated encrypted channel
@@ -92,6 +96,104 @@ func peerInitiatedVersionHandshake(conn net.Conn, version types.ProtoVersion) (u
 	return remoteversion, nil
 }
 
+// capabilities are the protocol versions and optional features a node
+// is able to speak.
+type capabilities struct {
+	minVersion types.ProtoVersion
+	maxVersion types.ProtoVersion
+	features   types.Features
+}
+
+// negotiate selects the highest protocol version supported by both sides
+// and the features advertised by both sides.
+func (c capabilities) negotiate(remote capabilities) (types.ProtoVersion, types.Features, error) {
+	version := c.maxVersion
+	if remote.maxVersion < version {
+		version = remote.maxVersion
+	}
+	if version < c.minVersion || version < remote.minVersion {
+		return 0, 0, fmt.Errorf("%w: local %d-%d, remote %d-%d", ErrNoCommonProtoVersion, c.minVersion, c.maxVersion, remote.minVersion, remote.maxVersion)
+	}
+	return version, c.features & remote.features, nil
+}
+
+// Negotiate the protocol version and features of the connection.
+// The maximum version of the remote peer is learned from the version
+// handshake. Peers older than capabilityProtoVersion do not know about
+// this handshake, so nothing is exchanged with them and they are assumed
+// to speak only their advertised version without any features.
+func selfInitiatedCapabilityHandshake(conn net.Conn, local capabilities, remoteVersion types.ProtoVersion) (types.ProtoVersion, types.Features, error) {
+	remote := capabilities{minVersion: remoteVersion, maxVersion: remoteVersion}
+	if remoteVersion >= capabilityProtoVersion {
+		if err := writeCapabilities(conn, local); err != nil {
+			return 0, 0, err
+		}
+		var err error
+		remote, err = readCapabilities(conn, remoteVersion)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return local.negotiate(remote)
+}
+
+func peerInitiatedCapabilityHandshake(conn net.Conn, local capabilities, remoteVersion types.ProtoVersion) (types.ProtoVersion, types.Features, error) {
+	remote := capabilities{minVersion: remoteVersion, maxVersion: remoteVersion}
+	if remoteVersion >= capabilityProtoVersion {
+		var err error
+		remote, err = readCapabilities(conn, remoteVersion)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := writeCapabilities(conn, local); err != nil {
+			return 0, 0, err
+		}
+	}
+	return local.negotiate(remote)
+}
+
+func writeCapabilities(conn net.Conn, local capabilities) error {
+	if err := writeUint32(conn, uint32(local.minVersion)); err != nil {
+		return err
+	}
+	return writeUint64(conn, uint64(local.features))
+}
+
+func readCapabilities(conn net.Conn, remoteVersion types.ProtoVersion) (capabilities, error) {
+	minVersion, err := readUint32(conn)
+	if err != nil {
+		return capabilities{}, err
+	}
+	features, err := readUint64(conn)
+	if err != nil {
+		return capabilities{}, err
+	}
+	return capabilities{
+		minVersion: types.ProtoVersion(minVersion),
+		maxVersion: remoteVersion,
+		features:   types.Features(features),
+	}, nil
+}
+
+func writeUint64(conn net.Conn, local uint64) error {
+	if err := writeUint32(conn, uint32(local>>32)); err != nil {
+		return err
+	}
+	return writeUint32(conn, uint32(local))
+}
+
+func readUint64(conn net.Conn) (uint64, error) {
+	hi, err := readUint32(conn)
+	if err != nil {
+		return 0, err
+	}
+	lo, err := readUint32(conn)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(hi)<<32 | uint64(lo), nil
+}
+
 func writeUint32(conn net.Conn, local uint32) error {
 	localBytes := marshalUint32(local)
 	_, err := conn.Write(localBytes[:])
@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alicenet/alicenet/types"
+)
+
+type capabilityResult struct {
+	version  types.ProtoVersion
+	features types.Features
+	err      error
+}
+
+func runCapabilityHandshake(t *testing.T, self, peer capabilities) (capabilityResult, capabilityResult) {
+	t.Helper()
+	selfConn, peerConn := net.Pipe()
+	defer selfConn.Close()
+	defer peerConn.Close()
+
+	selfResult := make(chan capabilityResult, 1)
+	peerResult := make(chan capabilityResult, 1)
+	go func() {
+		version, features, err := selfInitiatedCapabilityHandshake(selfConn, self, peer.maxVersion)
+		selfResult <- capabilityResult{version, features, err}
+	}()
+	go func() {
+		version, features, err := peerInitiatedCapabilityHandshake(peerConn, peer, self.maxVersion)
+		peerResult <- capabilityResult{version, features, err}
+	}()
+	return <-selfResult, <-peerResult
+}
+
+func TestCapabilityHandshake(t *testing.T) {
+	self := capabilities{minVersion: 1, maxVersion: 3, features: 0b0111}
+	peer := capabilities{minVersion: 2, maxVersion: 2, features: 0b1101}
+
+	selfResult, peerResult := runCapabilityHandshake(t, self, peer)
+	assert.Nil(t, selfResult.err)
+	assert.Nil(t, peerResult.err)
+	assert.Equal(t, types.ProtoVersion(2), selfResult.version)
+	assert.Equal(t, types.ProtoVersion(2), peerResult.version)
+	assert.Equal(t, types.Features(0b0101), selfResult.features)
+	assert.Equal(t, types.Features(0b0101), peerResult.features)
+}
+
+func TestCapabilityHandshakeLegacyPeer(t *testing.T) {
+	self := capabilities{minVersion: 1, maxVersion: 2, features: 0b0111}
+	selfConn, peerConn := net.Pipe()
+	defer selfConn.Close()
+	defer peerConn.Close()
+
+	// a legacy peer only sends its version, so nothing may be written
+	// during the capability handshake
+	version, features, err := selfInitiatedCapabilityHandshake(selfConn, self, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, types.ProtoVersion(1), version)
+	assert.Equal(t, types.Features(0), features)
+
+	version, features, err = peerInitiatedCapabilityHandshake(peerConn, self, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, types.ProtoVersion(1), version)
+	assert.Equal(t, types.Features(0), features)
+}
+
+func TestCapabilityHandshakeNoCommonVersion(t *testing.T) {
+	self := capabilities{minVersion: 3, maxVersion: 4}
+	peer := capabilities{minVersion: 2, maxVersion: 2}
+
+	selfResult, peerResult := runCapabilityHandshake(t, self, peer)
+	assert.True(t, errors.Is(selfResult.err, ErrNoCommonProtoVersion))
+	assert.True(t, errors.Is(peerResult.err, ErrNoCommonProtoVersion))
+}
+
+func TestFeaturesHas(t *testing.T) {
+	fs := types.Features(0b1010)
+	assert.True(t, fs.Has(0b1000))
+	assert.False(t, fs.Has(0b1001))
+	assert.True(t, fs.Has(0))
+}
@@ -21,8 +21,21 @@ var _ interfaces.P2PTransport = (*P2PTransport)(nil)
 // This is the required value for the network string used in this package due to
 // the design of brontide.
 const (
-	tcpNetwork   string             = "tcp"
-	protoVersion types.ProtoVersion = 1
+	tcpNetwork string = "tcp"
+
+	// protoVersion is the newest protocol version spoken by this node.
+	// This value is sent to peers in the version handshake.
+	protoVersion types.ProtoVersion = 2
+	// minProtoVersion is the oldest protocol version this node will accept
+	// for a connection.
+	minProtoVersion types.ProtoVersion = 1
+	// capabilityProtoVersion is the first protocol version that performs
+	// the capability handshake.
+	capabilityProtoVersion types.ProtoVersion = 2
+
+	// supportedFeatures are the optional features advertised by this node
+	// during the capability handshake.
+	supportedFeatures types.Features = types.FeatureBatchedGossip | types.FeatureTxInventory
 
 	// handshakeReadTimeout is a read timeout that will be enforced when
 	// waiting for state payloads during the various acts of Brontide. If
@@ -39,6 +52,8 @@ type P2PTransport struct {
 	localNodeAddr interfaces.NodeAddr
 	// This is the private key used during encryption and authentication.
 	localPrivateKey *secp256k1.PrivateKey
+	// These are the protocol versions and features advertised to peers.
+	localCapabilities capabilities
 	// This is the brontide listener.
 	listener *brontide.Listener
 	// This is the quit notification channel for Accept loops.
@@ -79,6 +94,13 @@ func (pt *P2PTransport) NodeAddr() interfaces.NodeAddr {
 	return pt.localNodeAddr
 }
 
+// Features returns the optional features advertised by the local node.
+// Connections will only have a feature enabled if the remote peer
+// advertises it as well.
+func (pt *P2PTransport) Features() types.Features {
+	return pt.localCapabilities.features
+}
+
 // Dial will dial a remote peer at the specified address with the given
 // protocol.
 func (pt *P2PTransport) Dial(addr interfaces.NodeAddr, protocol types.Protocol) (interfaces.P2PConn, error) {
@@ -121,7 +143,17 @@ func (pt *P2PTransport) Dial(addr interfaces.NodeAddr, protocol types.Protocol)
 		bconn.Close()
 		return nil, err
 	}
-	remoteVersion, err := selfInitiatedVersionHandshake(bconn, protoVersion)
+	remoteVersion, err := selfInitiatedVersionHandshake(bconn, pt.localCapabilities.maxVersion)
+	if err != nil {
+		bconn.Close()
+		return nil, err
+	}
+
+	if err := bconn.SetReadDeadline(time.Now().Add(handshakeReadTimeout)); err != nil {
+		bconn.Close()
+		return nil, err
+	}
+	version, features, err := selfInitiatedCapabilityHandshake(bconn, pt.localCapabilities, types.ProtoVersion(remoteVersion))
 	if err != nil {
 		bconn.Close()
 		return nil, err
@@ -157,7 +189,8 @@ func (pt *P2PTransport) Dial(addr interfaces.NodeAddr, protocol types.Protocol)
 		logger:       pt.logger,
 		initiator:    types.SelfInitiatedConnection,
 		protocol:     protocol,
-		protoVersion: types.ProtoVersion(remoteVersion),
+		protoVersion: version,
+		features:     features,
 		cleanupfn:    func() { close(closeChan) },
 		closeChan:    closeChan,
 	}, nil
@@ -269,7 +302,27 @@ func (pt *P2PTransport) doAliceNetHandshake(bconn *brontide.Conn, closeFn func()
 		closeFn()
 		return nil
 	}
-	remoteVersion, err := peerInitiatedVersionHandshake(bconn, protoVersion)
+	remoteVersion, err := peerInitiatedVersionHandshake(bconn, pt.localCapabilities.maxVersion)
+	if err != nil {
+		utils.DebugTrace(pt.logger, err)
+		err2 := bconn.Close()
+		if err2 != nil {
+			utils.DebugTrace(pt.logger, err2)
+		}
+		closeFn()
+		return nil
+	}
+
+	if err := bconn.SetReadDeadline(time.Now().Add(handshakeReadTimeout)); err != nil {
+		utils.DebugTrace(pt.logger, err)
+		err2 := bconn.Close()
+		if err2 != nil {
+			utils.DebugTrace(pt.logger, err2)
+		}
+		closeFn()
+		return nil
+	}
+	version, features, err := peerInitiatedCapabilityHandshake(bconn, pt.localCapabilities, types.ProtoVersion(remoteVersion))
 	if err != nil {
 		utils.DebugTrace(pt.logger, err)
 		err2 := bconn.Close()
@@ -385,7 +438,8 @@ func (pt *P2PTransport) doAliceNetHandshake(bconn *brontide.Conn, closeFn func()
 		logger:       pt.logger,
 		initiator:    types.PeerInitiatedConnection,
 		protocol:     types.Protocol(protocol),
-		protoVersion: types.ProtoVersion(remoteVersion),
+		protoVersion: version,
+		features:     features,
 		cleanupfn:    cleanupFn,
 		closeChan:    closeChan,
 	}
@@ -465,6 +519,11 @@ func NewP2PTransport(logger *logrus.Logger, cid types.ChainIdentifier, privateKe
 		originLimit:            mc,
 		numConnectionsbyIP:     make(map[string]int),
 		numConnectionsbyPubkey: make(map[string]int),
+		localCapabilities: capabilities{
+			minVersion: minProtoVersion,
+			maxVersion: protoVersion,
+			features:   supportedFeatures,
+		},
 	}
 	return transport, nil
 }
@@ -15,3 +15,21 @@ const (
 	DiscProtocol
 	Bootnode
 )
+
+// Features is a bit set of optional protocol capabilities that a node
+// advertises to its peers during the transport handshake. The features of a
+// connection are the intersection of the features advertised by both peers.
+type Features uint64
+
+// FeatureBatchedGossip allows transactions and consensus messages to be
+// gossiped to a peer in compressed batches.
+const FeatureBatchedGossip Features = 1 << 0
+
+// FeatureTxInventory allows transactions to be announced to a peer by hash
+// and pulled by the peer on demand instead of being pushed in full.
+const FeatureTxInventory Features = 1 << 1
+
+// Has returns true if every feature in f is present in the set.
+func (fs Features) Has(f Features) bool {
+	return fs&f == f
+}
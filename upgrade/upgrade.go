@@ -0,0 +1,542 @@
+// Package upgrade stages the new AliceNet node versions announced on layer1
+// and restarts the node into them at their execution epoch.
+//
+// A release is fetched from a local directory or https URL template together
+// with its .sha256 file and, if a public key is configured, its .sig ed25519
+// signature. The sha256 must also match the binary hash of the version, when
+// it is announced with one. Since the .sha256 file comes from the same source
+// as the release, a release is only staged if it is signed or its binary hash
+// was announced on layer1. Once checked, it is written to the staging
+// directory and the node is asked to restart when AliceNet reaches the
+// execution epoch of the version, or as soon as it is staged if the version
+// already became canonical. In dry run mode the release is staged but the
+// node keeps running.
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/layer1"
+	"github.com/alicenet/alicenet/layer1/chains/ethereum/events"
+	"github.com/alicenet/alicenet/layer1/monitor/objects"
+	"github.com/alicenet/alicenet/utils"
+)
+
+// DefaultArtifactName is the name of the releases in a source directory.
+const DefaultArtifactName = "alicenet-{{.Version}}-{{.OS}}-{{.Arch}}"
+
+// MaxArtifactSize is the maximum size of a release.
+const MaxArtifactSize = 512 << 20
+
+// epochPollInterval is how often the manager checks if the execution epoch of
+// the staged version was reached.
+const epochPollInterval = 10 * time.Second
+
+var (
+	ErrInvalidConfig     = errors.New("invalid upgrade configuration")
+	ErrChecksumMismatch  = errors.New("release checksum mismatch")
+	ErrInvalidSignature  = errors.New("invalid release signature")
+	ErrNothingStaged     = errors.New("no release staged")
+	ErrArtifactTooLarge  = errors.New("release too large")
+	ErrUnexpectedHTTPErr = errors.New("unexpected http status")
+	ErrUnverifiedRelease = errors.New("release is neither signed nor announced with a binary hash")
+	ErrInsecureSource    = errors.New("releases must be fetched over https")
+)
+
+// State is the state of the upgrade manager.
+type State string
+
+const (
+	Idle       State = "Idle"
+	Staging    State = "Staging"
+	Staged     State = "Staged"
+	Failed     State = "Failed"
+	Restarting State = "Restarting"
+)
+
+// Status describes the upgrade the manager is working on.
+type Status struct {
+	Enabled    bool
+	DryRun     bool
+	State      State
+	Version    bindings.CanonicalVersion
+	Artifact   string
+	Checksum   string
+	StagedPath string
+	Err        string
+	// CurrentEpoch is the last AliceNet epoch seen by the manager.
+	CurrentEpoch uint32
+}
+
+// Manager stages new node versions and requests a restart at their execution
+// epoch.
+type Manager struct {
+	sync.Mutex
+	cfg          config.UpgradeConfig
+	publicKey    ed25519.PublicKey
+	executable   string
+	currentEpoch func() (uint32, error)
+	logger       *logrus.Entry
+	client       *http.Client
+
+	status  Status
+	cancel  context.CancelFunc
+	staging *sync.WaitGroup
+	// required is set once the staged version became canonical, the node
+	// can no longer run the local version.
+	required bool
+
+	restartOnce sync.Once
+	restartChan chan struct{}
+	closeOnce   sync.Once
+	closeChan   chan struct{}
+}
+
+// NewManager creates a Manager that replaces executable with the new
+// versions. currentHeight returns the latest AliceNet height known by the
+// node.
+func NewManager(cfg config.UpgradeConfig, executable string, currentHeight func() (uint32, error), logger *logrus.Entry) (*Manager, error) {
+	m := &Manager{
+		cfg:        cfg,
+		executable: executable,
+		currentEpoch: func() (uint32, error) {
+			height, err := currentHeight()
+			if err != nil {
+				return 0, err
+			}
+			return utils.Epoch(height), nil
+		},
+		logger:      logger,
+		client:      &http.Client{Timeout: 10 * time.Minute},
+		status:      Status{Enabled: cfg.Enabled, DryRun: cfg.DryRun, State: Idle},
+		staging:     &sync.WaitGroup{},
+		restartChan: make(chan struct{}),
+		closeChan:   make(chan struct{}),
+	}
+	if !cfg.Enabled {
+		return m, nil
+	}
+	if cfg.Source == "" {
+		return nil, fmt.Errorf("%w: the release source is required", ErrInvalidConfig)
+	}
+	if strings.HasPrefix(cfg.Source, "http://") {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, ErrInsecureSource)
+	}
+	if cfg.PublicKey != "" {
+		key, err := hex.DecodeString(strings.TrimPrefix(cfg.PublicKey, "0x"))
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: the public key must be a hex encoded ed25519 key", ErrInvalidConfig)
+		}
+		m.publicKey = key
+	}
+	if m.cfg.StagingDir == "" {
+		m.cfg.StagingDir = filepath.Dir(executable)
+	}
+	return m, nil
+}
+
+// Start checks periodically if the node has to restart into a staged
+// version.
+func (m *Manager) Start() {
+	if !m.cfg.Enabled {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-m.closeChan:
+				return
+			case <-time.After(epochPollInterval):
+			}
+			m.checkEpoch()
+		}
+	}()
+}
+
+// Close stops the manager and any staging in progress.
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.closeChan)
+		m.Lock()
+		if m.cancel != nil {
+			m.cancel()
+		}
+		m.Unlock()
+		m.staging.Wait()
+	})
+}
+
+// RestartChan is closed when the node should shut down and call Install to
+// restart into the staged version.
+func (m *Manager) RestartChan() <-chan struct{} {
+	return m.restartChan
+}
+
+// Status returns the status of the manager.
+func (m *Manager) Status() Status {
+	m.Lock()
+	defer m.Unlock()
+	return m.status
+}
+
+// VersionAvailable starts staging version in the background if it is newer
+// than the local version and than the version already staged.
+func (m *Manager) VersionAvailable(version bindings.CanonicalVersion) {
+	if !m.cfg.Enabled {
+		return
+	}
+	logger := m.logger.WithField("Version", versionString(version))
+	major, minor, patch, _, err := utils.CompareCanonicalVersion(version)
+	if err != nil {
+		logger.Warnf("Not upgrading, could not compare with the local version: %v", err)
+		return
+	}
+	if !major && !minor && !patch {
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if m.status.State != Idle && m.status.State != Failed && !isNewer(version, m.status.Version) {
+		return
+	}
+	if m.status.State == Restarting {
+		return
+	}
+	if m.cancel != nil {
+		m.cancel()
+	}
+	ctx, cf := context.WithCancel(context.Background())
+	m.cancel = cf
+	m.status.Version = version
+	m.status.State = Staging
+	m.status.Artifact = ""
+	m.status.Checksum = ""
+	m.status.StagedPath = ""
+	m.status.Err = ""
+
+	m.staging.Add(1)
+	go func() {
+		defer m.staging.Done()
+		defer cf()
+		logger.Info("Staging new AliceNet node version")
+		if _, err := m.Stage(ctx, version); err != nil {
+			logger.Errorf("Failed to stage the new version: %v", err)
+			m.shutdownIfRequired()
+			return
+		}
+		m.checkEpoch()
+	}()
+}
+
+// VersionCanonical is called when a new major version became canonical and
+// the local version can no longer run. It returns true if the manager takes
+// over the shutdown of the node: the node restarts into version once it is
+// staged, or shuts down if staging fails. It returns false if automatic
+// upgrades are disabled or in dry run, the caller has to stop the node then.
+func (m *Manager) VersionCanonical(version bindings.CanonicalVersion) bool {
+	if !m.cfg.Enabled || m.cfg.DryRun {
+		return false
+	}
+	m.Lock()
+	m.required = true
+	m.Unlock()
+	m.VersionAvailable(version)
+
+	m.Lock()
+	if m.status.State == Idle {
+		// the version could not be compared with the local version
+		m.required = false
+		m.Unlock()
+		return false
+	}
+	m.Unlock()
+	m.checkEpoch()
+	return true
+}
+
+// Subscription returns the monitor subscription that feeds the versions
+// announced by the Dynamics contract to the manager.
+func (m *Manager) Subscription() *objects.Subscription {
+	return &objects.Subscription{
+		Name: "upgrade.NewAliceNetNodeVersionAvailable",
+		Contract: func(contracts layer1.AllSmartContracts) common.Address {
+			return contracts.EthereumContracts().DynamicsAddress()
+		},
+		Event: events.GetDynamicsEvents()["NewAliceNetNodeVersionAvailable"],
+		Processor: objects.TypedProcessor(
+			func(contracts layer1.AllSmartContracts, log types.Log) (*bindings.DynamicsNewAliceNetNodeVersionAvailable, error) {
+				return contracts.EthereumContracts().Dynamics().ParseNewAliceNetNodeVersionAvailable(log)
+			},
+			func(eth layer1.Client, contracts layer1.AllSmartContracts, logger *logrus.Entry, state *objects.MonitorState, event *bindings.DynamicsNewAliceNetNodeVersionAvailable) error {
+				m.VersionAvailable(event.Version)
+				return nil
+			},
+		),
+	}
+}
+
+// Stage fetches, checks and stages the release of version. It returns the
+// path of the staged release.
+func (m *Manager) Stage(ctx context.Context, version bindings.CanonicalVersion) (string, error) {
+	path, checksum, artifact, err := m.stage(ctx, version)
+
+	m.Lock()
+	defer m.Unlock()
+	if isNewer(m.status.Version, version) && m.status.State != Idle {
+		// a newer version replaced this one while it was staging
+		return path, err
+	}
+	m.status.Version = version
+	m.status.Artifact = artifact
+	m.status.Checksum = checksum
+	if err != nil {
+		m.status.State = Failed
+		m.status.Err = err.Error()
+		return "", err
+	}
+	m.status.State = Staged
+	m.status.StagedPath = path
+	m.status.Err = ""
+	return path, nil
+}
+
+func (m *Manager) stage(ctx context.Context, version bindings.CanonicalVersion) (string, string, string, error) {
+	artifact, err := ArtifactLocation(m.cfg.Source, version)
+	if err != nil {
+		return "", "", "", err
+	}
+	data, err := m.fetch(ctx, artifact)
+	if err != nil {
+		return "", "", artifact, err
+	}
+	rawChecksum, err := m.fetch(ctx, artifact+".sha256")
+	if err != nil {
+		return "", "", artifact, err
+	}
+	checksum, err := VerifyChecksum(data, rawChecksum)
+	if err != nil {
+		return "", "", artifact, err
+	}
+	hasBinaryHash := version.BinaryHash != ([32]byte{})
+	if hasBinaryHash && checksum != hex.EncodeToString(version.BinaryHash[:]) {
+		return "", checksum, artifact, fmt.Errorf("%w: the release does not match the binary hash announced on layer1", ErrChecksumMismatch)
+	}
+	if !hasBinaryHash && m.publicKey == nil {
+		return "", checksum, artifact, ErrUnverifiedRelease
+	}
+	if m.publicKey != nil {
+		sig, err := m.fetch(ctx, artifact+".sig")
+		if err != nil {
+			return "", checksum, artifact, err
+		}
+		if err := VerifySignature(m.publicKey, data, sig); err != nil {
+			return "", checksum, artifact, err
+		}
+	}
+
+	if err := os.MkdirAll(m.cfg.StagingDir, 0o755); err != nil {
+		return "", checksum, artifact, err
+	}
+	path := filepath.Join(m.cfg.StagingDir, fmt.Sprintf(".alicenet-%v.staged", versionString(version)))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o755); err != nil {
+		return "", checksum, artifact, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", checksum, artifact, err
+	}
+	return path, checksum, artifact, nil
+}
+
+// checkEpoch requests the restart once the execution epoch of the staged
+// version is reached.
+func (m *Manager) checkEpoch() {
+	epoch, err := m.currentEpoch()
+	if err != nil {
+		m.logger.Debugf("Could not get the current epoch: %v", err)
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.status.CurrentEpoch = epoch
+	if m.status.State != Staged || (epoch < m.status.Version.ExecutionEpoch && !m.required) {
+		return
+	}
+	logger := m.logger.WithFields(logrus.Fields{
+		"Version": versionString(m.status.Version),
+		"Epoch":   epoch,
+	})
+	if m.cfg.DryRun {
+		if m.status.Err == "" {
+			logger.Warnf("Dry run: the node would restart into %v now", m.status.StagedPath)
+			m.status.Err = "dry run, restart skipped"
+		}
+		return
+	}
+	logger.Warn("Execution epoch reached, restarting the node into the new version")
+	m.status.State = Restarting
+	m.restartOnce.Do(func() { close(m.restartChan) })
+}
+
+// shutdownIfRequired shuts down the node when the version that became
+// canonical could not be staged. Install fails afterwards and the node exits
+// without restarting.
+func (m *Manager) shutdownIfRequired() {
+	m.Lock()
+	defer m.Unlock()
+	if !m.required || m.status.State != Failed {
+		return
+	}
+	m.logger.Error("CRITICAL: the new canonical version could not be staged, shutting down! Please update your node!")
+	m.restartOnce.Do(func() { close(m.restartChan) })
+}
+
+// Install replaces the node executable with the staged release and returns
+// the path to execute. If the executable cannot be replaced the staged
+// release is returned to be executed in place.
+func (m *Manager) Install() (string, error) {
+	m.Lock()
+	defer m.Unlock()
+	if m.status.State != Restarting || m.status.StagedPath == "" {
+		return "", ErrNothingStaged
+	}
+	if err := os.Rename(m.status.StagedPath, m.executable); err != nil {
+		m.logger.Warnf("Could not replace %v, running the staged release in place: %v", m.executable, err)
+		return m.status.StagedPath, nil
+	}
+	return m.executable, nil
+}
+
+func (m *Manager) fetch(ctx context.Context, location string) ([]byte, error) {
+	if strings.HasPrefix(location, "http://") {
+		return nil, ErrInsecureSource
+	}
+	if !strings.HasPrefix(location, "https://") {
+		f, err := os.Open(strings.TrimPrefix(location, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return readLimited(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w fetching %v: %v", ErrUnexpectedHTTPErr, location, resp.Status)
+	}
+	return readLimited(resp.Body)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxArtifactSize {
+		return nil, ErrArtifactTooLarge
+	}
+	return data, nil
+}
+
+// ArtifactLocation returns the path or URL of the release of version. A
+// local directory source holds the releases under DefaultArtifactName, any
+// other source is a template with the Version, OS and Arch fields.
+func ArtifactLocation(source string, version bindings.CanonicalVersion) (string, error) {
+	isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	if !isURL {
+		if info, err := os.Stat(strings.TrimPrefix(source, "file://")); err == nil && info.IsDir() {
+			source = filepath.Join(source, DefaultArtifactName)
+		}
+	}
+	tmpl, err := template.New("artifact").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, struct{ Version, OS, Arch string }{
+		Version: versionString(version),
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return buffer.String(), nil
+}
+
+// VerifyChecksum checks data against a checksum file in the sha256sum format
+// and returns the hex encoded checksum.
+func VerifyChecksum(data []byte, checksumFile []byte) (string, error) {
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%w: empty checksum file", ErrChecksumMismatch)
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(fields[0], checksum) {
+		return "", fmt.Errorf("%w: expected %v, got %v", ErrChecksumMismatch, fields[0], checksum)
+	}
+	return checksum, nil
+}
+
+// VerifySignature checks the ed25519 signature of data. The signature may be
+// raw or hex encoded.
+func VerifySignature(publicKey ed25519.PublicKey, data []byte, sig []byte) error {
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(sig)), "0x"))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		sig = decoded
+	}
+	if len(sig) != ed25519.SignatureSize || !ed25519.Verify(publicKey, data, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func versionString(version bindings.CanonicalVersion) string {
+	return fmt.Sprintf("v%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+// isNewer returns true if a is a newer version than b.
+func isNewer(a, b bindings.CanonicalVersion) bool {
+	if a.Major != b.Major {
+		return a.Major > b.Major
+	}
+	if a.Minor != b.Minor {
+		return a.Minor > b.Minor
+	}
+	return a.Patch > b.Patch
+}
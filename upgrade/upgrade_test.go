@@ -0,0 +1,298 @@
+package upgrade
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alicenet/alicenet/bridge/bindings"
+	"github.com/alicenet/alicenet/config"
+	"github.com/alicenet/alicenet/constants"
+)
+
+var release = []byte("#!/bin/sh\necho new version\n")
+
+// newVersion returns a version announced with the binary hash of release.
+func newVersion(epoch uint32) bindings.CanonicalVersion {
+	return bindings.CanonicalVersion{Major: 1, Minor: 1, Patch: 0, ExecutionEpoch: epoch, BinaryHash: sha256.Sum256(release)}
+}
+
+// writeRelease writes the release of version, its checksum and signature to
+// dir.
+func writeRelease(t *testing.T, dir string, version bindings.CanonicalVersion, key ed25519.PrivateKey) string {
+	t.Helper()
+	path := filepath.Join(dir, fmt.Sprintf("alicenet-%v-%v-%v", versionString(version), runtime.GOOS, runtime.GOARCH))
+	sum := sha256.Sum256(release)
+	require.Nil(t, os.WriteFile(path, release, 0o644))
+	require.Nil(t, os.WriteFile(path+".sha256", []byte(hex.EncodeToString(sum[:])+"  alicenet\n"), 0o644))
+	if key != nil {
+		require.Nil(t, os.WriteFile(path+".sig", []byte(hex.EncodeToString(ed25519.Sign(key, release))), 0o644))
+	}
+	return path
+}
+
+func newTestManager(t *testing.T, cfg config.UpgradeConfig, height *uint32) *Manager {
+	t.Helper()
+	config.Configuration.Version = "v1.0.0"
+	executable := filepath.Join(t.TempDir(), "alicenet")
+	require.Nil(t, os.WriteFile(executable, []byte("old version"), 0o755))
+	m, err := NewManager(cfg, executable, func() (uint32, error) { return *height, nil }, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func TestNewManager_InvalidConfig(t *testing.T) {
+	_, err := NewManager(config.UpgradeConfig{Enabled: true}, "alicenet", nil, logrus.NewEntry(logrus.New()))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = NewManager(config.UpgradeConfig{Enabled: true, Source: t.TempDir(), PublicKey: "0x1234"}, "alicenet", nil, logrus.NewEntry(logrus.New()))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = NewManager(config.UpgradeConfig{Enabled: true, Source: "http://example.com/{{.Version}}"}, "alicenet", nil, logrus.NewEntry(logrus.New()))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	m, err := NewManager(config.UpgradeConfig{}, "alicenet", nil, logrus.NewEntry(logrus.New()))
+	require.Nil(t, err)
+	assert.Equal(t, Status{State: Idle}, m.Status())
+}
+
+func TestArtifactLocation(t *testing.T) {
+	dir := t.TempDir()
+	version := newVersion(0)
+
+	location, err := ArtifactLocation(dir, version)
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, fmt.Sprintf("alicenet-v1.1.0-%v-%v", runtime.GOOS, runtime.GOARCH)), location)
+
+	location, err = ArtifactLocation("https://example.com/{{.Version}}/alicenet-{{.OS}}", version)
+	require.Nil(t, err)
+	assert.Equal(t, "https://example.com/v1.1.0/alicenet-"+runtime.GOOS, location)
+
+	_, err = ArtifactLocation("https://example.com/{{.Unknown}}", version)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	sum := sha256.Sum256(release)
+	checksum, err := VerifyChecksum(release, []byte(hex.EncodeToString(sum[:])))
+	require.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), checksum)
+
+	_, err = VerifyChecksum([]byte("tampered"), []byte(hex.EncodeToString(sum[:])))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	_, err = VerifyChecksum(release, []byte{})
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	sig := ed25519.Sign(key, release)
+
+	assert.Nil(t, VerifySignature(pub, release, sig))
+	assert.Nil(t, VerifySignature(pub, release, []byte(hex.EncodeToString(sig)+"\n")))
+	assert.ErrorIs(t, VerifySignature(pub, []byte("tampered"), sig), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature(pub, release, []byte("not a signature")), ErrInvalidSignature)
+}
+
+func TestStage(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	source := t.TempDir()
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: source, PublicKey: hex.EncodeToString(pub)}, &height)
+
+	version := newVersion(10)
+	writeRelease(t, source, version, key)
+	path, err := m.Stage(context.Background(), version)
+	require.Nil(t, err)
+	staged, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, release, staged)
+	status := m.Status()
+	assert.Equal(t, Staged, status.State)
+	assert.Equal(t, path, status.StagedPath)
+
+	// the binary hash announced on layer1 must match the release
+	version.BinaryHash = [32]byte{1}
+	_, err = m.Stage(context.Background(), version)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, Failed, m.Status().State)
+
+	// a release signed with another key is rejected
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	version = newVersion(10)
+	writeRelease(t, source, version, otherKey)
+	_, err = m.Stage(context.Background(), version)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// a signed release does not need a binary hash
+	version.BinaryHash = [32]byte{}
+	writeRelease(t, source, version, key)
+	_, err = m.Stage(context.Background(), version)
+	assert.Nil(t, err)
+}
+
+func TestStage_Unverified(t *testing.T) {
+	source := t.TempDir()
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: source}, &height)
+
+	// without a public key the sha256 file, fetched from the same source as
+	// the release, is not enough
+	version := newVersion(10)
+	version.BinaryHash = [32]byte{}
+	writeRelease(t, source, version, nil)
+	_, err := m.Stage(context.Background(), version)
+	assert.ErrorIs(t, err, ErrUnverifiedRelease)
+	assert.Equal(t, Failed, m.Status().State)
+}
+
+func TestStage_HTTP(t *testing.T) {
+	files := t.TempDir()
+	version := newVersion(10)
+	path := writeRelease(t, files, version, nil)
+	server := httptest.NewTLSServer(http.FileServer(http.Dir(files)))
+	defer server.Close()
+
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: server.URL + "/" + filepath.Base(path)}, &height)
+	m.client = server.Client()
+	_, err := m.Stage(context.Background(), version)
+	require.Nil(t, err)
+
+	m = newTestManager(t, config.UpgradeConfig{Enabled: true, Source: server.URL + "/missing"}, &height)
+	m.client = server.Client()
+	_, err = m.Stage(context.Background(), version)
+	assert.ErrorIs(t, err, ErrUnexpectedHTTPErr)
+
+	_, err = m.fetch(context.Background(), "http://example.com/alicenet")
+	assert.ErrorIs(t, err, ErrInsecureSource)
+}
+
+func TestRestartAtExecutionEpoch(t *testing.T) {
+	source := t.TempDir()
+	version := newVersion(3)
+	writeRelease(t, source, version, nil)
+	height := uint32(constants.EpochLength)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: source}, &height)
+
+	_, err := m.Install()
+	assert.ErrorIs(t, err, ErrNothingStaged)
+
+	_, err = m.Stage(context.Background(), version)
+	require.Nil(t, err)
+	m.checkEpoch()
+	assert.Equal(t, Staged, m.Status().State)
+	assert.Equal(t, uint32(1), m.Status().CurrentEpoch)
+	select {
+	case <-m.RestartChan():
+		t.Fatal("restart requested before the execution epoch")
+	default:
+	}
+
+	height = 2*constants.EpochLength + 1
+	m.checkEpoch()
+	assert.Equal(t, Restarting, m.Status().State)
+	<-m.RestartChan()
+
+	path, err := m.Install()
+	require.Nil(t, err)
+	assert.Equal(t, m.executable, path)
+	installed, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, release, installed)
+}
+
+func TestDryRun(t *testing.T) {
+	source := t.TempDir()
+	version := newVersion(1)
+	writeRelease(t, source, version, nil)
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, DryRun: true, Source: source}, &height)
+
+	_, err := m.Stage(context.Background(), version)
+	require.Nil(t, err)
+	m.checkEpoch()
+	status := m.Status()
+	assert.Equal(t, Staged, status.State)
+	assert.NotEmpty(t, status.Err)
+	select {
+	case <-m.RestartChan():
+		t.Fatal("restart requested in dry run")
+	default:
+	}
+}
+
+func TestVersionAvailable_IgnoresOlderVersions(t *testing.T) {
+	source := t.TempDir()
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: source}, &height)
+
+	m.VersionAvailable(bindings.CanonicalVersion{Major: 1, Minor: 0, Patch: 0})
+	m.VersionAvailable(bindings.CanonicalVersion{Major: 0, Minor: 9, Patch: 9})
+	assert.Equal(t, Idle, m.Status().State)
+
+	version := newVersion(5)
+	writeRelease(t, source, version, nil)
+	m.VersionAvailable(version)
+	m.staging.Wait()
+	status := m.Status()
+	assert.Equal(t, Staged, status.State)
+	assert.Equal(t, version, status.Version)
+}
+
+func TestVersionCanonical(t *testing.T) {
+	source := t.TempDir()
+	version := newVersion(5)
+	writeRelease(t, source, version, nil)
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: source}, &height)
+
+	// the node restarts as soon as the canonical version is staged, without
+	// waiting for its execution epoch
+	assert.True(t, m.VersionCanonical(version))
+	m.staging.Wait()
+	<-m.RestartChan()
+	assert.Equal(t, Restarting, m.Status().State)
+	_, err := m.Install()
+	require.Nil(t, err)
+}
+
+func TestVersionCanonical_StagingFails(t *testing.T) {
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{Enabled: true, Source: t.TempDir()}, &height)
+
+	// the release is missing, the node shuts down without restarting
+	assert.True(t, m.VersionCanonical(newVersion(5)))
+	m.staging.Wait()
+	<-m.RestartChan()
+	assert.Equal(t, Failed, m.Status().State)
+	_, err := m.Install()
+	assert.ErrorIs(t, err, ErrNothingStaged)
+}
+
+func TestVersionCanonical_NotHandled(t *testing.T) {
+	height := uint32(1)
+	m := newTestManager(t, config.UpgradeConfig{}, &height)
+	assert.False(t, m.VersionCanonical(newVersion(5)))
+
+	m = newTestManager(t, config.UpgradeConfig{Enabled: true, DryRun: true, Source: t.TempDir()}, &height)
+	assert.False(t, m.VersionCanonical(newVersion(5)))
+}
@@ -74,3 +74,32 @@ func GetBlocksSinceDesperation(currentHeight, start, desperationDelay int) int {
 	}
 	return blocksSinceDesperation
 }
+
+// LeaderOrder returns the validator indexes in the order they are allowed to
+// take an action by LeaderElection. The first one is allowed right away, the
+// next ones as the blocks since desperation grow.
+func LeaderOrder(numValidators int, seedHash []byte) []int {
+	if numValidators <= 0 {
+		return []int{}
+	}
+	rand := (&big.Int{}).SetBytes(seedHash)
+	start := int((&big.Int{}).Mod(rand, big.NewInt(int64(numValidators))).Int64())
+	order := make([]int, numValidators)
+	for rank := range order {
+		order[rank] = (start + rank) % numValidators
+	}
+	return order
+}
+
+// GetBlocksSinceDesperationToLead returns the blocks since desperation after
+// which the validator at rank in the LeaderOrder is allowed to take an action.
+func GetBlocksSinceDesperationToLead(rank, desperationFactor int) int {
+	if rank <= 0 {
+		return 0
+	}
+	blocks := 1
+	for numValidatorsAllowed := 1; numValidatorsAllowed < rank; numValidatorsAllowed++ {
+		blocks += desperationFactor / numValidatorsAllowed
+	}
+	return blocks
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderOrder(t *testing.T) {
+	assert.Equal(t, []int{}, LeaderOrder(0, []byte{1}))
+	assert.Equal(t, []int{2, 3, 0, 1}, LeaderOrder(4, []byte{6}))
+	assert.Equal(t, []int{0}, LeaderOrder(1, []byte{6}))
+}
+
+func TestGetBlocksSinceDesperationToLead(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	seedHash := []byte{0x12, 0x34}
+	for _, numValidators := range []int{1, 2, 5, 10} {
+		for _, desperationFactor := range []int{0, 1, 7, 40} {
+			order := LeaderOrder(numValidators, seedHash)
+			for rank, idx := range order {
+				blocks := GetBlocksSinceDesperationToLead(rank, desperationFactor)
+				assert.True(
+					t,
+					LeaderElection(numValidators, idx, blocks, desperationFactor, seedHash, logger),
+					"validators %d factor %d rank %d", numValidators, desperationFactor, rank,
+				)
+				if blocks > 0 {
+					assert.False(
+						t,
+						LeaderElection(numValidators, idx, blocks-1, desperationFactor, seedHash, logger),
+						"validators %d factor %d rank %d", numValidators, desperationFactor, rank,
+					)
+				}
+			}
+		}
+	}
+}